@@ -0,0 +1,69 @@
+// Command periodtree prints the generated period hierarchy, so the Year → Quarter → Month
+// (and fiscal) trees can be eyeballed after generation. It works entirely in memory and
+// never touches the database.
+//
+// Usage:
+//
+//	go run ./cmd/periodtree -from 2026 -to 2027 -fy-start-month 4
+//	go run ./cmd/periodtree -from 2026 -to 2026 -format dot | dot -Tsvg > periods.svg
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/period/export"
+)
+
+func main() {
+	from := flag.Int("from", time.Now().Year(), "first calendar year to generate")
+	to := flag.Int("to", time.Now().Year(), "last calendar year to generate")
+	fyStartMonth := flag.Int("fy-start-month", 0, "fiscal year start month (1-12); 0 generates no fiscal years")
+	format := flag.String("format", "ascii", "output format: ascii or dot")
+	flag.Parse()
+
+	if *to < *from {
+		log.Fatalf("-to (%d) must not be before -from (%d)", *to, *from)
+	}
+
+	periods := domain.GeneratePeriods(*from, *to)
+
+	if *fyStartMonth != 0 {
+		if *fyStartMonth < 1 || *fyStartMonth > 12 {
+			log.Fatalf("-fy-start-month must be between 1 and 12, got %d", *fyStartMonth)
+		}
+
+		store := domain.NewPeriodStore(periods)
+		cfg := domain.FiscalCalendarConfig{StartMonth: time.Month(*fyStartMonth)}
+
+		// Only fiscal years fully covered by the generated months; the last one may run past -to
+		lastFY := *to
+		if *fyStartMonth != 1 {
+			lastFY = *to - 1
+		}
+		if lastFY >= *from {
+			fiscal, err := domain.GenerateFiscalYears(store.Months(), cfg, *from, lastFY)
+			if err != nil {
+				log.Fatalf("error generating fiscal years: %v", err)
+			}
+			periods = append(periods, fiscal...)
+		}
+	}
+
+	var err error
+	switch *format {
+	case "ascii":
+		err = export.WriteASCIITree(os.Stdout, periods)
+	case "dot":
+		err = export.WriteDOT(os.Stdout, periods)
+	default:
+		err = fmt.Errorf("unknown format %q (use ascii or dot)", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -14,7 +14,7 @@ type AuditInfo struct {
 // NewAuditInfo returns an AuditInfo with the current timestamp and creator.
 func NewAuditInfo(creator string) *AuditInfo {
 	if creator == "" {
-		creator = "system@internal.local"
+		creator = SystemActor
 	}
 
 	now := time.Now().UTC()
@@ -37,3 +37,34 @@ func (a *AuditInfo) UpdateAuditInfo(updatedBy string) {
 	a.UpdatedBy = &updatedBy
 	a.UpdatedAt = &now
 }
+
+// Clone returns a copy of the audit info that shares no pointers with the original; a nil
+// AuditInfo clones to nil.
+func (a *AuditInfo) Clone() *AuditInfo {
+	if a == nil {
+		return nil
+	}
+
+	c := *a
+	if a.UpdatedBy != nil {
+		updatedBy := *a.UpdatedBy
+		c.UpdatedBy = &updatedBy
+	}
+	if a.UpdatedAt != nil {
+		updatedAt := *a.UpdatedAt
+		c.UpdatedAt = &updatedAt
+	}
+	return &c
+}
+
+// LastActor returns who last changed the record: the updater, or the creator when it was
+// never updated. A nil AuditInfo has no actor.
+func (a *AuditInfo) LastActor() string {
+	if a == nil {
+		return ""
+	}
+	if a.UpdatedBy != nil && *a.UpdatedBy != "" {
+		return *a.UpdatedBy
+	}
+	return a.CreatedBy
+}
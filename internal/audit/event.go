@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"time"
+)
+
+// SystemActor is recorded when a change has no human author, e.g. scheduled jobs.
+const SystemActor = "system@internal.local"
+
+// EventAction is the kind of mutation an Event records.
+type EventAction string
+
+const (
+	ActionCreate       EventAction = "CREATE"
+	ActionUpdate       EventAction = "UPDATE"
+	ActionStatusChange EventAction = "STATUS_CHANGE"
+	ActionDelete       EventAction = "DELETE"
+	ActionRestore      EventAction = "RESTORE"
+	ActionMerge        EventAction = "MERGE"
+	ActionArchive      EventAction = "ARCHIVE" // Data moved to cold storage
+)
+
+// Entity types recorded in Event.EntityType.
+const (
+	EntityPeriod        = "period"
+	EntityTrade         = "trade"
+	EntityCompany       = "company"
+	EntityContactPerson = "contact_person"
+	EntityCreditLimit   = "credit_limit"
+	EntityFXRate        = "fx_rate"
+	EntityCurve         = "forward_curve"
+	EntityLocation      = "location"
+	EntityProduct       = "product"
+	EntityInvoice       = "invoice"
+	EntityNomination    = "nomination"
+	EntityTradeLink     = "trade_link"
+)
+
+// Event is one immutable row of the audit log: who did what to which record, and when.
+// Unlike AuditInfo, which only keeps the creator and the last updater of a record, the log
+// keeps every change.
+//
+// Details holds action-specific context, e.g. the old and new status of a status change.
+// It is stored as JSON, so values must be JSON-encodable.
+//
+// Example:
+//
+//	audit.NewEvent(audit.EntityTrade, "T1", audit.ActionStatusChange, "user@internal.local",
+//	    map[string]any{"from": "DRAFT", "to": "CONFIRMED"})
+type Event struct {
+	ID         int64          `json:"id"` // Assigned by the database
+	EntityType string         `json:"entityType"`
+	EntityID   string         `json:"entityId"`
+	Action     EventAction    `json:"action"`
+	Actor      string         `json:"actor"`
+	At         time.Time      `json:"at"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// NewEvent returns an Event that happened now. An empty actor is recorded as SystemActor.
+func NewEvent(entityType, entityID string, action EventAction, actor string, details map[string]any) Event {
+	if actor == "" {
+		actor = SystemActor
+	}
+
+	return Event{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Actor:      actor,
+		At:         time.Now().UTC(),
+		Details:    details,
+	}
+}
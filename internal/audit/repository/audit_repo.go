@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/pgbulk"
+)
+
+// AuditRepository reads the append-only audit log. Events are never written through this
+// interface: every repository records its own events with RecordTx, inside the transaction of
+// the mutation they describe, so there is no mutation without its event (and vice versa).
+type AuditRepository interface {
+	// GetEvents returns the complete history of one record, oldest first
+	GetEvents(ctx context.Context, entityType, entityID string) ([]audit.Event, error)
+
+	// GetEventsBetween returns all events with from <= At < to, oldest first
+	GetEventsBetween(ctx context.Context, from, to time.Time) ([]audit.Event, error)
+}
+
+// Compile-time check that RdsAuditRepository satisfies AuditRepository
+var _ AuditRepository = (*RdsAuditRepository)(nil)
+
+type RdsAuditRepository struct {
+	reader *sql.DB
+}
+
+func NewRdsAuditRepository(cfg *awsclient.Config) (*RdsAuditRepository, error) {
+	_, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsAuditRepository{reader: reader.Client}, nil
+}
+
+// RecordTx appends events to the audit log inside tx. When tx rolls back, the events are gone
+// as well; when it commits, they are permanent: the audit_events table rejects every UPDATE and
+// DELETE.
+//
+// Example:
+//
+//	if err := RecordTx(ctx, tx, audit.NewEvent(audit.EntityCompany, c.ID, audit.ActionCreate, user, nil)); err != nil {
+//	    return err
+//	}
+func RecordTx(ctx context.Context, tx *sql.Tx, events ...audit.Event) error {
+	rows := make([][]any, 0, len(events))
+	for _, e := range events {
+		details := e.Details
+		if details == nil {
+			details = map[string]any{}
+		}
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to encode details of %s event for %s %s: %w", e.Action, e.EntityType, e.EntityID, err)
+		}
+
+		// COPY sends []byte as bytea, so JSON goes over the wire as text
+		rows = append(rows, []any{e.EntityType, e.EntityID, string(e.Action), e.Actor, e.At, string(encoded)})
+	}
+
+	if err := pgbulk.CopyIn(ctx, tx, "audit_events", []string{
+		"entity_type", "entity_id", "action", "actor", "at", "details",
+	}, rows); err != nil {
+		return fmt.Errorf("failed to record audit events: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents returns the complete history of one record, oldest first.
+//
+// Example:
+//
+//	events, err := repo.GetEvents(ctx, audit.EntityTrade, "T1")
+//	// → CREATE, STATUS_CHANGE {"from":"DRAFT","to":"CONFIRMED"}, UPDATE, ...
+func (r *RdsAuditRepository) GetEvents(ctx context.Context, entityType, entityID string) ([]audit.Event, error) {
+	return r.queryEvents(ctx, `
+		SELECT id, entity_type, entity_id, action, actor, at, details FROM audit_events
+		WHERE entity_type=$1 AND entity_id=$2
+		ORDER BY id
+	`, entityType, entityID)
+}
+
+func (r *RdsAuditRepository) GetEventsBetween(ctx context.Context, from, to time.Time) ([]audit.Event, error) {
+	return r.queryEvents(ctx, `
+		SELECT id, entity_type, entity_id, action, actor, at, details FROM audit_events
+		WHERE at >= $1 AND at < $2
+		ORDER BY id
+	`, from, to)
+}
+
+func (r *RdsAuditRepository) queryEvents(ctx context.Context, query string, args ...any) ([]audit.Event, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []audit.Event
+	for rows.Next() {
+		var (
+			e       audit.Event
+			action  string
+			details []byte
+		)
+		if err := rows.Scan(&e.ID, &e.EntityType, &e.EntityID, &action, &e.Actor, &e.At, &details); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		e.Action = audit.EventAction(action)
+		if err := json.Unmarshal(details, &e.Details); err != nil {
+			return nil, fmt.Errorf("failed to decode details of audit event %d: %w", e.ID, err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,374 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/migrate"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// DefaultPrefix is the S3 key prefix under which snapshots are stored.
+const DefaultPrefix = "backups/"
+
+// snapshotIDLayout names snapshots after their UTC creation time, so they sort chronologically.
+const snapshotIDLayout = "20060102T150405Z"
+
+// table is one table of the book that is part of a snapshot.
+type table struct {
+	Name    string
+	OrderBy string // Makes the exported file deterministic
+
+	// Serial is a BIGSERIAL column whose sequence must be moved past the restored values
+	Serial string
+}
+
+// bookTables lists the tables of a snapshot in foreign key order: a table only references
+// tables above it. Restore inserts them top-down.
+// When a new table holds book data, add it here.
+var bookTables = []table{
+	{Name: "periods", OrderBy: "id, version"},
+	{Name: "period_metadata", OrderBy: "period_id, key"},
+	{Name: "companies", OrderBy: "id"},
+	{Name: "company_merges", OrderBy: "source_id"},
+	{Name: "contact_persons", OrderBy: "id"},
+	{Name: "credit_limits", OrderBy: "company_id"},
+	{Name: "locations", OrderBy: "id"},
+	{Name: "products", OrderBy: "id"},
+	{Name: "trades", OrderBy: "id"},
+	{Name: "trade_number_sequences", OrderBy: "kind, year"},
+	{Name: "trade_status_history", OrderBy: "trade_id, seq", Serial: "seq"},
+	{Name: "trade_breakdowns", OrderBy: "parent_trade_id, id"},
+	{Name: "trade_rollups", OrderBy: "parent_trade_id, period_id"},
+	{Name: "trade_breakdown_deltas", OrderBy: "trade_number, amended_at, trade_id, period_id"},
+	{Name: "trade_links", OrderBy: "id"},
+	{Name: "invoices", OrderBy: "id"},
+	{Name: "invoice_lines", OrderBy: "invoice_id, line_no"},
+	{Name: "invoice_number_sequences", OrderBy: "prefix, year"},
+	{Name: "nominations", OrderBy: "id"},
+	{Name: "breakdown_archives", OrderBy: "period_id"},
+	{Name: "fx_rates", OrderBy: "base, quote, rate_date, source"},
+	{Name: "forward_curves", OrderBy: "commodity, as_of, delivery_month"},
+}
+
+// ObjectStore is the part of S3 that backups need; *awsclient.S3Client implements it.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte, contentType string) (string, error)
+	GetObject(ctx context.Context, key, versionID string) ([]byte, error)
+	ListPrefixes(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Manifest describes one snapshot. It is stored as manifest.json next to the table files and
+// is written last, so a snapshot without a manifest is incomplete and never restored.
+type Manifest struct {
+	ID            string      `json:"id"` // e.g. "20261016T120000Z"
+	CreatedAt     time.Time   `json:"createdAt"`
+	CreatedBy     string      `json:"createdBy"`
+	SchemaVersion int         `json:"schemaVersion"` // Latest applied migration
+	Tables        []TableFile `json:"tables"`
+}
+
+// TableFile is the export of one table: one JSON object per line (JSON Lines), with the
+// column names as keys.
+type TableFile struct {
+	Table     string `json:"table"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"` // S3 object version; empty on unversioned buckets
+	Rows      int    `json:"rows"`
+	SHA256    string `json:"sha256"`
+}
+
+// Service takes snapshots of the book data to S3 and restores them.
+//
+// PURPOSE:
+//
+//	A snapshot is a consistent copy of all periods, companies, trades and breakdowns (including
+//	their status history, rollups and metadata), taken in one REPEATABLE READ transaction. Each
+//	table becomes one JSON Lines object in S3 under <prefix><snapshot ID>/, and S3 object
+//	versions are recorded in the manifest, so a snapshot stays restorable even when its objects
+//	are overwritten later on a versioned bucket.
+//
+//	Restore loads a snapshot into EMPTY tables (or replaces their contents, see RestoreOptions)
+//	in a single transaction: the book is restored completely or not at all.
+//
+// EXAMPLE USAGE:
+//
+//	svc := backup.NewService(rdsClient.Client, s3Client, backup.DefaultPrefix)
+//	m, err := svc.Snapshot(ctx, "user@internal.local")
+//	...
+//	err = svc.Restore(ctx, m.ID, backup.RestoreOptions{})
+type Service struct {
+	db     *sql.DB
+	store  ObjectStore
+	prefix string
+	now    func() time.Time
+}
+
+func NewService(db *sql.DB, store ObjectStore, prefix string) *Service {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &Service{db: db, store: store, prefix: prefix, now: time.Now}
+}
+
+// Snapshot exports all book tables to S3 and returns the manifest of the new snapshot.
+func (s *Service) Snapshot(ctx context.Context, createdBy string) (*Manifest, error) {
+	schemaVersion, err := s.schemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := s.now().UTC()
+	m := &Manifest{
+		ID:            createdAt.Format(snapshotIDLayout),
+		CreatedAt:     createdAt,
+		CreatedBy:     createdBy,
+		SchemaVersion: schemaVersion,
+	}
+
+	// One snapshot of the whole database: no trade without its breakdowns, or vice versa
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, t := range bookTables {
+		data, rows, err := exportTable(ctx, tx, t)
+		if err != nil {
+			return nil, err
+		}
+
+		key := s.objectKey(m.ID, t.Name+".jsonl")
+		versionID, err := s.store.PutObject(ctx, key, data, "application/x-ndjson")
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(data)
+		m.Tables = append(m.Tables, TableFile{
+			Table:     t.Name,
+			Key:       key,
+			VersionID: versionID,
+			Rows:      rows,
+			SHA256:    hex.EncodeToString(sum[:]),
+		})
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest of snapshot %s: %w", m.ID, err)
+	}
+	if _, err := s.store.PutObject(ctx, s.objectKey(m.ID, "manifest.json"), manifest, "application/json"); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// exportTable writes every row of t as one JSON object per line.
+func exportTable(ctx context.Context, tx *sql.Tx, t table) ([]byte, int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT row_to_json(t) FROM %s t ORDER BY %s`, t.Name, t.OrderBy))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to export %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	var (
+		buf   bytes.Buffer
+		count int
+	)
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan %s row: %w", t.Name, err)
+		}
+		buf.Write(row)
+		buf.WriteByte('\n')
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate %s rows: %w", t.Name, err)
+	}
+
+	return buf.Bytes(), count, nil
+}
+
+// ListSnapshots returns the IDs of all snapshots under the prefix, oldest first. Incomplete
+// snapshots (without manifest) are included; GetManifest fails for them.
+func (s *Service) ListSnapshots(ctx context.Context) ([]string, error) {
+	prefixes, err := s.store.ListPrefixes(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(p, s.prefix), "/"))
+	}
+
+	return ids, nil
+}
+
+// GetManifest reads the manifest of a snapshot.
+func (s *Service) GetManifest(ctx context.Context, snapshotID string) (*Manifest, error) {
+	data, err := s.store.GetObject(ctx, s.objectKey(snapshotID, "manifest.json"), "")
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s has no readable manifest: %w", snapshotID, err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of snapshot %s: %w", snapshotID, err)
+	}
+
+	return &m, nil
+}
+
+// RestoreOptions controls Restore.
+type RestoreOptions struct {
+	// Replace empties the book tables before loading the snapshot. Without it, Restore refuses
+	// to load into tables that hold data.
+	Replace bool
+}
+
+// Restore loads a snapshot into the database in a single transaction.
+//
+// Before anything is written, every table file is downloaded and verified against the SHA-256
+// in the manifest, and the schema version of the database must equal the one the snapshot was
+// taken with (migrate the database, or restore into a database at that version, first).
+//
+// Example (restore onto a fresh database):
+//
+//	migrate.NewMigrator(db).Up(ctx)
+//	err := svc.Restore(ctx, "20261016T120000Z", backup.RestoreOptions{})
+func (s *Service) Restore(ctx context.Context, snapshotID string, opts RestoreOptions) error {
+	m, err := s.GetManifest(ctx, snapshotID)
+	if err != nil {
+		return err
+	}
+
+	schemaVersion, err := s.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if schemaVersion != m.SchemaVersion {
+		return fmt.Errorf("snapshot %s was taken at schema version %d, database is at %d", m.ID, m.SchemaVersion, schemaVersion)
+	}
+
+	files := make(map[string]TableFile, len(m.Tables))
+	for _, f := range m.Tables {
+		files[f.Table] = f
+	}
+
+	data := make(map[string][]byte, len(bookTables))
+	for _, t := range bookTables {
+		f, ok := files[t.Name]
+		if !ok {
+			return fmt.Errorf("snapshot %s has no export of table %s", m.ID, t.Name)
+		}
+		body, err := s.store.GetObject(ctx, f.Key, f.VersionID)
+		if err != nil {
+			return err
+		}
+		if sum := sha256.Sum256(body); hex.EncodeToString(sum[:]) != f.SHA256 {
+			return fmt.Errorf("checksum mismatch for %s of snapshot %s", f.Key, m.ID)
+		}
+		data[t.Name] = body
+	}
+
+	tx, err := txn.Begin(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if opts.Replace {
+		names := make([]string, 0, len(bookTables))
+		for _, t := range bookTables {
+			names = append(names, t.Name)
+		}
+		// No CASCADE: a table outside the snapshot that references the book makes this fail
+		// instead of silently losing its rows
+		if _, err := tx.ExecContext(ctx, `TRUNCATE `+strings.Join(names, ", ")); err != nil {
+			return fmt.Errorf("failed to empty book tables: %w", err)
+		}
+	}
+
+	for _, t := range bookTables {
+		if err := restoreTable(ctx, tx.Tx, t, data[t.Name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore of snapshot %s: %w", m.ID, err)
+	}
+
+	return nil
+}
+
+// restoreTable inserts the JSON Lines export of t. All rows go in one statement, so rows that
+// reference rows of the same table (reversal breakdowns, merged companies) need no ordering.
+func restoreTable(ctx context.Context, tx *sql.Tx, t table, data []byte) error {
+	var hasRows bool
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, t.Name)).Scan(&hasRows); err != nil {
+		return fmt.Errorf("failed to check table %s: %w", t.Name, err)
+	}
+	if hasRows {
+		return fmt.Errorf("table %s is not empty; restore with Replace to overwrite it", t.Name)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return nil
+	}
+	array := append(append([]byte("["), bytes.Join(lines, []byte(","))...), ']')
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, $1::json)`, t.Name,
+	), string(array)); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", t.Name, err)
+	}
+
+	if t.Serial != "" {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+			`SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE((SELECT MAX(%[2]s) FROM %[1]s), 1))`, t.Name, t.Serial,
+		)); err != nil {
+			return fmt.Errorf("failed to reset sequence of %s.%s: %w", t.Name, t.Serial, err)
+		}
+	}
+
+	return nil
+}
+
+// schemaVersion returns the latest applied migration.
+func (s *Service) schemaVersion(ctx context.Context) (int, error) {
+	applied, err := migrate.NewMigrator(s.db).Applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for _, a := range applied {
+		version = max(version, a.Version)
+	}
+	return version, nil
+}
+
+func (s *Service) objectKey(snapshotID, name string) string {
+	return s.prefix + snapshotID + "/" + name
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/cashflow"
+)
+
+// defaultHorizon is how far ahead a projection without a to date looks.
+const defaultHorizon = 3 // months
+
+// ProjectionSource projects the cash flows of the book; *cashflow.Service implements it.
+type ProjectionSource interface {
+	Project(ctx context.Context, from, to time.Time, granularity cashflow.Granularity) (*cashflow.Projection, error)
+}
+
+// CashflowHandler serves cash flow projections to treasury.
+//
+//	GET /cashflow?from=2026-02-01&to=2026-04-30&granularity=WEEK
+//
+// from defaults to today, to to three months after from, granularity to WEEK (or MONTH).
+//
+// Example response:
+//
+//	{"currency": "EUR", "granularity": "WEEK", "overdue": {"inflow": 496000, ...},
+//	 "buckets": [{"label": "2026-W07", "inflow": 0, "outflow": -600000, "net": -600000,
+//	              "cumulative": -104000, "flows": 1}, ...],
+//	 "flows": [...]}
+type CashflowHandler struct {
+	source ProjectionSource
+	now    func() time.Time
+}
+
+func NewCashflowHandler(source ProjectionSource) *CashflowHandler {
+	return &CashflowHandler{source: source, now: time.Now}
+}
+
+// Register mounts the handler on mux.
+func (h *CashflowHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /cashflow", h.project)
+}
+
+func (h *CashflowHandler) project(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from := h.now().UTC()
+	if v := q.Get("from"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("from must be a date (YYYY-MM-DD), got %q", v))
+			return
+		}
+		from = d
+	}
+	to := from.AddDate(0, defaultHorizon, 0)
+	if v := q.Get("to"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("to must be a date (YYYY-MM-DD), got %q", v))
+			return
+		}
+		to = d
+	}
+	granularity := cashflow.GranularityWeek
+	if v := q.Get("granularity"); v != "" {
+		granularity = cashflow.Granularity(strings.ToUpper(v))
+		if granularity != cashflow.GranularityWeek && granularity != cashflow.GranularityMonth {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("granularity must be %s or %s, got %q", cashflow.GranularityWeek, cashflow.GranularityMonth, v))
+			return
+		}
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("to (%s) lies before from (%s)", to.Format("2006-01-02"), from.Format("2006-01-02")))
+		return
+	}
+
+	p, err := h.source.Project(r.Context(), from, to, granularity)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to project the cash flows: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
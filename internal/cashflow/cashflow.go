@@ -0,0 +1,345 @@
+package cashflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/fx"
+	invoice "github.com/nholding/cso-book/internal/invoice/domain"
+	invoicerepo "github.com/nholding/cso-book/internal/invoice/repository"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// Granularity is the length of the buckets of a projection.
+type Granularity string
+
+const (
+	GranularityWeek  Granularity = "WEEK"  // ISO weeks, Monday to Sunday
+	GranularityMonth Granularity = "MONTH" // Calendar months
+)
+
+// Source tells where the amount and date of a flow come from.
+type Source string
+
+const (
+	SourceBreakdown Source = "BREAKDOWN" // Not invoiced yet: breakdown amount, estimated due date
+	SourceInvoice   Source = "INVOICE"   // On an unpaid invoice: amount including tax
+)
+
+// Flow is one expected payment: positive when the book receives, negative when it pays.
+type Flow struct {
+	DueDate        time.Time       `json:"dueDate"`
+	Source         Source          `json:"source"`
+	TradeID        string          `json:"tradeId"`
+	TradeNumber    string          `json:"tradeNumber,omitempty"`
+	Kind           trade.TradeKind `json:"kind"`
+	CounterpartyID string          `json:"counterpartyId"`
+	BreakdownID    string          `json:"breakdownId"`
+	PeriodID       string          `json:"periodId"`
+	InvoiceNumber  string          `json:"invoiceNumber,omitempty"`
+	Terms          string          `json:"terms"`      // e.g. "30 days after B/L"
+	Amount         float64         `json:"amount"`     // In the projection currency
+	DealAmount     float64         `json:"dealAmount"` // In DealCurrency
+	DealCurrency   string          `json:"dealCurrency"`
+}
+
+// Bucket sums the flows due in one week or month.
+type Bucket struct {
+	Label      string    `json:"label"` // e.g. "2026-W07" or "2026-02"
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"` // Last day of the bucket
+	Inflow     float64   `json:"inflow"`
+	Outflow    float64   `json:"outflow"` // ≤ 0
+	Net        float64   `json:"net"`
+	Cumulative float64   `json:"cumulative"` // Net of this and all earlier buckets, overdue flows included
+	Flows      int       `json:"flows"`
+}
+
+// Projection is the expected cash in and out of the book between From and To.
+type Projection struct {
+	Currency    string      `json:"currency"`
+	Granularity Granularity `json:"granularity"`
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	Overdue     Bucket      `json:"overdue"` // Flows due before From that are not paid
+	Buckets     []Bucket    `json:"buckets"`
+	Flows       []Flow      `json:"flows"` // Overdue and in range, by due date
+}
+
+// Project
+//
+// PURPOSE:
+//
+//	Sums flows into buckets of one granularity between from and to (both inclusive, day
+//	precision). Every bucket of the range is listed, also when nothing is due in it, so
+//	treasury sees the gaps as well as the peaks:
+//
+//	  overdue                   inflow  496,000  outflow          0
+//	  2026-W07 (Feb 9 – 15)     inflow        0  outflow   -600,000  cumulative  -104,000
+//	  2026-W08 (Feb 16 – 22)    inflow  123,000  outflow          0  cumulative    19,000
+//
+//	Flows due before from count as overdue; flows due after to are left out.
+//
+// EXAMPLE USAGE:
+//
+//	p := cashflow.Project(flows, "EUR", feb1, apr30, cashflow.GranularityWeek)
+func Project(flows []Flow, currency string, from, to time.Time, granularity Granularity) *Projection {
+	from, to = day(from), day(to)
+	p := &Projection{Currency: currency, Granularity: granularity, From: from, To: to, Overdue: Bucket{Label: "overdue"}}
+
+	for start := bucketStart(from, granularity); !start.After(to); start = nextBucket(start, granularity) {
+		p.Buckets = append(p.Buckets, Bucket{
+			Label: bucketLabel(start, granularity),
+			Start: start,
+			End:   nextBucket(start, granularity).AddDate(0, 0, -1),
+		})
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool { return flows[i].DueDate.Before(flows[j].DueDate) })
+	for _, f := range flows {
+		due := day(f.DueDate)
+		var b *Bucket
+		switch {
+		case due.Before(from):
+			b = &p.Overdue
+		case due.After(to):
+			continue
+		default:
+			for i := range p.Buckets {
+				if !due.After(p.Buckets[i].End) {
+					b = &p.Buckets[i]
+					break
+				}
+			}
+		}
+		if f.Amount >= 0 {
+			b.Inflow += f.Amount
+		} else {
+			b.Outflow += f.Amount
+		}
+		b.Net += f.Amount
+		b.Flows++
+		p.Flows = append(p.Flows, f)
+	}
+
+	cumulative := p.Overdue.Net
+	for i := range p.Buckets {
+		cumulative += p.Buckets[i].Net
+		p.Buckets[i].Cumulative = cumulative
+	}
+	return p
+}
+
+// BookSource provides the live trades of the book with their breakdowns; *service.TradeService
+// implements it.
+type BookSource interface {
+	ListBookedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error)
+}
+
+// InvoiceSource provides the invoices; *service.InvoiceService implements it.
+type InvoiceSource interface {
+	List(ctx context.Context, filter invoicerepo.InvoiceFilter) ([]*invoice.Invoice, error)
+}
+
+// Service projects the cash flows of the book for treasury.
+//
+// PURPOSE:
+//
+//	Every open breakdown of a live trade is expected to be paid on the due date of the
+//	trade's payment terms (see trade.PaymentSchedule). Invoicing refines the estimate: a
+//	breakdown on an unpaid invoice is expected at its amount including tax, and terms that
+//	count from the invoice date count from the invoice's issue date; a breakdown on a PAID
+//	invoice has been settled and drops out. Sales are cash in, purchases and storage cash
+//	out. Amounts are expressed in one currency: the breakdown's reporting amount when it was
+//	converted into that currency, otherwise converted at today's rate.
+//
+//	Swaps are settled from index fixings and carry no breakdowns, so they are not projected.
+//
+// EXAMPLE USAGE:
+//
+//	svc := cashflow.NewService(tradeService, invoiceService, holidays, fxRates, "EUR")
+//	p, err := svc.Project(ctx, time.Now(), time.Now().AddDate(0, 3, 0), cashflow.GranularityWeek)
+type Service struct {
+	book     BookSource
+	invoices InvoiceSource // nil: nothing is invoiced or paid
+	holidays *period.HolidayCalendar
+	rates    fx.RateSource // nil: amounts must already be in currency
+	currency string
+	now      func() time.Time
+}
+
+func NewService(book BookSource, invoices InvoiceSource, holidays *period.HolidayCalendar, rates fx.RateSource, currency string) *Service {
+	return &Service{
+		book:     book,
+		invoices: invoices,
+		holidays: holidays,
+		rates:    rates,
+		currency: strings.ToUpper(currency),
+		now:      time.Now,
+	}
+}
+
+// Project returns the projection of the book between from and to, see Project.
+func (s *Service) Project(ctx context.Context, from, to time.Time, granularity Granularity) (*Projection, error) {
+	switch granularity {
+	case GranularityWeek, GranularityMonth:
+	default:
+		return nil, fmt.Errorf("invalid cashflow granularity %q", granularity)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("cashflow projection ends (%s) before it starts (%s)", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	flows, err := s.Flows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Project(flows, s.currency, from, to, granularity), nil
+}
+
+// invoicedLine is a line of an unpaid invoice.
+type invoicedLine struct {
+	invoice *invoice.Invoice
+	line    invoice.LineItem
+}
+
+// Flows returns every expected payment of the book, overdue ones included, in no particular
+// order.
+func (s *Service) Flows(ctx context.Context) ([]Flow, error) {
+	book, err := s.book.ListBookedTrades(ctx, repository.TradeFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+
+	paid := make(map[string]bool)
+	open := make(map[string]invoicedLine)
+	if s.invoices != nil {
+		invoices, err := s.invoices.List(ctx, invoicerepo.InvoiceFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load invoices: %w", err)
+		}
+		for _, inv := range invoices {
+			for _, l := range inv.Lines {
+				if inv.Status == invoice.InvoiceStatusPaid {
+					paid[l.Key()] = true
+				} else {
+					open[l.Key()] = invoicedLine{invoice: inv, line: l}
+				}
+			}
+		}
+	}
+
+	var flows []Flow
+	for _, bt := range book {
+		tb := bt.Trade.Base()
+		direction, ok := invoice.DirectionOf(bt.Trade.Kind())
+		if !ok {
+			continue
+		}
+		sign := 1.0
+		if direction == invoice.DirectionPayable {
+			sign = -1
+		}
+		terms := trade.DefaultPaymentTerms
+		if tb.PaymentTerms != nil {
+			terms = *tb.PaymentTerms
+		}
+		breakdowns := make(map[string]trade.TradeBreakdown, len(bt.Breakdowns))
+		for _, bd := range bt.Breakdowns {
+			breakdowns[bd.ID] = bd
+		}
+
+		for _, due := range trade.PaymentSchedule(s.holidays, bt.Trade, bt.Breakdowns) {
+			key := invoice.LineKey(tb.TradeNumber, tb.ID, due.PeriodID, due.Component)
+			if paid[key] {
+				continue
+			}
+			f := Flow{
+				DueDate:        due.DueDate,
+				Source:         SourceBreakdown,
+				TradeID:        tb.ID,
+				TradeNumber:    tb.TradeNumber,
+				Kind:           bt.Trade.Kind(),
+				CounterpartyID: bt.Trade.CounterpartyID(),
+				BreakdownID:    due.BreakdownID,
+				PeriodID:       due.PeriodID,
+				Terms:          due.Terms,
+				DealAmount:     due.Amount,
+				DealCurrency:   strings.ToUpper(due.Currency),
+			}
+			// Tax on top of the breakdown amount; 1 until invoiced
+			gross := 1.0
+			if il, ok := open[key]; ok {
+				f.Source = SourceInvoice
+				f.InvoiceNumber = il.invoice.Number
+				gross = 1 + il.invoice.TaxRatePct/100
+				f.DealAmount = il.line.Amount * gross
+				f.DealCurrency = il.invoice.Currency
+				if terms.Basis == trade.PaymentAfterInvoice {
+					f.DueDate = terms.DueDate(s.holidays, il.invoice.IssueDate)
+				}
+			}
+
+			bd := breakdowns[due.BreakdownID]
+			if strings.EqualFold(bd.ReportingCurrency, s.currency) && strings.EqualFold(bd.Currency, f.DealCurrency) {
+				f.Amount = sign * bd.ReportingAmount * gross
+			} else {
+				v, err := s.convert(f.DealAmount, f.DealCurrency, s.now())
+				if err != nil {
+					return nil, fmt.Errorf("trade %s, %s: %w", tb.ID, due.PeriodID, err)
+				}
+				f.Amount = sign * v
+			}
+			f.DealAmount *= sign
+			flows = append(flows, f)
+		}
+	}
+	return flows, nil
+}
+
+func (s *Service) convert(amount float64, currency string, date time.Time) (float64, error) {
+	if strings.EqualFold(currency, s.currency) {
+		return amount, nil
+	}
+	if s.rates == nil {
+		return 0, fmt.Errorf("cannot convert %s into %s: no FX rates", currency, s.currency)
+	}
+	return fx.Convert(s.rates, amount, currency, s.currency, date)
+}
+
+// day truncates t to the start of its day, UTC.
+func day(t time.Time) time.Time {
+	u := t.UTC()
+	return time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// bucketStart returns the first day of the bucket containing t: the Monday of its ISO week or
+// the first of its month.
+func bucketStart(t time.Time, granularity Granularity) time.Time {
+	d := day(t)
+	if granularity == GranularityMonth {
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	offset := (int(d.Weekday()) + 6) % 7 // Days since Monday
+	return d.AddDate(0, 0, -offset)
+}
+
+func nextBucket(start time.Time, granularity Granularity) time.Time {
+	if granularity == GranularityMonth {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+func bucketLabel(start time.Time, granularity Granularity) string {
+	if granularity == GranularityMonth {
+		return start.Format("2006-01")
+	}
+	year, week := start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
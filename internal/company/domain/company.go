@@ -1,7 +1,9 @@
 package company
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/nholding/cso-book/internal/audit"
 	"github.com/nholding/cso-book/internal/utils"
@@ -18,28 +20,81 @@ type Company struct {
 	City            string          `json:"city"`
 	Address         string          `json:"address"`
 	ContactPersonID string          `json:"contact_person_id"`
+	MergedIntoID    *string         `json:"merged_into_id,omitempty"` // Set when this company was merged into another (tombstone)
+	RowVersion      int             `json:"row_version"`              // Optimistic-lock counter, bumped by every update of the stored row
 	AuditInfo       audit.AuditInfo `json:"audit"`
+
+	// Registrations besides the CoC number, see Identifiers
+	LEI       string `json:"lei,omitempty"`
+	VATNumber string `json:"vat_number,omitempty"`
+
+	// Compliance, see CheckCompliance
+	KYCStatus           KYCStatus  `json:"kyc_status"`
+	KYCApprovedAt       *time.Time `json:"kyc_approved_at,omitempty"`
+	KYCExpiresAt        *time.Time `json:"kyc_expires_at,omitempty"`
+	SanctionsHit        bool       `json:"sanctions_hit"` // The last screening matched a sanctions list
+	SanctionsScreenedAt *time.Time `json:"sanctions_screened_at,omitempty"`
+	SanctionsNote       string     `json:"sanctions_note,omitempty"`
 }
 
-// Generate keys
+// GenerateKeys gives the company a new ID and derives its BusinessKey from the strongest
+// identifier it has: the LEI, else the VAT number, else the CoC number.
+//
+// Version C1 keys (still stored for older companies) hash the CoC number only. C2 keys name
+// the identifier they hash, so a CoC number never collides with a VAT number. Keys are fixed
+// at creation; deduplication also compares the identifiers themselves (see
+// CompanyService.Create).
 func (c *Company) GenerateKeys() {
-	c.Version = "C1" // version 1 of key logic
+	c.Version = "C2" // version 2 of key logic
 	c.ID = utils.GenerateStableID()
 
+	scheme, id := "coc", c.CoCNumber
+	switch {
+	case c.LEI != "":
+		scheme, id = "lei", c.LEI
+	case c.VATNumber != "":
+		scheme, id = "vat", c.VATNumber
+	}
 	c.BusinessKey = utils.GenerateBusinessKey(c.Version, map[string]string{
-		"coc": c.CoCNumber,
+		"scheme": scheme,
+		"id":     id,
 	})
 }
 
-// CreateCompany creates a company if it doesn't already exist
+// NewCompany builds a new company identified by its CoC number, see NewRegisteredCompany.
 func NewCompany(name, commonName, displayName, cocNumber, city, address, user string) (Company, error) {
+	return NewRegisteredCompany(name, commonName, displayName, Identifiers{CoCNumber: cocNumber}, city, address, user)
+}
+
+// NewRegisteredCompany builds a new company with its keys. At least one identifier is
+// required; a LEI or VAT number must pass its checksum (see Identifiers.Normalize). It does
+// not look for an existing company with the same identifiers; create companies through
+// CompanyService.Create to deduplicate them.
+//
+// Example:
+//
+//	c, err := company.NewRegisteredCompany("British Petroleum", "BP", "BP",
+//	    company.Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760", VATNumber: "GB980780684"},
+//	    "London", "1 St James's Square", "user@internal.local")
+func NewRegisteredCompany(name, commonName, displayName string, ids Identifiers, city, address, user string) (Company, error) {
+	if strings.TrimSpace(name) == "" {
+		return Company{}, fmt.Errorf("company name is required")
+	}
+	ids, err := ids.Normalize()
+	if err != nil {
+		return Company{}, fmt.Errorf("company %q: %w", name, err)
+	}
+
 	c := Company{
 		Name:        strings.ToLower(name),
 		CommonName:  commonName,
 		DisplayName: displayName,
-		CoCNumber:   cocNumber,
+		CoCNumber:   ids.CoCNumber,
+		LEI:         ids.LEI,
+		VATNumber:   ids.VATNumber,
 		City:        strings.ToLower(city),
 		Address:     strings.ToLower(address),
+		KYCStatus:   KYCStatusPending,
 		AuditInfo:   *audit.NewAuditInfo(user),
 	}
 
@@ -47,3 +102,94 @@ func NewCompany(name, commonName, displayName, cocNumber, city, address, user st
 
 	return c, nil
 }
+
+// ReportingName is the name shown for the company in reports: the display name, else the
+// common name, else the official name.
+func (c *Company) ReportingName() string {
+	switch {
+	case c.DisplayName != "":
+		return c.DisplayName
+	case c.CommonName != "":
+		return c.CommonName
+	}
+	return c.Name
+}
+
+// IsTombstoned reports whether the company was merged into another company.
+// Tombstoned companies are kept for audit purposes but must not be used for new trades.
+func (c *Company) IsTombstoned() bool {
+	return c.MergedIntoID != nil
+}
+
+// CompanyUpdate holds the changes to a company's details; nil fields are left as they are.
+// The CoC number is not among them: it identified the company when it was created. A company
+// booked under the wrong CoC number is created again under the right one and merged (see
+// ValidateMerge). A LEI or VAT number may be added or corrected; the BusinessKey keeps the
+// identifier it was made from.
+//
+// Example (the company moved):
+//
+//	city, address := "Rotterdam", "Weena 10"
+//	CompanyUpdate{City: &city, Address: &address}
+type CompanyUpdate struct {
+	Name            *string
+	CommonName      *string
+	DisplayName     *string
+	City            *string
+	Address         *string
+	ContactPersonID *string
+	LEI             *string // Validated like NormalizeLEI; "" removes it
+	VATNumber       *string // Validated like NormalizeVATNumber; "" removes it
+}
+
+// Apply applies u to the company, normalised the way NewCompany does, and touches the audit
+// info. It reports whether anything changed; an unchanged company keeps its audit info.
+func (c *Company) Apply(u CompanyUpdate, user string) (bool, error) {
+	if c.IsTombstoned() {
+		return false, fmt.Errorf("company %s was merged into %s and can no longer be changed", c.ID, *c.MergedIntoID)
+	}
+	if u.Name != nil && strings.TrimSpace(*u.Name) == "" {
+		return false, fmt.Errorf("company name is required")
+	}
+	ids := c.Identifiers()
+	if u.LEI != nil || u.VATNumber != nil {
+		if u.LEI != nil {
+			ids.LEI = *u.LEI
+		}
+		if u.VATNumber != nil {
+			ids.VATNumber = *u.VATNumber
+		}
+		var err error
+		if ids, err = ids.Normalize(); err != nil {
+			return false, fmt.Errorf("company %s: %w", c.ID, err)
+		}
+	}
+
+	changed := false
+	set := func(field *string, value *string) {
+		if value != nil && *field != *value {
+			*field = *value
+			changed = true
+		}
+	}
+	lower := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		v := strings.ToLower(*value)
+		return &v
+	}
+	set(&c.Name, lower(u.Name))
+	set(&c.CommonName, u.CommonName)
+	set(&c.DisplayName, u.DisplayName)
+	set(&c.City, lower(u.City))
+	set(&c.Address, lower(u.Address))
+	set(&c.ContactPersonID, u.ContactPersonID)
+	set(&c.LEI, &ids.LEI)
+	set(&c.VATNumber, &ids.VATNumber)
+
+	if changed {
+		c.AuditInfo.UpdateAuditInfo(user)
+	}
+	return changed, nil
+}
@@ -0,0 +1,131 @@
+package company
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/nholding/cso-book/internal/audit"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// ContactRole is what a contact person is approached for.
+//
+// CONFIRMATIONS: receives trade confirmations (recaps)
+// OPERATIONS:    nominations, vessels and deliveries
+// FINANCE:       invoices and payments
+// TRADING:       the counterparty's trader
+const (
+	ContactRoleConfirmations ContactRole = "CONFIRMATIONS"
+	ContactRoleOperations    ContactRole = "OPERATIONS"
+	ContactRoleFinance       ContactRole = "FINANCE"
+	ContactRoleTrading       ContactRole = "TRADING"
+)
+
+type ContactRole string
+
+// ContactPerson is a person at a company. Company.ContactPersonID points at the primary
+// contact; a company may have any number of others.
+//
+// Example:
+//
+//	ContactPerson{CompanyID: "01HF...", Name: "Jane Smith", Email: "confirmations@bp.example",
+//	    Phone: "+44 20 7946 0000", Role: ContactRoleConfirmations}
+type ContactPerson struct {
+	ID         string          `json:"id"` // Stable ULID (primary key)
+	CompanyID  string          `json:"company_id"`
+	Name       string          `json:"name"`
+	Email      string          `json:"email"` // Lower case
+	Phone      string          `json:"phone"`
+	Role       ContactRole     `json:"role"`
+	RowVersion int             `json:"row_version"` // Optimistic-lock counter, bumped by every update of the stored row
+	AuditInfo  audit.AuditInfo `json:"audit"`
+}
+
+// NewContactPerson builds a contact person of a company; name, a valid email address and a
+// known role are required.
+//
+// Example:
+//
+//	p, err := company.NewContactPerson(c.ID, "Jane Smith", "Confirmations@BP.example", "+44 20 7946 0000", company.ContactRoleConfirmations, "user@internal.local")
+//	// p.Email → "confirmations@bp.example"
+func NewContactPerson(companyID, name, email, phone string, role ContactRole, user string) (ContactPerson, error) {
+	p := ContactPerson{
+		ID:        utils.GenerateStableID(),
+		CompanyID: companyID,
+		Name:      strings.TrimSpace(name),
+		Email:     normalizeEmail(email),
+		Phone:     strings.TrimSpace(phone),
+		Role:      role,
+		AuditInfo: *audit.NewAuditInfo(user),
+	}
+	if p.CompanyID == "" {
+		return ContactPerson{}, fmt.Errorf("contact person %q must belong to a company", p.Name)
+	}
+	if err := p.Validate(); err != nil {
+		return ContactPerson{}, err
+	}
+	return p, nil
+}
+
+// Validate checks the name, email address and role of the contact person.
+func (p *ContactPerson) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("contact person name is required")
+	}
+	if _, err := mail.ParseAddress(p.Email); err != nil || strings.ContainsAny(p.Email, "<> ") {
+		return fmt.Errorf("contact person %q has an invalid email address %q", p.Name, p.Email)
+	}
+	switch p.Role {
+	case ContactRoleConfirmations, ContactRoleOperations, ContactRoleFinance, ContactRoleTrading:
+	default:
+		return fmt.Errorf("contact person %q has an unknown role %q", p.Name, p.Role)
+	}
+	return nil
+}
+
+// ContactUpdate holds the changes to a contact person; nil fields are left as they are. The
+// company a contact belongs to never changes (merges move contacts along with the company).
+//
+// Example (the confirmations desk got a new address):
+//
+//	email := "recaps@bp.example"
+//	ContactUpdate{Email: &email}
+type ContactUpdate struct {
+	Name  *string
+	Email *string
+	Phone *string
+	Role  *ContactRole
+}
+
+// Apply applies u to the contact person, validates the result and touches the audit info. It
+// reports whether anything changed; p is left as it was when an error is returned.
+func (p *ContactPerson) Apply(u ContactUpdate, user string) (bool, error) {
+	next := *p
+	if u.Name != nil {
+		next.Name = strings.TrimSpace(*u.Name)
+	}
+	if u.Email != nil {
+		next.Email = normalizeEmail(*u.Email)
+	}
+	if u.Phone != nil {
+		next.Phone = strings.TrimSpace(*u.Phone)
+	}
+	if u.Role != nil {
+		next.Role = *u.Role
+	}
+	if next.Name == p.Name && next.Email == p.Email && next.Phone == p.Phone && next.Role == p.Role {
+		return false, nil
+	}
+	if err := next.Validate(); err != nil {
+		return false, err
+	}
+
+	next.AuditInfo.UpdateAuditInfo(user)
+	*p = next
+	return true, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,111 @@
+package company
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+)
+
+// CreditRating is the internal credit rating of a counterparty, from A (strongest) to D.
+//
+// A: investment grade or parent-guaranteed
+// B: sound, standard terms
+// C: weak, short limits and close monitoring
+// D: no open credit; trades need prepayment or a letter of credit, the limit counts as zero
+const (
+	CreditRatingA CreditRating = "A"
+	CreditRatingB CreditRating = "B"
+	CreditRatingC CreditRating = "C"
+	CreditRatingD CreditRating = "D"
+)
+
+type CreditRating string
+
+// CreditLimit is the approved credit limit of a company with its internal rating. Every limit
+// is reviewed periodically: once the review date has passed the limit is suspended until it
+// is reviewed again (see EffectiveAmount).
+//
+// Example (5M EUR, rated B, to be reviewed by the end of the year):
+//
+//	CreditLimit{CompanyID: "01HF...", Amount: 5_000_000, Currency: "EUR", Rating: CreditRatingB,
+//	    ReviewDate: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)}
+type CreditLimit struct {
+	CompanyID  string          `json:"company_id"` // One limit per company
+	Amount     float64         `json:"amount"`
+	Currency   string          `json:"currency"`
+	Rating     CreditRating    `json:"rating"`
+	ReviewDate time.Time       `json:"review_date"` // Date (UTC midnight); the limit holds through this day
+	RowVersion int             `json:"row_version"` // Optimistic-lock counter, bumped by every update of the stored row
+	AuditInfo  audit.AuditInfo `json:"audit"`
+}
+
+// NewCreditLimit builds the credit limit of a company, see Validate.
+//
+// Example:
+//
+//	l, err := company.NewCreditLimit(c.ID, 5_000_000, "eur", company.CreditRatingB, reviewDate, "credit@internal.local")
+func NewCreditLimit(companyID string, amount float64, currency string, rating CreditRating, reviewDate time.Time, user string) (CreditLimit, error) {
+	l := CreditLimit{
+		CompanyID:  companyID,
+		Amount:     amount,
+		Currency:   strings.ToUpper(strings.TrimSpace(currency)),
+		Rating:     rating,
+		ReviewDate: truncateToDate(reviewDate),
+		AuditInfo:  *audit.NewAuditInfo(user),
+	}
+	if err := l.Validate(); err != nil {
+		return CreditLimit{}, err
+	}
+	return l, nil
+}
+
+// Validate checks the limit: a company, an amount of at least zero in a three-letter currency,
+// a known rating and a review date.
+func (l *CreditLimit) Validate() error {
+	if l.CompanyID == "" {
+		return fmt.Errorf("a credit limit must belong to a company")
+	}
+	if l.Amount < 0 {
+		return fmt.Errorf("the credit limit of company %s must not be negative, got %v", l.CompanyID, l.Amount)
+	}
+	if len(l.Currency) != 3 {
+		return fmt.Errorf("the credit limit of company %s has an invalid currency %q", l.CompanyID, l.Currency)
+	}
+	switch l.Rating {
+	case CreditRatingA, CreditRatingB, CreditRatingC, CreditRatingD:
+	default:
+		return fmt.Errorf("the credit limit of company %s has an unknown rating %q", l.CompanyID, l.Rating)
+	}
+	if l.ReviewDate.IsZero() {
+		return fmt.Errorf("the credit limit of company %s needs a review date", l.CompanyID)
+	}
+	return nil
+}
+
+// IsReviewOverdue reports whether the review date of the limit lies before the day of at.
+func (l *CreditLimit) IsReviewOverdue(at time.Time) bool {
+	return truncateToDate(at).After(l.ReviewDate)
+}
+
+// EffectiveAmount returns the limit that applies at a moment, with the reason when it is not
+// the approved amount: a D rating and an overdue review both leave no open credit.
+//
+// Example (review date 2026-03-31, checked on 2026-04-02):
+//
+//	amount, reason := l.EffectiveAmount(now) // → 0, "review overdue since 2026-03-31"
+func (l *CreditLimit) EffectiveAmount(at time.Time) (float64, string) {
+	switch {
+	case l.Rating == CreditRatingD:
+		return 0, "rated D"
+	case l.IsReviewOverdue(at):
+		return 0, "review overdue since " + l.ReviewDate.Format(time.DateOnly)
+	}
+	return l.Amount, ""
+}
+
+func truncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
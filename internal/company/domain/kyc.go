@@ -0,0 +1,122 @@
+package company
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KYCStatus is the know-your-customer status of a company.
+//
+// PENDING:  not (or no longer) approved, e.g. a new company or one with a sanctions hit
+// APPROVED: KYC completed; valid until KYCExpiresAt
+// EXPIRED:  the approval passed its expiry date and must be renewed
+const (
+	KYCStatusPending  KYCStatus = "PENDING"
+	KYCStatusApproved KYCStatus = "APPROVED"
+	KYCStatusExpired  KYCStatus = "EXPIRED"
+)
+
+type KYCStatus string
+
+// ComplianceError reports that a company may not be traded with, e.g. when confirming a trade.
+//
+// Example:
+//
+//	var compliance *company.ComplianceError
+//	if errors.As(err, &compliance) {
+//	    log.Printf("blocked: %s", compliance.Reason) // "KYC is EXPIRED since 2026-03-31"
+//	}
+type ComplianceError struct {
+	CompanyID string
+	Reason    string
+}
+
+func (e *ComplianceError) Error() string {
+	return fmt.Sprintf("company %s is not approved for trading: %s", e.CompanyID, e.Reason)
+}
+
+// KYCStatusAt returns the KYC status of the company at a moment: an approval past its expiry
+// date is EXPIRED even before anyone records it. Companies without a status are PENDING.
+func (c *Company) KYCStatusAt(at time.Time) KYCStatus {
+	switch {
+	case c.KYCStatus == "":
+		return KYCStatusPending
+	case c.KYCStatus == KYCStatusApproved && c.KYCExpiresAt != nil && !at.Before(*c.KYCExpiresAt):
+		return KYCStatusExpired
+	}
+	return c.KYCStatus
+}
+
+// RecordScreening records the outcome of a sanctions screening. A hit revokes the KYC
+// approval: the company is PENDING until the hit is cleared by a new screening and KYC is
+// approved again.
+//
+// Example:
+//
+//	c.RecordScreening(true, "name match on EU consolidated list, under review", time.Now(), "compliance@internal.local")
+func (c *Company) RecordScreening(hit bool, note string, at time.Time, user string) {
+	at = at.UTC()
+	c.SanctionsHit = hit
+	c.SanctionsNote = strings.TrimSpace(note)
+	c.SanctionsScreenedAt = &at
+	if hit && c.KYCStatus == KYCStatusApproved {
+		c.KYCStatus = KYCStatusPending
+	}
+	c.AuditInfo.UpdateAuditInfo(user)
+}
+
+// ApproveKYC approves the company until expiresAt. The company must have been screened
+// without a sanctions hit first, and the expiry must lie after the approval.
+//
+// Example (approved for one year):
+//
+//	err := c.ApproveKYC(now.AddDate(1, 0, 0), now, "compliance@internal.local")
+func (c *Company) ApproveKYC(expiresAt, at time.Time, user string) error {
+	if c.IsTombstoned() {
+		return fmt.Errorf("company %s was merged into %s and can no longer be approved", c.ID, *c.MergedIntoID)
+	}
+	if c.SanctionsScreenedAt == nil {
+		return fmt.Errorf("company %s must be screened against sanctions lists before its KYC is approved", c.ID)
+	}
+	if c.SanctionsHit {
+		return fmt.Errorf("company %s has an open sanctions hit: %s", c.ID, c.SanctionsNote)
+	}
+	at, expiresAt = at.UTC(), expiresAt.UTC()
+	if !expiresAt.After(at) {
+		return fmt.Errorf("the KYC approval of company %s must expire after %s", c.ID, at.Format(time.DateOnly))
+	}
+
+	c.KYCStatus = KYCStatusApproved
+	c.KYCApprovedAt = &at
+	c.KYCExpiresAt = &expiresAt
+	c.AuditInfo.UpdateAuditInfo(user)
+	return nil
+}
+
+// ExpireKYC records that the approval of the company has expired (see KYCStatusAt). It
+// reports whether the status changed.
+func (c *Company) ExpireKYC(at time.Time, user string) bool {
+	if c.KYCStatus != KYCStatusApproved || c.KYCStatusAt(at) != KYCStatusExpired {
+		return false
+	}
+	c.KYCStatus = KYCStatusExpired
+	c.AuditInfo.UpdateAuditInfo(user)
+	return true
+}
+
+// CheckCompliance fails with a *ComplianceError unless trades with the company may be
+// confirmed at a moment: its KYC is APPROVED and it has no open sanctions hit.
+func (c *Company) CheckCompliance(at time.Time) error {
+	if c.SanctionsHit {
+		return &ComplianceError{CompanyID: c.ID, Reason: "open sanctions hit: " + c.SanctionsNote}
+	}
+	switch status := c.KYCStatusAt(at); status {
+	case KYCStatusApproved:
+		return nil
+	case KYCStatusExpired:
+		return &ComplianceError{CompanyID: c.ID, Reason: "KYC is EXPIRED since " + c.KYCExpiresAt.Format(time.DateOnly)}
+	default:
+		return &ComplianceError{CompanyID: c.ID, Reason: "KYC is " + string(status)}
+	}
+}
@@ -0,0 +1,47 @@
+package company
+
+import (
+	"fmt"
+	"time"
+)
+
+// MergeRecord captures the outcome of merging a duplicate company (source) into the
+// surviving company (target). It is persisted as an audit row so we can always explain
+// why a counterparty disappeared and where its trades went.
+//
+// Example:
+//
+//	rec := MergeRecord{
+//	    SourceID: "01HFYEVZQYF5Y2ZYQJ2TFTKX8X",
+//	    TargetID: "01HFYF0D1MJ3S4Q2W6ZB0N8T5C",
+//	    MergedBy: "user@internal.local",
+//	    RepointedRows: map[string]int64{"trades.counterparty_id": 12},
+//	}
+type MergeRecord struct {
+	SourceID      string           `json:"source_id"`      // Company that is tombstoned
+	TargetID      string           `json:"target_id"`      // Surviving company
+	MergedBy      string           `json:"merged_by"`      // User performing the merge
+	MergedAt      time.Time        `json:"merged_at"`      // UTC timestamp of the merge
+	RepointedRows map[string]int64 `json:"repointed_rows"` // "table.column" → number of rows re-pointed
+}
+
+// ValidateMerge checks that source can be merged into target.
+//
+// Rules:
+//   - source and target must be different companies
+//   - neither company may already be tombstoned by an earlier merge
+func ValidateMerge(source, target *Company) error {
+	if source == nil || target == nil {
+		return fmt.Errorf("source and target company must both exist")
+	}
+	if source.ID == target.ID {
+		return fmt.Errorf("cannot merge company %s into itself", source.ID)
+	}
+	if source.IsTombstoned() {
+		return fmt.Errorf("source company %s was already merged into %s", source.ID, *source.MergedIntoID)
+	}
+	if target.IsTombstoned() {
+		return fmt.Errorf("target company %s was already merged into %s", target.ID, *target.MergedIntoID)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package company
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Identifiers are the registrations a company is recognised by. At least one is required;
+// the strongest one present makes the BusinessKey (see GenerateKeys): LEI, then VAT number,
+// then CoC number.
+//
+// Example:
+//
+//	Identifiers{CoCNumber: "12345678", LEI: "5493001KJTIIGC8Y1R12", VATNumber: "NL123456782B01"}
+type Identifiers struct {
+	CoCNumber string // Chamber of Commerce (trade register) number
+	LEI       string // Legal Entity Identifier, ISO 17442
+	VATNumber string // VAT number with its country prefix, e.g. "DE136695976"
+}
+
+// Normalize validates the identifiers and returns them in canonical form: trimmed, LEI and
+// VAT number upper case without separators. It fails when none is given or when the LEI or
+// VAT number is invalid (see NormalizeLEI and NormalizeVATNumber).
+func (ids Identifiers) Normalize() (Identifiers, error) {
+	ids.CoCNumber = strings.TrimSpace(ids.CoCNumber)
+	if ids.LEI != "" {
+		lei, err := NormalizeLEI(ids.LEI)
+		if err != nil {
+			return ids, err
+		}
+		ids.LEI = lei
+	}
+	if ids.VATNumber != "" {
+		vat, err := NormalizeVATNumber(ids.VATNumber)
+		if err != nil {
+			return ids, err
+		}
+		ids.VATNumber = vat
+	}
+	if ids.CoCNumber == "" && ids.LEI == "" && ids.VATNumber == "" {
+		return ids, fmt.Errorf("a CoC number, LEI or VAT number is required: it identifies the company")
+	}
+	return ids, nil
+}
+
+// Identifiers returns the registrations of the company.
+func (c *Company) Identifiers() Identifiers {
+	return Identifiers{CoCNumber: c.CoCNumber, LEI: c.LEI, VATNumber: c.VATNumber}
+}
+
+var leiPattern = regexp.MustCompile(`^[A-Z0-9]{18}[0-9]{2}$`)
+
+// NormalizeLEI validates a Legal Entity Identifier (ISO 17442): 20 characters, the last two
+// check digits under ISO 7064 MOD 97-10, like an IBAN. Spaces are ignored, letters may be
+// lower case.
+//
+// Example:
+//
+//	lei, err := company.NormalizeLEI("5493 001K JTII GC8Y 1R12") // → "5493001KJTIIGC8Y1R12", nil
+func NormalizeLEI(lei string) (string, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(lei), " ", ""))
+	if !leiPattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid LEI %q: expected 18 letters or digits followed by 2 check digits", lei)
+	}
+	if mod97(normalized) != 1 {
+		return "", fmt.Errorf("invalid LEI %q: check digits do not match", lei)
+	}
+	return normalized, nil
+}
+
+// LEIRecord is the entry of a LEI in the global LEI index (GLEIF).
+type LEIRecord struct {
+	LEI                string     `json:"lei"`
+	LegalName          string     `json:"legalName"`
+	City               string     `json:"city"`
+	Country            string     `json:"country"`            // ISO 3166 alpha-2 code of the legal address
+	EntityStatus       string     `json:"entityStatus"`       // ACTIVE or INACTIVE
+	RegistrationStatus string     `json:"registrationStatus"` // e.g. ISSUED, LAPSED, RETIRED
+	NextRenewal        *time.Time `json:"nextRenewal,omitempty"`
+}
+
+// CheckUsable fails when the LEI may not identify a company we trade with: the legal entity
+// is no longer active, or the LEI was retired, annulled or merged into another. Lapsed LEIs
+// (renewal overdue) are still usable.
+func (r *LEIRecord) CheckUsable() error {
+	if r.EntityStatus != "" && r.EntityStatus != "ACTIVE" {
+		return fmt.Errorf("LEI %s belongs to an %s legal entity", r.LEI, strings.ToLower(r.EntityStatus))
+	}
+	switch r.RegistrationStatus {
+	case "RETIRED", "ANNULLED", "DUPLICATE", "MERGED", "TRANSFERRED":
+		return fmt.Errorf("LEI %s is %s", r.LEI, r.RegistrationStatus)
+	}
+	return nil
+}
+
+// vatFormats lists the VAT number format per country prefix, after the prefix. Greece uses EL,
+// Northern Ireland XI; Switzerland and Norway have their own (non-EU) formats.
+var vatFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^U\d{8}$`),
+	"BE": regexp.MustCompile(`^[01]\d{9}$`),
+	"BG": regexp.MustCompile(`^\d{9,10}$`),
+	"CH": regexp.MustCompile(`^E\d{9}(MWST|TVA|IVA)?$`),
+	"CY": regexp.MustCompile(`^\d{8}[A-Z]$`),
+	"CZ": regexp.MustCompile(`^\d{8,10}$`),
+	"DE": regexp.MustCompile(`^\d{9}$`),
+	"DK": regexp.MustCompile(`^\d{8}$`),
+	"EE": regexp.MustCompile(`^\d{9}$`),
+	"EL": regexp.MustCompile(`^\d{9}$`),
+	"ES": regexp.MustCompile(`^[A-Z0-9]\d{7}[A-Z0-9]$`),
+	"FI": regexp.MustCompile(`^\d{8}$`),
+	"FR": regexp.MustCompile(`^[A-HJ-NP-Z0-9]{2}\d{9}$`),
+	"GB": regexp.MustCompile(`^(\d{9}|\d{12}|GD\d{3}|HA\d{3})$`),
+	"HR": regexp.MustCompile(`^\d{11}$`),
+	"HU": regexp.MustCompile(`^\d{8}$`),
+	"IE": regexp.MustCompile(`^(\d{7}[A-W][A-I]?|\d[A-Z+*]\d{5}[A-W])$`),
+	"IT": regexp.MustCompile(`^\d{11}$`),
+	"LT": regexp.MustCompile(`^(\d{9}|\d{12})$`),
+	"LU": regexp.MustCompile(`^\d{8}$`),
+	"LV": regexp.MustCompile(`^\d{11}$`),
+	"MT": regexp.MustCompile(`^\d{8}$`),
+	"NL": regexp.MustCompile(`^\d{9}B\d{2}$`),
+	"NO": regexp.MustCompile(`^\d{9}(MVA)?$`),
+	"PL": regexp.MustCompile(`^\d{10}$`),
+	"PT": regexp.MustCompile(`^\d{9}$`),
+	"RO": regexp.MustCompile(`^\d{2,10}$`),
+	"SE": regexp.MustCompile(`^\d{10}01$`),
+	"SI": regexp.MustCompile(`^\d{8}$`),
+	"SK": regexp.MustCompile(`^\d{10}$`),
+	"XI": regexp.MustCompile(`^(\d{9}|\d{12}|GD\d{3}|HA\d{3})$`),
+}
+
+// vatChecksums verifies the check digits of the countries whose algorithm is public; the
+// numbers of other countries are checked on format only.
+var vatChecksums = map[string]func(number string) bool{
+	"BE": vatChecksumBE,
+	"DE": vatChecksumDE,
+	"DK": vatChecksumDK,
+	"FR": vatChecksumFR,
+	"GB": vatChecksumGB,
+	"IT": vatChecksumIT,
+	"NL": vatChecksumNL,
+	"PL": vatChecksumPL,
+	"XI": vatChecksumGB,
+}
+
+// NormalizeVATNumber validates a VAT number with its country prefix and returns it upper case
+// without spaces, dots or dashes. The format of every EU country (plus GB, XI, CH and NO) is
+// checked; the check digits where the algorithm is public (BE, DE, DK, FR, GB, IT, NL, PL).
+//
+// Example:
+//
+//	vat, err := company.NormalizeVATNumber("de 136.695.976") // → "DE136695976", nil
+func NormalizeVATNumber(vat string) (string, error) {
+	normalized := strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(strings.TrimSpace(vat)))
+	if len(normalized) < 4 {
+		return "", fmt.Errorf("invalid VAT number %q: too short", vat)
+	}
+	country, number := normalized[:2], normalized[2:]
+	if country == "GR" {
+		country, normalized = "EL", "EL"+number // Greek VAT numbers carry the EL prefix
+	}
+
+	format, ok := vatFormats[country]
+	if !ok {
+		return "", fmt.Errorf("invalid VAT number %q: unknown country prefix %s", vat, country)
+	}
+	if !format.MatchString(number) {
+		return "", fmt.Errorf("invalid VAT number %q: not a valid %s format", vat, country)
+	}
+	if check, ok := vatChecksums[country]; ok && !check(number) {
+		return "", fmt.Errorf("invalid VAT number %q: check digits do not match", vat)
+	}
+	return normalized, nil
+}
+
+// mod97 returns the remainder of s modulo 97 with letters counting as 10 (A) to 35 (Z), as
+// used by ISO 7064 MOD 97-10.
+func mod97(s string) int {
+	remainder := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		}
+	}
+	return remainder
+}
+
+func digits(s string) []int {
+	d := make([]int, len(s))
+	for i, r := range s {
+		d[i] = int(r - '0')
+	}
+	return d
+}
+
+// weightedSum sums the digits of d multiplied by the weights, as far as both go.
+func weightedSum(d, weights []int) int {
+	sum := 0
+	for i := 0; i < len(d) && i < len(weights); i++ {
+		sum += d[i] * weights[i]
+	}
+	return sum
+}
+
+// vatChecksumBE: the last two digits are 97 minus the first eight modulo 97.
+func vatChecksumBE(n string) bool {
+	d := digits(n)
+	first := 0
+	for _, v := range d[:8] {
+		first = first*10 + v
+	}
+	return 97-first%97 == d[8]*10+d[9]
+}
+
+// vatChecksumDE: ISO 7064 MOD 11,10 over the first eight digits.
+func vatChecksumDE(n string) bool {
+	d := digits(n)
+	product := 10
+	for _, v := range d[:8] {
+		sum := (v + product) % 10
+		if sum == 0 {
+			sum = 10
+		}
+		product = (2 * sum) % 11
+	}
+	check := 11 - product
+	if check == 10 {
+		check = 0
+	}
+	return check == d[8]
+}
+
+// vatChecksumDK: the weighted sum of the eight digits is divisible by 11.
+func vatChecksumDK(n string) bool {
+	return weightedSum(digits(n), []int{2, 7, 6, 5, 4, 3, 2, 1})%11 == 0
+}
+
+// vatChecksumFR: a numeric key is (12 + 3 × (SIREN mod 97)) mod 97; letter keys (new-style
+// numbers) cannot be verified offline.
+func vatChecksumFR(n string) bool {
+	key := n[:2]
+	if strings.Trim(key, "0123456789") != "" {
+		return true
+	}
+	siren := 0
+	for _, v := range digits(n[2:]) {
+		siren = (siren*10 + v) % 97
+	}
+	k := digits(key)
+	return (12+3*siren)%97 == k[0]*10+k[1]
+}
+
+// vatChecksumGB: the weighted sum of the first seven digits plus the check number is divisible
+// by 97, either as is or (numbers issued since 2010) after adding 55. Government departments
+// (GD) and health authorities (HA) have no check digits.
+func vatChecksumGB(n string) bool {
+	if strings.HasPrefix(n, "GD") || strings.HasPrefix(n, "HA") {
+		return true
+	}
+	d := digits(n[:9])
+	total := weightedSum(d, []int{8, 7, 6, 5, 4, 3, 2}) + d[7]*10 + d[8]
+	return total%97 == 0 || (total+55)%97 == 0
+}
+
+// vatChecksumIT: Luhn over the eleven digits.
+func vatChecksumIT(n string) bool {
+	sum := 0
+	for i, v := range digits(n) {
+		if i%2 == 1 {
+			v *= 2
+			if v > 9 {
+				v -= 9
+			}
+		}
+		sum += v
+	}
+	return sum%10 == 0
+}
+
+// vatChecksumNL: the weighted sum of the first eight digits modulo 11 is the ninth digit
+// (numbers of legal entities), or the whole number including the NL prefix passes MOD 97-10
+// (numbers of sole proprietors, issued since 2020).
+func vatChecksumNL(n string) bool {
+	d := digits(n[:9])
+	if weightedSum(d, []int{9, 8, 7, 6, 5, 4, 3, 2})%11 == d[8] {
+		return true
+	}
+	return mod97("NL"+n) == 1
+}
+
+// vatChecksumPL: the weighted sum of the first nine digits modulo 11 is the tenth digit.
+func vatChecksumPL(n string) bool {
+	d := digits(n)
+	return weightedSum(d, []int{6, 5, 7, 2, 3, 4, 5, 6, 7})%11 == d[9]
+}
@@ -0,0 +1,134 @@
+package company
+
+import "testing"
+
+func TestNormalizeLEI(t *testing.T) {
+	tests := []struct {
+		name    string
+		lei     string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", lei: "5493001KJTIIGC8Y1R12", want: "5493001KJTIIGC8Y1R12"},
+		{name: "valid with letters", lei: "213800LH1BZH3DI6G760", want: "213800LH1BZH3DI6G760"},
+		{name: "spaces and lower case", lei: " 5493 001k jtii gc8y 1r12 ", want: "5493001KJTIIGC8Y1R12"},
+		{name: "wrong check digits", lei: "5493001KJTIIGC8Y1R13", wantErr: true},
+		{name: "transposed characters", lei: "4593001KJTIIGC8Y1R12", wantErr: true},
+		{name: "letter in check digits", lei: "5493001KJTIIGC8Y1R1A", wantErr: true},
+		{name: "too short", lei: "5493001KJTIIGC8Y1R1", wantErr: true},
+		{name: "too long", lei: "5493001KJTIIGC8Y1R121", wantErr: true},
+		{name: "separator", lei: "5493-001K-JTII-GC8Y-1R12", wantErr: true},
+		{name: "empty", lei: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeLEI(tt.lei)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeLEI(%q) = %q, want an error", tt.lei, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeLEI(%q): %v", tt.lei, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeLEI(%q) = %q, want %q", tt.lei, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeVATNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		vat     string
+		want    string
+		wantErr bool
+	}{
+		// Countries with check digits: one valid number and the same number with a wrong check digit
+		{name: "BE", vat: "BE0403170701", want: "BE0403170701"},
+		{name: "BE check digits", vat: "BE0403170702", wantErr: true},
+		{name: "DE", vat: "DE136695976", want: "DE136695976"},
+		{name: "DE separators", vat: "de 136.695.976", want: "DE136695976"},
+		{name: "DE check digit", vat: "DE136695977", wantErr: true},
+		{name: "DK", vat: "DK13585628", want: "DK13585628"},
+		{name: "DK check digit", vat: "DK13585627", wantErr: true},
+		{name: "FR numeric key", vat: "FR40303265045", want: "FR40303265045"},
+		{name: "FR numeric key mismatch", vat: "FR41303265045", wantErr: true},
+		{name: "FR letter key", vat: "FRK7399859412", want: "FRK7399859412"},
+		{name: "GB", vat: "GB980780684", want: "GB980780684"},
+		{name: "GB check digits", vat: "GB980780685", wantErr: true},
+		{name: "GB government department", vat: "GBGD001", want: "GBGD001"},
+		{name: "XI", vat: "XI980780684", want: "XI980780684"},
+		{name: "IT", vat: "IT00743110157", want: "IT00743110157"},
+		{name: "IT check digit", vat: "IT00743110158", wantErr: true},
+		{name: "NL legal entity", vat: "NL123456782B01", want: "NL123456782B01"},
+		{name: "NL check digit", vat: "NL123456783B01", wantErr: true},
+		{name: "PL", vat: "PL5260250274", want: "PL5260250274"},
+		{name: "PL check digit", vat: "PL5260250275", wantErr: true},
+
+		// Countries checked on format only
+		{name: "AT", vat: "ATU13585627", want: "ATU13585627"},
+		{name: "AT without U", vat: "AT13585627", wantErr: true},
+		{name: "ES", vat: "ESA28015865", want: "ESA28015865"},
+		{name: "SE", vat: "SE556036079301", want: "SE556036079301"},
+		{name: "SE without 01", vat: "SE556036079302", wantErr: true},
+		{name: "CH", vat: "CHE116281710MWST", want: "CHE116281710MWST"},
+		{name: "GR becomes EL", vat: "GR094014201", want: "EL094014201"},
+
+		{name: "unknown country", vat: "US123456789", wantErr: true},
+		{name: "wrong length", vat: "DE12345678", wantErr: true},
+		{name: "too short", vat: "DE1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeVATNumber(tt.vat)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeVATNumber(%q) = %q, want an error", tt.vat, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeVATNumber(%q): %v", tt.vat, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeVATNumber(%q) = %q, want %q", tt.vat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBusinessKeyUsesStrongestIdentifier(t *testing.T) {
+	const user = "user@internal.local"
+	newCompany := func(t *testing.T, ids Identifiers) Company {
+		t.Helper()
+		c, err := NewRegisteredCompany("BP", "BP", "BP", ids, "London", "1 St James's Square", user)
+		if err != nil {
+			t.Fatalf("NewRegisteredCompany(%+v): %v", ids, err)
+		}
+		return c
+	}
+
+	// The LEI wins over the CoC number, so a typo in the CoC number still dedups
+	a := newCompany(t, Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760"})
+	b := newCompany(t, Identifiers{CoCNumber: "00102499", LEI: "2138 00lh 1bzh 3di6 g760"})
+	if a.BusinessKey != b.BusinessKey {
+		t.Error("companies with the same LEI have different business keys")
+	}
+
+	// The same digits as CoC number and as VAT number are different identifiers
+	coc := newCompany(t, Identifiers{CoCNumber: "GB980780684"})
+	vat := newCompany(t, Identifiers{VATNumber: "GB980780684"})
+	if coc.BusinessKey == vat.BusinessKey {
+		t.Error("a CoC number and a VAT number with the same value share a business key")
+	}
+
+	if _, err := NewRegisteredCompany("BP", "BP", "BP", Identifiers{}, "London", "", user); err == nil {
+		t.Error("NewRegisteredCompany without identifiers succeeded")
+	}
+	if _, err := NewRegisteredCompany("BP", "BP", "BP", Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G761"}, "London", "", user); err == nil {
+		t.Error("NewRegisteredCompany with an invalid LEI succeeded")
+	}
+}
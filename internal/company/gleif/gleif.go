@@ -0,0 +1,126 @@
+package gleif
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	company "github.com/nholding/cso-book/internal/company/domain"
+)
+
+// DefaultBaseURL is the public GLEIF API; it needs no API key.
+const DefaultBaseURL = "https://api.gleif.org/api/v1"
+
+// Client looks up Legal Entity Identifiers in the global LEI index of the Global Legal Entity
+// Identifier Foundation (GLEIF).
+//
+// EXAMPLE USAGE:
+//
+//	client := gleif.NewClient(nil, "")
+//	companyService.SetLEIRegistry(client)
+//
+//	record, err := client.LookupLEI(ctx, "213800LH1BZH3DI6G760")
+//	// record.LegalName → "BP P.L.C.", record.RegistrationStatus → "ISSUED"
+type Client struct {
+	http    *http.Client
+	baseURL string
+}
+
+// NewClient creates a Client; a nil httpClient means one with a 10 second timeout, an empty
+// baseURL DefaultBaseURL.
+func NewClient(httpClient *http.Client, baseURL string) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{http: httpClient, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// LookupLEI returns the GLEIF record of a LEI; nil, nil when GLEIF does not know it.
+func (c *Client) LookupLEI(ctx context.Context, lei string) (*company.LEIRecord, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/lei-records/"+url.PathEscape(lei), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.api+json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GLEIF: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to query GLEIF: %s", resp.Status)
+	}
+
+	return ParseLEIRecord(resp.Body)
+}
+
+// leiDocument mirrors the part of a GLEIF lei-records response that is used:
+//
+//	{"data": {"attributes": {"lei": "...",
+//	  "entity": {"legalName": {"name": "..."}, "legalAddress": {"city": "...", "country": "GB"}, "status": "ACTIVE"},
+//	  "registration": {"status": "ISSUED", "nextRenewalDate": "2026-07-01T00:00:00Z"}}}}
+type leiDocument struct {
+	Data struct {
+		Attributes struct {
+			LEI    string `json:"lei"`
+			Entity struct {
+				LegalName struct {
+					Name string `json:"name"`
+				} `json:"legalName"`
+				LegalAddress struct {
+					City    string `json:"city"`
+					Country string `json:"country"`
+				} `json:"legalAddress"`
+				Status string `json:"status"`
+			} `json:"entity"`
+			Registration struct {
+				Status          string `json:"status"`
+				NextRenewalDate string `json:"nextRenewalDate"`
+			} `json:"registration"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// ParseLEIRecord parses a GLEIF lei-records response.
+func ParseLEIRecord(r io.Reader) (*company.LEIRecord, error) {
+	var doc leiDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse GLEIF record: %w", err)
+	}
+
+	a := doc.Data.Attributes
+	if a.LEI == "" {
+		return nil, fmt.Errorf("GLEIF record: the response contains no LEI")
+	}
+	record := &company.LEIRecord{
+		LEI:                a.LEI,
+		LegalName:          a.Entity.LegalName.Name,
+		City:               a.Entity.LegalAddress.City,
+		Country:            a.Entity.LegalAddress.Country,
+		EntityStatus:       a.Entity.Status,
+		RegistrationStatus: a.Registration.Status,
+	}
+	if a.Registration.NextRenewalDate != "" {
+		renewal, err := time.Parse(time.RFC3339, a.Registration.NextRenewalDate)
+		if err != nil {
+			return nil, fmt.Errorf("GLEIF record %s: invalid renewal date %q", a.LEI, a.Registration.NextRenewalDate)
+		}
+		renewal = renewal.UTC()
+		record.NextRenewal = &renewal
+	}
+	return record, nil
+}
@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// CompanyRepository defines the interface for storing and retrieving Companies from a persistence layer
+type CompanyRepository interface {
+	// Save inserts a new company; it fails when a company with the same BusinessKey exists
+	Save(ctx context.Context, c *company.Company) error
+
+	// Update updates an existing company, checked against its RowVersion (optimistic
+	// concurrency; a lost race returns *dberr.ConflictError)
+	Update(ctx context.Context, c *company.Company) error
+
+	// FindByID retrieves a company, including tombstoned ones; returns nil, nil when it does not exist
+	FindByID(ctx context.Context, id string) (*company.Company, error)
+
+	// FindByBusinessKey retrieves the company with a deduplication key; returns nil, nil when none exists
+	FindByBusinessKey(ctx context.Context, businessKey string) (*company.Company, error)
+
+	// FindByIdentifiers retrieves the companies sharing any of the non-empty identifiers,
+	// live companies first
+	FindByIdentifiers(ctx context.Context, ids company.Identifiers) ([]*company.Company, error)
+
+	// List retrieves all companies that were not merged into another, ordered by name
+	List(ctx context.Context) ([]*company.Company, error)
+
+	// Merge re-points every reference from sourceID to targetID and tombstones the source,
+	// all inside a single transaction.
+	Merge(ctx context.Context, sourceID, targetID, mergedBy string) (*company.MergeRecord, error)
+}
+
+// companyReference describes a column in another table that holds a company ID.
+type companyReference struct {
+	Table  string
+	Column string
+}
+
+// companyReferences lists every table/column that points at a company.
+// When a new table references companies, add it here so Merge re-points it as well.
+var companyReferences = []companyReference{
+	{Table: "trades", Column: "counterparty_id"},
+	{Table: "invoices", Column: "company_id"},
+	{Table: "credit_limits", Column: "company_id"},
+	{Table: "contact_persons", Column: "company_id"},
+}
+
+// Compile-time check that RdsCompanyRepository satisfies CompanyRepository
+var _ CompanyRepository = (*RdsCompanyRepository)(nil)
+
+type RdsCompanyRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsCompanyRepository(cfg *awsclient.Config) (*RdsCompanyRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsCompanyRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// Save inserts a new company. The business_key column is unique, so a company that is already
+// known (same CoC number) is rejected instead of being stored twice.
+//
+// Example:
+//
+//	c, _ := company.NewCompany("British Petroleum", "BP", "BP", "12345678", "London", "1 St James's Square", "user@internal.local")
+//	err := repo.Save(ctx, &c)
+func (r *RdsCompanyRepository) Save(ctx context.Context, c *company.Company) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO companies (
+			id, business_key, version, name, common_name, display_name, coc_number, city, address,
+			contact_person_id, merged_into_id, row_version,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at,
+			kyc_status, kyc_approved_at, kyc_expires_at, sanctions_hit, sanctions_screened_at, sanctions_note,
+			lei, vat_number
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,1,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23)
+	`,
+		c.ID,
+		c.BusinessKey,
+		c.Version,
+		c.Name,
+		c.CommonName,
+		c.DisplayName,
+		c.CoCNumber,
+		c.City,
+		c.Address,
+		c.ContactPersonID,
+		c.MergedIntoID,
+		c.AuditInfo.CreatedBy,
+		c.AuditInfo.CreatedAt,
+		c.AuditInfo.UpdatedBy,
+		c.AuditInfo.UpdatedAt,
+		string(kycStatus(c)),
+		c.KYCApprovedAt,
+		c.KYCExpiresAt,
+		c.SanctionsHit,
+		c.SanctionsScreenedAt,
+		c.SanctionsNote,
+		c.LEI,
+		c.VATNumber,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "company", ID: c.ID, Key: "business key " + c.BusinessKey, Err: err}
+		}
+		return fmt.Errorf("failed to insert company %s: %w", c.ID, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityCompany, c.ID, audit.ActionCreate, c.AuditInfo.CreatedBy,
+		map[string]any{"name": c.Name, "businessKey": c.BusinessKey})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit company transaction: %w", err)
+	}
+
+	c.RowVersion = 1
+	return nil
+}
+
+// Update writes the current state of an existing company. The ID, business key and creation
+// audit never change; tombstoning goes through Merge.
+//
+// Updates are checked with optimistic concurrency: c must carry the RowVersion it was read
+// with. When the stored company was changed in the meantime, nothing is written and a
+// *dberr.ConflictError is returned. On success c.RowVersion is incremented to match the DB.
+func (r *RdsCompanyRepository) Update(ctx context.Context, c *company.Company) error {
+	expected := c.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE companies
+		SET name=$1, common_name=$2, display_name=$3, coc_number=$4, city=$5, address=$6,
+		    contact_person_id=$7, audit_updated_by=$8, audit_updated_at=$9, row_version=row_version+1,
+		    kyc_status=$12, kyc_approved_at=$13, kyc_expires_at=$14, sanctions_hit=$15, sanctions_screened_at=$16, sanctions_note=$17,
+		    lei=$18, vat_number=$19
+		WHERE id=$10 AND row_version=$11
+	`,
+		c.Name,
+		c.CommonName,
+		c.DisplayName,
+		c.CoCNumber,
+		c.City,
+		c.Address,
+		c.ContactPersonID,
+		c.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		c.ID,
+		expected,
+		string(kycStatus(c)),
+		c.KYCApprovedAt,
+		c.KYCExpiresAt,
+		c.SanctionsHit,
+		c.SanctionsScreenedAt,
+		c.SanctionsNote,
+		c.LEI,
+		c.VATNumber,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update company %s: %w", c.ID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM companies WHERE id=$1`, c.ID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "company", ID: c.ID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of company %s: %w", c.ID, err)
+		}
+		return &dberr.ConflictError{Entity: "company", ID: c.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityCompany, c.ID, audit.ActionUpdate, c.AuditInfo.LastActor(),
+		map[string]any{"name": c.Name, "cocNumber": c.CoCNumber, "lei": c.LEI, "vatNumber": c.VATNumber, "city": c.City, "address": c.Address,
+			"kycStatus": kycStatus(c), "kycExpiresAt": c.KYCExpiresAt, "sanctionsHit": c.SanctionsHit})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit company transaction: %w", err)
+	}
+
+	c.RowVersion = expected + 1
+	return nil
+}
+
+// FindByID retrieves a single company by ID. Returns nil, nil when the company does not exist.
+func (r *RdsCompanyRepository) FindByID(ctx context.Context, id string) (*company.Company, error) {
+	return r.findOne(ctx, `SELECT `+companyColumns+` FROM companies WHERE id=$1`, id)
+}
+
+// FindByBusinessKey retrieves the company with the given deduplication key
+// (see Company.GenerateKeys). Returns nil, nil when no such company exists.
+//
+// Example (check for a duplicate before creating a company):
+//
+//	c, _ := company.NewCompany(name, commonName, displayName, coc, city, address, user)
+//	existing, err := repo.FindByBusinessKey(ctx, c.BusinessKey)
+func (r *RdsCompanyRepository) FindByBusinessKey(ctx context.Context, businessKey string) (*company.Company, error) {
+	return r.findOne(ctx, `SELECT `+companyColumns+` FROM companies WHERE business_key=$1`, businessKey)
+}
+
+// FindByIdentifiers retrieves the companies registered under any of the given LEI, VAT number
+// or CoC number, whatever key version they were stored with. Live companies come first, then
+// those sharing the strongest identifier (LEI before VAT number before CoC number).
+//
+// Example (companies the new company would duplicate):
+//
+//	matches, err := repo.FindByIdentifiers(ctx, company.Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760"})
+func (r *RdsCompanyRepository) FindByIdentifiers(ctx context.Context, ids company.Identifiers) ([]*company.Company, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+		SELECT `+companyColumns+` FROM companies
+		WHERE ($1 <> '' AND lei=$1) OR ($2 <> '' AND vat_number=$2) OR ($3 <> '' AND coc_number=$3)
+		ORDER BY merged_into_id IS NOT NULL, ($1 <> '' AND lei=$1) DESC, ($2 <> '' AND vat_number=$2) DESC, id
+	`, ids.LEI, ids.VATNumber, ids.CoCNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies by identifiers: %w", err)
+	}
+	defer rows.Close()
+
+	var companies []*company.Company
+	for rows.Next() {
+		c, err := scanCompany(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies = append(companies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate company rows: %w", err)
+	}
+
+	return companies, nil
+}
+
+// List retrieves all live companies: tombstoned (merged) companies are left out.
+func (r *RdsCompanyRepository) List(ctx context.Context) ([]*company.Company, error) {
+	rows, err := r.reader.QueryContext(ctx,
+		`SELECT `+companyColumns+` FROM companies WHERE merged_into_id IS NULL ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	var companies []*company.Company
+	for rows.Next() {
+		c, err := scanCompany(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies = append(companies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate company rows: %w", err)
+	}
+
+	return companies, nil
+}
+
+func (r *RdsCompanyRepository) findOne(ctx context.Context, query string, arg string) (*company.Company, error) {
+	c, err := scanCompany(r.reader.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan company: %w", err)
+	}
+
+	return c, nil
+}
+
+// companyColumns is the column list scanned by scanCompany.
+const companyColumns = `id, business_key, version, name, common_name, display_name, coc_number, city, address,
+	contact_person_id, merged_into_id, row_version, audit_created_by, audit_created_at, audit_updated_by, audit_updated_at,
+	kyc_status, kyc_approved_at, kyc_expires_at, sanctions_hit, sanctions_screened_at, sanctions_note, lei, vat_number`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCompany(row rowScanner) (*company.Company, error) {
+	var (
+		c   company.Company
+		kyc string
+	)
+	if err := row.Scan(
+		&c.ID,
+		&c.BusinessKey,
+		&c.Version,
+		&c.Name,
+		&c.CommonName,
+		&c.DisplayName,
+		&c.CoCNumber,
+		&c.City,
+		&c.Address,
+		&c.ContactPersonID,
+		&c.MergedIntoID,
+		&c.RowVersion,
+		&c.AuditInfo.CreatedBy,
+		&c.AuditInfo.CreatedAt,
+		&c.AuditInfo.UpdatedBy,
+		&c.AuditInfo.UpdatedAt,
+		&kyc,
+		&c.KYCApprovedAt,
+		&c.KYCExpiresAt,
+		&c.SanctionsHit,
+		&c.SanctionsScreenedAt,
+		&c.SanctionsNote,
+		&c.LEI,
+		&c.VATNumber,
+	); err != nil {
+		return nil, err
+	}
+	c.KYCStatus = company.KYCStatus(kyc)
+
+	return &c, nil
+}
+
+// kycStatus returns the stored KYC status of a company; PENDING when none was set.
+func kycStatus(c *company.Company) company.KYCStatus {
+	if c.KYCStatus == "" {
+		return company.KYCStatusPending
+	}
+	return c.KYCStatus
+}
+
+// Merge re-points all references (trades, invoices, credit limits) from the source company
+// to the target company, tombstones the source and records the merge in company_merges.
+// Everything happens in one transaction: either all references move, or none do. When both
+// companies have a credit limit, the target keeps its own and the source's is dropped.
+//
+// Example:
+//
+//	rec, err := repo.Merge(ctx, duplicateID, survivorID, "user@internal.local")
+//	// rec.RepointedRows → map[trades.counterparty_id:12 invoices.company_id:3 credit_limits.company_id:1]
+func (r *RdsCompanyRepository) Merge(ctx context.Context, sourceID, targetID, mergedBy string) (*company.MergeRecord, error) {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rec := &company.MergeRecord{
+		SourceID:      sourceID,
+		TargetID:      targetID,
+		MergedBy:      mergedBy,
+		MergedAt:      time.Now().UTC(),
+		RepointedRows: make(map[string]int64),
+	}
+
+	// A company has one credit limit: the survivor keeps its own, the source's is dropped
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM credit_limits WHERE company_id=$1 AND EXISTS (SELECT 1 FROM credit_limits WHERE company_id=$2)
+	`, sourceID, targetID); err != nil {
+		return nil, fmt.Errorf("failed to drop the credit limit of company %s: %w", sourceID, err)
+	}
+
+	for _, ref := range companyReferences {
+		query := fmt.Sprintf(`UPDATE %s SET %s=$1 WHERE %s=$2`, ref.Table, ref.Column, ref.Column)
+
+		res, err := tx.ExecContext(ctx, query, targetID, sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-point %s.%s from %s to %s: %w", ref.Table, ref.Column, sourceID, targetID, err)
+		}
+
+		rows, _ := res.RowsAffected()
+		rec.RepointedRows[ref.Table+"."+ref.Column] = rows
+	}
+
+	// Tombstone the source. The merged_into_id IS NULL guard protects against concurrent merges.
+	res, err := tx.ExecContext(ctx, `
+		UPDATE companies
+		SET merged_into_id=$1, audit_updated_by=$2, audit_updated_at=$3, row_version=row_version+1
+		WHERE id=$4 AND merged_into_id IS NULL
+	`, targetID, mergedBy, rec.MergedAt, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tombstone company %s: %w", sourceID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, &dberr.NotFoundError{Entity: "company", ID: sourceID, Reason: "does not exist or was already merged"}
+	}
+
+	// Touch the surviving company's audit info so the merge is visible on the target as well
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE companies SET audit_updated_by=$1, audit_updated_at=$2, row_version=row_version+1 WHERE id=$3
+	`, mergedBy, rec.MergedAt, targetID); err != nil {
+		return nil, fmt.Errorf("failed to update audit info of company %s: %w", targetID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO company_merges (source_id, target_id, merged_by, merged_at) VALUES ($1,$2,$3,$4)
+	`, rec.SourceID, rec.TargetID, rec.MergedBy, rec.MergedAt); err != nil {
+		return nil, fmt.Errorf("failed to record merge of %s into %s: %w", sourceID, targetID, err)
+	}
+
+	details := map[string]any{"sourceId": sourceID, "targetId": targetID, "repointedRows": rec.RepointedRows}
+	if err := auditrepo.RecordTx(ctx, tx.Tx,
+		audit.NewEvent(audit.EntityCompany, sourceID, audit.ActionMerge, mergedBy, details),
+		audit.NewEvent(audit.EntityCompany, targetID, audit.ActionMerge, mergedBy, details),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge transaction: %w", err)
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// ContactRepository stores the contact persons of companies.
+type ContactRepository interface {
+	// SaveContact inserts a new contact person; it fails with *dberr.DuplicateError when the ID is taken
+	SaveContact(ctx context.Context, p *company.ContactPerson) error
+
+	// UpdateContact updates an existing contact person, checked against its RowVersion
+	// (optimistic concurrency; a lost race returns *dberr.ConflictError)
+	UpdateContact(ctx context.Context, p *company.ContactPerson) error
+
+	// DeleteContact removes a contact person; it fails with *dberr.NotFoundError when it does not exist
+	DeleteContact(ctx context.Context, id, user string) error
+
+	// FindContactByID retrieves a contact person; returns nil, nil when it does not exist
+	FindContactByID(ctx context.Context, id string) (*company.ContactPerson, error)
+
+	// ListContacts retrieves the contact persons of a company, ordered by role and name
+	ListContacts(ctx context.Context, companyID string) ([]*company.ContactPerson, error)
+}
+
+// Compile-time check that RdsContactRepository satisfies ContactRepository
+var _ ContactRepository = (*RdsContactRepository)(nil)
+
+// RdsContactRepository keeps contact persons in contact_persons.
+type RdsContactRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsContactRepository(cfg *awsclient.Config) (*RdsContactRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsContactRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveContact inserts a contact person. The same address may be stored twice for a company (a
+// merge brings the contacts of both companies together); CompanyService.AddContact prevents it
+// for new contacts.
+//
+// Example:
+//
+//	p, _ := company.NewContactPerson(companyID, "Jane Smith", "confirmations@bp.example", "", company.ContactRoleConfirmations, "user@internal.local")
+//	err := repo.SaveContact(ctx, &p)
+func (r *RdsContactRepository) SaveContact(ctx context.Context, p *company.ContactPerson) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO contact_persons (
+			id, company_id, name, email, phone, role, row_version,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,1,$7,$8,$9,$10)
+	`,
+		p.ID,
+		p.CompanyID,
+		p.Name,
+		p.Email,
+		p.Phone,
+		string(p.Role),
+		p.AuditInfo.CreatedBy,
+		p.AuditInfo.CreatedAt,
+		p.AuditInfo.UpdatedBy,
+		p.AuditInfo.UpdatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "contact person", ID: p.ID, Err: err}
+		}
+		return fmt.Errorf("failed to insert contact person %s: %w", p.ID, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityContactPerson, p.ID, audit.ActionCreate, p.AuditInfo.CreatedBy,
+		map[string]any{"companyId": p.CompanyID, "name": p.Name, "email": p.Email, "role": p.Role})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contact person transaction: %w", err)
+	}
+
+	p.RowVersion = 1
+	return nil
+}
+
+// UpdateContact writes the current state of an existing contact person. The ID, company and
+// creation audit never change.
+//
+// Updates are checked with optimistic concurrency: p must carry the RowVersion it was read
+// with. On success p.RowVersion is incremented to match the DB.
+func (r *RdsContactRepository) UpdateContact(ctx context.Context, p *company.ContactPerson) error {
+	expected := p.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE contact_persons
+		SET name=$1, email=$2, phone=$3, role=$4, audit_updated_by=$5, audit_updated_at=$6, row_version=row_version+1
+		WHERE id=$7 AND row_version=$8
+	`,
+		p.Name,
+		p.Email,
+		p.Phone,
+		string(p.Role),
+		p.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		p.ID,
+		expected,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update contact person %s: %w", p.ID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM contact_persons WHERE id=$1`, p.ID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "contact person", ID: p.ID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of contact person %s: %w", p.ID, err)
+		}
+		return &dberr.ConflictError{Entity: "contact person", ID: p.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityContactPerson, p.ID, audit.ActionUpdate, p.AuditInfo.LastActor(),
+		map[string]any{"name": p.Name, "email": p.Email, "phone": p.Phone, "role": p.Role})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contact person transaction: %w", err)
+	}
+
+	p.RowVersion = expected + 1
+	return nil
+}
+
+// DeleteContact removes a contact person; the audit log keeps who removed it.
+func (r *RdsContactRepository) DeleteContact(ctx context.Context, id, user string) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var companyID, email string
+	err = tx.QueryRowContext(ctx, `DELETE FROM contact_persons WHERE id=$1 RETURNING company_id, email`, id).Scan(&companyID, &email)
+	if err == sql.ErrNoRows {
+		return &dberr.NotFoundError{Entity: "contact person", ID: id}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete contact person %s: %w", id, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityContactPerson, id, audit.ActionDelete, user,
+		map[string]any{"companyId": companyID, "email": email})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contact person transaction: %w", err)
+	}
+	return nil
+}
+
+// FindContactByID retrieves a single contact person. Returns nil, nil when it does not exist.
+func (r *RdsContactRepository) FindContactByID(ctx context.Context, id string) (*company.ContactPerson, error) {
+	p, err := scanContact(r.reader.QueryRowContext(ctx, `SELECT `+contactColumns+` FROM contact_persons WHERE id=$1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan contact person: %w", err)
+	}
+	return p, nil
+}
+
+// ListContacts retrieves the contact persons of a company, ordered by role and name.
+//
+// Example:
+//
+//	contacts, err := repo.ListContacts(ctx, companyID)
+func (r *RdsContactRepository) ListContacts(ctx context.Context, companyID string) ([]*company.ContactPerson, error) {
+	rows, err := r.reader.QueryContext(ctx,
+		`SELECT `+contactColumns+` FROM contact_persons WHERE company_id=$1 ORDER BY role, name, id`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact persons of company %s: %w", companyID, err)
+	}
+	defer rows.Close()
+
+	var contacts []*company.ContactPerson
+	for rows.Next() {
+		p, err := scanContact(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contact person: %w", err)
+		}
+		contacts = append(contacts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate contact person rows: %w", err)
+	}
+	return contacts, nil
+}
+
+// contactColumns is the column list scanned by scanContact.
+const contactColumns = `id, company_id, name, email, phone, role, row_version,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+func scanContact(row rowScanner) (*company.ContactPerson, error) {
+	var (
+		p    company.ContactPerson
+		role string
+	)
+	if err := row.Scan(
+		&p.ID,
+		&p.CompanyID,
+		&p.Name,
+		&p.Email,
+		&p.Phone,
+		&role,
+		&p.RowVersion,
+		&p.AuditInfo.CreatedBy,
+		&p.AuditInfo.CreatedAt,
+		&p.AuditInfo.UpdatedBy,
+		&p.AuditInfo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	p.Role = company.ContactRole(role)
+
+	return &p, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// CreditLimitRepository stores the credit limits and ratings of companies.
+type CreditLimitRepository interface {
+	// SaveCreditLimit inserts the limit of a company; it fails with *dberr.DuplicateError when
+	// the company already has one
+	SaveCreditLimit(ctx context.Context, l *company.CreditLimit) error
+
+	// UpdateCreditLimit updates the limit of a company, checked against its RowVersion
+	// (optimistic concurrency; a lost race returns *dberr.ConflictError)
+	UpdateCreditLimit(ctx context.Context, l *company.CreditLimit) error
+
+	// FindCreditLimit retrieves the limit of a company; returns nil, nil when it has none
+	FindCreditLimit(ctx context.Context, companyID string) (*company.CreditLimit, error)
+
+	// ListCreditLimits retrieves the limits matching the filter, by review date
+	ListCreditLimits(ctx context.Context, filter CreditLimitFilter) ([]*company.CreditLimit, error)
+}
+
+// CreditLimitFilter narrows ListCreditLimits. Zero fields match everything.
+//
+// Example (C and D rated limits to review by the end of June):
+//
+//	CreditLimitFilter{Ratings: []company.CreditRating{company.CreditRatingC, company.CreditRatingD},
+//	    ReviewDueBy: time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)}
+type CreditLimitFilter struct {
+	Ratings     []company.CreditRating
+	ReviewDueBy time.Time // Limits with a review date on or before this date
+}
+
+// Compile-time check that RdsCreditLimitRepository satisfies CreditLimitRepository
+var _ CreditLimitRepository = (*RdsCreditLimitRepository)(nil)
+
+// RdsCreditLimitRepository keeps credit limits in credit_limits.
+type RdsCreditLimitRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsCreditLimitRepository(cfg *awsclient.Config) (*RdsCreditLimitRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsCreditLimitRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveCreditLimit inserts the first limit of a company. company_id is the primary key, so a
+// company has one limit; later changes go through UpdateCreditLimit.
+//
+// Example:
+//
+//	l, _ := company.NewCreditLimit(companyID, 5_000_000, "EUR", company.CreditRatingB, reviewDate, "credit@internal.local")
+//	err := repo.SaveCreditLimit(ctx, &l)
+func (r *RdsCreditLimitRepository) SaveCreditLimit(ctx context.Context, l *company.CreditLimit) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO credit_limits (
+			company_id, amount, currency, rating, review_date, row_version,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,1,$6,$7,$8,$9)
+	`,
+		l.CompanyID,
+		l.Amount,
+		l.Currency,
+		string(l.Rating),
+		l.ReviewDate,
+		l.AuditInfo.CreatedBy,
+		l.AuditInfo.CreatedAt,
+		l.AuditInfo.UpdatedBy,
+		l.AuditInfo.UpdatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "credit limit", ID: l.CompanyID, Err: err}
+		}
+		return fmt.Errorf("failed to insert credit limit of company %s: %w", l.CompanyID, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityCreditLimit, l.CompanyID, audit.ActionCreate, l.AuditInfo.CreatedBy,
+		creditLimitDetails(l))); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit credit limit transaction: %w", err)
+	}
+
+	l.RowVersion = 1
+	return nil
+}
+
+// UpdateCreditLimit writes the current amount, rating and review date of a company's limit.
+//
+// Updates are checked with optimistic concurrency: l must carry the RowVersion it was read
+// with. On success l.RowVersion is incremented to match the DB.
+func (r *RdsCreditLimitRepository) UpdateCreditLimit(ctx context.Context, l *company.CreditLimit) error {
+	expected := l.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE credit_limits
+		SET amount=$1, currency=$2, rating=$3, review_date=$4, audit_updated_by=$5, audit_updated_at=$6, row_version=row_version+1
+		WHERE company_id=$7 AND row_version=$8
+	`,
+		l.Amount,
+		l.Currency,
+		string(l.Rating),
+		l.ReviewDate,
+		l.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		l.CompanyID,
+		expected,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update credit limit of company %s: %w", l.CompanyID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM credit_limits WHERE company_id=$1`, l.CompanyID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "credit limit", ID: l.CompanyID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of credit limit %s: %w", l.CompanyID, err)
+		}
+		return &dberr.ConflictError{Entity: "credit limit", ID: l.CompanyID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityCreditLimit, l.CompanyID, audit.ActionUpdate, l.AuditInfo.LastActor(),
+		creditLimitDetails(l))); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit credit limit transaction: %w", err)
+	}
+
+	l.RowVersion = expected + 1
+	return nil
+}
+
+// FindCreditLimit retrieves the limit of a company. Returns nil, nil when it has none.
+func (r *RdsCreditLimitRepository) FindCreditLimit(ctx context.Context, companyID string) (*company.CreditLimit, error) {
+	l, err := scanCreditLimit(r.reader.QueryRowContext(ctx, `SELECT `+creditLimitColumns+` FROM credit_limits WHERE company_id=$1`, companyID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan credit limit: %w", err)
+	}
+	return l, nil
+}
+
+// ListCreditLimits retrieves the limits matching the filter, earliest review date first.
+//
+// Example (limits due for review this week):
+//
+//	due, err := repo.ListCreditLimits(ctx, CreditLimitFilter{ReviewDueBy: time.Now().AddDate(0, 0, 7)})
+func (r *RdsCreditLimitRepository) ListCreditLimits(ctx context.Context, filter CreditLimitFilter) ([]*company.CreditLimit, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if len(filter.Ratings) > 0 {
+		ratings := make([]string, len(filter.Ratings))
+		for i, rating := range filter.Ratings {
+			ratings[i] = string(rating)
+		}
+		add("rating = ANY($%d)", pq.Array(ratings))
+	}
+	if !filter.ReviewDueBy.IsZero() {
+		add("review_date <= $%d", filter.ReviewDueBy)
+	}
+
+	query := `SELECT ` + creditLimitColumns + ` FROM credit_limits`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY review_date, company_id`
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query credit limits: %w", err)
+	}
+	defer rows.Close()
+
+	var limits []*company.CreditLimit
+	for rows.Next() {
+		l, err := scanCreditLimit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan credit limit: %w", err)
+		}
+		limits = append(limits, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate credit limit rows: %w", err)
+	}
+	return limits, nil
+}
+
+// creditLimitColumns is the column list scanned by scanCreditLimit.
+const creditLimitColumns = `company_id, amount, currency, rating, review_date, row_version,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+func scanCreditLimit(row rowScanner) (*company.CreditLimit, error) {
+	var (
+		l      company.CreditLimit
+		rating string
+	)
+	if err := row.Scan(
+		&l.CompanyID,
+		&l.Amount,
+		&l.Currency,
+		&rating,
+		&l.ReviewDate,
+		&l.RowVersion,
+		&l.AuditInfo.CreatedBy,
+		&l.AuditInfo.CreatedAt,
+		&l.AuditInfo.UpdatedBy,
+		&l.AuditInfo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	l.Rating = company.CreditRating(rating)
+	l.ReviewDate = l.ReviewDate.UTC()
+
+	return &l, nil
+}
+
+func creditLimitDetails(l *company.CreditLimit) map[string]any {
+	return map[string]any{
+		"amount":     l.Amount,
+		"currency":   l.Currency,
+		"rating":     l.Rating,
+		"reviewDate": l.ReviewDate.Format(time.DateOnly),
+	}
+}
@@ -0,0 +1,719 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/company/repository"
+	"github.com/nholding/cso-book/internal/exposure"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/trade/confirmation"
+)
+
+// Compile-time checks that CompanyService can address trade confirmations and provide the
+// credit limits of the exposure checks
+var (
+	_ confirmation.ContactSource = (*CompanyService)(nil)
+	_ exposure.LimitSource       = (*CompanyService)(nil)
+)
+
+type CompanyService struct {
+	repo     repository.CompanyRepository
+	contacts repository.ContactRepository     // nil: companies have no contact persons
+	credit   repository.CreditLimitRepository // nil: companies have no credit limits
+	leis     LEIRegistry                      // nil: LEIs are checked on their checksum only
+	now      func() time.Time
+}
+
+// LEIRegistry looks up Legal Entity Identifiers; *gleif.Client implements it.
+type LEIRegistry interface {
+	// LookupLEI returns the registry entry of a LEI; nil, nil when it is not registered
+	LookupLEI(ctx context.Context, lei string) (*company.LEIRecord, error)
+}
+
+func NewCompanyService(repo repository.CompanyRepository) *CompanyService {
+	return &CompanyService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// SetLEIRegistry makes Create and Update check new LEIs against a LEI registry, e.g. GLEIF.
+func (s *CompanyService) SetLEIRegistry(leis LEIRegistry) {
+	s.leis = leis
+}
+
+// SetCreditLimits enables the credit limits and ratings of companies, e.g. as the
+// exposure.LimitSource of the credit check on confirmation.
+func (s *CompanyService) SetCreditLimits(credit repository.CreditLimitRepository) {
+	s.credit = credit
+}
+
+// SetContacts enables the contact persons of companies, e.g. as recipients of confirmations.
+func (s *CompanyService) SetContacts(contacts repository.ContactRepository) {
+	s.contacts = contacts
+}
+
+// Create
+//
+// PURPOSE:
+//
+//	Registers a company unless it is already known. Before inserting, the company is looked
+//	up by its BusinessKey (see Company.GenerateKeys) and by each of its identifiers, so a
+//	company stored under an older key version or under another identifier is found as well:
+//
+//	  new identifiers                 → the company is stored and returned, created = true
+//	  LEI, VAT or CoC number known    → the existing company is returned, created = false
+//	  known, but merged away          → the company it was merged into is returned, created = false
+//
+//	The LEI and VAT number must pass their checksums. With a LEI registry configured
+//	(SetLEIRegistry) a new company's LEI must also exist in it and be usable.
+//
+//	A concurrent create of the same company loses on the unique business key and returns the
+//	winner as well, so callers never see a duplicate error.
+//
+// EXAMPLE USAGE:
+//
+//	c, created, err := companyService.Create(ctx, "British Petroleum", "BP", "BP",
+//	    company.Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760"},
+//	    "London", "1 St James's Square", "user@internal.local")
+//	if !created {
+//	    log.Printf("company %s already exists", c.ID)
+//	}
+func (s *CompanyService) Create(ctx context.Context, name, commonName, displayName string, ids company.Identifiers, city, address, user string) (*company.Company, bool, error) {
+	if user == "" {
+		return nil, false, errors.New("the creating user is required")
+	}
+
+	c, err := company.NewRegisteredCompany(name, commonName, displayName, ids, city, address, user)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.existing(ctx, &c)
+	if err != nil || existing != nil {
+		return existing, false, err
+	}
+	if err := s.checkLEI(ctx, c.LEI); err != nil {
+		return nil, false, err
+	}
+
+	err = s.repo.Save(ctx, &c)
+	var dup *dberr.DuplicateError
+	if errors.As(err, &dup) {
+		// Created concurrently since the lookup above
+		existing, err := s.existing(ctx, &c)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			return existing, false, nil
+		}
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to save company %q: %w", c.Name, err)
+	}
+	return &c, true, nil
+}
+
+// existing returns the live company c duplicates: the company with its business key or, failing
+// that, the best match on its identifiers (see FindByIdentifiers); the company itself, or the
+// company it was merged into. It returns nil, nil when c is new.
+func (s *CompanyService) existing(ctx context.Context, c *company.Company) (*company.Company, error) {
+	found, err := s.repo.FindByBusinessKey(ctx, c.BusinessKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up company by business key %s: %w", c.BusinessKey, err)
+	}
+	if found == nil {
+		matches, err := s.repo.FindByIdentifiers(ctx, c.Identifiers())
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up company %q by its identifiers: %w", c.Name, err)
+		}
+		if len(matches) > 0 {
+			found = matches[0]
+		}
+	}
+
+	// Merges may chain (A into B, later B into C); follow them to the survivor
+	for seen := map[string]bool{}; found != nil && found.IsTombstoned(); {
+		if seen[found.ID] {
+			return nil, fmt.Errorf("company %s is part of a merge cycle", found.ID)
+		}
+		seen[found.ID] = true
+
+		id := *found.MergedIntoID
+		if found, err = s.repo.FindByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to load company %s: %w", id, err)
+		}
+		if found == nil {
+			return nil, &dberr.NotFoundError{Entity: "company", ID: id, Reason: "does not exist but was merged into"}
+		}
+	}
+	return found, nil
+}
+
+// checkLEI looks a LEI up in the LEI registry, when one is configured, and fails when it is
+// unknown or not usable (see company.LEIRecord.CheckUsable).
+func (s *CompanyService) checkLEI(ctx context.Context, lei string) error {
+	if s.leis == nil || lei == "" {
+		return nil
+	}
+	record, err := s.leis.LookupLEI(ctx, lei)
+	if err != nil {
+		return fmt.Errorf("failed to look up LEI %s: %w", lei, err)
+	}
+	if record == nil {
+		return fmt.Errorf("LEI %s is not registered", lei)
+	}
+	return record.CheckUsable()
+}
+
+// Update
+//
+// PURPOSE:
+//
+//	Changes the details of a company (see company.CompanyUpdate). The change is checked against
+//	the company's RowVersion and recorded in the audit log by the repository; an update that
+//	changes nothing is not written. Merged companies cannot be updated.
+//
+// EXAMPLE USAGE:
+//
+//	display := "BP Trading"
+//	c, err := companyService.Update(ctx, id, company.CompanyUpdate{DisplayName: &display}, "user@internal.local")
+func (s *CompanyService) Update(ctx context.Context, id string, u company.CompanyUpdate, user string) (*company.Company, error) {
+	if user == "" {
+		return nil, errors.New("the updating user is required")
+	}
+
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company %s: %w", id, err)
+	}
+	if c == nil {
+		return nil, &dberr.NotFoundError{Entity: "company", ID: id}
+	}
+
+	if u.ContactPersonID != nil && *u.ContactPersonID != "" && s.contacts != nil {
+		if _, err := s.contactOf(ctx, id, *u.ContactPersonID); err != nil {
+			return nil, err
+		}
+	}
+
+	previous := c.Identifiers()
+	changed, err := c.Apply(u, user)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return c, nil
+	}
+	if err := s.checkIdentifiers(ctx, c, previous); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to update company %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// checkIdentifiers rejects a LEI or VAT number given to c that another live company already
+// has, and a new LEI the LEI registry does not know.
+func (s *CompanyService) checkIdentifiers(ctx context.Context, c *company.Company, previous company.Identifiers) error {
+	added := company.Identifiers{}
+	if c.LEI != previous.LEI {
+		added.LEI = c.LEI
+	}
+	if c.VATNumber != previous.VATNumber {
+		added.VATNumber = c.VATNumber
+	}
+	if added.LEI == "" && added.VATNumber == "" {
+		return nil
+	}
+
+	matches, err := s.repo.FindByIdentifiers(ctx, added)
+	if err != nil {
+		return fmt.Errorf("failed to look up companies by identifiers: %w", err)
+	}
+	for _, m := range matches {
+		if m.ID != c.ID && !m.IsTombstoned() {
+			key := "LEI " + added.LEI
+			if added.LEI == "" || m.LEI != added.LEI {
+				key = "VAT number " + added.VATNumber
+			}
+			return &dberr.DuplicateError{Entity: "company", ID: m.ID, Key: key}
+		}
+	}
+	return s.checkLEI(ctx, added.LEI)
+}
+
+// Merge
+//
+// PURPOSE:
+//
+//	Resolves a duplicate counterparty that slipped past BusinessKey deduplication
+//	(e.g. a typo in the CoC number). The source company is merged into the target:
+//
+//	  - All trades, invoices and credit limits are re-pointed to the target
+//	  - The source is tombstoned (MergedIntoID = target) but never deleted
+//	  - The merge is recorded for audit
+//
+//	The repository performs all of this inside ONE transaction.
+//
+// EXAMPLE USAGE:
+//
+//	rec, err := companyService.Merge(ctx, duplicateID, survivorID, "user@internal.local")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(rec.RepointedRows["trades.counterparty_id"]) // → 12
+func (s *CompanyService) Merge(ctx context.Context, sourceID, targetID, mergedBy string) (*company.MergeRecord, error) {
+	source, err := s.repo.FindByID(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source company %s: %w", sourceID, err)
+	}
+
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load target company %s: %w", targetID, err)
+	}
+
+	if err := company.ValidateMerge(source, target); err != nil {
+		return nil, fmt.Errorf("cannot merge %s into %s: %w", sourceID, targetID, err)
+	}
+
+	rec, err := s.repo.Merge(ctx, sourceID, targetID, mergedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge company %s into %s: %w", sourceID, targetID, err)
+	}
+
+	return rec, nil
+}
+
+// AddContact
+//
+// PURPOSE:
+//
+//	Registers a contact person of a live company (see company.NewContactPerson). A company
+//	without a primary contact gets the new contact as its primary one. The same email address
+//	is registered once per role.
+//
+// EXAMPLE USAGE:
+//
+//	p, err := companyService.AddContact(ctx, companyID, "Jane Smith", "confirmations@bp.example", "+44 20 7946 0000",
+//	    company.ContactRoleConfirmations, "user@internal.local")
+func (s *CompanyService) AddContact(ctx context.Context, companyID, name, email, phone string, role company.ContactRole, user string) (*company.ContactPerson, error) {
+	if s.contacts == nil {
+		return nil, errors.New("contact persons are not enabled")
+	}
+	if user == "" {
+		return nil, errors.New("the creating user is required")
+	}
+
+	c, err := s.repo.FindByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company %s: %w", companyID, err)
+	}
+	if c == nil {
+		return nil, &dberr.NotFoundError{Entity: "company", ID: companyID}
+	}
+	if c.IsTombstoned() {
+		return nil, fmt.Errorf("company %s was merged into %s; add the contact there", c.ID, *c.MergedIntoID)
+	}
+
+	p, err := company.NewContactPerson(companyID, name, email, phone, role, user)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.contacts.ListContacts(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contact persons of company %s: %w", companyID, err)
+	}
+	for _, e := range existing {
+		if e.Email == p.Email && e.Role == p.Role {
+			return nil, &dberr.DuplicateError{Entity: "contact person", ID: e.ID, Key: fmt.Sprintf("%s as %s of company %s", p.Email, p.Role, companyID)}
+		}
+	}
+
+	if err := s.contacts.SaveContact(ctx, &p); err != nil {
+		return nil, fmt.Errorf("failed to save contact person %q: %w", p.Name, err)
+	}
+
+	if c.ContactPersonID == "" {
+		if _, err := s.Update(ctx, companyID, company.CompanyUpdate{ContactPersonID: &p.ID}, user); err != nil {
+			return nil, fmt.Errorf("contact person %s was saved, but making it the primary contact failed: %w", p.ID, err)
+		}
+	}
+	return &p, nil
+}
+
+// UpdateContact changes the details of a contact person (see company.ContactUpdate); an update
+// that changes nothing is not written.
+//
+// Example:
+//
+//	phone := "+44 20 7946 0001"
+//	p, err := companyService.UpdateContact(ctx, contactID, company.ContactUpdate{Phone: &phone}, "user@internal.local")
+func (s *CompanyService) UpdateContact(ctx context.Context, id string, u company.ContactUpdate, user string) (*company.ContactPerson, error) {
+	if s.contacts == nil {
+		return nil, errors.New("contact persons are not enabled")
+	}
+	if user == "" {
+		return nil, errors.New("the updating user is required")
+	}
+
+	p, err := s.contacts.FindContactByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contact person %s: %w", id, err)
+	}
+	if p == nil {
+		return nil, &dberr.NotFoundError{Entity: "contact person", ID: id}
+	}
+
+	changed, err := p.Apply(u, user)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return p, nil
+	}
+	if err := s.contacts.UpdateContact(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to update contact person %s: %w", id, err)
+	}
+	return p, nil
+}
+
+// RemoveContact deletes a contact person. The primary contact of a company cannot be removed
+// until another contact is made primary (see CompanyUpdate.ContactPersonID).
+func (s *CompanyService) RemoveContact(ctx context.Context, id, user string) error {
+	if s.contacts == nil {
+		return errors.New("contact persons are not enabled")
+	}
+	if user == "" {
+		return errors.New("the removing user is required")
+	}
+
+	p, err := s.contacts.FindContactByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to load contact person %s: %w", id, err)
+	}
+	if p == nil {
+		return &dberr.NotFoundError{Entity: "contact person", ID: id}
+	}
+	c, err := s.repo.FindByID(ctx, p.CompanyID)
+	if err != nil {
+		return fmt.Errorf("failed to load company %s: %w", p.CompanyID, err)
+	}
+	if c != nil && c.ContactPersonID == id {
+		return fmt.Errorf("contact person %s is the primary contact of company %s; make another contact primary first", id, c.ID)
+	}
+
+	if err := s.contacts.DeleteContact(ctx, id, user); err != nil {
+		return fmt.Errorf("failed to remove contact person %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListContacts returns the contact persons of a company, by role and name.
+func (s *CompanyService) ListContacts(ctx context.Context, companyID string) ([]*company.ContactPerson, error) {
+	if s.contacts == nil {
+		return nil, nil
+	}
+	contacts, err := s.contacts.ListContacts(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contact persons of company %s: %w", companyID, err)
+	}
+	return contacts, nil
+}
+
+// ConfirmationContacts returns the addresses trade confirmations of a company are sent to: its
+// CONFIRMATIONS contacts or, when it has none, its primary contact. It implements
+// confirmation.ContactSource.
+//
+// Example:
+//
+//	mailer := confirmation.NewMailer(generator, companyService, ses, tradeService, "confirmations@acme.example")
+func (s *CompanyService) ConfirmationContacts(ctx context.Context, companyID string) ([]string, error) {
+	contacts, err := s.ListContacts(ctx, companyID)
+	if err != nil || len(contacts) == 0 {
+		return nil, err
+	}
+
+	var to []string
+	seen := make(map[string]bool)
+	for _, p := range contacts {
+		if p.Role == company.ContactRoleConfirmations && !seen[p.Email] {
+			seen[p.Email] = true
+			to = append(to, p.Email)
+		}
+	}
+	if len(to) > 0 {
+		return to, nil
+	}
+
+	c, err := s.repo.FindByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company %s: %w", companyID, err)
+	}
+	if c == nil || c.ContactPersonID == "" {
+		return nil, nil
+	}
+	for _, p := range contacts {
+		if p.ID == c.ContactPersonID {
+			return []string{p.Email}, nil
+		}
+	}
+	return nil, nil
+}
+
+// contactOf loads contact person id and checks that it belongs to company companyID.
+func (s *CompanyService) contactOf(ctx context.Context, companyID, id string) (*company.ContactPerson, error) {
+	if s.contacts == nil {
+		return nil, errors.New("contact persons are not enabled")
+	}
+	p, err := s.contacts.FindContactByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contact person %s: %w", id, err)
+	}
+	if p == nil {
+		return nil, &dberr.NotFoundError{Entity: "contact person", ID: id}
+	}
+	if p.CompanyID != companyID {
+		return nil, fmt.Errorf("contact person %s belongs to company %s, not %s", id, p.CompanyID, companyID)
+	}
+	return p, nil
+}
+
+// SetCreditLimit
+//
+// PURPOSE:
+//
+//	Approves the credit limit of a live company, or revises it: amount, currency, internal
+//	rating and the date by which it must be reviewed again (see company.CreditLimit). Every
+//	change is audited; re-approving an overdue limit with a new review date lifts its
+//	suspension.
+//
+// EXAMPLE USAGE:
+//
+//	review := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+//	l, err := companyService.SetCreditLimit(ctx, companyID, 5_000_000, "EUR", company.CreditRatingB, review, "credit@internal.local")
+func (s *CompanyService) SetCreditLimit(ctx context.Context, companyID string, amount float64, currency string, rating company.CreditRating, reviewDate time.Time, user string) (*company.CreditLimit, error) {
+	if s.credit == nil {
+		return nil, errors.New("credit limits are not enabled")
+	}
+	if user == "" {
+		return nil, errors.New("the approving user is required")
+	}
+
+	c, err := s.repo.FindByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company %s: %w", companyID, err)
+	}
+	if c == nil {
+		return nil, &dberr.NotFoundError{Entity: "company", ID: companyID}
+	}
+	if c.IsTombstoned() {
+		return nil, fmt.Errorf("company %s was merged into %s; set the credit limit there", c.ID, *c.MergedIntoID)
+	}
+
+	l, err := company.NewCreditLimit(companyID, amount, currency, rating, reviewDate, user)
+	if err != nil {
+		return nil, err
+	}
+	if l.IsReviewOverdue(s.now()) {
+		return nil, fmt.Errorf("the review date %s of the credit limit of company %s has already passed", l.ReviewDate.Format(time.DateOnly), companyID)
+	}
+
+	existing, err := s.credit.FindCreditLimit(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the credit limit of company %s: %w", companyID, err)
+	}
+	if existing == nil {
+		if err := s.credit.SaveCreditLimit(ctx, &l); err != nil {
+			return nil, fmt.Errorf("failed to save the credit limit of company %s: %w", companyID, err)
+		}
+		return &l, nil
+	}
+
+	existing.Amount, existing.Currency, existing.Rating, existing.ReviewDate = l.Amount, l.Currency, l.Rating, l.ReviewDate
+	existing.AuditInfo.UpdateAuditInfo(user)
+	if err := s.credit.UpdateCreditLimit(ctx, existing); err != nil {
+		return nil, fmt.Errorf("failed to update the credit limit of company %s: %w", companyID, err)
+	}
+	return existing, nil
+}
+
+// GetCreditLimit returns the approved credit limit of a company; nil when it has none.
+func (s *CompanyService) GetCreditLimit(ctx context.Context, companyID string) (*company.CreditLimit, error) {
+	if s.credit == nil {
+		return nil, nil
+	}
+	l, err := s.credit.FindCreditLimit(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the credit limit of company %s: %w", companyID, err)
+	}
+	return l, nil
+}
+
+// CreditLimit returns the limit that applies to a company now, for the exposure checks; it
+// implements exposure.LimitSource. A D rating or an overdue review leaves a limit of zero, so
+// every new sale to the company breaches it until the limit is reviewed.
+//
+// Example:
+//
+//	exposures := exposure.NewService(tradeService, companyService, fxRates, "EUR")
+//	tradeService.SetCreditCheck(exposures, service.CreditPolicyBlock, "credit-officer")
+func (s *CompanyService) CreditLimit(ctx context.Context, companyID string) (*exposure.Limit, error) {
+	l, err := s.GetCreditLimit(ctx, companyID)
+	if err != nil || l == nil {
+		return nil, err
+	}
+
+	amount, note := l.EffectiveAmount(s.now())
+	return &exposure.Limit{
+		CompanyID: companyID,
+		Amount:    amount,
+		Currency:  l.Currency,
+		Rating:    string(l.Rating),
+		Note:      note,
+	}, nil
+}
+
+// CreditReviewsDue returns the credit limits to review on or before a date, earliest first;
+// overdue limits are among them.
+//
+// Example:
+//
+//	due, err := companyService.CreditReviewsDue(ctx, time.Now().AddDate(0, 1, 0))
+func (s *CompanyService) CreditReviewsDue(ctx context.Context, by time.Time) ([]*company.CreditLimit, error) {
+	if s.credit == nil {
+		return nil, nil
+	}
+	limits, err := s.credit.ListCreditLimits(ctx, repository.CreditLimitFilter{ReviewDueBy: by})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit limits due for review: %w", err)
+	}
+	return limits, nil
+}
+
+// RecordScreening
+//
+// PURPOSE:
+//
+//	Records the outcome of a sanctions screening of a company (see
+//	company.Company.RecordScreening). A hit revokes an approved KYC, which blocks the
+//	confirmation of trades with the company (see CheckCounterparty) until a clean screening
+//	and a new approval.
+//
+// EXAMPLE USAGE:
+//
+//	c, err := companyService.RecordScreening(ctx, companyID, false, "no matches", "compliance@internal.local")
+func (s *CompanyService) RecordScreening(ctx context.Context, id string, hit bool, note, user string) (*company.Company, error) {
+	if user == "" {
+		return nil, errors.New("the screening user is required")
+	}
+	if hit && strings.TrimSpace(note) == "" {
+		return nil, fmt.Errorf("a note describing the sanctions hit on company %s is required", id)
+	}
+
+	c, err := s.live(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.RecordScreening(hit, note, s.now(), user)
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to record the screening of company %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// ApproveKYC approves the KYC of a screened company without sanctions hit until expiresAt.
+//
+// Example (approved for one year):
+//
+//	c, err := companyService.ApproveKYC(ctx, companyID, time.Now().AddDate(1, 0, 0), "compliance@internal.local")
+func (s *CompanyService) ApproveKYC(ctx context.Context, id string, expiresAt time.Time, user string) (*company.Company, error) {
+	if user == "" {
+		return nil, errors.New("the approving user is required")
+	}
+
+	c, err := s.live(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ApproveKYC(expiresAt, s.now(), user); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to approve the KYC of company %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// ExpireKYC marks every approval past its expiry date EXPIRED and returns the companies it
+// changed, e.g. from a daily job. Expired approvals block confirmations whether or not this
+// has run; it keeps the stored status and the audit log in line.
+func (s *CompanyService) ExpireKYC(ctx context.Context, user string) ([]*company.Company, error) {
+	if user == "" {
+		return nil, errors.New("the expiring user is required")
+	}
+
+	companies, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+
+	now := s.now()
+	var expired []*company.Company
+	var errs []error
+	for _, c := range companies {
+		if !c.ExpireKYC(now, user) {
+			continue
+		}
+		if err := s.repo.Update(ctx, c); err != nil {
+			errs = append(errs, fmt.Errorf("company %s: %w", c.ID, err))
+			continue
+		}
+		expired = append(expired, c)
+	}
+	return expired, errors.Join(errs...)
+}
+
+// CheckCounterparty fails with a *company.ComplianceError unless trades with the company may be
+// confirmed now: its KYC is approved and not expired, and it has no open sanctions hit. It
+// implements tradeservice.ComplianceChecker.
+//
+// Example:
+//
+//	tradeService.SetComplianceCheck(companyService)
+func (s *CompanyService) CheckCounterparty(ctx context.Context, companyID string) error {
+	c, err := s.repo.FindByID(ctx, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to load company %s: %w", companyID, err)
+	}
+	if c == nil {
+		return &dberr.NotFoundError{Entity: "company", ID: companyID}
+	}
+	if c.IsTombstoned() {
+		return &company.ComplianceError{CompanyID: companyID, Reason: "merged into " + *c.MergedIntoID}
+	}
+	return c.CheckCompliance(s.now())
+}
+
+// live loads a company that was not merged into another.
+func (s *CompanyService) live(ctx context.Context, id string) (*company.Company, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company %s: %w", id, err)
+	}
+	if c == nil {
+		return nil, &dberr.NotFoundError{Entity: "company", ID: id}
+	}
+	if c.IsTombstoned() {
+		return nil, fmt.Errorf("company %s was merged into %s", c.ID, *c.MergedIntoID)
+	}
+	return c, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+)
+
+// fakeCompanies is an in-memory CompanyRepository holding the companies by ID. Like the
+// companies table, the business key is unique.
+type fakeCompanies struct {
+	companies map[string]*company.Company
+}
+
+func (f *fakeCompanies) Save(_ context.Context, c *company.Company) error {
+	for _, existing := range f.companies {
+		if existing.BusinessKey == c.BusinessKey {
+			return &dberr.DuplicateError{Entity: "company", ID: c.ID, Key: "business key " + c.BusinessKey}
+		}
+	}
+	stored := *c
+	f.companies[c.ID] = &stored
+	return nil
+}
+
+func (f *fakeCompanies) Update(_ context.Context, c *company.Company) error {
+	stored := *c
+	f.companies[c.ID] = &stored
+	return nil
+}
+
+func (f *fakeCompanies) FindByID(_ context.Context, id string) (*company.Company, error) {
+	if c, ok := f.companies[id]; ok {
+		found := *c
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeCompanies) FindByBusinessKey(_ context.Context, businessKey string) (*company.Company, error) {
+	for _, c := range f.companies {
+		if c.BusinessKey == businessKey {
+			found := *c
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeCompanies) FindByIdentifiers(_ context.Context, ids company.Identifiers) ([]*company.Company, error) {
+	var matches []*company.Company
+	for _, c := range f.companies {
+		if (ids.LEI != "" && c.LEI == ids.LEI) || (ids.VATNumber != "" && c.VATNumber == ids.VATNumber) ||
+			(ids.CoCNumber != "" && c.CoCNumber == ids.CoCNumber) {
+			found := *c
+			matches = append(matches, &found)
+		}
+	}
+	// Live companies first, like the RDS repository
+	sort.Slice(matches, func(i, j int) bool { return !matches[i].IsTombstoned() && matches[j].IsTombstoned() })
+	return matches, nil
+}
+
+func (f *fakeCompanies) List(_ context.Context) ([]*company.Company, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeCompanies) Merge(_ context.Context, _, _, _ string) (*company.MergeRecord, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestCreateDeduplicatesOnIdentifiers(t *testing.T) {
+	ctx := context.Background()
+	const user = "user@internal.local"
+
+	tests := []struct {
+		name        string
+		existing    company.Identifiers
+		merged      bool // existing was merged into a survivor with other identifiers
+		ids         company.Identifiers
+		wantCreated bool
+		wantMatch   bool // the existing company (or its survivor) is returned
+	}{
+		{
+			name:      "same LEI, CoC number with a typo",
+			existing:  company.Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760"},
+			ids:       company.Identifiers{CoCNumber: "00102499", LEI: "213800lh1bzh3di6g760"},
+			wantMatch: true,
+		},
+		{
+			name:      "VAT number known, now with a LEI",
+			existing:  company.Identifiers{VATNumber: "GB980780684"},
+			ids:       company.Identifiers{LEI: "213800LH1BZH3DI6G760", VATNumber: "gb 980 7806 84"},
+			wantMatch: true,
+		},
+		{
+			name:      "CoC number known, now with a VAT number",
+			existing:  company.Identifiers{CoCNumber: "00102498"},
+			ids:       company.Identifiers{CoCNumber: "00102498", VATNumber: "GB980780684"},
+			wantMatch: true,
+		},
+		{
+			name:      "known but merged away",
+			existing:  company.Identifiers{LEI: "213800LH1BZH3DI6G760"},
+			merged:    true,
+			ids:       company.Identifiers{LEI: "213800LH1BZH3DI6G760"},
+			wantMatch: true,
+		},
+		{
+			name:        "new identifiers",
+			existing:    company.Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760"},
+			ids:         company.Identifiers{CoCNumber: "17001910", VATNumber: "DE136695976"},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCompanies{companies: make(map[string]*company.Company)}
+			s := NewCompanyService(repo)
+
+			existing, created, err := s.Create(ctx, "BP", "BP", "BP", tt.existing, "London", "1 St James's Square", user)
+			if err != nil || !created {
+				t.Fatalf("Create of the first company: created %v, %v", created, err)
+			}
+			want := existing.ID
+			if tt.merged {
+				survivor, _, err := s.Create(ctx, "BP p.l.c.", "BP", "BP", company.Identifiers{CoCNumber: "12345678"}, "London", "", user)
+				if err != nil {
+					t.Fatalf("Create of the survivor: %v", err)
+				}
+				repo.companies[existing.ID].MergedIntoID = &survivor.ID
+				want = survivor.ID
+			}
+
+			c, created, err := s.Create(ctx, "British Petroleum", "BP", "BP", tt.ids, "London", "1 St James's Square", user)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("created = %v, want %v", created, tt.wantCreated)
+			}
+			if tt.wantMatch && c.ID != want {
+				t.Errorf("Create returned company %s, want %s", c.ID, want)
+			}
+			if !tt.wantMatch && c.ID == want {
+				t.Errorf("Create returned the existing company %s", c.ID)
+			}
+		})
+	}
+}
+
+func TestUpdateRejectsIdentifierOfAnotherCompany(t *testing.T) {
+	ctx := context.Background()
+	const user = "user@internal.local"
+	repo := &fakeCompanies{companies: make(map[string]*company.Company)}
+	s := NewCompanyService(repo)
+
+	bp, _, err := s.Create(ctx, "BP", "BP", "BP", company.Identifiers{CoCNumber: "00102498", LEI: "213800LH1BZH3DI6G760"}, "London", "", user)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other, _, err := s.Create(ctx, "Other", "Other", "Other", company.Identifiers{CoCNumber: "17001910"}, "Hamburg", "", user)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	lei := "213800LH1BZH3DI6G760"
+	_, err = s.Update(ctx, other.ID, company.CompanyUpdate{LEI: &lei}, user)
+	var dup *dberr.DuplicateError
+	if !errors.As(err, &dup) || dup.ID != bp.ID {
+		t.Fatalf("Update with the LEI of %s: %v, want a duplicate of it", bp.ID, err)
+	}
+	if stored := repo.companies[other.ID]; stored.LEI != "" {
+		t.Errorf("the rejected LEI was stored: %s", stored.LEI)
+	}
+
+	vat := "DE136695976"
+	if _, err := s.Update(ctx, other.ID, company.CompanyUpdate{VATNumber: &vat}, user); err != nil {
+		t.Errorf("Update with an unused VAT number: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package curves
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCurve is matched (errors.Is) when no forward curve is known for a commodity.
+var ErrNoCurve = errors.New("no forward curve")
+
+// Point is the forward price of one delivery month.
+type Point struct {
+	Month time.Time `json:"month"` // First day of the delivery month, UTC
+	Price float64   `json:"price"` // Per MT, in the curve currency
+}
+
+// Curve is the forward price curve of a commodity as assessed on one day.
+//
+// Example (Brent assessed on 2 January 2026):
+//
+//	c, err := curves.NewCurve("ICE-BRENT", "USD", asOf, []curves.Point{
+//	    {Month: jan, Price: 610}, {Month: feb, Price: 606}, {Month: apr, Price: 598},
+//	})
+//	c.MonthPrice(mar) // → 602.2, interpolated by days between FEB and APR
+type Curve struct {
+	Commodity string    `json:"commodity"` // Commodity or price index, e.g. "diesel" or "ICE-BRENT"
+	Currency  string    `json:"currency"`
+	AsOf      time.Time `json:"asOf"`   // Assessment day, UTC midnight
+	Points    []Point   `json:"points"` // Sorted by month, one point per month
+}
+
+// NewCurve builds a curve, normalizing months to their first day and sorting the points. It
+// fails on an empty curve, a non-positive price or two points in the same month.
+func NewCurve(commodity, currency string, asOf time.Time, points []Point) (*Curve, error) {
+	if commodity == "" {
+		return nil, errors.New("a forward curve requires a commodity")
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("forward curve %s has no points", commodity)
+	}
+
+	sorted := make([]Point, len(points))
+	for i, p := range points {
+		if p.Price <= 0 {
+			return nil, fmt.Errorf("forward curve %s: price of %s must be positive, got %v", commodity, p.Month.Format("2006-01"), p.Price)
+		}
+		sorted[i] = Point{Month: MonthStart(p.Month), Price: p.Price}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Month.Before(sorted[j].Month) })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Month.Equal(sorted[i-1].Month) {
+			return nil, fmt.Errorf("forward curve %s has two prices for %s", commodity, sorted[i].Month.Format("2006-01"))
+		}
+	}
+
+	return &Curve{
+		Commodity: commodity,
+		Currency:  strings.ToUpper(currency),
+		AsOf:      day(asOf),
+		Points:    sorted,
+	}, nil
+}
+
+// MonthPrice returns the forward price of a delivery month. Months between two points are
+// interpolated linearly (see PriceAt); months before the first or after the last point take
+// the price of the nearest point (flat extrapolation).
+func (c *Curve) MonthPrice(month time.Time) float64 {
+	return c.PriceAt(MonthStart(month))
+}
+
+// PriceAt interpolates the curve linearly by calendar days between the months around t, so a
+// delivery in mid-March on a FEB 606 / APR 598 curve prices at about 602. Outside the curve
+// the price is flat.
+func (c *Curve) PriceAt(t time.Time) float64 {
+	t = t.UTC()
+	pts := c.Points
+	i := sort.Search(len(pts), func(i int) bool { return pts[i].Month.After(t) })
+	switch {
+	case i == 0:
+		return pts[0].Price
+	case i == len(pts):
+		return pts[len(pts)-1].Price
+	}
+
+	lo, hi := pts[i-1], pts[i]
+	w := float64(t.Sub(lo.Month)) / float64(hi.Month.Sub(lo.Month))
+	return lo.Price + w*(hi.Price-lo.Price)
+}
+
+// AveragePrice returns the day-weighted average forward price over [from, to] (day
+// precision), e.g. the expected monthly average of a formula-priced breakdown.
+func (c *Curve) AveragePrice(from, to time.Time) float64 {
+	from, to = day(from), day(to)
+	if to.Before(from) {
+		return c.PriceAt(from)
+	}
+
+	var sum float64
+	var n int
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		sum += c.PriceAt(d)
+		n++
+	}
+	return sum / float64(n)
+}
+
+// Set holds the latest known forward curve of every commodity. It is safe for concurrent use.
+type Set struct {
+	mu     sync.RWMutex
+	curves map[string]*Curve // Commodity → curve
+}
+
+func NewSet() *Set {
+	return &Set{curves: make(map[string]*Curve)}
+}
+
+// Put stores a curve, unless a curve of the commodity with a later assessment day is known.
+func (s *Set) Put(curves ...*Curve) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range curves {
+		key := normalize(c.Commodity)
+		if cur, ok := s.curves[key]; ok && cur.AsOf.After(c.AsOf) {
+			continue
+		}
+		s.curves[key] = c
+	}
+}
+
+// Curve returns the curve of a commodity.
+func (s *Set) Curve(commodity string) (*Curve, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.curves[normalize(commodity)]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s", ErrNoCurve, commodity)
+	}
+	return c, nil
+}
+
+// ForwardPrice returns the forward price of a commodity for a delivery month.
+func (s *Set) ForwardPrice(commodity string, month time.Time) (float64, error) {
+	c, err := s.Curve(commodity)
+	if err != nil {
+		return 0, err
+	}
+	return c.MonthPrice(month), nil
+}
+
+// ForwardAverage returns the expected average price of a commodity over [from, to]; it makes
+// the set a trade.ForwardPriceSource for estimating formula prices before quotes exist.
+func (s *Set) ForwardAverage(commodity string, from, to time.Time) (float64, error) {
+	c, err := s.Curve(commodity)
+	if err != nil {
+		return 0, err
+	}
+	return c.AveragePrice(from, to), nil
+}
+
+// MonthStart returns the first day of the month of t, UTC.
+func MonthStart(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func normalize(commodity string) string {
+	return strings.ToLower(strings.TrimSpace(commodity))
+}
@@ -0,0 +1,35 @@
+package curves
+
+import (
+	"time"
+
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// forwardQuotes serves published quotes, and forward estimates for what is not published yet.
+type forwardQuotes struct {
+	published trade.IndexQuoteSource
+	forwards  *Set
+}
+
+// WithForwards combines published index quotes with forward curves, for evaluating pricing
+// formulas (see trade.PriceBreakdowns): months without quotes get the forward estimate of the
+// index curve as provisional price. published may be nil when no quotes are loaded yet.
+//
+// Example:
+//
+//	tradeService.SetIndexQuotes(curves.WithForwards(quoteRepo, curveSet))
+func WithForwards(published trade.IndexQuoteSource, forwards *Set) trade.IndexQuoteSource {
+	return &forwardQuotes{published: published, forwards: forwards}
+}
+
+func (q *forwardQuotes) Quotes(index string, from, to time.Time) ([]trade.IndexQuote, error) {
+	if q.published == nil {
+		return nil, nil
+	}
+	return q.published.Quotes(index, from, to)
+}
+
+func (q *forwardQuotes) ForwardAverage(index string, from, to time.Time) (float64, error) {
+	return q.forwards.ForwardAverage(index, from, to)
+}
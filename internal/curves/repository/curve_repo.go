@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	"github.com/nholding/cso-book/internal/curves"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/pgbulk"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// CurveRepository defines the interface for storing and retrieving forward curves from a persistence layer
+type CurveRepository interface {
+	// SaveCurve stores a curve, replacing the curve of the same commodity and assessment day
+	SaveCurve(ctx context.Context, c *curves.Curve, savedBy string) error
+
+	// LatestCurve returns the most recent curve of a commodity assessed on or before asOf;
+	// returns nil, nil when there is none
+	LatestCurve(ctx context.Context, commodity string, asOf time.Time) (*curves.Curve, error)
+
+	// LatestCurves returns the most recent curve of every commodity assessed on or before asOf
+	LatestCurves(ctx context.Context, asOf time.Time) ([]*curves.Curve, error)
+}
+
+// Compile-time check that RdsCurveRepository satisfies CurveRepository
+var _ CurveRepository = (*RdsCurveRepository)(nil)
+
+type RdsCurveRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsCurveRepository(cfg *awsclient.Config) (*RdsCurveRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsCurveRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveCurve replaces the points of a commodity's curve for its assessment day in one
+// transaction, so readers never see a mix of two uploads.
+//
+// Example:
+//
+//	c, _ := curves.NewCurve("ICE-BRENT", "USD", asOf, points)
+//	err := repo.SaveCurve(ctx, c, "user@internal.local")
+func (r *RdsCurveRepository) SaveCurve(ctx context.Context, c *curves.Curve, savedBy string) error {
+	commodity := normalize(c.Commodity)
+	asOf := c.AsOf.UTC().Format("2006-01-02")
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM forward_curves WHERE commodity=$1 AND as_of=$2`, commodity, asOf); err != nil {
+		return fmt.Errorf("failed to delete forward curve %s of %s: %w", commodity, asOf, err)
+	}
+
+	now := time.Now().UTC()
+	rows := make([][]any, 0, len(c.Points))
+	for _, p := range c.Points {
+		rows = append(rows, []any{commodity, c.AsOf, p.Month, p.Price, c.Currency, savedBy, now})
+	}
+	if err := pgbulk.CopyIn(ctx, tx.Tx, "forward_curves",
+		[]string{"commodity", "as_of", "delivery_month", "price", "currency", "created_by", "created_at"}, rows); err != nil {
+		return err
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityCurve, commodity, audit.ActionCreate, savedBy,
+		map[string]any{"asOf": asOf, "points": len(c.Points), "currency": c.Currency})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit forward curve transaction: %w", err)
+	}
+	return nil
+}
+
+// LatestCurve returns the most recent curve of one commodity.
+func (r *RdsCurveRepository) LatestCurve(ctx context.Context, commodity string, asOf time.Time) (*curves.Curve, error) {
+	found, err := r.query(ctx, `
+		SELECT commodity, as_of, delivery_month, price, currency
+		FROM forward_curves
+		WHERE commodity=$1 AND as_of = (
+			SELECT MAX(as_of) FROM forward_curves WHERE commodity=$1 AND as_of <= $2
+		)
+		ORDER BY delivery_month
+	`, normalize(commodity), asOf.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+// LatestCurves returns the most recent curve of every commodity, e.g. to fill a curves.Set.
+func (r *RdsCurveRepository) LatestCurves(ctx context.Context, asOf time.Time) ([]*curves.Curve, error) {
+	return r.query(ctx, `
+		SELECT f.commodity, f.as_of, f.delivery_month, f.price, f.currency
+		FROM forward_curves f
+		JOIN (
+			SELECT commodity, MAX(as_of) AS as_of FROM forward_curves WHERE as_of <= $1 GROUP BY commodity
+		) latest ON latest.commodity = f.commodity AND latest.as_of = f.as_of
+		ORDER BY f.commodity, f.delivery_month
+	`, asOf.UTC().Format("2006-01-02"))
+}
+
+// query groups rows ordered by commodity and delivery month into curves.
+func (r *RdsCurveRepository) query(ctx context.Context, query string, args ...any) ([]*curves.Curve, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query forward curves: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		result []*curves.Curve
+		cur    *curves.Curve
+	)
+	for rows.Next() {
+		var (
+			commodity, currency string
+			asOf                time.Time
+			p                   curves.Point
+		)
+		if err := rows.Scan(&commodity, &asOf, &p.Month, &p.Price, &currency); err != nil {
+			return nil, fmt.Errorf("failed to scan forward curve point: %w", err)
+		}
+		p.Month = p.Month.UTC()
+
+		if cur == nil || cur.Commodity != commodity {
+			cur = &curves.Curve{Commodity: commodity, Currency: currency, AsOf: asOf.UTC()}
+			result = append(result, cur)
+		}
+		cur.Points = append(cur.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read forward curves: %w", err)
+	}
+	return result, nil
+}
+
+func normalize(commodity string) string {
+	return strings.ToLower(strings.TrimSpace(commodity))
+}
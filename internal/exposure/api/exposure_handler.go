@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/nholding/cso-book/internal/exposure"
+)
+
+// ExposureSource provides the credit exposure per counterparty; *exposure.Service implements it.
+type ExposureSource interface {
+	Exposure(ctx context.Context, counterpartyID string) (exposure.Exposure, error)
+	ExposureAll(ctx context.Context) ([]exposure.Exposure, error)
+}
+
+// ExposureHandler serves the open value of the book per counterparty.
+//
+//	GET /exposures                    every counterparty with open trades, highest receivable first
+//	GET /exposures/{counterpartyId}   one counterparty
+//
+// Example response of GET /exposures/C42:
+//
+//	{"counterpartyId": "C42", "currency": "EUR", "receivable": 4600000, "payable": 0,
+//	 "net": 4600000, "limit": 5000000, "hasLimit": true, "utilisation": 0.92}
+type ExposureHandler struct {
+	source ExposureSource
+}
+
+func NewExposureHandler(source ExposureSource) *ExposureHandler {
+	return &ExposureHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *ExposureHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /exposures", h.list)
+	mux.HandleFunc("GET /exposures/{counterpartyId}", h.get)
+}
+
+func (h *ExposureHandler) list(w http.ResponseWriter, r *http.Request) {
+	all, err := h.source.ExposureAll(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to compute exposures: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, all)
+}
+
+func (h *ExposureHandler) get(w http.ResponseWriter, r *http.Request) {
+	counterpartyID := r.PathValue("counterpartyId")
+
+	e, err := h.source.Exposure(r.Context(), counterpartyID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to compute the exposure of %s: %w", counterpartyID, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, e)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,335 @@
+package exposure
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/fx"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// DefaultWarnRatio is the share of the credit limit from which a counterparty is reported as
+// NEAR_LIMIT.
+const DefaultWarnRatio = 0.9
+
+// Limit is the credit limit of a counterparty company.
+//
+// Amount is the limit that applies now. A LimitSource that suspends limits (e.g. when their
+// review is overdue) reports 0 with the reason in Note.
+type Limit struct {
+	CompanyID string  `json:"companyId"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+	Rating    string  `json:"rating,omitempty"` // Internal credit rating, if any
+	Note      string  `json:"note,omitempty"`   // Why Amount differs from the approved limit, e.g. "review overdue since 2026-03-31"
+}
+
+// LimitSource provides the configured credit limits.
+type LimitSource interface {
+	// CreditLimit returns the limit of a company; nil, nil when none is configured
+	CreditLimit(ctx context.Context, companyID string) (*Limit, error)
+}
+
+// StaticLimits is a LimitSource backed by a fixed map, e.g. limits read from configuration.
+type StaticLimits map[string]Limit
+
+func (l StaticLimits) CreditLimit(_ context.Context, companyID string) (*Limit, error) {
+	limit, ok := l[companyID]
+	if !ok {
+		return nil, nil
+	}
+	limit.CompanyID = companyID
+	return &limit, nil
+}
+
+// SettlementSource tells which breakdowns have been paid. Invoicing (the InvoiceService of
+// internal/invoice) implements it; without one every breakdown counts as unpaid until the
+// trade leaves the book.
+type SettlementSource interface {
+	// SettledBreakdowns returns the IDs of the paid breakdowns of a counterparty's trades
+	SettledBreakdowns(ctx context.Context, counterpartyID string) (map[string]bool, error)
+}
+
+// BookSource provides the live trades of the book with their breakdowns; *service.TradeService
+// implements it.
+type BookSource interface {
+	ListBookedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error)
+}
+
+// Exposure is the open value of the trades with one counterparty, in the exposure currency.
+//
+// Receivable is what the counterparty owes us for open sales; Payable what we owe it for open
+// purchases. The credit limit is compared against Receivable: we only lose what was delivered
+// or promised to the counterparty and not paid.
+type Exposure struct {
+	CounterpartyID string  `json:"counterpartyId"`
+	Currency       string  `json:"currency"`
+	Receivable     float64 `json:"receivable"`
+	Payable        float64 `json:"payable"`
+	Net            float64 `json:"net"`             // Receivable minus Payable
+	Limit          float64 `json:"limit,omitempty"` // In Currency; 0 when no limit is configured
+	HasLimit       bool    `json:"hasLimit"`
+	Utilisation    float64 `json:"utilisation,omitempty"` // Receivable / Limit
+	Rating         string  `json:"rating,omitempty"`      // Internal credit rating of the counterparty
+	LimitNote      string  `json:"limitNote,omitempty"`   // See Limit.Note
+}
+
+// WarningLevel tells how close a counterparty is to its credit limit.
+type WarningLevel string
+
+const (
+	LevelNearLimit WarningLevel = "NEAR_LIMIT" // At or above the warn ratio of the limit
+	LevelBreach    WarningLevel = "BREACH"     // Above the limit
+)
+
+// Warning reports that a new trade would bring a counterparty close to or over its limit.
+type Warning struct {
+	Level     WarningLevel `json:"level"`
+	TradeID   string       `json:"tradeId"`
+	Current   Exposure     `json:"current"`   // Without the trade
+	Projected float64      `json:"projected"` // Receivable including the trade
+	Message   string       `json:"message"`
+}
+
+// LimitError rejects the confirmation of a trade that would take its counterparty over the
+// credit limit.
+type LimitError struct {
+	Warning Warning
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("trade %s cannot be confirmed: %s", e.Warning.TradeID, e.Warning.Message)
+}
+
+// Service aggregates the open value of the book per counterparty and compares it against
+// credit limits.
+//
+// PURPOSE:
+//
+//	A breakdown is open until it is paid (see SettlementSource): future deliveries are value
+//	the counterparty has promised to take, delivered months are value it still has to pay.
+//	Amounts are expressed in one currency: the breakdown's reporting amount when it was
+//	converted into that currency, otherwise converted at today's rate.
+//
+//	CheckTrade projects the exposure including a new or amended trade and returns a Warning
+//	when it reaches the warn ratio of the limit, so the trader can act before confirming.
+//	Warnings do not block booking; confirmation can be blocked through
+//	TradeService.SetCreditCheck.
+//
+// EXAMPLE USAGE:
+//
+//	svc := exposure.NewService(tradeService, exposure.StaticLimits{
+//	    "C42": {Amount: 5_000_000, Currency: "EUR"},
+//	}, fxRates, "EUR")
+//
+//	tradeService.SetCreditCheck(svc, service.CreditPolicyFlag, "")
+//	sale, breakdowns, warning, err := tradeService.CreateSale(ctx, req, booking) // Runs svc.CheckTrade
+//	if warning != nil {
+//	    log.Printf("credit: %s", warning.Message) // "C42 exposure 5.2M EUR would exceed its limit of 5M EUR"
+//	}
+type Service struct {
+	book        BookSource
+	limits      LimitSource
+	settlements SettlementSource // nil: nothing is settled
+	rates       fx.RateSource    // nil: amounts must already be in currency
+	currency    string
+	warnRatio   float64
+	now         func() time.Time
+}
+
+func NewService(book BookSource, limits LimitSource, rates fx.RateSource, currency string) *Service {
+	return &Service{
+		book:      book,
+		limits:    limits,
+		rates:     rates,
+		currency:  strings.ToUpper(currency),
+		warnRatio: DefaultWarnRatio,
+		now:       time.Now,
+	}
+}
+
+// SetSettlements makes paid breakdowns drop out of the exposure.
+func (s *Service) SetSettlements(settlements SettlementSource) {
+	s.settlements = settlements
+}
+
+// SetWarnRatio changes the share of the limit from which CheckTrade warns, e.g. 0.8.
+func (s *Service) SetWarnRatio(ratio float64) {
+	s.warnRatio = ratio
+}
+
+// Exposure returns the exposure to one counterparty.
+func (s *Service) Exposure(ctx context.Context, counterpartyID string) (Exposure, error) {
+	book, err := s.book.ListBookedTrades(ctx, repository.TradeFilter{CounterpartyID: counterpartyID})
+	if err != nil {
+		return Exposure{}, fmt.Errorf("failed to load trades of counterparty %s: %w", counterpartyID, err)
+	}
+	return s.exposure(ctx, counterpartyID, book)
+}
+
+// ExposureAll returns the exposure to every counterparty with open trades, highest receivable first.
+func (s *Service) ExposureAll(ctx context.Context) ([]Exposure, error) {
+	book, err := s.book.ListBookedTrades(ctx, repository.TradeFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+
+	byCounterparty := make(map[string][]trade.BookedTrade)
+	for _, bt := range book {
+		id := bt.Trade.CounterpartyID()
+		byCounterparty[id] = append(byCounterparty[id], bt)
+	}
+
+	all := make([]Exposure, 0, len(byCounterparty))
+	for id, trades := range byCounterparty {
+		e, err := s.exposure(ctx, id, trades)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Receivable != all[j].Receivable {
+			return all[i].Receivable > all[j].Receivable
+		}
+		return all[i].CounterpartyID < all[j].CounterpartyID
+	})
+	return all, nil
+}
+
+// CheckTrade projects the exposure to the trade's counterparty with the given breakdowns of the
+// trade, replacing whatever the book already holds for it and for the version it amends (see
+// TradeBase.PreviousVersionID). It returns nil when the projection stays below the warn ratio
+// or no limit is configured.
+func (s *Service) CheckTrade(ctx context.Context, t trade.Trade, breakdowns []trade.TradeBreakdown) (*Warning, error) {
+	tb := t.Base()
+	counterpartyID := t.CounterpartyID()
+
+	book, err := s.book.ListBookedTrades(ctx, repository.TradeFilter{CounterpartyID: counterpartyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades of counterparty %s: %w", counterpartyID, err)
+	}
+	current, err := s.exposure(ctx, counterpartyID, book, tb.ID, tb.PreviousVersionID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.HasLimit || t.Kind() != trade.TradeKindSale {
+		return nil, nil
+	}
+
+	added, err := s.openValue(ctx, counterpartyID, breakdowns, nil)
+	if err != nil {
+		return nil, err
+	}
+	projected := current.Receivable + added
+
+	var level WarningLevel
+	switch {
+	case projected > current.Limit:
+		level = LevelBreach
+	case projected >= s.warnRatio*current.Limit:
+		level = LevelNearLimit
+	default:
+		return nil, nil
+	}
+
+	verb := "would exceed"
+	if level == LevelNearLimit {
+		verb = fmt.Sprintf("would use %.0f%% of", 100*projected/current.Limit)
+	}
+	message := fmt.Sprintf("%s exposure %.2f %s %s its limit of %.2f %s",
+		counterpartyID, projected, s.currency, verb, current.Limit, s.currency)
+	if current.LimitNote != "" {
+		message += " (" + current.LimitNote + ")"
+	}
+	return &Warning{
+		Level:     level,
+		TradeID:   tb.ID,
+		Current:   current,
+		Projected: projected,
+		Message:   message,
+	}, nil
+}
+
+// exposure sums the open value of a counterparty's trades, leaving out the trades exclude.
+func (s *Service) exposure(ctx context.Context, counterpartyID string, book []trade.BookedTrade, exclude ...string) (Exposure, error) {
+	e := Exposure{CounterpartyID: counterpartyID, Currency: s.currency}
+
+	var settled map[string]bool
+	if s.settlements != nil {
+		var err error
+		if settled, err = s.settlements.SettledBreakdowns(ctx, counterpartyID); err != nil {
+			return Exposure{}, fmt.Errorf("failed to load settlements of counterparty %s: %w", counterpartyID, err)
+		}
+	}
+
+	for _, bt := range book {
+		if !trade.IsLive(bt.Trade) || slices.Contains(exclude, bt.Trade.Base().ID) || bt.Trade.CounterpartyID() != counterpartyID {
+			continue
+		}
+		v, err := s.openValue(ctx, counterpartyID, bt.Breakdowns, settled)
+		if err != nil {
+			return Exposure{}, fmt.Errorf("trade %s: %w", bt.Trade.Base().ID, err)
+		}
+		if bt.Trade.Kind() == trade.TradeKindSale {
+			e.Receivable += v
+		} else {
+			e.Payable += v
+		}
+	}
+	e.Net = e.Receivable - e.Payable
+
+	if s.limits != nil {
+		limit, err := s.limits.CreditLimit(ctx, counterpartyID)
+		if err != nil {
+			return Exposure{}, fmt.Errorf("failed to load credit limit of counterparty %s: %w", counterpartyID, err)
+		}
+		if limit != nil {
+			amount, err := s.convert(limit.Amount, limit.Currency, s.now())
+			if err != nil {
+				return Exposure{}, fmt.Errorf("credit limit of counterparty %s: %w", counterpartyID, err)
+			}
+			e.Limit, e.HasLimit = amount, true
+			e.Rating, e.LimitNote = limit.Rating, limit.Note
+			if amount > 0 {
+				e.Utilisation = e.Receivable / amount
+			}
+		}
+	}
+	return e, nil
+}
+
+// openValue sums the unpaid breakdowns in the exposure currency.
+func (s *Service) openValue(_ context.Context, counterpartyID string, breakdowns []trade.TradeBreakdown, settled map[string]bool) (float64, error) {
+	var total float64
+	for _, bd := range breakdowns {
+		if settled[bd.ID] {
+			continue
+		}
+		if strings.EqualFold(bd.ReportingCurrency, s.currency) {
+			total += bd.ReportingAmount
+			continue
+		}
+		v, err := s.convert(bd.TotalAmount, bd.Currency, s.now())
+		if err != nil {
+			return 0, fmt.Errorf("breakdown %s with %s: %w", bd.PeriodID, counterpartyID, err)
+		}
+		total += v
+	}
+	return total, nil
+}
+
+func (s *Service) convert(amount float64, currency string, date time.Time) (float64, error) {
+	if strings.EqualFold(currency, s.currency) {
+		return amount, nil
+	}
+	if s.rates == nil {
+		return 0, fmt.Errorf("cannot convert %s into %s: no FX rates", currency, s.currency)
+	}
+	return fx.Convert(s.rates, amount, currency, s.currency, date)
+}
@@ -0,0 +1,164 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReportingCurrency is the currency the book is reported in unless configured otherwise.
+const DefaultReportingCurrency = "EUR"
+
+// ErrNoRate is matched (errors.Is) when no rate is known for a currency pair on or before a date.
+var ErrNoRate = errors.New("no fx rate")
+
+// Rate is the price of one unit of Base in Quote on a date, e.g. 1 EUR = 1.0842 USD.
+type Rate struct {
+	Base   string    `json:"base"`
+	Quote  string    `json:"quote"`
+	Date   time.Time `json:"date"` // Day the rate applies to, UTC midnight
+	Rate   float64   `json:"rate"`
+	Source string    `json:"source"` // e.g. "ECB" or "manual"
+}
+
+// RateSource looks up the rate converting from one currency into another on a date.
+type RateSource interface {
+	Rate(from, to string, date time.Time) (float64, error)
+}
+
+// Table is an in-memory FX rate table. It is safe for concurrent use.
+//
+// PURPOSE:
+//
+//	Deals are priced in their own currency (USD cargoes, GBP storage fees, ...); the book is
+//	reported in one currency (EUR). A lookup returns the most recent rate on or before the
+//	requested day, so weekends, holidays and future delivery months use the last known
+//	fixing. Pairs are resolved directly, inverted, or crossed through the pivot currency:
+//
+//	    EUR→USD  direct        EUR/USD
+//	    USD→EUR  inverted      1 / EUR/USD
+//	    USD→GBP  crossed       EUR/GBP / EUR/USD
+//
+// EXAMPLE USAGE:
+//
+//	table := fx.NewTable("EUR")
+//	table.Add(fx.Rate{Base: "EUR", Quote: "USD", Date: day, Rate: 1.0842, Source: "ECB"})
+//	r, err := table.Rate("USD", "EUR", day) // → 0.9223
+type Table struct {
+	mu    sync.RWMutex
+	pivot string
+	rates map[string][]Rate // "EUR/USD" → rates sorted by date
+}
+
+// NewTable creates an empty table crossing pairs through pivot (the ECB publishes EUR rates,
+// so "EUR" is the usual pivot).
+func NewTable(pivot string) *Table {
+	return &Table{pivot: normalize(pivot), rates: make(map[string][]Rate)}
+}
+
+// Add stores rates, replacing any rate of the same pair and day.
+func (t *Table) Add(rates ...Rate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, r := range rates {
+		r.Base, r.Quote = normalize(r.Base), normalize(r.Quote)
+		r.Date = day(r.Date)
+		key := r.Base + "/" + r.Quote
+
+		series := t.rates[key]
+		i := sort.Search(len(series), func(i int) bool { return !series[i].Date.Before(r.Date) })
+		if i < len(series) && series[i].Date.Equal(r.Date) {
+			series[i] = r
+			continue
+		}
+		series = append(series, Rate{})
+		copy(series[i+1:], series[i:])
+		series[i] = r
+		t.rates[key] = series
+	}
+}
+
+// Rate returns the factor converting an amount in from into to, using the most recent rates
+// on or before date.
+func (t *Table) Rate(from, to string, date time.Time) (float64, error) {
+	from, to = normalize(from), normalize(to)
+	if from == to {
+		return 1, nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if r, ok := t.pair(from, to, date); ok {
+		return r, nil
+	}
+
+	if from != t.pivot && to != t.pivot {
+		a, okA := t.pair(t.pivot, from, date)
+		b, okB := t.pair(t.pivot, to, date)
+		if okA && okB {
+			return b / a, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w for %s/%s on or before %s", ErrNoRate, from, to, date.Format("2006-01-02"))
+}
+
+// Latest returns the most recent stored rate of every pair, sorted by pair.
+func (t *Table) Latest() []Rate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	latest := make([]Rate, 0, len(t.rates))
+	for _, series := range t.rates {
+		latest = append(latest, series[len(series)-1])
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		return latest[i].Base+latest[i].Quote < latest[j].Base+latest[j].Quote
+	})
+	return latest
+}
+
+// pair looks up a pair directly or inverted. Callers hold the read lock.
+func (t *Table) pair(from, to string, date time.Time) (float64, bool) {
+	if r, ok := t.lookup(from+"/"+to, date); ok {
+		return r, true
+	}
+	if r, ok := t.lookup(to+"/"+from, date); ok && r != 0 {
+		return 1 / r, true
+	}
+	return 0, false
+}
+
+// lookup returns the most recent rate of a pair on or before date.
+func (t *Table) lookup(key string, date time.Time) (float64, bool) {
+	series := t.rates[key]
+	d := day(date)
+	i := sort.Search(len(series), func(i int) bool { return series[i].Date.After(d) })
+	if i == 0 {
+		return 0, false
+	}
+	return series[i-1].Rate, true
+}
+
+// Convert converts amount from one currency into another on date.
+func Convert(src RateSource, amount float64, from, to string, date time.Time) (float64, error) {
+	r, err := src.Rate(from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return amount * r, nil
+}
+
+func normalize(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
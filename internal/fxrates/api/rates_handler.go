@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nholding/cso-book/internal/fx"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+)
+
+// RateStore provides the effective FX rates and manual overrides; *fxrates.Service implements it.
+type RateStore interface {
+	Latest() []fx.Rate
+	SetOverride(ctx context.Context, rate fx.Rate, reason, actor string) error
+	DeleteOverride(ctx context.Context, base, quote string, date time.Time, actor string) error
+}
+
+// RatesHandler serves the FX rates of the book and lets the back office override a fixing.
+//
+//	GET    /fx/rates                              latest effective rate of every pair
+//	PUT    /fx/rates/{base}/{quote}/{date}        override the rate of a pair on a day
+//	DELETE /fx/rates/{base}/{quote}/{date}?by=..  remove the override; the ECB rate applies again
+//
+// Example override:
+//
+//	PUT /fx/rates/EUR/USD/2026-01-02
+//	{"rate": 1.0851, "reason": "ECB republished the fixing", "setBy": "user@internal.local"}
+//
+// Example response of GET /fx/rates:
+//
+//	{"rates": [{"base": "EUR", "quote": "USD", "date": "2026-01-02T00:00:00Z", "rate": 1.0851, "source": "MANUAL"}, ...]}
+type RatesHandler struct {
+	store RateStore
+}
+
+func NewRatesHandler(store RateStore) *RatesHandler {
+	return &RatesHandler{store: store}
+}
+
+// Register mounts the handler on mux.
+func (h *RatesHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /fx/rates", h.latest)
+	mux.HandleFunc("PUT /fx/rates/{base}/{quote}/{date}", h.override)
+	mux.HandleFunc("DELETE /fx/rates/{base}/{quote}/{date}", h.deleteOverride)
+}
+
+// overrideRequest is the body of PUT /fx/rates/{base}/{quote}/{date}.
+type overrideRequest struct {
+	Rate   float64 `json:"rate"`
+	Reason string  `json:"reason"`
+	SetBy  string  `json:"setBy"`
+}
+
+func (h *RatesHandler) latest(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"rates": h.store.Latest()})
+}
+
+func (h *RatesHandler) override(w http.ResponseWriter, r *http.Request) {
+	date, err := time.Parse("2006-01-02", r.PathValue("date"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", r.PathValue("date")))
+		return
+	}
+
+	var req overrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if req.SetBy == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("setBy is required"))
+		return
+	}
+	if req.Rate <= 0 || req.Reason == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("a positive rate and a reason are required"))
+		return
+	}
+
+	rate := fx.Rate{Base: r.PathValue("base"), Quote: r.PathValue("quote"), Date: date, Rate: req.Rate}
+	if err := h.store.SetOverride(r.Context(), rate, req.Reason, req.SetBy); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to override fx rate: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RatesHandler) deleteOverride(w http.ResponseWriter, r *http.Request) {
+	date, err := time.Parse("2006-01-02", r.PathValue("date"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", r.PathValue("date")))
+		return
+	}
+	by := r.URL.Query().Get("by")
+	if by == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("query parameter by is required"))
+		return
+	}
+
+	err = h.store.DeleteOverride(r.Context(), r.PathValue("base"), r.PathValue("quote"), date, by)
+	if errors.Is(err, dberr.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to delete fx rate override: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
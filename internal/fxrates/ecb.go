@@ -0,0 +1,106 @@
+package fxrates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nholding/cso-book/internal/fx"
+)
+
+// SourceECB marks rates published by the European Central Bank.
+const SourceECB = "ECB"
+
+// ECB reference rate feeds: the latest fixing, and the last 90 fixings for backfilling.
+const (
+	ECBDailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	ECB90DayURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+)
+
+// ECBClient downloads the euro foreign exchange reference rates the ECB publishes every
+// working day at around 16:00 CET. Every rate is quoted as 1 EUR = x units of the currency.
+type ECBClient struct {
+	http *http.Client
+}
+
+// NewECBClient creates an ECBClient; a nil httpClient means one with a 30 second timeout.
+func NewECBClient(httpClient *http.Client) *ECBClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &ECBClient{http: httpClient}
+}
+
+// FetchDaily returns the latest fixing.
+func (c *ECBClient) FetchDaily(ctx context.Context) ([]fx.Rate, error) {
+	return c.fetch(ctx, ECBDailyURL)
+}
+
+// Fetch90Days returns the fixings of the last 90 days, e.g. to fill gaps after an outage.
+func (c *ECBClient) Fetch90Days(ctx context.Context) ([]fx.Rate, error) {
+	return c.fetch(ctx, ECB90DayURL)
+}
+
+func (c *ECBClient) fetch(ctx context.Context, url string) ([]fx.Rate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download ECB rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download ECB rates: %s", resp.Status)
+	}
+
+	return ParseECB(resp.Body)
+}
+
+// ecbEnvelope mirrors the gesmes envelope of the ECB feeds:
+//
+//	<Cube><Cube time="2026-01-02"><Cube currency="USD" rate="1.0842"/>...</Cube></Cube>
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECB parses an ECB reference rate feed into EUR-based rates.
+func ParseECB(r io.Reader) ([]fx.Rate, error) {
+	var env ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&env); err != nil {
+		return nil, fmt.Errorf("failed to parse ECB rates: %w", err)
+	}
+
+	var rates []fx.Rate
+	for _, d := range env.Days {
+		date, err := time.Parse("2006-01-02", d.Time)
+		if err != nil {
+			return nil, fmt.Errorf("ECB rates: invalid date %q", d.Time)
+		}
+		for _, cr := range d.Rates {
+			rate, err := strconv.ParseFloat(cr.Rate, 64)
+			if err != nil || rate <= 0 {
+				return nil, fmt.Errorf("ECB rates: invalid %s rate %q on %s", cr.Currency, cr.Rate, d.Time)
+			}
+			rates = append(rates, fx.Rate{Base: "EUR", Quote: cr.Currency, Date: date, Rate: rate, Source: SourceECB})
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("ECB rates: the feed contains no rates")
+	}
+	return rates, nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	"github.com/nholding/cso-book/internal/fx"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// SourceManual marks rates entered by hand; they win over published rates of the same pair and day.
+const SourceManual = "MANUAL"
+
+// RateRepository defines the interface for storing and retrieving FX rates from a persistence layer
+type RateRepository interface {
+	// SaveRates upserts published rates (e.g. an ECB fixing); a rate of the same pair, day and
+	// source is replaced
+	SaveRates(ctx context.Context, rates []fx.Rate, savedBy string) error
+
+	// LoadRates returns the rates of every pair on or after since, oldest first. When a pair has
+	// both a published and a MANUAL rate on a day, only the MANUAL one is returned.
+	LoadRates(ctx context.Context, since time.Time) ([]fx.Rate, error)
+
+	// SetOverride stores a MANUAL rate for a pair and day, replacing an earlier override
+	SetOverride(ctx context.Context, rate fx.Rate, reason, setBy string) error
+
+	// DeleteOverride removes the MANUAL rate of a pair and day, so the published rate applies again;
+	// returns *dberr.NotFoundError when there is none
+	DeleteOverride(ctx context.Context, base, quote string, date time.Time, deletedBy string) error
+}
+
+// Compile-time check that RdsRateRepository satisfies RateRepository
+var _ RateRepository = (*RdsRateRepository)(nil)
+
+type RdsRateRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsRateRepository(cfg *awsclient.Config) (*RdsRateRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsRateRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveRates upserts rates in a single statement: the columns are passed as arrays and unnested
+// server side, so a 90-day ECB backfill (~2,700 rates) is one round trip. Bulk loads by a
+// scheduled job are not written to the audit log; overrides are.
+//
+// Example:
+//
+//	rates, _ := ecb.FetchDaily(ctx)
+//	err := repo.SaveRates(ctx, rates, audit.SystemActor)
+func (r *RdsRateRepository) SaveRates(ctx context.Context, rates []fx.Rate, savedBy string) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	n := len(rates)
+	bases, quotes, dates, sources := make([]string, n), make([]string, n), make([]string, n), make([]string, n)
+	values := make([]float64, n)
+	for i, rt := range rates {
+		if rt.Source == "" || strings.EqualFold(rt.Source, SourceManual) {
+			return fmt.Errorf("rate %s/%s on %s: published rates need a source other than %s", rt.Base, rt.Quote, rt.Date.Format("2006-01-02"), SourceManual)
+		}
+		bases[i], quotes[i] = normalize(rt.Base), normalize(rt.Quote)
+		dates[i] = rt.Date.UTC().Format("2006-01-02")
+		sources[i] = rt.Source
+		values[i] = rt.Rate
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO fx_rates (base, quote, rate_date, source, rate, updated_by, updated_at)
+		SELECT b, q, d::date, s, v, $6, $7
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::float8[]) AS t(b, q, d, s, v)
+		ON CONFLICT (base, quote, rate_date, source)
+		DO UPDATE SET rate=EXCLUDED.rate, updated_by=EXCLUDED.updated_by, updated_at=EXCLUDED.updated_at
+	`,
+		pq.Array(bases),
+		pq.Array(quotes),
+		pq.Array(dates),
+		pq.Array(sources),
+		pq.Array(values),
+		savedBy,
+		time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("failed to save %d fx rates: %w", n, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fx rate transaction: %w", err)
+	}
+	return nil
+}
+
+// LoadRates returns the effective rates since a day: DISTINCT ON keeps one row per pair and
+// day, preferring MANUAL over any published source.
+func (r *RdsRateRepository) LoadRates(ctx context.Context, since time.Time) ([]fx.Rate, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+		SELECT DISTINCT ON (base, quote, rate_date) base, quote, rate_date, source, rate
+		FROM fx_rates
+		WHERE rate_date >= $1
+		ORDER BY base, quote, rate_date, (source = $2) DESC, source
+	`, since.UTC().Format("2006-01-02"), SourceManual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fx rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []fx.Rate
+	for rows.Next() {
+		var rt fx.Rate
+		if err := rows.Scan(&rt.Base, &rt.Quote, &rt.Date, &rt.Source, &rt.Rate); err != nil {
+			return nil, fmt.Errorf("failed to scan fx rate: %w", err)
+		}
+		rt.Date = rt.Date.UTC()
+		rates = append(rates, rt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read fx rates: %w", err)
+	}
+	return rates, nil
+}
+
+// SetOverride stores a MANUAL rate and records it in the audit log together with the rate it
+// replaces, if any.
+//
+// Example (the ECB fixing of a day was wrong):
+//
+//	err := repo.SetOverride(ctx, fx.Rate{Base: "EUR", Quote: "USD", Date: day, Rate: 1.0851},
+//	    "ECB republished the fixing", "user@internal.local")
+func (r *RdsRateRepository) SetOverride(ctx context.Context, rate fx.Rate, reason, setBy string) error {
+	if rate.Rate <= 0 {
+		return fmt.Errorf("rate %s/%s must be positive, got %v", rate.Base, rate.Quote, rate.Rate)
+	}
+	if reason == "" {
+		return fmt.Errorf("a reason is required to override rate %s/%s", rate.Base, rate.Quote)
+	}
+	base, quote, date := normalize(rate.Base), normalize(rate.Quote), rate.Date.UTC().Format("2006-01-02")
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var previous sql.NullFloat64
+	if err := tx.QueryRowContext(ctx, `
+		SELECT rate FROM fx_rates
+		WHERE base=$1 AND quote=$2 AND rate_date=$3
+		ORDER BY (source = $4) DESC, source
+		LIMIT 1
+	`, base, quote, date, SourceManual).Scan(&previous); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read fx rate %s/%s on %s: %w", base, quote, date, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO fx_rates (base, quote, rate_date, source, rate, updated_by, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		ON CONFLICT (base, quote, rate_date, source)
+		DO UPDATE SET rate=EXCLUDED.rate, updated_by=EXCLUDED.updated_by, updated_at=EXCLUDED.updated_at
+	`, base, quote, date, SourceManual, rate.Rate, setBy, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to override fx rate %s/%s on %s: %w", base, quote, date, err)
+	}
+
+	details := map[string]any{"date": date, "rate": rate.Rate, "reason": reason}
+	if previous.Valid {
+		details["previousRate"] = previous.Float64
+	}
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityFXRate, base+"/"+quote, audit.ActionUpdate, setBy, details)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fx rate transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteOverride removes a MANUAL rate and records the removal in the audit log.
+func (r *RdsRateRepository) DeleteOverride(ctx context.Context, base, quote string, date time.Time, deletedBy string) error {
+	base, quote = normalize(base), normalize(quote)
+	day := date.UTC().Format("2006-01-02")
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var removed float64
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM fx_rates
+		WHERE base=$1 AND quote=$2 AND rate_date=$3 AND source=$4
+		RETURNING rate
+	`, base, quote, day, SourceManual).Scan(&removed)
+	if err == sql.ErrNoRows {
+		return &dberr.NotFoundError{Entity: "fx rate override", ID: base + "/" + quote + " " + day}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete fx rate override %s/%s on %s: %w", base, quote, day, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityFXRate, base+"/"+quote, audit.ActionDelete, deletedBy,
+		map[string]any{"date": day, "rate": removed})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fx rate transaction: %w", err)
+	}
+	return nil
+}
+
+func normalize(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
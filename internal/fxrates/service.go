@@ -0,0 +1,158 @@
+package fxrates
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nholding/cso-book/internal/fx"
+	"github.com/nholding/cso-book/internal/fxrates/repository"
+	"github.com/nholding/cso-book/internal/platform/scheduler"
+)
+
+// DefaultHistory is how far back Load reads rates. It covers a year of month-end revaluations
+// and breakdowns of trades booked in the past year.
+const DefaultHistory = 400 * 24 * time.Hour
+
+// Publisher fetches published reference rates; *ECBClient implements it.
+type Publisher interface {
+	FetchDaily(ctx context.Context) ([]fx.Rate, error)
+	Fetch90Days(ctx context.Context) ([]fx.Rate, error)
+}
+
+// Service keeps the FX rates of the book: it pulls the daily ECB fixing, persists it, applies
+// manual overrides and serves lookups from memory. It implements fx.RateSource, so it is what
+// the breakdown and reporting layers convert and revalue with.
+//
+// PURPOSE:
+//
+//	The ECB publishes EUR reference rates every working day around 16:00 CET. A daily job
+//	(Job) stores them in fx_rates; the back office can override a fixing for a pair and day
+//	(SetOverride), which wins over the ECB rate until it is deleted again. After every change
+//	the in-memory table is rebuilt from the database, so lookups always see the effective
+//	rates:
+//
+//	    ECB feed ──────► fx_rates (ECB)    ─┐
+//	    PUT /fx/rates ─► fx_rates (MANUAL) ─┴─► LoadRates (MANUAL wins) ─► fx.Table ─► Rate()
+//
+// EXAMPLE USAGE:
+//
+//	rates := fxrates.NewService(rateRepo, fxrates.NewECBClient(nil))
+//	if err := rates.Load(ctx); err != nil {
+//	    log.Fatalf("error loading fx rates: %v", err)
+//	}
+//	tradeService.SetFXRates(rates, settings.ReportingCurrency)
+//	go scheduler.RunDaily(ctx, 16, 30, cet, rates.Job(audit.SystemActor), func(err error) {
+//	    log.Printf("fx rate refresh failed: %v", err)
+//	})
+type Service struct {
+	repo      repository.RateRepository
+	publisher Publisher
+	history   time.Duration
+
+	mu    sync.RWMutex
+	table *fx.Table
+}
+
+// Compile-time check that Service can be used as the rate source of conversions
+var _ fx.RateSource = (*Service)(nil)
+
+func NewService(repo repository.RateRepository, publisher Publisher) *Service {
+	return &Service{
+		repo:      repo,
+		publisher: publisher,
+		history:   DefaultHistory,
+		table:     fx.NewTable("EUR"),
+	}
+}
+
+// Rate returns the factor converting an amount in from into to on date (see fx.Table.Rate).
+func (s *Service) Rate(from, to string, date time.Time) (float64, error) {
+	s.mu.RLock()
+	table := s.table
+	s.mu.RUnlock()
+
+	return table.Rate(from, to, date)
+}
+
+// Latest returns the most recent effective rate of every pair.
+func (s *Service) Latest() []fx.Rate {
+	s.mu.RLock()
+	table := s.table
+	s.mu.RUnlock()
+
+	return table.Latest()
+}
+
+// Load rebuilds the in-memory table from the database. The new table replaces the old one in
+// one step, so lookups never see a half-loaded table.
+func (s *Service) Load(ctx context.Context) error {
+	rates, err := s.repo.LoadRates(ctx, time.Now().UTC().Add(-s.history))
+	if err != nil {
+		return err
+	}
+
+	table := fx.NewTable("EUR")
+	table.Add(rates...)
+
+	s.mu.Lock()
+	s.table = table
+	s.mu.Unlock()
+	return nil
+}
+
+// Refresh pulls the latest ECB fixing, stores it and reloads the table. It returns the number
+// of rates received.
+func (s *Service) Refresh(ctx context.Context, actor string) (int, error) {
+	return s.pull(ctx, s.publisher.FetchDaily, actor)
+}
+
+// Backfill pulls the fixings of the last 90 days, e.g. on first start or after the job has
+// not run for a while. Existing ECB rates are overwritten with the same values.
+func (s *Service) Backfill(ctx context.Context, actor string) (int, error) {
+	return s.pull(ctx, s.publisher.Fetch90Days, actor)
+}
+
+func (s *Service) pull(ctx context.Context, fetch func(context.Context) ([]fx.Rate, error), actor string) (int, error) {
+	rates, err := fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.SaveRates(ctx, rates, actor); err != nil {
+		return 0, err
+	}
+	if err := s.Load(ctx); err != nil {
+		return 0, fmt.Errorf("fx rates saved but not reloaded: %w", err)
+	}
+	return len(rates), nil
+}
+
+// SetOverride replaces the rate of a pair on one day with a manual rate.
+//
+// Example:
+//
+//	err := rates.SetOverride(ctx, fx.Rate{Base: "EUR", Quote: "USD", Date: day, Rate: 1.0851},
+//	    "ECB republished the fixing", "user@internal.local")
+func (s *Service) SetOverride(ctx context.Context, rate fx.Rate, reason, actor string) error {
+	if err := s.repo.SetOverride(ctx, rate, reason, actor); err != nil {
+		return err
+	}
+	return s.Load(ctx)
+}
+
+// DeleteOverride removes a manual rate; the ECB rate of that day applies again.
+func (s *Service) DeleteOverride(ctx context.Context, base, quote string, date time.Time, actor string) error {
+	if err := s.repo.DeleteOverride(ctx, base, quote, date, actor); err != nil {
+		return err
+	}
+	return s.Load(ctx)
+}
+
+// Job returns the daily refresh as a scheduler job.
+func (s *Service) Job(actor string) scheduler.Job {
+	return func(ctx context.Context) error {
+		_, err := s.Refresh(ctx, actor)
+		return err
+	}
+}
@@ -0,0 +1,320 @@
+package invoice
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// InvoiceStatus is the lifecycle state of an invoice: DRAFT → SENT → PAID.
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft InvoiceStatus = "DRAFT" // Generated, may still be reviewed
+	InvoiceStatusSent  InvoiceStatus = "SENT"  // Issued to (or received from) the counterparty
+	InvoiceStatusPaid  InvoiceStatus = "PAID"  // Settled
+)
+
+// Direction tells who pays an invoice.
+type Direction string
+
+const (
+	DirectionReceivable Direction = "RECEIVABLE" // Sales: we invoice the counterparty
+	DirectionPayable    Direction = "PAYABLE"    // Purchases and storage: the counterparty invoices us
+)
+
+// invoiceNumberPrefixes are the leading letters of invoice numbers per direction.
+var invoiceNumberPrefixes = map[Direction]string{
+	DirectionReceivable: "INV",
+	DirectionPayable:    "BILL",
+}
+
+// NumberPrefix returns the invoice number prefix of a direction, e.g. "INV" for receivables.
+// It names the number sequence the invoice draws from.
+func NumberPrefix(d Direction) (string, error) {
+	prefix, ok := invoiceNumberPrefixes[d]
+	if !ok {
+		return "", fmt.Errorf("no invoice number prefix for direction %q", d)
+	}
+	return prefix, nil
+}
+
+// FormatInvoiceNumber formats the number of an invoice from the sequential number of its
+// direction in the year it was issued. Numbers have at least four digits.
+//
+// Example:
+//
+//	FormatInvoiceNumber(DirectionReceivable, 2026, 1)  // → "INV-2026-0001"
+//	FormatInvoiceNumber(DirectionPayable, 2026, 42)    // → "BILL-2026-0042"
+func FormatInvoiceNumber(d Direction, year, seq int) (string, error) {
+	prefix, err := NumberPrefix(d)
+	if err != nil {
+		return "", err
+	}
+	if seq <= 0 {
+		return "", fmt.Errorf("invalid invoice number sequence %d", seq)
+	}
+	return fmt.Sprintf("%s-%d-%04d", prefix, year, seq), nil
+}
+
+// LineItem is one invoiced breakdown: the delivery (or fee) of one trade in the invoice month.
+type LineItem struct {
+	BreakdownID string                   `json:"breakdownId"`
+	TradeID     string                   `json:"tradeId"`
+	TradeNumber string                   `json:"tradeNumber,omitempty"`
+	PeriodID    string                   `json:"periodId"`
+	Component   trade.BreakdownComponent `json:"component,omitempty"`
+	Description string                   `json:"description"`
+	VolumeMT    float64                  `json:"volumeMT"`
+	PricePerMT  float64                  `json:"pricePerMT"`
+	Amount      float64                  `json:"amount"` // Net of tax, in the invoice currency
+}
+
+// Key identifies what a line invoices independently of the breakdown version: amending a trade
+// replaces its breakdowns by new ones, but the month it delivered stays invoiced. The trade
+// number is shared by all versions of a trade; trades without one fall back to their ID.
+func (l LineItem) Key() string {
+	return LineKey(l.TradeNumber, l.TradeID, l.PeriodID, l.Component)
+}
+
+// LineKey builds the key of LineItem.Key from its parts.
+//
+// Example:
+//
+//	LineKey("S-2026-0042", "T7", "2026-JAN", "")  // → "S-2026-0042|2026-JAN|COMMODITY"
+func LineKey(tradeNumber, tradeID, periodID string, component trade.BreakdownComponent) string {
+	ref := tradeNumber
+	if ref == "" {
+		ref = tradeID
+	}
+	if component == "" {
+		component = trade.ComponentCommodity
+	}
+	return ref + "|" + periodID + "|" + string(component)
+}
+
+// Invoice bills the delivered breakdowns of one counterparty in one delivery month, direction
+// and currency.
+//
+// Example (sales to C42 in January 2026 at 21% tax):
+//
+//	Invoice{Number: "INV-2026-0001", CompanyID: "C42", PeriodID: "2026-JAN", Direction: DirectionReceivable,
+//	    Currency: "EUR", NetAmount: 496000, TaxRatePct: 21, TaxAmount: 104160, GrossAmount: 600160, Status: InvoiceStatusDraft}
+type Invoice struct {
+	ID          string          `json:"id"`     // Stable ULID (primary key)
+	Number      string          `json:"number"` // Human-readable and unique, e.g. INV-2026-0001
+	CompanyID   string          `json:"companyId"`
+	CompanyName string          `json:"companyName,omitempty"` // Copied from the breakdowns for display
+	PeriodID    string          `json:"periodId"`              // Delivery month
+	Direction   Direction       `json:"direction"`
+	Currency    string          `json:"currency"`
+	Lines       []LineItem      `json:"lines"`
+	NetAmount   float64         `json:"netAmount"`
+	TaxRatePct  float64         `json:"taxRatePct"`
+	TaxAmount   float64         `json:"taxAmount"`
+	GrossAmount float64         `json:"grossAmount"`
+	Status      InvoiceStatus   `json:"status"`
+	IssueDate   time.Time       `json:"issueDate"`
+	SentAt      *time.Time      `json:"sentAt,omitempty"`
+	PaidAt      *time.Time      `json:"paidAt,omitempty"`
+	RowVersion  int             `json:"rowVersion"` // Optimistic-lock counter, bumped by every update of the stored row
+	AuditInfo   audit.AuditInfo `json:"audit"`
+}
+
+// SetTax applies a tax rate to the net amount of the lines. Amounts are rounded to cents.
+func (inv *Invoice) SetTax(ratePct float64) error {
+	if ratePct < 0 || ratePct > 100 {
+		return fmt.Errorf("tax rate must lie between 0%% and 100%%, got %v%%", ratePct)
+	}
+
+	var net float64
+	for _, l := range inv.Lines {
+		net += l.Amount
+	}
+	inv.NetAmount = roundCents(net)
+	inv.TaxRatePct = ratePct
+	inv.TaxAmount = roundCents(inv.NetAmount * ratePct / 100)
+	inv.GrossAmount = inv.NetAmount + inv.TaxAmount
+	return nil
+}
+
+// MarkSent moves a DRAFT invoice to SENT.
+func (inv *Invoice) MarkSent(at time.Time, user string) error {
+	if inv.Status != InvoiceStatusDraft {
+		return fmt.Errorf("invoice %s is %s; only DRAFT invoices can be sent", inv.Number, inv.Status)
+	}
+	at = at.UTC()
+	inv.Status = InvoiceStatusSent
+	inv.SentAt = &at
+	inv.AuditInfo.UpdateAuditInfo(user)
+	return nil
+}
+
+// MarkPaid moves a SENT invoice to PAID.
+func (inv *Invoice) MarkPaid(at time.Time, user string) error {
+	if inv.Status != InvoiceStatusSent {
+		return fmt.Errorf("invoice %s is %s; only SENT invoices can be paid", inv.Number, inv.Status)
+	}
+	at = at.UTC()
+	inv.Status = InvoiceStatusPaid
+	inv.PaidAt = &at
+	inv.AuditInfo.UpdateAuditInfo(user)
+	return nil
+}
+
+// DirectionOf returns who pays for the deliveries of a trade kind. Swaps settle in cash and are
+// not invoiced from breakdowns.
+func DirectionOf(kind trade.TradeKind) (Direction, bool) {
+	switch kind {
+	case trade.TradeKindSale:
+		return DirectionReceivable, true
+	case trade.TradeKindPurchase, trade.TradeKindStorage:
+		return DirectionPayable, true
+	}
+	return "", false
+}
+
+// GroupDeliveries
+//
+// PURPOSE:
+//
+//	Groups the delivered breakdowns of a book into draft invoices, one per counterparty,
+//	delivery month, direction and currency:
+//
+//	  C42  2026-JAN  RECEIVABLE  EUR
+//	      S-2026-0007  2026-JAN  800 MT × 620   496,000
+//	      S-2026-0011  2026-JAN  200 MT × 615   123,000
+//
+//	A breakdown is delivered once its EndDate lies before asOf. Only CONFIRMED trades are
+//	invoiced, only FINAL prices (a provisional month waits until its fixings are known), and
+//	never a line whose key is in invoiced (see LineItem.Key). Reversal entries are skipped.
+//	When periodID is set, only that delivery month is grouped.
+//
+//	The drafts carry no number or tax yet; the invoicing service assigns them. They are ordered
+//	by delivery month, then counterparty, direction (receivables first) and currency.
+//
+// EXAMPLE USAGE:
+//
+//	drafts := invoice.GroupDeliveries(book, "2026-JAN", time.Now(), invoicedKeys, "user@internal.local")
+func GroupDeliveries(book []trade.BookedTrade, periodID string, asOf time.Time, invoiced map[string]bool, user string) []Invoice {
+	type key struct {
+		company, period, currency string
+		direction                 Direction
+	}
+	drafts := make(map[key]*Invoice)
+	months := make(map[*Invoice]time.Time) // Start of the delivery month of each draft
+
+	for _, bt := range book {
+		tb := bt.Trade.Base()
+		if !trade.IsLive(bt.Trade) || tb.Status != trade.TradeStatusConfirmed {
+			continue
+		}
+		direction, ok := DirectionOf(bt.Trade.Kind())
+		if !ok {
+			continue
+		}
+
+		for _, bd := range trade.LiveBreakdowns(bt.Breakdowns) {
+			if bd.IsReversal() || bd.PriceStatus != trade.PriceFinal || !bd.EndDate.Before(asOf) {
+				continue
+			}
+			if periodID != "" && bd.PeriodID != periodID {
+				continue
+			}
+			line := LineItem{
+				BreakdownID: bd.ID,
+				TradeID:     tb.ID,
+				TradeNumber: tb.TradeNumber,
+				PeriodID:    bd.PeriodID,
+				Component:   bd.Component,
+				Description: describeLine(tb, bd),
+				VolumeMT:    bd.VolumeMT,
+				PricePerMT:  bd.PricePerMT,
+				Amount:      bd.TotalAmount,
+			}
+			if invoiced[line.Key()] {
+				continue
+			}
+
+			currency := strings.ToUpper(bd.Currency)
+			k := key{bt.Trade.CounterpartyID(), bd.PeriodID, currency, direction}
+			inv := drafts[k]
+			if inv == nil {
+				inv = &Invoice{
+					ID:          utils.GenerateStableID(),
+					CompanyID:   k.company,
+					CompanyName: bd.CounterpartyName,
+					PeriodID:    k.period,
+					Direction:   direction,
+					Currency:    currency,
+					Status:      InvoiceStatusDraft,
+					IssueDate:   utils.StartOfDay(asOf.UTC()),
+					AuditInfo:   *audit.NewAuditInfo(user),
+				}
+				drafts[k] = inv
+			}
+			months[inv] = time.Date(bd.StartDate.Year(), bd.StartDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+			inv.Lines = append(inv.Lines, line)
+			inv.NetAmount += line.Amount
+		}
+	}
+
+	ordered := make([]*Invoice, 0, len(drafts))
+	for _, inv := range drafts {
+		sort.SliceStable(inv.Lines, func(i, j int) bool { return inv.Lines[i].Key() < inv.Lines[j].Key() })
+		inv.NetAmount = roundCents(inv.NetAmount)
+		inv.GrossAmount = inv.NetAmount
+		ordered = append(ordered, inv)
+	}
+	// Months in calendar order: period IDs like "2026-APR" and "2026-JAN" do not sort as strings
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if !months[a].Equal(months[b]) {
+			return months[a].Before(months[b])
+		}
+		if a.PeriodID != b.PeriodID {
+			return a.PeriodID < b.PeriodID
+		}
+		if a.CompanyID != b.CompanyID {
+			return a.CompanyID < b.CompanyID
+		}
+		if a.Direction != b.Direction {
+			return a.Direction > b.Direction // RECEIVABLE first
+		}
+		return a.Currency < b.Currency
+	})
+
+	result := make([]Invoice, len(ordered))
+	for i, inv := range ordered {
+		result[i] = *inv
+	}
+	return result
+}
+
+// describeLine returns the text of an invoice line, e.g. "S-2026-0007 ULSD 10ppm 2026-JAN".
+func describeLine(tb *trade.TradeBase, bd trade.TradeBreakdown) string {
+	ref := tb.TradeNumber
+	if ref == "" {
+		ref = tb.ID
+	}
+	parts := []string{ref}
+	if tb.Commodity != "" {
+		parts = append(parts, tb.Commodity)
+	}
+	parts = append(parts, bd.PeriodID)
+	if bd.IsFee() {
+		parts = append(parts, "availability fee")
+	}
+	return strings.Join(parts, " ")
+}
+
+// roundCents rounds an amount to two decimals.
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
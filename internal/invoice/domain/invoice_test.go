@@ -0,0 +1,52 @@
+package invoice
+
+import (
+	"testing"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+func TestGroupDeliveriesOrdersMonthsByCalendar(t *testing.T) {
+	store := period.NewMockPeriodStore(2026, 2026)
+	const user = "user@internal.local"
+
+	// Two counterparties delivering JAN–JUN 2026; as strings the months would sort APR, FEB, JAN, ...
+	var book []trade.BookedTrade
+	for _, supplier := range []string{"C43", "C42"} {
+		purchase, breakdowns := trade.NewPurchase(store, supplier, period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q2"},
+			1000, 600, "EUR", user)
+		purchase.ID = "P-" + supplier
+		purchase.Status = trade.TradeStatusConfirmed
+		book = append(book, trade.BookedTrade{Trade: &purchase, Breakdowns: breakdowns})
+	}
+
+	drafts := GroupDeliveries(book, "", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), nil, user)
+
+	var got []string
+	for _, inv := range drafts {
+		got = append(got, inv.PeriodID+" "+inv.CompanyID)
+	}
+	want := []string{
+		"2026-JAN C42", "2026-JAN C43",
+		"2026-FEB C42", "2026-FEB C43",
+		"2026-MAR C42", "2026-MAR C43",
+		"2026-APR C42", "2026-APR C43",
+		"2026-MAY C42", "2026-MAY C43",
+		"2026-JUN C42", "2026-JUN C43",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GroupDeliveries returned %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("GroupDeliveries returned %v, want %v", got, want)
+		}
+	}
+	for _, inv := range drafts {
+		if inv.Direction != DirectionPayable || len(inv.Lines) != 1 || inv.NetAmount != 600000 {
+			t.Errorf("draft %s %s: %s with %d lines over %v, want one payable line over 600000", inv.PeriodID, inv.CompanyID, inv.Direction, len(inv.Lines), inv.NetAmount)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	invoice "github.com/nholding/cso-book/internal/invoice/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// InvoiceRepository defines the interface for storing and retrieving Invoices from a persistence layer
+type InvoiceRepository interface {
+	// SaveInvoice inserts a new invoice with its lines; it fails with *dberr.DuplicateError
+	// when one of its lines is already invoiced
+	SaveInvoice(ctx context.Context, inv *invoice.Invoice) error
+
+	// UpdateStatus writes the status of an invoice, checked against its RowVersion (optimistic
+	// concurrency; a lost race returns *dberr.ConflictError)
+	UpdateStatus(ctx context.Context, inv *invoice.Invoice) error
+
+	// FindByID retrieves an invoice with its lines; returns nil, nil when it does not exist
+	FindByID(ctx context.Context, id string) (*invoice.Invoice, error)
+
+	// List retrieves the invoices matching the filter with their lines, by issue date and number
+	List(ctx context.Context, filter InvoiceFilter) ([]*invoice.Invoice, error)
+
+	// InvoicedKeys returns the line keys (see invoice.LineItem.Key) of a company's invoices, of
+	// every company when companyID is empty; statuses narrows the invoices when given
+	InvoicedKeys(ctx context.Context, companyID string, statuses ...invoice.InvoiceStatus) (map[string]bool, error)
+
+	// NextNumber reserves and returns the next invoice number of prefix in year
+	NextNumber(ctx context.Context, prefix string, year int) (int, error)
+}
+
+// InvoiceFilter narrows List. Zero fields match everything.
+//
+// Example (open receivables of C42):
+//
+//	InvoiceFilter{CompanyID: "C42", Direction: invoice.DirectionReceivable, Status: invoice.InvoiceStatusSent}
+type InvoiceFilter struct {
+	CompanyID string
+	PeriodID  string
+	Direction invoice.Direction
+	Status    invoice.InvoiceStatus
+}
+
+// Compile-time check that RdsInvoiceRepository satisfies InvoiceRepository
+var _ InvoiceRepository = (*RdsInvoiceRepository)(nil)
+
+// RdsInvoiceRepository keeps invoices in the invoices and invoice_lines tables and their number
+// counters in invoice_number_sequences, one row per prefix and year like trade numbers.
+type RdsInvoiceRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsInvoiceRepository(cfg *awsclient.Config) (*RdsInvoiceRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsInvoiceRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveInvoice inserts a new invoice and its lines. The line_key column is unique, so a delivery
+// month that is already invoiced is rejected instead of being billed twice.
+//
+// Example:
+//
+//	drafts := invoice.GroupDeliveries(book, "2026-JAN", time.Now(), invoiced, "user@internal.local")
+//	err := repo.SaveInvoice(ctx, &drafts[0])
+func (r *RdsInvoiceRepository) SaveInvoice(ctx context.Context, inv *invoice.Invoice) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO invoices (
+			id, invoice_number, company_id, company_name, period_id, direction, currency,
+			net_amount, tax_rate_pct, tax_amount, gross_amount, status, issue_date, sent_at, paid_at,
+			row_version, audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,1,$16,$17,$18,$19)
+	`,
+		inv.ID,
+		inv.Number,
+		inv.CompanyID,
+		inv.CompanyName,
+		inv.PeriodID,
+		string(inv.Direction),
+		inv.Currency,
+		inv.NetAmount,
+		inv.TaxRatePct,
+		inv.TaxAmount,
+		inv.GrossAmount,
+		string(inv.Status),
+		inv.IssueDate,
+		inv.SentAt,
+		inv.PaidAt,
+		inv.AuditInfo.CreatedBy,
+		inv.AuditInfo.CreatedAt,
+		inv.AuditInfo.UpdatedBy,
+		inv.AuditInfo.UpdatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "invoice", ID: inv.ID, Key: "number " + inv.Number, Err: err}
+		}
+		return fmt.Errorf("failed to insert invoice %s: %w", inv.Number, err)
+	}
+
+	for i, l := range inv.Lines {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO invoice_lines (
+				invoice_id, line_no, line_key, breakdown_id, trade_id, trade_number, period_id,
+				component, description, volume_mt, price_per_mt, amount
+			) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+		`,
+			inv.ID,
+			i+1,
+			l.Key(),
+			l.BreakdownID,
+			l.TradeID,
+			sql.NullString{String: l.TradeNumber, Valid: l.TradeNumber != ""},
+			l.PeriodID,
+			string(componentOrCommodity(l.Component)),
+			l.Description,
+			l.VolumeMT,
+			l.PricePerMT,
+			l.Amount,
+		); err != nil {
+			if dberr.IsUniqueViolation(err) {
+				return &dberr.DuplicateError{Entity: "invoice line", ID: l.BreakdownID, Key: "line " + l.Key(), Err: err}
+			}
+			return fmt.Errorf("failed to insert line %d of invoice %s: %w", i+1, inv.Number, err)
+		}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityInvoice, inv.ID, audit.ActionCreate, inv.AuditInfo.CreatedBy,
+		map[string]any{"number": inv.Number, "companyId": inv.CompanyID, "periodId": inv.PeriodID, "gross": inv.GrossAmount, "currency": inv.Currency})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit invoice transaction: %w", err)
+	}
+
+	inv.RowVersion = 1
+	return nil
+}
+
+// UpdateStatus writes the status, sent and paid timestamps of an invoice. Amounts and lines never
+// change once stored. On success inv.RowVersion is incremented to match the DB.
+func (r *RdsInvoiceRepository) UpdateStatus(ctx context.Context, inv *invoice.Invoice) error {
+	expected := inv.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE invoices
+		SET status=$1, sent_at=$2, paid_at=$3, audit_updated_by=$4, audit_updated_at=$5, row_version=row_version+1
+		WHERE id=$6 AND row_version=$7
+	`,
+		string(inv.Status),
+		inv.SentAt,
+		inv.PaidAt,
+		inv.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		inv.ID,
+		expected,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice %s: %w", inv.Number, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM invoices WHERE id=$1`, inv.ID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "invoice", ID: inv.ID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of invoice %s: %w", inv.ID, err)
+		}
+		return &dberr.ConflictError{Entity: "invoice", ID: inv.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityInvoice, inv.ID, audit.ActionStatusChange, inv.AuditInfo.LastActor(),
+		map[string]any{"number": inv.Number, "to": string(inv.Status)})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit invoice transaction: %w", err)
+	}
+
+	inv.RowVersion = expected + 1
+	return nil
+}
+
+// FindByID retrieves a single invoice with its lines. Returns nil, nil when the invoice does not
+// exist.
+func (r *RdsInvoiceRepository) FindByID(ctx context.Context, id string) (*invoice.Invoice, error) {
+	invoices, err := r.query(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE id=$1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(invoices) == 0 {
+		return nil, nil // Not found
+	}
+	return invoices[0], nil
+}
+
+// List retrieves the invoices matching the filter with their lines, ordered by issue date and
+// number.
+//
+// Example:
+//
+//	invoices, err := repo.List(ctx, InvoiceFilter{PeriodID: "2026-JAN", Status: invoice.InvoiceStatusDraft})
+func (r *RdsInvoiceRepository) List(ctx context.Context, filter InvoiceFilter) ([]*invoice.Invoice, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.CompanyID != "" {
+		add("company_id=$%d", filter.CompanyID)
+	}
+	if filter.PeriodID != "" {
+		add("period_id=$%d", filter.PeriodID)
+	}
+	if filter.Direction != "" {
+		add("direction=$%d", string(filter.Direction))
+	}
+	if filter.Status != "" {
+		add("status=$%d", string(filter.Status))
+	}
+
+	query := `SELECT ` + invoiceColumns + ` FROM invoices`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY issue_date, invoice_number`
+	return r.query(ctx, query, args...)
+}
+
+// InvoicedKeys returns the line keys of the invoices of a company, optionally only of invoices
+// in one of statuses. Company merges re-point invoices, so the keys follow the surviving company.
+//
+// Example:
+//
+//	paid, err := repo.InvoicedKeys(ctx, "C42", invoice.InvoiceStatusPaid)
+//	// paid["S-2026-0007|2026-JAN|COMMODITY"] → true
+func (r *RdsInvoiceRepository) InvoicedKeys(ctx context.Context, companyID string, statuses ...invoice.InvoiceStatus) (map[string]bool, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if companyID != "" {
+		add("i.company_id=$%d", companyID)
+	}
+	if len(statuses) > 0 {
+		names := make([]string, len(statuses))
+		for i, s := range statuses {
+			names[i] = string(s)
+		}
+		add("i.status = ANY($%d)", pq.Array(names))
+	}
+
+	query := `SELECT l.line_key FROM invoice_lines l JOIN invoices i ON i.id = l.invoice_id`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoiced lines: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan invoiced line: %w", err)
+		}
+		keys[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invoiced lines: %w", err)
+	}
+	return keys, nil
+}
+
+// NextNumber increments the counter of prefix in year, creating it on first use. Inside a
+// txn.Manager transaction the increment commits or rolls back with the invoice, so invoice
+// numbers have no gaps.
+//
+// Example:
+//
+//	seq, err := repo.NextNumber(ctx, "INV", 2026) // → 7
+//	number, _ := invoice.FormatInvoiceNumber(invoice.DirectionReceivable, 2026, seq) // → "INV-2026-0007"
+func (r *RdsInvoiceRepository) NextNumber(ctx context.Context, prefix string, year int) (int, error) {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var next int
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO invoice_number_sequences (prefix, year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (prefix, year) DO UPDATE SET last_number = invoice_number_sequences.last_number + 1
+		RETURNING last_number
+	`, prefix, year).Scan(&next); err != nil {
+		return 0, fmt.Errorf("failed to reserve %s invoice number for %d: %w", prefix, year, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit invoice number transaction: %w", err)
+	}
+
+	return next, nil
+}
+
+// query runs an invoice query and loads the lines of the invoices it returns.
+func (r *RdsInvoiceRepository) query(ctx context.Context, query string, args ...any) ([]*invoice.Invoice, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []*invoice.Invoice
+	byID := make(map[string]*invoice.Invoice)
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, inv)
+		byID[inv.ID] = inv
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invoices: %w", err)
+	}
+	if len(invoices) == 0 {
+		return invoices, nil
+	}
+
+	ids := make([]string, len(invoices))
+	for i, inv := range invoices {
+		ids[i] = inv.ID
+	}
+	lines, err := r.reader.QueryContext(ctx, `
+		SELECT invoice_id, breakdown_id, trade_id, COALESCE(trade_number, ''), period_id, component,
+		       description, volume_mt, price_per_mt, amount
+		FROM invoice_lines
+		WHERE invoice_id = ANY($1)
+		ORDER BY invoice_id, line_no
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoice lines: %w", err)
+	}
+	defer lines.Close()
+
+	for lines.Next() {
+		var (
+			invoiceID string
+			component string
+			l         invoice.LineItem
+		)
+		if err := lines.Scan(&invoiceID, &l.BreakdownID, &l.TradeID, &l.TradeNumber, &l.PeriodID, &component,
+			&l.Description, &l.VolumeMT, &l.PricePerMT, &l.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice line: %w", err)
+		}
+		l.Component = trade.BreakdownComponent(component)
+		if inv := byID[invoiceID]; inv != nil {
+			inv.Lines = append(inv.Lines, l)
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invoice lines: %w", err)
+	}
+	return invoices, nil
+}
+
+// componentOrCommodity stores an empty breakdown component as COMMODITY, like LineItem.Key does.
+func componentOrCommodity(c trade.BreakdownComponent) trade.BreakdownComponent {
+	if c == "" {
+		return trade.ComponentCommodity
+	}
+	return c
+}
+
+// invoiceColumns is the column list scanned by scanInvoice.
+const invoiceColumns = `id, invoice_number, company_id, company_name, period_id, direction, currency,
+	net_amount, tax_rate_pct, tax_amount, gross_amount, status, issue_date, sent_at, paid_at, row_version,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInvoice(row rowScanner) (*invoice.Invoice, error) {
+	var (
+		inv       invoice.Invoice
+		direction string
+		status    string
+	)
+	if err := row.Scan(
+		&inv.ID,
+		&inv.Number,
+		&inv.CompanyID,
+		&inv.CompanyName,
+		&inv.PeriodID,
+		&direction,
+		&inv.Currency,
+		&inv.NetAmount,
+		&inv.TaxRatePct,
+		&inv.TaxAmount,
+		&inv.GrossAmount,
+		&status,
+		&inv.IssueDate,
+		&inv.SentAt,
+		&inv.PaidAt,
+		&inv.RowVersion,
+		&inv.AuditInfo.CreatedBy,
+		&inv.AuditInfo.CreatedAt,
+		&inv.AuditInfo.UpdatedBy,
+		&inv.AuditInfo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	inv.Direction = invoice.Direction(direction)
+	inv.Status = invoice.InvoiceStatus(status)
+
+	return &inv, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/exposure"
+	invoice "github.com/nholding/cso-book/internal/invoice/domain"
+	"github.com/nholding/cso-book/internal/invoice/repository"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+	traderepo "github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// BookSource provides the live trades of the book with their breakdowns; *service.TradeService
+// implements it.
+type BookSource interface {
+	ListBookedTrades(ctx context.Context, filter traderepo.TradeFilter) ([]trade.BookedTrade, error)
+}
+
+// TaxRates provides the tax rate of an invoice, e.g. by the counterparty's country and VAT
+// status.
+type TaxRates interface {
+	// TaxRatePct returns the rate in percent, e.g. 21 for 21% VAT
+	TaxRatePct(ctx context.Context, companyID string, direction invoice.Direction) (float64, error)
+}
+
+// FlatTaxRate is a TaxRates that applies the same rate to every invoice; 0 for zero-rated
+// (e.g. reverse-charged or exported) deliveries.
+type FlatTaxRate float64
+
+func (r FlatTaxRate) TaxRatePct(context.Context, string, invoice.Direction) (float64, error) {
+	return float64(r), nil
+}
+
+// Compile-time check that InvoiceService can tell exposure which breakdowns are paid
+var _ exposure.SettlementSource = (*InvoiceService)(nil)
+
+type InvoiceService struct {
+	repo  repository.InvoiceRepository
+	book  BookSource
+	taxes TaxRates
+	tx    *txn.Manager // nil: every repository call runs in its own transaction
+}
+
+func NewInvoiceService(repo repository.InvoiceRepository, book BookSource, taxes TaxRates, tx *txn.Manager) *InvoiceService {
+	if taxes == nil {
+		taxes = FlatTaxRate(0)
+	}
+	return &InvoiceService{
+		repo:  repo,
+		book:  book,
+		taxes: taxes,
+		tx:    tx,
+	}
+}
+
+// GenerateDrafts
+//
+// PURPOSE:
+//
+//	Invoices everything delivered before asOf that is not invoiced yet: the delivered
+//	breakdowns of the CONFIRMED trades are grouped per counterparty, delivery month, direction
+//	and currency (see invoice.GroupDeliveries), taxed, numbered and stored as DRAFT:
+//
+//	  INV-2026-0001   C42  2026-JAN  RECEIVABLE  EUR  net 619,000  tax 129,990  gross 748,990
+//	  BILL-2026-0001  C17  2026-JAN  PAYABLE     USD  net 600,000  tax       0  gross 600,000
+//
+//	An empty periodID invoices every delivery month. Running it again only picks up what was
+//	delivered (or finally priced) since, so it can run daily. With a txn.Manager the whole
+//	run commits as one: invoice numbers have no gaps and a month is never half invoiced.
+//
+// EXAMPLE USAGE:
+//
+//	drafts, err := invoiceService.GenerateDrafts(ctx, "2026-JAN", time.Now(), "user@internal.local")
+func (s *InvoiceService) GenerateDrafts(ctx context.Context, periodID string, asOf time.Time, user string) ([]*invoice.Invoice, error) {
+	if user == "" {
+		return nil, errors.New("the invoicing user is required")
+	}
+
+	book, err := s.book.ListBookedTrades(ctx, traderepo.TradeFilter{PeriodID: periodID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+	invoiced, err := s.repo.InvoicedKeys(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invoiced deliveries: %w", err)
+	}
+
+	drafts := invoice.GroupDeliveries(book, periodID, asOf, invoiced, user)
+	created := make([]*invoice.Invoice, 0, len(drafts))
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		for i := range drafts {
+			inv := &drafts[i]
+			rate, err := s.taxes.TaxRatePct(ctx, inv.CompanyID, inv.Direction)
+			if err != nil {
+				return fmt.Errorf("failed to look up the tax rate of %s: %w", inv.CompanyID, err)
+			}
+			if err := inv.SetTax(rate); err != nil {
+				return fmt.Errorf("invoice of %s for %s: %w", inv.CompanyID, inv.PeriodID, err)
+			}
+			if err := s.assignNumber(ctx, inv); err != nil {
+				return err
+			}
+			if err := s.repo.SaveInvoice(ctx, inv); err != nil {
+				return fmt.Errorf("failed to save invoice %s: %w", inv.Number, err)
+			}
+			created = append(created, inv)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Get returns an invoice with its lines; it fails with a *dberr.NotFoundError when it does not
+// exist.
+func (s *InvoiceService) Get(ctx context.Context, id string) (*invoice.Invoice, error) {
+	inv, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invoice %s: %w", id, err)
+	}
+	if inv == nil {
+		return nil, &dberr.NotFoundError{Entity: "invoice", ID: id}
+	}
+	return inv, nil
+}
+
+// List returns the invoices matching the filter, by issue date and number.
+func (s *InvoiceService) List(ctx context.Context, filter repository.InvoiceFilter) ([]*invoice.Invoice, error) {
+	invoices, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// MarkSent records that a DRAFT invoice was issued to (or received from) the counterparty.
+//
+// Example:
+//
+//	inv, err := invoiceService.MarkSent(ctx, "01JH...", time.Now(), "user@internal.local")
+func (s *InvoiceService) MarkSent(ctx context.Context, id string, at time.Time, user string) (*invoice.Invoice, error) {
+	return s.transition(ctx, id, func(inv *invoice.Invoice) error { return inv.MarkSent(at, user) })
+}
+
+// MarkPaid records that a SENT invoice was settled. Its lines then count as paid in credit
+// exposure (see SettledBreakdowns).
+func (s *InvoiceService) MarkPaid(ctx context.Context, id string, at time.Time, user string) (*invoice.Invoice, error) {
+	return s.transition(ctx, id, func(inv *invoice.Invoice) error { return inv.MarkPaid(at, user) })
+}
+
+// SettledBreakdowns returns the IDs of the current breakdowns of a counterparty's trades whose
+// delivery is on a PAID invoice. Lines are matched by key (see invoice.LineItem.Key), so a month
+// stays paid when its trade is amended and gets new breakdowns.
+func (s *InvoiceService) SettledBreakdowns(ctx context.Context, counterpartyID string) (map[string]bool, error) {
+	paid, err := s.repo.InvoicedKeys(ctx, counterpartyID, invoice.InvoiceStatusPaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load paid invoices of %s: %w", counterpartyID, err)
+	}
+	settled := make(map[string]bool)
+	if len(paid) == 0 {
+		return settled, nil
+	}
+
+	book, err := s.book.ListBookedTrades(ctx, traderepo.TradeFilter{CounterpartyID: counterpartyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+	for _, bt := range book {
+		tb := bt.Trade.Base()
+		for _, bd := range bt.Breakdowns {
+			if paid[invoice.LineKey(tb.TradeNumber, tb.ID, bd.PeriodID, bd.Component)] {
+				settled[bd.ID] = true
+			}
+		}
+	}
+	return settled, nil
+}
+
+// transition loads an invoice, applies a status change and stores it.
+func (s *InvoiceService) transition(ctx context.Context, id string, change func(inv *invoice.Invoice) error) (*invoice.Invoice, error) {
+	inv, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(inv); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateStatus(ctx, inv); err != nil {
+		return nil, fmt.Errorf("failed to update invoice %s: %w", inv.Number, err)
+	}
+	return inv, nil
+}
+
+// assignNumber gives an invoice the next number of its direction in the year of its issue date.
+func (s *InvoiceService) assignNumber(ctx context.Context, inv *invoice.Invoice) error {
+	prefix, err := invoice.NumberPrefix(inv.Direction)
+	if err != nil {
+		return err
+	}
+	year := inv.IssueDate.Year()
+	seq, err := s.repo.NextNumber(ctx, prefix, year)
+	if err != nil {
+		return err
+	}
+	inv.Number, err = invoice.FormatInvoiceNumber(inv.Direction, year, seq)
+	return err
+}
+
+func (s *InvoiceService) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	location "github.com/nholding/cso-book/internal/location/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// LocationRepository defines the interface for storing and retrieving Locations from a persistence layer
+type LocationRepository interface {
+	// Save inserts a new location; it fails with *dberr.DuplicateError when the code is taken
+	Save(ctx context.Context, l *location.Location) error
+
+	// Update updates an existing location, checked against its RowVersion (optimistic
+	// concurrency; a lost race returns *dberr.ConflictError)
+	Update(ctx context.Context, l *location.Location) error
+
+	// FindByID retrieves a location; returns nil, nil when it does not exist
+	FindByID(ctx context.Context, id string) (*location.Location, error)
+
+	// FindByCode retrieves the location with a code; returns nil, nil when none exists
+	FindByCode(ctx context.Context, code string) (*location.Location, error)
+
+	// List retrieves the locations of a type (all types when empty), ordered by code
+	List(ctx context.Context, locationType location.LocationType) ([]*location.Location, error)
+}
+
+// Compile-time check that RdsLocationRepository satisfies LocationRepository
+var _ LocationRepository = (*RdsLocationRepository)(nil)
+
+type RdsLocationRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsLocationRepository(cfg *awsclient.Config) (*RdsLocationRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsLocationRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// Save inserts a new location. The code column is unique, so a location that is already known
+// is rejected instead of being stored twice.
+//
+// Example:
+//
+//	l, _ := location.NewLocation("NLRTM", "Rotterdam", location.TypePort, "NL", "", "user@internal.local")
+//	err := repo.Save(ctx, &l)
+func (r *RdsLocationRepository) Save(ctx context.Context, l *location.Location) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO locations (
+			id, code, name, type, country, parent_id, active, row_version,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,1,$8,$9,$10,$11)
+	`,
+		l.ID,
+		l.Code,
+		l.Name,
+		string(l.Type),
+		l.Country,
+		l.ParentID,
+		l.Active,
+		l.AuditInfo.CreatedBy,
+		l.AuditInfo.CreatedAt,
+		l.AuditInfo.UpdatedBy,
+		l.AuditInfo.UpdatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "location", ID: l.ID, Key: "code " + l.Code, Err: err}
+		}
+		return fmt.Errorf("failed to insert location %s: %w", l.Code, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityLocation, l.ID, audit.ActionCreate, l.AuditInfo.CreatedBy,
+		map[string]any{"code": l.Code, "name": l.Name, "type": string(l.Type)})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit location transaction: %w", err)
+	}
+
+	l.RowVersion = 1
+	return nil
+}
+
+// Update writes the current state of an existing location. The ID, code and creation audit
+// never change. On success l.RowVersion is incremented to match the DB.
+func (r *RdsLocationRepository) Update(ctx context.Context, l *location.Location) error {
+	expected := l.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE locations
+		SET name=$1, type=$2, country=$3, parent_id=$4, active=$5,
+		    audit_updated_by=$6, audit_updated_at=$7, row_version=row_version+1
+		WHERE id=$8 AND row_version=$9
+	`,
+		l.Name,
+		string(l.Type),
+		l.Country,
+		l.ParentID,
+		l.Active,
+		l.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		l.ID,
+		expected,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update location %s: %w", l.ID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM locations WHERE id=$1`, l.ID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "location", ID: l.ID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of location %s: %w", l.ID, err)
+		}
+		return &dberr.ConflictError{Entity: "location", ID: l.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityLocation, l.ID, audit.ActionUpdate, l.AuditInfo.LastActor(),
+		map[string]any{"name": l.Name, "type": string(l.Type), "country": l.Country, "active": l.Active})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit location transaction: %w", err)
+	}
+
+	l.RowVersion = expected + 1
+	return nil
+}
+
+// FindByID retrieves a single location by ID. Returns nil, nil when the location does not exist.
+func (r *RdsLocationRepository) FindByID(ctx context.Context, id string) (*location.Location, error) {
+	return r.findOne(ctx, `SELECT `+locationColumns+` FROM locations WHERE id=$1`, id)
+}
+
+// FindByCode retrieves a location by its code, case-insensitively.
+func (r *RdsLocationRepository) FindByCode(ctx context.Context, code string) (*location.Location, error) {
+	return r.findOne(ctx, `SELECT `+locationColumns+` FROM locations WHERE code=$1`, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// List retrieves the locations of one type, or all locations when locationType is empty.
+// Inactive locations are included; callers that book trades filter them out.
+func (r *RdsLocationRepository) List(ctx context.Context, locationType location.LocationType) ([]*location.Location, error) {
+	rows, err := r.reader.QueryContext(ctx,
+		`SELECT `+locationColumns+` FROM locations WHERE $1 = '' OR type = $1 ORDER BY code`, string(locationType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query locations: %w", err)
+	}
+	defer rows.Close()
+
+	var locations []*location.Location
+	for rows.Next() {
+		l, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan location: %w", err)
+		}
+		locations = append(locations, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate location rows: %w", err)
+	}
+
+	return locations, nil
+}
+
+func (r *RdsLocationRepository) findOne(ctx context.Context, query string, arg string) (*location.Location, error) {
+	l, err := scanLocation(r.reader.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan location: %w", err)
+	}
+
+	return l, nil
+}
+
+// locationColumns is the column list scanned by scanLocation.
+const locationColumns = `id, code, name, type, country, parent_id, active, row_version,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLocation(row rowScanner) (*location.Location, error) {
+	var (
+		l            location.Location
+		locationType string
+	)
+	if err := row.Scan(
+		&l.ID,
+		&l.Code,
+		&l.Name,
+		&locationType,
+		&l.Country,
+		&l.ParentID,
+		&l.Active,
+		&l.RowVersion,
+		&l.AuditInfo.CreatedBy,
+		&l.AuditInfo.CreatedAt,
+		&l.AuditInfo.UpdatedBy,
+		&l.AuditInfo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	l.Type = location.LocationType(locationType)
+
+	return &l, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	location "github.com/nholding/cso-book/internal/location/domain"
+	"github.com/nholding/cso-book/internal/location/repository"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+)
+
+type LocationService struct {
+	repo repository.LocationRepository
+}
+
+func NewLocationService(repo repository.LocationRepository) *LocationService {
+	return &LocationService{
+		repo: repo,
+	}
+}
+
+// Create
+//
+// PURPOSE:
+//
+//	Adds a port, terminal or storage site to the master data. Codes are unique: a code that is
+//	already taken is rejected with a *dberr.DuplicateError. A terminal or storage site may lie
+//	within a parent location, which must exist and be a PORT.
+//
+// EXAMPLE USAGE:
+//
+//	port, err := locationService.Create(ctx, "NLRTM", "Rotterdam", location.TypePort, "NL", "", "user@internal.local")
+//	term, err := locationService.Create(ctx, "NLRTM-EUROTANK", "Euro Tank Terminal", location.TypeTerminal, "NL", port.ID, "user@internal.local")
+func (s *LocationService) Create(ctx context.Context, code, name string, locationType location.LocationType, country, parentID, user string) (*location.Location, error) {
+	l, err := location.NewLocation(code, name, locationType, country, parentID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByCode(ctx, l.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up location %s: %w", l.Code, err)
+	}
+	if existing != nil {
+		return nil, &dberr.DuplicateError{Entity: "location", ID: existing.ID, Key: "code " + l.Code}
+	}
+
+	if parentID != "" {
+		parent, err := s.repo.FindByID(ctx, parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load parent location %s: %w", parentID, err)
+		}
+		if parent == nil {
+			return nil, &dberr.NotFoundError{Entity: "location", ID: parentID, Reason: "parent of " + l.Code}
+		}
+		if parent.Type != location.TypePort {
+			return nil, fmt.Errorf("location %s cannot lie within %s: the parent must be a %s, not a %s", l.Code, parent.Code, location.TypePort, parent.Type)
+		}
+	}
+
+	if err := s.repo.Save(ctx, &l); err != nil {
+		return nil, fmt.Errorf("failed to save location %s: %w", l.Code, err)
+	}
+	return &l, nil
+}
+
+// Get returns a location; it fails with a *dberr.NotFoundError when it does not exist.
+func (s *LocationService) Get(ctx context.Context, id string) (*location.Location, error) {
+	l, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location %s: %w", id, err)
+	}
+	if l == nil {
+		return nil, &dberr.NotFoundError{Entity: "location", ID: id}
+	}
+	return l, nil
+}
+
+// List returns the locations of one type, or all when locationType is empty.
+func (s *LocationService) List(ctx context.Context, locationType location.LocationType) ([]*location.Location, error) {
+	if locationType != "" && !locationType.IsValid() {
+		return nil, fmt.Errorf("invalid location type %q", locationType)
+	}
+	return s.repo.List(ctx, locationType)
+}
+
+// Deactivate keeps a location for the trades that refer to it but stops new trades from using it.
+func (s *LocationService) Deactivate(ctx context.Context, id, user string) (*location.Location, error) {
+	l, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !l.Active {
+		return l, nil
+	}
+
+	l.Active = false
+	l.AuditInfo.UpdateAuditInfo(user)
+	if err := s.repo.Update(ctx, l); err != nil {
+		return nil, fmt.Errorf("failed to deactivate location %s: %w", l.Code, err)
+	}
+	return l, nil
+}
+
+// CheckLocation fails when a location does not exist (*dberr.NotFoundError) or is inactive, so
+// new trades and nominations only refer to valid master data. It implements the
+// LocationChecker of the trade service.
+func (s *LocationService) CheckLocation(ctx context.Context, id string) error {
+	l, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !l.Active {
+		return fmt.Errorf("location %s (%s) is inactive", l.Code, l.ID)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/nholding/cso-book/internal/netting"
+)
+
+// StatementSource provides netting statements; *netting.Service implements it.
+type StatementSource interface {
+	Statements(ctx context.Context, counterpartyID, periodID string) ([]netting.Statement, error)
+}
+
+// NettingHandler serves netting statements to back office.
+//
+//	GET /netting/statements?counterparty=C42&month=2026-JAN   both filters optional
+//
+// Example response:
+//
+//	[{"counterpartyId": "C42", "periodId": "2026-JAN", "currency": "EUR",
+//	  "payable": 600000, "receivable": 496000, "netAmount": 104000, "direction": "WE_PAY",
+//	  "lines": [...]}]
+type NettingHandler struct {
+	source StatementSource
+}
+
+func NewNettingHandler(source StatementSource) *NettingHandler {
+	return &NettingHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *NettingHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /netting/statements", h.statements)
+}
+
+func (h *NettingHandler) statements(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	statements, err := h.source.Statements(r.Context(), q.Get("counterparty"), q.Get("month"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to compute netting statements: %w", err))
+		return
+	}
+	if statements == nil {
+		statements = []netting.Statement{}
+	}
+	writeJSON(w, http.StatusOK, statements)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
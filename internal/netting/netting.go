@@ -0,0 +1,193 @@
+package netting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// amountTolerance is the net amount below which a statement counts as balanced (float rounding).
+const amountTolerance = 0.005
+
+// Direction tells who pays the net amount of a statement.
+type Direction string
+
+const (
+	DirectionWePay    Direction = "WE_PAY"   // Purchases exceed sales: we pay the counterparty
+	DirectionTheyPay  Direction = "THEY_PAY" // Sales exceed purchases: the counterparty pays us
+	DirectionBalanced Direction = "BALANCED" // Purchases and sales cancel out
+)
+
+// Line is one trade's delivery in the month of a statement.
+type Line struct {
+	TradeID     string          `json:"tradeId"`
+	BreakdownID string          `json:"breakdownId"`
+	Kind        trade.TradeKind `json:"kind"`
+	VolumeMT    float64         `json:"volumeMT"`
+	PricePerMT  float64         `json:"pricePerMT"`
+	Amount      float64         `json:"amount"`
+}
+
+// Statement nets the purchases from and sales to one counterparty in one delivery month and
+// currency into a single settlement amount.
+type Statement struct {
+	CounterpartyID string    `json:"counterpartyId"`
+	PeriodID       string    `json:"periodId"` // Delivery month
+	Month          time.Time `json:"month"`    // First day of the delivery month, UTC
+	Currency       string    `json:"currency"`
+	PurchasedMT    float64   `json:"purchasedMT"`
+	SoldMT         float64   `json:"soldMT"`
+	Payable        float64   `json:"payable"`    // Purchase amounts: what we owe
+	Receivable     float64   `json:"receivable"` // Sale amounts: what we are owed
+	NetAmount      float64   `json:"netAmount"`  // Always ≥ 0; Direction says who pays
+	Direction      Direction `json:"direction"`
+	Lines          []Line    `json:"lines"` // Purchases first, then by trade ID
+}
+
+// Net pairs the purchases and sales of a book per counterparty, delivery month and currency.
+//
+// PURPOSE:
+//
+//	Instead of two invoices (we pay for what we bought, the counterparty pays for what we
+//	sold) back office settles one net amount per counterparty and month:
+//
+//	    C42, 2026-JAN, EUR
+//	        purchase T1  1000 MT @ 600  payable     600000
+//	        sale     T7   800 MT @ 620  receivable  496000
+//	        → we pay 104000 (WE_PAY)
+//
+//	Amounts are netted only within one currency; a counterparty trading in EUR and USD in the
+//	same month gets one statement per currency. Trades that are not live are ignored, as are
+//	reversal entries (they only exist for cancelled trades).
+//
+// Statements are sorted by month, counterparty and currency.
+func Net(book []trade.BookedTrade) []Statement {
+	type key struct{ counterparty, period, currency string }
+	statements := make(map[key]*Statement)
+
+	for _, bt := range book {
+		if !trade.IsLive(bt.Trade) {
+			continue
+		}
+		tb := bt.Trade.Base()
+		kind := bt.Trade.Kind()
+
+		for _, bd := range bt.Breakdowns {
+			if bd.IsReversal() {
+				continue
+			}
+			currency := strings.ToUpper(bd.Currency)
+			k := key{bt.Trade.CounterpartyID(), bd.PeriodID, currency}
+			s := statements[k]
+			if s == nil {
+				y, m, _ := bd.StartDate.UTC().Date()
+				s = &Statement{
+					CounterpartyID: k.counterparty,
+					PeriodID:       k.period,
+					Month:          time.Date(y, m, 1, 0, 0, 0, 0, time.UTC),
+					Currency:       currency,
+				}
+				statements[k] = s
+			}
+
+			if kind == trade.TradeKindSale {
+				s.SoldMT += bd.VolumeMT
+				s.Receivable += bd.TotalAmount
+			} else {
+				s.PurchasedMT += bd.VolumeMT
+				s.Payable += bd.TotalAmount
+			}
+			s.Lines = append(s.Lines, Line{
+				TradeID:     tb.ID,
+				BreakdownID: bd.ID,
+				Kind:        kind,
+				VolumeMT:    bd.VolumeMT,
+				PricePerMT:  bd.PricePerMT,
+				Amount:      bd.TotalAmount,
+			})
+		}
+	}
+
+	result := make([]Statement, 0, len(statements))
+	for _, s := range statements {
+		net := s.Receivable - s.Payable
+		switch {
+		case math.Abs(net) < amountTolerance:
+			s.Direction = DirectionBalanced
+		case net > 0:
+			s.Direction = DirectionTheyPay
+		default:
+			s.Direction = DirectionWePay
+		}
+		s.NetAmount = math.Abs(net)
+
+		sort.SliceStable(s.Lines, func(i, j int) bool {
+			if s.Lines[i].Kind != s.Lines[j].Kind {
+				return s.Lines[i].Kind == trade.TradeKindPurchase
+			}
+			return s.Lines[i].TradeID < s.Lines[j].TradeID
+		})
+		result = append(result, *s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if !a.Month.Equal(b.Month) {
+			return a.Month.Before(b.Month)
+		}
+		if a.CounterpartyID != b.CounterpartyID {
+			return a.CounterpartyID < b.CounterpartyID
+		}
+		return a.Currency < b.Currency
+	})
+	return result
+}
+
+// BookSource provides the live trades of the book with their breakdowns; *service.TradeService
+// implements it.
+type BookSource interface {
+	ListBookedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error)
+}
+
+// Service produces netting statements from the current book.
+//
+// EXAMPLE USAGE:
+//
+//	svc := netting.NewService(tradeService)
+//	statements, err := svc.Statements(ctx, "C42", "2026-JAN")
+//	// → [{CounterpartyID: "C42", PeriodID: "2026-JAN", Currency: "EUR",
+//	//     NetAmount: 104000, Direction: "WE_PAY", Lines: [...]}]
+type Service struct {
+	book BookSource
+}
+
+func NewService(book BookSource) *Service {
+	return &Service{book: book}
+}
+
+// Statements returns the netting statements of a counterparty and/or delivery month; an empty
+// argument matches all.
+func (s *Service) Statements(ctx context.Context, counterpartyID, periodID string) ([]Statement, error) {
+	book, err := s.book.ListBookedTrades(ctx, repository.TradeFilter{CounterpartyID: counterpartyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+
+	all := Net(book)
+	if periodID == "" {
+		return all, nil
+	}
+	var filtered []Statement
+	for _, st := range all {
+		if st.PeriodID == periodID {
+			filtered = append(filtered, st)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,264 @@
+package nomination
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// NominationStatus is the lifecycle state of a nomination: NOMINATED → ACTUALIZED.
+type NominationStatus string
+
+const (
+	NominationStatusNominated  NominationStatus = "NOMINATED"  // Open; may be revised until the deadline
+	NominationStatusActualized NominationStatus = "ACTUALIZED" // Written to the trade as its final volume of the month
+)
+
+// firmVolumeEpsilonMT is how far a nomination of a trade without tolerance may lie from the
+// contract volume, to absorb rounding of converted quantities.
+const firmVolumeEpsilonMT = 0.001
+
+// DeadlineRule tells when the nominations of a delivery month close, counted in business days
+// before the month starts.
+//
+// Example: nominations close on the 5th business day before the delivery month.
+//
+//	DeadlineRule{BusinessDaysBefore: 5}
+type DeadlineRule struct {
+	BusinessDaysBefore int
+}
+
+// DefaultDeadlineRule closes nominations five business days before the delivery month.
+var DefaultDeadlineRule = DeadlineRule{BusinessDaysBefore: 5}
+
+// Deadline returns the last moment (end of day, UTC) nominations for a delivery month are
+// accepted. A nil cal only skips weekends.
+//
+// Example (March 2026, 5 business days before, weekends only):
+//
+//	DefaultDeadlineRule.Deadline(cal, march) // → 2026-02-23 23:59:59.999999999
+func (r DeadlineRule) Deadline(cal *period.HolidayCalendar, month *period.Period) (time.Time, error) {
+	if r.BusinessDaysBefore < 0 {
+		return time.Time{}, fmt.Errorf("the nomination deadline must not lie after the start of the month, got %d business days before", r.BusinessDaysBefore)
+	}
+	if month.Granularity != period.MonthlyPeriod {
+		return time.Time{}, fmt.Errorf("nominations are made per month, %s is a %s period", month.ID, month.Granularity)
+	}
+	if cal == nil {
+		cal = period.NewHolidayCalendar()
+	}
+	return utils.EndOfDay(cal.AddBusinessDays(month.StartDate, -r.BusinessDaysBefore)), nil
+}
+
+// Nomination is the volume an operator nominates for one delivery month of a contract. A
+// contract has one nomination per month, revised in place until the deadline.
+//
+// Example (10,000 MT ±5% in seller's option, January nominated at 10,400 MT):
+//
+//	Nomination{TradeRef: "P-2026-0012", PeriodID: "2026-JAN", VolumeMT: 10400, ContractVolumeMT: 10000,
+//	    Deadline: 2025-12-23 23:59:59, Status: NominationStatusNominated, Revision: 2}
+type Nomination struct {
+	ID               string           `json:"id"`       // Stable ULID (primary key)
+	TradeRef         string           `json:"tradeRef"` // See trade.TradeRef: the trade number, shared by all versions of the trade
+	TradeID          string           `json:"tradeId"`  // Version of the trade the nomination was last made against
+	PeriodID         string           `json:"periodId"` // Delivery month
+	VolumeMT         float64          `json:"volumeMT"`
+	ContractVolumeMT float64          `json:"contractVolumeMT"` // Contract volume of the month when nominated
+	MinVolumeMT      float64          `json:"minVolumeMT"`      // Tolerance band the nomination was validated against
+	MaxVolumeMT      float64          `json:"maxVolumeMT"`
+	Deadline         time.Time        `json:"deadline"`
+	Status           NominationStatus `json:"status"`
+	Revision         int              `json:"revision"` // 1 for the first nomination, bumped by every revision
+	NominatedBy      string           `json:"nominatedBy"`
+	NominatedAt      time.Time        `json:"nominatedAt"`
+	ActualizedAt     *time.Time       `json:"actualizedAt,omitempty"`
+	RowVersion       int              `json:"rowVersion"` // Optimistic-lock counter, bumped by every update of the stored row
+	AuditInfo        audit.AuditInfo  `json:"audit"`
+}
+
+// Key identifies the contract month of a nomination; see NominationKey.
+func (n *Nomination) Key() string {
+	return NominationKey(n.TradeRef, n.PeriodID)
+}
+
+// NominationKey builds the key of a contract month, e.g. "P-2026-0012|2026-JAN".
+func NominationKey(tradeRef, periodID string) string {
+	return tradeRef + "|" + periodID
+}
+
+// ToleranceBand returns the volumes a delivery month of a trade may be nominated at: the
+// contract volume of the month within the trade's tolerance, or exactly the contract volume when
+// the trade is firm. It fails when the trade does not deliver in the month or when the month's
+// volume was already declared.
+//
+// Example (10,000 MT in January, +5%/-5%):
+//
+//	contract, low, high, err := nomination.ToleranceBand(t, breakdowns, "2026-JAN")
+//	// → 10000, 9500, 10500, nil
+func ToleranceBand(t trade.Trade, breakdowns []trade.TradeBreakdown, periodID string) (contract, low, high float64, err error) {
+	tb := t.Base()
+	if _, ok := tb.DeclaredVolumes[periodID]; ok {
+		return 0, 0, 0, fmt.Errorf("the volume of %s of trade %s is already declared", periodID, tb.ID)
+	}
+
+	found := false
+	for _, bd := range trade.LiveBreakdowns(breakdowns) {
+		if bd.PeriodID == periodID && !bd.IsReversal() && !bd.IsFee() {
+			contract += bd.VolumeMT
+			found = true
+		}
+	}
+	if !found {
+		return 0, 0, 0, fmt.Errorf("trade %s does not deliver in %s", tb.ID, periodID)
+	}
+
+	if tb.Tolerance == nil {
+		return contract, contract, contract, nil
+	}
+	return contract, contract * (1 - tb.Tolerance.MinusPct/100), contract * (1 + tb.Tolerance.PlusPct/100), nil
+}
+
+// Nominate
+//
+// PURPOSE:
+//
+//	Nominates the volume of one delivery month of a CONFIRMED trade, or revises the open
+//	nomination of that month (existing), and returns the nomination to store:
+//
+//	  P-2026-0012  2026-JAN  10,000 MT ±5%  → nominated 10,400 MT  (rev 1)
+//	  P-2026-0012  2026-JAN                 → revised    10,200 MT  (rev 2)
+//
+//	The volume must lie within the tolerance band of the month (see ToleranceBand) and at
+//	must not lie after the deadline. Actualized nominations are final.
+//
+// EXAMPLE USAGE:
+//
+//	n, err := nomination.Nominate(existing, t, breakdowns, "2026-JAN", 10400, deadline, time.Now(), "user@internal.local")
+func Nominate(existing *Nomination, t trade.Trade, breakdowns []trade.TradeBreakdown, periodID string, volumeMT float64, deadline, at time.Time, user string) (*Nomination, error) {
+	tb := t.Base()
+	if !trade.IsLive(t) || tb.Status != trade.TradeStatusConfirmed {
+		return nil, fmt.Errorf("trade %s is %s; volumes are nominated on live CONFIRMED trades only", tb.ID, tb.Status)
+	}
+	at = at.UTC()
+	if at.After(deadline) {
+		return nil, fmt.Errorf("nominations for %s closed at %s", periodID, deadline.Format(time.RFC3339))
+	}
+	if existing != nil && existing.Status != NominationStatusNominated {
+		return nil, fmt.Errorf("the nomination of %s for trade %s is %s and can no longer be revised", periodID, tb.ID, existing.Status)
+	}
+
+	contract, low, high, err := ToleranceBand(t, breakdowns, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if tb.Tolerance == nil {
+		if math.Abs(volumeMT-contract) > firmVolumeEpsilonMT {
+			return nil, fmt.Errorf("trade %s has no volume tolerance; %s must be nominated at its contract volume of %v MT, got %v MT", tb.ID, periodID, contract, volumeMT)
+		}
+	} else if volumeMT < low || volumeMT > high {
+		return nil, fmt.Errorf("nominated volume %v MT of %s lies outside the tolerance of trade %s (%v–%v MT)", volumeMT, periodID, tb.ID, low, high)
+	}
+
+	n := existing
+	if n == nil {
+		n = &Nomination{
+			ID:        utils.GenerateStableID(),
+			TradeRef:  trade.TradeRef(tb),
+			PeriodID:  periodID,
+			Status:    NominationStatusNominated,
+			AuditInfo: *audit.NewAuditInfo(user),
+		}
+	} else {
+		n.AuditInfo.UpdateAuditInfo(user)
+	}
+	n.TradeID = tb.ID
+	n.VolumeMT = volumeMT
+	n.ContractVolumeMT = contract
+	n.MinVolumeMT = low
+	n.MaxVolumeMT = high
+	n.Deadline = deadline
+	n.Revision++
+	n.NominatedBy = user
+	n.NominatedAt = at
+	return n, nil
+}
+
+// MarkActualized records that the nominated volume became the final volume of the month.
+func (n *Nomination) MarkActualized(at time.Time, user string) error {
+	if n.Status != NominationStatusNominated {
+		return fmt.Errorf("the nomination of %s for %s is %s; only NOMINATED volumes are actualized", n.PeriodID, n.TradeRef, n.Status)
+	}
+	at = at.UTC()
+	n.Status = NominationStatusActualized
+	n.ActualizedAt = &at
+	n.AuditInfo.UpdateAuditInfo(user)
+	return nil
+}
+
+// ApplyNominations
+//
+// PURPOSE:
+//
+//	Returns a copy of the book in which the open nominations replace the contract volume of
+//	their months, so views built from breakdowns (e.g. the position ladder) show what operators
+//	expect to move rather than the contract:
+//
+//	  P-2026-0012  2026-JAN  10,000 MT  → 10,400 MT (nominated)
+//
+//	Only the volumes of the month's non-fee breakdowns change, in proportion when a month has
+//	several. Actualized nominations are already part of the breakdowns (the trade's declared
+//	volumes) and are left alone, as are months the trade does not deliver in. The book passed
+//	in is not modified.
+//
+// EXAMPLE USAGE:
+//
+//	nominated := nomination.ApplyNominations(book, nominations)
+func ApplyNominations(book []trade.BookedTrade, nominations []*Nomination) []trade.BookedTrade {
+	open := make(map[string]float64)
+	for _, n := range nominations {
+		if n.Status == NominationStatusNominated {
+			open[n.Key()] = n.VolumeMT
+		}
+	}
+	if len(open) == 0 {
+		return book
+	}
+
+	result := make([]trade.BookedTrade, len(book))
+	for i, bt := range book {
+		result[i] = bt
+		tb := bt.Trade.Base()
+		ref := trade.TradeRef(tb)
+
+		contract := make(map[string]float64)
+		for _, bd := range trade.LiveBreakdowns(bt.Breakdowns) {
+			if _, ok := open[NominationKey(ref, bd.PeriodID)]; ok && !bd.IsReversal() && !bd.IsFee() {
+				contract[bd.PeriodID] += bd.VolumeMT
+			}
+		}
+		if len(contract) == 0 {
+			continue
+		}
+
+		breakdowns := make([]trade.TradeBreakdown, len(bt.Breakdowns))
+		copy(breakdowns, bt.Breakdowns)
+		for j := range breakdowns {
+			bd := &breakdowns[j]
+			total := contract[bd.PeriodID]
+			if total == 0 || bd.IsReversal() || bd.IsFee() || bd.IsCancelled() {
+				continue
+			}
+			if _, declared := tb.DeclaredVolumes[bd.PeriodID]; declared {
+				continue
+			}
+			bd.VolumeMT *= open[NominationKey(ref, bd.PeriodID)] / total
+		}
+		result[i].Breakdowns = breakdowns
+	}
+	return result
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	nomination "github.com/nholding/cso-book/internal/nomination/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// NominationRepository defines the interface for storing and retrieving Nominations from a persistence layer
+type NominationRepository interface {
+	// SaveNomination inserts a new nomination; it fails with *dberr.DuplicateError when the
+	// contract month is already nominated
+	SaveNomination(ctx context.Context, n *nomination.Nomination) error
+
+	// UpdateNomination writes a revised or actualized nomination, checked against its RowVersion
+	// (optimistic concurrency; a lost race returns *dberr.ConflictError)
+	UpdateNomination(ctx context.Context, n *nomination.Nomination) error
+
+	// FindByKey retrieves the nomination of a contract month; returns nil, nil when there is none
+	FindByKey(ctx context.Context, tradeRef, periodID string) (*nomination.Nomination, error)
+
+	// List retrieves the nominations matching the filter, by delivery month and trade reference
+	List(ctx context.Context, filter NominationFilter) ([]*nomination.Nomination, error)
+}
+
+// NominationFilter narrows List. Zero fields match everything.
+//
+// Example (open nominations of January):
+//
+//	NominationFilter{PeriodID: "2026-JAN", Status: nomination.NominationStatusNominated}
+type NominationFilter struct {
+	TradeRef string
+	PeriodID string
+	Status   nomination.NominationStatus
+}
+
+// Compile-time check that RdsNominationRepository satisfies NominationRepository
+var _ NominationRepository = (*RdsNominationRepository)(nil)
+
+// RdsNominationRepository keeps nominations in the nominations table, one row per contract month.
+type RdsNominationRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsNominationRepository(cfg *awsclient.Config) (*RdsNominationRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsNominationRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveNomination inserts the first nomination of a contract month. (trade_ref, period_id) is
+// unique, so two operators nominating the same month at once cannot both win.
+func (r *RdsNominationRepository) SaveNomination(ctx context.Context, n *nomination.Nomination) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO nominations (
+			id, trade_ref, trade_id, period_id, volume_mt, contract_volume_mt, min_volume_mt, max_volume_mt,
+			deadline, status, revision, nominated_by, nominated_at, actualized_at,
+			row_version, audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,1,$15,$16,$17,$18)
+	`,
+		n.ID,
+		n.TradeRef,
+		n.TradeID,
+		n.PeriodID,
+		n.VolumeMT,
+		n.ContractVolumeMT,
+		n.MinVolumeMT,
+		n.MaxVolumeMT,
+		n.Deadline,
+		string(n.Status),
+		n.Revision,
+		n.NominatedBy,
+		n.NominatedAt,
+		n.ActualizedAt,
+		n.AuditInfo.CreatedBy,
+		n.AuditInfo.CreatedAt,
+		n.AuditInfo.UpdatedBy,
+		n.AuditInfo.UpdatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "nomination", ID: n.ID, Key: "month " + n.Key(), Err: err}
+		}
+		return fmt.Errorf("failed to insert nomination %s: %w", n.Key(), err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityNomination, n.ID, audit.ActionCreate, n.AuditInfo.CreatedBy,
+		map[string]any{"tradeRef": n.TradeRef, "periodId": n.PeriodID, "volumeMT": n.VolumeMT})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit nomination transaction: %w", err)
+	}
+
+	n.RowVersion = 1
+	return nil
+}
+
+// UpdateNomination writes the volume, status and revision of a nomination. On success
+// n.RowVersion is incremented to match the DB.
+func (r *RdsNominationRepository) UpdateNomination(ctx context.Context, n *nomination.Nomination) error {
+	expected := n.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE nominations
+		SET trade_id=$1, volume_mt=$2, contract_volume_mt=$3, min_volume_mt=$4, max_volume_mt=$5, deadline=$6,
+		    status=$7, revision=$8, nominated_by=$9, nominated_at=$10, actualized_at=$11,
+		    audit_updated_by=$12, audit_updated_at=$13, row_version=row_version+1
+		WHERE id=$14 AND row_version=$15
+	`,
+		n.TradeID,
+		n.VolumeMT,
+		n.ContractVolumeMT,
+		n.MinVolumeMT,
+		n.MaxVolumeMT,
+		n.Deadline,
+		string(n.Status),
+		n.Revision,
+		n.NominatedBy,
+		n.NominatedAt,
+		n.ActualizedAt,
+		n.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		n.ID,
+		expected,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update nomination %s: %w", n.Key(), err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM nominations WHERE id=$1`, n.ID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "nomination", ID: n.ID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of nomination %s: %w", n.ID, err)
+		}
+		return &dberr.ConflictError{Entity: "nomination", ID: n.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	action := audit.ActionUpdate
+	if n.Status == nomination.NominationStatusActualized {
+		action = audit.ActionStatusChange
+	}
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityNomination, n.ID, action, n.AuditInfo.LastActor(),
+		map[string]any{"tradeRef": n.TradeRef, "periodId": n.PeriodID, "volumeMT": n.VolumeMT, "revision": n.Revision, "status": string(n.Status)})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit nomination transaction: %w", err)
+	}
+
+	n.RowVersion = expected + 1
+	return nil
+}
+
+// FindByKey retrieves the nomination of one contract month. Returns nil, nil when the month is
+// not nominated.
+func (r *RdsNominationRepository) FindByKey(ctx context.Context, tradeRef, periodID string) (*nomination.Nomination, error) {
+	nominations, err := r.query(ctx, `SELECT `+nominationColumns+` FROM nominations WHERE trade_ref=$1 AND period_id=$2`, tradeRef, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if len(nominations) == 0 {
+		return nil, nil // Not found
+	}
+	return nominations[0], nil
+}
+
+// List retrieves the nominations matching the filter, ordered by delivery month and trade
+// reference.
+//
+// Example:
+//
+//	nominations, err := repo.List(ctx, NominationFilter{PeriodID: "2026-JAN"})
+func (r *RdsNominationRepository) List(ctx context.Context, filter NominationFilter) ([]*nomination.Nomination, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TradeRef != "" {
+		add("trade_ref=$%d", filter.TradeRef)
+	}
+	if filter.PeriodID != "" {
+		add("period_id=$%d", filter.PeriodID)
+	}
+	if filter.Status != "" {
+		add("status=$%d", string(filter.Status))
+	}
+
+	query := `SELECT ` + nominationColumns + ` FROM nominations`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY period_id, trade_ref`
+	return r.query(ctx, query, args...)
+}
+
+func (r *RdsNominationRepository) query(ctx context.Context, query string, args ...any) ([]*nomination.Nomination, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nominations: %w", err)
+	}
+	defer rows.Close()
+
+	var nominations []*nomination.Nomination
+	for rows.Next() {
+		n, err := scanNomination(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan nomination: %w", err)
+		}
+		nominations = append(nominations, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate nominations: %w", err)
+	}
+	return nominations, nil
+}
+
+// nominationColumns is the column list scanned by scanNomination.
+const nominationColumns = `id, trade_ref, trade_id, period_id, volume_mt, contract_volume_mt, min_volume_mt, max_volume_mt,
+	deadline, status, revision, nominated_by, nominated_at, actualized_at, row_version,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNomination(row rowScanner) (*nomination.Nomination, error) {
+	var (
+		n      nomination.Nomination
+		status string
+	)
+	if err := row.Scan(
+		&n.ID,
+		&n.TradeRef,
+		&n.TradeID,
+		&n.PeriodID,
+		&n.VolumeMT,
+		&n.ContractVolumeMT,
+		&n.MinVolumeMT,
+		&n.MaxVolumeMT,
+		&n.Deadline,
+		&status,
+		&n.Revision,
+		&n.NominatedBy,
+		&n.NominatedAt,
+		&n.ActualizedAt,
+		&n.RowVersion,
+		&n.AuditInfo.CreatedBy,
+		&n.AuditInfo.CreatedAt,
+		&n.AuditInfo.UpdatedBy,
+		&n.AuditInfo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	n.Status = nomination.NominationStatus(status)
+
+	return &n, nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	nomination "github.com/nholding/cso-book/internal/nomination/domain"
+	"github.com/nholding/cso-book/internal/nomination/repository"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/position"
+	"github.com/nholding/cso-book/internal/trade"
+	traderepo "github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// TradeSource provides the trades nominations are made against and writes actualized volumes
+// back to them; *service.TradeService implements it.
+type TradeSource interface {
+	GetBookedTrade(ctx context.Context, id string) (*trade.BookedTrade, error)
+	ListBookedTrades(ctx context.Context, filter traderepo.TradeFilter) ([]trade.BookedTrade, error)
+	DeclareVolume(ctx context.Context, tradeID, periodID string, volumeMT float64, booking trade.BookingRequest) ([]trade.TradeBreakdown, error)
+}
+
+// Compile-time check that NominationService can feed nominated volumes into the position views
+var _ position.NominationSource = (*NominationService)(nil)
+
+type NominationService struct {
+	repo     repository.NominationRepository
+	trades   TradeSource
+	store    *period.PeriodStore
+	holidays *period.HolidayCalendar // nil: deadlines only skip weekends
+	rule     nomination.DeadlineRule
+	tx       *txn.Manager // nil: every repository call runs in its own transaction
+	now      func() time.Time
+}
+
+func NewNominationService(repo repository.NominationRepository, trades TradeSource, store *period.PeriodStore, tx *txn.Manager) *NominationService {
+	return &NominationService{
+		repo:   repo,
+		trades: trades,
+		store:  store,
+		rule:   nomination.DefaultDeadlineRule,
+		tx:     tx,
+		now:    time.Now,
+	}
+}
+
+// SetHolidays makes nomination deadlines skip the holidays of cal as well as weekends.
+func (s *NominationService) SetHolidays(cal *period.HolidayCalendar) {
+	s.holidays = cal
+}
+
+// SetDeadlineRule changes when nominations close; nomination.DefaultDeadlineRule applies
+// until it is called.
+func (s *NominationService) SetDeadlineRule(rule nomination.DeadlineRule) {
+	s.rule = rule
+}
+
+// Deadline returns the last moment nominations for a delivery month are accepted.
+//
+// Example:
+//
+//	deadline, err := nominationService.Deadline("2026-MAR") // → 2026-02-23 23:59:59 (5 business days before)
+func (s *NominationService) Deadline(periodID string) (time.Time, error) {
+	month := s.store.FindByID(periodID)
+	if month == nil {
+		return time.Time{}, &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
+	return s.rule.Deadline(s.holidays, month)
+}
+
+// Nominate
+//
+// PURPOSE:
+//
+//	Nominates the volume of one delivery month of a CONFIRMED trade before the deadline of
+//	the month, or revises the month's open nomination (see nomination.Nominate). The volume is
+//	validated against the trade's tolerance; firm trades are nominated at their contract
+//	volume. Nominations are kept per trade number, so a nomination made before an amendment
+//	is revised, not duplicated, after it.
+//
+// EXAMPLE USAGE:
+//
+//	n, err := nominationService.Nominate(ctx, "01J...", "2026-JAN", 10400, "user@internal.local")
+func (s *NominationService) Nominate(ctx context.Context, tradeID, periodID string, volumeMT float64, user string) (*nomination.Nomination, error) {
+	if user == "" {
+		return nil, errors.New("the nominating user is required")
+	}
+
+	deadline, err := s.Deadline(periodID)
+	if err != nil {
+		return nil, err
+	}
+	bt, err := s.trades.GetBookedTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var n *nomination.Nomination
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.FindByKey(ctx, trade.TradeRef(bt.Trade.Base()), periodID)
+		if err != nil {
+			return fmt.Errorf("failed to load the nomination of %s for trade %s: %w", periodID, tradeID, err)
+		}
+		n, err = nomination.Nominate(existing, bt.Trade, bt.Breakdowns, periodID, volumeMT, deadline, s.now(), user)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			err = s.repo.SaveNomination(ctx, n)
+		} else {
+			err = s.repo.UpdateNomination(ctx, n)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save nomination %s: %w", n.Key(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// List returns the nominations matching the filter, by delivery month and trade reference.
+func (s *NominationService) List(ctx context.Context, filter repository.NominationFilter) ([]*nomination.Nomination, error) {
+	nominations, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nominations: %w", err)
+	}
+	return nominations, nil
+}
+
+// Actualize
+//
+// PURPOSE:
+//
+//	Makes the open nominations of a delivery month final once its deadline has passed: the
+//	nominated volume is declared on the current version of each trade with a tolerance (see
+//	TradeService.DeclareVolume), which recalculates the month's amounts, costs and fees, and
+//	the nomination becomes ACTUALIZED. Firm trades already deliver their nominated volume and
+//	are only marked.
+//
+//	Each nomination is actualized in its own transaction; one that fails (e.g. its trade was
+//	cancelled or the month is HARD_CLOSED) does not hold back the others and is reported in
+//	the joined error.
+//
+// EXAMPLE USAGE:
+//
+//	actualized, err := nominationService.Actualize(ctx, "2026-JAN", "user@internal.local")
+func (s *NominationService) Actualize(ctx context.Context, periodID, user string) ([]*nomination.Nomination, error) {
+	if user == "" {
+		return nil, errors.New("the actualizing user is required")
+	}
+
+	deadline, err := s.Deadline(periodID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !now.After(deadline) {
+		return nil, fmt.Errorf("nominations for %s are open until %s", periodID, deadline.Format(time.RFC3339))
+	}
+
+	open, err := s.repo.List(ctx, repository.NominationFilter{PeriodID: periodID, Status: nomination.NominationStatusNominated})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the nominations of %s: %w", periodID, err)
+	}
+	if len(open) == 0 {
+		return nil, nil
+	}
+	book, err := s.trades.ListBookedTrades(ctx, traderepo.TradeFilter{PeriodID: periodID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+	live := make(map[string]trade.Trade)
+	for _, bt := range book {
+		if trade.IsLive(bt.Trade) {
+			live[trade.TradeRef(bt.Trade.Base())] = bt.Trade
+		}
+	}
+
+	var actualized []*nomination.Nomination
+	var errs []error
+	for _, n := range open {
+		t, ok := live[n.TradeRef]
+		if !ok {
+			errs = append(errs, fmt.Errorf("nomination %s: trade %s is no longer live", n.Key(), n.TradeRef))
+			continue
+		}
+		err := s.withinTx(ctx, func(ctx context.Context) error {
+			return s.actualize(ctx, n, t, now, user)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("nomination %s: %w", n.Key(), err))
+			continue
+		}
+		actualized = append(actualized, n)
+	}
+	return actualized, errors.Join(errs...)
+}
+
+// actualize declares the nominated volume on t when it has a tolerance and marks n actualized.
+func (s *NominationService) actualize(ctx context.Context, n *nomination.Nomination, t trade.Trade, at time.Time, user string) error {
+	tb := t.Base()
+	if declared, ok := tb.DeclaredVolumes[n.PeriodID]; ok {
+		if math.Abs(declared-n.VolumeMT) > 0.001 {
+			return fmt.Errorf("trade %s already declared %v MT for %s", tb.ID, declared, n.PeriodID)
+		}
+	} else if tb.Tolerance != nil {
+		if _, err := s.trades.DeclareVolume(ctx, tb.ID, n.PeriodID, n.VolumeMT, trade.BookingRequest{User: user, At: at}); err != nil {
+			return err
+		}
+	}
+
+	if err := n.MarkActualized(at, user); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateNomination(ctx, n); err != nil {
+		return fmt.Errorf("failed to update nomination %s: %w", n.Key(), err)
+	}
+	return nil
+}
+
+// ApplyNominations replaces the contract volumes of the book by the open nominations (see
+// nomination.ApplyNominations), for position.Engine.SetNominations.
+func (s *NominationService) ApplyNominations(ctx context.Context, book []trade.BookedTrade) ([]trade.BookedTrade, error) {
+	open, err := s.repo.List(ctx, repository.NominationFilter{Status: nomination.NominationStatusNominated})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load open nominations: %w", err)
+	}
+	return nomination.ApplyNominations(book, open), nil
+}
+
+func (s *NominationService) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
 // BreakDownTradePeriodRange
 // The core function of BreakDownTradePeriodRange is to take a PeriodRange
 // (whether it's a single period, a multi-period range, or a full calendar)
@@ -35,7 +40,7 @@ func (ps *PeriodStore) BreakDownTradePeriodRange(pr PeriodRange) []string {
 	// Prepare a slice to collect the month IDs that fall fully within the period range
 	var monthIDs []string
 
-	for _, m := range ps.Months {
+	for _, m := range ps.Months() {
 		// A month is included IFF it is fully contained in the range:
 		//   month.Start >= range.Start AND month.End <= range.End
 		if !m.StartDate.Before(startPeriod.StartDate) && !m.EndDate.After(endPeriod.EndDate) {
@@ -45,3 +50,196 @@ func (ps *PeriodStore) BreakDownTradePeriodRange(pr PeriodRange) []string {
 
 	return monthIDs
 }
+
+// BreakDownDateRange
+// Returns the IDs of all months that overlap the inclusive date range [start, end], in
+// chronological order. Unlike BreakDownTradePeriodRange, months only need to overlap the
+// range, not be fully contained in it, so custom-dated (broken) delivery windows map onto
+// every month they touch.
+//
+// Example:
+//
+//	start := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+//	end := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+//	months := ps.BreakDownDateRange(start, end)
+//
+// Output: [ "2026-MAR", "2026-APR", "2026-MAY" ]
+func (ps *PeriodStore) BreakDownDateRange(start, end time.Time) []string {
+	if end.Before(start) {
+		return nil
+	}
+
+	var monthIDs []string
+	for _, m := range ps.Months() {
+		// A month overlaps IFF month.Start <= range.End AND month.End >= range.Start
+		if !m.StartDate.After(end) && !m.EndDate.Before(start) {
+			monthIDs = append(monthIDs, m.ID)
+		}
+	}
+
+	return monthIDs
+}
+
+// Breakdown-only granularities. Weeks and days are not stored as periods; they are derived
+// from the calendar when a caller breaks a range down to that resolution.
+const (
+	WeeklyPeriod PeriodGranularity = "WEEKLY" // ISO-8601 weeks, IDs like "2026-W05"
+	DailyPeriod  PeriodGranularity = "DAILY"  // Days, IDs like "2026-01-15"
+)
+
+// BreakDownPeriodRangeTo
+// Generalizes BreakDownTradePeriodRange to a caller-chosen target granularity, so reporting
+// can aggregate at the resolution it needs.
+//
+//   - MONTHLY:   identical to BreakDownTradePeriodRange
+//   - QUARTERLY: stored quarters fully inside the range, of the start period's calendar
+//     (FY ranges give FY quarters, CAL/month ranges give CAL quarters)
+//   - CALENDAR:  stored years fully inside the range, of the start period's calendar
+//   - WEEKLY:    ISO weeks OVERLAPPING the range (weeks straddle month boundaries, so
+//     requiring full containment would silently drop the edge weeks)
+//   - DAILY:     every day in the range
+//
+// Example:
+//
+//	pr := PeriodRange{StartPeriodID: "2026-JAN", EndPeriodID: "2026-JUN"}
+//	quarters, _ := ps.BreakDownPeriodRangeTo(pr, QuarterlyPeriod) // → [2026-Q1, 2026-Q2]
+//	weeks, _ := ps.BreakDownPeriodRangeTo(pr, WeeklyPeriod)       // → [2026-W01, ..., 2026-W27]
+//	days, _ := ps.BreakDownPeriodRangeTo(pr, DailyPeriod)         // → [2026-01-01, ..., 2026-06-30]
+func (ps *PeriodStore) BreakDownPeriodRangeTo(pr PeriodRange, target PeriodGranularity) ([]string, error) {
+	startPeriod := ps.FindByID(pr.StartPeriodID)
+	endPeriod := ps.FindByID(pr.EndPeriodID)
+	if startPeriod == nil || endPeriod == nil {
+		return nil, fmt.Errorf("period range %s → %s references unknown periods", pr.StartPeriodID, pr.EndPeriodID)
+	}
+	if startPeriod.StartDate.After(endPeriod.EndDate) {
+		return nil, fmt.Errorf("period range %s → %s is reversed", pr.StartPeriodID, pr.EndPeriodID)
+	}
+
+	start, end := startPeriod.StartDate, endPeriod.EndDate
+
+	switch target {
+	case MonthlyPeriod:
+		return ps.BreakDownTradePeriodRange(pr), nil
+
+	case QuarterlyPeriod, CalendarYearPeriod:
+		// Months always belong to the Gregorian calendar; fiscal ranges start at a fiscal period
+		calendar := startPeriod.Calendar
+		var candidates []*Period
+		if target == QuarterlyPeriod {
+			candidates = ps.Quarters()
+		} else {
+			candidates = ps.Years()
+		}
+
+		var ids []string
+		for _, p := range candidates {
+			if p.Calendar == calendar && !p.StartDate.Before(start) && !p.EndDate.After(end) {
+				ids = append(ids, p.ID)
+			}
+		}
+		return ids, nil
+
+	case WeeklyPeriod:
+		// Step back to the Monday of the first week, then walk week by week
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+
+		var ids []string
+		for !day.After(end) {
+			year, week := day.ISOWeek()
+			ids = append(ids, fmt.Sprintf("%d-W%02d", year, week))
+			day = day.AddDate(0, 0, 7)
+		}
+		return ids, nil
+
+	case DailyPeriod:
+		var ids []string
+		for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC); !day.After(end); day = day.AddDate(0, 0, 1) {
+			ids = append(ids, day.Format("2006-01-02"))
+		}
+		return ids, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported breakdown granularity %q", target)
+	}
+}
+
+// AggregateToParent
+// The inverse of BreakDownTradePeriodRange: maps a set of month IDs back up to the minimal
+// set of Gregorian periods that covers exactly those months, for displaying trades compactly.
+//
+//   - a year replaces its months when all of them are in the set
+//   - otherwise a quarter replaces its months when all of them are in the set
+//   - remaining months are returned as-is
+//
+// Coverage is decided by date against the months in the store, so a quarter is only rolled up
+// when every stored month inside it is present. The input order does not matter and duplicates
+// are ignored; the result is in chronological order. Fiscal periods are never returned, since
+// months always belong to the Gregorian calendar.
+//
+// Example:
+//
+//	ids := []string{"2026-JAN", "2026-FEB", "2026-MAR", "2026-APR", "2027-JAN", ..., "2027-DEC"}
+//	periods, _ := ps.AggregateToParent(ids)
+//
+// Output: [ "2026-Q1", "2026-APR", "2027" ]
+func (ps *PeriodStore) AggregateToParent(monthIDs []string) ([]string, error) {
+	selected := make(map[string]bool, len(monthIDs))
+	for _, id := range monthIDs {
+		p := ps.FindByID(id)
+		if p == nil {
+			return nil, fmt.Errorf("unknown period %s", id)
+		}
+		if p.Granularity != MonthlyPeriod {
+			return nil, fmt.Errorf("period %s is %s, expected %s", id, p.Granularity, MonthlyPeriod)
+		}
+		selected[id] = true
+	}
+
+	months := ps.Months()
+	covered := make(map[string]bool, len(selected))
+
+	// covers reports whether every stored month within p is selected and not yet rolled up
+	covers := func(p *Period) bool {
+		var inside []string
+		for _, m := range months {
+			if !m.StartDate.Before(p.StartDate) && !m.EndDate.After(p.EndDate) {
+				if !selected[m.ID] || covered[m.ID] {
+					return false
+				}
+				inside = append(inside, m.ID)
+			}
+		}
+		return len(inside) > 0
+	}
+	markCovered := func(p *Period) {
+		for _, m := range months {
+			if !m.StartDate.Before(p.StartDate) && !m.EndDate.After(p.EndDate) {
+				covered[m.ID] = true
+			}
+		}
+	}
+
+	// Largest periods first, so a full year wins over its four quarters
+	var result []*Period
+	for _, candidates := range [][]*Period{ps.Years(), ps.Quarters()} {
+		for _, p := range candidates {
+			if p.Calendar == CalendarGregorian && covers(p) {
+				markCovered(p)
+				result = append(result, p)
+			}
+		}
+	}
+	for _, m := range months {
+		if selected[m.ID] && !covered[m.ID] {
+			result = append(result, m)
+		}
+	}
+
+	result = sortedByStart(result)
+	ids := make([]string, len(result))
+	for i, p := range result {
+		ids[i] = p.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,567 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validation rule identifiers. Every ValidationIssue carries exactly one of these.
+const (
+	RuleHierarchy          = "HIERARCHY"
+	RuleOverlap            = "OVERLAP"
+	RuleGap                = "GAP"
+	RuleFiscalCoverage     = "FISCAL_COVERAGE"
+	RuleWeekFiscalCoverage = "WEEK_FISCAL_COVERAGE"
+	RuleDataQuality        = "DATA_QUALITY"
+)
+
+// ValidationIssue is a single structured finding produced by the CalendarValidator.
+// It implements error, so it can be returned wherever a plain error is expected.
+//
+// Example:
+//
+//	ValidationIssue{
+//	    Rule:      RuleHierarchy,
+//	    PeriodIDs: []string{"FY2026-Q1", "2026"},
+//	    Message:   "period FY2026-Q1 (FY) has parent 2026 (CAL) with different calendar type",
+//	}
+type ValidationIssue struct {
+	Rule      string   `json:"rule"`      // One of the Rule* constants
+	Severity  Severity `json:"severity"`  // ERROR blocks startup; WARNING is reported only
+	PeriodIDs []string `json:"periodIds"` // Offending period(s); the first ID is the primary offender
+	Message   string   `json:"message"`   // Human-readable description
+	Err       error    `json:"-"`         // Typed cause, when the rule has one (e.g. *ParentCycleError); nil otherwise
+}
+
+func (i ValidationIssue) Error() string {
+	return i.Message
+}
+
+// Unwrap exposes the typed cause, so callers can use errors.As on an issue.
+func (i ValidationIssue) Unwrap() error {
+	return i.Err
+}
+
+// DefaultMaxHierarchyDepth is the longest allowed parent chain: Year → Quarter → Month.
+const DefaultMaxHierarchyDepth = 3
+
+// ParentCycleError reports a parent chain that loops back on itself, e.g. A → B → A.
+// Chain lists the periods of the cycle, starting at the smallest ID, and repeats the first
+// ID at the end to show the loop.
+type ParentCycleError struct {
+	Chain []string
+}
+
+func (e *ParentCycleError) Error() string {
+	return fmt.Sprintf("parent cycle detected: %s", strings.Join(e.Chain, " → "))
+}
+
+// HierarchyDepthError reports a parent chain longer than the allowed maximum.
+// Chain runs from the offending period up to its root.
+type HierarchyDepthError struct {
+	PeriodID string
+	Depth    int
+	MaxDepth int
+	Chain    []string
+}
+
+func (e *HierarchyDepthError) Error() string {
+	return fmt.Sprintf("period %s has hierarchy depth %d (max %d): %s",
+		e.PeriodID, e.Depth, e.MaxDepth, strings.Join(e.Chain, " → "))
+}
+
+func newIssue(rule string, periodIDs []string, format string, args ...any) ValidationIssue {
+	return ValidationIssue{
+		Rule:      rule,
+		Severity:  ruleSeverity(rule),
+		PeriodIDs: periodIDs,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+// CalendarValidator
+//
+// Purpose:
+//
+//	Validates ANY set of periods — the in-memory store at startup, a proposed import,
+//	or a custom calendar before it is persisted — without needing a PeriodService,
+//	a PeriodStore or a database.
+//
+//	Every rule is a pure function of the given periods and returns structured
+//	ValidationIssues. Parent references are resolved WITHIN the given set, so a
+//	proposed set must be self-contained (include its parents).
+//
+// Example usage:
+//
+//	proposed := GeneratePeriods(2030, 2031)
+//	report := NewCalendarValidator().Validate(proposed)
+//	if report.HasErrors() {
+//	    // reject the import
+//	}
+type CalendarValidator struct {
+	// MaxDepth is the longest allowed parent chain, counting the period itself
+	MaxDepth int
+}
+
+func NewCalendarValidator() *CalendarValidator {
+	return &CalendarValidator{MaxDepth: DefaultMaxHierarchyDepth}
+}
+
+// Validate runs every rule and returns all issues in one ValidationReport.
+func (v *CalendarValidator) Validate(periods []*Period) ValidationReport {
+	return NewValidationReport(
+		v.ValidateDataQuality(periods),
+		v.ValidateHierarchy(periods),
+		v.ValidateOverlaps(periods),
+		v.ValidateGaps(periods),
+		v.ValidateFiscalCoverage(periods),
+		v.ValidateWeekFiscalCoverage(periods),
+	)
+}
+
+// ValidateDataQuality runs Period.Validate on every period (non-empty ID and name,
+// known granularity, start before end). Issues are ordered by period ID.
+func (v *CalendarValidator) ValidateDataQuality(periods []*Period) []ValidationIssue {
+	periods = live(periods)
+	var issues []ValidationIssue
+	for _, p := range sortedByID(periods) {
+		if err := p.Validate(); err != nil {
+			issues = append(issues, newIssue(RuleDataQuality, []string{p.ID}, "period %s: %v", p.ID, err))
+		}
+	}
+	return issues
+}
+
+// ValidateHierarchy
+//
+// Enforces the structural rules of the CAL and FY hierarchies (see PeriodService.ValidateHierarchy
+// for the full description of the overlay model):
+//
+//   - years are roots
+//   - months must be Gregorian; a declared month parent must exist, be Gregorian,
+//     be of larger granularity and contain the month
+//   - quarters must have an existing parent of the SAME calendar, of larger granularity,
+//     which is not the quarter itself and which contains it by date
+//   - parent chains must not loop (*ParentCycleError) and must not be deeper than
+//     MaxDepth (*HierarchyDepthError); both are available through errors.As
+func (v *CalendarValidator) ValidateHierarchy(periods []*Period) []ValidationIssue {
+	periods = live(periods)
+	byID := indexByID(periods)
+	var issues []ValidationIssue
+
+	// Validation is performed by granularity order (for readability of the output only)
+	for _, p := range sortedByGranularity(periods) {
+
+		// YEAR periods (CAL or FY) are ROOTS
+		if p.Granularity == CalendarYearPeriod {
+			continue
+		}
+
+		// MONTHS are atomic delivery units: they MAY belong to a CAL hierarchy and
+		// MUST NOT be required to belong to a FY hierarchy.
+		if p.Granularity == MonthlyPeriod {
+			if p.Calendar != CalendarGregorian {
+				issues = append(issues, newIssue(RuleHierarchy, []string{p.ID},
+					"month %s has invalid calendar %s (months must be Gregorian)", p.ID, p.Calendar))
+			}
+
+			if p.ParentPeriodID == nil {
+				continue
+			}
+
+			parent, exists := byID[*p.ParentPeriodID]
+			if !exists {
+				issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, *p.ParentPeriodID},
+					"month %s references missing parent %s", p.ID, *p.ParentPeriodID))
+				continue
+			}
+
+			if parent.Calendar != CalendarGregorian {
+				issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+					"month %s has non-Gregorian parent %s", p.ID, parent.ID))
+			}
+
+			if parent.GranularityRank() <= p.GranularityRank() {
+				issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+					"month %s has invalid parent granularity %s", p.ID, parent.Granularity))
+			}
+
+			if parent.StartDate.After(p.StartDate) || parent.EndDate.Before(p.EndDate) {
+				issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+					"month %s is not fully contained in parent %s", p.ID, parent.ID))
+			}
+			continue
+		}
+
+		// ALL NON-YEAR, NON-MONTH PERIODS (i.e. QUARTERS)
+
+		// Rule 1: Parent must exist
+		if p.ParentPeriodID == nil {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID},
+				"period %s (%s) has no parent but is not a year", p.ID, p.Granularity))
+			continue
+		}
+
+		parent, exists := byID[*p.ParentPeriodID]
+		if !exists {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, *p.ParentPeriodID},
+				"child %s references missing parent %s", p.ID, *p.ParentPeriodID))
+			continue
+		}
+
+		// Rule 2: Calendar isolation (CRITICAL)
+		if parent.Calendar != p.Calendar {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+				"period %s (%s) has parent %s (%s) with different calendar type", p.ID, p.Calendar, parent.ID, parent.Calendar))
+			continue
+		}
+
+		// Rule 3: No self-reference
+		if parent.ID == p.ID {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID},
+				"period %s cannot reference itself as a parent", p.ID))
+		}
+
+		// Rule 4: Granularity ordering
+		if parent.GranularityRank() <= p.GranularityRank() {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+				"period %s (%s) has parent %s (%s) which is not a larger granularity", p.ID, p.Granularity, parent.ID, parent.Granularity))
+		}
+
+		// Rule 5: Date containment
+		if parent.StartDate.After(p.StartDate) {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+				"child %s starts before parent %s", p.ID, parent.ID))
+		}
+		if parent.EndDate.Before(p.EndDate) {
+			issues = append(issues, newIssue(RuleHierarchy, []string{p.ID, parent.ID},
+				"child %s ends after parent %s", p.ID, parent.ID))
+		}
+	}
+
+	return append(issues, v.validateParentChains(periods, byID)...)
+}
+
+// validateParentChains walks every parent chain to its root. Per-edge checks cannot see
+// cycles (A → B → A passes each edge check when A and B look alike) or chains that are
+// too deep, so they are detected here. Every cycle is reported once, however many periods
+// lead into it. Chains ending at a missing parent stop there; that is reported per edge.
+func (v *CalendarValidator) validateParentChains(periods []*Period, byID map[string]*Period) []ValidationIssue {
+	maxDepth := v.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxHierarchyDepth
+	}
+
+	var issues []ValidationIssue
+	reportedCycles := make(map[string]bool)
+
+	for _, p := range sortedByID(periods) {
+		chain := []string{p.ID}
+		position := map[string]int{p.ID: 0}
+
+		for cur := p; cur.ParentPeriodID != nil; {
+			parent, ok := byID[*cur.ParentPeriodID]
+			if !ok {
+				break
+			}
+
+			if start, seen := position[parent.ID]; seen {
+				cycle := canonicalCycle(chain[start:])
+				key := strings.Join(cycle, ",")
+				if !reportedCycles[key] {
+					reportedCycles[key] = true
+					err := &ParentCycleError{Chain: cycle}
+					issues = append(issues, ValidationIssue{
+						Rule:      RuleHierarchy,
+						Severity:  SeverityError,
+						PeriodIDs: cycle[:len(cycle)-1],
+						Message:   err.Error(),
+						Err:       err,
+					})
+				}
+				chain = nil // Depth is meaningless for a cyclic chain
+				break
+			}
+
+			position[parent.ID] = len(chain)
+			chain = append(chain, parent.ID)
+			cur = parent
+		}
+
+		if len(chain) > maxDepth {
+			err := &HierarchyDepthError{PeriodID: p.ID, Depth: len(chain), MaxDepth: maxDepth, Chain: chain}
+			issues = append(issues, ValidationIssue{
+				Rule:      RuleHierarchy,
+				Severity:  SeverityError,
+				PeriodIDs: []string{p.ID},
+				Message:   err.Error(),
+				Err:       err,
+			})
+		}
+	}
+
+	return issues
+}
+
+// canonicalCycle rotates a cycle to start at its smallest ID and closes the loop,
+// so the same cycle found from different entry points is reported identically.
+func canonicalCycle(cycle []string) []string {
+	minIdx := 0
+	for i, id := range cycle {
+		if id < cycle[minIdx] {
+			minIdx = i
+		}
+	}
+
+	out := make([]string, 0, len(cycle)+1)
+	out = append(out, cycle[minIdx:]...)
+	out = append(out, cycle[:minIdx]...)
+	return append(out, out[0])
+}
+
+// ValidateOverlaps reports periods of the same calendar AND granularity that overlap.
+// CAL and FY quarters legitimately overlap each other (FY2026-Q1 = Apr–Jun 2026 = 2026-Q2),
+// so each calendar is checked on its own.
+func (v *CalendarValidator) ValidateOverlaps(periods []*Period) []ValidationIssue {
+	periods = live(periods)
+	type groupKey struct {
+		calendar    CalendarType
+		granularity PeriodGranularity
+	}
+
+	grouped := make(map[groupKey][]*Period)
+	var keys []groupKey
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		k := groupKey{p.Calendar, p.Granularity}
+		if _, ok := grouped[k]; !ok {
+			keys = append(keys, k)
+		}
+		grouped[k] = append(grouped[k], p)
+	}
+
+	// Deterministic group order
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].calendar != keys[j].calendar {
+			return keys[i].calendar < keys[j].calendar
+		}
+		return keys[i].granularity < keys[j].granularity
+	})
+
+	var issues []ValidationIssue
+	for _, k := range keys {
+		list := sortedByStart(grouped[k])
+		for i := 1; i < len(list); i++ {
+			prev, curr := list[i-1], list[i]
+
+			// Overlap if: curr.Start <= prev.End (end dates are inclusive)
+			if !curr.StartDate.After(prev.EndDate) {
+				issues = append(issues, newIssue(RuleOverlap, []string{prev.ID, curr.ID},
+					"Overlap detected (%s): %s (%s → %s) overlaps with %s (%s → %s)",
+					k.granularity,
+					prev.ID, fmtDate(prev.StartDate), fmtDate(prev.EndDate),
+					curr.ID, fmtDate(curr.StartDate), fmtDate(curr.EndDate)))
+			}
+		}
+	}
+
+	return issues
+}
+
+// ValidateGaps reports missing time between consecutive Gregorian months.
+func (v *CalendarValidator) ValidateGaps(periods []*Period) []ValidationIssue {
+	periods = live(periods)
+	var months []*Period
+	for _, p := range periods {
+		if p != nil && p.Granularity == MonthlyPeriod && p.Calendar == CalendarGregorian {
+			months = append(months, p)
+		}
+	}
+	months = sortedByStart(months)
+
+	var issues []ValidationIssue
+	for i := 1; i < len(months); i++ {
+		prev, curr := months[i-1], months[i]
+		if curr.StartDate.After(prev.EndDate.Add(time.Nanosecond)) {
+			issues = append(issues, newIssue(RuleGap, []string{prev.ID, curr.ID},
+				"Gap detected (%s): %s ends %s but %s starts %s",
+				MonthlyPeriod, prev.ID, fmtDate(prev.EndDate), curr.ID, fmtDate(curr.StartDate)))
+		}
+	}
+
+	return issues
+}
+
+// ValidateFiscalCoverage checks that every month-based fiscal year (FY) spans exactly
+// 12 contiguous Gregorian months and starts/ends on month boundaries.
+// See PeriodService.ValidateFiscalCoverage for the rationale of each rule.
+func (v *CalendarValidator) ValidateFiscalCoverage(periods []*Period) []ValidationIssue {
+	periods = live(periods)
+	var months, fiscalYears []*Period
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		if p.Granularity == MonthlyPeriod && p.Calendar == CalendarGregorian {
+			months = append(months, p)
+		}
+		if p.Granularity == CalendarYearPeriod && p.Calendar == CalendarFiscal {
+			fiscalYears = append(fiscalYears, p)
+		}
+	}
+	months = sortedByStart(months)
+
+	var issues []ValidationIssue
+	for _, fy := range sortedByStart(fiscalYears) {
+		// A month belongs to a fiscal year IF AND ONLY IF it lies fully inside it
+		var fyMonths []*Period
+		for _, m := range months {
+			if !m.StartDate.Before(fy.StartDate) && !m.EndDate.After(fy.EndDate) {
+				fyMonths = append(fyMonths, m)
+			}
+		}
+
+		// RULE 1: Fiscal year must span EXACTLY 12 months
+		if len(fyMonths) != 12 {
+			issues = append(issues, newIssue(RuleFiscalCoverage, []string{fy.ID},
+				"fiscal year %s spans %d months (expected exactly 12)", fy.ID, len(fyMonths)))
+		}
+
+		// RULE 2: Months must be contiguous (no gaps or overlaps)
+		for i := 1; i < len(fyMonths); i++ {
+			prev, curr := fyMonths[i-1], fyMonths[i]
+			if !curr.StartDate.Equal(prev.EndDate.Add(time.Nanosecond)) {
+				issues = append(issues, newIssue(RuleFiscalCoverage, []string{fy.ID, prev.ID, curr.ID},
+					"fiscal year %s has gap or overlap between %s and %s", fy.ID, prev.ID, curr.ID))
+			}
+		}
+
+		// RULE 3: Boundary alignment
+		if len(fyMonths) > 0 {
+			first, last := fyMonths[0], fyMonths[len(fyMonths)-1]
+			if !first.StartDate.Equal(fy.StartDate) {
+				issues = append(issues, newIssue(RuleFiscalCoverage, []string{fy.ID},
+					"fiscal year %s does not start on a month boundary (starts %s)", fy.ID, first.StartDate))
+			}
+			if !last.EndDate.Equal(fy.EndDate) {
+				issues = append(issues, newIssue(RuleFiscalCoverage, []string{fy.ID},
+					"fiscal year %s does not end on a month boundary (ends %s)", fy.ID, last.EndDate))
+			}
+		}
+	}
+
+	return issues
+}
+
+// ValidateWeekFiscalCoverage validates all week-based (FW) fiscal years:
+// 52/53 whole weeks with 13/13/13/13(14)-week quarters (ValidateWeekFiscalYear),
+// a common year-end weekday (taken from the earliest year), and contiguity between years.
+// Quarters are matched to their year through ParentPeriodID.
+func (v *CalendarValidator) ValidateWeekFiscalCoverage(periods []*Period) []ValidationIssue {
+	periods = live(periods)
+	var years, quarters []*Period
+	for _, p := range periods {
+		if p == nil || p.Calendar != CalendarFiscalWeek {
+			continue
+		}
+		switch p.Granularity {
+		case CalendarYearPeriod:
+			years = append(years, p)
+		case QuarterlyPeriod:
+			quarters = append(quarters, p)
+		}
+	}
+	if len(years) == 0 {
+		return nil
+	}
+
+	years = sortedByStart(years)
+	quarters = sortedByStart(quarters)
+	endWeekday := years[0].EndDate.Weekday()
+
+	var issues []ValidationIssue
+	for i, fy := range years {
+		var fyQuarters []*Period
+		for _, q := range quarters {
+			if q.ParentPeriodID != nil && *q.ParentPeriodID == fy.ID {
+				fyQuarters = append(fyQuarters, q)
+			}
+		}
+
+		for _, msg := range ValidateWeekFiscalYear(fy, fyQuarters, endWeekday) {
+			issues = append(issues, newIssue(RuleWeekFiscalCoverage, []string{fy.ID}, "%s", msg))
+		}
+
+		if i > 0 && !fy.StartDate.Equal(years[i-1].EndDate.Add(time.Nanosecond)) {
+			issues = append(issues, newIssue(RuleWeekFiscalCoverage, []string{fy.ID, years[i-1].ID},
+				"fiscal year %s does not start directly after %s", fy.ID, years[i-1].ID))
+		}
+	}
+
+	return issues
+}
+
+// indexByID builds an ID lookup over a period set, skipping nil entries.
+func indexByID(periods []*Period) map[string]*Period {
+	byID := make(map[string]*Period, len(periods))
+	for _, p := range periods {
+		if p != nil {
+			byID[p.ID] = p
+		}
+	}
+	return byID
+}
+
+// sortedByID returns a copy of the non-nil periods sorted by ID.
+func sortedByID(periods []*Period) []*Period {
+	out := compact(periods)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// sortedByStart returns a copy of the non-nil periods sorted by StartDate, ties broken by ID.
+func sortedByStart(periods []*Period) []*Period {
+	out := compact(periods)
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartDate.Equal(out[j].StartDate) {
+			return out[i].StartDate.Before(out[j].StartDate)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// sortedByGranularity returns years first, then quarters, then months, each chronologically.
+func sortedByGranularity(periods []*Period) []*Period {
+	out := sortedByStart(periods)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].GranularityRank() > out[j].GranularityRank()
+	})
+	return out
+}
+
+// live returns the non-nil periods that are not soft-deleted. Every rule starts with it, so
+// soft-deleted periods neither raise issues nor satisfy references.
+func live(periods []*Period) []*Period {
+	out := make([]*Period, 0, len(periods))
+	for _, p := range periods {
+		if p != nil && !p.IsDeleted() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func compact(periods []*Period) []*Period {
+	out := make([]*Period, 0, len(periods))
+	for _, p := range periods {
+		if p != nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
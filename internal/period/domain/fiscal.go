@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/nholding/cso-book/internal/audit"
@@ -23,6 +24,11 @@ import (
 type FiscalCalendarConfig struct {
 	StartYear  int        // the calendar year where the fiscal year begins (e.g., 2026)
 	StartMonth time.Month // the month where fiscal year begins (e.g., April)
+
+	// Week-based (52/53-week) fiscal years; see GenerateWeekFiscalYear.
+	WeekBased      bool            // generate a week-based fiscal year instead of a month-based one
+	YearEndWeekday time.Weekday    // weekday on which every fiscal year ends (e.g., time.Saturday)
+	YearEndRule    WeekYearEndRule // how the year-end weekday is chosen around the end of the last month
 }
 
 type FiscalCalendar struct {
@@ -42,7 +48,7 @@ type FiscalCalendar struct {
 //
 // Assumptions:
 //
-//   - The store.Months slice contains all months from GeneratePeriods and is
+//   - The store.Months() snapshot contains all months from GeneratePeriods and is
 //     sorted chronologically (earliest → latest).
 //   - Fiscal quarters always span 3 months starting from the fiscal year start month.
 //   - The function sets proper ParentPeriodID/ChildPeriodIDs relationships.
@@ -134,6 +140,7 @@ func GenerateFiscalYear(months []*Period, cfg FiscalCalendarConfig) ([]*Period,
 		StartDate:      fyStart,
 		EndDate:        fyEnd,
 		ChildPeriodIDs: []string{}, // will be filled with fiscal quarters
+		Version:        1,
 		AuditInfo:      audit.NewAuditInfo(systemUser),
 	}
 
@@ -168,6 +175,7 @@ func GenerateFiscalYear(months []*Period, cfg FiscalCalendarConfig) ([]*Period,
 			StartDate:      qMonths[0].StartDate,
 			EndDate:        qMonths[len(qMonths)-1].EndDate,
 			ChildPeriodIDs: []string{},
+			Version:        1,
 			AuditInfo:      audit.NewAuditInfo(systemUser),
 		}
 
@@ -186,6 +194,96 @@ func GenerateFiscalYear(months []*Period, cfg FiscalCalendarConfig) ([]*Period,
 	return fyPeriods, nil
 }
 
+// GenerateFiscalYears
+//
+// Purpose:
+//
+//	Generates a consistent run of consecutive fiscal years (startYear … endYear, inclusive)
+//	that all start in cfg.StartMonth. Every fiscal year is built with GenerateFiscalYear,
+//	so quarters reference the SAME shared Gregorian month IDs, and the result is then
+//	validated ACROSS years, which a loop over GenerateFiscalYear cannot do:
+//
+//	  - FY(n+1) starts exactly one nanosecond after FY(n) ends (no gaps, no overlaps)
+//	  - Every month is a child of exactly one fiscal quarter across the whole run
+//
+// Parameters:
+//
+//	months             - Gregorian months (e.g. store.Months())
+//	cfg                - only cfg.StartMonth is used; cfg.StartYear is replaced per year
+//	startYear, endYear - first and last fiscal year to generate (inclusive)
+//
+// Example usage:
+//
+//	cfg := FiscalCalendarConfig{StartMonth: time.April}
+//	fyPeriods, err := GenerateFiscalYears(store.Months(), cfg, 2026, 2028)
+//
+// Expected outcome:
+//
+//	FY2026 (Apr 2026 – Mar 2027), FY2026-Q1 … FY2026-Q4,
+//	FY2027 (Apr 2027 – Mar 2028), FY2027-Q1 … FY2027-Q4,
+//	FY2028 (Apr 2028 – Mar 2029), FY2028-Q1 … FY2028-Q4
+//
+// Notes:
+//
+//   - Requires Gregorian months for the full span, i.e. up to (endYear+1) when the
+//     fiscal year does not start in January.
+//   - Fails as a whole: either all fiscal years are returned, or none.
+func GenerateFiscalYears(months []*Period, cfg FiscalCalendarConfig, startYear, endYear int) ([]*Period, error) {
+	if startYear > endYear {
+		return nil, fmt.Errorf("invalid fiscal year range: startYear %d is after endYear %d", startYear, endYear)
+	}
+
+	var all []*Period
+	var years []*Period
+
+	for y := startYear; y <= endYear; y++ {
+		yearCfg := FiscalCalendarConfig{StartYear: y, StartMonth: cfg.StartMonth}
+
+		fyPeriods, err := GenerateFiscalYear(months, yearCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate FY%d: %w", y, err)
+		}
+
+		years = append(years, fyPeriods[0]) // GenerateFiscalYear returns the year first
+		all = append(all, fyPeriods...)
+	}
+
+	if errs := validateFiscalRun(years, all); len(errs) > 0 {
+		return nil, fmt.Errorf("fiscal years FY%d–FY%d are inconsistent: %s", startYear, endYear, strings.Join(errs, "; "))
+	}
+
+	return all, nil
+}
+
+// validateFiscalRun checks cross-year invariants of consecutive fiscal years:
+// contiguity between years and single ownership of every month by a fiscal quarter.
+func validateFiscalRun(years []*Period, all []*Period) []string {
+	var errs []string
+
+	for i := 1; i < len(years); i++ {
+		prev, curr := years[i-1], years[i]
+		if !curr.StartDate.Equal(prev.EndDate.Add(time.Nanosecond)) {
+			errs = append(errs, fmt.Sprintf("%s does not start directly after %s ends", curr.ID, prev.ID))
+		}
+	}
+
+	owner := make(map[string]string)
+	for _, p := range all {
+		if p.Granularity != QuarterlyPeriod {
+			continue
+		}
+		for _, monthID := range p.ChildPeriodIDs {
+			if other, ok := owner[monthID]; ok {
+				errs = append(errs, fmt.Sprintf("month %s belongs to both %s and %s", monthID, other, p.ID))
+				continue
+			}
+			owner[monthID] = p.ID
+		}
+	}
+
+	return errs
+}
+
 //// GenerateFiscalPeriods generates a full fiscal year (months, quarters, and year period)
 //// based on a user-provided fiscal calendar configuration. This allows supporting fiscal years
 //// that do not start in January. For example, a fiscal year starting in April 2026.
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+)
+
+// WeekYearEndRule determines on which date a week-based fiscal year ends.
+//
+// LAST:    the last YearEndWeekday of the month before StartMonth
+// NEAREST: the YearEndWeekday nearest to the last day of the month before StartMonth
+//
+//	(may fall a few days into StartMonth)
+type WeekYearEndRule string
+
+const (
+	WeekYearEndLast    WeekYearEndRule = "LAST"
+	WeekYearEndNearest WeekYearEndRule = "NEAREST"
+)
+
+const week = 7 * 24 * time.Hour
+
+// GenerateWeekFiscalYear
+//
+// Purpose:
+//
+//	Generates a week-based (52/53-week, "4-4-5" style) fiscal year and its quarters.
+//	Such a fiscal year always ends on the same weekday, so its length alternates
+//	between 52 weeks (364 days) and, every five or six years, 53 weeks (371 days).
+//
+//	Quarters are 13 weeks each. In a 53-week year the extra week is added to Q4,
+//	which then spans 14 weeks.
+//
+// Why a separate calendar (FW):
+//
+//	Week-based boundaries do not align with Gregorian months, so these periods
+//	cannot satisfy the month-based invariants checked by ValidateFiscalCoverage.
+//	They are validated by ValidateWeekFiscalYear instead. Quarters carry no
+//	ChildPeriodIDs because no month belongs to exactly one week-based quarter.
+//
+// Example usage:
+//
+//	cfg := FiscalCalendarConfig{
+//	    StartYear:      2026,
+//	    StartMonth:     time.February,
+//	    WeekBased:      true,
+//	    YearEndWeekday: time.Saturday,
+//	    YearEndRule:    WeekYearEndLast,
+//	}
+//	fw, err := GenerateWeekFiscalYear(cfg)
+//
+// Expected outcome:
+//
+//	fw[0] = FW2026     → Feb 1, 2026 – Jan 30, 2027 (52 weeks)
+//	fw[1] = FW2026-Q1  → Feb 1, 2026 – May 2, 2026  (13 weeks)
+//	...
+//	fw[4] = FW2026-Q4  → Nov 1, 2026 – Jan 30, 2027 (13 weeks)
+func GenerateWeekFiscalYear(cfg FiscalCalendarConfig) ([]*Period, error) {
+	systemUser := "system@internal.local"
+
+	prevEnd, err := weekFiscalYearEnd(cfg.StartYear-1, cfg)
+	if err != nil {
+		return nil, err
+	}
+	end, err := weekFiscalYearEnd(cfg.StartYear, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	start := prevEnd.AddDate(0, 0, 1)
+	endInclusive := end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	weeks := int(end.AddDate(0, 0, 1).Sub(start) / week)
+
+	fyID := fmt.Sprintf("FW%d", cfg.StartYear)
+	fy := &Period{
+		ID:             fyID,
+		Name:           fmt.Sprintf("Fiscal Year %d (%d weeks)", cfg.StartYear, weeks),
+		Calendar:       CalendarFiscalWeek,
+		Granularity:    CalendarYearPeriod,
+		StartDate:      start,
+		EndDate:        endInclusive,
+		ChildPeriodIDs: []string{},
+		Version:        1,
+		AuditInfo:      audit.NewAuditInfo(systemUser),
+	}
+
+	periods := []*Period{fy}
+
+	qStart := start
+	for q := 1; q <= 4; q++ {
+		qWeeks := 13
+		if q == 4 {
+			qWeeks = weeks - 39 // 13 in a 52-week year, 14 in a 53-week year
+		}
+		qEnd := qStart.AddDate(0, 0, 7*qWeeks)
+
+		qID := fmt.Sprintf("FW%d-Q%d", cfg.StartYear, q)
+		periods = append(periods, &Period{
+			ID:             qID,
+			Name:           fmt.Sprintf("FW%d Q%d (%d weeks)", cfg.StartYear, q, qWeeks),
+			Calendar:       CalendarFiscalWeek,
+			Granularity:    QuarterlyPeriod,
+			ParentPeriodID: &fyID,
+			StartDate:      qStart,
+			EndDate:        qEnd.Add(-time.Nanosecond),
+			ChildPeriodIDs: []string{},
+			Version:        1,
+			AuditInfo:      audit.NewAuditInfo(systemUser),
+		})
+		fy.ChildPeriodIDs = append(fy.ChildPeriodIDs, qID)
+
+		qStart = qEnd
+	}
+
+	if errs := ValidateWeekFiscalYear(fy, periods[1:], cfg.YearEndWeekday); len(errs) > 0 {
+		return nil, fmt.Errorf("generated %s is invalid: %v", fyID, errs)
+	}
+
+	return periods, nil
+}
+
+// weekFiscalYearEnd returns the last DAY (midnight UTC) of the week-based fiscal year that
+// starts in the given year.
+func weekFiscalYearEnd(startYear int, cfg FiscalCalendarConfig) (time.Time, error) {
+	// Day 0 of StartMonth in startYear+1 is the last day of the month before StartMonth.
+	anchor := time.Date(startYear+1, cfg.StartMonth, 0, 0, 0, 0, 0, time.UTC)
+
+	switch cfg.YearEndRule {
+	case WeekYearEndLast:
+		back := (int(anchor.Weekday()) - int(cfg.YearEndWeekday) + 7) % 7
+		return anchor.AddDate(0, 0, -back), nil
+	case WeekYearEndNearest:
+		back := (int(anchor.Weekday()) - int(cfg.YearEndWeekday) + 7) % 7
+		if back > 3 {
+			return anchor.AddDate(0, 0, 7-back), nil
+		}
+		return anchor.AddDate(0, 0, -back), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid week year-end rule %q", cfg.YearEndRule)
+	}
+}
+
+// ValidateWeekFiscalYear
+// Checks the invariants of a week-based fiscal year, tolerating the 53rd week:
+//
+//   - the year spans exactly 52 or 53 whole weeks and ends on endWeekday
+//   - it has four contiguous quarters covering the year without gaps or overlaps
+//   - Q1–Q3 span 13 weeks; Q4 spans 13 weeks, or 14 in a 53-week year
+//
+// Returns one message per violation.
+func ValidateWeekFiscalYear(fy *Period, quarters []*Period, endWeekday time.Weekday) []string {
+	var errs []string
+
+	days := fy.EndDate.Add(time.Nanosecond).Sub(fy.StartDate)
+	weeks := int(days / week)
+	if days%week != 0 || (weeks != 52 && weeks != 53) {
+		errs = append(errs, fmt.Sprintf("%s spans %s, expected 52 or 53 whole weeks", fy.ID, days))
+	}
+	if fy.EndDate.Weekday() != endWeekday {
+		errs = append(errs, fmt.Sprintf("%s ends on %s, expected %s", fy.ID, fy.EndDate.Weekday(), endWeekday))
+	}
+
+	if len(quarters) != 4 {
+		return append(errs, fmt.Sprintf("%s has %d quarters, expected 4", fy.ID, len(quarters)))
+	}
+
+	expectedStart := fy.StartDate
+	for i, q := range quarters {
+		if !q.StartDate.Equal(expectedStart) {
+			errs = append(errs, fmt.Sprintf("%s does not start directly after the previous quarter", q.ID))
+		}
+
+		qWeeks := q.EndDate.Add(time.Nanosecond).Sub(q.StartDate) / week
+		expectedWeeks := time.Duration(13)
+		if i == 3 && weeks == 53 {
+			expectedWeeks = 14
+		}
+		if qWeeks != expectedWeeks {
+			errs = append(errs, fmt.Sprintf("%s spans %d weeks, expected %d", q.ID, qWeeks, expectedWeeks))
+		}
+
+		expectedStart = q.EndDate.Add(time.Nanosecond)
+	}
+
+	if !quarters[3].EndDate.Equal(fy.EndDate) {
+		errs = append(errs, fmt.Sprintf("%s does not end with its fourth quarter", fy.ID))
+	}
+
+	return errs
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Holiday is a non-business day, e.g. a public holiday or an exchange closure.
+type Holiday struct {
+	Date time.Time // Day of the holiday (UTC, time of day ignored)
+	Name string    // e.g. "King's Day"
+}
+
+// HolidayCalendar answers business-day questions: a business day is a weekday (Mon–Fri)
+// that is not a holiday.
+//
+// Example:
+//
+//	cal := NewHolidayCalendar(
+//	    Holiday{Date: time.Date(2026, 4, 27, 0, 0, 0, 0, time.UTC), Name: "King's Day"},
+//	    Holiday{Date: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day"},
+//	)
+//	cal.IsBusinessDay(time.Date(2026, 4, 27, 0, 0, 0, 0, time.UTC)) // → false
+type HolidayCalendar struct {
+	holidays map[string]Holiday // Keyed by "2006-01-02"
+}
+
+func NewHolidayCalendar(holidays ...Holiday) *HolidayCalendar {
+	cal := &HolidayCalendar{holidays: make(map[string]Holiday, len(holidays))}
+	for _, h := range holidays {
+		cal.holidays[dayKey(h.Date)] = h
+	}
+	return cal
+}
+
+// IsHoliday reports whether the day of t is a holiday.
+func (c *HolidayCalendar) IsHoliday(t time.Time) bool {
+	_, ok := c.holidays[dayKey(t)]
+	return ok
+}
+
+// IsBusinessDay reports whether the day of t is a weekday and not a holiday.
+func (c *HolidayCalendar) IsBusinessDay(t time.Time) bool {
+	wd := t.UTC().Weekday()
+	return wd != time.Saturday && wd != time.Sunday && !c.IsHoliday(t)
+}
+
+// AddBusinessDays moves n business days forward (n > 0) or backward (n < 0) from the day of t.
+// With n == 0 it returns the day itself when it is a business day, otherwise the next business day.
+// The result is the start of the day (UTC).
+//
+// Example (Friday 2026-01-30):
+//
+//	cal.AddBusinessDays(fri, 1) // → Monday 2026-02-02
+func (c *HolidayCalendar) AddBusinessDays(t time.Time, n int) time.Time {
+	u := t.UTC()
+	day := time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, time.UTC)
+
+	if n == 0 {
+		for !c.IsBusinessDay(day) {
+			day = day.AddDate(0, 0, 1)
+		}
+		return day
+	}
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		day = day.AddDate(0, 0, step)
+		if c.IsBusinessDay(day) {
+			n--
+		}
+	}
+	return day
+}
+
+// Holidays returns all holidays in chronological order.
+func (c *HolidayCalendar) Holidays() []Holiday {
+	out := make([]Holiday, 0, len(c.holidays))
+	for _, h := range c.holidays {
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
+
+func dayKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// CutOffRule defines a recurring deadline relative to the end of every period of one
+// granularity, counted in business days.
+//
+// Example: month-end close is due on the 3rd business day after the month ends.
+//
+//	CutOffRule{Name: "Month-end close", Granularity: MonthlyPeriod, BusinessDaysAfterEnd: 3}
+type CutOffRule struct {
+	Name                 string
+	Granularity          PeriodGranularity
+	BusinessDaysAfterEnd int
+}
+
+// CutOff is one concrete deadline produced by a CutOffRule for a period.
+type CutOff struct {
+	PeriodID string
+	Name     string
+	Date     time.Time // Day of the deadline (start of day, UTC)
+}
+
+// CutOffs computes the deadlines of rule for every matching period, in chronological order.
+//
+// Example (rule above, January 2026):
+//
+//	CutOff{PeriodID: "2026-JAN", Name: "Month-end close", Date: 2026-02-04}
+func (c *HolidayCalendar) CutOffs(rule CutOffRule, periods []*Period) ([]CutOff, error) {
+	if rule.BusinessDaysAfterEnd < 1 {
+		return nil, fmt.Errorf("cut-off rule %q must be at least one business day after period end", rule.Name)
+	}
+
+	var cutOffs []CutOff
+	for _, p := range periods {
+		if p == nil || p.Granularity != rule.Granularity {
+			continue
+		}
+		cutOffs = append(cutOffs, CutOff{
+			PeriodID: p.ID,
+			Name:     rule.Name,
+			Date:     c.AddBusinessDays(p.EndDate, rule.BusinessDaysAfterEnd),
+		})
+	}
+
+	sort.Slice(cutOffs, func(i, j int) bool {
+		if !cutOffs[i].Date.Equal(cutOffs[j].Date) {
+			return cutOffs[i].Date.Before(cutOffs[j].Date)
+		}
+		return cutOffs[i].PeriodID < cutOffs[j].PeriodID
+	})
+
+	return cutOffs, nil
+}
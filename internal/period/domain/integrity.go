@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Referential integrity rule identifiers (see CalendarValidator.CheckIntegrity).
+const (
+	RuleOrphanMonth       = "ORPHAN_MONTH"
+	RuleDanglingChild     = "DANGLING_CHILD"
+	RuleDanglingReference = "DANGLING_REFERENCE"
+)
+
+// PeriodReference is a reference to a period held by another record, e.g. a trade breakdown.
+// Other domains map their records to PeriodReferences, so the period domain can check them
+// without depending on those domains.
+//
+// Example:
+//
+//	PeriodReference{Kind: "breakdown", OwnerID: "T1", RecordID: "BD-17", PeriodID: "2026-JAN"}
+type PeriodReference struct {
+	Kind     string // Type of the referencing record, e.g. "breakdown"
+	OwnerID  string // Aggregate the record belongs to, e.g. the parent trade ID
+	RecordID string // ID of the referencing record itself
+	PeriodID string // Referenced period
+}
+
+// RepairItem is one integrity problem together with a suggested fix.
+type RepairItem struct {
+	Issue      ValidationIssue
+	Suggestion string
+}
+
+// RepairReport lists all integrity problems found by CheckIntegrity with repair suggestions.
+// It is a report for humans: nothing is repaired automatically.
+type RepairReport struct {
+	Items []RepairItem
+}
+
+// Healthy reports whether no integrity problems were found.
+func (r RepairReport) Healthy() bool {
+	return len(r.Items) == 0
+}
+
+// Errors returns every problem as an error (one per item, with its repair suggestion); nil when
+// the report is healthy. Use it to feed a report into sweep validators.
+func (r RepairReport) Errors() []error {
+	if r.Healthy() {
+		return nil
+	}
+	errs := make([]error, len(r.Items))
+	for i, item := range r.Items {
+		errs[i] = fmt.Errorf("%w (%s)", item.Issue, item.Suggestion)
+	}
+	return errs
+}
+
+// String renders the report one problem per line, followed by the suggested fix.
+//
+// Example:
+//
+//	[ORPHAN_MONTH] month 2026-JAN is not referenced by any quarter
+//	    → set parent_period_id of 2026-JAN to 2026-Q1
+func (r RepairReport) String() string {
+	if r.Healthy() {
+		return "no integrity problems found"
+	}
+
+	var b strings.Builder
+	for _, item := range r.Items {
+		fmt.Fprintf(&b, "[%s] %s\n    → %s\n", item.Issue.Rule, item.Issue.Message, item.Suggestion)
+	}
+	return b.String()
+}
+
+// CheckIntegrity
+//
+// PURPOSE:
+//
+//	Finds referential integrity problems that the hierarchy rules do not catch:
+//
+//	  - ORPHAN_MONTH:       Gregorian months that no quarter references, neither through the
+//	                        month's ParentPeriodID nor through a quarter's ChildPeriodIDs
+//	  - DANGLING_CHILD:     ChildPeriodIDs pointing at periods that do not exist
+//	  - DANGLING_REFERENCE: external records (e.g. trade breakdowns) referencing periods
+//	                        that do not exist (anymore)
+//
+//	Every problem comes with a repair suggestion. For orphan months the suggestion names the
+//	Gregorian quarter that contains the month by date, when there is one.
+//
+// EXAMPLE USAGE:
+//
+//	report := NewCalendarValidator().CheckIntegrity(store.All(), trade.BreakdownReferences(bds))
+//	if !report.Healthy() {
+//	    log.Println(report)
+//	}
+func (v *CalendarValidator) CheckIntegrity(periods []*Period, refs []PeriodReference) RepairReport {
+	periods = live(periods) // References to soft-deleted periods are dangling
+	byID := indexByID(periods)
+	var report RepairReport
+
+	// Which months are referenced by a quarter, in either direction?
+	referenced := make(map[string]bool)
+	var calQuarters []*Period
+	for _, p := range sortedByID(periods) {
+		if p.Granularity == MonthlyPeriod && p.ParentPeriodID != nil {
+			if parent, ok := byID[*p.ParentPeriodID]; ok && parent.Granularity == QuarterlyPeriod {
+				referenced[p.ID] = true
+			}
+		}
+		if p.Granularity == QuarterlyPeriod {
+			for _, childID := range p.ChildPeriodIDs {
+				referenced[childID] = true
+			}
+			if p.Calendar == CalendarGregorian {
+				calQuarters = append(calQuarters, p)
+			}
+		}
+	}
+
+	// ORPHAN_MONTH
+	for _, m := range sortedByStart(periods) {
+		if m.Granularity != MonthlyPeriod || m.Calendar != CalendarGregorian || referenced[m.ID] {
+			continue
+		}
+
+		suggestion := fmt.Sprintf("no Gregorian quarter contains %s; generate the quarters of its year", m.ID)
+		for _, q := range calQuarters {
+			if !m.StartDate.Before(q.StartDate) && !m.EndDate.After(q.EndDate) {
+				suggestion = fmt.Sprintf("set parent_period_id of %s to %s", m.ID, q.ID)
+				break
+			}
+		}
+
+		report.Items = append(report.Items, RepairItem{
+			Issue:      newIssue(RuleOrphanMonth, []string{m.ID}, "month %s is not referenced by any quarter", m.ID),
+			Suggestion: suggestion,
+		})
+	}
+
+	// DANGLING_CHILD
+	for _, p := range sortedByID(periods) {
+		for _, childID := range p.ChildPeriodIDs {
+			if _, ok := byID[childID]; ok {
+				continue
+			}
+			report.Items = append(report.Items, RepairItem{
+				Issue: newIssue(RuleDanglingChild, []string{p.ID, childID},
+					"period %s lists child %s, which does not exist", p.ID, childID),
+				Suggestion: fmt.Sprintf("remove %s from the children of %s", childID, p.ID),
+			})
+		}
+	}
+
+	// DANGLING_REFERENCE
+	sorted := append([]PeriodReference(nil), refs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].OwnerID != sorted[j].OwnerID {
+			return sorted[i].OwnerID < sorted[j].OwnerID
+		}
+		return sorted[i].RecordID < sorted[j].RecordID
+	})
+	for _, ref := range sorted {
+		if _, ok := byID[ref.PeriodID]; ok {
+			continue
+		}
+		report.Items = append(report.Items, RepairItem{
+			Issue: newIssue(RuleDanglingReference, []string{ref.PeriodID},
+				"%s %s of %s references period %s, which does not exist", ref.Kind, ref.RecordID, ref.OwnerID, ref.PeriodID),
+			Suggestion: fmt.Sprintf("restore period %s, or regenerate the %ss of %s", ref.PeriodID, ref.Kind, ref.OwnerID),
+		})
+	}
+
+	return report
+}
@@ -0,0 +1,292 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/cache"
+)
+
+// PeriodLookup is the read API shared by the fully resident PeriodStore and the
+// repository-backed LazyPeriodStore. Code that only needs lookups and breakdowns should
+// depend on PeriodLookup, so deployments can choose either store.
+type PeriodLookup interface {
+	// Find returns the period with the given ID, or nil when it does not exist.
+	Find(ctx context.Context, id string) (*Period, error)
+
+	// MonthsInPeriodRange returns the IDs of all months fully inside the range (see BreakDownTradePeriodRange).
+	MonthsInPeriodRange(ctx context.Context, pr PeriodRange) ([]string, error)
+
+	// MonthsOverlapping returns the IDs of all months overlapping [start, end] (see BreakDownDateRange).
+	MonthsOverlapping(ctx context.Context, start, end time.Time) ([]string, error)
+}
+
+var (
+	_ PeriodLookup = (*PeriodStore)(nil)
+	_ PeriodLookup = (*LazyPeriodStore)(nil)
+)
+
+// Find implements PeriodLookup. The in-memory store never fails.
+func (ps *PeriodStore) Find(_ context.Context, id string) (*Period, error) {
+	return ps.FindByID(id), nil
+}
+
+// MonthsInPeriodRange implements PeriodLookup.
+func (ps *PeriodStore) MonthsInPeriodRange(_ context.Context, pr PeriodRange) ([]string, error) {
+	return ps.BreakDownTradePeriodRange(pr), nil
+}
+
+// MonthsOverlapping implements PeriodLookup.
+func (ps *PeriodStore) MonthsOverlapping(_ context.Context, start, end time.Time) ([]string, error) {
+	return ps.BreakDownDateRange(start, end), nil
+}
+
+// PeriodLoader is the subset of the period repository the LazyPeriodStore needs.
+// repository.PeriodRepository satisfies it.
+type PeriodLoader interface {
+	// FindByID retrieves the current version of a Period; returns nil, nil when it does not exist
+	FindByID(ctx context.Context, id string) (*Period, error)
+
+	// GetPeriodsStartingBetween retrieves the current version of all Periods with from <= StartDate < to
+	GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*Period, error)
+}
+
+// LazyPeriodStore
+//
+// PURPOSE:
+//
+//	A repository-backed alternative to PeriodStore for deployments that generate a very
+//	wide calendar (e.g. 1950–2100) and do not want every period resident in memory.
+//
+//	Periods are loaded on demand in YEAR BUCKETS: bucket 2026 holds every period whose
+//	StartDate falls in calendar year 2026 (the CAL year, its quarters and months, but also
+//	FY2026 when it starts in April 2026). At most MaxYears buckets are kept; the least
+//	recently used bucket is evicted first.
+//
+// CONCURRENCY:
+//
+//	Safe for concurrent use. Returned periods are shared snapshots and must not be modified.
+//
+// EXAMPLE USAGE:
+//
+//	lazy := domain.NewLazyPeriodStore(periodRepo, 10) // keep 10 years in memory
+//	months, err := lazy.MonthsInPeriodRange(ctx, domain.PeriodRange{
+//	    StartPeriodID: "1987-Q3",
+//	    EndPeriodID:   "1988-Q1",
+//	})
+//	// → [1987-JUL, ..., 1988-MAR], loading buckets 1987 and 1988
+type LazyPeriodStore struct {
+	loader PeriodLoader
+
+	mu       sync.Mutex
+	buckets  *cache.LRU[int, *yearBucket]
+	idToYear map[string]int // Bucket of periods whose ID does not start with their start year (e.g. FW2026 starting Dec 2025)
+}
+
+// yearBucket holds the periods starting in one calendar year.
+type yearBucket struct {
+	byID   map[string]*Period
+	months []*Period // Chronologically sorted
+}
+
+func NewLazyPeriodStore(loader PeriodLoader, maxYears int) *LazyPeriodStore {
+	return &LazyPeriodStore{
+		loader:   loader,
+		buckets:  cache.NewLRU[int, *yearBucket](maxYears),
+		idToYear: make(map[string]int),
+	}
+}
+
+// Find returns the period with the given ID, loading its year on demand.
+// Returns nil, nil when the period does not exist.
+func (s *LazyPeriodStore) Find(ctx context.Context, id string) (*Period, error) {
+	// Fast path: the ID names its year ("2026-JAN", "FY2026-Q1") or was resolved before
+	year, ok := s.knownYear(id)
+	if ok {
+		bucket, err := s.bucket(ctx, year)
+		if err != nil {
+			return nil, err
+		}
+		if p, found := bucket.byID[id]; found {
+			return p, nil
+		}
+	}
+
+	// Slow path: ask the repository, then cache the period's whole year
+	p, err := s.loader.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load period %s: %w", id, err)
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	startYear := p.StartDate.UTC().Year()
+	s.mu.Lock()
+	s.idToYear[id] = startYear
+	s.mu.Unlock()
+
+	bucket, err := s.bucket(ctx, startYear)
+	if err != nil {
+		return nil, err
+	}
+	if cached, found := bucket.byID[id]; found {
+		return cached, nil
+	}
+	return p, nil
+}
+
+// MonthsInPeriodRange returns the IDs of all months fully contained in the range, in
+// chronological order. Unknown or reversed ranges return nil, like BreakDownTradePeriodRange.
+func (s *LazyPeriodStore) MonthsInPeriodRange(ctx context.Context, pr PeriodRange) ([]string, error) {
+	startPeriod, err := s.Find(ctx, pr.StartPeriodID)
+	if err != nil {
+		return nil, err
+	}
+	endPeriod, err := s.Find(ctx, pr.EndPeriodID)
+	if err != nil {
+		return nil, err
+	}
+	if startPeriod == nil || endPeriod == nil || startPeriod.StartDate.After(endPeriod.EndDate) {
+		return nil, nil
+	}
+
+	return s.months(ctx, startPeriod.StartDate, endPeriod.EndDate, func(m *Period) bool {
+		return !m.StartDate.Before(startPeriod.StartDate) && !m.EndDate.After(endPeriod.EndDate)
+	})
+}
+
+// MonthsOverlapping returns the IDs of all months overlapping the inclusive range [start, end].
+func (s *LazyPeriodStore) MonthsOverlapping(ctx context.Context, start, end time.Time) ([]string, error) {
+	if end.Before(start) {
+		return nil, nil
+	}
+
+	return s.months(ctx, start, end, func(m *Period) bool {
+		return !m.StartDate.After(end) && !m.EndDate.Before(start)
+	})
+}
+
+// PeriodRangeLoader is implemented by loaders that can filter a date window in the database
+// (see PeriodRepository.GetPeriodsInRange).
+type PeriodRangeLoader interface {
+	GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity PeriodGranularity) ([]*Period, error)
+}
+
+// PeriodsInRange returns all periods of the given granularity (empty = all) overlapping the
+// inclusive window [start, end], in chronological order.
+//
+// When the loader implements PeriodRangeLoader the window is fetched in one query without
+// touching the year buckets, so a wide reporting window does not evict the working set.
+// Otherwise the buckets covering the window are walked, starting one year early because
+// fiscal years and quarters can start in the previous calendar year.
+//
+// Example:
+//
+//	quarters, err := lazy.PeriodsInRange(ctx, start, end, domain.QuarterlyPeriod)
+func (s *LazyPeriodStore) PeriodsInRange(ctx context.Context, start, end time.Time, granularity PeriodGranularity) ([]*Period, error) {
+	if end.Before(start) {
+		return nil, nil
+	}
+
+	var periods []*Period
+	if rl, ok := s.loader.(PeriodRangeLoader); ok {
+		loaded, err := rl.GetPeriodsInRange(ctx, start, end, granularity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load periods in range: %w", err)
+		}
+		periods = loaded
+	} else {
+		for year := start.UTC().Year() - 1; year <= end.UTC().Year(); year++ {
+			bucket, err := s.bucket(ctx, year)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range bucket.byID {
+				if (granularity == "" || p.Granularity == granularity) &&
+					!p.StartDate.After(end) && !p.EndDate.Before(start) {
+					periods = append(periods, p)
+				}
+			}
+		}
+	}
+
+	return sortedByStart(periods), nil
+}
+
+// months walks the year buckets covering [start, end] and returns the matching month IDs.
+func (s *LazyPeriodStore) months(ctx context.Context, start, end time.Time, match func(m *Period) bool) ([]string, error) {
+	var monthIDs []string
+	for year := start.UTC().Year(); year <= end.UTC().Year(); year++ {
+		bucket, err := s.bucket(ctx, year)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range bucket.months {
+			if match(m) {
+				monthIDs = append(monthIDs, m.ID)
+			}
+		}
+	}
+	return monthIDs, nil
+}
+
+// bucket returns the cached bucket of a year, loading it from the repository on a miss.
+// The repository is queried without holding the lock, so slow loads do not block
+// lookups of other years; concurrent misses on the same year may load it twice.
+func (s *LazyPeriodStore) bucket(ctx context.Context, year int) (*yearBucket, error) {
+	s.mu.Lock()
+	b, ok := s.buckets.Get(year)
+	s.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	periods, err := s.loader.GetPeriodsStartingBetween(ctx, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load periods of year %d: %w", year, err)
+	}
+
+	b = &yearBucket{byID: make(map[string]*Period, len(periods))}
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		b.byID[p.ID] = p
+		if p.Granularity == MonthlyPeriod {
+			b.months = append(b.months, p)
+		}
+	}
+	sort.Slice(b.months, func(i, j int) bool {
+		return b.months[i].StartDate.Before(b.months[j].StartDate)
+	})
+
+	s.mu.Lock()
+	s.buckets.Add(year, b)
+	s.mu.Unlock()
+
+	return b, nil
+}
+
+// knownYear returns the bucket a period ID most likely lives in: a year resolved earlier,
+// or the first four-digit number in the ID ("2026-JAN" → 2026, "FY2026-Q1" → 2026).
+func (s *LazyPeriodStore) knownYear(id string) (int, bool) {
+	s.mu.Lock()
+	year, ok := s.idToYear[id]
+	s.mu.Unlock()
+	if ok {
+		return year, true
+	}
+
+	for i := 0; i+4 <= len(id); i++ {
+		if year, err := strconv.Atoi(id[i : i+4]); err == nil && year > 0 {
+			return year, true
+		}
+	}
+	return 0, false
+}
@@ -21,6 +21,7 @@ const (
 	CalendarYearPeriod PeriodGranularity = "CALENDAR"
 	CalendarGregorian  CalendarType      = "CAL" // normal Jan–Dec calendar
 	CalendarFiscal     CalendarType      = "FY"  // fiscal calendar
+	CalendarFiscalWeek CalendarType      = "FW"  // week-based (52/53-week) fiscal calendar
 )
 
 // Period defines a specific period of time for purchases and sales. It represents 'Years', 'Quarters', and 'Months.
@@ -45,6 +46,14 @@ type Period struct {
 	ChildPeriodIDs []string          // IDs of child periods (e.g., year has quarters, quarter has months); not stored in the DB
 	StartDate      time.Time         // Period start (UTC, inclusive)
 	EndDate        time.Time         // Period end (UTC, inclusive)
+	Metadata       map[string]string // Free-form tags (e.g. "budget-relevant" → "true"); stored in the period_metadata side table
+	Version        int               // Definition version, starting at 1; corrections create a new version
+	RowVersion     int               // Optimistic-lock counter of the stored row, bumped by every checked update (0 = 1)
+	EffectiveFrom  time.Time         // Moment from which this version is valid (zero = since the beginning)
+	EffectiveTo    *time.Time        // Moment until which this version was valid (inclusive); nil = current version
+	Status         PeriodStatus      // Accounting close state (OPEN, SOFT_CLOSED, HARD_CLOSED); empty means OPEN
+	DeletedAt      *time.Time        // Set when the period is soft-deleted; nil for live periods
+	DeletedBy      *string           // User who soft-deleted the period
 	AuditInfo      *audit.AuditInfo
 }
 
@@ -69,6 +78,12 @@ type PeriodRange struct {
 	EndPeriodID   string // ID of the ending period (e.g., "2026-Q2")
 }
 
+// IsZero reports whether the range is empty, e.g. for custom-dated trades that carry
+// explicit delivery dates instead of a PeriodRange.
+func (pr PeriodRange) IsZero() bool {
+	return pr.StartPeriodID == "" && pr.EndPeriodID == ""
+}
+
 // GeneratePeriods creates years, quarters, and months for a range of years.
 //
 // Example:
@@ -97,6 +112,7 @@ func GeneratePeriods(startYear, endYear int) []*Period {
 			ChildPeriodIDs: []string{},
 			StartDate:      yearStart,
 			EndDate:        yearEnd,
+			Version:        1,
 			AuditInfo:      audit.NewAuditInfo(systemUser),
 		}
 		periods = append(periods, yearPeriod)
@@ -116,6 +132,7 @@ func GeneratePeriods(startYear, endYear int) []*Period {
 				ChildPeriodIDs: []string{},
 				StartDate:      qStart,
 				EndDate:        qEnd,
+				Version:        1,
 				AuditInfo:      audit.NewAuditInfo(systemUser),
 			}
 
@@ -134,6 +151,7 @@ func GeneratePeriods(startYear, endYear int) []*Period {
 					ChildPeriodIDs: []string{},
 					StartDate:      monthStart,
 					EndDate:        monthEnd,
+					Version:        1,
 					AuditInfo:      audit.NewAuditInfo(systemUser),
 				}
 
@@ -151,7 +169,7 @@ func GeneratePeriods(startYear, endYear int) []*Period {
 // Validate checks the period for consistency and returns an error if invalid.
 func (p *Period) Validate() error {
 	if p.ID == "" {
-		fmt.Errorf("period ID cannot be empty")
+		return fmt.Errorf("period ID cannot be empty")
 	}
 	if p.Name == "" {
 		return fmt.Errorf("period name cannot be empty")
@@ -165,6 +183,72 @@ func (p *Period) Validate() error {
 	return nil
 }
 
+// SetTag sets a metadata key/value on the period, initialising the map when needed.
+//
+// Example:
+//
+//	p.SetTag("budget-relevant", "true")
+//	p.SetTag("audited", "2026-03-31")
+func (p *Period) SetTag(key, value string) {
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]string)
+	}
+	p.Metadata[key] = value
+}
+
+// RemoveTag deletes a metadata key from the period. Removing an unknown key is a no-op.
+func (p *Period) RemoveTag(key string) {
+	delete(p.Metadata, key)
+}
+
+// Tag returns the value stored under key and whether the key is present.
+func (p *Period) Tag(key string) (string, bool) {
+	v, ok := p.Metadata[key]
+	return v, ok
+}
+
+// IsDeleted reports whether the period is soft-deleted. Soft-deleted periods stay in the DB
+// (and can be restored) but are excluded from the PeriodStore and from validation.
+func (p *Period) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// Clone returns a copy of the period that shares no mutable state with the original
+// (metadata, child IDs, pointer fields and audit info are copied), so the copy can be
+// modified safely while other goroutines read the original.
+func (p *Period) Clone() *Period {
+	c := *p
+	c.ChildPeriodIDs = append([]string(nil), p.ChildPeriodIDs...)
+	if p.Metadata != nil {
+		c.Metadata = make(map[string]string, len(p.Metadata))
+		for k, v := range p.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	c.ParentPeriodID = cloneString(p.ParentPeriodID)
+	c.DeletedBy = cloneString(p.DeletedBy)
+	c.EffectiveTo = cloneTime(p.EffectiveTo)
+	c.DeletedAt = cloneTime(p.DeletedAt)
+	c.AuditInfo = p.AuditInfo.Clone()
+	return &c
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
 // GranularityRank
 // Purpose:
 //
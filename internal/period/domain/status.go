@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/dberr"
+)
+
+// PeriodStatus is the accounting close state of a period.
+//
+// OPEN:        trades and breakdowns in the period may be created, amended and cancelled.
+// SOFT_CLOSED: month-end is in progress; adjustments are still allowed but flagged.
+// HARD_CLOSED: the period is final. Its data must NEVER be mutated again; corrections are
+//
+//	booked as reversal entries in the current open period instead.
+type PeriodStatus string
+
+const (
+	PeriodOpen       PeriodStatus = "OPEN"
+	PeriodSoftClosed PeriodStatus = "SOFT_CLOSED"
+	PeriodHardClosed PeriodStatus = "HARD_CLOSED"
+)
+
+// statusRank orders statuses so that closing can only move forward.
+func statusRank(s PeriodStatus) int {
+	switch s {
+	case PeriodSoftClosed:
+		return 1
+	case PeriodHardClosed:
+		return 2
+	default: // OPEN and the zero value
+		return 0
+	}
+}
+
+// EffectiveStatus returns the period's status, treating the zero value as OPEN.
+func (p *Period) EffectiveStatus() PeriodStatus {
+	if p.Status == "" {
+		return PeriodOpen
+	}
+	return p.Status
+}
+
+// IsHardClosed reports whether the period's data is final.
+func (p *Period) IsHardClosed() bool {
+	return p.EffectiveStatus() == PeriodHardClosed
+}
+
+// TransitionStatus moves the period to a new close status.
+//
+// Allowed:   OPEN → SOFT_CLOSED → HARD_CLOSED, and SOFT_CLOSED → OPEN (re-open during month-end)
+// Forbidden: anything out of HARD_CLOSED
+func (p *Period) TransitionStatus(to PeriodStatus) error {
+	from := p.EffectiveStatus()
+
+	switch to {
+	case PeriodOpen, PeriodSoftClosed, PeriodHardClosed:
+	default:
+		return fmt.Errorf("invalid period status %q", to)
+	}
+
+	if from == PeriodHardClosed && to != PeriodHardClosed {
+		return &dberr.PeriodClosedError{PeriodID: p.ID, Status: string(PeriodHardClosed), Op: "be moved to " + string(to)}
+	}
+	if statusRank(to) < statusRank(from) && !(from == PeriodSoftClosed && to == PeriodOpen) {
+		return fmt.Errorf("period %s cannot move from %s to %s", p.ID, from, to)
+	}
+
+	p.Status = to
+	return nil
+}
+
+// CurrentOpenMonth returns the month in which corrections should be posted at moment `at`:
+// the month containing `at` if it is not hard-closed, otherwise the first later month that is
+// not hard-closed. Returns nil when no such month exists in the store.
+//
+// Example (Jan and Feb 2026 hard-closed, at = 2026-02-10):
+//
+//	m := ps.CurrentOpenMonth(at) // → 2026-MAR
+func (ps *PeriodStore) CurrentOpenMonth(at time.Time) *Period {
+	for _, m := range ps.Months() {
+		if m == nil || m.EndDate.Before(at) {
+			continue
+		}
+		if !m.IsHardClosed() {
+			return m
+		}
+	}
+	return nil
+}
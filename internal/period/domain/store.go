@@ -1,26 +1,37 @@
 package domain
 
 import (
+	"fmt"
 	"sort"
+	"sync"
 )
 
 // PeriodStore stores/caches all periods in memory for fast lookups and efficient breakdowns.
 // Intended to reduce RDS queries: load all periods at app startup.
 //
+// CONCURRENCY:
+//
+//	PeriodStore is safe for concurrent readers and writers. All access goes through its
+//	methods, which take an RWMutex. Periods are copy-on-write: a *Period returned by the
+//	store is a snapshot that is never modified afterwards, so callers may read it without
+//	locking but MUST NOT modify it. To change a period use Update (or Put a new value);
+//	readers holding the old pointer keep seeing the old, consistent snapshot.
+//
 // Example usage:
 //
 //	ps := NewPeriodStore(periods)
 //	jan2026 := ps.FindByID("2026-JAN")
 //	fmt.Println(jan2026.Name) // → "January 2026"
 type PeriodStore struct {
-	Periods  map[string]*Period // Lookup by ID
-	Months   []*Period          // Chronologically sorted months
-	Quarters []*Period          // Optional, sorted quarters
-	Years    []*Period          // Optional, sorted years
+	mu       sync.RWMutex
+	periods  map[string]*Period // Lookup by ID
+	months   []*Period          // Chronologically sorted months
+	quarters []*Period          // Optional, sorted quarters
+	years    []*Period          // Optional, sorted years
 }
 
 // NewPeriodStore initializes a PeriodStore from a slice of Periods.
-// It builds both a lookup map and a chronologically sorted months slice.
+// It builds both a lookup map and chronologically sorted month/quarter/year slices.
 //
 // Example:
 //
@@ -29,78 +40,233 @@ type PeriodStore struct {
 //	jan := store.FindByID("2026-JAN")
 func NewPeriodStore(periods []*Period) *PeriodStore {
 	store := &PeriodStore{
-		Periods: make(map[string]*Period),
+		periods: make(map[string]*Period),
 	}
+	store.Put(periods...)
+	return store
+}
+
+// Put adds periods to the store, replacing any existing period with the same ID, and
+// keeps the month/quarter/year slices sorted. Put takes ownership of the given periods:
+// the caller must not modify them afterwards. Soft-deleted periods are never stored:
+// putting one removes the live period with that ID.
+//
+// Example (fiscal years generated after startup):
+//
+//	fyPeriods, _ := GenerateFiscalYear(store.Months(), cfg)
+//	store.Put(fyPeriods...)
+func (ps *PeriodStore) Put(periods ...*Period) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
 
 	for _, p := range periods {
-		store.Periods[p.ID] = p
+		if p == nil {
+			continue
+		}
+
+		if existing, ok := ps.periods[p.ID]; ok {
+			ps.removeLocked(existing)
+			delete(ps.periods, p.ID)
+		}
+		if p.IsDeleted() {
+			continue
+		}
+		ps.periods[p.ID] = p
 
 		switch p.Granularity {
 		case MonthlyPeriod:
-			store.Months = append(store.Months, p)
+			ps.months = append(ps.months, p)
 		case QuarterlyPeriod:
-			store.Quarters = append(store.Quarters, p)
+			ps.quarters = append(ps.quarters, p)
 		case CalendarYearPeriod:
-			store.Years = append(store.Years, p)
+			ps.years = append(ps.years, p)
 		}
 	}
 
-	// Sort Months by StartDate
-	sort.Slice(store.Months, func(i, j int) bool {
-		return store.Months[i].StartDate.Before(store.Months[j].StartDate)
-	})
-
-	sort.Slice(store.Quarters, func(i, j int) bool {
-		return store.Quarters[i].StartDate.Before(store.Quarters[j].StartDate)
-	})
+	ps.sortLocked()
+}
 
-	sort.Slice(store.Years, func(i, j int) bool {
-		return store.Years[i].StartDate.Before(store.Years[j].StartDate)
-	})
+// Remove drops the period with the given ID from the store, e.g. after it was soft-deleted.
+// It reports whether the period was present.
+func (ps *PeriodStore) Remove(id string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
 
-	return store
+	existing, ok := ps.periods[id]
+	if !ok {
+		return false
+	}
+	ps.removeLocked(existing)
+	delete(ps.periods, id)
+	return true
 }
 
-// SortAll
+// Update applies fn to a copy of the period and swaps the copy into the store (copy-on-write).
+// Concurrent readers never observe a half-applied change. fn runs under the store's write
+// lock, so it must be quick and must not call back into the store.
 //
-//	Sorts all PeriodStore slices (Months, Quarters, Years) chronologically by StartDate.
+// Example:
 //
-// When to call:
-//   - After manually adding periods to the store
-//   - After generating fiscal years/quarters dynamically
+//	err := store.Update("2026-JAN", func(p *Period) {
+//	    p.Status = PeriodHardClosed
+//	})
+func (ps *PeriodStore) Update(id string, fn func(p *Period)) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	current, ok := ps.periods[id]
+	if !ok {
+		return fmt.Errorf("period %s does not exist", id)
+	}
+
+	next := current.Clone()
+	fn(next)
+	if next.ID != id || next.Granularity != current.Granularity {
+		return fmt.Errorf("period %s: Update must not change the ID or granularity", id)
+	}
+
+	ps.periods[id] = next
+	for _, list := range [][]*Period{ps.months, ps.quarters, ps.years} {
+		for i, p := range list {
+			if p == current {
+				list[i] = next
+			}
+		}
+	}
+	ps.sortLocked()
+
+	return nil
+}
+
+// Months returns a snapshot of all months, in chronological order.
+func (ps *PeriodStore) Months() []*Period {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return append([]*Period(nil), ps.months...)
+}
+
+// Quarters returns a snapshot of all quarters (CAL, FY and FW), in chronological order.
+func (ps *PeriodStore) Quarters() []*Period {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return append([]*Period(nil), ps.quarters...)
+}
+
+// Years returns a snapshot of all years (CAL, FY and FW), in chronological order.
+func (ps *PeriodStore) Years() []*Period {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return append([]*Period(nil), ps.years...)
+}
+
+// Len returns the number of periods in the store.
+func (ps *PeriodStore) Len() int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return len(ps.periods)
+}
+
+// removeLocked drops p from the granularity slices. The caller holds the write lock.
+func (ps *PeriodStore) removeLocked(p *Period) {
+	for _, list := range []*[]*Period{&ps.months, &ps.quarters, &ps.years} {
+		kept := (*list)[:0]
+		for _, existing := range *list {
+			if existing != p {
+				kept = append(kept, existing)
+			}
+		}
+		*list = kept
+	}
+}
+
+// sortLocked sorts the months, quarters and years chronologically by StartDate.
+// The caller holds the write lock.
 //
 // Notes:
-//   - Sorting Months is critical for correct behavior of
+//   - Sorting months is critical for correct behavior of
 //     BreakDownTradePeriodRange.
-//   - Sorting Years and Quarters ensures validation and
+//   - Sorting years and quarters ensures validation and
 //     traversal logic works predictably.
-func (ps *PeriodStore) SortAll() {
-	sort.Slice(ps.Months, func(i, j int) bool {
-		return ps.Months[i].StartDate.Before(ps.Months[j].StartDate)
-	})
-
-	sort.Slice(ps.Quarters, func(i, j int) bool {
-		return ps.Quarters[i].StartDate.Before(ps.Quarters[j].StartDate)
-	})
-
-	sort.Slice(ps.Years, func(i, j int) bool {
-		return ps.Years[i].StartDate.Before(ps.Years[j].StartDate)
-	})
+func (ps *PeriodStore) sortLocked() {
+	for _, list := range [][]*Period{ps.months, ps.quarters, ps.years} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].StartDate.Before(list[j].StartDate)
+		})
+	}
 }
 
-// FindByID retrieves a period pointer by ID
+// FindByID retrieves a period pointer by ID. The returned period must not be modified;
+// use Update instead.
 //
 // Example:
 //
 //	p := store.FindByID("2026-JAN")
 //	fmt.Println(p.Name) // → "January 2026"
 func (ps *PeriodStore) FindByID(id string) *Period {
-	if p, ok := ps.Periods[id]; ok {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	if p, ok := ps.periods[id]; ok {
 		return p
 	}
 	return nil
 }
 
+// FilterByTag returns all periods carrying the metadata key, regardless of its value,
+// ordered chronologically (ties broken by ID so the output is deterministic).
+//
+// Example:
+//
+//	budget := store.FilterByTag("budget-relevant")
+//	// → [2026-Q1, 2026-JAN, 2026-FEB, ...]
+func (ps *PeriodStore) FilterByTag(key string) []*Period {
+	return ps.filter(func(p *Period) bool {
+		_, ok := p.Tag(key)
+		return ok
+	})
+}
+
+// FilterByTagValue returns all periods whose metadata key equals value,
+// ordered chronologically.
+//
+// Example:
+//
+//	audited := store.FilterByTagValue("audited", "true")
+func (ps *PeriodStore) FilterByTagValue(key, value string) []*Period {
+	return ps.filter(func(p *Period) bool {
+		v, ok := p.Tag(key)
+		return ok && v == value
+	})
+}
+
+// All returns every period in the store, ordered chronologically.
+// Useful to hand the full store to a CalendarValidator.
+func (ps *PeriodStore) All() []*Period {
+	return ps.filter(func(p *Period) bool { return true })
+}
+
+// filter returns all periods matching the predicate, sorted by StartDate and ID.
+func (ps *PeriodStore) filter(match func(p *Period) bool) []*Period {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	var result []*Period
+	for _, p := range ps.periods {
+		if p != nil && match(p) {
+			result = append(result, p)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].StartDate.Equal(result[j].StartDate) {
+			return result[i].StartDate.Before(result[j].StartDate)
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
 // Creates a PeriodStore from hardcoded periods. Used for development purposes only.
 //
 // EXAMPLE: Use this during development BEFORE hooking up AWS.
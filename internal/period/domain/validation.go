@@ -1,22 +1,21 @@
 package domain
 
 import (
-	"fmt"
-	"sort"
 	"time"
 )
 
 // DetectOverlaps
-// validates that no two periods of the same granularity overlap.
+// validates that no two periods of the same calendar and granularity overlap.
 //
-// It returns a slice of human-readable error messages.
+// It returns a slice of human-readable error messages. Use
+// CalendarValidator.ValidateOverlaps for structured issues.
 //
 // HOW IT WORKS:
-//   - Group periods by granularity (YEARLY/CALENDAR, QUARTERLY, MONTHLY)
+//   - Group periods by calendar (CAL, FY, FW) and granularity (CALENDAR, QUARTERLY, MONTHLY)
 //   - For each group:
 //   - Sort by StartDate
 //   - Compare each period with the next one
-//   - If StartDate < previous.EndDate → OVERLAP
+//   - If StartDate <= previous.EndDate → OVERLAP (end dates are inclusive)
 //
 // EXAMPLE USAGE:
 //
@@ -31,49 +30,37 @@ import (
 //
 // ============================================================================
 func DetectOverlaps(periods []*Period) []string {
+	return issueMessages(NewCalendarValidator().ValidateOverlaps(periods))
+}
 
-	// --- 1. Group periods by granularity -----------------------------------
-	grouped := map[PeriodGranularity][]*Period{
-		CalendarYearPeriod: {},
-		QuarterlyPeriod:    {},
-		MonthlyPeriod:      {},
-	}
+// DetectGaps
+// validates that consecutive Gregorian months are contiguous, i.e. each month starts
+// exactly one nanosecond after the previous month ends.
+//
+// A gap means trades spanning the missing month would silently lose volume
+// in BreakDownTradePeriodRange, so gaps are reported as errors.
+//
+// EXAMPLE USAGE:
+//
+//	errs := DetectGaps(periods)
+//
+// EXPECTED OUTPUT (if 2026-MAR is missing):
+//
+//	"Gap detected (MONTHLY): 2026-FEB ends 2026-02-28 but 2026-APR starts 2026-04-01"
+func DetectGaps(periods []*Period) []string {
+	return issueMessages(NewCalendarValidator().ValidateGaps(periods))
+}
 
-	for _, p := range periods {
-		grouped[p.Granularity] = append(grouped[p.Granularity], p)
+func issueMessages(issues []ValidationIssue) []string {
+	if len(issues) == 0 {
+		return nil
 	}
 
-	var errs []string
-
-	// --- 2. Validate overlaps inside each granularity group -----------------
-	for granularity, list := range grouped {
-
-		// Sort by StartDate (oldest first)
-		sort.Slice(list, func(i, j int) bool {
-			return list[i].StartDate.Before(list[j].StartDate)
-		})
-
-		for i := 1; i < len(list); i++ {
-			prev := list[i-1]
-			curr := list[i]
-
-			// Overlap if: curr.Start < prev.End
-			if curr.StartDate.Before(prev.EndDate) {
-				errs = append(errs, fmt.Sprintf(
-					"Overlap detected (%s): %s (%s → %s) overlaps with %s (%s → %s)",
-					granularity,
-					prev.ID,
-					fmtDate(prev.StartDate),
-					fmtDate(prev.EndDate),
-					curr.ID,
-					fmtDate(curr.StartDate),
-					fmtDate(curr.EndDate),
-				))
-			}
-		}
+	msgs := make([]string, len(issues))
+	for i, issue := range issues {
+		msgs[i] = issue.Message
 	}
-
-	return errs
+	return msgs
 }
 
 // Utility to format time for nicer error messages
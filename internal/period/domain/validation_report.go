@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+)
+
+// Severity classifies a ValidationIssue.
+type Severity string
+
+const (
+	SeverityError   Severity = "ERROR"   // Data is unsafe to use; startup must fail
+	SeverityWarning Severity = "WARNING" // Data is usable but should be repaired
+)
+
+// ruleSeverity returns the default severity of a rule. Structural and coverage rules are
+// errors; integrity findings that do not affect breakdowns are warnings.
+func ruleSeverity(rule string) Severity {
+	switch rule {
+	case RuleOrphanMonth, RuleDanglingChild:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+// ValidationReport
+//
+// PURPOSE:
+//
+//	The typed result of period validation. It replaces the []string / []error mix the
+//	validators used to return: every issue carries its rule ID, severity and offending
+//	period IDs, so callers can filter, JSON-encode and assert on the results.
+//
+//	Issues are sorted deterministically (rule, first period ID, message), so two runs
+//	over the same data produce identical reports.
+//
+// EXAMPLE USAGE:
+//
+//	report, err := periodService.ValidateHierarchy()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, issue := range report.BySeverity(domain.SeverityError).Issues {
+//	    log.Println(issue.Rule, issue.PeriodIDs, issue.Message)
+//	}
+//	_ = json.NewEncoder(w).Encode(report)
+//
+// EXAMPLE JSON:
+//
+//	{"issues":[{"rule":"OVERLAP","severity":"ERROR","periodIds":["2026-MAR","2026-APR"],
+//	            "message":"Overlap detected (MONTHLY): ..."}]}
+type ValidationReport struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// NewValidationReport merges groups of issues into one deterministically sorted report.
+func NewValidationReport(groups ...[]ValidationIssue) ValidationReport {
+	issues := []ValidationIssue{}
+	for _, g := range groups {
+		issues = append(issues, g...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if pa, pb := firstID(a), firstID(b); pa != pb {
+			return pa < pb
+		}
+		return a.Message < b.Message
+	})
+
+	return ValidationReport{Issues: issues}
+}
+
+func firstID(i ValidationIssue) string {
+	if len(i.PeriodIDs) == 0 {
+		return ""
+	}
+	return i.PeriodIDs[0]
+}
+
+// Len returns the number of issues.
+func (r ValidationReport) Len() int {
+	return len(r.Issues)
+}
+
+// HasErrors reports whether the report contains at least one ERROR.
+func (r ValidationReport) HasErrors() bool {
+	for _, i := range r.Issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns a report with the issues matching keep, in the same order.
+func (r ValidationReport) Filter(keep func(ValidationIssue) bool) ValidationReport {
+	out := ValidationReport{Issues: []ValidationIssue{}}
+	for _, i := range r.Issues {
+		if keep(i) {
+			out.Issues = append(out.Issues, i)
+		}
+	}
+	return out
+}
+
+// ByRule returns the issues of one rule, e.g. RuleOverlap.
+func (r ValidationReport) ByRule(rule string) ValidationReport {
+	return r.Filter(func(i ValidationIssue) bool { return i.Rule == rule })
+}
+
+// BySeverity returns the issues of one severity.
+func (r ValidationReport) BySeverity(severity Severity) ValidationReport {
+	return r.Filter(func(i ValidationIssue) bool { return i.Severity == severity })
+}
+
+// ForPeriod returns the issues in which the period is one of the offenders.
+func (r ValidationReport) ForPeriod(periodID string) ValidationReport {
+	return r.Filter(func(i ValidationIssue) bool {
+		for _, id := range i.PeriodIDs {
+			if id == periodID {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Errors returns every issue as an error (one per issue); nil when the report is empty.
+// Use it to feed a report into APIs that work with []error, such as sweep validators.
+func (r ValidationReport) Errors() []error {
+	if len(r.Issues) == 0 {
+		return nil
+	}
+	errs := make([]error, len(r.Issues))
+	for i, issue := range r.Issues {
+		errs[i] = issue
+	}
+	return errs
+}
+
+// Err joins all ERROR issues into a single error, or returns nil when there are none.
+// errors.As still reaches typed causes such as *ParentCycleError.
+func (r ValidationReport) Err() error {
+	return errors.Join(r.BySeverity(SeverityError).Errors()...)
+}
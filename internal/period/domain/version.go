@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+)
+
+// Period versioning
+//
+// Period definitions are effective-dated. A correction (e.g. a fiscal calendar moving its
+// start month) never mutates the existing row. Instead:
+//
+//	v1: FY2026  Apr 2026 – Mar 2027   EffectiveFrom: -        EffectiveTo: 2026-06-30
+//	v2: FY2026  Jul 2026 – Jun 2027   EffectiveFrom: 2026-07-01  EffectiveTo: nil (current)
+//
+// Reports that were produced before the correction can be reproduced by loading the
+// store "as of" their run date (see SelectAsOf / NewPeriodStoreAsOf).
+
+// NextVersion returns a copy of the period with Version incremented and EffectiveFrom set,
+// and closes the receiver by setting its EffectiveTo to the last nanosecond before effectiveFrom.
+// The caller applies the correction to the returned copy.
+//
+// Example:
+//
+//	next, err := fy2026.NextVersion(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), "user@internal.local")
+//	next.StartDate = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+//	next.EndDate = next.StartDate.AddDate(1, 0, 0).Add(-time.Nanosecond)
+func (p *Period) NextVersion(effectiveFrom time.Time, user string) (*Period, error) {
+	if p.EffectiveTo != nil {
+		return nil, fmt.Errorf("period %s version %d is not the current version", p.ID, p.Version)
+	}
+	if !effectiveFrom.After(p.EffectiveFrom) {
+		return nil, fmt.Errorf("new version of period %s must become effective after %s", p.ID, p.EffectiveFrom.Format(time.RFC3339))
+	}
+
+	next := p.Clone()
+	next.Version = p.Version + 1
+	next.EffectiveFrom = effectiveFrom.UTC()
+	next.EffectiveTo = nil
+	if next.Metadata == nil {
+		next.Metadata = make(map[string]string)
+	}
+	next.AuditInfo = audit.NewAuditInfo(user)
+
+	closedAt := effectiveFrom.UTC().Add(-time.Nanosecond)
+	p.EffectiveTo = &closedAt
+
+	return next, nil
+}
+
+// IsEffectiveAt reports whether this version of the period was valid at the given moment.
+func (p *Period) IsEffectiveAt(at time.Time) bool {
+	if at.Before(p.EffectiveFrom) {
+		return false
+	}
+	return p.EffectiveTo == nil || !at.After(*p.EffectiveTo)
+}
+
+// SelectAsOf picks, for each period ID, the version that was effective at asOf.
+// Periods without any version effective at that moment are omitted.
+// The output is sorted by ID for determinism.
+//
+// Example:
+//
+//	current := SelectAsOf(allVersions, time.Now())
+//	lastYear := SelectAsOf(allVersions, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+func SelectAsOf(versions []*Period, asOf time.Time) []*Period {
+	selected := make(map[string]*Period)
+	for _, p := range versions {
+		if p == nil || !p.IsEffectiveAt(asOf) {
+			continue
+		}
+		// Defensive: if histories overlap, the highest version wins
+		if existing, ok := selected[p.ID]; !ok || p.Version > existing.Version {
+			selected[p.ID] = p
+		}
+	}
+
+	result := make([]*Period, 0, len(selected))
+	for _, p := range selected {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// NewPeriodStoreAsOf builds a PeriodStore from all period versions as they were effective at asOf.
+func NewPeriodStoreAsOf(versions []*Period, asOf time.Time) *PeriodStore {
+	return NewPeriodStore(SelectAsOf(versions, asOf))
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+)
+
+// ICalOptions selects what goes into the .ics feed.
+type ICalOptions struct {
+	Name          string                     // Calendar name shown in Outlook, e.g. "CSO trading calendar"
+	Granularities []domain.PeriodGranularity // Period granularities to include; empty means all
+	Holidays      []domain.Holiday
+	CutOffs       []domain.CutOff
+	GeneratedAt   time.Time // DTSTAMP of every event; zero means time.Now()
+}
+
+// WriteICal
+//
+// PURPOSE:
+//
+//	Renders the trading calendar as an iCalendar (RFC 5545) feed that operations can
+//	subscribe to in Outlook. Three kinds of all-day events are written:
+//
+//	  - period boundaries: one event per period, spanning its full date range
+//	  - cut-offs:          one event on the day of each deadline
+//	  - holidays:          one event per non-business day
+//
+//	Event UIDs are derived from the period ID / date, so re-exporting updates
+//	existing events in subscribed calendars instead of duplicating them.
+//
+// EXAMPLE USAGE:
+//
+//	err := export.WriteICal(w, store.All(), export.ICalOptions{
+//	    Name:          "CSO trading calendar",
+//	    Granularities: []domain.PeriodGranularity{domain.MonthlyPeriod, domain.QuarterlyPeriod},
+//	    CutOffs:       cutOffs,
+//	    Holidays:      holidays.Holidays(),
+//	})
+//
+// EXAMPLE OUTPUT (one event):
+//
+//	BEGIN:VEVENT
+//	UID:period-2026-Q1@cso-book
+//	DTSTAMP:20260101T000000Z
+//	DTSTART;VALUE=DATE:20260101
+//	DTEND;VALUE=DATE:20260401
+//	SUMMARY:Q1 2026 (CAL QUARTERLY)
+//	CATEGORIES:PERIOD
+//	END:VEVENT
+func WriteICal(w io.Writer, periods []*domain.Period, opts ICalOptions) error {
+	stamp := opts.GeneratedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	include := make(map[domain.PeriodGranularity]bool, len(opts.Granularities))
+	for _, g := range opts.Granularities {
+		include[g] = true
+	}
+
+	iw := &icalWriter{w: bufio.NewWriter(w), stamp: stamp.UTC().Format("20060102T150405Z")}
+
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//nholding//cso-book//EN")
+	iw.line("CALSCALE:GREGORIAN")
+	iw.line("METHOD:PUBLISH")
+	if opts.Name != "" {
+		iw.line("X-WR-CALNAME:" + escapeText(opts.Name))
+	}
+
+	for _, p := range periods {
+		if p == nil || (len(include) > 0 && !include[p.Granularity]) {
+			continue
+		}
+		// DTEND of an all-day event is exclusive: the day after the period's last day
+		iw.event("period-"+p.ID, p.StartDate, p.EndDate.AddDate(0, 0, 1),
+			fmt.Sprintf("%s (%s %s)", p.Name, p.Calendar, p.Granularity), "PERIOD")
+	}
+
+	for _, c := range opts.CutOffs {
+		iw.event("cutoff-"+c.PeriodID+"-"+c.Date.Format("20060102"), c.Date, c.Date.AddDate(0, 0, 1),
+			fmt.Sprintf("%s: %s", c.Name, c.PeriodID), "CUT-OFF")
+	}
+
+	for _, h := range opts.Holidays {
+		iw.event("holiday-"+h.Date.UTC().Format("20060102"), h.Date, h.Date.AddDate(0, 0, 1),
+			h.Name, "HOLIDAY")
+	}
+
+	iw.line("END:VCALENDAR")
+
+	if iw.err != nil {
+		return fmt.Errorf("failed to write iCal feed: %w", iw.err)
+	}
+	if err := iw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write iCal feed: %w", err)
+	}
+	return nil
+}
+
+// icalWriter writes CRLF-terminated, folded content lines and remembers the first error.
+type icalWriter struct {
+	w     *bufio.Writer
+	stamp string
+	err   error
+}
+
+func (iw *icalWriter) event(uid string, start, end time.Time, summary, category string) {
+	iw.line("BEGIN:VEVENT")
+	iw.line("UID:" + escapeText(uid) + "@cso-book")
+	iw.line("DTSTAMP:" + iw.stamp)
+	iw.line("DTSTART;VALUE=DATE:" + start.UTC().Format("20060102"))
+	iw.line("DTEND;VALUE=DATE:" + end.UTC().Format("20060102"))
+	iw.line("SUMMARY:" + escapeText(summary))
+	iw.line("CATEGORIES:" + category)
+	iw.line("TRANSP:TRANSPARENT")
+	iw.line("END:VEVENT")
+}
+
+// line writes one content line, folded at 75 octets as required by RFC 5545 §3.1.
+func (iw *icalWriter) line(s string) {
+	if iw.err != nil {
+		return
+	}
+
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		// Never split a multi-byte UTF-8 sequence
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, iw.err = iw.w.WriteString(s[:cut] + "\r\n "); iw.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	_, iw.err = iw.w.WriteString(s + "\r\n")
+}
+
+// escapeText escapes a TEXT value (RFC 5545 §3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
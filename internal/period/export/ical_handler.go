@@ -0,0 +1,60 @@
+package export
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+)
+
+// ICalHandler serves the trading calendar as a subscribable .ics feed.
+//
+// The feed is rendered on every request from the live PeriodStore, so newly generated
+// fiscal years and status changes appear on the next Outlook refresh.
+//
+// Example:
+//
+//	mux.Handle("GET /calendar.ics", export.NewICalHandler(store, cal, rules, export.ICalOptions{
+//	    Name: "CSO trading calendar",
+//	}))
+type ICalHandler struct {
+	store    *domain.PeriodStore
+	holidays *domain.HolidayCalendar
+	rules    []domain.CutOffRule
+	opts     ICalOptions
+}
+
+// NewICalHandler creates the feed handler. holidays may be nil when no holiday calendar is
+// configured; cut-offs are then computed on weekdays only.
+func NewICalHandler(store *domain.PeriodStore, holidays *domain.HolidayCalendar, rules []domain.CutOffRule, opts ICalOptions) *ICalHandler {
+	if holidays == nil {
+		holidays = domain.NewHolidayCalendar()
+	}
+	return &ICalHandler{store: store, holidays: holidays, rules: rules, opts: opts}
+}
+
+func (h *ICalHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	periods := h.store.All()
+
+	opts := h.opts
+	opts.Holidays = h.holidays.Holidays()
+	for _, rule := range h.rules {
+		cutOffs, err := h.holidays.CutOffs(rule, periods)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		opts.CutOffs = append(opts.CutOffs, cutOffs...)
+	}
+
+	// Render into a buffer first, so a failure still produces a proper error response
+	var buf bytes.Buffer
+	if err := WriteICal(&buf, periods, opts); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="trading-calendar.ics"`)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+)
+
+// periodTree is the Year → Quarter → Month forest built from ParentPeriodID links
+// (ChildPeriodIDs are not persisted, so parent links are the source of truth).
+//
+// Fiscal quarters do not own months; their months are attached as OVERLAY children,
+// selected by date range exactly like ValidateFiscalCoverage does.
+type periodTree struct {
+	roots    []*domain.Period
+	children map[string][]*domain.Period
+	overlays map[string][]*domain.Period
+}
+
+func buildPeriodTree(periods []*domain.Period) periodTree {
+	t := periodTree{
+		children: make(map[string][]*domain.Period),
+		overlays: make(map[string][]*domain.Period),
+	}
+
+	byID := make(map[string]*domain.Period, len(periods))
+	var months []*domain.Period
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		byID[p.ID] = p
+		if p.Granularity == domain.MonthlyPeriod {
+			months = append(months, p)
+		}
+	}
+
+	for _, p := range byID {
+		if p.ParentPeriodID != nil && byID[*p.ParentPeriodID] != nil {
+			t.children[*p.ParentPeriodID] = append(t.children[*p.ParentPeriodID], p)
+		} else {
+			t.roots = append(t.roots, p)
+		}
+
+		// Fiscal (FY) quarters do not own months; attach them by date
+		if p.Calendar == domain.CalendarFiscal && p.Granularity == domain.QuarterlyPeriod {
+			for _, m := range months {
+				if !m.StartDate.Before(p.StartDate) && !m.EndDate.After(p.EndDate) {
+					t.overlays[p.ID] = append(t.overlays[p.ID], m)
+				}
+			}
+		}
+	}
+
+	sortChronologically(t.roots)
+	for _, list := range t.children {
+		sortChronologically(list)
+	}
+	for _, list := range t.overlays {
+		sortChronologically(list)
+	}
+
+	return t
+}
+
+// sortChronologically orders by calendar (CAL before FY before FW), then start date, then ID,
+// so Gregorian and fiscal trees are printed as separate blocks.
+func sortChronologically(list []*domain.Period) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Calendar != list[j].Calendar {
+			return calendarOrder(list[i].Calendar) < calendarOrder(list[j].Calendar)
+		}
+		if !list[i].StartDate.Equal(list[j].StartDate) {
+			return list[i].StartDate.Before(list[j].StartDate)
+		}
+		return list[i].ID < list[j].ID
+	})
+}
+
+func calendarOrder(c domain.CalendarType) int {
+	switch c {
+	case domain.CalendarGregorian:
+		return 0
+	case domain.CalendarFiscal:
+		return 1
+	case domain.CalendarFiscalWeek:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// WriteASCIITree renders the period hierarchy as an indented ASCII tree. Months that belong
+// to a fiscal quarter by date only (overlay) are marked with "~".
+//
+// EXAMPLE OUTPUT:
+//
+//	2026 [CAL CALENDAR] 2026-01-01 → 2026-12-31
+//	├── 2026-Q1 [CAL QUARTERLY] 2026-01-01 → 2026-03-31
+//	│   ├── 2026-JAN [CAL MONTHLY] 2026-01-01 → 2026-01-31
+//	│   ├── 2026-FEB [CAL MONTHLY] 2026-02-01 → 2026-02-28
+//	│   └── 2026-MAR [CAL MONTHLY] 2026-03-01 → 2026-03-31
+//	...
+//	FY2026 [FY CALENDAR] 2026-04-01 → 2027-03-31
+//	├── FY2026-Q1 [FY QUARTERLY] 2026-04-01 → 2026-06-30
+//	│   ├── ~ 2026-APR [CAL MONTHLY] 2026-04-01 → 2026-04-30
+func WriteASCIITree(w io.Writer, periods []*domain.Period) error {
+	t := buildPeriodTree(periods)
+	bw := bufio.NewWriter(w)
+
+	var walk func(p *domain.Period, prefix string, last, overlay, root bool)
+	walk = func(p *domain.Period, prefix string, last, overlay, root bool) {
+		branch, childPrefix := "├── ", prefix+"│   "
+		if last {
+			branch, childPrefix = "└── ", prefix+"    "
+		}
+		if root {
+			branch, childPrefix = "", ""
+		}
+		marker := ""
+		if overlay {
+			marker = "~ "
+		}
+
+		fmt.Fprintf(bw, "%s%s%s%s\n", prefix, branch, marker, describe(p))
+
+		kids := t.children[p.ID]
+		over := t.overlays[p.ID]
+		for i, c := range kids {
+			walk(c, childPrefix, i == len(kids)-1 && len(over) == 0, false, false)
+		}
+		for i, m := range over {
+			walk(m, childPrefix, i == len(over)-1, true, false)
+		}
+	}
+
+	for _, r := range t.roots {
+		walk(r, "", true, false, true)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write period tree: %w", err)
+	}
+	return nil
+}
+
+// WriteDOT renders the period hierarchy as a Graphviz DOT digraph. Parent links are solid
+// edges; fiscal overlay months are dashed edges. Render with e.g. `dot -Tsvg tree.dot`.
+func WriteDOT(w io.Writer, periods []*domain.Period) error {
+	t := buildPeriodTree(periods)
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph periods {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	fmt.Fprintln(bw, "  node [shape=box, fontname=\"Helvetica\"];")
+
+	var walk func(p *domain.Period)
+	walk = func(p *domain.Period) {
+		fmt.Fprintf(bw, "  %s [label=%s, style=filled, fillcolor=%s];\n",
+			dotID(p.ID), dotID(p.ID+"\n"+fmtDay(p)), dotColor(p.Calendar))
+		for _, c := range t.children[p.ID] {
+			fmt.Fprintf(bw, "  %s -> %s;\n", dotID(p.ID), dotID(c.ID))
+			walk(c)
+		}
+		for _, m := range t.overlays[p.ID] {
+			fmt.Fprintf(bw, "  %s -> %s [style=dashed];\n", dotID(p.ID), dotID(m.ID))
+		}
+	}
+	for _, r := range t.roots {
+		walk(r)
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write period graph: %w", err)
+	}
+	return nil
+}
+
+func describe(p *domain.Period) string {
+	return fmt.Sprintf("%s [%s %s] %s", p.ID, p.Calendar, p.Granularity, fmtDay(p))
+}
+
+func fmtDay(p *domain.Period) string {
+	return p.StartDate.Format("2006-01-02") + " → " + p.EndDate.Format("2006-01-02")
+}
+
+// dotID quotes an identifier or label for DOT.
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func dotColor(c domain.CalendarType) string {
+	switch c {
+	case domain.CalendarFiscal:
+		return "lightgoldenrod"
+	case domain.CalendarFiscalWeek:
+		return "lightpink"
+	default:
+		return "lightblue"
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/chaos"
+)
+
+// ChaosPeriodRepository decorates a PeriodRepository with injected latency and failures,
+// so retry, timeout and degradation behavior of services can be exercised without breaking
+// a real database. In builds with the "prod" tag the injector is a no-op.
+//
+// Example:
+//
+//	repo := NewChaosPeriodRepository(rdsRepo, chaos.Config{
+//	    Latency:   250 * time.Millisecond,
+//	    ErrorRate: 0.1,
+//	    Overrides: map[string]float64{"SavePeriods": 1}, // every save fails
+//	})
+type ChaosPeriodRepository struct {
+	inner    PeriodRepository
+	injector *chaos.Injector
+}
+
+var _ PeriodRepository = (*ChaosPeriodRepository)(nil)
+
+func NewChaosPeriodRepository(inner PeriodRepository, cfg chaos.Config) *ChaosPeriodRepository {
+	return &ChaosPeriodRepository{
+		inner:    inner,
+		injector: chaos.NewInjector(cfg),
+	}
+}
+
+func (c *ChaosPeriodRepository) SavePeriods(ctx context.Context, periods []*domain.Period) error {
+	if err := c.injector.Before(ctx, "SavePeriods"); err != nil {
+		return err
+	}
+	return c.inner.SavePeriods(ctx, periods)
+}
+
+func (c *ChaosPeriodRepository) UpsertPeriods(ctx context.Context, periods []*domain.Period) error {
+	if err := c.injector.Before(ctx, "UpsertPeriods"); err != nil {
+		return err
+	}
+	return c.inner.UpsertPeriods(ctx, periods)
+}
+
+func (c *ChaosPeriodRepository) UpdatePeriods(ctx context.Context, periods []*domain.Period) error {
+	if err := c.injector.Before(ctx, "UpdatePeriods"); err != nil {
+		return err
+	}
+	return c.inner.UpdatePeriods(ctx, periods)
+}
+
+func (c *ChaosPeriodRepository) GetAllPeriods(ctx context.Context) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetAllPeriods"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetAllPeriods(ctx)
+}
+
+func (c *ChaosPeriodRepository) ForEachPeriod(ctx context.Context, fn func(p *domain.Period) error) error {
+	if err := c.injector.Before(ctx, "ForEachPeriod"); err != nil {
+		return err
+	}
+	return c.inner.ForEachPeriod(ctx, fn)
+}
+
+func (c *ChaosPeriodRepository) GetAllPeriodVersions(ctx context.Context) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetAllPeriodVersions"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetAllPeriodVersions(ctx)
+}
+
+func (c *ChaosPeriodRepository) GetPeriodsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetPeriodsAsOf"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetPeriodsAsOf(ctx, asOf)
+}
+
+func (c *ChaosPeriodRepository) SavePeriodVersion(ctx context.Context, closed *domain.Period, next *domain.Period) error {
+	if err := c.injector.Before(ctx, "SavePeriodVersion"); err != nil {
+		return err
+	}
+	return c.inner.SavePeriodVersion(ctx, closed, next)
+}
+
+func (c *ChaosPeriodRepository) SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error {
+	if err := c.injector.Before(ctx, "SetPeriodStatus"); err != nil {
+		return err
+	}
+	return c.inner.SetPeriodStatus(ctx, periodID, status, updatedBy)
+}
+
+func (c *ChaosPeriodRepository) FindByID(ctx context.Context, id string) (*domain.Period, error) {
+	if err := c.injector.Before(ctx, "FindByID"); err != nil {
+		return nil, err
+	}
+	return c.inner.FindByID(ctx, id)
+}
+
+func (c *ChaosPeriodRepository) GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetPeriodsStartingBetween"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetPeriodsStartingBetween(ctx, from, to)
+}
+
+func (c *ChaosPeriodRepository) GetPeriodsByGranularity(ctx context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetPeriodsByGranularity"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetPeriodsByGranularity(ctx, granularity)
+}
+
+func (c *ChaosPeriodRepository) GetPeriodsByCalendar(ctx context.Context, calendar domain.CalendarType) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetPeriodsByCalendar"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetPeriodsByCalendar(ctx, calendar)
+}
+
+func (c *ChaosPeriodRepository) GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetPeriodsInRange"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetPeriodsInRange(ctx, start, end, granularity)
+}
+
+func (c *ChaosPeriodRepository) SoftDeletePeriod(ctx context.Context, periodID string, deletedBy string) error {
+	if err := c.injector.Before(ctx, "SoftDeletePeriod"); err != nil {
+		return err
+	}
+	return c.inner.SoftDeletePeriod(ctx, periodID, deletedBy)
+}
+
+func (c *ChaosPeriodRepository) RestorePeriod(ctx context.Context, periodID string, restoredBy string) (*domain.Period, error) {
+	if err := c.injector.Before(ctx, "RestorePeriod"); err != nil {
+		return nil, err
+	}
+	return c.inner.RestorePeriod(ctx, periodID, restoredBy)
+}
+
+func (c *ChaosPeriodRepository) GetDeletedPeriods(ctx context.Context) ([]*domain.Period, error) {
+	if err := c.injector.Before(ctx, "GetDeletedPeriods"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetDeletedPeriods(ctx)
+}
+
+func (c *ChaosPeriodRepository) SavePeriodMetadata(ctx context.Context, periodID string, metadata map[string]string) error {
+	if err := c.injector.Before(ctx, "SavePeriodMetadata"); err != nil {
+		return err
+	}
+	return c.inner.SavePeriodMetadata(ctx, periodID, metadata)
+}
+
+func (c *ChaosPeriodRepository) GetAllPeriodMetadata(ctx context.Context) (map[string]map[string]string, error) {
+	if err := c.injector.Before(ctx, "GetAllPeriodMetadata"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetAllPeriodMetadata(ctx)
+}
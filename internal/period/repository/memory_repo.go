@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+)
+
+// InMemoryPeriodRepository is a PeriodRepository that keeps all rows in memory, for unit tests
+// and local development without RDS. It mirrors the semantics of RdsPeriodRepository:
+//
+//   - every version of a Period is kept; the current version has EffectiveTo == nil
+//   - soft-deleted Periods are excluded from all "current" queries
+//   - ChildPeriodIDs are NOT stored, exactly like in the DB
+//   - metadata lives in a separate side table and is replaced as a whole
+//
+// Periods are copied on the way in and on the way out, so callers can never modify stored
+// rows through a pointer they hold. It is safe for concurrent use.
+//
+// Example:
+//
+//	repo := NewInMemoryPeriodRepository()
+//	ps := service.NewPeriodService(repo)
+//	err := ps.InitializePeriods(ctx, 2026, 2027, nil)
+type InMemoryPeriodRepository struct {
+	mu       sync.RWMutex
+	rows     []*domain.Period // All versions of all Periods, in insertion order
+	metadata map[string]map[string]string
+}
+
+var _ PeriodRepository = (*InMemoryPeriodRepository)(nil)
+
+func NewInMemoryPeriodRepository() *InMemoryPeriodRepository {
+	return &InMemoryPeriodRepository{metadata: make(map[string]map[string]string)}
+}
+
+// SavePeriods inserts new Periods. Like the DB primary key, it fails when a Period with the
+// same ID and version already exists; nothing is saved in that case.
+func (r *InMemoryPeriodRepository) SavePeriods(_ context.Context, periods []*domain.Period) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Validate everything first, so a failure leaves the repository untouched (like a rollback)
+	seen := make(map[string]bool, len(periods))
+	var rows []*domain.Period
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("period %s validation failed: %w", p.ID, err)
+		}
+
+		row := toRow(p)
+		row.Version = versionOrDefault(row.Version)
+		row.RowVersion = 1
+		key := fmt.Sprintf("%s/%d", row.ID, row.Version)
+		if seen[key] || r.findLocked(row.ID, row.Version) != nil {
+			return &dberr.DuplicateError{Entity: "period", ID: p.ID}
+		}
+		seen[key] = true
+		rows = append(rows, row)
+	}
+
+	for _, row := range rows {
+		r.rows = append(r.rows, row)
+		if len(row.Metadata) > 0 {
+			r.metadata[row.ID] = copyMetadata(row.Metadata)
+		}
+		row.Metadata = nil
+	}
+
+	return nil
+}
+
+// UpsertPeriods inserts new Periods and updates the definition of existing ones (same ID and
+// version). Like the RDS repository, it fails for superseded versions and upserts metadata
+// per key.
+func (r *InMemoryPeriodRepository) UpsertPeriods(_ context.Context, periods []*domain.Period) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("period %s validation failed: %w", p.ID, err)
+		}
+		if existing := r.findLocked(p.ID, versionOrDefault(p.Version)); existing != nil && existing.EffectiveTo != nil {
+			return fmt.Errorf("period %s version %d is superseded and cannot be overwritten", p.ID, existing.Version)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+
+		row := toRow(p)
+		row.Version = versionOrDefault(row.Version)
+		metadata := row.Metadata
+		row.Metadata = nil
+
+		if existing := r.findLocked(row.ID, row.Version); existing != nil {
+			existing.Name = row.Name
+			existing.Calendar = row.Calendar
+			existing.Granularity = row.Granularity
+			existing.ParentPeriodID = row.ParentPeriodID
+			existing.StartDate = row.StartDate
+			existing.EndDate = row.EndDate
+			existing.RowVersion = versionOrDefault(existing.RowVersion) + 1
+			if existing.AuditInfo != nil && row.AuditInfo != nil {
+				audit := *existing.AuditInfo
+				updatedBy := row.AuditInfo.LastActor()
+				audit.UpdatedBy = &updatedBy
+				audit.UpdatedAt = &now
+				existing.AuditInfo = &audit
+			}
+		} else {
+			row.RowVersion = 1
+			r.rows = append(r.rows, row)
+		}
+
+		for key, value := range metadata {
+			if r.metadata[row.ID] == nil {
+				r.metadata[row.ID] = make(map[string]string)
+			}
+			r.metadata[row.ID][key] = value
+		}
+	}
+
+	return nil
+}
+
+// UpdatePeriods updates the definition of the current version of existing Periods in place.
+// Like the RDS repository, every Period must carry the RowVersion it was read with; a stale
+// one fails the whole call with a *dberr.ConflictError.
+func (r *InMemoryPeriodRepository) UpdatePeriods(_ context.Context, periods []*domain.Period) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range periods {
+		row := r.currentLocked(p.ID)
+		if row == nil {
+			return &dberr.NotFoundError{Entity: "period", ID: p.ID}
+		}
+		expected, actual := versionOrDefault(p.RowVersion), versionOrDefault(row.RowVersion)
+		if expected != actual {
+			return &dberr.ConflictError{Entity: "period", ID: p.ID, ExpectedVersion: expected, ActualVersion: actual}
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, p := range periods {
+		row := r.currentLocked(p.ID)
+		row.Name = p.Name
+		row.Granularity = p.Granularity
+		row.ParentPeriodID = copyString(p.ParentPeriodID)
+		row.StartDate = p.StartDate
+		row.EndDate = p.EndDate
+		row.RowVersion = versionOrDefault(row.RowVersion) + 1
+		p.RowVersion = row.RowVersion
+		if row.AuditInfo != nil {
+			audit := *row.AuditInfo
+			if updatedBy := p.AuditInfo.LastActor(); updatedBy != "" {
+				audit.UpdatedBy = &updatedBy
+			}
+			audit.UpdatedAt = &now
+			row.AuditInfo = &audit
+		}
+	}
+
+	return nil
+}
+
+func (r *InMemoryPeriodRepository) GetAllPeriods(_ context.Context) ([]*domain.Period, error) {
+	return r.query(func(p *domain.Period) bool { return isCurrent(p) }), nil
+}
+
+// ForEachPeriod calls fn for the current version of every Period in chronological order.
+// It works on a snapshot, so fn may write to the repository.
+func (r *InMemoryPeriodRepository) ForEachPeriod(_ context.Context, fn func(p *domain.Period) error) error {
+	periods := r.query(func(p *domain.Period) bool { return isCurrent(p) })
+	sort.SliceStable(periods, func(i, j int) bool {
+		if !periods[i].StartDate.Equal(periods[j].StartDate) {
+			return periods[i].StartDate.Before(periods[j].StartDate)
+		}
+		return periods[i].ID < periods[j].ID
+	})
+
+	for _, p := range periods {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryPeriodRepository) GetAllPeriodVersions(_ context.Context) ([]*domain.Period, error) {
+	periods := r.query(func(p *domain.Period) bool { return true })
+	sort.SliceStable(periods, func(i, j int) bool {
+		if periods[i].ID != periods[j].ID {
+			return periods[i].ID < periods[j].ID
+		}
+		return periods[i].Version < periods[j].Version
+	})
+	return periods, nil
+}
+
+func (r *InMemoryPeriodRepository) GetPeriodsAsOf(_ context.Context, asOf time.Time) ([]*domain.Period, error) {
+	return r.query(func(p *domain.Period) bool {
+		return !p.EffectiveFrom.After(asOf) && (p.EffectiveTo == nil || !p.EffectiveTo.Before(asOf))
+	}), nil
+}
+
+func (r *InMemoryPeriodRepository) SavePeriodVersion(_ context.Context, closed *domain.Period, next *domain.Period) error {
+	if closed.EffectiveTo == nil {
+		return fmt.Errorf("period %s version %d must be closed before saving version %d", closed.ID, closed.Version, next.Version)
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("period %s version %d validation failed: %w", next.ID, next.Version, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.currentLocked(closed.ID)
+	if current == nil || current.Version != closed.Version {
+		return fmt.Errorf("period %s version %d is not the current version", closed.ID, closed.Version)
+	}
+	if r.findLocked(next.ID, next.Version) != nil {
+		return &dberr.DuplicateError{Entity: "period", ID: next.ID, Key: fmt.Sprintf("version %d", next.Version)}
+	}
+
+	effectiveTo := *closed.EffectiveTo
+	current.EffectiveTo = &effectiveTo
+
+	row := toRow(next)
+	row.Metadata = nil // Metadata is not versioned
+	r.rows = append(r.rows, row)
+
+	return nil
+}
+
+func (r *InMemoryPeriodRepository) SetPeriodStatus(_ context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row := r.currentLocked(periodID)
+	if row == nil {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
+
+	row.Status = status
+	if row.AuditInfo != nil {
+		audit := *row.AuditInfo
+		audit.UpdateAuditInfo(updatedBy)
+		row.AuditInfo = &audit
+	}
+	return nil
+}
+
+// FindByID returns the current version of a Period; nil, nil when it does not exist.
+func (r *InMemoryPeriodRepository) FindByID(_ context.Context, id string) (*domain.Period, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row := r.currentLocked(id)
+	if row == nil || row.IsDeleted() {
+		return nil, nil // Not found
+	}
+	return r.fromRowLocked(row), nil
+}
+
+func (r *InMemoryPeriodRepository) GetPeriodsStartingBetween(_ context.Context, from, to time.Time) ([]*domain.Period, error) {
+	return r.query(func(p *domain.Period) bool {
+		return isCurrent(p) && !p.StartDate.Before(from) && p.StartDate.Before(to)
+	}), nil
+}
+
+func (r *InMemoryPeriodRepository) GetPeriodsByGranularity(_ context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	return r.query(func(p *domain.Period) bool {
+		return isCurrent(p) && p.Granularity == granularity
+	}), nil
+}
+
+func (r *InMemoryPeriodRepository) GetPeriodsByCalendar(_ context.Context, calendar domain.CalendarType) ([]*domain.Period, error) {
+	return r.query(func(p *domain.Period) bool {
+		return isCurrent(p) && p.Calendar == calendar
+	}), nil
+}
+
+func (r *InMemoryPeriodRepository) GetPeriodsInRange(_ context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("invalid period window: end %s is before start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	return r.query(func(p *domain.Period) bool {
+		return isCurrent(p) && (granularity == "" || p.Granularity == granularity) &&
+			!p.StartDate.After(end) && !p.EndDate.Before(start)
+	}), nil
+}
+
+func (r *InMemoryPeriodRepository) SoftDeletePeriod(_ context.Context, periodID string, deletedBy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row := r.currentLocked(periodID)
+	if row == nil || row.IsDeleted() {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID, Reason: "does not exist or is already deleted"}
+	}
+
+	now := time.Now().UTC()
+	row.DeletedAt = &now
+	row.DeletedBy = &deletedBy
+	return nil
+}
+
+func (r *InMemoryPeriodRepository) RestorePeriod(_ context.Context, periodID string, restoredBy string) (*domain.Period, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row := r.currentLocked(periodID)
+	if row == nil || !row.IsDeleted() {
+		return nil, &dberr.NotFoundError{Entity: "period", ID: periodID, Reason: "does not exist or is not deleted"}
+	}
+
+	row.DeletedAt = nil
+	row.DeletedBy = nil
+	if row.AuditInfo != nil {
+		audit := *row.AuditInfo
+		audit.UpdateAuditInfo(restoredBy)
+		row.AuditInfo = &audit
+	}
+	return r.fromRowLocked(row), nil
+}
+
+func (r *InMemoryPeriodRepository) GetDeletedPeriods(_ context.Context) ([]*domain.Period, error) {
+	return r.query(func(p *domain.Period) bool { return p.EffectiveTo == nil && p.IsDeleted() }), nil
+}
+
+func (r *InMemoryPeriodRepository) SavePeriodMetadata(_ context.Context, periodID string, metadata map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(metadata) == 0 {
+		delete(r.metadata, periodID)
+		return nil
+	}
+	r.metadata[periodID] = copyMetadata(metadata)
+	return nil
+}
+
+func (r *InMemoryPeriodRepository) GetAllPeriodMetadata(_ context.Context) (map[string]map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]map[string]string, len(r.metadata))
+	for id, m := range r.metadata {
+		out[id] = copyMetadata(m)
+	}
+	return out, nil
+}
+
+// query returns copies of all rows matching match, with their metadata attached.
+func (r *InMemoryPeriodRepository) query(match func(p *domain.Period) bool) []*domain.Period {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var periods []*domain.Period
+	for _, row := range r.rows {
+		if match(row) {
+			periods = append(periods, r.fromRowLocked(row))
+		}
+	}
+	return periods
+}
+
+func (r *InMemoryPeriodRepository) currentLocked(id string) *domain.Period {
+	for _, row := range r.rows {
+		if row.ID == id && row.EffectiveTo == nil {
+			return row
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryPeriodRepository) findLocked(id string, version int) *domain.Period {
+	for _, row := range r.rows {
+		if row.ID == id && row.Version == version {
+			return row
+		}
+	}
+	return nil
+}
+
+// isCurrent reports whether a stored row is the live, current version of its Period.
+func isCurrent(p *domain.Period) bool {
+	return p.EffectiveTo == nil && !p.IsDeleted()
+}
+
+func (r *InMemoryPeriodRepository) fromRowLocked(row *domain.Period) *domain.Period {
+	p := row.Clone()
+	p.Metadata = copyMetadata(r.metadata[row.ID])
+	return p
+}
+
+// toRow copies a Period into its stored form: no ChildPeriodIDs and an effective status,
+// like the columns of the periods table.
+func toRow(p *domain.Period) *domain.Period {
+	row := p.Clone()
+	row.ChildPeriodIDs = nil
+	row.Status = p.EffectiveStatus()
+	return row
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func copyMetadata(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
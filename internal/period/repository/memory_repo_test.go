@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+)
+
+func TestInMemoryPeriodRepository(t *testing.T) {
+	testPeriodRepository(t, func(t *testing.T) PeriodRepository {
+		return NewInMemoryPeriodRepository()
+	})
+}
+
+func TestInMemoryPeriodRepositoryCopiesPeriods(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPeriodRepository()
+
+	periods := domain.GeneratePeriods(2026, 2026)
+	if err := repo.SavePeriods(ctx, periods); err != nil {
+		t.Fatalf("SavePeriods: %v", err)
+	}
+	periods[0].Name = "changed after saving"
+
+	found, err := repo.FindByID(ctx, periods[0].ID)
+	if err != nil || found == nil {
+		t.Fatalf("FindByID: %v, %v", found, err)
+	}
+	found.Name = "changed after reading"
+	found.SetTag("leaked", "true")
+
+	again, _ := repo.FindByID(ctx, periods[0].ID)
+	if again.Name != "2026" {
+		t.Errorf("stored name = %q, want 2026", again.Name)
+	}
+	if _, ok := again.Tag("leaked"); ok {
+		t.Error("metadata set on a returned period reached the repository")
+	}
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/metrics"
+)
+
+// MetricsPeriodRepository decorates a PeriodRepository with Prometheus metrics: latency,
+// outcome and row count of every operation (see metrics.RepositoryMetrics), labelled
+// repository="period". Comparing the repository latency with the duration of e.g. startup
+// shows whether time is spent in the DB or in the application.
+//
+// Example:
+//
+//	m := metrics.NewRepositoryMetrics(prometheus.DefaultRegisterer)
+//	repo := NewMetricsPeriodRepository(rdsRepo, m)
+type MetricsPeriodRepository struct {
+	inner   PeriodRepository
+	metrics *metrics.RepositoryMetrics
+}
+
+var _ PeriodRepository = (*MetricsPeriodRepository)(nil)
+
+func NewMetricsPeriodRepository(inner PeriodRepository, m *metrics.RepositoryMetrics) *MetricsPeriodRepository {
+	return &MetricsPeriodRepository{
+		inner:   inner,
+		metrics: m,
+	}
+}
+
+func (m *MetricsPeriodRepository) observe(op string, start time.Time, rows int, err error) {
+	m.metrics.Observe("period", op, start, rows, err)
+}
+
+func (m *MetricsPeriodRepository) SavePeriods(ctx context.Context, periods []*domain.Period) error {
+	began := time.Now()
+	err := m.inner.SavePeriods(ctx, periods)
+	m.observe("SavePeriods", began, len(periods), err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) UpsertPeriods(ctx context.Context, periods []*domain.Period) error {
+	began := time.Now()
+	err := m.inner.UpsertPeriods(ctx, periods)
+	m.observe("UpsertPeriods", began, len(periods), err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) UpdatePeriods(ctx context.Context, periods []*domain.Period) error {
+	began := time.Now()
+	err := m.inner.UpdatePeriods(ctx, periods)
+	m.observe("UpdatePeriods", began, len(periods), err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) GetAllPeriods(ctx context.Context) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetAllPeriods(ctx)
+	m.observe("GetAllPeriods", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) ForEachPeriod(ctx context.Context, fn func(p *domain.Period) error) error {
+	began, rows := time.Now(), 0
+	err := m.inner.ForEachPeriod(ctx, func(p *domain.Period) error {
+		rows++
+		return fn(p)
+	})
+	m.observe("ForEachPeriod", began, rows, err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) GetAllPeriodVersions(ctx context.Context) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetAllPeriodVersions(ctx)
+	m.observe("GetAllPeriodVersions", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) GetPeriodsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetPeriodsAsOf(ctx, asOf)
+	m.observe("GetPeriodsAsOf", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) SavePeriodVersion(ctx context.Context, closed *domain.Period, next *domain.Period) error {
+	began := time.Now()
+	err := m.inner.SavePeriodVersion(ctx, closed, next)
+	m.observe("SavePeriodVersion", began, 2, err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error {
+	began := time.Now()
+	err := m.inner.SetPeriodStatus(ctx, periodID, status, updatedBy)
+	m.observe("SetPeriodStatus", began, 1, err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) FindByID(ctx context.Context, id string) (*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.FindByID(ctx, id)
+	rows := 0
+	if result != nil {
+		rows = 1
+	}
+	m.observe("FindByID", began, rows, err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetPeriodsStartingBetween(ctx, from, to)
+	m.observe("GetPeriodsStartingBetween", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) GetPeriodsByGranularity(ctx context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetPeriodsByGranularity(ctx, granularity)
+	m.observe("GetPeriodsByGranularity", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) GetPeriodsByCalendar(ctx context.Context, calendar domain.CalendarType) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetPeriodsByCalendar(ctx, calendar)
+	m.observe("GetPeriodsByCalendar", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetPeriodsInRange(ctx, start, end, granularity)
+	m.observe("GetPeriodsInRange", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) SoftDeletePeriod(ctx context.Context, periodID string, deletedBy string) error {
+	began := time.Now()
+	err := m.inner.SoftDeletePeriod(ctx, periodID, deletedBy)
+	m.observe("SoftDeletePeriod", began, 1, err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) RestorePeriod(ctx context.Context, periodID string, restoredBy string) (*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.RestorePeriod(ctx, periodID, restoredBy)
+	m.observe("RestorePeriod", began, 1, err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) GetDeletedPeriods(ctx context.Context) ([]*domain.Period, error) {
+	began := time.Now()
+	result, err := m.inner.GetDeletedPeriods(ctx)
+	m.observe("GetDeletedPeriods", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsPeriodRepository) SavePeriodMetadata(ctx context.Context, periodID string, metadata map[string]string) error {
+	began := time.Now()
+	err := m.inner.SavePeriodMetadata(ctx, periodID, metadata)
+	m.observe("SavePeriodMetadata", began, 1, err)
+	return err
+}
+
+func (m *MetricsPeriodRepository) GetAllPeriodMetadata(ctx context.Context) (map[string]map[string]string, error) {
+	began := time.Now()
+	result, err := m.inner.GetAllPeriodMetadata(ctx)
+	m.observe("GetAllPeriodMetadata", began, len(result), err)
+	return result, err
+}
@@ -4,41 +4,114 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	//	"strings"
+	"strings"
 	"time"
 
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
 	"github.com/nholding/cso-book/internal/period/domain"
 	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/pgbulk"
+	"github.com/nholding/cso-book/internal/platform/txn"
 )
 
 // PeriodRepository defines the interface for storing and retrieving Periods from a persistence layer
 type PeriodRepository interface {
 	// SavePeriods persists Periods. NOTE: ChildPeriodIDs are NOT stored in the DB.
-	SavePeriods(ctx context.Context, periods []domain.Period) error
+	SavePeriods(ctx context.Context, periods []*domain.Period) error
 
-	// GetAllPeriods retrieves all Periods from the DB
-	GetAllPeriods(ctx context.Context) ([]domain.Period, error)
+	// UpsertPeriods inserts Periods, or updates the definition of those that already exist
+	UpsertPeriods(ctx context.Context, periods []*domain.Period) error
 
+	// UpdatePeriods updates existing Periods in place, checked against their RowVersion
+	// (optimistic concurrency; a lost race returns *dberr.ConflictError).
+	UpdatePeriods(ctx context.Context, periods []*domain.Period) error
+
+	// GetAllPeriods retrieves the current version of all Periods from the DB.
+	// Like every "current" query below, it excludes soft-deleted Periods.
+	GetAllPeriods(ctx context.Context) ([]*domain.Period, error)
+
+	// ForEachPeriod streams the current version of all Periods to fn without loading them all at once
+	ForEachPeriod(ctx context.Context, fn func(p *domain.Period) error) error
+
+	// GetAllPeriodVersions retrieves every version of every Period, including superseded ones
+	GetAllPeriodVersions(ctx context.Context) ([]*domain.Period, error)
+
+	// GetPeriodsAsOf retrieves the version of each Period that was effective at asOf
+	GetPeriodsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Period, error)
+
+	// SavePeriodVersion closes the current version of a Period and inserts its successor
+	SavePeriodVersion(ctx context.Context, closed *domain.Period, next *domain.Period) error
+
+	// SetPeriodStatus updates the close status of a Period
+	SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error
+
+	// FindByID retrieves the current version of a Period; returns nil, nil when it does not exist
 	FindByID(ctx context.Context, id string) (*domain.Period, error)
+
+	// GetPeriodsStartingBetween retrieves the current version of all Periods with from <= StartDate < to
+	GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*domain.Period, error)
+
+	// GetPeriodsByGranularity retrieves the current version of all Periods of one granularity
+	GetPeriodsByGranularity(ctx context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error)
+
+	// GetPeriodsByCalendar retrieves the current version of all Periods of one calendar
+	GetPeriodsByCalendar(ctx context.Context, calendar domain.CalendarType) ([]*domain.Period, error)
+
+	// GetPeriodsInRange retrieves the current version of all Periods overlapping [start, end];
+	// an empty granularity matches all granularities
+	GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error)
+
+	// SoftDeletePeriod logically removes a Period; it is excluded from all current-version queries
+	SoftDeletePeriod(ctx context.Context, periodID string, deletedBy string) error
+
+	// RestorePeriod undoes SoftDeletePeriod and returns the restored Period
+	RestorePeriod(ctx context.Context, periodID string, restoredBy string) (*domain.Period, error)
+
+	// GetDeletedPeriods retrieves the current version of all soft-deleted Periods
+	GetDeletedPeriods(ctx context.Context) ([]*domain.Period, error)
+
+	// SavePeriodMetadata replaces all metadata of a Period
+	SavePeriodMetadata(ctx context.Context, periodID string, metadata map[string]string) error
+
+	// GetAllPeriodMetadata retrieves the metadata of all Periods, grouped by Period ID
+	GetAllPeriodMetadata(ctx context.Context) (map[string]map[string]string, error)
 }
 
+// Compile-time check that RdsPeriodRepository satisfies PeriodRepository
+var _ PeriodRepository = (*RdsPeriodRepository)(nil)
+
+// PeriodRepository is the loader behind domain.LazyPeriodStore
+var (
+	_ domain.PeriodLoader      = PeriodRepository(nil)
+	_ domain.PeriodRangeLoader = PeriodRepository(nil)
+)
+
+// RdsPeriodRepository stores Periods in Postgres. Writes (and the reads inside write
+// transactions) go to the writer; read-only queries go to the reader endpoint when one is
+// configured (see awsclient.Config.DBReaderEndpoint).
 type RdsPeriodRepository struct {
-	db *sql.DB
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
 }
 
 func NewRdsPeriodRepository(cfg *awsclient.Config) (*RdsPeriodRepository, error) {
-	rdsClient, err := cfg.NewRDSClient()
+	writer, reader, err := cfg.NewRDSReadWriteClients()
 	if err != nil {
 		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
 	}
 
-	return &RdsPeriodRepository{db: rdsClient.Client}, nil
+	return &RdsPeriodRepository{db: writer.Client, reader: reader.Client}, nil
 }
 
 // SavePeriods Inserts a slice of Periods into the database.
 // Will fail if a period with the same ID already exists. This method does NOT touch existing records.
 // It assumes the Periods do NOT exist yet in the DB!
 //
+// Rows are streamed with Postgres COPY (see pgbulk.CopyIn), so saving decades of periods
+// costs one round trip per table instead of one per period.
+//
 // Example:
 //
 //	ctx := context.TODO()
@@ -48,9 +121,98 @@ func (p *RdsPeriodRepository) SavePeriods(ctx context.Context, periods []*domain
 		return nil
 	}
 
-	tx, err := p.db.BeginTx(ctx, nil)
+	// Validate everything up front: a COPY cannot be partially undone row by row
+	periodRows := make([][]any, 0, len(periods))
+	var metadataRows [][]any
+	var events []audit.Event
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("period %s validation failed: %w", p.ID, err)
+		}
+
+		periodRows = append(periodRows, []any{
+			p.ID,
+			p.Name,
+			string(p.Calendar),
+			string(p.Granularity),
+			p.ParentPeriodID,
+			p.StartDate,
+			p.EndDate,
+			versionOrDefault(p.Version),
+			p.EffectiveFrom,
+			p.EffectiveTo,
+			string(p.EffectiveStatus()),
+			p.AuditInfo.CreatedBy,
+			p.AuditInfo.CreatedAt,
+			p.AuditInfo.UpdatedBy,
+			p.AuditInfo.UpdatedAt,
+		})
+		for key, value := range p.Metadata {
+			metadataRows = append(metadataRows, []any{p.ID, key, value})
+		}
+		events = append(events, audit.NewEvent(audit.EntityPeriod, p.ID, audit.ActionCreate, p.AuditInfo.LastActor(),
+			map[string]any{"version": versionOrDefault(p.Version)}))
+	}
+
+	tx, err := txn.Begin(ctx, p.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := pgbulk.CopyIn(ctx, tx.Tx, "periods", periodCopyColumns, periodRows); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "period", Err: err}
+		}
+		return fmt.Errorf("failed to insert periods: %w", err)
+	}
+	if err := pgbulk.CopyIn(ctx, tx.Tx, "period_metadata", []string{"period_id", "key", "value"}, metadataRows); err != nil {
+		return fmt.Errorf("failed to insert period metadata: %w", err)
+	}
+	if err := auditrepo.RecordTx(ctx, tx.Tx, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// periodCopyColumns is the column order of the rows SavePeriods copies into the periods table.
+var periodCopyColumns = []string{
+	"id", "name", "calendar", "granularity", "parent_period_id", "start_date", "end_date",
+	"version", "effective_from", "effective_to", "status",
+	"audit_created_by", "audit_created_at", "audit_updated_by", "audit_updated_at",
+}
+
+// UpsertPeriods inserts a slice of Periods, updating the definition of every Period whose
+// ID and version already exist (INSERT ... ON CONFLICT DO UPDATE). Unlike SavePeriods it never
+// fails on duplicate IDs, so regenerating periods or re-running initialization is idempotent.
+//
+// Only the current version of a Period can be overwritten: upserting a superseded version
+// (effective_to set) fails. Metadata keys carried by the Period are upserted as well; existing
+// keys that the Period does not carry are kept.
+//
+// Example:
+//
+//	err := repo.UpsertPeriods(ctx, domain.GeneratePeriods(2026, 2027))
+func (p *RdsPeriodRepository) UpsertPeriods(ctx context.Context, periods []*domain.Period) error {
+	if len(periods) == 0 {
+		return nil
+	}
+
+	tx, err := txn.Begin(ctx, p.db)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
 	}
 
 	defer func() {
@@ -60,11 +222,23 @@ func (p *RdsPeriodRepository) SavePeriods(ctx context.Context, periods []*domain
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO periods (
 			id, name, calendar, granularity, parent_period_id, start_date, end_date,
+			version, effective_from, effective_to, status,
 			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10, $11)
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
+		ON CONFLICT (id, version) DO UPDATE SET
+			name=EXCLUDED.name,
+			calendar=EXCLUDED.calendar,
+			granularity=EXCLUDED.granularity,
+			parent_period_id=EXCLUDED.parent_period_id,
+			start_date=EXCLUDED.start_date,
+			end_date=EXCLUDED.end_date,
+			audit_updated_by=$16,
+			audit_updated_at=NOW(),
+			row_version=periods.row_version+1
+		WHERE periods.effective_to IS NULL
+		RETURNING (xmax = 0) AS inserted
 	`)
 	if err != nil {
-		tx.Rollback()
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
@@ -78,7 +252,8 @@ func (p *RdsPeriodRepository) SavePeriods(ctx context.Context, periods []*domain
 			return fmt.Errorf("period %s validation failed: %w", p.ID, err)
 		}
 
-		_, err := stmt.ExecContext(ctx,
+		var inserted bool
+		err := stmt.QueryRowContext(ctx,
 			p.ID,
 			p.Name,
 			p.Calendar,
@@ -86,13 +261,40 @@ func (p *RdsPeriodRepository) SavePeriods(ctx context.Context, periods []*domain
 			p.ParentPeriodID,
 			p.StartDate,
 			p.EndDate,
+			versionOrDefault(p.Version),
+			p.EffectiveFrom,
+			p.EffectiveTo,
+			p.EffectiveStatus(),
 			p.AuditInfo.CreatedBy,
 			p.AuditInfo.CreatedAt,
 			p.AuditInfo.UpdatedBy,
 			p.AuditInfo.UpdatedAt,
-		)
+			p.AuditInfo.LastActor(), // audit_updated_by when the period already exists
+		).Scan(&inserted)
+		if err == sql.ErrNoRows {
+			// The conflicting row exists but the WHERE clause skipped it
+			return fmt.Errorf("period %s version %d is superseded and cannot be overwritten", p.ID, versionOrDefault(p.Version))
+		}
 		if err != nil {
-			return fmt.Errorf("failed to insert period %s: %w", p.ID, err)
+			return fmt.Errorf("failed to upsert period %s: %w", p.ID, err)
+		}
+
+		action := audit.ActionUpdate
+		if inserted {
+			action = audit.ActionCreate
+		}
+		if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, p.ID, action, p.AuditInfo.LastActor(),
+			map[string]any{"version": versionOrDefault(p.Version)})); err != nil {
+			return err
+		}
+
+		for key, value := range p.Metadata {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO period_metadata (period_id, key, value) VALUES ($1,$2,$3)
+				ON CONFLICT (period_id, key) DO UPDATE SET value=EXCLUDED.value
+			`, p.ID, key, value); err != nil {
+				return fmt.Errorf("failed to upsert metadata %q of period %s: %w", key, p.ID, err)
+			}
 		}
 	}
 
@@ -105,40 +307,70 @@ func (p *RdsPeriodRepository) SavePeriods(ctx context.Context, periods []*domain
 
 // UpdatePeriods updates a slice of existing Periods in the database.
 // Will fail if a period does NOT exist in the DB.
+//
+// Updates are checked with optimistic concurrency: each Period must carry the RowVersion it was
+// read with. When the stored row was changed in the meantime, nothing is updated and a
+// *dberr.ConflictError is returned (errors.Is(err, dberr.ErrConflict)). On success the
+// RowVersion of every given Period is incremented to match the DB.
+//
+// Example:
+//
+//	p, _ := repo.FindByID(ctx, "2026-JAN")
+//	p.Name = "January 2026 (corrected)"
+//	err := repo.UpdatePeriods(ctx, []*domain.Period{p})
 func (p *RdsPeriodRepository) UpdatePeriods(ctx context.Context, periods []*domain.Period) error {
 	if len(periods) == 0 {
 		return nil
 	}
 
-	tx, err := p.db.BeginTx(ctx, nil)
+	tx, err := txn.Begin(ctx, p.db)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
 	}
 
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
 	for _, p := range periods {
-		query := `
+		expected := versionOrDefault(p.RowVersion)
+
+		res, err := tx.ExecContext(ctx, `
 			UPDATE periods
-			SET name=$1, granularity=$2, parent_period_id=$3, start_date=$4, end_date=$5, audit_user=$6, audit_updated_at=$7
-			WHERE id=$8
-		`
-		res, err := tx.ExecContext(ctx, query,
+			SET name=$1, granularity=$2, parent_period_id=$3, start_date=$4, end_date=$5,
+			    audit_updated_by=$6, audit_updated_at=$7, row_version=row_version+1
+			WHERE id=$8 AND effective_to IS NULL AND row_version=$9
+		`,
 			p.Name,
 			string(p.Granularity),
 			p.ParentPeriodID,
 			p.StartDate,
 			p.EndDate,
-			p.AuditInfo.CreatedBy,
+			p.AuditInfo.LastActor(),
 			time.Now().UTC(),
 			p.ID,
+			expected,
 		)
 		if err != nil {
-			tx.Rollback()
 			return fmt.Errorf("failed to update period %s: %w", p.ID, err)
 		}
-		rows, _ := res.RowsAffected()
-		if rows == 0 {
-			tx.Rollback()
-			return fmt.Errorf("period %s does not exist", p.ID)
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			var actual int
+			err := tx.QueryRowContext(ctx,
+				`SELECT row_version FROM periods WHERE id=$1 AND effective_to IS NULL`, p.ID,
+			).Scan(&actual)
+			if err == sql.ErrNoRows {
+				return &dberr.NotFoundError{Entity: "period", ID: p.ID}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read row version of period %s: %w", p.ID, err)
+			}
+			return &dberr.ConflictError{Entity: "period", ID: p.ID, ExpectedVersion: expected, ActualVersion: actual}
+		}
+
+		if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, p.ID, audit.ActionUpdate, p.AuditInfo.LastActor(),
+			map[string]any{"name": p.Name, "startDate": p.StartDate, "endDate": p.EndDate, "parentPeriodId": p.ParentPeriodID})); err != nil {
+			return err
 		}
 	}
 
@@ -146,44 +378,623 @@ func (p *RdsPeriodRepository) UpdatePeriods(ctx context.Context, periods []*doma
 		return fmt.Errorf("failed to commit update transaction: %w", err)
 	}
 
+	for _, p := range periods {
+		p.RowVersion = versionOrDefault(p.RowVersion) + 1
+	}
+
 	return nil
 }
 
-// GetAllPeriods retrieves all periods from the DB
+// SetPeriodStatus updates the close status of the current version of a period.
+// Status changes are operational state, not definition corrections, so they do not create a new version.
+func (r *RdsPeriodRepository) SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var oldStatus string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM periods WHERE id=$1 AND effective_to IS NULL FOR UPDATE`, periodID,
+	).Scan(&oldStatus)
+	if err == sql.ErrNoRows {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read status of period %s: %w", periodID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE periods SET status=$1, audit_updated_by=$2, audit_updated_at=$3
+		WHERE id=$4 AND effective_to IS NULL
+	`, string(status), updatedBy, time.Now().UTC(), periodID); err != nil {
+		return fmt.Errorf("failed to update status of period %s: %w", periodID, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, periodID, audit.ActionStatusChange, updatedBy,
+		map[string]any{"from": oldStatus, "to": string(status)})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit status transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SoftDeletePeriod logically removes the current version of a Period: it is kept in the DB
+// (including its history) but excluded from every "current" query until it is restored.
+//
+// Example:
+//
+//	err := repo.SoftDeletePeriod(ctx, "2031-JAN", "ops@internal.local")
+func (r *RdsPeriodRepository) SoftDeletePeriod(ctx context.Context, periodID string, deletedBy string) error {
+	return r.execAudited(ctx, audit.NewEvent(audit.EntityPeriod, periodID, audit.ActionDelete, deletedBy, nil),
+		"does not exist or is already deleted", `
+		UPDATE periods SET deleted_at=$1, deleted_by=$2
+		WHERE id=$3 AND effective_to IS NULL AND deleted_at IS NULL
+	`, time.Now().UTC(), deletedBy, periodID)
+}
+
+// RestorePeriod undoes SoftDeletePeriod. The restored Period is returned from the UPDATE
+// itself and its metadata is read in the same transaction, so callers never have to re-read
+// it from the reader, which may not have replicated the restore yet.
+func (r *RdsPeriodRepository) RestorePeriod(ctx context.Context, periodID string, restoredBy string) (*domain.Period, error) {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	p, err := scanPeriod(tx.QueryRowContext(ctx, `
+		UPDATE periods SET deleted_at=NULL, deleted_by=NULL, audit_updated_by=$1, audit_updated_at=$2
+		WHERE id=$3 AND effective_to IS NULL AND deleted_at IS NOT NULL
+		RETURNING `+periodColumns,
+		restoredBy, time.Now().UTC(), periodID))
+	if err == sql.ErrNoRows {
+		return nil, &dberr.NotFoundError{Entity: "period", ID: periodID, Reason: "does not exist or is not deleted"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore period %s: %w", periodID, err)
+	}
+
+	if p.Metadata, err = queryPeriodMetadata(ctx, tx.Tx, periodID); err != nil {
+		return nil, err
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, periodID, audit.ActionRestore, restoredBy, nil)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return p, nil
+}
+
+// execAudited runs a single-row UPDATE and records event in the same transaction.
+// When no row matches, it fails with a *dberr.NotFoundError giving notFound as the reason.
+func (r *RdsPeriodRepository) execAudited(ctx context.Context, event audit.Event, notFound string, query string, args ...any) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s period %s: %w", strings.ToLower(string(event.Action)), event.EntityID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return &dberr.NotFoundError{Entity: "period", ID: event.EntityID, Reason: notFound}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeletedPeriods retrieves the current version of all soft-deleted Periods, e.g. to offer
+// them for restore.
+func (r *RdsPeriodRepository) GetDeletedPeriods(ctx context.Context) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `SELECT `+periodColumns+` FROM periods WHERE effective_to IS NULL AND deleted_at IS NOT NULL`)
+}
+
+// periodColumns is the column list shared by all period SELECT queries; see scanPeriod.
+const periodColumns = `id, name, calendar, granularity, parent_period_id, start_date, end_date, version, effective_from, effective_to, status, deleted_at, deleted_by, row_version`
+
+// GetAllPeriods retrieves the CURRENT version of all periods from the DB
 // This is called at startup to populate the in-memory PeriodStore
 func (r *RdsPeriodRepository) GetAllPeriods(ctx context.Context) ([]*domain.Period, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, name, granularity, parent_period_id, start_date, end_date FROM periods`)
+	return r.queryPeriods(ctx, `SELECT `+periodColumns+` FROM periods WHERE effective_to IS NULL AND deleted_at IS NULL`)
+}
+
+// ForEachPeriod streams the current version of all Periods, in chronological order (start
+// date, then ID), to fn one row at a time. Unlike GetAllPeriods it never materializes the whole
+// table, so it scales to daily periods across decades. Metadata is joined in the same query.
+//
+// Returning an error from fn stops the iteration; ForEachPeriod returns that error unchanged.
+// fn runs while the result set is open and holds a DB connection, so keep it fast.
+//
+// Example:
+//
+//	var months int
+//	err := repo.ForEachPeriod(ctx, func(p *domain.Period) error {
+//	    if p.Granularity == domain.MonthlyPeriod {
+//	        months++
+//	    }
+//	    return nil
+//	})
+func (r *RdsPeriodRepository) ForEachPeriod(ctx context.Context, fn func(p *domain.Period) error) error {
+	rows, err := r.reader.QueryContext(ctx, `
+		SELECT `+qualifiedPeriodColumns("p")+`, m.key, m.value
+		FROM periods p
+		LEFT JOIN period_metadata m ON m.period_id = p.id
+		WHERE p.effective_to IS NULL AND p.deleted_at IS NULL
+		ORDER BY p.start_date, p.id
+	`)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query periods: %w", err)
+		return fmt.Errorf("failed to query periods: %w", err)
 	}
 	defer rows.Close()
 
-	var periods []*domain.Period
+	// Metadata rows of one period arrive consecutively; emit a period once the next one starts
+	var current *domain.Period
 	for rows.Next() {
-		p := &domain.Period{}
-		var granularity string
-		if err := rows.Scan(&p.ID, &p.Name, &granularity, &p.ParentPeriodID, &p.StartDate, &p.EndDate); err != nil {
-			return nil, fmt.Errorf("failed to scan period row: %w", err)
+		var key, value sql.NullString
+		p, err := scanPeriod(extraScanner{row: rows, extra: []any{&key, &value}})
+		if err != nil {
+			return fmt.Errorf("failed to scan period row: %w", err)
+		}
+
+		if current == nil || current.ID != p.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			current = p
+		}
+		if key.Valid {
+			current.SetTag(key.String, value.String)
 		}
-		p.Granularity = domain.PeriodGranularity(granularity)
-		periods = append(periods, p)
 	}
-	return periods, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate period rows: %w", err)
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+// GetAllPeriodVersions retrieves EVERY version of every period, including superseded ones.
+// Use domain.SelectAsOf / domain.NewPeriodStoreAsOf to pick the versions valid at a moment.
+func (r *RdsPeriodRepository) GetAllPeriodVersions(ctx context.Context) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `SELECT `+periodColumns+` FROM periods ORDER BY id, version`)
 }
 
-// FindByID retrieves a single period by ID
+// GetPeriodsAsOf retrieves the version of each period that was effective at asOf.
+//
+// Example:
+//
+//	periods, err := repo.GetPeriodsAsOf(ctx, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+//	store := domain.NewPeriodStore(periods)
+func (r *RdsPeriodRepository) GetPeriodsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `
+		SELECT `+periodColumns+` FROM periods
+		WHERE effective_from <= $1 AND (effective_to IS NULL OR effective_to >= $1)
+	`, asOf)
+}
+
+// SavePeriodVersion closes the current version of a period and inserts its successor
+// in one transaction. `closed` must carry the EffectiveTo set by Period.NextVersion.
+//
+// Example:
+//
+//	next, _ := current.NextVersion(effectiveFrom, user)
+//	next.StartDate = correctedStart
+//	err := repo.SavePeriodVersion(ctx, current, next)
+func (r *RdsPeriodRepository) SavePeriodVersion(ctx context.Context, closed *domain.Period, next *domain.Period) error {
+	if closed.EffectiveTo == nil {
+		return fmt.Errorf("period %s version %d must be closed before saving version %d", closed.ID, closed.Version, next.Version)
+	}
+
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("period %s version %d validation failed: %w", next.ID, next.Version, err)
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE periods SET effective_to=$1
+		WHERE id=$2 AND version=$3 AND effective_to IS NULL
+	`, closed.EffectiveTo, closed.ID, closed.Version)
+	if err != nil {
+		return fmt.Errorf("failed to close period %s version %d: %w", closed.ID, closed.Version, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return fmt.Errorf("period %s version %d is not the current version", closed.ID, closed.Version)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO periods (
+			id, name, calendar, granularity, parent_period_id, start_date, end_date,
+			version, effective_from, effective_to, status,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
+	`,
+		next.ID,
+		next.Name,
+		next.Calendar,
+		next.Granularity,
+		next.ParentPeriodID,
+		next.StartDate,
+		next.EndDate,
+		next.Version,
+		next.EffectiveFrom,
+		next.EffectiveTo,
+		next.EffectiveStatus(),
+		next.AuditInfo.CreatedBy,
+		next.AuditInfo.CreatedAt,
+		next.AuditInfo.UpdatedBy,
+		next.AuditInfo.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert period %s version %d: %w", next.ID, next.Version, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, next.ID, audit.ActionUpdate, next.AuditInfo.LastActor(),
+		map[string]any{"version": next.Version, "supersedes": closed.Version, "effectiveFrom": next.EffectiveFrom})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit period version transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves the current version of a single period by ID
 func (r *RdsPeriodRepository) FindByID(ctx context.Context, id string) (*domain.Period, error) {
-	row := r.db.QueryRowContext(ctx,
-		`SELECT id, name, granularity, parent_period_id, start_date, end_date FROM periods WHERE id=$1`, id)
+	row := r.reader.QueryRowContext(ctx,
+		`SELECT `+periodColumns+` FROM periods WHERE id=$1 AND effective_to IS NULL AND deleted_at IS NULL`, id)
 
-	var p domain.Period
-	var granularity string
-	if err := row.Scan(&p.ID, &p.Name, &granularity, &p.ParentPeriodID, &p.StartDate, &p.EndDate); err != nil {
+	p, err := scanPeriod(row)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Not found
 		}
 		return nil, fmt.Errorf("failed to scan period: %w", err)
 	}
+
+	metadata, err := queryPeriodMetadata(ctx, r.reader, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Metadata = metadata
+
+	return p, nil
+}
+
+// GetPeriodsStartingBetween retrieves the current version of all Periods starting in [from, to).
+// Used by domain.LazyPeriodStore to load one year bucket at a time.
+func (r *RdsPeriodRepository) GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*domain.Period, error) {
+	return r.queryCurrentPeriodsWhere(ctx, `p.start_date >= $1 AND p.start_date < $2`, from, to)
+}
+
+// GetPeriodsByGranularity retrieves the current version of all Periods of one granularity,
+// filtered in SQL, so services that only need e.g. months do not load every period.
+//
+// Example:
+//
+//	months, err := repo.GetPeriodsByGranularity(ctx, domain.MonthlyPeriod)
+func (r *RdsPeriodRepository) GetPeriodsByGranularity(ctx context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	return r.queryCurrentPeriodsWhere(ctx, `p.granularity = $1`, string(granularity))
+}
+
+// GetPeriodsByCalendar retrieves the current version of all Periods of one calendar
+// (CAL, FY or FW), filtered in SQL.
+//
+// Example:
+//
+//	fiscal, err := repo.GetPeriodsByCalendar(ctx, domain.CalendarFiscal)
+func (r *RdsPeriodRepository) GetPeriodsByCalendar(ctx context.Context, calendar domain.CalendarType) ([]*domain.Period, error) {
+	return r.queryCurrentPeriodsWhere(ctx, `p.calendar = $1`, string(calendar))
+}
+
+// GetPeriodsInRange retrieves the current version of all Periods of the given granularity that
+// overlap the inclusive window [start, end], filtered in SQL. An empty granularity matches
+// every granularity. Reporting queries use it to fetch only the window they display.
+//
+// Example (all months touched by Q1 2026):
+//
+//	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+//	end := time.Date(2026, 3, 31, 23, 59, 59, 999999999, time.UTC)
+//	months, err := repo.GetPeriodsInRange(ctx, start, end, domain.MonthlyPeriod)
+func (r *RdsPeriodRepository) GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("invalid period window: end %s is before start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	if granularity == "" {
+		return r.queryCurrentPeriodsWhere(ctx, `p.start_date <= $2 AND p.end_date >= $1`, start, end)
+	}
+	return r.queryCurrentPeriodsWhere(ctx, `p.start_date <= $2 AND p.end_date >= $1 AND p.granularity = $3`, start, end, string(granularity))
+}
+
+// queryCurrentPeriodsWhere retrieves the current version of the Periods matching where (a SQL
+// condition on the periods table aliased as p) and loads only their metadata.
+func (r *RdsPeriodRepository) queryCurrentPeriodsWhere(ctx context.Context, where string, args ...any) ([]*domain.Period, error) {
+	periods, err := r.queryPeriodRows(ctx, `
+		SELECT `+periodColumns+` FROM periods p
+		WHERE `+where+` AND p.effective_to IS NULL AND p.deleted_at IS NULL
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.reader.QueryContext(ctx, `
+		SELECT m.period_id, m.key, m.value FROM period_metadata m
+		JOIN periods p ON p.id = m.period_id
+		WHERE `+where+` AND p.effective_to IS NULL AND p.deleted_at IS NULL
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query period metadata: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*domain.Period, len(periods))
+	for _, p := range periods {
+		byID[p.ID] = p
+	}
+	for rows.Next() {
+		var periodID, key, value string
+		if err := rows.Scan(&periodID, &key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan period metadata row: %w", err)
+		}
+		if p, ok := byID[periodID]; ok {
+			p.SetTag(key, value)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate period metadata rows: %w", err)
+	}
+
+	return periods, nil
+}
+
+func (r *RdsPeriodRepository) queryPeriods(ctx context.Context, query string, args ...any) ([]*domain.Period, error) {
+	periods, err := r.queryPeriodRows(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := r.GetAllPeriodMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range periods {
+		p.Metadata = metadata[p.ID]
+	}
+
+	return periods, nil
+}
+
+// queryPeriodRows runs a SELECT over periodColumns without loading metadata.
+func (r *RdsPeriodRepository) queryPeriodRows(ctx context.Context, query string, args ...any) ([]*domain.Period, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query periods: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []*domain.Period
+	for rows.Next() {
+		p, err := scanPeriod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan period row: %w", err)
+		}
+		periods = append(periods, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate period rows: %w", err)
+	}
+
+	return periods, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// extraScanner scans the period columns followed by extra columns of the same row.
+type extraScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (e extraScanner) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
+
+// qualifiedPeriodColumns returns periodColumns prefixed with a table alias, e.g. "p.id, p.name, ...".
+func qualifiedPeriodColumns(alias string) string {
+	cols := strings.Split(periodColumns, ", ")
+	for i, c := range cols {
+		cols[i] = alias + "." + c
+	}
+	return strings.Join(cols, ", ")
+}
+
+// scanPeriod scans one row selected with periodColumns into a Period.
+func scanPeriod(row rowScanner) (*domain.Period, error) {
+	p := &domain.Period{}
+	var calendar, granularity, status string
+	if err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&calendar,
+		&granularity,
+		&p.ParentPeriodID,
+		&p.StartDate,
+		&p.EndDate,
+		&p.Version,
+		&p.EffectiveFrom,
+		&p.EffectiveTo,
+		&status,
+		&p.DeletedAt,
+		&p.DeletedBy,
+		&p.RowVersion,
+	); err != nil {
+		return nil, err
+	}
+	p.Status = domain.PeriodStatus(status)
+	p.Calendar = domain.CalendarType(calendar)
 	p.Granularity = domain.PeriodGranularity(granularity)
-	return &p, nil
+	return p, nil
+}
+
+// versionOrDefault maps the zero value of Period.Version to 1 (the first version).
+func versionOrDefault(v int) int {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// SavePeriodMetadata replaces ALL metadata of a period with the given map.
+// Keys that are not present in metadata are removed from the side table.
+//
+// Example:
+//
+//	err := repo.SavePeriodMetadata(ctx, "2026-Q1", map[string]string{
+//	    "budget-relevant": "true",
+//	    "audited":         "2026-05-01",
+//	})
+func (r *RdsPeriodRepository) SavePeriodMetadata(ctx context.Context, periodID string, metadata map[string]string) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM period_metadata WHERE period_id=$1`, periodID); err != nil {
+		return fmt.Errorf("failed to clear metadata of period %s: %w", periodID, err)
+	}
+
+	if err := insertPeriodMetadata(ctx, tx.Tx, periodID, metadata); err != nil {
+		return err
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, periodID, audit.ActionUpdate, audit.SystemActor,
+		map[string]any{"metadata": metadata})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit metadata transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetAllPeriodMetadata loads the complete period_metadata side table, grouped by period ID.
+func (r *RdsPeriodRepository) GetAllPeriodMetadata(ctx context.Context) (map[string]map[string]string, error) {
+	rows, err := r.reader.QueryContext(ctx, `SELECT period_id, key, value FROM period_metadata`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query period metadata: %w", err)
+	}
+	defer rows.Close()
+
+	metadata := make(map[string]map[string]string)
+	for rows.Next() {
+		var periodID, key, value string
+		if err := rows.Scan(&periodID, &key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan period metadata row: %w", err)
+		}
+		if metadata[periodID] == nil {
+			metadata[periodID] = make(map[string]string)
+		}
+		metadata[periodID][key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate period metadata rows: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// queryPeriodMetadata loads the metadata of a single period through q. Returns nil when the
+// period has no tags.
+func queryPeriodMetadata(ctx context.Context, q queryer, periodID string) (map[string]string, error) {
+	rows, err := q.QueryContext(ctx, `SELECT key, value FROM period_metadata WHERE period_id=$1`, periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metadata of period %s: %w", periodID, err)
+	}
+	defer rows.Close()
+
+	var metadata map[string]string
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan metadata of period %s: %w", periodID, err)
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate metadata of period %s: %w", periodID, err)
+	}
+
+	return metadata, nil
+}
+
+// insertPeriodMetadata writes the key/value pairs of a period into period_metadata within tx.
+func insertPeriodMetadata(ctx context.Context, tx *sql.Tx, periodID string, metadata map[string]string) error {
+	for key, value := range metadata {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO period_metadata (period_id, key, value) VALUES ($1,$2,$3)`,
+			periodID, key, value,
+		); err != nil {
+			return fmt.Errorf("failed to insert metadata %q of period %s: %w", key, periodID, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+)
+
+// testPeriodRepository checks the PeriodRepository contract that the in-memory and SQLite
+// repositories share with RdsPeriodRepository. newRepo returns an empty repository.
+func testPeriodRepository(t *testing.T, newRepo func(t *testing.T) PeriodRepository) {
+	ctx := context.Background()
+	const user = "ops@internal.local"
+
+	// seeded returns a repository holding the calendar periods of 2026, with a tag on Q1
+	seeded := func(t *testing.T) PeriodRepository {
+		t.Helper()
+		repo := newRepo(t)
+		periods := domain.GeneratePeriods(2026, 2026)
+		for _, p := range periods {
+			if p.ID == "2026-Q1" {
+				p.SetTag("budget-relevant", "true")
+			}
+		}
+		if err := repo.SavePeriods(ctx, periods); err != nil {
+			t.Fatalf("SavePeriods: %v", err)
+		}
+		return repo
+	}
+
+	mustFind := func(t *testing.T, repo PeriodRepository, id string) *domain.Period {
+		t.Helper()
+		p, err := repo.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID(%s): %v", id, err)
+		}
+		if p == nil {
+			t.Fatalf("FindByID(%s) found nothing", id)
+		}
+		return p
+	}
+
+	t.Run("save and find", func(t *testing.T) {
+		repo := seeded(t)
+
+		all, err := repo.GetAllPeriods(ctx)
+		if err != nil {
+			t.Fatalf("GetAllPeriods: %v", err)
+		}
+		if len(all) != 17 {
+			t.Errorf("GetAllPeriods returned %d periods, want 17", len(all))
+		}
+
+		q1 := mustFind(t, repo, "2026-Q1")
+		wantStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		wantEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+		if !q1.StartDate.Equal(wantStart) || !q1.EndDate.Equal(wantEnd) {
+			t.Errorf("2026-Q1 spans %s – %s, want %s – %s", q1.StartDate, q1.EndDate, wantStart, wantEnd)
+		}
+		if q1.ParentPeriodID == nil || *q1.ParentPeriodID != "2026" {
+			t.Errorf("2026-Q1 parent = %v, want 2026", q1.ParentPeriodID)
+		}
+		if v, ok := q1.Tag("budget-relevant"); !ok || v != "true" {
+			t.Errorf("2026-Q1 tag budget-relevant = %q, %v", v, ok)
+		}
+		if len(q1.ChildPeriodIDs) != 0 {
+			t.Errorf("ChildPeriodIDs are stored: %v", q1.ChildPeriodIDs)
+		}
+
+		if p, err := repo.FindByID(ctx, "2031-JAN"); err != nil || p != nil {
+			t.Errorf("FindByID(2031-JAN) = %v, %v, want nil, nil", p, err)
+		}
+	})
+
+	t.Run("duplicate save", func(t *testing.T) {
+		repo := seeded(t)
+
+		err := repo.SavePeriods(ctx, domain.GeneratePeriods(2026, 2026)[:1])
+		if !errors.Is(err, dberr.ErrDuplicateID) {
+			t.Errorf("SavePeriods of an existing period: %v, want ErrDuplicateID", err)
+		}
+	})
+
+	t.Run("upsert is idempotent", func(t *testing.T) {
+		repo := seeded(t)
+
+		periods := domain.GeneratePeriods(2026, 2027)
+		periods[0].Name = "Year 2026"
+		if err := repo.UpsertPeriods(ctx, periods); err != nil {
+			t.Fatalf("UpsertPeriods: %v", err)
+		}
+
+		all, err := repo.GetAllPeriods(ctx)
+		if err != nil {
+			t.Fatalf("GetAllPeriods: %v", err)
+		}
+		if len(all) != 34 {
+			t.Errorf("GetAllPeriods returned %d periods, want 34", len(all))
+		}
+		year := mustFind(t, repo, "2026")
+		if year.Name != "Year 2026" || year.RowVersion != 2 {
+			t.Errorf("2026 is %q at row version %d, want %q at 2", year.Name, year.RowVersion, "Year 2026")
+		}
+		if _, ok := mustFind(t, repo, "2026-Q1").Tag("budget-relevant"); !ok {
+			t.Error("upsert dropped a metadata key the periods do not carry")
+		}
+	})
+
+	t.Run("stale update conflicts", func(t *testing.T) {
+		repo := seeded(t)
+
+		first := mustFind(t, repo, "2026-JAN")
+		second := mustFind(t, repo, "2026-JAN")
+
+		first.Name = "January"
+		first.AuditInfo.UpdateAuditInfo("editor@internal.local")
+		if err := repo.UpdatePeriods(ctx, []*domain.Period{first}); err != nil {
+			t.Fatalf("UpdatePeriods: %v", err)
+		}
+		if first.RowVersion != 2 {
+			t.Errorf("RowVersion after update = %d, want 2", first.RowVersion)
+		}
+		if got := mustFind(t, repo, "2026-JAN").AuditInfo.LastActor(); got != "editor@internal.local" {
+			t.Errorf("updated by %q, want editor@internal.local", got)
+		}
+
+		second.Name = "Jan"
+		err := repo.UpdatePeriods(ctx, []*domain.Period{second})
+		var conflict *dberr.ConflictError
+		if !errors.As(err, &conflict) || conflict.ExpectedVersion != 1 || conflict.ActualVersion != 2 {
+			t.Fatalf("stale UpdatePeriods: %v, want a conflict between row versions 1 and 2", err)
+		}
+		if got := mustFind(t, repo, "2026-JAN").Name; got != "January" {
+			t.Errorf("name after the conflict = %q, want January", got)
+		}
+
+		missing := &domain.Period{ID: "2031-JAN"}
+		if err := repo.UpdatePeriods(ctx, []*domain.Period{missing}); !errors.Is(err, dberr.ErrNotFound) {
+			t.Errorf("UpdatePeriods of an unknown period: %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("versions", func(t *testing.T) {
+		repo := seeded(t)
+
+		current := mustFind(t, repo, "2026-Q1")
+		effectiveFrom := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+		next, err := current.NextVersion(effectiveFrom, user)
+		if err != nil {
+			t.Fatalf("NextVersion: %v", err)
+		}
+		next.Name = "First quarter 2026"
+		if err := repo.SavePeriodVersion(ctx, current, next); err != nil {
+			t.Fatalf("SavePeriodVersion: %v", err)
+		}
+		if err := repo.SavePeriodVersion(ctx, current, next); err == nil {
+			t.Error("SavePeriodVersion of a superseded version succeeded")
+		}
+
+		if got := mustFind(t, repo, "2026-Q1"); got.Version != 2 || got.Name != "First quarter 2026" {
+			t.Errorf("current 2026-Q1 is version %d %q, want version 2", got.Version, got.Name)
+		}
+
+		versions, err := repo.GetAllPeriodVersions(ctx)
+		if err != nil {
+			t.Fatalf("GetAllPeriodVersions: %v", err)
+		}
+		if len(versions) != 18 {
+			t.Errorf("GetAllPeriodVersions returned %d rows, want 18", len(versions))
+		}
+
+		before, err := repo.GetPeriodsAsOf(ctx, effectiveFrom.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("GetPeriodsAsOf: %v", err)
+		}
+		for _, p := range before {
+			if p.ID == "2026-Q1" && p.Version != 1 {
+				t.Errorf("2026-Q1 before the change is version %d, want 1", p.Version)
+			}
+		}
+
+		stale := *current
+		stale.Version = 1
+		if err := repo.UpsertPeriods(ctx, []*domain.Period{&stale}); err == nil {
+			t.Error("UpsertPeriods overwrote a superseded version")
+		}
+	})
+
+	t.Run("soft delete and restore", func(t *testing.T) {
+		repo := seeded(t)
+
+		if err := repo.SoftDeletePeriod(ctx, "2026-DEC", user); err != nil {
+			t.Fatalf("SoftDeletePeriod: %v", err)
+		}
+		if err := repo.SoftDeletePeriod(ctx, "2026-DEC", user); !errors.Is(err, dberr.ErrNotFound) {
+			t.Errorf("second SoftDeletePeriod: %v, want ErrNotFound", err)
+		}
+		if p, err := repo.FindByID(ctx, "2026-DEC"); err != nil || p != nil {
+			t.Errorf("FindByID of a deleted period = %v, %v, want nil, nil", p, err)
+		}
+		months, err := repo.GetPeriodsByGranularity(ctx, domain.MonthlyPeriod)
+		if err != nil {
+			t.Fatalf("GetPeriodsByGranularity: %v", err)
+		}
+		if len(months) != 11 {
+			t.Errorf("GetPeriodsByGranularity returned %d months, want 11", len(months))
+		}
+
+		deleted, err := repo.GetDeletedPeriods(ctx)
+		if err != nil {
+			t.Fatalf("GetDeletedPeriods: %v", err)
+		}
+		if len(deleted) != 1 || deleted[0].ID != "2026-DEC" || deleted[0].DeletedBy == nil || *deleted[0].DeletedBy != user {
+			t.Fatalf("GetDeletedPeriods = %v, want 2026-DEC deleted by %s", deleted, user)
+		}
+
+		restored, err := repo.RestorePeriod(ctx, "2026-DEC", user)
+		if err != nil {
+			t.Fatalf("RestorePeriod: %v", err)
+		}
+		if restored == nil || restored.ID != "2026-DEC" || restored.IsDeleted() {
+			t.Errorf("RestorePeriod returned %v, want the restored 2026-DEC", restored)
+		}
+		if _, err := repo.RestorePeriod(ctx, "2026-DEC", user); !errors.Is(err, dberr.ErrNotFound) {
+			t.Errorf("second RestorePeriod: %v, want ErrNotFound", err)
+		}
+		mustFind(t, repo, "2026-DEC")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		repo := seeded(t)
+
+		if err := repo.SetPeriodStatus(ctx, "2026-JAN", domain.PeriodSoftClosed, user); err != nil {
+			t.Fatalf("SetPeriodStatus: %v", err)
+		}
+		if got := mustFind(t, repo, "2026-JAN").Status; got != domain.PeriodSoftClosed {
+			t.Errorf("status = %s, want %s", got, domain.PeriodSoftClosed)
+		}
+		if err := repo.SetPeriodStatus(ctx, "2031-JAN", domain.PeriodSoftClosed, user); !errors.Is(err, dberr.ErrNotFound) {
+			t.Errorf("SetPeriodStatus of an unknown period: %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		repo := seeded(t)
+
+		if err := repo.SavePeriodMetadata(ctx, "2026-Q1", map[string]string{"audited": "2026-05-01"}); err != nil {
+			t.Fatalf("SavePeriodMetadata: %v", err)
+		}
+		metadata, err := repo.GetAllPeriodMetadata(ctx)
+		if err != nil {
+			t.Fatalf("GetAllPeriodMetadata: %v", err)
+		}
+		if got := metadata["2026-Q1"]; len(got) != 1 || got["audited"] != "2026-05-01" {
+			t.Errorf("metadata of 2026-Q1 = %v, want only audited", got)
+		}
+	})
+
+	t.Run("windows", func(t *testing.T) {
+		repo := seeded(t)
+		start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+		starting, err := repo.GetPeriodsStartingBetween(ctx, start, end)
+		if err != nil {
+			t.Fatalf("GetPeriodsStartingBetween: %v", err)
+		}
+		if got := periodIDs(starting); len(got) != 2 || !got["2026-FEB"] || !got["2026-MAR"] {
+			t.Errorf("GetPeriodsStartingBetween = %v, want 2026-FEB and 2026-MAR", got)
+		}
+
+		// Q1 and Q2 overlap [FEB 1, APR 1], the year does too but is not a quarter
+		quarters, err := repo.GetPeriodsInRange(ctx, start, end, domain.QuarterlyPeriod)
+		if err != nil {
+			t.Fatalf("GetPeriodsInRange: %v", err)
+		}
+		if got := periodIDs(quarters); len(got) != 2 || !got["2026-Q1"] || !got["2026-Q2"] {
+			t.Errorf("GetPeriodsInRange = %v, want 2026-Q1 and 2026-Q2", got)
+		}
+		if _, err := repo.GetPeriodsInRange(ctx, end, start, ""); err == nil {
+			t.Error("GetPeriodsInRange accepted an inverted window")
+		}
+
+		var previous time.Time
+		var count int
+		err = repo.ForEachPeriod(ctx, func(p *domain.Period) error {
+			if p.StartDate.Before(previous) {
+				t.Errorf("ForEachPeriod returned %s out of order", p.ID)
+			}
+			previous = p.StartDate
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ForEachPeriod: %v", err)
+		}
+		if count != 17 {
+			t.Errorf("ForEachPeriod visited %d periods, want 17", count)
+		}
+	})
+}
+
+func periodIDs(periods []*domain.Period) map[string]bool {
+	ids := make(map[string]bool, len(periods))
+	for _, p := range periods {
+		ids[p.ID] = true
+	}
+	return ids
+}
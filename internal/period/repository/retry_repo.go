@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/retry"
+)
+
+// RetryingPeriodRepository decorates a PeriodRepository with retries on transient DB errors
+// (serialization failures, deadlocks, connection resets, throttling), using exponential
+// backoff with jitter. Permanent errors — validation, "does not exist", *dberr.ConflictError —
+// are returned immediately; when all attempts fail, a *dberr.RetryExhaustedError is returned.
+//
+// Every write of the RDS repository runs in a single transaction, so a failed attempt leaves
+// nothing behind and can safely be repeated. ForEachPeriod is only retried as long as fn was
+// not called yet, so fn never sees a Period twice.
+//
+// Example:
+//
+//	repo := NewRetryingPeriodRepository(rdsRepo, retry.DefaultPolicy)
+//	periodService := service.NewPeriodService(repo)
+type RetryingPeriodRepository struct {
+	inner  PeriodRepository
+	policy retry.Policy
+}
+
+var _ PeriodRepository = (*RetryingPeriodRepository)(nil)
+
+func NewRetryingPeriodRepository(inner PeriodRepository, policy retry.Policy) *RetryingPeriodRepository {
+	return &RetryingPeriodRepository{
+		inner:  inner,
+		policy: policy,
+	}
+}
+
+func (r *RetryingPeriodRepository) SavePeriods(ctx context.Context, periods []*domain.Period) error {
+	return retry.Do(ctx, r.policy, "SavePeriods", func(ctx context.Context) error {
+		return r.inner.SavePeriods(ctx, periods)
+	})
+}
+
+func (r *RetryingPeriodRepository) UpsertPeriods(ctx context.Context, periods []*domain.Period) error {
+	return retry.Do(ctx, r.policy, "UpsertPeriods", func(ctx context.Context) error {
+		return r.inner.UpsertPeriods(ctx, periods)
+	})
+}
+
+func (r *RetryingPeriodRepository) UpdatePeriods(ctx context.Context, periods []*domain.Period) error {
+	return retry.Do(ctx, r.policy, "UpdatePeriods", func(ctx context.Context) error {
+		return r.inner.UpdatePeriods(ctx, periods)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetAllPeriods(ctx context.Context) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetAllPeriods", r.inner.GetAllPeriods)
+}
+
+func (r *RetryingPeriodRepository) ForEachPeriod(ctx context.Context, fn func(p *domain.Period) error) error {
+	started := false
+	retryable := r.policy.Retryable
+	if retryable == nil {
+		retryable = retry.IsTransient
+	}
+	policy := r.policy
+	policy.Retryable = func(err error) bool {
+		return !started && retryable(err)
+	}
+
+	return retry.Do(ctx, policy, "ForEachPeriod", func(ctx context.Context) error {
+		return r.inner.ForEachPeriod(ctx, func(p *domain.Period) error {
+			started = true
+			return fn(p)
+		})
+	})
+}
+
+func (r *RetryingPeriodRepository) GetAllPeriodVersions(ctx context.Context) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetAllPeriodVersions", r.inner.GetAllPeriodVersions)
+}
+
+func (r *RetryingPeriodRepository) GetPeriodsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetPeriodsAsOf", func(ctx context.Context) ([]*domain.Period, error) {
+		return r.inner.GetPeriodsAsOf(ctx, asOf)
+	})
+}
+
+func (r *RetryingPeriodRepository) SavePeriodVersion(ctx context.Context, closed *domain.Period, next *domain.Period) error {
+	return retry.Do(ctx, r.policy, "SavePeriodVersion", func(ctx context.Context) error {
+		return r.inner.SavePeriodVersion(ctx, closed, next)
+	})
+}
+
+func (r *RetryingPeriodRepository) SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error {
+	return retry.Do(ctx, r.policy, "SetPeriodStatus", func(ctx context.Context) error {
+		return r.inner.SetPeriodStatus(ctx, periodID, status, updatedBy)
+	})
+}
+
+func (r *RetryingPeriodRepository) FindByID(ctx context.Context, id string) (*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "FindByID", func(ctx context.Context) (*domain.Period, error) {
+		return r.inner.FindByID(ctx, id)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetPeriodsStartingBetween", func(ctx context.Context) ([]*domain.Period, error) {
+		return r.inner.GetPeriodsStartingBetween(ctx, from, to)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetPeriodsByGranularity(ctx context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetPeriodsByGranularity", func(ctx context.Context) ([]*domain.Period, error) {
+		return r.inner.GetPeriodsByGranularity(ctx, granularity)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetPeriodsByCalendar(ctx context.Context, calendar domain.CalendarType) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetPeriodsByCalendar", func(ctx context.Context) ([]*domain.Period, error) {
+		return r.inner.GetPeriodsByCalendar(ctx, calendar)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetPeriodsInRange", func(ctx context.Context) ([]*domain.Period, error) {
+		return r.inner.GetPeriodsInRange(ctx, start, end, granularity)
+	})
+}
+
+func (r *RetryingPeriodRepository) SoftDeletePeriod(ctx context.Context, periodID string, deletedBy string) error {
+	return retry.Do(ctx, r.policy, "SoftDeletePeriod", func(ctx context.Context) error {
+		return r.inner.SoftDeletePeriod(ctx, periodID, deletedBy)
+	})
+}
+
+func (r *RetryingPeriodRepository) RestorePeriod(ctx context.Context, periodID string, restoredBy string) (*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "RestorePeriod", func(ctx context.Context) (*domain.Period, error) {
+		return r.inner.RestorePeriod(ctx, periodID, restoredBy)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetDeletedPeriods(ctx context.Context) ([]*domain.Period, error) {
+	return retry.DoValue(ctx, r.policy, "GetDeletedPeriods", r.inner.GetDeletedPeriods)
+}
+
+func (r *RetryingPeriodRepository) SavePeriodMetadata(ctx context.Context, periodID string, metadata map[string]string) error {
+	return retry.Do(ctx, r.policy, "SavePeriodMetadata", func(ctx context.Context) error {
+		return r.inner.SavePeriodMetadata(ctx, periodID, metadata)
+	})
+}
+
+func (r *RetryingPeriodRepository) GetAllPeriodMetadata(ctx context.Context) (map[string]map[string]string, error) {
+	return retry.DoValue(ctx, r.policy, "GetAllPeriodMetadata", r.inner.GetAllPeriodMetadata)
+}
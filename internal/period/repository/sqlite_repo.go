@@ -0,0 +1,475 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/sqlitedb"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// SqlitePeriodRepository stores Periods in a SQLite database opened with sqlitedb.Open, for
+// local development and single-instance deployments that keep their periods across restarts
+// without a Postgres server. It uses the same tables and mirrors the semantics of
+// RdsPeriodRepository:
+//
+//   - every version of a Period is kept; the current version has effective_to IS NULL
+//   - soft-deleted Periods are excluded from all "current" queries
+//   - ChildPeriodIDs are NOT stored
+//   - updates are checked against row_version (optimistic concurrency)
+//
+// Unlike the RDS repository it records no audit events: the audit log lives in Postgres.
+// All times are stored in UTC, so the text columns compare chronologically.
+//
+// Example:
+//
+//	db, err := sqlitedb.Open(ctx, "cso-book.db")
+//	repo := NewSqlitePeriodRepository(db)
+//	ps := service.NewPeriodService(repo)
+type SqlitePeriodRepository struct {
+	db *sql.DB
+}
+
+var _ PeriodRepository = (*SqlitePeriodRepository)(nil)
+
+func NewSqlitePeriodRepository(db *sql.DB) *SqlitePeriodRepository {
+	return &SqlitePeriodRepository{db: db}
+}
+
+// SavePeriods inserts new Periods. Like the RDS repository, it fails with a
+// *dberr.DuplicateError when a Period with the same ID and version already exists; nothing is
+// saved in that case.
+func (r *SqlitePeriodRepository) SavePeriods(ctx context.Context, periods []*domain.Period) error {
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("period %s validation failed: %w", p.ID, err)
+		}
+	}
+
+	return r.withinTx(ctx, func(tx *sql.Tx) error {
+		for _, p := range periods {
+			if p == nil {
+				continue
+			}
+			if err := insertSqlitePeriod(ctx, tx, p); err != nil {
+				if sqlitedb.IsUniqueViolation(err) {
+					return &dberr.DuplicateError{Entity: "period", ID: p.ID, Err: err}
+				}
+				return fmt.Errorf("failed to insert period %s: %w", p.ID, err)
+			}
+			if err := upsertSqlitePeriodMetadata(ctx, tx, p.ID, p.Metadata); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpsertPeriods inserts new Periods and updates the definition of existing ones (same ID and
+// version). Only the current version of a Period can be overwritten; metadata is upserted
+// per key.
+func (r *SqlitePeriodRepository) UpsertPeriods(ctx context.Context, periods []*domain.Period) error {
+	for _, p := range periods {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("period %s validation failed: %w", p.ID, err)
+		}
+	}
+
+	return r.withinTx(ctx, func(tx *sql.Tx) error {
+		for _, p := range periods {
+			if p == nil {
+				continue
+			}
+
+			var superseded bool
+			err := tx.QueryRowContext(ctx,
+				`SELECT effective_to IS NOT NULL FROM periods WHERE id=? AND version=?`, p.ID, versionOrDefault(p.Version),
+			).Scan(&superseded)
+			switch {
+			case err == sql.ErrNoRows:
+				if err := insertSqlitePeriod(ctx, tx, p); err != nil {
+					return fmt.Errorf("failed to insert period %s: %w", p.ID, err)
+				}
+			case err != nil:
+				return fmt.Errorf("failed to read period %s: %w", p.ID, err)
+			case superseded:
+				return fmt.Errorf("period %s version %d is superseded and cannot be overwritten", p.ID, versionOrDefault(p.Version))
+			default:
+				if _, err := tx.ExecContext(ctx, `
+					UPDATE periods
+					SET name=?, calendar=?, granularity=?, parent_period_id=?, start_date=?, end_date=?,
+					    audit_updated_by=?, audit_updated_at=?, row_version=row_version+1
+					WHERE id=? AND version=?
+				`,
+					p.Name,
+					string(p.Calendar),
+					string(p.Granularity),
+					p.ParentPeriodID,
+					p.StartDate.UTC(),
+					p.EndDate.UTC(),
+					p.AuditInfo.LastActor(),
+					time.Now().UTC(),
+					p.ID,
+					versionOrDefault(p.Version),
+				); err != nil {
+					return fmt.Errorf("failed to update period %s: %w", p.ID, err)
+				}
+			}
+
+			if err := upsertSqlitePeriodMetadata(ctx, tx, p.ID, p.Metadata); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdatePeriods updates the definition of the current version of existing Periods in place.
+// Every Period must carry the RowVersion it was read with; a stale one fails the whole call
+// with a *dberr.ConflictError. On success the RowVersion of every given Period is incremented.
+func (r *SqlitePeriodRepository) UpdatePeriods(ctx context.Context, periods []*domain.Period) error {
+	if len(periods) == 0 {
+		return nil
+	}
+
+	err := r.withinTx(ctx, func(tx *sql.Tx) error {
+		for _, p := range periods {
+			expected := versionOrDefault(p.RowVersion)
+
+			res, err := tx.ExecContext(ctx, `
+				UPDATE periods
+				SET name=?, granularity=?, parent_period_id=?, start_date=?, end_date=?,
+				    audit_updated_by=?, audit_updated_at=?, row_version=row_version+1
+				WHERE id=? AND effective_to IS NULL AND row_version=?
+			`,
+				p.Name,
+				string(p.Granularity),
+				p.ParentPeriodID,
+				p.StartDate.UTC(),
+				p.EndDate.UTC(),
+				p.AuditInfo.LastActor(),
+				time.Now().UTC(),
+				p.ID,
+				expected,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to update period %s: %w", p.ID, err)
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				var actual int
+				err := tx.QueryRowContext(ctx,
+					`SELECT row_version FROM periods WHERE id=? AND effective_to IS NULL`, p.ID,
+				).Scan(&actual)
+				if err == sql.ErrNoRows {
+					return &dberr.NotFoundError{Entity: "period", ID: p.ID}
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read row version of period %s: %w", p.ID, err)
+				}
+				return &dberr.ConflictError{Entity: "period", ID: p.ID, ExpectedVersion: expected, ActualVersion: actual}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range periods {
+		p.RowVersion = versionOrDefault(p.RowVersion) + 1
+	}
+	return nil
+}
+
+func (r *SqlitePeriodRepository) GetAllPeriods(ctx context.Context) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `WHERE effective_to IS NULL AND deleted_at IS NULL`)
+}
+
+// ForEachPeriod calls fn for the current version of every Period in chronological order (start
+// date, then ID). The periods are read before fn is called, so fn may write to the repository.
+func (r *SqlitePeriodRepository) ForEachPeriod(ctx context.Context, fn func(p *domain.Period) error) error {
+	periods, err := r.queryPeriods(ctx, `WHERE effective_to IS NULL AND deleted_at IS NULL ORDER BY start_date, id`)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range periods {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SqlitePeriodRepository) GetAllPeriodVersions(ctx context.Context) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `ORDER BY id, version`)
+}
+
+func (r *SqlitePeriodRepository) GetPeriodsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `WHERE effective_from <= ? AND (effective_to IS NULL OR effective_to >= ?)`, asOf.UTC(), asOf.UTC())
+}
+
+// SavePeriodVersion closes the current version of a Period and inserts its successor in one
+// transaction. closed must carry the EffectiveTo set by Period.NextVersion.
+func (r *SqlitePeriodRepository) SavePeriodVersion(ctx context.Context, closed *domain.Period, next *domain.Period) error {
+	if closed.EffectiveTo == nil {
+		return fmt.Errorf("period %s version %d must be closed before saving version %d", closed.ID, closed.Version, next.Version)
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("period %s version %d validation failed: %w", next.ID, next.Version, err)
+	}
+
+	return r.withinTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE periods SET effective_to=?
+			WHERE id=? AND version=? AND effective_to IS NULL
+		`, closed.EffectiveTo.UTC(), closed.ID, closed.Version)
+		if err != nil {
+			return fmt.Errorf("failed to close period %s version %d: %w", closed.ID, closed.Version, err)
+		}
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			return fmt.Errorf("period %s version %d is not the current version", closed.ID, closed.Version)
+		}
+
+		if err := insertSqlitePeriod(ctx, tx, next); err != nil {
+			if sqlitedb.IsUniqueViolation(err) {
+				return &dberr.DuplicateError{Entity: "period", ID: next.ID, Key: fmt.Sprintf("version %d", next.Version), Err: err}
+			}
+			return fmt.Errorf("failed to insert period %s version %d: %w", next.ID, next.Version, err)
+		}
+		return nil
+	})
+}
+
+// SetPeriodStatus updates the close status of the current version of a Period. Like in the RDS
+// repository, status changes do not create a new version.
+func (r *SqlitePeriodRepository) SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, updatedBy string) error {
+	return r.execCurrent(ctx, periodID, "", `
+		UPDATE periods SET status=?, audit_updated_by=?, audit_updated_at=?
+		WHERE id=? AND effective_to IS NULL
+	`, string(status), updatedBy, time.Now().UTC(), periodID)
+}
+
+// FindByID returns the current version of a Period; nil, nil when it does not exist.
+func (r *SqlitePeriodRepository) FindByID(ctx context.Context, id string) (*domain.Period, error) {
+	periods, err := r.queryPeriods(ctx, `WHERE id=? AND effective_to IS NULL AND deleted_at IS NULL`, id)
+	if err != nil || len(periods) == 0 {
+		return nil, err // nil, nil when not found
+	}
+	return periods[0], nil
+}
+
+func (r *SqlitePeriodRepository) GetPeriodsStartingBetween(ctx context.Context, from, to time.Time) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `WHERE start_date >= ? AND start_date < ? AND effective_to IS NULL AND deleted_at IS NULL`, from.UTC(), to.UTC())
+}
+
+func (r *SqlitePeriodRepository) GetPeriodsByGranularity(ctx context.Context, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `WHERE granularity=? AND effective_to IS NULL AND deleted_at IS NULL`, string(granularity))
+}
+
+func (r *SqlitePeriodRepository) GetPeriodsByCalendar(ctx context.Context, calendar domain.CalendarType) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `WHERE calendar=? AND effective_to IS NULL AND deleted_at IS NULL`, string(calendar))
+}
+
+func (r *SqlitePeriodRepository) GetPeriodsInRange(ctx context.Context, start, end time.Time, granularity domain.PeriodGranularity) ([]*domain.Period, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("invalid period window: end %s is before start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	if granularity == "" {
+		return r.queryPeriods(ctx, `WHERE start_date <= ? AND end_date >= ? AND effective_to IS NULL AND deleted_at IS NULL`, end.UTC(), start.UTC())
+	}
+	return r.queryPeriods(ctx, `WHERE start_date <= ? AND end_date >= ? AND granularity=? AND effective_to IS NULL AND deleted_at IS NULL`,
+		end.UTC(), start.UTC(), string(granularity))
+}
+
+func (r *SqlitePeriodRepository) SoftDeletePeriod(ctx context.Context, periodID string, deletedBy string) error {
+	return r.execCurrent(ctx, periodID, "does not exist or is already deleted", `
+		UPDATE periods SET deleted_at=?, deleted_by=?
+		WHERE id=? AND effective_to IS NULL AND deleted_at IS NULL
+	`, time.Now().UTC(), deletedBy, periodID)
+}
+
+func (r *SqlitePeriodRepository) RestorePeriod(ctx context.Context, periodID string, restoredBy string) (*domain.Period, error) {
+	if err := r.execCurrent(ctx, periodID, "does not exist or is not deleted", `
+		UPDATE periods SET deleted_at=NULL, deleted_by=NULL, audit_updated_by=?, audit_updated_at=?
+		WHERE id=? AND effective_to IS NULL AND deleted_at IS NOT NULL
+	`, restoredBy, time.Now().UTC(), periodID); err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, periodID)
+}
+
+func (r *SqlitePeriodRepository) GetDeletedPeriods(ctx context.Context) ([]*domain.Period, error) {
+	return r.queryPeriods(ctx, `WHERE effective_to IS NULL AND deleted_at IS NOT NULL`)
+}
+
+// SavePeriodMetadata replaces ALL metadata of a Period with the given map.
+func (r *SqlitePeriodRepository) SavePeriodMetadata(ctx context.Context, periodID string, metadata map[string]string) error {
+	return r.withinTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM period_metadata WHERE period_id=?`, periodID); err != nil {
+			return fmt.Errorf("failed to clear metadata of period %s: %w", periodID, err)
+		}
+		return upsertSqlitePeriodMetadata(ctx, tx, periodID, metadata)
+	})
+}
+
+func (r *SqlitePeriodRepository) GetAllPeriodMetadata(ctx context.Context) (map[string]map[string]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT period_id, key, value FROM period_metadata`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query period metadata: %w", err)
+	}
+	defer rows.Close()
+
+	metadata := make(map[string]map[string]string)
+	for rows.Next() {
+		var periodID, key, value string
+		if err := rows.Scan(&periodID, &key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan period metadata row: %w", err)
+		}
+		if metadata[periodID] == nil {
+			metadata[periodID] = make(map[string]string)
+		}
+		metadata[periodID][key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate period metadata rows: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// queryPeriods selects the Periods matching clause (a WHERE and/or ORDER BY clause on the
+// periods table) with their audit info and metadata attached.
+func (r *SqlitePeriodRepository) queryPeriods(ctx context.Context, clause string, args ...any) ([]*domain.Period, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+periodColumns+`, audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		FROM periods `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query periods: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []*domain.Period
+	for rows.Next() {
+		info := &audit.AuditInfo{}
+		p, err := scanPeriod(extraScanner{row: rows, extra: []any{&info.CreatedBy, &info.CreatedAt, &info.UpdatedBy, &info.UpdatedAt}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan period row: %w", err)
+		}
+		p.AuditInfo = info
+		periods = append(periods, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate period rows: %w", err)
+	}
+	if len(periods) == 0 {
+		return nil, nil
+	}
+
+	metadata, err := r.GetAllPeriodMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range periods {
+		p.Metadata = metadata[p.ID]
+	}
+
+	return periods, nil
+}
+
+// execCurrent runs a single-row UPDATE of the current version of a Period. When no row
+// matches, it fails with a *dberr.NotFoundError giving notFound as the reason.
+func (r *SqlitePeriodRepository) execCurrent(ctx context.Context, periodID string, notFound string, query string, args ...any) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update period %s: %w", periodID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID, Reason: notFound}
+	}
+	return nil
+}
+
+// withinTx runs fn in a transaction (or the ambient one, see txn.Begin) and commits it when
+// fn returns nil.
+func (r *SqlitePeriodRepository) withinTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx.Tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertSqlitePeriod inserts one period row within tx, in its stored form (effective status,
+// times in UTC).
+func insertSqlitePeriod(ctx context.Context, tx *sql.Tx, p *domain.Period) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO periods (
+			id, name, calendar, granularity, parent_period_id, start_date, end_date,
+			version, effective_from, effective_to, status,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+	`,
+		p.ID,
+		p.Name,
+		string(p.Calendar),
+		string(p.Granularity),
+		p.ParentPeriodID,
+		p.StartDate.UTC(),
+		p.EndDate.UTC(),
+		versionOrDefault(p.Version),
+		p.EffectiveFrom.UTC(),
+		utcOrNil(p.EffectiveTo),
+		string(p.EffectiveStatus()),
+		p.AuditInfo.CreatedBy,
+		p.AuditInfo.CreatedAt.UTC(),
+		p.AuditInfo.UpdatedBy,
+		utcOrNil(p.AuditInfo.UpdatedAt),
+	)
+	return err
+}
+
+// upsertSqlitePeriodMetadata writes the key/value pairs of a Period into period_metadata
+// within tx, overwriting existing keys.
+func upsertSqlitePeriodMetadata(ctx context.Context, tx *sql.Tx, periodID string, metadata map[string]string) error {
+	for key, value := range metadata {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO period_metadata (period_id, key, value) VALUES (?,?,?)
+			ON CONFLICT (period_id, key) DO UPDATE SET value=excluded.value
+		`, periodID, key, value); err != nil {
+			return fmt.Errorf("failed to upsert metadata %q of period %s: %w", key, periodID, err)
+		}
+	}
+	return nil
+}
+
+// utcOrNil converts an optional time to UTC; nil stays NULL.
+func utcOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
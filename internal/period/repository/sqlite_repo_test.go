@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/sqlitedb"
+)
+
+func TestSqlitePeriodRepository(t *testing.T) {
+	testPeriodRepository(t, func(t *testing.T) PeriodRepository {
+		db, err := sqlitedb.Open(context.Background(), ":memory:")
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return NewSqlitePeriodRepository(db)
+	})
+}
+
+func TestSqlitePeriodRepositoryKeepsPeriodsAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cso-book.db")
+
+	db, err := sqlitedb.Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := NewSqlitePeriodRepository(db).SavePeriods(ctx, domain.GeneratePeriods(2026, 2026)); err != nil {
+		t.Fatalf("SavePeriods: %v", err)
+	}
+	db.Close()
+
+	// Opening again must keep the data (CREATE TABLE IF NOT EXISTS)
+	db, err = sqlitedb.Open(ctx, path)
+	if err != nil {
+		t.Fatalf("second Open: %v", err)
+	}
+	defer db.Close()
+
+	all, err := NewSqlitePeriodRepository(db).GetAllPeriods(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPeriods: %v", err)
+	}
+	if len(all) != 17 {
+		t.Errorf("GetAllPeriods after reopening returned %d periods, want 17", len(all))
+	}
+}
@@ -3,21 +3,33 @@ package service
 import (
 	"context"
 	"fmt"
-	"sort"
+	"sync"
 	"time"
 
 	"github.com/nholding/cso-book/internal/period/domain"
 	"github.com/nholding/cso-book/internal/period/repository"
+	"github.com/nholding/cso-book/internal/platform/dberr"
 )
 
 type PeriodService struct {
-	repo  *repository.RdsPeriodRepository
-	store *domain.PeriodStore
+	repo      repository.PeriodRepository
+	store     *domain.PeriodStore
+	validator *domain.CalendarValidator
+
+	metadataMu sync.Mutex // Serializes TagPeriod/UntagPeriod, so DB writes land in store order
 }
 
-func NewPeriodService(repo *repository.RdsPeriodRepository) *PeriodService {
+// NewPeriodService creates a PeriodService on top of any PeriodRepository: the RDS repository in
+// production, the in-memory repository in tests and local development, or a decorator such as
+// ChaosPeriodRepository around either of them.
+//
+// Example:
+//
+//	ps := NewPeriodService(repository.NewInMemoryPeriodRepository())
+func NewPeriodService(repo repository.PeriodRepository) *PeriodService {
 	return &PeriodService{
-		repo: repo,
+		repo:      repo,
+		validator: domain.NewCalendarValidator(),
 	}
 }
 
@@ -53,8 +65,8 @@ func NewPeriodService(repo *repository.RdsPeriodRepository) *PeriodService {
 // RESPONSIBILITIES (IN ORDER):
 //
 //  1. Load all existing periods from persistent storage
-//  2. Generate Gregorian calendar periods if none exist
-//  3. Persist generated calendar periods
+//  2. Generate the Gregorian calendar periods of [startYear, endYear] that do not exist yet
+//  3. Persist (upsert) generated calendar periods
 //  4. Initialize the in-memory PeriodStore
 //  5. Generate fiscal calendars (if configured)
 //  6. Validate structural hierarchy (CAL + FY)
@@ -67,8 +79,8 @@ func NewPeriodService(repo *repository.RdsPeriodRepository) *PeriodService {
 //
 // EXAMPLE USAGE:
 //
-//	repo := NewRdsPeriodRepository(db)
-//	ps   := NewPeriodService(repo)
+//	repo, _ := repository.NewRdsPeriodRepository(&cfg) // or repository.NewInMemoryPeriodRepository()
+//	ps      := NewPeriodService(repo)
 //
 //	fiscalCfg := domain.FiscalCalendarConfig{
 //	    StartYear:  2026,
@@ -113,23 +125,48 @@ func (s *PeriodService) InitializePeriods(ctx context.Context, startYear int, en
 		return fmt.Errorf("failed to load periods from DB: %w", err)
 	}
 
-	// STEP 2: Generate Gregorian calendar periods if none exist
+	// STEP 2: Generate Gregorian calendar periods that do not exist yet
 	// This typically occurs:
 	//   - On first deployment
 	//   - In a brand-new environment
+	//   - When the configured year range is extended
 	//
 	// IMPORTANT:
 	//   Gregorian periods are ALWAYS generated first
 	//   because all other logic depends on months existing.
-	if len(periods) == 0 {
+	//   Periods are upserted, so re-running initialization (e.g. after a crash
+	//   halfway through) is idempotent instead of failing on duplicate IDs.
+	loaded := make(map[string]bool, len(periods))
+	for _, p := range periods {
+		loaded[p.ID] = true
+	}
 
-		// Generate YEAR → QUARTER → MONTH
-		periods = domain.GeneratePeriods(startYear, endYear)
+	// Soft-deleted periods were removed on purpose: never regenerate them, neither here nor
+	// as part of a fiscal calendar (STEP 4)
+	deletedPeriods, err := s.repo.GetDeletedPeriods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load deleted periods from DB: %w", err)
+	}
+	deleted := make(map[string]bool, len(deletedPeriods))
+	for _, p := range deletedPeriods {
+		loaded[p.ID] = true
+		deleted[p.ID] = true
+	}
 
+	// Generate YEAR → QUARTER → MONTH
+	var missing []*domain.Period
+	for _, p := range domain.GeneratePeriods(startYear, endYear) {
+		if !loaded[p.ID] {
+			missing = append(missing, p)
+		}
+	}
+
+	if len(missing) > 0 {
 		// Persist generated periods
-		if err := s.repo.SavePeriods(ctx, periods); err != nil {
+		if err := s.repo.UpsertPeriods(ctx, missing); err != nil {
 			return fmt.Errorf("failed to persist generated calendar periods: %w", err)
 		}
+		periods = append(periods, missing...)
 	}
 
 	// STEP 3: Initialize in-memory PeriodStore
@@ -145,34 +182,42 @@ func (s *PeriodService) InitializePeriods(ctx context.Context, startYear int, en
 	//   - Must be generated BEFORE validation
 	for _, cfg := range fiscalConfigs {
 		fyID := fmt.Sprintf("FY%d", cfg.StartYear)
+		if cfg.WeekBased {
+			fyID = fmt.Sprintf("FW%d", cfg.StartYear)
+		}
 
-		if s.store.FindByID(fyID) != nil &&
-			s.store.FindByID(fyID+"-Q1") != nil {
+		exists := func(id string) bool { return s.store.FindByID(id) != nil || deleted[id] }
+		if exists(fyID) && exists(fyID+"-Q1") {
 			continue
 		}
 
-		fiscalPeriods, err := domain.GenerateFiscalYear(s.store.Months, cfg)
+		var generated []*domain.Period
+		if cfg.WeekBased {
+			generated, err = domain.GenerateWeekFiscalYear(cfg)
+		} else {
+			generated, err = domain.GenerateFiscalYear(s.store.Months(), cfg)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to generate fiscal year FY%d: %w", cfg.StartYear, err)
+			return fmt.Errorf("failed to generate fiscal year %s: %w", fyID, err)
 		}
 
-		if err := s.repo.SavePeriods(ctx, fiscalPeriods); err != nil {
-			return fmt.Errorf("failed to persist fiscal year FY%d: %w", cfg.StartYear, err)
+		fiscalPeriods := make([]*domain.Period, 0, len(generated))
+		for _, p := range generated {
+			if !deleted[p.ID] {
+				fiscalPeriods = append(fiscalPeriods, p)
+			}
+		}
+		if len(fiscalPeriods) == 0 {
+			continue
 		}
 
-		for _, p := range fiscalPeriods {
-			s.store.Periods[p.ID] = p
-
-			switch p.Granularity {
-			case domain.CalendarYearPeriod:
-				s.store.Years = append(s.store.Years, p)
-			case domain.QuarterlyPeriod:
-				s.store.Quarters = append(s.store.Quarters, p)
-			}
+		// Upsert: a fiscal year that was only partially persisted is completed, not rejected
+		if err := s.repo.UpsertPeriods(ctx, fiscalPeriods); err != nil {
+			return fmt.Errorf("failed to persist fiscal year %s: %w", fyID, err)
 		}
-	}
 
-	s.store.SortAll()
+		s.store.Put(fiscalPeriods...)
+	}
 
 	// ------------------------------------------------------------
 	// STEP 5: Validate structural hierarchy
@@ -182,8 +227,12 @@ func (s *PeriodService) InitializePeriods(ctx context.Context, startYear int, en
 	//   ✔ No CAL/FY cross-contamination
 	//   ✔ Months are shared atomic leaves
 	//   ✔ Granularity ordering is correct
-	if errs := s.ValidateHierarchy(); len(errs) > 0 {
-		return fmt.Errorf("period hierarchy validation failed")
+	report, err := s.ValidateHierarchy()
+	if err != nil {
+		return err
+	}
+	if report.HasErrors() {
+		return fmt.Errorf("period hierarchy validation failed: %w", report.Err())
 	}
 
 	// ------------------------------------------------------------
@@ -194,8 +243,21 @@ func (s *PeriodService) InitializePeriods(ctx context.Context, startYear int, en
 	//   ✔ Boundaries align to month start/end
 	//   ✔ Safe for trading, delivery, and risk
 	// ------------------------------------------------------------
-	if errs := s.ValidateFiscalCoverage(); len(errs) > 0 {
-		return fmt.Errorf("fiscal calendar validation failed")
+	report, err = s.ValidateFiscalCoverage()
+	if err != nil {
+		return err
+	}
+	if report.HasErrors() {
+		return fmt.Errorf("fiscal calendar validation failed: %w", report.Err())
+	}
+
+	// Week-based (52/53-week) fiscal years are validated by week invariants instead
+	report, err = s.ValidateWeekFiscalCoverage()
+	if err != nil {
+		return err
+	}
+	if report.HasErrors() {
+		return fmt.Errorf("week-based fiscal calendar validation failed: %w", report.Err())
 	}
 
 	// ------------------------------------------------------------
@@ -255,6 +317,8 @@ func (s *PeriodService) InitializePeriods(ctx context.Context, startYear int, en
 //	✘ FY periods having CAL parents
 //	✘ Month → FY parent links
 //	✘ Self-referential periods
+//	✘ Cyclic parent chains (A → B → A)         → *domain.ParentCycleError
+//	✘ Chains deeper than Year → Quarter → Month → *domain.HierarchyDepthError
 //	✘ Inverted or overlapping hierarchies
 //
 // WHEN TO CALL:
@@ -273,231 +337,29 @@ func (s *PeriodService) InitializePeriods(ctx context.Context, startYear int, en
 //
 // EXAMPLE USAGE:
 //
-//	errs := periodService.ValidateHierarchy()
-//	if len(errs) > 0 {
-//	    for _, err := range errs {
-//	        log.Println("Period hierarchy validation error:", err)
+//	report, err := periodService.ValidateHierarchy()
+//	if err != nil || report.HasErrors() {
+//	    for _, issue := range report.Issues {
+//	        log.Println("Period hierarchy validation error:", issue.Rule, issue.PeriodIDs, issue.Message)
 //	    }
 //	    os.Exit(1)
 //	}
 //
 // EXPECTED OUTPUT (VALID):
 //
-//	No errors returned
+//	Empty report (report.Len() == 0)
 //
 // EXAMPLE INVALID OUTPUTS:
 //
 //   - "period FY2026-Q1 (FY) has parent 2026 (CAL) with different calendar type"
 //   - "child 2026-FEB references missing parent 2026-QQ"
 //   - "period 2026-Q1 has parent 2026-MAR which is not a larger granularity"
-func (s *PeriodService) ValidateHierarchy() []error {
-
-	// ------------------------------------------------------------
-	// Guard clause: PeriodStore must be initialized
-	// ------------------------------------------------------------
+func (s *PeriodService) ValidateHierarchy() (domain.ValidationReport, error) {
 	if s.store == nil {
-		return []error{fmt.Errorf("period store not initialised")}
+		return domain.ValidationReport{}, fmt.Errorf("period store not initialised")
 	}
 
-	var errs []error
-
-	// ------------------------------------------------------------
-	// Validation is performed by granularity order
-	// (for readability only; logic does not depend on order)
-	// ------------------------------------------------------------
-	for _, periodList := range [][]*domain.Period{
-		s.store.Years,
-		s.store.Quarters,
-		s.store.Months,
-	} {
-
-		for _, p := range periodList {
-
-			// ----------------------------------------------------
-			// Defensive programming: skip nil entries
-			// ----------------------------------------------------
-			if p == nil {
-				continue
-			}
-
-			// ----------------------------------------------------
-			// YEAR periods (CAL or FY) are ROOTS
-			// ----------------------------------------------------
-			// They must not have parents and require no validation
-			// beyond existence.
-			if p.Granularity == domain.CalendarYearPeriod {
-				continue
-			}
-
-			// ----------------------------------------------------
-			// SPECIAL CASE: MONTHS
-			// ----------------------------------------------------
-			// Months are atomic delivery units.
-			//
-			// They:
-			//   - MAY belong to a CAL hierarchy (YEAR → Q → MONTH)
-			//   - MUST NOT be required to belong to FY hierarchy
-			//
-			// Therefore:
-			//   - We validate MONTHS ONLY within their declared calendar
-			//   - We do NOT require FY parents for months
-			if p.Granularity == domain.MonthlyPeriod {
-
-				// Month must belong to CALENDAR
-				if p.Calendar != domain.CalendarGregorian {
-					errs = append(errs,
-						fmt.Errorf(
-							"month %s has invalid calendar %s (months must be Gregorian)",
-							p.ID,
-							p.Calendar,
-						),
-					)
-				}
-
-				// If month declares a parent, validate it normally
-				if p.ParentPeriodID != nil {
-
-					parent, exists := s.store.Periods[*p.ParentPeriodID]
-					if !exists {
-						errs = append(errs,
-							fmt.Errorf(
-								"month %s references missing parent %s",
-								p.ID,
-								*p.ParentPeriodID,
-							),
-						)
-						continue
-					}
-
-					// Parent must be CAL
-					if parent.Calendar != domain.CalendarGregorian {
-						errs = append(errs,
-							fmt.Errorf(
-								"month %s has non-Gregorian parent %s",
-								p.ID,
-								parent.ID,
-							),
-						)
-					}
-
-					// Parent must be larger granularity
-					if parent.GranularityRank() <= p.GranularityRank() {
-						errs = append(errs,
-							fmt.Errorf(
-								"month %s has invalid parent granularity %s",
-								p.ID,
-								parent.Granularity,
-							),
-						)
-					}
-
-					// Parent must contain month by date
-					if parent.StartDate.After(p.StartDate) ||
-						parent.EndDate.Before(p.EndDate) {
-						errs = append(errs,
-							fmt.Errorf(
-								"month %s is not fully contained in parent %s",
-								p.ID,
-								parent.ID,
-							),
-						)
-					}
-				}
-
-				// Month validation complete
-				continue
-			}
-
-			// ----------------------------------------------------
-			// ALL NON-YEAR, NON-MONTH PERIODS (i.e. QUARTERS)
-			// ----------------------------------------------------
-
-			// Rule 1: Parent must exist
-			if p.ParentPeriodID == nil {
-				errs = append(errs,
-					fmt.Errorf(
-						"period %s (%s) has no parent but is not a year",
-						p.ID,
-						p.Granularity,
-					),
-				)
-				continue
-			}
-
-			parentID := *p.ParentPeriodID
-			parent, exists := s.store.Periods[parentID]
-			if !exists {
-				errs = append(errs,
-					fmt.Errorf(
-						"child %s references missing parent %s",
-						p.ID,
-						parentID,
-					),
-				)
-				continue
-			}
-
-			// Rule 2: Calendar isolation (CRITICAL)
-			if parent.Calendar != p.Calendar {
-				errs = append(errs,
-					fmt.Errorf(
-						"period %s (%s) has parent %s (%s) with different calendar type",
-						p.ID,
-						p.Calendar,
-						parent.ID,
-						parent.Calendar,
-					),
-				)
-				continue
-			}
-
-			// Rule 3: No self-reference
-			if parent.ID == p.ID {
-				errs = append(errs,
-					fmt.Errorf(
-						"period %s cannot reference itself as a parent",
-						p.ID,
-					),
-				)
-			}
-
-			// Rule 4: Granularity ordering
-			if parent.GranularityRank() <= p.GranularityRank() {
-				errs = append(errs,
-					fmt.Errorf(
-						"period %s (%s) has parent %s (%s) which is not a larger granularity",
-						p.ID,
-						p.Granularity,
-						parent.ID,
-						parent.Granularity,
-					),
-				)
-			}
-
-			// Rule 5: Date containment
-			if parent.StartDate.After(p.StartDate) {
-				errs = append(errs,
-					fmt.Errorf(
-						"child %s starts before parent %s",
-						p.ID,
-						parent.ID,
-					),
-				)
-			}
-
-			if parent.EndDate.Before(p.EndDate) {
-				errs = append(errs,
-					fmt.Errorf(
-						"child %s ends after parent %s",
-						p.ID,
-						parent.ID,
-					),
-				)
-			}
-		}
-	}
-
-	return errs
+	return domain.NewValidationReport(s.validator.ValidateHierarchy(s.store.All())), nil
 }
 
 // ValidateFiscalCoverage
@@ -556,16 +418,18 @@ func (s *PeriodService) ValidateHierarchy() []error {
 //
 // RETURN VALUE:
 //
-//	[]error
-//	  - Empty slice (nil or len==0): fiscal calendars are valid
-//	  - Non-empty slice: application MUST NOT continue
+//	domain.ValidationReport (rule FISCAL_COVERAGE)
+//	  - report.HasErrors() == false: fiscal calendars are valid
+//	  - report.HasErrors() == true:  application MUST NOT continue
+//	error
+//	  - non-nil only when the store is not initialised
 //
 // EXAMPLE USAGE:
 //
-//	errs := periodService.ValidateFiscalCoverage()
-//	if len(errs) > 0 {
-//	    for _, err := range errs {
-//	        log.Println("Fiscal validation error:", err)
+//	report, err := periodService.ValidateFiscalCoverage()
+//	if err != nil || report.HasErrors() {
+//	    for _, issue := range report.Issues {
+//	        log.Println("Fiscal validation error:", issue.Message)
 //	    }
 //	    os.Exit(1)
 //	}
@@ -588,165 +452,238 @@ func (s *PeriodService) ValidateHierarchy() []error {
 //   - FY starts mid-month
 //   - FY overlaps months incorrectly
 
-func (s *PeriodService) ValidateFiscalCoverage() []error {
+func (s *PeriodService) ValidateFiscalCoverage() (domain.ValidationReport, error) {
+	if s.store == nil {
+		return domain.ValidationReport{}, fmt.Errorf("period store not initialised")
+	}
 
-	// ------------------------------------------------------------
-	// Guard clause: the service must be initialized
-	// ------------------------------------------------------------
-	// If the in-memory PeriodStore has not been initialized,
-	// validation cannot proceed safely.
+	return domain.NewValidationReport(s.validator.ValidateFiscalCoverage(s.store.All())), nil
+}
+
+// TagPeriod sets a metadata tag on a period and persists the period's full metadata.
+// The in-memory store is only updated after the DB write succeeded.
+//
+// Example:
+//
+//	err := periodService.TagPeriod(ctx, "2026-Q1", "budget-relevant", "true")
+func (s *PeriodService) TagPeriod(ctx context.Context, periodID, key, value string) error {
+	err := s.updateMetadata(ctx, periodID, func(p *domain.Period) {
+		p.SetTag(key, value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist tag %q on period %s: %w", key, periodID, err)
+	}
+	return nil
+}
+
+// UntagPeriod removes a metadata tag from a period and persists the remaining metadata.
+func (s *PeriodService) UntagPeriod(ctx context.Context, periodID, key string) error {
+	err := s.updateMetadata(ctx, periodID, func(p *domain.Period) {
+		p.RemoveTag(key)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove tag %q from period %s: %w", key, periodID, err)
+	}
+	return nil
+}
+
+// updateMetadata persists the metadata that results from applying change to a period, then
+// applies the same change inside store.Update, so it lands on the latest snapshot of the period.
+// Metadata changes are serialized: two concurrent tags both end up in the DB and the store
+// instead of the last write dropping the other's key.
+func (s *PeriodService) updateMetadata(ctx context.Context, periodID string, change func(p *domain.Period)) error {
 	if s.store == nil {
-		return []error{fmt.Errorf("period store not initialised")}
+		return fmt.Errorf("period store not initialised")
 	}
 
-	var errs []error
+	s.metadataMu.Lock()
+	defer s.metadataMu.Unlock()
 
-	// ------------------------------------------------------------
-	// STEP 1: Identify all fiscal year periods
-	// ------------------------------------------------------------
-	// Fiscal years are defined as:
-	//   - Calendar == FY
-	//   - Granularity == YEAR
-	//
-	// Note:
-	//   Fiscal years may coexist with calendar years in the same store.
-	//   They are independent overlays and validated independently.
-	var fiscalYears []*domain.Period
+	p := s.store.FindByID(periodID)
+	if p == nil {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
 
-	for _, y := range s.store.Years {
-		if y == nil {
-			continue
-		}
+	next := p.Clone()
+	change(next)
+	metadata := next.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
 
-		if y.Calendar == domain.CalendarFiscal &&
-			y.Granularity == domain.CalendarYearPeriod {
-			fiscalYears = append(fiscalYears, y)
-		}
+	if err := s.repo.SavePeriodMetadata(ctx, periodID, metadata); err != nil {
+		return err
 	}
 
-	// ------------------------------------------------------------
-	// No fiscal years configured is NOT an error
-	// ------------------------------------------------------------
-	// This allows deployments that only use calendar periods.
-	if len(fiscalYears) == 0 {
+	return s.store.Update(periodID, change)
+}
+
+// PeriodsWithTag returns all periods carrying the given metadata key, e.g. "budget-relevant".
+func (s *PeriodService) PeriodsWithTag(key string) []*domain.Period {
+	if s.store == nil {
 		return nil
 	}
 
-	// ------------------------------------------------------------
-	// STEP 2: Validate each fiscal year independently
-	// ------------------------------------------------------------
-	for _, fy := range fiscalYears {
-
-		// --------------------------------------------------------
-		// STEP 2.1: Collect all Gregorian months fully inside FY
-		// --------------------------------------------------------
-		// A month belongs to a fiscal year IF AND ONLY IF:
-		//
-		//   month.Start >= fy.Start AND month.End <= fy.End
-		//
-		// We do NOT:
-		//   - Check parent hierarchy
-		//   - Require fiscal ownership
-		//   - Care which CAL year/quarter the month belongs to
-		//
-		// This guarantees a single, unambiguous mapping
-		// from fiscal year → months.
-		var months []*domain.Period
-
-		for _, m := range s.store.Months {
-			if m == nil {
-				continue
-			}
+	return s.store.FilterByTag(key)
+}
 
-			if !m.StartDate.Before(fy.StartDate) &&
-				!m.EndDate.After(fy.EndDate) {
-				months = append(months, m)
-			}
-		}
+// CorrectPeriod
+//
+// PURPOSE:
+//
+//	Applies a correction to a period definition WITHOUT mutating history.
+//	The current version is closed at effectiveFrom and a new version carrying
+//	the correction becomes current. Reports produced before the correction can
+//	still be reproduced with LoadStoreAsOf.
+//
+// EXAMPLE USAGE (fiscal year moves from April to July start):
+//
+//	err := periodService.CorrectPeriod(ctx, "FY2026", effectiveFrom, "user@internal.local",
+//	    func(p *domain.Period) {
+//	        p.StartDate = time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+//	        p.EndDate = p.StartDate.AddDate(1, 0, 0).Add(-time.Nanosecond)
+//	    })
+func (s *PeriodService) CorrectPeriod(ctx context.Context, periodID string, effectiveFrom time.Time, user string, apply func(p *domain.Period)) error {
+	if s.store == nil {
+		return fmt.Errorf("period store not initialised")
+	}
 
-		// --------------------------------------------------------
-		// RULE 1: Fiscal year must span EXACTLY 12 months
-		// --------------------------------------------------------
-		// Commodity trades for a fiscal year MUST map to exactly
-		// twelve delivery months — no more, no fewer.
-		if len(months) != 12 {
-			errs = append(errs,
-				fmt.Errorf(
-					"fiscal year %s spans %d months (expected exactly 12)",
-					fy.ID,
-					len(months),
-				),
-			)
-			// We continue validation to report ALL problems.
-		}
+	current := s.store.FindByID(periodID)
+	if current == nil {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
 
-		// --------------------------------------------------------
-		// STEP 2.2: Sort months chronologically
-		// --------------------------------------------------------
-		// Ensures deterministic validation and gap detection.
-		sort.Slice(months, func(i, j int) bool {
-			return months[i].StartDate.Before(months[j].StartDate)
-		})
-
-		// --------------------------------------------------------
-		// RULE 2: Months must be contiguous (no gaps or overlaps)
-		// --------------------------------------------------------
-		// Each month must start immediately after the previous
-		// month ends.
-		for i := 1; i < len(months); i++ {
-			prev := months[i-1]
-			curr := months[i]
-
-			expectedStart := prev.EndDate.Add(time.Nanosecond)
-
-			if !curr.StartDate.Equal(expectedStart) {
-				errs = append(errs,
-					fmt.Errorf(
-						"fiscal year %s has gap or overlap between %s and %s",
-						fy.ID,
-						prev.ID,
-						curr.ID,
-					),
-				)
-			}
-		}
+	// Work on a copy so the store is untouched if persisting fails
+	closed := *current
+	next, err := closed.NextVersion(effectiveFrom, user)
+	if err != nil {
+		return fmt.Errorf("failed to create new version of period %s: %w", periodID, err)
+	}
 
-		// --------------------------------------------------------
-		// RULE 3: Boundary alignment
-		// --------------------------------------------------------
-		// The fiscal year MUST:
-		//   - Start exactly at the beginning of a month
-		//   - End exactly at the end of a month
-		//
-		// Partial-month fiscal years are NOT allowed because they
-		// break delivery, settlement, and risk calculations.
-		if len(months) > 0 {
-			first := months[0]
-			last := months[len(months)-1]
-
-			if !first.StartDate.Equal(fy.StartDate) {
-				errs = append(errs,
-					fmt.Errorf(
-						"fiscal year %s does not start on a month boundary (starts %s)",
-						fy.ID,
-						first.StartDate,
-					),
-				)
-			}
+	apply(next)
 
-			if !last.EndDate.Equal(fy.EndDate) {
-				errs = append(errs,
-					fmt.Errorf(
-						"fiscal year %s does not end on a month boundary (ends %s)",
-						fy.ID,
-						last.EndDate,
-					),
-				)
-			}
+	if err := s.repo.SavePeriodVersion(ctx, &closed, next); err != nil {
+		return fmt.Errorf("failed to persist new version of period %s: %w", periodID, err)
+	}
+
+	// Swap the current version in the store
+	s.store.Put(next)
+
+	return nil
+}
+
+// SetPeriodStatus moves a period through its close lifecycle (OPEN → SOFT_CLOSED → HARD_CLOSED).
+// The transition is validated in the domain, persisted, and only then applied to the store.
+//
+// Example:
+//
+//	err := periodService.SetPeriodStatus(ctx, "2026-JAN", domain.PeriodHardClosed, "controller@internal.local")
+func (s *PeriodService) SetPeriodStatus(ctx context.Context, periodID string, status domain.PeriodStatus, user string) error {
+	if s.store == nil {
+		return fmt.Errorf("period store not initialised")
+	}
+
+	p := s.store.FindByID(periodID)
+	if p == nil {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
+
+	candidate := *p
+	if err := candidate.TransitionStatus(status); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetPeriodStatus(ctx, periodID, status, user); err != nil {
+		return fmt.Errorf("failed to persist status of period %s: %w", periodID, err)
+	}
+
+	return s.store.Update(periodID, func(p *domain.Period) {
+		p.Status = status
+		p.AuditInfo.UpdateAuditInfo(user)
+	})
+}
+
+// DeletePeriod soft-deletes a period: it stays in the DB and can be restored, but is removed
+// from the store and therefore from validation, breakdowns and lookups. A period that still has
+// live children (e.g. a quarter with months) cannot be deleted, since that would orphan them.
+//
+// Example:
+//
+//	err := periodService.DeletePeriod(ctx, "2031-JAN", "ops@internal.local")
+func (s *PeriodService) DeletePeriod(ctx context.Context, periodID string, user string) error {
+	if s.store == nil {
+		return fmt.Errorf("period store not initialised")
+	}
+
+	if s.store.FindByID(periodID) == nil {
+		return &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
+
+	for _, p := range s.store.All() {
+		if p.ParentPeriodID != nil && *p.ParentPeriodID == periodID {
+			return fmt.Errorf("period %s still has child %s; delete its children first", periodID, p.ID)
 		}
 	}
 
-	return errs
+	if err := s.repo.SoftDeletePeriod(ctx, periodID, user); err != nil {
+		return fmt.Errorf("failed to delete period %s: %w", periodID, err)
+	}
+
+	s.store.Remove(periodID)
+	return nil
+}
+
+// RestorePeriod undoes DeletePeriod and puts the period back into the store. Restore parents
+// before their children; otherwise ValidateHierarchy reports the restored child.
+//
+// Example:
+//
+//	err := periodService.RestorePeriod(ctx, "2031-JAN", "ops@internal.local")
+func (s *PeriodService) RestorePeriod(ctx context.Context, periodID string, user string) error {
+	if s.store == nil {
+		return fmt.Errorf("period store not initialised")
+	}
+
+	// The store copy is the row as restored; a re-read could hit a replica without the restore
+	p, err := s.repo.RestorePeriod(ctx, periodID, user)
+	if err != nil {
+		return fmt.Errorf("failed to restore period %s: %w", periodID, err)
+	}
+
+	s.store.Put(p)
+	return nil
+}
+
+// LoadStoreAsOf builds a separate, read-only PeriodStore with the period definitions as they
+// were effective at asOf. The service's own (current) store is not affected.
+//
+// Example:
+//
+//	store, err := periodService.LoadStoreAsOf(ctx, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+//	months := store.BreakDownTradePeriodRange(pr)
+func (s *PeriodService) LoadStoreAsOf(ctx context.Context, asOf time.Time) (*domain.PeriodStore, error) {
+	periods, err := s.repo.GetPeriodsAsOf(ctx, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load periods as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+
+	return domain.NewPeriodStore(periods), nil
+}
+
+// ValidateWeekFiscalCoverage
+// validates all week-based (FW) fiscal years in the store:
+//
+//   - each year spans 52 or 53 whole weeks with 13/13/13/13(14)-week quarters
+//   - all years end on the same weekday (taken from the earliest year)
+//   - consecutive years are contiguous
+//
+// No week-based fiscal years configured is NOT an error.
+func (s *PeriodService) ValidateWeekFiscalCoverage() (domain.ValidationReport, error) {
+	if s.store == nil {
+		return domain.ValidationReport{}, fmt.Errorf("period store not initialised")
+	}
+
+	return domain.NewValidationReport(s.validator.ValidateWeekFiscalCoverage(s.store.All())), nil
 }
 
 func (s *PeriodService) GetPeriodStore() *domain.PeriodStore {
@@ -803,6 +740,37 @@ func (s *PeriodService) BreakDownTradeRange(pr domain.PeriodRange) []string {
 	return s.store.BreakDownTradePeriodRange(pr)
 }
 
+// BreakDownTradeRangeTo breaks a PeriodRange down to the given granularity
+// (MONTHLY, QUARTERLY, CALENDAR, WEEKLY or DAILY) using the in-memory store.
+// See domain.PeriodStore.BreakDownPeriodRangeTo for the rules per granularity.
+//
+// Example:
+//
+//	quarters, err := ps.BreakDownTradeRangeTo(pr, domain.QuarterlyPeriod)
+func (s *PeriodService) BreakDownTradeRangeTo(pr domain.PeriodRange, target domain.PeriodGranularity) ([]string, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("period store not initialised")
+	}
+
+	return s.store.BreakDownPeriodRangeTo(pr, target)
+}
+
+// AggregateToParent rolls a set of month IDs up to the minimal covering set of Gregorian
+// years, quarters and months using the in-memory store. It is the inverse of BreakDownTradeRange
+// and is meant for displaying trades compactly.
+// See domain.PeriodStore.AggregateToParent for the rules.
+//
+// Example:
+//
+//	ids, err := ps.AggregateToParent([]string{"2026-JAN", "2026-FEB", "2026-MAR"}) // → [2026-Q1]
+func (s *PeriodService) AggregateToParent(monthIDs []string) ([]string, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("period store not initialised")
+	}
+
+	return s.store.AggregateToParent(monthIDs)
+}
+
 //func (s *PeriodService) BreakDownTradeRange(pr domain.PeriodRange) []string {
 //	if s.store == nil {
 //		return nil
@@ -844,38 +812,76 @@ func (s *PeriodService) BreakDownTradeRange(pr domain.PeriodRange) []string {
 //
 // EXAMPLE:
 //
-//	errs := ps.ValidateOverlaps()
-//	if len(errs) > 0 {
-//	    for _, e := range errs {
-//	        fmt.Println(e)
+//	report, err := ps.ValidateOverlaps()
+//	if err == nil {
+//	    for _, issue := range report.Issues {
+//	        fmt.Println(issue.PeriodIDs, issue.Message)
 //	    }
 //	}
 //
 // EXPECTED OUTPUT (if overlaps exist):
 //
 //	"Overlap detected (MONTHLY): 2026-FEB overlaps with 2026-MAR"
-func (s *PeriodService) ValidateOverlaps() []error {
+func (s *PeriodService) ValidateOverlaps() (domain.ValidationReport, error) {
 	if s.store == nil {
-		return []error{fmt.Errorf("period store not initialised")}
+		return domain.ValidationReport{}, fmt.Errorf("period store not initialised")
 	}
 
-	// Collect all periods into a slice
-	periodList := make([]*domain.Period, 0, len(s.store.Periods))
-	for _, p := range s.store.Periods {
-		periodList = append(periodList, p)
+	return domain.NewValidationReport(s.validator.ValidateOverlaps(s.store.All())), nil
+}
+
+// ValidateGaps
+// checks that the Gregorian months in the store form a contiguous sequence.
+// This function is an implementation of DetectGaps in the domain.
+func (s *PeriodService) ValidateGaps() (domain.ValidationReport, error) {
+	if s.store == nil {
+		return domain.ValidationReport{}, fmt.Errorf("period store not initialised")
 	}
 
-	// Call domain function to detect overlaps
-	errStrs := domain.DetectOverlaps(periodList)
-	if len(errStrs) == 0 {
-		return nil
+	return domain.NewValidationReport(s.validator.ValidateGaps(s.store.All())), nil
+}
+
+// ValidateDataQuality
+// runs Period.Validate on every period in the store (non-empty ID and name,
+// known granularity, start before end) and returns one error per invalid period.
+func (s *PeriodService) ValidateDataQuality() (domain.ValidationReport, error) {
+	if s.store == nil {
+		return domain.ValidationReport{}, fmt.Errorf("period store not initialised")
 	}
 
-	// Convert string errors to []error
-	errs := make([]error, len(errStrs))
-	for i, e := range errStrs {
-		errs[i] = fmt.Errorf("%s", e)
+	return domain.NewValidationReport(s.validator.ValidateDataQuality(s.store.All())), nil
+}
+
+// CheckIntegrity
+// finds orphan months, dangling ChildPeriodIDs and external records (e.g. trade breakdowns,
+// see trade.BreakdownReferences) that reference periods missing from the store, and returns
+// a report with a repair suggestion per problem.
+//
+// Example:
+//
+//	report, err := periodService.CheckIntegrity(trade.BreakdownReferences(breakdowns)...)
+//	if err == nil && !report.Healthy() {
+//	    fmt.Println(report)
+//	}
+func (s *PeriodService) CheckIntegrity(refs ...domain.PeriodReference) (domain.RepairReport, error) {
+	if s.store == nil {
+		return domain.RepairReport{}, fmt.Errorf("period store not initialised")
 	}
 
-	return errs
+	return s.validator.CheckIntegrity(s.store.All(), refs), nil
+}
+
+// ReportErrors adapts a validator result to []error, for APIs such as sweep validators.
+// A failure to validate at all is returned as the single error.
+//
+// Example:
+//
+//	sweepDomain.NewValidator("period.overlaps", func(ctx context.Context) []error {
+//	    return service.ReportErrors(periodService.ValidateOverlaps())
+//	})
+func ReportErrors(report domain.ValidationReport, err error) []error {
+	if err != nil {
+		return []error{err}
+	}
+	return report.Errors()
 }
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/period/repository"
+)
+
+func newTestService(t *testing.T) *PeriodService {
+	t.Helper()
+
+	s := NewPeriodService(repository.NewInMemoryPeriodRepository())
+	if err := s.InitializePeriods(context.Background(), 2026, 2026, nil); err != nil {
+		t.Fatalf("InitializePeriods: %v", err)
+	}
+	return s
+}
+
+// TestConcurrentReadsAndUpdates runs readers of store snapshots next to status and tag
+// updates; run with -race to check the copy-on-write contract of the store.
+func TestConcurrentReadsAndUpdates(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	const writers = 8
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				for _, p := range s.store.All() {
+					_ = p.EffectiveStatus()
+					_ = p.AuditInfo.LastActor()
+					_, _ = p.Tag("budget-relevant")
+					if p.AuditInfo != nil && p.AuditInfo.UpdatedAt != nil {
+						_ = p.AuditInfo.UpdatedAt.IsZero()
+					}
+				}
+			}
+		}()
+	}
+
+	var writerWG sync.WaitGroup
+	errs := make(chan error, writers*20)
+	for w := 0; w < writers; w++ {
+		writerWG.Add(1)
+		go func(w int) {
+			defer writerWG.Done()
+			user := fmt.Sprintf("user%d@internal.local", w)
+			for i := 0; i < 10; i++ {
+				// SOFT_CLOSED → OPEN is allowed, so two writers can toggle the same month
+				status := domain.PeriodSoftClosed
+				if i%2 == 1 {
+					status = domain.PeriodOpen
+				}
+				if err := s.SetPeriodStatus(ctx, "2026-MAR", status, user); err != nil {
+					// A concurrent writer may have set the same status first
+					continue
+				}
+			}
+			if err := s.TagPeriod(ctx, "2026-Q1", fmt.Sprintf("tag-%d", w), user); err != nil {
+				errs <- err
+			}
+		}(w)
+	}
+	writerWG.Wait()
+	close(stop)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("TagPeriod: %v", err)
+	}
+
+	q1 := s.store.FindByID("2026-Q1")
+	stored, err := s.repo.GetAllPeriodMetadata(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPeriodMetadata: %v", err)
+	}
+	for w := 0; w < writers; w++ {
+		key := fmt.Sprintf("tag-%d", w)
+		if _, ok := q1.Tag(key); !ok {
+			t.Errorf("store lost tag %s: %v", key, q1.Metadata)
+		}
+		if _, ok := stored["2026-Q1"][key]; !ok {
+			t.Errorf("repository lost tag %s: %v", key, stored["2026-Q1"])
+		}
+	}
+}
+
+func TestUpdateDoesNotModifySnapshot(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	before := s.store.FindByID("2026-JAN")
+	actor := before.AuditInfo.LastActor()
+
+	if err := s.SetPeriodStatus(ctx, "2026-JAN", domain.PeriodSoftClosed, "controller@internal.local"); err != nil {
+		t.Fatalf("SetPeriodStatus: %v", err)
+	}
+
+	if before.EffectiveStatus() != domain.PeriodOpen {
+		t.Errorf("snapshot status changed to %s", before.EffectiveStatus())
+	}
+	if got := before.AuditInfo.LastActor(); got != actor {
+		t.Errorf("snapshot audit info changed: last actor %q, want %q", got, actor)
+	}
+
+	after := s.store.FindByID("2026-JAN")
+	if after.EffectiveStatus() != domain.PeriodSoftClosed || after.AuditInfo.LastActor() != "controller@internal.local" {
+		t.Errorf("store has status %s by %q, want SOFT_CLOSED by controller", after.EffectiveStatus(), after.AuditInfo.LastActor())
+	}
+}
+
+func TestInitializePeriodsKeepsDeletedFiscalYear(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryPeriodRepository()
+	fy := []domain.FiscalCalendarConfig{{StartYear: 2026, StartMonth: time.April}}
+
+	if err := NewPeriodService(repo).InitializePeriods(ctx, 2026, 2027, fy); err != nil {
+		t.Fatalf("InitializePeriods: %v", err)
+	}
+	removed := []string{"FY2026", "FY2026-Q1", "FY2026-Q2", "FY2026-Q3", "FY2026-Q4"}
+	for _, id := range removed {
+		if err := repo.SoftDeletePeriod(ctx, id, "controller@internal.local"); err != nil {
+			t.Fatalf("SoftDeletePeriod %s: %v", id, err)
+		}
+	}
+
+	// A restart must not bring the fiscal year back
+	s := NewPeriodService(repo)
+	if err := s.InitializePeriods(ctx, 2026, 2027, fy); err != nil {
+		t.Fatalf("InitializePeriods after delete: %v", err)
+	}
+
+	for _, id := range removed {
+		if p := s.store.FindByID(id); p != nil {
+			t.Errorf("deleted period %s is back in the store", id)
+		}
+	}
+	deleted, err := repo.GetDeletedPeriods(ctx)
+	if err != nil {
+		t.Fatalf("GetDeletedPeriods: %v", err)
+	}
+	if len(deleted) != len(removed) {
+		t.Errorf("%d deleted periods, want %d", len(deleted), len(removed))
+	}
+	for _, p := range deleted {
+		if p.RowVersion != 1 {
+			t.Errorf("deleted period %s was rewritten (row version %d)", p.ID, p.RowVersion)
+		}
+	}
+}
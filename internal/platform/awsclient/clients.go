@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -13,6 +15,34 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// DBAuthMode selects how NewRDSClient authenticates against Postgres.
+type DBAuthMode string
+
+const (
+	DBAuthIAM      DBAuthMode = "iam"      // RDS IAM auth token (default)
+	DBAuthPassword DBAuthMode = "password" // Plain password, e.g. a docker-compose Postgres
+	DBAuthSecret   DBAuthMode = "secret"   // Username/password from AWS Secrets Manager (DBSecretID)
+)
+
+// Config holds the AWS and database settings.
+//
+// By default the database connection uses RDS IAM authentication. For a local Postgres
+// (docker-compose) set DBAuth to DBAuthPassword, or set DBDSN to use a connection string as-is.
+// With DBAuthSecret the username and password are read from the Secrets Manager secret
+// DBSecretID (the JSON layout RDS uses, see DBCredentials), so rotated passwords are picked up
+// without a restart.
+//
+// Example (docker-compose):
+//
+//	cfg := awsclient.Config{
+//	    DBAuth:     awsclient.DBAuthPassword,
+//	    DBEndpoint: "localhost",
+//	    DBPort:     5432,
+//	    DBUser:     "postgres",
+//	    DBPassword: "postgres",
+//	    DBName:     "postgres",
+//	    DBSSLMode:  "disable",
+//	}
 type Config struct {
 	Profile      string // Primarily for dev purposes
 	S3BucketName string
@@ -22,6 +52,23 @@ type Config struct {
 	DBUser     string // e.g. "masteruser" or some IAM-enabled user
 	DBName     string // e.g. "postgres" or your DB name
 	DBPort     int    // e.g. 5432
+
+	// DBReaderEndpoint is the Aurora reader endpoint (e.g. erikkn-test.cluster-ro-abc123xyz...).
+	// When set, repositories route read-only queries to it; empty sends everything to DBEndpoint.
+	DBReaderEndpoint string
+
+	DBAuth     DBAuthMode // "iam" (default when empty), "password" or "secret"
+	DBPassword string     // Only used with DBAuthPassword
+	DBSecretID string     // Only used with DBAuthSecret; name or ARN of the secret
+	DBSSLMode  string     // libpq sslmode; empty means "require"
+	DBDSN      string     // Full connection string; when set, all other DB settings are ignored
+
+	SecretCacheTTL time.Duration // How long secrets are cached (default 5m)
+
+	// Connection pool; zero values fall back to the DefaultDB* constants
+	DBMaxOpenConns    int           // Maximum open connections (default 10)
+	DBMaxIdleConns    int           // Maximum idle connections kept in the pool (default 5)
+	DBConnMaxLifetime time.Duration // Connections are recycled after this age (default 30m)
 }
 
 type Clients struct {
@@ -38,6 +85,9 @@ type S3Client struct {
 // RDSClient encapsulates the PostgreSQL RDS client (sql.DB) with IAM authentication
 type RDSClient struct {
 	Client *sql.DB // The actual PostgreSQL database client
+
+	unhealthy    atomic.Bool // Result of the last health check (see RunHealthCheck)
+	maxIdleConns int         // Pool idle limit, restored after a failed health check
 }
 
 func (c *Config) LoadAWSConfig() (*aws.Config, error) {
@@ -67,26 +117,137 @@ func NewS3Client(cfg *Config) (*S3Client, error) {
 	}, nil
 }
 
-// NewRDSClient creates and returns a new PostgreSQL RDS client using IAM authentication
+// NewRDSClient creates and returns a new PostgreSQL client. Authentication is selected by
+// DBAuth (IAM token or plain password); DBDSN overrides everything.
+//
+// With IAM auth a new token is built for every new connection (see iamConnector), so the
+// pool keeps working after the 15 minute token lifetime.
 func (c *Config) NewRDSClient() (*RDSClient, error) {
-	// Step 1: Load AWS config (credentials, region, etc.)
-	awsCfg, err := c.LoadAWSConfig()
+	db, err := c.openDB()
+	if err != nil {
+		return nil, err
+	}
+	c.applyPoolSettings(db)
+
+	// Ping the DB to ensure the connection is working
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping RDS PostgreSQL database: %v", err)
+	}
+
+	// Return the established database connection wrapped in RDSClient
+	client := &RDSClient{Client: db}
+	client.maxIdleConns = c.maxIdleConns()
+	return client, nil
+}
+
+// NewRDSReadWriteClients creates a client for the writer endpoint and one for the reader
+// endpoint (DBReaderEndpoint). Without a reader endpoint — or with DBDSN set — both are the
+// same client, so callers can always send reads to the reader.
+//
+// Reads from the reader may lag behind the writer by a few milliseconds (Aurora replica lag).
+// Reads that must see a write made just before, or whose RowVersion is used for a checked
+// update, belong on the writer.
+//
+// Example:
+//
+//	writer, reader, err := cfg.NewRDSReadWriteClients()
+//	...
+//	_, err = writer.Client.ExecContext(ctx, `UPDATE ...`)
+//	rows, err := reader.Client.QueryContext(ctx, `SELECT ...`)
+func (c *Config) NewRDSReadWriteClients() (*RDSClient, *RDSClient, error) {
+	writer, err := c.NewRDSClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.DBReaderEndpoint == "" || c.DBDSN != "" {
+		return writer, writer, nil
+	}
+
+	readerCfg := *c
+	readerCfg.DBEndpoint = c.DBReaderEndpoint
+	readerCfg.DBReaderEndpoint = ""
+
+	reader, err := readerCfg.NewRDSClient()
+	if err != nil {
+		_ = writer.Client.Close()
+		return nil, nil, fmt.Errorf("failed to connect to reader endpoint: %w", err)
+	}
+
+	return writer, reader, nil
+}
+
+// openDB opens the sql.DB for the configured auth mode.
+func (c *Config) openDB() (*sql.DB, error) {
+	var connStr string
+	switch {
+	case c.DBDSN != "":
+		connStr = c.DBDSN
+	case c.DBAuth == "" || c.DBAuth == DBAuthIAM:
+		// Step 1: Load AWS config (credentials, region, etc.)
+		awsCfg, err := c.LoadAWSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config for RDS: %v", err)
+		}
+		// Tokens are built per connection, never baked into a connection string
+		return sql.OpenDB(newIAMConnector(c, awsCfg.Credentials)), nil
+	case c.DBAuth == DBAuthPassword:
+		connStr = c.passwordConnectionString()
+	case c.DBAuth == DBAuthSecret:
+		if c.DBSecretID == "" {
+			return nil, fmt.Errorf("DB auth mode %q requires DBSecretID", c.DBAuth)
+		}
+		secrets, err := NewSecretsClient(c)
+		if err != nil {
+			return nil, err
+		}
+		// Credentials are read per connection (cached), never baked into a connection string
+		return sql.OpenDB(newSecretConnector(c, secrets)), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB auth mode %q", c.DBAuth)
+	}
+
+	// Open the PostgreSQL connection (sql.DB)
+	db, err := sql.Open("postgres", connStr) // Use "postgres" driver for PostgreSQL
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config for RDS: %v", err)
+		return nil, fmt.Errorf("failed to open DB connection: %v", err)
+	}
+	return db, nil
+}
+
+// passwordConnectionString builds the connection string for DBAuthPassword.
+func (c *Config) passwordConnectionString() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		url.QueryEscape(c.DBUser),
+		url.QueryEscape(c.DBPassword),
+		c.DBEndpoint,
+		c.DBPort,
+		url.QueryEscape(c.DBName),
+		c.sslMode(),
+	)
+}
+
+func (c *Config) sslMode() string {
+	if c.DBSSLMode == "" {
+		return "require"
 	}
+	return c.DBSSLMode
+}
 
+// buildIAMConnectionString builds a connection string with a new IAM auth token from credentials.
+func (c *Config) buildIAMConnectionString(ctx context.Context, credentials aws.CredentialsProvider) (string, error) {
 	endpointWithPort := fmt.Sprintf("%s:%d", c.DBEndpoint, c.DBPort)
 
 	// This operation is performed locally, not an API call
 	authToken, err := rdsutils.BuildAuthToken(
-		context.TODO(),
+		ctx,
 		endpointWithPort,
 		c.Region,
 		c.DBUser,
-		awsCfg.Credentials, // Uses the loaded credentials provider from aws.Config
+		credentials, // Uses the loaded credentials provider from aws.Config
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create authentication token: %w", err)
+		return "", fmt.Errorf("failed to create authentication token: %w", err)
 	}
 
 	escapedUser := url.QueryEscape(c.DBUser)
@@ -95,27 +256,14 @@ func (c *Config) NewRDSClient() (*RDSClient, error) {
 
 	// 2. Use the token as the password in a standard database connection string
 	// For PostgreSQL (using pgx driver):
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s/%s?sslmode=require",
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s/%s?sslmode=%s",
 		escapedUser,
 		escapedToken,
 		c.DBEndpoint,
 		escapedDB,
-	)
-
-	// Step 4: Open the PostgreSQL connection (sql.DB)
-	db, err := sql.Open("postgres", connStr) // Use "postgres" driver for PostgreSQL
-	if err != nil {
-		return nil, fmt.Errorf("failed to open DB connection: %v", err)
-	}
-
-	// Step 5: Ping the DB to ensure the connection is working
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping RDS PostgreSQL database: %v", err)
-	}
-
-	// Return the established database connection wrapped in RDSClient
-	return &RDSClient{Client: db}, nil
+		c.sslMode(),
+	), nil
 }
 
 // NewAWSClients creates and returns a new Clients object with RDS and S3 clients
@@ -0,0 +1,54 @@
+package awsclient
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/lib/pq"
+)
+
+// iamConnector is a driver.Connector that builds a fresh RDS IAM auth token for every new
+// physical connection (the "BeforeConnect" hook).
+//
+// PURPOSE:
+//
+//	An IAM auth token is only valid for 15 minutes. Baking one token into the connection
+//	string works until the pool has to open a new connection after the token expired —
+//	typically an hour after boot, when idle connections were recycled — and from then on
+//	every new connection fails with "PAM authentication failed".
+//
+//	Tokens are only checked when a connection is opened, so connections that are already
+//	open keep working after their token expired.
+//
+// EXAMPLE USAGE:
+//
+//	db := sql.OpenDB(newIAMConnector(cfg, awsCfg.Credentials))
+type iamConnector struct {
+	cfg         *Config
+	credentials aws.CredentialsProvider
+}
+
+func newIAMConnector(cfg *Config, credentials aws.CredentialsProvider) *iamConnector {
+	return &iamConnector{cfg: cfg, credentials: credentials}
+}
+
+// Connect builds a new auth token and opens a connection with it.
+func (c *iamConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connStr, err := c.cfg.buildIAMConnectionString(ctx, c.credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := pq.NewConnector(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IAM connection string: %w", err)
+	}
+
+	return connector.Connect(ctx)
+}
+
+func (c *iamConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
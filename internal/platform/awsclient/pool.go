@@ -0,0 +1,110 @@
+package awsclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Connection pool defaults, used when the corresponding Config field is zero.
+const (
+	DefaultDBMaxOpenConns    = 10
+	DefaultDBMaxIdleConns    = 5
+	DefaultDBConnMaxLifetime = 30 * time.Minute
+	DefaultDBHealthInterval  = 30 * time.Second
+	defaultDBPingTimeout     = 5 * time.Second
+)
+
+func (c *Config) maxOpenConns() int {
+	if c.DBMaxOpenConns == 0 {
+		return DefaultDBMaxOpenConns
+	}
+	return c.DBMaxOpenConns
+}
+
+func (c *Config) maxIdleConns() int {
+	if c.DBMaxIdleConns == 0 {
+		return DefaultDBMaxIdleConns
+	}
+	return c.DBMaxIdleConns
+}
+
+func (c *Config) connMaxLifetime() time.Duration {
+	if c.DBConnMaxLifetime == 0 {
+		return DefaultDBConnMaxLifetime
+	}
+	return c.DBConnMaxLifetime
+}
+
+// applyPoolSettings configures the sql.DB connection pool from the Config.
+func (c *Config) applyPoolSettings(db *sql.DB) {
+	db.SetMaxOpenConns(c.maxOpenConns())
+	db.SetMaxIdleConns(c.maxIdleConns())
+	db.SetConnMaxLifetime(c.connMaxLifetime())
+}
+
+// Healthy reports the result of the last health check ping (true before the first check).
+func (r *RDSClient) Healthy() bool {
+	return !r.unhealthy.Load()
+}
+
+// RunHealthCheck
+//
+// PURPOSE:
+//
+//	Pings the database every interval (DefaultDBHealthInterval when zero) until ctx is
+//	cancelled, so a broken database is noticed before the next request hits it.
+//
+//	When a ping fails, the idle connections are dropped: they are most likely dead (failover,
+//	network reset), and sql.DB would otherwise hand them out one by one. The next query then
+//	dials a new connection (with a fresh IAM token). Errors are passed to onError (which may
+//	be nil) and never stop the loop; Healthy reports the latest result.
+//
+// EXAMPLE USAGE:
+//
+//	go rdsClient.RunHealthCheck(ctx, 0, func(err error) {
+//	    log.Println("database health check failed:", err)
+//	})
+func (r *RDSClient) RunHealthCheck(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = DefaultDBHealthInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.checkHealth(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// checkHealth pings the database once and resets the idle pool when the ping fails.
+func (r *RDSClient) checkHealth(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, defaultDBPingTimeout)
+	defer cancel()
+
+	if err := r.Client.PingContext(pingCtx); err != nil {
+		r.unhealthy.Store(true)
+
+		// Dropping to zero closes all idle connections; restore the limit afterwards
+		r.Client.SetMaxIdleConns(0)
+		if r.maxIdleConns > 0 {
+			r.Client.SetMaxIdleConns(r.maxIdleConns)
+		} else {
+			r.Client.SetMaxIdleConns(DefaultDBMaxIdleConns)
+		}
+
+		return fmt.Errorf("failed to ping RDS PostgreSQL database: %w", err)
+	}
+
+	r.unhealthy.Store(false)
+	return nil
+}
@@ -0,0 +1,78 @@
+package awsclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// PutObject uploads body to key in the configured bucket. It returns the S3 version ID of the
+// new object; that is empty when versioning is not enabled on the bucket.
+//
+// Example:
+//
+//	versionID, err := s3Client.PutObject(ctx, "backups/20261016T120000Z/manifest.json", data, "application/json")
+func (c *S3Client) PutObject(ctx context.Context, key string, body []byte, contentType string) (string, error) {
+	out, err := c.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(c.BucketName),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload s3://%s/%s: %w", c.BucketName, key, err)
+	}
+
+	return aws.ToString(out.VersionId), nil
+}
+
+// GetObject downloads key from the configured bucket. An empty versionID reads the latest version.
+func (c *S3Client) GetObject(ctx context.Context, key, versionID string) ([]byte, error) {
+	in := &s3.GetObjectInput{
+		Bucket: aws.String(c.BucketName),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		in.VersionId = aws.String(versionID)
+	}
+
+	out, err := c.Client.GetObject(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download s3://%s/%s: %w", c.BucketName, key, err)
+	}
+	defer out.Body.Close()
+
+	body, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", c.BucketName, key, err)
+	}
+
+	return body, nil
+}
+
+// ListPrefixes returns the "directories" directly below prefix, e.g. with prefix "backups/" it
+// returns "backups/20261016T120000Z/", "backups/20261017T120000Z/", ... in lexical order.
+func (c *S3Client) ListPrefixes(ctx context.Context, prefix string) ([]string, error) {
+	var prefixes []string
+
+	paginator := s3.NewListObjectsV2Paginator(c.Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(c.BucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list s3://%s/%s: %w", c.BucketName, prefix, err)
+		}
+		for _, p := range page.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(p.Prefix))
+		}
+	}
+
+	return prefixes, nil
+}
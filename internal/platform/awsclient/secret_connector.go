@@ -0,0 +1,93 @@
+package awsclient
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lib/pq"
+)
+
+// invalidPassword is the SQLSTATE Postgres returns for rejected credentials.
+const invalidPassword = "28P01"
+
+// secretConnector is a driver.Connector that reads the DB credentials from Secrets Manager
+// (DBSecretID) for every new physical connection.
+//
+// PURPOSE:
+//
+//	When RDS rotates the password, connections that are already open keep working, but new
+//	connections need the new password. The connector reads the credentials through the
+//	SecretsClient cache, and when Postgres rejects them it invalidates the cache and retries
+//	once with the freshly read secret. Rotation therefore never needs a restart.
+//
+// EXAMPLE USAGE:
+//
+//	db := sql.OpenDB(newSecretConnector(cfg, secrets))
+type secretConnector struct {
+	cfg     *Config
+	secrets *SecretsClient
+}
+
+func newSecretConnector(cfg *Config, secrets *SecretsClient) *secretConnector {
+	return &secretConnector{cfg: cfg, secrets: secrets}
+}
+
+// Connect opens a connection with the cached credentials, re-reading them once when they were rotated.
+func (c *secretConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connect(ctx)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == invalidPassword {
+		c.secrets.Invalidate(c.cfg.DBSecretID)
+		return c.connect(ctx)
+	}
+
+	return conn, err
+}
+
+func (c *secretConnector) connect(ctx context.Context) (driver.Conn, error) {
+	var creds DBCredentials
+	if err := c.secrets.GetSecretJSON(ctx, c.cfg.DBSecretID, &creds); err != nil {
+		return nil, err
+	}
+
+	connector, err := pq.NewConnector(c.cfg.secretConnectionString(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string for secret %s: %w", c.cfg.DBSecretID, err)
+	}
+
+	return connector.Connect(ctx)
+}
+
+func (c *secretConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// secretConnectionString builds the connection string for DBAuthSecret. Host, port and database
+// come from the Config; the values in the secret are only used when the Config leaves them empty
+// (the Config must win, or reader clients would connect to the writer host of the secret).
+func (c *Config) secretConnectionString(creds DBCredentials) string {
+	host, port, dbName := c.DBEndpoint, c.DBPort, c.DBName
+	if host == "" {
+		host = creds.Host
+	}
+	if port == 0 {
+		port = creds.Port
+	}
+	if dbName == "" {
+		dbName = creds.DBName
+	}
+
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		url.QueryEscape(creds.Username),
+		url.QueryEscape(creds.Password),
+		host,
+		port,
+		url.QueryEscape(dbName),
+		c.sslMode(),
+	)
+}
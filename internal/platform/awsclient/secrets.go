@@ -0,0 +1,122 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// DefaultSecretCacheTTL is how long a secret is served from cache when SecretCacheTTL is zero.
+const DefaultSecretCacheTTL = 5 * time.Minute
+
+// SecretsClient reads secrets from AWS Secrets Manager and caches them in memory.
+//
+// PURPOSE:
+//
+//	Every new DB connection needs the credentials, and Secrets Manager is billed (and
+//	throttled) per call, so secrets are cached for SecretCacheTTL. Rotation is handled in two
+//	ways: the cache expires, so a rotated secret is picked up within the TTL, and callers that
+//	see the old value being rejected (e.g. "password authentication failed") call Invalidate
+//	to read the new value immediately.
+//
+// EXAMPLE USAGE:
+//
+//	secrets, err := awsclient.NewSecretsClient(&cfg)
+//	var creds awsclient.DBCredentials
+//	err = secrets.GetSecretJSON(ctx, "prod/cso-book/db", &creds)
+type SecretsClient struct {
+	client *secretsmanager.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// DBCredentials is the JSON layout of the secrets RDS creates and rotates for a database user.
+// Host, Port and DBName are optional; they fill in what the Config leaves empty.
+type DBCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	DBName   string `json:"dbname,omitempty"`
+}
+
+// NewSecretsClient creates a Secrets Manager client for the configured region and profile.
+func NewSecretsClient(cfg *Config) (*SecretsClient, error) {
+	awsCfg, err := cfg.LoadAWSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for Secrets Manager client: %v", err)
+	}
+
+	ttl := cfg.SecretCacheTTL
+	if ttl == 0 {
+		ttl = DefaultSecretCacheTTL
+	}
+
+	return &SecretsClient{
+		client: secretsmanager.NewFromConfig(*awsCfg),
+		ttl:    ttl,
+		now:    time.Now,
+		cache:  make(map[string]cachedSecret),
+	}, nil
+}
+
+// GetSecretString returns the current value (AWSCURRENT) of a secret, from cache when it was
+// fetched less than the TTL ago. secretID is the name or ARN of the secret.
+func (s *SecretsClient) GetSecretString(ctx context.Context, secretID string) (string, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[secretID]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.fetchedAt) < s.ttl {
+		return cached.value, nil
+	}
+
+	out, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", secretID, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+
+	s.mu.Lock()
+	s.cache[secretID] = cachedSecret{value: *out.SecretString, fetchedAt: s.now()}
+	s.mu.Unlock()
+
+	return *out.SecretString, nil
+}
+
+// GetSecretJSON decodes a JSON secret into v.
+func (s *SecretsClient) GetSecretJSON(ctx context.Context, secretID string, v any) error {
+	value, err := s.GetSecretString(ctx, secretID)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		// Never include the value: it is a secret
+		return fmt.Errorf("secret %s is not valid JSON for %T", secretID, v)
+	}
+	return nil
+}
+
+// Invalidate drops a secret from the cache, so the next read fetches it from Secrets Manager.
+// Call it when the cached value is rejected, e.g. after a rotation.
+func (s *SecretsClient) Invalidate(secretID string) {
+	s.mu.Lock()
+	delete(s.cache, secretID)
+	s.mu.Unlock()
+}
@@ -0,0 +1,113 @@
+package awsclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// SESClient sends email through the Amazon SES v2 API. It calls the SendEmail REST endpoint
+// directly, signed with SigV4, so no SES SDK module is needed.
+type SESClient struct {
+	creds    aws.CredentialsProvider
+	region   string
+	endpoint string // e.g. https://email.eu-central-1.amazonaws.com
+	signer   *v4.Signer
+	http     *http.Client
+
+	// ConfigurationSet is the SES configuration set every email is sent with, e.g. to track
+	// bounces and complaints; empty means none.
+	ConfigurationSet string
+}
+
+// NewSESClient creates an SES client for the configured region and profile.
+func NewSESClient(cfg *Config) (*SESClient, error) {
+	awsCfg, err := cfg.LoadAWSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for SES client: %v", err)
+	}
+
+	return &SESClient{
+		creds:    awsCfg.Credentials,
+		region:   awsCfg.Region,
+		endpoint: fmt.Sprintf("https://email.%s.amazonaws.com", awsCfg.Region),
+		signer:   v4.NewSigner(),
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// SendEmail sends an HTML email from a verified SES identity and returns the SES message ID.
+//
+// Example:
+//
+//	messageID, err := sesClient.SendEmail(ctx, "confirmations@acme.example", []string{"ops@shell.example"},
+//	    "Purchase confirmation P-2026-0001", html)
+func (c *SESClient) SendEmail(ctx context.Context, from string, to []string, subject, htmlBody string) (string, error) {
+	type content struct {
+		Data    string `json:"Data"`
+		Charset string `json:"Charset"`
+	}
+	in := map[string]any{
+		"FromEmailAddress": from,
+		"Destination":      map[string]any{"ToAddresses": to},
+		"Content": map[string]any{
+			"Simple": map[string]any{
+				"Subject": content{Data: subject, Charset: "UTF-8"},
+				"Body":    map[string]any{"Html": content{Data: htmlBody, Charset: "UTF-8"}},
+			},
+		},
+	}
+	if c.ConfigurationSet != "" {
+		in["ConfigurationSetName"] = c.ConfigurationSet
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	creds, err := c.creds.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve AWS credentials for SES: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "ses", c.region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign SES request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call SES: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read SES response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("SES rejected the email to %v: %s: %s", to, resp.Status, bytes.TrimSpace(data))
+	}
+
+	var out struct {
+		MessageID string `json:"MessageId"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("failed to decode SES response: %w", err)
+	}
+	return out.MessageID, nil
+}
@@ -0,0 +1,17 @@
+package awsclient
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// NewSSMClient creates an SSM Parameter Store client for the configured region and profile.
+func NewSSMClient(cfg *Config) (*ssm.Client, error) {
+	awsCfg, err := cfg.LoadAWSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for SSM client: %v", err)
+	}
+
+	return ssm.NewFromConfig(*awsCfg), nil
+}
@@ -0,0 +1,80 @@
+package cache
+
+import "container/list"
+
+// LRU is a fixed-capacity, least-recently-used cache. When the cache is full, adding a
+// new key evicts the entry that was used least recently.
+//
+// LRU is NOT safe for concurrent use; callers guard it with their own lock.
+//
+// Example:
+//
+//	c := cache.NewLRU[int, string](2)
+//	c.Add(2025, "a")
+//	c.Add(2026, "b")
+//	c.Get(2025)      // 2025 is now most recently used
+//	c.Add(2027, "c") // evicts 2026
+type LRU[K comparable, V any] struct {
+	capacity int
+	order    *list.List // Front = most recently used
+	items    map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRU creates a cache holding at most capacity entries. A capacity below 1 is treated as 1.
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRU[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element, capacity),
+	}
+}
+
+// Get returns the value stored under key and marks it as most recently used.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*entry[K, V]).value, true
+}
+
+// Add stores value under key, replacing any existing value, and evicts the least recently
+// used entry when the cache is over capacity.
+func (c *LRU[K, V]) Add(key K, value V) {
+	if el, ok := c.items[key]; ok {
+		el.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[K, V]).key)
+	}
+}
+
+// Remove deletes key from the cache. Removing an unknown key is a no-op.
+func (c *LRU[K, V]) Remove(key K) {
+	if el, ok := c.items[key]; ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *LRU[K, V]) Len() int {
+	return c.order.Len()
+}
@@ -0,0 +1,35 @@
+package chaos
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInjected is returned by repository calls that were made to fail on purpose.
+// Services under test can branch on it with errors.Is.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config controls the faults injected into decorated repositories.
+//
+// Example (every call takes 200–300ms and 5% of the calls fail):
+//
+//	cfg := chaos.Config{
+//	    Latency:   200 * time.Millisecond,
+//	    Jitter:    100 * time.Millisecond,
+//	    ErrorRate: 0.05,
+//	}
+type Config struct {
+	Latency   time.Duration      // Fixed latency added to every call
+	Jitter    time.Duration      // Additional random latency in [0, Jitter)
+	ErrorRate float64            // Probability in [0, 1] that a call fails with ErrInjected
+	Overrides map[string]float64 // Per-operation error rates (e.g. "GetAllPeriods": 1), taking precedence over ErrorRate
+	Seed      uint64             // Seed for reproducible runs; 0 picks a random seed
+}
+
+// errorRate returns the error rate of an operation.
+func (c Config) errorRate(op string) float64 {
+	if rate, ok := c.Overrides[op]; ok {
+		return rate
+	}
+	return c.ErrorRate
+}
@@ -0,0 +1,67 @@
+//go:build !prod
+
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Enabled reports whether fault injection is compiled in. It is false in builds with the "prod" tag.
+const Enabled = true
+
+// Injector injects latency and failures before repository calls.
+// It is safe for concurrent use.
+type Injector struct {
+	cfg Config
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewInjector creates an Injector for the given configuration.
+func NewInjector(cfg Config) *Injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	return &Injector{
+		cfg: cfg,
+		rng: rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// Before is called at the start of every decorated operation. It sleeps for the configured
+// latency (returning early with ctx.Err() when the context ends first) and then decides
+// whether the operation fails.
+func (i *Injector) Before(ctx context.Context, op string) error {
+	if i == nil {
+		return nil
+	}
+
+	i.mu.Lock()
+	delay := i.cfg.Latency
+	if i.cfg.Jitter > 0 {
+		delay += time.Duration(i.rng.Int64N(int64(i.cfg.Jitter)))
+	}
+	fail := i.rng.Float64() < i.cfg.errorRate(op)
+	i.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if fail {
+		return fmt.Errorf("%s: %w", op, ErrInjected)
+	}
+	return nil
+}
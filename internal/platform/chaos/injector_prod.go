@@ -0,0 +1,24 @@
+//go:build prod
+
+package chaos
+
+import (
+	"context"
+)
+
+// Enabled reports whether fault injection is compiled in. It is false in builds with the "prod" tag.
+const Enabled = false
+
+// Injector is a no-op in production builds: decorated repositories behave exactly like the
+// repositories they wrap.
+type Injector struct{}
+
+// NewInjector ignores the configuration in production builds.
+func NewInjector(cfg Config) *Injector {
+	return &Injector{}
+}
+
+// Before never injects latency or failures in production builds.
+func (i *Injector) Before(ctx context.Context, op string) error {
+	return nil
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+)
+
+// ConfigFileEnv names the YAML configuration file when -config is not given.
+const ConfigFileEnv = "CSO_CONFIG"
+
+// featureEnvPrefix prefixes feature flag environment variables, e.g. CSO_FEATURES_ARCHIVAL=true.
+const featureEnvPrefix = "CSO_FEATURES_"
+
+// override is one key=value set by an environment variable or a flag.
+type override struct {
+	key, value string
+}
+
+// Load resolves the configuration of the application and validates it.
+//
+// PURPOSE:
+//
+//	Sources are merged in increasing precedence; each one only sets what it mentions:
+//
+//	    1. Defaults()
+//	    2. the profile selected by -profile, CSO_PROFILE or "profile:" in the file (default
+//	       prod), see builtinProfiles
+//	    3. the YAML file given by -config or CSO_CONFIG (optional); its profiles.<name> section
+//	       wins over the rest of the file
+//	    4. SSM Parameter Store below /cso-book/<env>/, when ssm is true and db/dsn is not set
+//	       (see SSMLoader)
+//	    5. environment variables: CSO_DB_ENDPOINT, CSO_FEATURES_ARCHIVAL, AWS_REGION, ...
+//	    6. command line flags: -db-endpoint, -feature archival, -region, ...
+//
+//	Every setting has the same key in all sources (see settings), e.g. db/endpoint is
+//	"db: {endpoint: ...}" in YAML. Unknown keys are errors. Log the result with
+//	Settings.String, which redacts secrets.
+//
+// EXAMPLE USAGE:
+//
+//	settings, err := config.Load(ctx, flag.CommandLine, os.Args[1:])
+//	if err != nil {
+//	    log.Fatalf("error loading configuration: %v", err)
+//	}
+//	log.Printf("configuration:\n%s", settings)
+//
+// with config.yaml:
+//
+//	profile: staging
+//	db:
+//	  user: cso_book
+//	  max-open-conns: 20
+//	features:
+//	  archival: true
+//	profiles:
+//	  dev:
+//	    s3:
+//	      bucket: cso-book-dev-jane
+func Load(ctx context.Context, fs *flag.FlagSet, args []string) (*Settings, error) {
+	flagValues := make(map[string]*string, len(settings))
+	for _, st := range settings {
+		flagValues[st.Key] = fs.String(st.flagName(), "", fmt.Sprintf("%s (env %s)", st.Usage, st.envName()))
+	}
+	var features []override
+	fs.Func("feature", "enable a feature flag: name or name=true|false (repeatable)", func(v string) error {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			value = "true"
+		}
+		features = append(features, override{key: featurePrefix + name, value: value})
+		return nil
+	})
+	configFile := fs.String("config", os.Getenv(ConfigFileEnv), "YAML configuration file (env "+ConfigFileEnv+")")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// Environment and flags are collected first: they may select the environment and SSM,
+	// which are applied below them
+	overrides := envOverrides(os.LookupEnv, os.Environ())
+	fs.Visit(func(f *flag.Flag) {
+		for _, st := range settings {
+			if st.flagName() == f.Name {
+				overrides = append(overrides, override{key: st.Key, value: *flagValues[st.Key]})
+			}
+		}
+	})
+	overrides = append(overrides, features...)
+
+	var fileValues map[string]string
+	if *configFile != "" {
+		var err error
+		if fileValues, err = readYAMLFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	profile := DefaultProfile
+	if v, ok := fileValues["profile"]; ok {
+		profile = v
+	}
+	for _, o := range overrides {
+		if o.key == "profile" {
+			profile = o.value
+		}
+	}
+	builtin, fromFile, err := profileValues(profile, fileValues)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Defaults()
+	if err := applyValues(s, builtin); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+	topLevel := make(map[string]string, len(fileValues))
+	for k, v := range fileValues {
+		if !strings.HasPrefix(k, profilePrefix) {
+			topLevel[k] = v
+		}
+	}
+	if err := applyValues(s, topLevel); err != nil {
+		return nil, fmt.Errorf("configuration file %s: %w", *configFile, err)
+	}
+	if err := applyValues(s, fromFile); err != nil {
+		return nil, fmt.Errorf("configuration file %s: profile %s: %w", *configFile, profile, err)
+	}
+	s.Profile = profile
+
+	// What the environment and flags would select, without SSM
+	selected := *s
+	selected.Features = Features{}
+	if err := applyOverrides(&selected, overrides); err != nil {
+		return nil, err
+	}
+
+	if selected.SSM && selected.AWS.DBDSN == "" {
+		bootstrap := selected.AWS
+		ssmClient, err := awsclient.NewSSMClient(&bootstrap)
+		if err != nil {
+			return nil, err
+		}
+		if err := NewSSMLoader(ssmClient, DefaultSSMRoot, selected.Env).Apply(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyOverrides(s, overrides); err != nil {
+		return nil, err
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func applyOverrides(s *Settings, overrides []override) error {
+	for _, o := range overrides {
+		if err := s.set(o.key, o.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envOverrides returns the settings and feature flags set in the environment.
+func envOverrides(lookup func(string) (string, bool), environ []string) []override {
+	var overrides []override
+	for _, st := range settings {
+		// Set but empty counts as unset, like most tools do
+		if v, ok := lookup(st.envName()); ok && v != "" {
+			overrides = append(overrides, override{key: st.Key, value: v})
+		}
+	}
+
+	var features []override
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if flag, ok := strings.CutPrefix(name, featureEnvPrefix); ok && flag != "" {
+			// CSO_FEATURES_LATE_TRADES → late-trades
+			flag = strings.ToLower(strings.ReplaceAll(flag, "_", "-"))
+			features = append(features, override{key: featurePrefix + flag, value: value})
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].key < features[j].key })
+
+	return append(overrides, features...)
+}
+
+// readYAMLFile reads a YAML configuration file as flattened setting keys and values.
+func readYAMLFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenYAML("", doc, values); err != nil {
+		return nil, fmt.Errorf("configuration file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// applyValues sets the given settings in key order, so errors are reported deterministically.
+func applyValues(s *Settings, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := s.set(k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenYAML turns nested mappings into setting keys: {db: {port: 5432}} → "db/port": "5432".
+func flattenYAML(prefix string, node map[string]any, out map[string]string) error {
+	for k, v := range node {
+		key := prefix + k
+		switch v := v.(type) {
+		case map[string]any:
+			if err := flattenYAML(key+"/", v, out); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("setting %s: lists are not supported", key)
+		case nil:
+			// "key:" without a value leaves the setting unchanged
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
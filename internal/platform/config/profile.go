@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Backend selects where repositories store their data.
+type Backend string
+
+const (
+	BackendMemory Backend = "memory" // In-memory repositories; nothing survives a restart
+	BackendSQLite Backend = "sqlite" // SQLite database file (sqlite/path), no AWS needed
+	BackendRDS    Backend = "rds"    // Postgres (RDS/Aurora, or docker-compose via db/dsn)
+)
+
+// DefaultProfile is used when no profile is selected.
+const DefaultProfile = "prod"
+
+// profilePrefix is the YAML key below which a configuration file defines or adjusts profiles,
+// e.g. profiles: {dev: {s3: {bucket: my-bucket}}}.
+const profilePrefix = "profiles/"
+
+// builtinProfiles are the named environments selectable with -profile (or CSO_PROFILE). A
+// profile is a set of setting values applied right above the defaults, so the YAML file, SSM,
+// environment variables and flags still override every value.
+//
+//	dev      in-memory repositories, no AWS at all
+//	local    SQLite database file cso-book.db, no AWS at all; the data survives restarts
+//	staging  RDS, settings from SSM below /cso-book/staging/
+//	prod     RDS, settings from SSM below /cso-book/prod/
+var builtinProfiles = map[string]map[string]string{
+	"dev": {
+		"backend":   string(BackendMemory),
+		"env":       "dev",
+		"ssm":       "false",
+		"s3/bucket": "cso-book-dev",
+	},
+	"local": {
+		"backend":     string(BackendSQLite),
+		"sqlite/path": "cso-book.db",
+		"env":         "local",
+		"ssm":         "false",
+		"s3/bucket":   "cso-book-dev",
+	},
+	"staging": {
+		"backend":     string(BackendRDS),
+		"env":         "staging",
+		"ssm":         "true",
+		"aws/profile": "stagingadmin",
+		"s3/bucket":   "cso-book-staging",
+	},
+	"prod": {
+		"backend":     string(BackendRDS),
+		"env":         "prod",
+		"ssm":         "true",
+		"aws/profile": "productionadmin",
+		"s3/bucket":   "terraform-tfstate-production-nh",
+	},
+}
+
+// profileValues returns the values of a profile: the built-in ones and those of the profiles
+// section of the configuration file, which take precedence over the rest of the file.
+func profileValues(name string, fileValues map[string]string) (builtin, file map[string]string, err error) {
+	builtin, known := builtinProfiles[name]
+
+	file = make(map[string]string)
+	prefix := profilePrefix + name + "/"
+	for k, v := range fileValues {
+		if key, ok := strings.CutPrefix(k, prefix); ok {
+			file[key] = v
+			known = true
+		}
+	}
+
+	if !known {
+		return nil, nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(profileNames(fileValues), ", "))
+	}
+	return builtin, file, nil
+}
+
+// profileNames lists the built-in profiles and those defined in the configuration file.
+func profileNames(fileValues map[string]string) []string {
+	seen := make(map[string]bool)
+	for name := range builtinProfiles {
+		seen[name] = true
+	}
+	for k := range fileValues {
+		if rest, ok := strings.CutPrefix(k, profilePrefix); ok {
+			name, _, _ := strings.Cut(rest, "/")
+			seen[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// DefaultSSMRoot is the Parameter Store path below which every environment has its parameters,
+// e.g. /cso-book/prod/db/endpoint.
+const DefaultSSMRoot = "/cso-book"
+
+// SSMLoader reads the Settings of one environment from SSM Parameter Store.
+//
+// PURPOSE:
+//
+//	Endpoints, buckets and flags differ per environment and must not be compiled into the
+//	binary. Every environment keeps its parameters below <root>/<env>/:
+//
+//	    /cso-book/prod/region               eu-central-1
+//	    /cso-book/prod/s3/bucket            cso-book-prod
+//	    /cso-book/prod/db/endpoint          cso-book.cluster-abc.eu-central-1.rds.amazonaws.com
+//	    /cso-book/prod/db/reader-endpoint   cso-book.cluster-ro-abc.eu-central-1.rds.amazonaws.com
+//	    /cso-book/prod/db/port              5432
+//	    /cso-book/prod/db/name              postgres
+//	    /cso-book/prod/db/user              cso_book
+//	    /cso-book/prod/db/auth              iam
+//	    /cso-book/prod/features/archival    true
+//
+//	Parameter names are the setting keys (see settings). SecureString parameters are
+//	decrypted. An unknown parameter name is an error, so typos do not go unnoticed.
+//
+//	Load uses it between the YAML file and the environment variables; it can also be used
+//	on its own.
+//
+// EXAMPLE USAGE:
+//
+//	s := config.Defaults()
+//	ssmClient, err := awsclient.NewSSMClient(&s.AWS)
+//	err = config.NewSSMLoader(ssmClient, config.DefaultSSMRoot, "prod").Apply(ctx, s)
+//	if s.Features.Enabled("archival") { ... }
+type SSMLoader struct {
+	client ssm.GetParametersByPathAPIClient
+	path   string
+}
+
+func NewSSMLoader(client ssm.GetParametersByPathAPIClient, root, env string) *SSMLoader {
+	return &SSMLoader{
+		client: client,
+		path:   strings.TrimSuffix(root, "/") + "/" + env + "/",
+	}
+}
+
+// Parameters returns all parameters of the environment by their name relative to the
+// environment path, e.g. "db/endpoint".
+func (l *SSMLoader) Parameters(ctx context.Context) (map[string]string, error) {
+	params := make(map[string]string)
+
+	paginator := ssm.NewGetParametersByPathPaginator(l.client, &ssm.GetParametersByPathInput{
+		Path:           aws.String(l.path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SSM parameters below %s: %w", l.path, err)
+		}
+		for _, p := range page.Parameters {
+			params[strings.TrimPrefix(aws.ToString(p.Name), l.path)] = aws.ToString(p.Value)
+		}
+	}
+
+	return params, nil
+}
+
+// Apply reads the parameters of the environment and applies them to s. Parameters that are
+// not set keep their current value.
+func (l *SSMLoader) Apply(ctx context.Context, s *Settings) error {
+	params, err := l.Parameters(ctx)
+	if err != nil {
+		return err
+	}
+
+	// In name order, so errors are stable
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := s.set(name, params[name]); err != nil {
+			return fmt.Errorf("invalid SSM configuration below %s: %w", l.path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package dberr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrNotFound is matched (errors.Is) by every NotFoundError.
+var ErrNotFound = errors.New("not found")
+
+// NotFoundError is returned when an operation targets a record that does not exist (or is not
+// in the state the operation needs, e.g. restoring a period that is not deleted). Lookups such
+// as FindByID keep returning nil, nil instead: absence is a normal answer there.
+//
+// Example:
+//
+//	err := periodService.TagPeriod(ctx, "2031-JAN", "budget-relevant", "true")
+//	if errors.Is(err, dberr.ErrNotFound) {
+//	    http.Error(w, err.Error(), http.StatusNotFound)
+//	}
+type NotFoundError struct {
+	Entity string // e.g. "period", "trade", "company"
+	ID     string
+	Reason string // Optional; replaces "does not exist", e.g. "does not exist or is already deleted"
+}
+
+func (e *NotFoundError) Error() string {
+	reason := e.Reason
+	if reason == "" {
+		reason = "does not exist"
+	}
+	return fmt.Sprintf("%s %s %s", e.Entity, e.ID, reason)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ErrDuplicateID is matched (errors.Is) by every DuplicateError.
+var ErrDuplicateID = errors.New("duplicate id")
+
+// DuplicateError is returned when inserting a record whose ID (or other unique key, such as a
+// company's business key) is already taken. It wraps the driver error, if any.
+//
+// Example:
+//
+//	err := repo.Create(ctx, company)
+//	var dup *dberr.DuplicateError
+//	if errors.As(err, &dup) {
+//	    log.Printf("%s %s already exists", dup.Entity, dup.ID)
+//	}
+type DuplicateError struct {
+	Entity string
+	ID     string // Empty when a batch insert failed and the driver did not say which row
+	Key    string // Optional name of the unique key when it is not the ID, e.g. "business key AB-123"
+	Err    error
+}
+
+func (e *DuplicateError) Error() string {
+	msg := e.Entity
+	if e.ID != "" {
+		msg += " " + e.ID
+	}
+	if e.Key != "" {
+		msg += " (" + e.Key + ")"
+	}
+	msg += " already exists"
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicateID
+}
+
+// uniqueViolation is the Postgres SQLSTATE of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err is (or wraps) a Postgres unique constraint violation.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// ErrPeriodClosed is matched (errors.Is) by every PeriodClosedError.
+var ErrPeriodClosed = errors.New("period closed")
+
+// PeriodClosedError is returned when an operation would change data of a period whose close
+// status forbids it, e.g. re-opening a HARD_CLOSED month.
+//
+// Example:
+//
+//	err := periodService.SetPeriodStatus(ctx, "2026-JAN", domain.PeriodOpen, "controller@internal.local")
+//	if errors.Is(err, dberr.ErrPeriodClosed) {
+//	    // post a correction in the current open month instead
+//	}
+type PeriodClosedError struct {
+	PeriodID string
+	Status   string // e.g. "HARD_CLOSED"
+	Op       string // What was refused, e.g. "move to OPEN"
+}
+
+func (e *PeriodClosedError) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("period %s is %s", e.PeriodID, e.Status)
+	}
+	return fmt.Sprintf("period %s is %s and cannot %s", e.PeriodID, e.Status, e.Op)
+}
+
+func (e *PeriodClosedError) Is(target error) bool {
+	return target == ErrPeriodClosed
+}
+
+// ErrConflict is matched (errors.Is) by every ConflictError, for callers that only need to
+// know that their update lost a race.
+var ErrConflict = errors.New("concurrent modification")
+
+// ConflictError is returned by optimistic-concurrency checked updates when the record was
+// modified by someone else since it was read: the row version in the DB no longer matches
+// the version the caller started from. The caller should reload and retry (or ask the user).
+//
+// Example:
+//
+//	err := repo.UpdatePeriods(ctx, []*domain.Period{p})
+//	var conflict *dberr.ConflictError
+//	if errors.As(err, &conflict) {
+//	    log.Printf("%s %s changed concurrently, reloading", conflict.Entity, conflict.ID)
+//	}
+type ConflictError struct {
+	Entity          string // e.g. "period", "trade", "company"
+	ID              string
+	ExpectedVersion int // Row version the caller read
+	ActualVersion   int // Row version currently stored
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently: expected row version %d, found %d",
+		e.Entity, e.ID, e.ExpectedVersion, e.ActualVersion)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ErrRetryExhausted is matched (errors.Is) by every RetryExhaustedError.
+var ErrRetryExhausted = errors.New("retries exhausted")
+
+// RetryExhaustedError is returned by retried operations (see package retry) when every attempt
+// failed with a transient error. It wraps the last error, so errors.As still finds e.g. the
+// underlying *pq.Error.
+type RetryExhaustedError struct {
+	Op       string // Operation name, e.g. "SavePeriods"
+	Attempts int
+	Err      error // Error of the last attempt
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("%s failed after %d attempts: %v", e.Op, e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RetryExhaustedError) Is(target error) bool {
+	return target == ErrRetryExhausted
+}
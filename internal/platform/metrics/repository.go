@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RepositoryMetrics records latency, outcome and row counts of repository operations.
+//
+// All series are labelled with the repository (e.g. "period") and the operation
+// (e.g. "GetAllPeriods"):
+//
+//	csobook_repository_operation_duration_seconds{repository,operation,outcome}  histogram
+//	csobook_repository_operations_total{repository,operation,outcome}            counter
+//	csobook_repository_rows{repository,operation}                                histogram
+//
+// outcome is "ok" or "error", so the error rate of an operation is
+//
+//	rate(csobook_repository_operations_total{outcome="error"}[5m])
+//	  / rate(csobook_repository_operations_total[5m])
+//
+// Example:
+//
+//	m := metrics.NewRepositoryMetrics(prometheus.DefaultRegisterer)
+//	repo := repository.NewMetricsPeriodRepository(rdsRepo, m)
+type RepositoryMetrics struct {
+	duration   *prometheus.HistogramVec
+	operations *prometheus.CounterVec
+	rows       *prometheus.HistogramVec
+}
+
+// NewRepositoryMetrics creates the repository metrics and registers them with reg.
+// It panics when they are already registered, like prometheus.MustRegister.
+func NewRepositoryMetrics(reg prometheus.Registerer) *RepositoryMetrics {
+	m := &RepositoryMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "csobook",
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of repository operations.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"repository", "operation", "outcome"}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "csobook",
+			Subsystem: "repository",
+			Name:      "operations_total",
+			Help:      "Repository operations by outcome.",
+		}, []string{"repository", "operation", "outcome"}),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "csobook",
+			Subsystem: "repository",
+			Name:      "rows",
+			Help:      "Rows read or written by successful repository operations.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8), // 1 … 16384
+		}, []string{"repository", "operation"}),
+	}
+
+	reg.MustRegister(m.duration, m.operations, m.rows)
+	return m
+}
+
+// Observe records one operation that started at start and read or wrote rows rows.
+// Row counts are only recorded for successful operations. A nil receiver is a no-op, so
+// decorators can be used without metrics.
+func (m *RepositoryMetrics) Observe(repository, operation string, start time.Time, rows int, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	m.duration.WithLabelValues(repository, operation, outcome).Observe(time.Since(start).Seconds())
+	m.operations.WithLabelValues(repository, operation, outcome).Inc()
+	if err == nil {
+		m.rows.WithLabelValues(repository, operation).Observe(float64(rows))
+	}
+}
@@ -0,0 +1,225 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var embedded embed.FS
+
+// advisoryLockKey serialises concurrent migration runs (e.g. several instances starting at once).
+const advisoryLockKey = 0x63736f626f6f6b // "csobook"
+
+// Migration is one versioned schema change, read from an embedded file named
+// "<version>_<name>.sql", e.g. "0001_periods.sql".
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// AppliedMigration is a row of the schema_migrations version table.
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations to a Postgres database and records every applied
+// version in the schema_migrations table, so each migration runs exactly once.
+//
+// Every migration runs in its own transaction together with its version row, so a failing
+// migration leaves no partial schema behind and is retried on the next run.
+//
+// Example (at startup):
+//
+//	rdsClient, err := cfg.NewRDSClient()
+//	...
+//	applied, err := migrate.NewMigrator(rdsClient.Client).Up(ctx)
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator for the migrations embedded in this package.
+func NewMigrator(db *sql.DB) *Migrator {
+	migrations, err := Load(embedded)
+	if err != nil {
+		// The embedded files are part of the binary; a bad file name is a programming error
+		panic(err)
+	}
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Load reads all "sql/<version>_<name>.sql" files from fsys, ordered by version.
+// Versions must be unique.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "sql/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	seen := make(map[int]string, len(files))
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		base := strings.TrimSuffix(path.Base(file), ".sql")
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>.sql", file)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, file, version)
+		}
+		seen[version] = file
+
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(body)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrations returns all known migrations, ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+// Up applies all pending migrations in version order and returns the ones it applied.
+// Running Up on an up-to-date database is a no-op.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, mig := range m.migrations {
+		ok, err := m.apply(ctx, mig)
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			applied = append(applied, mig)
+		}
+	}
+
+	return applied, nil
+}
+
+// Applied returns the rows of the version table, ordered by version.
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, name, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied = append(applied, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if !done[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER     PRIMARY KEY,
+			name       TEXT        NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// apply runs one migration unless it was already applied; it reports whether it ran.
+func (m *Migrator) apply(ctx context.Context, mig Migration) (bool, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Held until commit/rollback, so concurrent runs apply each migration only once
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, advisoryLockKey); err != nil {
+		return false, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version=$1)`, mig.Version,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check migration %04d: %w", mig.Version, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, mig.SQL); err != nil {
+		return false, fmt.Errorf("failed to apply migration %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1,$2,$3)`,
+		mig.Version, mig.Name, time.Now().UTC(),
+	); err != nil {
+		return false, fmt.Errorf("failed to record migration %04d: %w", mig.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration %04d: %w", mig.Version, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,32 @@
+-- Periods (years, quarters, months) of all calendars, one row per definition version.
+-- ChildPeriodIDs are NOT stored: parent_period_id is the source of truth for the hierarchy.
+CREATE TABLE IF NOT EXISTS periods (
+    id               TEXT        NOT NULL,
+    version          INTEGER     NOT NULL DEFAULT 1,
+    name             TEXT        NOT NULL,
+    calendar         TEXT        NOT NULL,
+    granularity      TEXT        NOT NULL,
+    parent_period_id TEXT        NULL,
+    start_date       TIMESTAMPTZ NOT NULL,
+    end_date         TIMESTAMPTZ NOT NULL,
+    effective_from   TIMESTAMPTZ NOT NULL DEFAULT '0001-01-01 00:00:00+00',
+    effective_to     TIMESTAMPTZ NULL,
+    status           TEXT        NOT NULL DEFAULT 'OPEN',
+    audit_created_by TEXT        NOT NULL,
+    audit_created_at TIMESTAMPTZ NOT NULL,
+    audit_updated_by TEXT        NULL,
+    audit_updated_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (id, version),
+    CHECK (end_date >= start_date)
+);
+
+-- At most one current version per period
+CREATE UNIQUE INDEX IF NOT EXISTS periods_current_idx ON periods (id) WHERE effective_to IS NULL;
+CREATE INDEX IF NOT EXISTS periods_start_date_idx ON periods (start_date) WHERE effective_to IS NULL;
+
+CREATE TABLE IF NOT EXISTS period_metadata (
+    period_id TEXT NOT NULL,
+    key       TEXT NOT NULL,
+    value     TEXT NOT NULL,
+    PRIMARY KEY (period_id, key)
+);
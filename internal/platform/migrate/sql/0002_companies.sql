@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS companies (
+    id                TEXT        PRIMARY KEY,
+    business_key      TEXT        NOT NULL UNIQUE,
+    version           TEXT        NOT NULL,
+    name              TEXT        NOT NULL,
+    common_name       TEXT        NOT NULL DEFAULT '',
+    display_name      TEXT        NOT NULL DEFAULT '',
+    coc_number        TEXT        NOT NULL DEFAULT '',
+    city              TEXT        NOT NULL DEFAULT '',
+    address           TEXT        NOT NULL DEFAULT '',
+    contact_person_id TEXT        NOT NULL DEFAULT '',
+    merged_into_id    TEXT        NULL REFERENCES companies (id),
+    audit_created_by  TEXT        NOT NULL,
+    audit_created_at  TIMESTAMPTZ NOT NULL,
+    audit_updated_by  TEXT        NULL,
+    audit_updated_at  TIMESTAMPTZ NULL
+);
+
+CREATE TABLE IF NOT EXISTS company_merges (
+    source_id TEXT        NOT NULL REFERENCES companies (id),
+    target_id TEXT        NOT NULL REFERENCES companies (id),
+    merged_by TEXT        NOT NULL,
+    merged_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (source_id)
+);
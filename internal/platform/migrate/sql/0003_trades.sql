@@ -0,0 +1,69 @@
+-- Purchases and sales share one table; kind tells them apart.
+CREATE TABLE IF NOT EXISTS trades (
+    id               TEXT             PRIMARY KEY,
+    kind             TEXT             NOT NULL CHECK (kind IN ('PURCHASE', 'SALE')),
+    counterparty_id  TEXT             NOT NULL,
+    start_period_id  TEXT             NULL,
+    end_period_id    TEXT             NULL,
+    delivery_start   TIMESTAMPTZ      NULL,
+    delivery_end     TIMESTAMPTZ      NULL,
+    breakdown_mode   TEXT             NOT NULL DEFAULT 'FULL_MONTHS',
+    volume_mt        DOUBLE PRECISION NOT NULL,
+    price_per_mt     DOUBLE PRECISION NOT NULL,
+    currency         TEXT             NOT NULL,
+    status           TEXT             NOT NULL,
+    audit_created_by TEXT             NOT NULL,
+    audit_created_at TIMESTAMPTZ      NOT NULL,
+    audit_updated_by TEXT             NULL,
+    audit_updated_at TIMESTAMPTZ      NULL
+);
+
+CREATE INDEX IF NOT EXISTS trades_counterparty_idx ON trades (counterparty_id);
+
+CREATE TABLE IF NOT EXISTS trade_status_history (
+    trade_id   TEXT        NOT NULL REFERENCES trades (id),
+    old_status TEXT        NOT NULL,
+    new_status TEXT        NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL,
+    changed_by TEXT        NOT NULL,
+    reason     TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS trade_status_history_trade_idx ON trade_status_history (trade_id, changed_at);
+
+-- One row per delivery month of a trade, plus reversal entries (see CancelTrade)
+CREATE TABLE IF NOT EXISTS trade_breakdowns (
+    id                 TEXT             PRIMARY KEY,
+    business_key       TEXT             NOT NULL DEFAULT '',
+    parent_trade_id    TEXT             NOT NULL REFERENCES trades (id),
+    period_id          TEXT             NOT NULL,
+    start_date         TIMESTAMPTZ      NOT NULL,
+    end_date           TIMESTAMPTZ      NOT NULL,
+    month_fraction     DOUBLE PRECISION NOT NULL DEFAULT 1,
+    volume_mt          DOUBLE PRECISION NOT NULL,
+    price_per_mt       DOUBLE PRECISION NOT NULL,
+    currency           TEXT             NOT NULL,
+    total_amount       DOUBLE PRECISION NOT NULL,
+    reversal_of_id     TEXT             NULL REFERENCES trade_breakdowns (id),
+    reversed_period_id TEXT             NULL,
+    audit_created_by   TEXT             NOT NULL,
+    audit_created_at   TIMESTAMPTZ      NOT NULL,
+    audit_updated_by   TEXT             NULL,
+    audit_updated_at   TIMESTAMPTZ      NULL
+);
+
+CREATE INDEX IF NOT EXISTS trade_breakdowns_trade_idx ON trade_breakdowns (parent_trade_id);
+CREATE INDEX IF NOT EXISTS trade_breakdowns_period_idx ON trade_breakdowns (period_id);
+
+CREATE TABLE IF NOT EXISTS trade_rollups (
+    parent_trade_id TEXT             NOT NULL REFERENCES trades (id),
+    period_id       TEXT             NOT NULL,
+    calendar        TEXT             NOT NULL,
+    granularity     TEXT             NOT NULL,
+    volume_mt       DOUBLE PRECISION NOT NULL,
+    total_amount    DOUBLE PRECISION NOT NULL,
+    month_count     INTEGER          NOT NULL,
+    PRIMARY KEY (parent_trade_id, period_id)
+);
+
+CREATE INDEX IF NOT EXISTS trade_rollups_period_idx ON trade_rollups (period_id);
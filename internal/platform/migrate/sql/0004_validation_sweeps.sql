@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS validation_sweeps (
+    id          TEXT        PRIMARY KEY,
+    started_at  TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS validation_sweep_validators (
+    sweep_id      TEXT    NOT NULL REFERENCES validation_sweeps (id),
+    validator     TEXT    NOT NULL,
+    finding_count INTEGER NOT NULL,
+    PRIMARY KEY (sweep_id, validator)
+);
+
+CREATE TABLE IF NOT EXISTS validation_findings (
+    sweep_id  TEXT        NOT NULL REFERENCES validation_sweeps (id),
+    validator TEXT        NOT NULL,
+    message   TEXT        NOT NULL,
+    found_at  TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,6 @@
+-- Soft delete: rows are logically removed (and can be restored) instead of being deleted.
+ALTER TABLE periods ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+ALTER TABLE periods ADD COLUMN IF NOT EXISTS deleted_by TEXT NULL;
+
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS deleted_by TEXT NULL;
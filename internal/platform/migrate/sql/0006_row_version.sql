@@ -0,0 +1,5 @@
+-- Optimistic concurrency control: every checked update bumps row_version and only succeeds
+-- when the caller's version still matches.
+ALTER TABLE periods ADD COLUMN IF NOT EXISTS row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS row_version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,7 @@
+-- Booking policy overrides are compliance audit records kept for the full lifetime of a trade.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS policy_overrides JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS trades_status_idx ON trades (status) WHERE deleted_at IS NULL;
+
+-- Keeps the status history of a trade in insertion order, also for changes within the same instant.
+ALTER TABLE trade_status_history ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
@@ -0,0 +1,25 @@
+-- Append-only audit log of every mutation of periods, trades and companies.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id          BIGSERIAL   PRIMARY KEY,
+    entity_type TEXT        NOT NULL,
+    entity_id   TEXT        NOT NULL,
+    action      TEXT        NOT NULL,
+    actor       TEXT        NOT NULL,
+    at          TIMESTAMPTZ NOT NULL,
+    details     JSONB       NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_entity_idx ON audit_events (entity_type, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_events_at_idx ON audit_events (at);
+
+-- Rows are immutable: reject every UPDATE and DELETE, whoever issues it.
+CREATE OR REPLACE FUNCTION audit_events_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_immutable ON audit_events;
+CREATE TRIGGER audit_events_immutable
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_immutable();
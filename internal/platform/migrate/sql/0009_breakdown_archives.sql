@@ -0,0 +1,17 @@
+-- Index of the months whose breakdowns were moved from trade_breakdowns to S3 (cold archive).
+CREATE TABLE IF NOT EXISTS breakdown_archives (
+    period_id   TEXT        PRIMARY KEY,
+    s3_key      TEXT        NOT NULL,
+    version_id  TEXT        NOT NULL DEFAULT '',
+    row_count   INTEGER     NOT NULL,
+    sha256      TEXT        NOT NULL,
+    trade_ids   TEXT[]      NOT NULL DEFAULT '{}', -- Trades with breakdowns in the month
+    archived_by TEXT        NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS breakdown_archives_trade_ids_idx ON breakdown_archives USING GIN (trade_ids);
+
+-- A reversal entry may outlive the breakdown it reverses in the hot table once that breakdown is
+-- archived, so reversal_of_id becomes a plain reference.
+ALTER TABLE trade_breakdowns DROP CONSTRAINT IF EXISTS trade_breakdowns_reversal_of_id_fkey;
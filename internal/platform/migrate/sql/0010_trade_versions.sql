@@ -0,0 +1,6 @@
+-- Amending a CONFIRMED trade creates a new version; both versions stay in trades and link to
+-- each other so the full chain can be audited.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS previous_version_id TEXT REFERENCES trades (id);
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS superseded_by_id TEXT REFERENCES trades (id);
+
+CREATE INDEX IF NOT EXISTS trades_previous_version_idx ON trades (previous_version_id) WHERE previous_version_id IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Whether volume_mt is delivered per month or is the total of the trade (see trade.AllocationMode).
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS allocation_mode TEXT NOT NULL DEFAULT 'PER_MONTH_FIXED';
@@ -0,0 +1,12 @@
+-- Quantities as booked (e.g. BBL of crude, MWH of LNG). volume_mt keeps the same quantity in
+-- metric tonnes, which is what positions aggregate.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS commodity TEXT NOT NULL DEFAULT '';
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'MT';
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS quantity DOUBLE PRECISION;
+UPDATE trades SET quantity = volume_mt WHERE quantity IS NULL;
+ALTER TABLE trades ALTER COLUMN quantity SET NOT NULL;
+
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'MT';
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS quantity DOUBLE PRECISION;
+UPDATE trade_breakdowns SET quantity = volume_mt WHERE quantity IS NULL;
+ALTER TABLE trade_breakdowns ALTER COLUMN quantity SET NOT NULL;
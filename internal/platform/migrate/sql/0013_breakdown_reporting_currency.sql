@@ -0,0 +1,5 @@
+-- Breakdown amounts converted into the book's reporting currency (see trade.ConvertToReporting).
+-- An empty reporting_currency marks breakdowns booked before conversion existed.
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS reporting_currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS fx_rate DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS reporting_amount DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+-- Daily FX rates: ECB reference rates pulled by fxrates.Service and manual overrides entered by
+-- the back office. For the same pair and day a MANUAL rate wins over the ECB fixing.
+CREATE TABLE IF NOT EXISTS fx_rates (
+    base       TEXT             NOT NULL,
+    quote      TEXT             NOT NULL,
+    rate_date  DATE             NOT NULL,
+    source     TEXT             NOT NULL, -- ECB | MANUAL
+    rate       DOUBLE PRECISION NOT NULL CHECK (rate > 0),
+    updated_by TEXT             NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL,
+    PRIMARY KEY (base, quote, rate_date, source)
+);
+
+CREATE INDEX IF NOT EXISTS fx_rates_rate_date_idx ON fx_rates (rate_date);
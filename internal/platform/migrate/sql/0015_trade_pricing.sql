@@ -0,0 +1,4 @@
+-- Floating prices (see trade.PricingFormula): the formula of a trade, NULL for a fixed price, and
+-- whether the price of a breakdown is still PROVISIONAL or FINAL.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS pricing JSONB;
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS price_status TEXT NOT NULL DEFAULT 'FINAL';
@@ -0,0 +1,12 @@
+-- Forward price curves (see curves.Curve): one row per commodity, assessment day and delivery
+-- month. Saving a curve again for the same day replaces all of its points.
+CREATE TABLE IF NOT EXISTS forward_curves (
+    commodity      TEXT             NOT NULL,
+    as_of          DATE             NOT NULL,
+    delivery_month DATE             NOT NULL CHECK (EXTRACT(DAY FROM delivery_month) = 1),
+    price          DOUBLE PRECISION NOT NULL CHECK (price > 0),
+    currency       TEXT             NOT NULL,
+    created_by     TEXT             NOT NULL,
+    created_at     TIMESTAMPTZ      NOT NULL,
+    PRIMARY KEY (commodity, as_of, delivery_month)
+);
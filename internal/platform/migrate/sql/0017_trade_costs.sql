@@ -0,0 +1,4 @@
+-- Trade costs (see trade.CostComponent): the fee components of a trade, and the share of them
+-- allocated to each breakdown month.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS costs JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS cost_amount DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- Location master data (ports, terminals, storage sites), referenced by trades as their
+-- delivery location instead of a free-text place name.
+CREATE TABLE IF NOT EXISTS locations (
+    id               TEXT        PRIMARY KEY,
+    code             TEXT        NOT NULL UNIQUE,
+    name             TEXT        NOT NULL,
+    type             TEXT        NOT NULL,
+    country          TEXT        NOT NULL,
+    parent_id        TEXT        NULL REFERENCES locations (id),
+    active           BOOLEAN     NOT NULL DEFAULT TRUE,
+    row_version      INTEGER     NOT NULL DEFAULT 1,
+    audit_created_by TEXT        NOT NULL,
+    audit_created_at TIMESTAMPTZ NOT NULL,
+    audit_updated_by TEXT        NULL,
+    audit_updated_at TIMESTAMPTZ NULL
+);
+
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS location_id TEXT NULL REFERENCES locations (id);
+CREATE INDEX IF NOT EXISTS trades_location_id_idx ON trades (location_id);
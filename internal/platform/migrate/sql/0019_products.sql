@@ -0,0 +1,20 @@
+-- Product master data (commodity, grade, quality specs, default unit), referenced by trades so
+-- positions can be reported per product.
+CREATE TABLE IF NOT EXISTS products (
+    id               TEXT        PRIMARY KEY,
+    code             TEXT        NOT NULL UNIQUE,
+    name             TEXT        NOT NULL,
+    commodity        TEXT        NOT NULL,
+    grade            TEXT        NOT NULL DEFAULT '',
+    default_unit     TEXT        NOT NULL DEFAULT 'MT',
+    specs            JSONB       NOT NULL DEFAULT '[]',
+    active           BOOLEAN     NOT NULL DEFAULT TRUE,
+    row_version      INTEGER     NOT NULL DEFAULT 1,
+    audit_created_by TEXT        NOT NULL,
+    audit_created_at TIMESTAMPTZ NOT NULL,
+    audit_updated_by TEXT        NULL,
+    audit_updated_at TIMESTAMPTZ NULL
+);
+
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS product_id TEXT NULL REFERENCES products (id);
+CREATE INDEX IF NOT EXISTS trades_product_id_idx ON trades (product_id);
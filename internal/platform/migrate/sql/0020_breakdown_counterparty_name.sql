@@ -0,0 +1,4 @@
+-- Display name of the counterparty company, copied into every breakdown when the trade is
+-- booked so reports and exports do not have to join the companies table. Breakdowns booked
+-- before the trade service resolved counterparties keep an empty name.
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS counterparty_name TEXT NOT NULL DEFAULT '';
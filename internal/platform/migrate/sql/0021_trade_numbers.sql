@@ -0,0 +1,13 @@
+-- Human-readable trade numbers, e.g. P-2026-0001, quoted to counterparties next to the
+-- internal ULID. All versions of an amended trade share their number, so it is indexed but
+-- not unique; trades booked before numbering keep NULL.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS trade_number TEXT NULL;
+CREATE INDEX IF NOT EXISTS trades_trade_number_idx ON trades (trade_number);
+
+-- One counter per trade kind and year, incremented inside the booking transaction.
+CREATE TABLE IF NOT EXISTS trade_number_sequences (
+    kind        TEXT    NOT NULL,
+    year        INTEGER NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (kind, year)
+);
@@ -0,0 +1,4 @@
+-- Hash of a trade's commercial terms, used to reject deals that are booked twice. Not unique:
+-- intended duplicates can be booked with an override, and superseded versions keep their key.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS business_key TEXT NULL;
+CREATE INDEX IF NOT EXISTS trades_business_key_idx ON trades (business_key);
@@ -0,0 +1,3 @@
+-- Lineage of split trades: both children of a split point at the trade that was divided.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS split_from_id TEXT NULL REFERENCES trades (id);
+CREATE INDEX IF NOT EXISTS trades_split_from_id_idx ON trades (split_from_id);
@@ -0,0 +1,5 @@
+-- Delivery months cancelled on their own (e.g. force majeure) while the rest of the trade
+-- stays live. The trade lists them so rebuilt breakdowns stay cancelled; the breakdowns of
+-- those months are kept with status CANCELLED.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS cancelled_period_ids TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'LIVE';
@@ -0,0 +1,5 @@
+-- Financially settled fixed-vs-index swaps share the trades table with purchases and sales.
+-- They have no breakdowns; swap_side is only set for them.
+ALTER TABLE trades DROP CONSTRAINT IF EXISTS trades_kind_check;
+ALTER TABLE trades ADD CONSTRAINT trades_kind_check CHECK (kind IN ('PURCHASE', 'SALE', 'SWAP'));
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS swap_side TEXT CHECK (swap_side IN ('PAY_FIXED', 'RECEIVE_FIXED'));
@@ -0,0 +1,5 @@
+-- Storage and throughput agreements share the trades table; their breakdowns are monthly fee
+-- accruals. storage_terms (service, fee, injection/withdrawal rights) is only set for them.
+ALTER TABLE trades DROP CONSTRAINT IF EXISTS trades_kind_check;
+ALTER TABLE trades ADD CONSTRAINT trades_kind_check CHECK (kind IN ('PURCHASE', 'SALE', 'SWAP', 'STORAGE'));
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS storage_terms JSONB;
@@ -0,0 +1,4 @@
+-- Availability (capacity) fees are priced apart from the commodity price. Their monthly
+-- accruals are breakdowns of their own, told apart from the deliveries by component.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS availability_fee_per_mt DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS component TEXT NOT NULL DEFAULT 'COMMODITY';
@@ -0,0 +1,3 @@
+-- Take-or-pay / minimum volume clause of a term contract: obligation granularity, minimum
+-- volume, deficiency price and make-up right. Null for trades without one.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS take_or_pay JSONB;
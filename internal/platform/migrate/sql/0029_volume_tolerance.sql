@@ -0,0 +1,4 @@
+-- Volume tolerance (+/- percentages in buyer's or seller's option) and the final volumes per
+-- delivery month declared under it. Null for firm trades.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS tolerance JSONB;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS declared_volumes JSONB;
@@ -0,0 +1,5 @@
+-- Delivery windows (laycans) per delivery month, for scheduling and demurrage. The trade keeps
+-- them per month so they survive rebuilt breakdowns; each breakdown carries its own window.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS laycans JSONB;
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS laycan_start TIMESTAMPTZ;
+ALTER TABLE trade_breakdowns ADD COLUMN IF NOT EXISTS laycan_end TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- Indexes for the trade blotter, which lists live trades by creation time and by trader.
+CREATE INDEX IF NOT EXISTS trades_created_at_idx ON trades (audit_created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS trades_created_by_idx ON trades (audit_created_by, audit_created_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,12 @@
+-- Full-text search over companies, trades and the comments (reasons) of their status changes.
+-- The 'simple' configuration neither stems nor drops stop words, so names and deal numbers
+-- match as typed.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', name || ' ' || common_name || ' ' || display_name || ' ' || coc_number || ' ' || city)) STORED;
+CREATE INDEX IF NOT EXISTS companies_search_idx ON companies USING GIN (search_vector);
+
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(trade_number, '') || ' ' || commodity)) STORED;
+CREATE INDEX IF NOT EXISTS trades_search_idx ON trades USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS trade_status_history_search_idx ON trade_status_history USING GIN (to_tsvector('simple', reason));
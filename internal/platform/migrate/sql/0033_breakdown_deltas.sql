@@ -0,0 +1,20 @@
+-- What each amendment changed per delivery month (see trade.DiffBreakdowns), so finance can
+-- see exactly what moved in each period. Rows are written with the amendment and never updated.
+CREATE TABLE IF NOT EXISTS trade_breakdown_deltas (
+    trade_id          TEXT             NOT NULL REFERENCES trades (id),
+    previous_trade_id TEXT             NULL REFERENCES trades (id),
+    trade_number      TEXT             NULL,
+    period_id         TEXT             NOT NULL,
+    old_volume_mt     DOUBLE PRECISION NOT NULL,
+    new_volume_mt     DOUBLE PRECISION NOT NULL,
+    old_amount        DOUBLE PRECISION NOT NULL,
+    new_amount        DOUBLE PRECISION NOT NULL,
+    old_currency      TEXT             NOT NULL DEFAULT '',
+    new_currency      TEXT             NOT NULL DEFAULT '',
+    reason            TEXT             NOT NULL DEFAULT '',
+    amended_by        TEXT             NOT NULL,
+    amended_at        TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS trade_breakdown_deltas_trade_idx ON trade_breakdown_deltas (trade_number, amended_at);
+CREATE INDEX IF NOT EXISTS trade_breakdown_deltas_period_idx ON trade_breakdown_deltas (period_id, amended_at);
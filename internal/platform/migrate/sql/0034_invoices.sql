@@ -0,0 +1,54 @@
+-- Invoices of delivered breakdowns, one per counterparty, delivery month, direction and
+-- currency (see invoice.GroupDeliveries). company_id is re-pointed by company merges.
+CREATE TABLE IF NOT EXISTS invoices (
+    id               TEXT PRIMARY KEY,
+    invoice_number   TEXT             NOT NULL UNIQUE,
+    company_id       TEXT             NOT NULL REFERENCES companies (id),
+    company_name     TEXT             NOT NULL DEFAULT '',
+    period_id        TEXT             NOT NULL,
+    direction        TEXT             NOT NULL,
+    currency         TEXT             NOT NULL,
+    net_amount       DOUBLE PRECISION NOT NULL,
+    tax_rate_pct     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    tax_amount       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    gross_amount     DOUBLE PRECISION NOT NULL,
+    status           TEXT             NOT NULL DEFAULT 'DRAFT',
+    issue_date       DATE             NOT NULL,
+    sent_at          TIMESTAMPTZ      NULL,
+    paid_at          TIMESTAMPTZ      NULL,
+    row_version      INTEGER          NOT NULL DEFAULT 1,
+    audit_created_by TEXT             NOT NULL,
+    audit_created_at TIMESTAMPTZ      NOT NULL,
+    audit_updated_by TEXT             NULL,
+    audit_updated_at TIMESTAMPTZ      NULL
+);
+
+CREATE INDEX IF NOT EXISTS invoices_company_idx ON invoices (company_id, period_id);
+CREATE INDEX IF NOT EXISTS invoices_status_idx ON invoices (status, issue_date);
+
+-- One line per invoiced breakdown. line_key (trade number, month, component) is unique, so a
+-- delivery month is invoiced once even when the trade is amended afterwards.
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    invoice_id   TEXT             NOT NULL REFERENCES invoices (id),
+    line_no      INTEGER          NOT NULL,
+    line_key     TEXT             NOT NULL UNIQUE,
+    breakdown_id TEXT             NOT NULL,
+    trade_id     TEXT             NOT NULL REFERENCES trades (id),
+    trade_number TEXT             NULL,
+    period_id    TEXT             NOT NULL,
+    component    TEXT             NOT NULL DEFAULT 'COMMODITY',
+    description  TEXT             NOT NULL DEFAULT '',
+    volume_mt    DOUBLE PRECISION NOT NULL,
+    price_per_mt DOUBLE PRECISION NOT NULL,
+    amount       DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (invoice_id, line_no)
+);
+
+-- One counter per number prefix (INV, BILL) and year, incremented inside the invoicing
+-- transaction.
+CREATE TABLE IF NOT EXISTS invoice_number_sequences (
+    prefix      TEXT    NOT NULL,
+    year        INTEGER NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (prefix, year)
+);
@@ -0,0 +1,3 @@
+-- Payment terms of a trade, e.g. "30 days after B/L" (see trade.PaymentTerms). Null means the
+-- default terms.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS payment_terms JSONB;
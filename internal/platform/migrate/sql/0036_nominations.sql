@@ -0,0 +1,26 @@
+-- Monthly volume nominations, one row per contract month (see nomination.Nominate). trade_ref is
+-- the trade number, so a nomination survives amendments that give the trade a new ID.
+CREATE TABLE IF NOT EXISTS nominations (
+    id                 TEXT PRIMARY KEY,
+    trade_ref          TEXT             NOT NULL,
+    trade_id           TEXT             NOT NULL REFERENCES trades (id),
+    period_id          TEXT             NOT NULL,
+    volume_mt          DOUBLE PRECISION NOT NULL,
+    contract_volume_mt DOUBLE PRECISION NOT NULL,
+    min_volume_mt      DOUBLE PRECISION NOT NULL,
+    max_volume_mt      DOUBLE PRECISION NOT NULL,
+    deadline           TIMESTAMPTZ      NOT NULL,
+    status             TEXT             NOT NULL DEFAULT 'NOMINATED',
+    revision           INTEGER          NOT NULL DEFAULT 1,
+    nominated_by       TEXT             NOT NULL,
+    nominated_at       TIMESTAMPTZ      NOT NULL,
+    actualized_at      TIMESTAMPTZ      NULL,
+    row_version        INTEGER          NOT NULL DEFAULT 1,
+    audit_created_by   TEXT             NOT NULL,
+    audit_created_at   TIMESTAMPTZ      NOT NULL,
+    audit_updated_by   TEXT             NULL,
+    audit_updated_at   TIMESTAMPTZ      NULL,
+    UNIQUE (trade_ref, period_id)
+);
+
+CREATE INDEX IF NOT EXISTS nominations_period_idx ON nominations (period_id, status);
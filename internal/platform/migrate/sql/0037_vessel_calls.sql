@@ -0,0 +1,3 @@
+-- Vessel and laytime data per delivery month (see trade.VesselCall); the demurrage it produces
+-- is allocated to the costs of the month.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS vessel_calls JSONB;
@@ -0,0 +1,14 @@
+-- Back-to-back links between purchases and sales (see trade.TradeLink). Trades are referred to by
+-- trade number, so links survive amendments; volume_mt is linked in every month of period_ids.
+CREATE TABLE IF NOT EXISTS trade_links (
+    id               TEXT PRIMARY KEY,
+    purchase_ref     TEXT             NOT NULL,
+    sale_ref         TEXT             NOT NULL,
+    volume_mt        DOUBLE PRECISION NOT NULL,
+    period_ids       TEXT[]           NOT NULL,
+    audit_created_by TEXT             NOT NULL,
+    audit_created_at TIMESTAMPTZ      NOT NULL,
+    UNIQUE (purchase_ref, sale_ref)
+);
+
+CREATE INDEX IF NOT EXISTS trade_links_sale_idx ON trade_links (sale_ref);
@@ -0,0 +1,3 @@
+-- Status history queries across trades (see RdsTradeRepository.ListStatusChanges), e.g. every
+-- cancellation of a month, filter on the new status and the time of the change.
+CREATE INDEX IF NOT EXISTS trade_status_history_status_idx ON trade_status_history (new_status, changed_at);
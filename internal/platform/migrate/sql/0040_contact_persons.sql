@@ -0,0 +1,19 @@
+-- Contact persons of companies (see company.ContactPerson). companies.contact_person_id points at
+-- the primary contact; confirmations go to the CONFIRMATIONS contacts.
+CREATE TABLE IF NOT EXISTS contact_persons (
+    id               TEXT PRIMARY KEY,
+    company_id       TEXT        NOT NULL REFERENCES companies (id),
+    name             TEXT        NOT NULL,
+    email            TEXT        NOT NULL,
+    phone            TEXT        NOT NULL DEFAULT '',
+    role             TEXT        NOT NULL,
+    row_version      INTEGER     NOT NULL DEFAULT 1,
+    audit_created_by TEXT        NOT NULL,
+    audit_created_at TIMESTAMPTZ NOT NULL,
+    audit_updated_by TEXT        NULL,
+    audit_updated_at TIMESTAMPTZ NULL
+);
+
+-- Not unique per company: a merge moves the contacts of both companies onto the survivor, and
+-- may bring the same person twice.
+CREATE INDEX IF NOT EXISTS contact_persons_company_idx ON contact_persons (company_id, role);
@@ -0,0 +1,16 @@
+-- Credit limits and internal ratings, one per company (see company.CreditLimit). A limit is
+-- suspended once review_date has passed. company_id is re-pointed by company merges.
+CREATE TABLE IF NOT EXISTS credit_limits (
+    company_id       TEXT PRIMARY KEY REFERENCES companies (id),
+    amount           DOUBLE PRECISION NOT NULL,
+    currency         TEXT             NOT NULL,
+    rating           TEXT             NOT NULL,
+    review_date      DATE             NOT NULL,
+    row_version      INTEGER          NOT NULL DEFAULT 1,
+    audit_created_by TEXT             NOT NULL,
+    audit_created_at TIMESTAMPTZ      NOT NULL,
+    audit_updated_by TEXT             NULL,
+    audit_updated_at TIMESTAMPTZ      NULL
+);
+
+CREATE INDEX IF NOT EXISTS credit_limits_review_idx ON credit_limits (review_date);
@@ -0,0 +1,11 @@
+-- KYC and sanctions screening of companies (see company.CheckCompliance). Existing companies start
+-- PENDING: confirmations against them are blocked once the compliance check is enabled, until
+-- their KYC is approved.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS kyc_status            TEXT        NOT NULL DEFAULT 'PENDING';
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS kyc_approved_at       TIMESTAMPTZ NULL;
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS kyc_expires_at        TIMESTAMPTZ NULL;
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS sanctions_hit         BOOLEAN     NOT NULL DEFAULT FALSE;
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS sanctions_screened_at TIMESTAMPTZ NULL;
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS sanctions_note        TEXT        NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS companies_kyc_idx ON companies (kyc_status, kyc_expires_at);
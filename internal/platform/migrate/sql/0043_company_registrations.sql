@@ -0,0 +1,8 @@
+-- LEI and VAT number of companies (see company.Identifiers). Company deduplication looks companies
+-- up by every identifier, whichever one their business key was made from.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS lei        TEXT NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS vat_number TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS companies_lei_idx ON companies (lei) WHERE lei <> '';
+CREATE INDEX IF NOT EXISTS companies_vat_number_idx ON companies (vat_number) WHERE vat_number <> '';
+CREATE INDEX IF NOT EXISTS companies_coc_number_idx ON companies (coc_number) WHERE coc_number <> '';
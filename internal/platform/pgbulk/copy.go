@@ -0,0 +1,50 @@
+package pgbulk
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// CopyIn bulk-inserts rows into table with Postgres COPY FROM STDIN inside tx. It is an
+// order of magnitude faster than row-by-row INSERTs through a prepared statement, which
+// matters for large sets such as decades of periods or the breakdowns of a long-dated trade.
+//
+// Every row must hold one value per column, in column order. COPY does not support
+// ON CONFLICT: a duplicate key fails the whole statement (and thus the transaction).
+//
+// Example:
+//
+//	err := pgbulk.CopyIn(ctx, tx, "period_metadata", []string{"period_id", "key", "value"}, [][]any{
+//	    {"2026-JAN", "budget-relevant", "true"},
+//	    {"2026-FEB", "budget-relevant", "true"},
+//	})
+func CopyIn(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return fmt.Errorf("failed to prepare COPY into %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("COPY into %s: row %d has %d values, expected %d", table, i, len(row), len(columns))
+		}
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return fmt.Errorf("failed to buffer row %d for COPY into %s: %w", i, table, err)
+		}
+	}
+
+	// The final Exec without arguments flushes the buffered rows to the server
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to COPY %d rows into %s: %w", len(rows), table, err)
+	}
+
+	return nil
+}
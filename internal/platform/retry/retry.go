@@ -0,0 +1,151 @@
+package retry
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+)
+
+// Policy controls how often and how fast an operation is retried.
+//
+// The delay before retry n (1-based) is BaseDelay·2^(n-1), capped at MaxDelay, of which a
+// random half is jitter, so instances that failed together do not retry in lockstep.
+//
+// Example (up to 5 attempts, waiting ~100ms, ~200ms, ~400ms, ~800ms in between):
+//
+//	p := retry.Policy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+type Policy struct {
+	MaxAttempts int              // Total attempts including the first; <= 1 disables retries
+	BaseDelay   time.Duration    // Delay before the first retry (before jitter)
+	MaxDelay    time.Duration    // Upper bound of a single delay; 0 means no bound
+	Retryable   func(error) bool // Classifies errors; nil means IsTransient
+	sleep       func(time.Duration) <-chan time.Time
+}
+
+// DefaultPolicy suits interactive repository calls: 4 attempts within roughly one second.
+var DefaultPolicy = Policy{
+	MaxAttempts: 4,
+	BaseDelay:   50 * time.Millisecond,
+	MaxDelay:    time.Second,
+}
+
+// Do runs fn until it succeeds, fails with a non-retryable error, or the attempts are used up.
+//
+//   - non-retryable errors (e.g. a *dberr.ConflictError or a validation error) are returned as-is
+//   - when all attempts failed with retryable errors, a *dberr.RetryExhaustedError wrapping the
+//     last error is returned
+//   - when ctx ends while waiting, ctx.Err() is returned
+//
+// fn must be safe to run again after a failure, e.g. a single DB transaction that was rolled back.
+// Inside a txn.Manager unit of work fn runs once: a failed statement aborts the ambient
+// transaction, so only the unit of work as a whole can be retried.
+//
+// Example:
+//
+//	err := retry.Do(ctx, retry.DefaultPolicy, "SavePeriods", func(ctx context.Context) error {
+//	    return repo.SavePeriods(ctx, periods)
+//	})
+//	if errors.Is(err, dberr.ErrRetryExhausted) { ... }
+func Do(ctx context.Context, p Policy, op string, fn func(ctx context.Context) error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsTransient
+	}
+	attempts := max(p.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || !retryable(err) || txn.InTx(ctx) {
+			return err
+		}
+		if attempt == attempts {
+			return &dberr.RetryExhaustedError{Op: op, Attempts: attempts, Err: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.Delay(attempt)):
+		}
+	}
+}
+
+// DoValue is Do for operations that return a value, e.g. repository reads.
+//
+// Example:
+//
+//	periods, err := retry.DoValue(ctx, retry.DefaultPolicy, "GetAllPeriods", repo.GetAllPeriods)
+func DoValue[T any](ctx context.Context, p Policy, op string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := Do(ctx, p, op, func(ctx context.Context) error {
+		var err error
+		value, err = fn(ctx)
+		return err
+	})
+	return value, err
+}
+
+// Delay returns the jittered delay before retry n (1-based).
+func (p Policy) Delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Postgres SQLSTATE codes (and classes) that are worth retrying.
+var transientCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections (throttling by the server / RDS proxy)
+	"55P03": true, // lock_not_available
+	"57P01": true, // admin_shutdown (e.g. failover)
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// IsTransient reports whether err is a temporary database failure: serialization failures and
+// deadlocks, connection resets and failovers, and server-side throttling. Context cancellation
+// and deadlines are never transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08: connection exception
+		return transientCodes[pqErr.Code] || strings.HasPrefix(string(pqErr.Code), "08")
+	}
+
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Job is a unit of work executed by the scheduler.
+type Job func(ctx context.Context) error
+
+// NextDailyRun returns the next occurrence of hour:minute in loc strictly after now.
+//
+// Example:
+//
+//	now := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+//	NextDailyRun(now, 2, 0, time.UTC) // → 2026-03-02 02:00:00 UTC
+func NextDailyRun(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunDaily executes job every day at hour:minute in loc until ctx is cancelled.
+// Errors returned by the job are passed to onError (which may be nil) and never stop
+// the schedule: a failing night must not prevent the next one from running.
+//
+// RunDaily blocks, so it is normally started in its own goroutine:
+//
+//	go scheduler.RunDaily(ctx, 2, 0, time.UTC, job, func(err error) {
+//	    log.Println("nightly job failed:", err)
+//	})
+func RunDaily(ctx context.Context, hour, minute int, loc *time.Location, job Job, onError func(error)) {
+	for {
+		wait := time.Until(NextDailyRun(time.Now(), hour, minute, loc))
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := job(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
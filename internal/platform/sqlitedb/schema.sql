@@ -0,0 +1,58 @@
+-- Schema of the sqlite backend: the tables of the Postgres migrations (internal/platform/migrate)
+-- that the SQLite repositories use, in their current shape. Applied on every Open.
+
+-- Periods (years, quarters, months) of all calendars, one row per definition version.
+-- ChildPeriodIDs are NOT stored: parent_period_id is the source of truth for the hierarchy.
+CREATE TABLE IF NOT EXISTS periods (
+    id               TEXT      NOT NULL,
+    version          INTEGER   NOT NULL DEFAULT 1,
+    name             TEXT      NOT NULL,
+    calendar         TEXT      NOT NULL,
+    granularity      TEXT      NOT NULL,
+    parent_period_id TEXT      NULL,
+    start_date       TIMESTAMP NOT NULL,
+    end_date         TIMESTAMP NOT NULL,
+    effective_from   TIMESTAMP NOT NULL,
+    effective_to     TIMESTAMP NULL,
+    status           TEXT      NOT NULL DEFAULT 'OPEN',
+    deleted_at       TIMESTAMP NULL,
+    deleted_by       TEXT      NULL,
+    row_version      INTEGER   NOT NULL DEFAULT 1,
+    audit_created_by TEXT      NOT NULL,
+    audit_created_at TIMESTAMP NOT NULL,
+    audit_updated_by TEXT      NULL,
+    audit_updated_at TIMESTAMP NULL,
+    PRIMARY KEY (id, version),
+    CHECK (end_date >= start_date)
+);
+
+-- At most one current version per period
+CREATE UNIQUE INDEX IF NOT EXISTS periods_current_idx ON periods (id) WHERE effective_to IS NULL;
+CREATE INDEX IF NOT EXISTS periods_start_date_idx ON periods (start_date) WHERE effective_to IS NULL;
+
+CREATE TABLE IF NOT EXISTS period_metadata (
+    period_id TEXT NOT NULL,
+    key       TEXT NOT NULL,
+    value     TEXT NOT NULL,
+    PRIMARY KEY (period_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS validation_sweeps (
+    id          TEXT      PRIMARY KEY,
+    started_at  TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS validation_sweep_validators (
+    sweep_id      TEXT    NOT NULL REFERENCES validation_sweeps (id),
+    validator     TEXT    NOT NULL,
+    finding_count INTEGER NOT NULL,
+    PRIMARY KEY (sweep_id, validator)
+);
+
+CREATE TABLE IF NOT EXISTS validation_findings (
+    sweep_id  TEXT      NOT NULL REFERENCES validation_sweeps (id),
+    validator TEXT      NOT NULL,
+    message   TEXT      NOT NULL,
+    found_at  TIMESTAMP NOT NULL
+);
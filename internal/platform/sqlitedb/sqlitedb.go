@@ -0,0 +1,61 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/mattn/go-sqlite3" // Also registers the "sqlite3" database/sql driver
+)
+
+//go:embed schema.sql
+var schema string
+
+// Open opens (or creates) the SQLite database file at path and brings its schema up to date.
+// The sqlite backend is meant for local development and single-instance deployments: the data
+// survives restarts without a Postgres server.
+//
+// Write transactions take the database lock when they begin (_txlock=immediate), so two
+// writers never deadlock on a lock upgrade; a writer waits up to 5 seconds for the lock.
+// ":memory:" opens a private in-memory database, e.g. for tests.
+//
+// Example:
+//
+//	db, err := sqlitedb.Open(ctx, "cso-book.db")
+//	periodRepo := repository.NewSqlitePeriodRepository(db)
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	params := url.Values{
+		"_foreign_keys": {"on"},
+		"_busy_timeout": {"5000"},
+		"_txlock":       {"immediate"},
+	}
+	if path != ":memory:" {
+		params.Set("_journal_mode", "WAL")
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+	if path == ":memory:" {
+		// Every connection would get its own empty in-memory database
+		db.SetMaxOpenConns(1)
+	}
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the schema of SQLite database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// IsUniqueViolation reports whether err is (or wraps) a SQLite primary key or unique constraint
+// violation, the counterpart of dberr.IsUniqueViolation.
+func IsUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique)
+}
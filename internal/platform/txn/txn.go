@@ -0,0 +1,119 @@
+package txn
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ctxKey is the context key of the ambient transaction.
+type ctxKey struct{}
+
+// Manager runs units of work that span several repositories in ONE database transaction.
+//
+// PURPOSE:
+//
+//	Every RDS repository method opens its own transaction through Begin. Inside
+//	Manager.WithinTx, Begin instead joins the ambient transaction carried by the context, so
+//	saving a trade, its breakdowns, its rollups and its audit events either commits as a
+//	whole or not at all.
+//
+// EXAMPLE USAGE:
+//
+//	tm := txn.NewManager(rdsClient.Client)
+//	err := tm.WithinTx(ctx, func(ctx context.Context) error {
+//	    if err := tradeRepo.SaveTrade(ctx, &p); err != nil {
+//	        return err // Rolls back everything
+//	    }
+//	    return breakdownRepo.SaveBreakdowns(ctx, p.ID, breakdowns, rollups)
+//	})
+type Manager struct {
+	db *sql.DB
+}
+
+func NewManager(db *sql.DB) *Manager {
+	return &Manager{db: db}
+}
+
+// WithinTx runs fn in a transaction and commits it when fn returns nil. When fn returns an
+// error or panics, the transaction is rolled back (a panic is re-raised afterwards).
+//
+// Calls nest: a WithinTx inside fn joins the outer transaction instead of starting a new one.
+// Repository calls made with the ctx passed to fn run in the transaction; calls made with
+// another context do not.
+func (m *Manager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := Begin(ctx, m.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, ctxKey{}, tx.Tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Tx is a transaction started (or joined) by Begin. Commit and Rollback only act on
+// transactions Begin started itself; on a joined transaction they are no-ops, so the
+// unit of work that owns it decides the outcome.
+type Tx struct {
+	*sql.Tx
+	owned bool
+}
+
+// Begin starts a transaction on db, or joins the ambient transaction of a surrounding
+// Manager.WithinTx. Repositories use it instead of db.BeginTx, keeping the usual pattern:
+//
+//	tx, err := txn.Begin(ctx, r.db)
+//	if err != nil {
+//	    return err
+//	}
+//	defer func() {
+//	    _ = tx.Rollback()
+//	}()
+//	...
+//	return tx.Commit()
+func Begin(ctx context.Context, db *sql.DB) (*Tx, error) {
+	if tx, ok := ctx.Value(ctxKey{}).(*sql.Tx); ok {
+		return &Tx{Tx: tx}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &Tx{Tx: tx, owned: true}, nil
+}
+
+// Commit commits an owned transaction.
+func (t *Tx) Commit() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls back an owned transaction.
+func (t *Tx) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
+
+// InTx reports whether ctx carries the ambient transaction of a Manager.WithinTx.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxKey{}).(*sql.Tx)
+	return ok
+}
@@ -0,0 +1,271 @@
+package pnl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/curves"
+	"github.com/nholding/cso-book/internal/fx"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// volumeTolerance is the open volume (MT) below which a month counts as flat (float rounding).
+const volumeTolerance = 1e-6
+
+// Line is the PnL of one period: a month, or a quarter or year (CAL or FY) summed from its months.
+type Line struct {
+	PeriodID    string                   `json:"periodId"`
+	Calendar    period.CalendarType      `json:"calendar"`
+	Granularity period.PeriodGranularity `json:"granularity"`
+	Realized    float64                  `json:"realized"`   // Delivered and matched volume: sales minus purchases
+	Unrealized  float64                  `json:"unrealized"` // Future months and open volume: marked against the forward curve
+	Total       float64                  `json:"total"`
+	Costs       float64                  `json:"costs"` // Trade costs included in Total
+	PurchasedMT float64                  `json:"purchasedMT"`
+	SoldMT      float64                  `json:"soldMT"`
+}
+
+// Report is the PnL of the book as of a day, in one currency.
+type Report struct {
+	AsOf     time.Time `json:"asOf"`
+	Currency string    `json:"currency"`
+	Months   []Line    `json:"months"`   // By month start
+	Quarters []Line    `json:"quarters"` // By quarter start; CAL and FY
+	Years    []Line    `json:"years"`    // By year start; CAL and FY
+	Total    Line      `json:"total"`
+}
+
+// Calculator computes the PnL of the book from its purchases, sales and forward curves.
+//
+// PURPOSE:
+//
+//	The PnL of a month is what its sales bring in minus what its purchases cost, with the
+//	open (net) volume valued at the forward price of the trade's commodity for the month:
+//
+//	    total = Σ sales amount − Σ purchases amount + net MT × forward
+//
+//	A long position is thereby worth what it could be sold for today, a short one costs what it
+//	would take to cover it. Months that ended before the valuation day are delivered; for them
+//	the matched volume (the smaller of purchased and sold) is realized at the average sale minus
+//	the average purchase price, and the rest stays unrealized. Future months are unrealized
+//	in full:
+//
+//	    2026-JAN (delivered)  bought 1000 MT @ 600, sold 800 MT @ 620, forward 610
+//	        realized   = 800 × (620 − 600)  = 16000
+//	        unrealized = 200 × (610 − 600)  =  2000
+//
+//	Trade costs (see trade.AllocateCosts) make a purchase dearer and a sale less profitable:
+//	they are added to the purchase amounts and deducted from the sale amounts of their month.
+//
+//	Amounts are converted into the report currency with the FX rates of the month start.
+//
+//	Months are summed into quarters and years (Gregorian and fiscal) using the PeriodStore:
+//	a month counts in every quarter/year whose date range contains it, as for TradeRollup.
+//
+// EXAMPLE USAGE:
+//
+//	calc := pnl.NewCalculator(periodStore, curveSet, fxRates, "EUR")
+//	book, err := tradeService.ListReportedTrades(ctx, repository.TradeFilter{})
+//	report, err := calc.Calculate(book, time.Now())
+//	// report.Quarters → [{PeriodID: "2026-Q1", Realized: 120000, Unrealized: 0, ...},
+//	//                    {PeriodID: "2026-Q2", Realized: 0, Unrealized: 45000, ...}, ...]
+type Calculator struct {
+	store    *period.PeriodStore
+	curves   *curves.Set
+	rates    fx.RateSource // May be nil when every amount and curve is in currency
+	currency string
+}
+
+func NewCalculator(store *period.PeriodStore, curveSet *curves.Set, rates fx.RateSource, currency string) *Calculator {
+	return &Calculator{store: store, curves: curveSet, rates: rates, currency: strings.ToUpper(currency)}
+}
+
+// monthAcc accumulates one month while calculating.
+type monthAcc struct {
+	p          *period.Period
+	purchased  float64            // MT
+	sold       float64            // MT
+	bought     float64            // Purchase amounts plus their costs, in the report currency
+	proceeds   float64            // Sale amounts less their costs, in the report currency
+	costs      float64            // Trade costs, in the report currency
+	net        map[string]float64 // Commodity → purchased minus sold MT
+	netTradeID map[string]string  // Commodity → a trade contributing to it, for error messages
+}
+
+// Calculate computes the PnL of book as of asOf from the breakdowns each trade reports (see
+// trade.ReportedBreakdowns): deleted and superseded trades are ignored, a cancelled trade
+// keeps its HARD_CLOSED months, offset by its reversal entries in the month they were posted.
+// It fails when a month has open volume of a commodity without a forward curve, or an amount
+// cannot be converted into the report currency.
+func (c *Calculator) Calculate(book []trade.BookedTrade, asOf time.Time) (*Report, error) {
+	months := make(map[string]*monthAcc)
+
+	for _, bt := range book {
+		tb := bt.Trade.Base()
+		sign := 1.0 // +1 adds volume to the long side (purchases), -1 to the short side (sales)
+		if bt.Trade.Kind() == trade.TradeKindSale {
+			sign = -1
+		}
+
+		for _, bd := range trade.ReportedBreakdowns(bt, c.store) {
+			p := c.store.FindByID(bd.PeriodID)
+			if p == nil {
+				return nil, fmt.Errorf("breakdown %s of trade %s: unknown period", bd.PeriodID, tb.ID)
+			}
+			acc := months[p.ID]
+			if acc == nil {
+				acc = &monthAcc{p: p, net: make(map[string]float64), netTradeID: make(map[string]string)}
+				months[p.ID] = acc
+			}
+
+			amount, err := c.convert(bd.TotalAmount, bd.Currency, p.StartDate)
+			if err != nil {
+				return nil, fmt.Errorf("breakdown %s of trade %s: %w", bd.PeriodID, tb.ID, err)
+			}
+			cost, err := c.convert(bd.CostAmount, bd.Currency, p.StartDate)
+			if err != nil {
+				return nil, fmt.Errorf("costs of breakdown %s of trade %s: %w", bd.PeriodID, tb.ID, err)
+			}
+			acc.costs += cost
+			if sign > 0 {
+				acc.purchased += bd.VolumeMT
+				acc.bought += amount + cost
+			} else {
+				acc.sold += bd.VolumeMT
+				acc.proceeds += amount - cost
+			}
+
+			commodity := markCommodity(tb)
+			acc.net[commodity] += sign * bd.VolumeMT
+			acc.netTradeID[commodity] = tb.ID
+		}
+	}
+
+	report := &Report{AsOf: asOf, Currency: c.currency}
+	for _, acc := range months {
+		// Open volume, marked against the curve of its commodity
+		var mark float64
+		for commodity, net := range acc.net {
+			if math.Abs(net) < volumeTolerance {
+				continue
+			}
+			forward, err := c.forward(commodity, acc.p.StartDate)
+			if err != nil {
+				return nil, fmt.Errorf("month %s of trade %s: %w", acc.p.ID, acc.netTradeID[commodity], err)
+			}
+			mark += net * forward
+		}
+
+		line := Line{
+			PeriodID:    acc.p.ID,
+			Calendar:    acc.p.Calendar,
+			Granularity: acc.p.Granularity,
+			PurchasedMT: acc.purchased,
+			SoldMT:      acc.sold,
+			Total:       acc.proceeds - acc.bought + mark,
+			Costs:       acc.costs,
+		}
+		if delivered(acc.p, asOf) && acc.purchased > 0 && acc.sold > 0 {
+			matched := math.Min(acc.purchased, acc.sold)
+			line.Realized = matched * (acc.proceeds/acc.sold - acc.bought/acc.purchased)
+		}
+		line.Unrealized = line.Total - line.Realized
+		report.Months = append(report.Months, line)
+		report.Total.add(line)
+	}
+	report.Total.PeriodID = "TOTAL"
+	c.sortByStart(report.Months)
+
+	report.Quarters = c.aggregate(report.Months, c.store.Quarters())
+	report.Years = c.aggregate(report.Months, c.store.Years())
+	return report, nil
+}
+
+// aggregate sums month lines into every given quarter or year that contains at least one of them.
+func (c *Calculator) aggregate(months []Line, parents []*period.Period) []Line {
+	var lines []Line
+	for _, agg := range parents {
+		if agg == nil {
+			continue
+		}
+		line := Line{PeriodID: agg.ID, Calendar: agg.Calendar, Granularity: agg.Granularity}
+		found := false
+		for _, m := range months {
+			p := c.store.FindByID(m.PeriodID)
+			if p == nil || p.StartDate.Before(agg.StartDate) || p.EndDate.After(agg.EndDate) {
+				continue
+			}
+			line.add(m)
+			found = true
+		}
+		if found {
+			lines = append(lines, line)
+		}
+	}
+	c.sortByStart(lines)
+	return lines
+}
+
+func (l *Line) add(o Line) {
+	l.Realized += o.Realized
+	l.Unrealized += o.Unrealized
+	l.Total += o.Total
+	l.Costs += o.Costs
+	l.PurchasedMT += o.PurchasedMT
+	l.SoldMT += o.SoldMT
+}
+
+// markCommodity is the curve a trade is marked against: its commodity, or the index of a
+// floating trade booked without one.
+func markCommodity(tb *trade.TradeBase) string {
+	if tb.Commodity == "" && tb.Pricing != nil {
+		return tb.Pricing.Index
+	}
+	return tb.Commodity
+}
+
+// forward returns the curve price of a commodity for a month, in the report currency.
+func (c *Calculator) forward(commodity string, month time.Time) (float64, error) {
+	if commodity == "" {
+		return 0, fmt.Errorf("open volume without a commodity cannot be marked")
+	}
+	if c.curves == nil {
+		return 0, fmt.Errorf("no forward curves to mark open volume against")
+	}
+
+	curve, err := c.curves.Curve(commodity)
+	if err != nil {
+		return 0, err
+	}
+	return c.convert(curve.MonthPrice(month), curve.Currency, month)
+}
+
+// convert converts an amount into the report currency at the rate of date.
+func (c *Calculator) convert(amount float64, currency string, date time.Time) (float64, error) {
+	if strings.EqualFold(currency, c.currency) {
+		return amount, nil
+	}
+	if c.rates == nil {
+		return 0, fmt.Errorf("cannot convert %s into %s: no FX rates", currency, c.currency)
+	}
+	return fx.Convert(c.rates, amount, currency, c.currency, date)
+}
+
+func (c *Calculator) sortByStart(lines []Line) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := c.store.FindByID(lines[i].PeriodID), c.store.FindByID(lines[j].PeriodID)
+		if !a.StartDate.Equal(b.StartDate) {
+			return a.StartDate.Before(b.StartDate)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// delivered reports whether a month ended before the valuation day.
+func delivered(p *period.Period, asOf time.Time) bool {
+	return p.EndDate.Before(asOf)
+}
@@ -0,0 +1,86 @@
+package pnl
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nholding/cso-book/internal/curves"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+func TestCalculateAcrossCancellationAfterHardClose(t *testing.T) {
+	store := period.NewMockPeriodStore(2026, 2026)
+	for _, id := range []string{"2026-JAN", "2026-FEB"} {
+		if err := store.Update(id, func(p *period.Period) { p.Status = period.PeriodHardClosed }); err != nil {
+			t.Fatalf("closing %s: %v", id, err)
+		}
+	}
+
+	purchase, breakdowns := trade.NewPurchase(store, "supplier", period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"},
+		1000, 600, "EUR", "trader@internal.local")
+	purchase.Commodity = "diesel"
+
+	at := time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC)
+	result, err := trade.CancelTrade(&purchase.TradeBase, breakdowns, store, at, "counterparty default", "trader@internal.local")
+	if err != nil {
+		t.Fatalf("CancelTrade: %v", err)
+	}
+	if len(result.Reversals) != 2 || len(result.Removed) != 1 {
+		t.Fatalf("got %d reversals and %d removed breakdowns, want 2 and 1", len(result.Reversals), len(result.Removed))
+	}
+
+	curve, err := curves.NewCurve("diesel", "EUR", at, []curves.Point{{Month: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), Price: 610}})
+	if err != nil {
+		t.Fatalf("NewCurve: %v", err)
+	}
+	curveSet := curves.NewSet()
+	curveSet.Put(curve)
+	calc := NewCalculator(store, curveSet, nil, "EUR")
+
+	// The removed MAR breakdown may still be loaded with the trade; it must not count
+	book := []trade.BookedTrade{{Trade: &purchase, Breakdowns: append(breakdowns, result.Reversals...)}}
+	report, err := calc.Calculate(book, at)
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+
+	perMonth := breakdowns[0].VolumeMT * (610 - 600)
+	want := map[string]float64{
+		"2026-JAN": perMonth,      // Closed months stay as they were reported
+		"2026-FEB": perMonth,      //
+		"2026-MAR": -2 * perMonth, // Both reversals are posted in the first open month
+	}
+	if len(report.Months) != len(want) {
+		t.Fatalf("got %d months, want %d: %+v", len(report.Months), len(want), report.Months)
+	}
+	for _, line := range report.Months {
+		w, ok := want[line.PeriodID]
+		if !ok {
+			t.Errorf("unexpected month %s", line.PeriodID)
+			continue
+		}
+		if math.Abs(line.Total-w) > 1e-6 {
+			t.Errorf("%s: total %v, want %v", line.PeriodID, line.Total, w)
+		}
+	}
+	if math.Abs(report.Total.Total) > 1e-6 {
+		t.Errorf("total PnL of the cancelled trade is %v, want 0", report.Total.Total)
+	}
+}
+
+func TestCalculateIgnoresSupersededTrades(t *testing.T) {
+	store := period.NewMockPeriodStore(2026, 2026)
+	purchase, breakdowns := trade.NewPurchase(store, "supplier", period.PeriodRange{StartPeriodID: "2026-JAN", EndPeriodID: "2026-JAN"},
+		1000, 600, "EUR", "trader@internal.local")
+	purchase.Status = trade.TradeStatusSuperseded
+
+	report, err := NewCalculator(store, curves.NewSet(), nil, "EUR").Calculate([]trade.BookedTrade{{Trade: &purchase, Breakdowns: breakdowns}}, time.Now())
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	if len(report.Months) != 0 {
+		t.Errorf("superseded trade shows up in %+v", report.Months)
+	}
+}
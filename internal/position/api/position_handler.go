@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/position"
+)
+
+// PositionSource provides the positions of the book; *position.Engine implements it.
+type PositionSource interface {
+	GetProductPosition(ctx context.Context, productID, periodID string) (position.Position, error)
+	GetProductLadder(ctx context.Context, productID string) (position.Ladder, error)
+}
+
+// PositionHandler serves the net position of the book.
+//
+//	GET /positions              full ladder: months, quarters, years and total
+//	GET /positions/{periodId}   one month, quarter or year, e.g. 2026-JAN, 2026-Q1, 2026
+//
+// Both take an optional ?product={productId} to report the trades of one product only.
+//
+// Example response of GET /positions/2026-Q1:
+//
+//	{"periodId": "2026-Q1", "calendar": "CAL", "granularity": "QUARTERLY",
+//	 "purchasedMT": 30000, "soldMT": 12000, "netMT": 18000}
+type PositionHandler struct {
+	source PositionSource
+}
+
+func NewPositionHandler(source PositionSource) *PositionHandler {
+	return &PositionHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *PositionHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /positions", h.ladder)
+	mux.HandleFunc("GET /positions/{periodId}", h.position)
+}
+
+func (h *PositionHandler) ladder(w http.ResponseWriter, r *http.Request) {
+	ladder, err := h.source.GetProductLadder(r.Context(), r.URL.Query().Get("product"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to compute the position ladder: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, ladder)
+}
+
+func (h *PositionHandler) position(w http.ResponseWriter, r *http.Request) {
+	periodID := r.PathValue("periodId")
+
+	pos, err := h.source.GetProductPosition(r.Context(), r.URL.Query().Get("product"), periodID)
+	if errors.Is(err, dberr.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to compute the position of %s: %w", periodID, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, pos)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,251 @@
+package position
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// Position is the net volume of the book in one period: a month, or a quarter or year (CAL or
+// FY) summed from its months.
+type Position struct {
+	PeriodID    string                   `json:"periodId"`
+	Calendar    period.CalendarType      `json:"calendar"`
+	Granularity period.PeriodGranularity `json:"granularity"`
+	PurchasedMT float64                  `json:"purchasedMT"`
+	SoldMT      float64                  `json:"soldMT"`
+	NetMT       float64                  `json:"netMT"` // Purchased minus sold; positive means long
+}
+
+// Ladder is the position of the book month by month, with the quarters and years above.
+// Months run without gaps from the first to the last month with volume.
+type Ladder struct {
+	Months   []Position `json:"months"`
+	Quarters []Position `json:"quarters"` // CAL and FY, by start date
+	Years    []Position `json:"years"`    // CAL and FY, by start date
+	Total    Position   `json:"total"`
+}
+
+// Snapshot holds the monthly positions of a book at one moment.
+type Snapshot struct {
+	store  *period.PeriodStore
+	months map[string]*Position // Month ID → position
+}
+
+// Compute sums the breakdown volumes of a book per delivery month: purchases add to the long
+// side, sales to the short side, of the breakdowns each trade reports (see
+// trade.ReportedBreakdowns). Reversal entries count in the month they reverse (as in
+// BuildVolumeLadder), so a cancelled trade drops out of its HARD_CLOSED months as well.
+//
+// Example:
+//
+//	snap := position.Compute(periodStore, book)
+//	pos, _ := snap.Position("2026-Q1")
+//	// → {PeriodID: "2026-Q1", PurchasedMT: 30000, SoldMT: 12000, NetMT: 18000}
+func Compute(store *period.PeriodStore, book []trade.BookedTrade) *Snapshot {
+	s := &Snapshot{store: store, months: make(map[string]*Position)}
+
+	for _, bt := range book {
+		sale := bt.Trade.Kind() == trade.TradeKindSale
+
+		for _, bd := range trade.ReportedBreakdowns(bt, store) {
+			monthID := bd.PeriodID
+			if bd.IsReversal() && bd.ReversedPeriodID != nil {
+				monthID = *bd.ReversedPeriodID
+			}
+			m := store.FindByID(monthID)
+			if m == nil {
+				continue
+			}
+
+			pos := s.months[m.ID]
+			if pos == nil {
+				pos = &Position{PeriodID: m.ID, Calendar: m.Calendar, Granularity: m.Granularity}
+				s.months[m.ID] = pos
+			}
+			if sale {
+				pos.SoldMT += bd.VolumeMT
+			} else {
+				pos.PurchasedMT += bd.VolumeMT
+			}
+			pos.NetMT = pos.PurchasedMT - pos.SoldMT
+		}
+	}
+
+	return s
+}
+
+// Position returns the position of a month, quarter or year. Quarters and years sum the months
+// that lie within their date range. It fails with a *dberr.NotFoundError for an unknown period.
+func (s *Snapshot) Position(periodID string) (Position, error) {
+	p := s.store.FindByID(periodID)
+	if p == nil {
+		return Position{}, &dberr.NotFoundError{Entity: "period", ID: periodID}
+	}
+	if p.Granularity == period.MonthlyPeriod {
+		if pos, ok := s.months[p.ID]; ok {
+			return *pos, nil
+		}
+		return Position{PeriodID: p.ID, Calendar: p.Calendar, Granularity: p.Granularity}, nil
+	}
+
+	pos, _ := s.aggregate(p)
+	return pos, nil
+}
+
+// Ladder returns the full position ladder of the snapshot.
+func (s *Snapshot) Ladder() Ladder {
+	var ladder Ladder
+	ladder.Total.PeriodID = "TOTAL"
+
+	var first, last *period.Period
+	for id := range s.months {
+		m := s.store.FindByID(id)
+		if first == nil || m.StartDate.Before(first.StartDate) {
+			first = m
+		}
+		if last == nil || m.StartDate.After(last.StartDate) {
+			last = m
+		}
+	}
+	if first == nil {
+		return ladder
+	}
+
+	for _, m := range s.store.Months() {
+		if m.StartDate.Before(first.StartDate) || m.StartDate.After(last.StartDate) {
+			continue
+		}
+		pos, _ := s.Position(m.ID)
+		ladder.Months = append(ladder.Months, pos)
+		ladder.Total.PurchasedMT += pos.PurchasedMT
+		ladder.Total.SoldMT += pos.SoldMT
+	}
+	ladder.Total.NetMT = ladder.Total.PurchasedMT - ladder.Total.SoldMT
+
+	ladder.Quarters = s.aggregateAll(s.store.Quarters())
+	ladder.Years = s.aggregateAll(s.store.Years())
+	return ladder
+}
+
+// aggregate sums the months within p. It reports whether any month had volume.
+func (s *Snapshot) aggregate(p *period.Period) (Position, bool) {
+	pos := Position{PeriodID: p.ID, Calendar: p.Calendar, Granularity: p.Granularity}
+	found := false
+	for id, m := range s.months {
+		mp := s.store.FindByID(id)
+		if mp.StartDate.Before(p.StartDate) || mp.EndDate.After(p.EndDate) {
+			continue
+		}
+		pos.PurchasedMT += m.PurchasedMT
+		pos.SoldMT += m.SoldMT
+		found = true
+	}
+	pos.NetMT = pos.PurchasedMT - pos.SoldMT
+	return pos, found
+}
+
+// aggregateAll returns the positions of the given quarters or years that have volume, by start date.
+func (s *Snapshot) aggregateAll(parents []*period.Period) []Position {
+	var out []Position
+	for _, p := range parents {
+		if p == nil {
+			continue
+		}
+		if pos, ok := s.aggregate(p); ok {
+			out = append(out, pos)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := s.store.FindByID(out[i].PeriodID), s.store.FindByID(out[j].PeriodID)
+		if !a.StartDate.Equal(b.StartDate) {
+			return a.StartDate.Before(b.StartDate)
+		}
+		return a.ID < b.ID
+	})
+	return out
+}
+
+// BookSource provides the trades that count in the book with the breakdowns they report
+// (see trade.ReportedBreakdowns); *service.TradeService implements it.
+type BookSource interface {
+	ListReportedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error)
+}
+
+// NominationSource replaces contract volumes by the volumes operators nominated for the coming
+// months; *service.NominationService implements it.
+type NominationSource interface {
+	ApplyNominations(ctx context.Context, book []trade.BookedTrade) ([]trade.BookedTrade, error)
+}
+
+// Engine computes positions from the current book on every call, so it never serves stale data.
+//
+// EXAMPLE USAGE:
+//
+//	engine := position.NewEngine(tradeService, periodService.GetPeriodStore())
+//	pos, err := engine.GetPosition(ctx, "2026-JAN")
+//	ladder, err := engine.GetLadder(ctx)
+//	ulsd, err := engine.GetProductLadder(ctx, ulsdProductID) // Trades of one product only
+type Engine struct {
+	source      BookSource
+	store       *period.PeriodStore
+	nominations NominationSource // nil: positions use the contract volumes
+}
+
+func NewEngine(source BookSource, store *period.PeriodStore) *Engine {
+	return &Engine{source: source, store: store}
+}
+
+// SetNominations makes positions count the nominated volume of every nominated month instead
+// of its contract volume; nil restores the contract volumes.
+func (e *Engine) SetNominations(nominations NominationSource) {
+	e.nominations = nominations
+}
+
+// GetPosition returns the net position of the book in a month, quarter or year.
+func (e *Engine) GetPosition(ctx context.Context, periodID string) (Position, error) {
+	return e.GetProductPosition(ctx, "", periodID)
+}
+
+// GetLadder returns the full position ladder of the book.
+func (e *Engine) GetLadder(ctx context.Context) (Ladder, error) {
+	return e.GetProductLadder(ctx, "")
+}
+
+// GetProductPosition returns the net position of the trades of one product in a month, quarter
+// or year; an empty productID means the whole book.
+func (e *Engine) GetProductPosition(ctx context.Context, productID, periodID string) (Position, error) {
+	snap, err := e.snapshot(ctx, productID)
+	if err != nil {
+		return Position{}, err
+	}
+	return snap.Position(periodID)
+}
+
+// GetProductLadder returns the position ladder of the trades of one product; an empty
+// productID means the whole book.
+func (e *Engine) GetProductLadder(ctx context.Context, productID string) (Ladder, error) {
+	snap, err := e.snapshot(ctx, productID)
+	if err != nil {
+		return Ladder{}, err
+	}
+	return snap.Ladder(), nil
+}
+
+func (e *Engine) snapshot(ctx context.Context, productID string) (*Snapshot, error) {
+	book, err := e.source.ListReportedTrades(ctx, repository.TradeFilter{ProductID: productID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the book: %w", err)
+	}
+	if e.nominations != nil {
+		if book, err = e.nominations.ApplyNominations(ctx, book); err != nil {
+			return nil, fmt.Errorf("failed to apply nominations: %w", err)
+		}
+	}
+	return Compute(e.store, book), nil
+}
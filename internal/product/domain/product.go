@@ -0,0 +1,101 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nholding/cso-book/internal/audit"
+	"github.com/nholding/cso-book/internal/units"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// QualitySpec is one contractual quality limit of a product, e.g. sulphur at most 10 mg/kg.
+// Min and Max are optional; at least one of them is set.
+type QualitySpec struct {
+	Property string   `json:"property"`         // e.g. "sulphur", "density", "flash point"
+	Min      *float64 `json:"min,omitempty"`    // Lower limit, inclusive
+	Max      *float64 `json:"max,omitempty"`    // Upper limit, inclusive
+	Unit     string   `json:"unit"`             // Unit of the limits, e.g. "mg/kg", "kg/m3", "°C"
+	Method   string   `json:"method,omitempty"` // Test method, e.g. "EN ISO 20846"
+}
+
+// Product is a tradeable grade of a commodity. Trades refer to it by ID, so the book can hold
+// several products and report positions per product.
+//
+// Example (EN 590 diesel, booked in tonnes):
+//
+//	maxS := 10.0
+//	p, _ := product.NewProduct("ULSD-10PPM", "ULSD 10ppm", "diesel", "EN 590", units.MetricTonne,
+//	    []product.QualitySpec{{Property: "sulphur", Max: &maxS, Unit: "mg/kg", Method: "EN ISO 20846"}},
+//	    "user@internal.local")
+type Product struct {
+	ID          string          `json:"id"`          // Stable ULID (primary key)
+	Code        string          `json:"code"`        // Unique, upper case, e.g. ULSD-10PPM
+	Name        string          `json:"name"`        // e.g. ULSD 10ppm
+	Commodity   string          `json:"commodity"`   // Commodity for unit conversion and curves, e.g. "diesel" (see units.DefaultCommodities)
+	Grade       string          `json:"grade"`       // Grade or standard, e.g. "EN 590"
+	DefaultUnit units.Unit      `json:"defaultUnit"` // Unit trades of the product are booked in by default
+	Specs       []QualitySpec   `json:"specs"`
+	Active      bool            `json:"active"`     // Inactive products stay for history but take no new trades
+	RowVersion  int             `json:"rowVersion"` // Optimistic-lock counter, bumped by every update of the stored row
+	AuditInfo   audit.AuditInfo `json:"audit"`
+}
+
+// NewProduct creates an active product with a fresh ID. The code is upper-cased and the
+// commodity lower-cased; an empty defaultUnit means MT.
+func NewProduct(code, name, commodity, grade string, defaultUnit units.Unit, specs []QualitySpec, user string) (Product, error) {
+	unit, err := units.ParseUnit(string(defaultUnit))
+	if err != nil {
+		return Product{}, fmt.Errorf("product %s: %w", code, err)
+	}
+
+	p := Product{
+		ID:          utils.GenerateStableID(),
+		Code:        strings.ToUpper(strings.TrimSpace(code)),
+		Name:        strings.TrimSpace(name),
+		Commodity:   strings.ToLower(strings.TrimSpace(commodity)),
+		Grade:       strings.TrimSpace(grade),
+		DefaultUnit: unit,
+		Specs:       specs,
+		Active:      true,
+		AuditInfo:   *audit.NewAuditInfo(user),
+	}
+
+	if err := p.Validate(); err != nil {
+		return Product{}, err
+	}
+	return p, nil
+}
+
+// Validate checks that a product is complete and its quality limits are consistent.
+func (p *Product) Validate() error {
+	var errs []error
+	if p.Code == "" {
+		errs = append(errs, errors.New("code is required"))
+	}
+	if p.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if p.Commodity == "" {
+		errs = append(errs, errors.New("commodity is required"))
+	}
+	if u, err := units.ParseUnit(string(p.DefaultUnit)); err != nil || u != p.DefaultUnit {
+		errs = append(errs, fmt.Errorf("invalid default unit %q", p.DefaultUnit))
+	}
+	for _, s := range p.Specs {
+		switch {
+		case s.Property == "":
+			errs = append(errs, errors.New("quality spec without a property"))
+		case s.Min == nil && s.Max == nil:
+			errs = append(errs, fmt.Errorf("quality spec %s has neither a min nor a max", s.Property))
+		case s.Min != nil && s.Max != nil && *s.Min > *s.Max:
+			errs = append(errs, fmt.Errorf("quality spec %s: min %v is above max %v", s.Property, *s.Min, *s.Max))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("product %s is invalid: %w", p.Code, errors.Join(errs...))
+	}
+	return nil
+}
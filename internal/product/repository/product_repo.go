@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	product "github.com/nholding/cso-book/internal/product/domain"
+	"github.com/nholding/cso-book/internal/units"
+)
+
+// ProductRepository defines the interface for storing and retrieving Products from a persistence layer
+type ProductRepository interface {
+	// Save inserts a new product; it fails with *dberr.DuplicateError when the code is taken
+	Save(ctx context.Context, p *product.Product) error
+
+	// Update updates an existing product, checked against its RowVersion (optimistic
+	// concurrency; a lost race returns *dberr.ConflictError)
+	Update(ctx context.Context, p *product.Product) error
+
+	// FindByID retrieves a product; returns nil, nil when it does not exist
+	FindByID(ctx context.Context, id string) (*product.Product, error)
+
+	// FindByCode retrieves the product with a code; returns nil, nil when none exists
+	FindByCode(ctx context.Context, code string) (*product.Product, error)
+
+	// List retrieves the products of a commodity (all when empty), ordered by code
+	List(ctx context.Context, commodity string) ([]*product.Product, error)
+}
+
+// Compile-time check that RdsProductRepository satisfies ProductRepository
+var _ ProductRepository = (*RdsProductRepository)(nil)
+
+type RdsProductRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsProductRepository(cfg *awsclient.Config) (*RdsProductRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsProductRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// Save inserts a new product. The code column is unique, so a product that is already known
+// is rejected instead of being stored twice.
+//
+// Example:
+//
+//	p, _ := product.NewProduct("ULSD-10PPM", "ULSD 10ppm", "diesel", "EN 590", units.MetricTonne, specs, "user@internal.local")
+//	err := repo.Save(ctx, &p)
+func (r *RdsProductRepository) Save(ctx context.Context, p *product.Product) error {
+	specs, err := specsJSON(p)
+	if err != nil {
+		return err
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO products (
+			id, code, name, commodity, grade, default_unit, specs, active, row_version,
+			audit_created_by, audit_created_at, audit_updated_by, audit_updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,1,$9,$10,$11,$12)
+	`,
+		p.ID,
+		p.Code,
+		p.Name,
+		p.Commodity,
+		p.Grade,
+		string(p.DefaultUnit),
+		specs,
+		p.Active,
+		p.AuditInfo.CreatedBy,
+		p.AuditInfo.CreatedAt,
+		p.AuditInfo.UpdatedBy,
+		p.AuditInfo.UpdatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "product", ID: p.ID, Key: "code " + p.Code, Err: err}
+		}
+		return fmt.Errorf("failed to insert product %s: %w", p.Code, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityProduct, p.ID, audit.ActionCreate, p.AuditInfo.CreatedBy,
+		map[string]any{"code": p.Code, "commodity": p.Commodity, "grade": p.Grade})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit product transaction: %w", err)
+	}
+
+	p.RowVersion = 1
+	return nil
+}
+
+// Update writes the current state of an existing product. The ID, code and creation audit
+// never change. On success p.RowVersion is incremented to match the DB.
+func (r *RdsProductRepository) Update(ctx context.Context, p *product.Product) error {
+	expected := p.RowVersion
+	if expected <= 0 {
+		expected = 1
+	}
+	specs, err := specsJSON(p)
+	if err != nil {
+		return err
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE products
+		SET name=$1, commodity=$2, grade=$3, default_unit=$4, specs=$5, active=$6,
+		    audit_updated_by=$7, audit_updated_at=$8, row_version=row_version+1
+		WHERE id=$9 AND row_version=$10
+	`,
+		p.Name,
+		p.Commodity,
+		p.Grade,
+		string(p.DefaultUnit),
+		specs,
+		p.Active,
+		p.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		p.ID,
+		expected,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update product %s: %w", p.ID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		var actual int
+		err := tx.QueryRowContext(ctx, `SELECT row_version FROM products WHERE id=$1`, p.ID).Scan(&actual)
+		if err == sql.ErrNoRows {
+			return &dberr.NotFoundError{Entity: "product", ID: p.ID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row version of product %s: %w", p.ID, err)
+		}
+		return &dberr.ConflictError{Entity: "product", ID: p.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityProduct, p.ID, audit.ActionUpdate, p.AuditInfo.LastActor(),
+		map[string]any{"name": p.Name, "grade": p.Grade, "defaultUnit": string(p.DefaultUnit), "specs": len(p.Specs), "active": p.Active})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit product transaction: %w", err)
+	}
+
+	p.RowVersion = expected + 1
+	return nil
+}
+
+// FindByID retrieves a single product by ID. Returns nil, nil when the product does not exist.
+func (r *RdsProductRepository) FindByID(ctx context.Context, id string) (*product.Product, error) {
+	return r.findOne(ctx, `SELECT `+productColumns+` FROM products WHERE id=$1`, id)
+}
+
+// FindByCode retrieves a product by its code, case-insensitively.
+func (r *RdsProductRepository) FindByCode(ctx context.Context, code string) (*product.Product, error) {
+	return r.findOne(ctx, `SELECT `+productColumns+` FROM products WHERE code=$1`, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// List retrieves the products of one commodity, or all products when commodity is empty.
+// Inactive products are included.
+func (r *RdsProductRepository) List(ctx context.Context, commodity string) ([]*product.Product, error) {
+	rows, err := r.reader.QueryContext(ctx,
+		`SELECT `+productColumns+` FROM products WHERE $1 = '' OR commodity = $1 ORDER BY code`,
+		strings.ToLower(strings.TrimSpace(commodity)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*product.Product
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate product rows: %w", err)
+	}
+
+	return products, nil
+}
+
+func (r *RdsProductRepository) findOne(ctx context.Context, query string, arg string) (*product.Product, error) {
+	p, err := scanProduct(r.reader.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan product: %w", err)
+	}
+
+	return p, nil
+}
+
+// specsJSON encodes the quality specs of a product, never null.
+func specsJSON(p *product.Product) ([]byte, error) {
+	specs := p.Specs
+	if specs == nil {
+		specs = []product.QualitySpec{}
+	}
+	b, err := json.Marshal(specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quality specs of product %s: %w", p.Code, err)
+	}
+	return b, nil
+}
+
+// productColumns is the column list scanned by scanProduct.
+const productColumns = `id, code, name, commodity, grade, default_unit, specs, active, row_version,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner) (*product.Product, error) {
+	var (
+		p     product.Product
+		unit  string
+		specs []byte
+	)
+	if err := row.Scan(
+		&p.ID,
+		&p.Code,
+		&p.Name,
+		&p.Commodity,
+		&p.Grade,
+		&unit,
+		&specs,
+		&p.Active,
+		&p.RowVersion,
+		&p.AuditInfo.CreatedBy,
+		&p.AuditInfo.CreatedAt,
+		&p.AuditInfo.UpdatedBy,
+		&p.AuditInfo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	p.DefaultUnit = units.Unit(unit)
+	if len(specs) > 0 {
+		if err := json.Unmarshal(specs, &p.Specs); err != nil {
+			return nil, fmt.Errorf("failed to decode quality specs of product %s: %w", p.ID, err)
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	product "github.com/nholding/cso-book/internal/product/domain"
+	"github.com/nholding/cso-book/internal/product/repository"
+	"github.com/nholding/cso-book/internal/units"
+)
+
+type ProductService struct {
+	repo repository.ProductRepository
+}
+
+func NewProductService(repo repository.ProductRepository) *ProductService {
+	return &ProductService{
+		repo: repo,
+	}
+}
+
+// Create
+//
+// PURPOSE:
+//
+//	Adds a product to the master data. Codes are unique: a code that is already taken is
+//	rejected with a *dberr.DuplicateError.
+//
+// EXAMPLE USAGE:
+//
+//	maxS := 10.0
+//	p, err := productService.Create(ctx, "ULSD-10PPM", "ULSD 10ppm", "diesel", "EN 590", units.MetricTonne,
+//	    []product.QualitySpec{{Property: "sulphur", Max: &maxS, Unit: "mg/kg"}}, "user@internal.local")
+func (s *ProductService) Create(ctx context.Context, code, name, commodity, grade string, defaultUnit units.Unit, specs []product.QualitySpec, user string) (*product.Product, error) {
+	p, err := product.NewProduct(code, name, commodity, grade, defaultUnit, specs, user)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByCode(ctx, p.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up product %s: %w", p.Code, err)
+	}
+	if existing != nil {
+		return nil, &dberr.DuplicateError{Entity: "product", ID: existing.ID, Key: "code " + p.Code}
+	}
+
+	if err := s.repo.Save(ctx, &p); err != nil {
+		return nil, fmt.Errorf("failed to save product %s: %w", p.Code, err)
+	}
+	return &p, nil
+}
+
+// Get returns a product; it fails with a *dberr.NotFoundError when it does not exist.
+func (s *ProductService) Get(ctx context.Context, id string) (*product.Product, error) {
+	p, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product %s: %w", id, err)
+	}
+	if p == nil {
+		return nil, &dberr.NotFoundError{Entity: "product", ID: id}
+	}
+	return p, nil
+}
+
+// List returns the products of one commodity, or all when commodity is empty.
+func (s *ProductService) List(ctx context.Context, commodity string) ([]*product.Product, error) {
+	return s.repo.List(ctx, commodity)
+}
+
+// UpdateSpecs replaces the quality specs of a product.
+func (s *ProductService) UpdateSpecs(ctx context.Context, id string, specs []product.QualitySpec, user string) (*product.Product, error) {
+	p, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Specs = specs
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	p.AuditInfo.UpdateAuditInfo(user)
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to update quality specs of product %s: %w", p.Code, err)
+	}
+	return p, nil
+}
+
+// Deactivate keeps a product for the trades that refer to it but stops new trades from using it.
+func (s *ProductService) Deactivate(ctx context.Context, id, user string) (*product.Product, error) {
+	p, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Active {
+		return p, nil
+	}
+
+	p.Active = false
+	p.AuditInfo.UpdateAuditInfo(user)
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to deactivate product %s: %w", p.Code, err)
+	}
+	return p, nil
+}
+
+// ActiveProduct returns a product that new trades may be booked in: it fails when the product
+// does not exist (*dberr.NotFoundError) or is inactive. It implements the ProductCatalog of
+// the trade service.
+func (s *ProductService) ActiveProduct(ctx context.Context, id string) (*product.Product, error) {
+	p, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Active {
+		return nil, fmt.Errorf("product %s (%s) is inactive", p.Code, p.ID)
+	}
+	return p, nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nholding/cso-book/internal/search"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// Searcher runs searches; *search.Service implements it.
+type Searcher interface {
+	Search(ctx context.Context, q search.Query) ([]search.Hit, error)
+}
+
+// SearchHandler serves free-text search over trades and companies.
+//
+//	GET /search?q=shell+rott&type=TRADE&kind=PURCHASE&status=CONFIRMED,PENDING&limit=10
+//
+// Query parameters:
+//
+//	q            - search text (required); every word must match as a prefix
+//	type         - TRADE or COMPANY (default: both)
+//	kind         - narrow trade hits to PURCHASE, SALE, SWAP or STORAGE
+//	status       - narrow trade hits to these comma-separated statuses
+//	counterparty - narrow trade hits to one counterparty company ID
+//	limit        - number of hits (default 20, max 100)
+//
+// Example response:
+//
+//	{
+//	  "query": "shell rott",
+//	  "hits": [
+//	    {"type": "COMPANY", "id": "01J...C", "label": "Shell Rotterdam", "matchedOn": "company", "rank": 0.09},
+//	    {"type": "TRADE", "id": "01J...T", "label": "S-2026-0042", "matchedOn": "counterparty", "rank": 0.09,
+//	     "kind": "SALE", "status": "CONFIRMED", "counterpartyId": "01J...C", "counterpartyName": "Shell Rotterdam"}
+//	  ]
+//	}
+type SearchHandler struct {
+	searcher Searcher
+}
+
+func NewSearchHandler(searcher Searcher) *SearchHandler {
+	return &SearchHandler{searcher: searcher}
+}
+
+// Register mounts the handler on mux.
+func (h *SearchHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /search", h)
+}
+
+func (h *SearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := search.Query{
+		Text:           params.Get("q"),
+		TradeKind:      trade.TradeKind(params.Get("kind")),
+		CounterpartyID: params.Get("counterparty"),
+	}
+	if search.PrefixQuery(q.Text) == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("search text q must contain at least one letter or digit"))
+		return
+	}
+
+	switch t := search.HitType(params.Get("type")); t {
+	case "":
+	case search.HitTrade, search.HitCompany:
+		q.Types = []search.HitType{t}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("type must be %s or %s", search.HitTrade, search.HitCompany))
+		return
+	}
+	if v := params.Get("status"); v != "" {
+		for _, st := range strings.Split(v, ",") {
+			q.TradeStatuses = append(q.TradeStatuses, trade.TradeStatus(strings.TrimSpace(st)))
+		}
+	}
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > search.MaxLimit {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", search.MaxLimit))
+			return
+		}
+		q.Limit = n
+	}
+
+	hits, err := h.searcher.Search(r.Context(), q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to search %q: %w", q.Text, err))
+		return
+	}
+	if hits == nil {
+		hits = []search.Hit{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"query": q.Text, "hits": hits})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/search"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// Compile-time check that RdsSearchRepository satisfies search.Index
+var _ search.Index = (*RdsSearchRepository)(nil)
+
+// RdsSearchRepository searches the search_vector columns of companies and trades and the
+// reasons of trade_status_history (see migration 0032_search.sql). It only reads, so all
+// queries go to the reader endpoint.
+type RdsSearchRepository struct {
+	reader *sql.DB
+}
+
+func NewRdsSearchRepository(cfg *awsclient.Config) (*RdsSearchRepository, error) {
+	_, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsSearchRepository{reader: reader.Client}, nil
+}
+
+// SearchCompanies returns the companies that were not merged into another and match tsquery,
+// most relevant first.
+func (r *RdsSearchRepository) SearchCompanies(ctx context.Context, q search.Query, tsquery string) ([]search.Hit, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+		SELECT id, COALESCE(NULLIF(display_name, ''), NULLIF(common_name, ''), name), ts_rank(search_vector, query) AS rank
+		FROM companies, to_tsquery('simple', $1) query
+		WHERE merged_into_id IS NULL AND search_vector @@ query
+		ORDER BY rank DESC, name
+		LIMIT $2
+	`, tsquery, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []search.Hit
+	for rows.Next() {
+		h := search.Hit{Type: search.HitCompany, MatchedOn: "company"}
+		if err := rows.Scan(&h.ID, &h.Label, &h.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan company hit: %w", err)
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate company hits: %w", err)
+	}
+	return hits, nil
+}
+
+// SearchTrades returns the live trades whose deal number starts with the search text, or
+// whose own terms, counterparty or status change reasons match tsquery, most relevant first.
+// A deal number match ranks above every full-text match.
+func (r *RdsSearchRepository) SearchTrades(ctx context.Context, q search.Query, tsquery string) ([]search.Hit, error) {
+	args := []any{tsquery, likePrefix(q.Text)}
+	conditions := []string{"t.deleted_at IS NULL"}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if q.TradeKind != "" {
+		add("t.kind=$%d", string(q.TradeKind))
+	}
+	if len(q.TradeStatuses) > 0 {
+		statuses := make([]string, len(q.TradeStatuses))
+		for i, st := range q.TradeStatuses {
+			statuses[i] = string(st)
+		}
+		add("t.status=ANY($%d)", pq.Array(statuses))
+	}
+	if q.CounterpartyID != "" {
+		add("t.counterparty_id=$%d", q.CounterpartyID)
+	}
+	args = append(args, q.Limit)
+
+	rows, err := r.reader.QueryContext(ctx, `
+		SELECT id, trade_number, kind, status, counterparty_id, counterparty_name, comment, matched_on, rank
+		FROM (
+			SELECT t.id, COALESCE(t.trade_number, t.id) AS trade_number, t.kind, t.status, t.counterparty_id,
+				COALESCE(NULLIF(c.display_name, ''), NULLIF(c.common_name, ''), c.name, '') AS counterparty_name,
+				COALESCE(h.reason, '') AS comment,
+				CASE
+					WHEN t.trade_number ILIKE $2 THEN 'tradeNumber'
+					WHEN t.search_vector @@ query THEN 'terms'
+					WHEN c.search_vector @@ query THEN 'counterparty'
+					ELSE 'comment'
+				END AS matched_on,
+				CASE WHEN t.trade_number ILIKE $2 THEN 1 ELSE 0 END
+					+ GREATEST(ts_rank(t.search_vector, query), COALESCE(ts_rank(c.search_vector, query), 0),
+						COALESCE(ts_rank(to_tsvector('simple', h.reason), query), 0)) AS rank,
+				t.audit_created_at
+			FROM trades t
+			CROSS JOIN to_tsquery('simple', $1) query
+			LEFT JOIN companies c ON c.id = t.counterparty_id
+			LEFT JOIN LATERAL (
+				SELECT reason FROM trade_status_history
+				WHERE trade_id = t.id AND to_tsvector('simple', reason) @@ query
+				ORDER BY changed_at DESC
+				LIMIT 1
+			) h ON true
+			WHERE `+strings.Join(conditions, " AND ")+`
+				AND (t.trade_number ILIKE $2 OR t.search_vector @@ query OR c.search_vector @@ query OR h.reason IS NOT NULL)
+		) hits
+		ORDER BY rank DESC, audit_created_at DESC
+		LIMIT $`+fmt.Sprint(len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []search.Hit
+	for rows.Next() {
+		h := search.Hit{Type: search.HitTrade}
+		var kind, status string
+		if err := rows.Scan(&h.ID, &h.Label, &kind, &status, &h.CounterpartyID, &h.CounterpartyName, &h.Comment, &h.MatchedOn, &h.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan trade hit: %w", err)
+		}
+		h.Kind = trade.TradeKind(kind)
+		h.Status = trade.TradeStatus(status)
+		if h.MatchedOn != "comment" {
+			h.Comment = ""
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trade hits: %w", err)
+	}
+	return hits, nil
+}
+
+// likePrefix returns an ILIKE pattern matching values that start with text, with the LIKE
+// wildcards in text escaped.
+func likePrefix(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+	return text + "%"
+}
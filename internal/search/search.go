@@ -0,0 +1,165 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// HitType tells what a search hit points at.
+type HitType string
+
+const (
+	HitTrade   HitType = "TRADE"
+	HitCompany HitType = "COMPANY"
+)
+
+// Query is a free-text search, optionally narrowed by structured trade criteria.
+//
+// Example (confirmed purchases from anything matching "shell rotterdam"):
+//
+//	search.Query{Text: "shell rotterdam", Types: []search.HitType{search.HitTrade},
+//	    TradeKind: trade.TradeKindPurchase, TradeStatuses: []trade.TradeStatus{trade.TradeStatusConfirmed}}
+type Query struct {
+	Text  string
+	Types []HitType // Empty means trades and companies
+
+	// Structured criteria; they narrow trade hits only.
+	TradeKind      trade.TradeKind
+	TradeStatuses  []trade.TradeStatus
+	CounterpartyID string
+
+	Limit int // 0 means DefaultLimit
+}
+
+// wants reports whether the query asks for hits of type t.
+func (q Query) wants(t HitType) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, want := range q.Types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Hit is one search result. Rank is the relevance (higher is better) used to order hits of
+// both types together; MatchedOn names what matched, e.g. "tradeNumber", "counterparty",
+// "comment" or "company".
+type Hit struct {
+	Type      HitType `json:"type"`
+	ID        string  `json:"id"`
+	Label     string  `json:"label"` // Deal number of a trade, reporting name of a company
+	MatchedOn string  `json:"matchedOn"`
+	Rank      float64 `json:"rank"`
+
+	// Trade hits only
+	Kind             trade.TradeKind   `json:"kind,omitempty"`
+	Status           trade.TradeStatus `json:"status,omitempty"`
+	CounterpartyID   string            `json:"counterpartyId,omitempty"`
+	CounterpartyName string            `json:"counterpartyName,omitempty"`
+	Comment          string            `json:"comment,omitempty"` // The matching status change reason
+}
+
+// Index runs searches against the stored trades and companies; *repository.RdsSearchRepository
+// implements it with Postgres full-text search.
+type Index interface {
+	SearchTrades(ctx context.Context, q Query, tsquery string) ([]Hit, error)
+	SearchCompanies(ctx context.Context, q Query, tsquery string) ([]Hit, error)
+}
+
+type Service struct {
+	index Index
+}
+
+func NewService(index Index) *Service {
+	return &Service{index: index}
+}
+
+// Search
+//
+// PURPOSE:
+//
+//	Finds trades and companies for free text typed by a user. Every word must match, and a
+//	word matches as a prefix, so partial input finds results while typing:
+//
+//	  "shel rott"     → company Shell Rotterdam B.V. and its trades
+//	  "P-2026-00"     → trades P-2026-0001 … P-2026-0099
+//	  "force majeure" → trades whose status was changed with that comment
+//
+//	Trades match on deal number, commodity, counterparty name and the comments (reasons)
+//	of their status changes; companies on their names, CoC number and city. Hits of both
+//	types are merged by relevance.
+//
+// EXAMPLE USAGE:
+//
+//	hits, err := searchService.Search(ctx, search.Query{Text: "shell", Limit: 10})
+//	// → [{Type: COMPANY, Label: "Shell"}, {Type: TRADE, Label: "S-2026-0042", CounterpartyName: "shell"}, ...]
+func (s *Service) Search(ctx context.Context, q Query) ([]Hit, error) {
+	if q.Limit == 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", MaxLimit, q.Limit)
+	}
+	tsquery := PrefixQuery(q.Text)
+	if tsquery == "" {
+		return nil, errors.New("search text must contain at least one letter or digit")
+	}
+
+	var hits []Hit
+	if q.wants(HitCompany) {
+		companies, err := s.index.SearchCompanies(ctx, q, tsquery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search companies: %w", err)
+		}
+		hits = append(hits, companies...)
+	}
+	if q.wants(HitTrade) {
+		trades, err := s.index.SearchTrades(ctx, q, tsquery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search trades: %w", err)
+		}
+		hits = append(hits, trades...)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Rank > hits[j].Rank })
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
+
+// PrefixQuery turns free text into a Postgres tsquery in which every word must match as a
+// prefix. Characters with a meaning in tsquery syntax are dropped; words are lower-cased like
+// the 'simple' text search configuration does.
+//
+// Example:
+//
+//	PrefixQuery("Shell  Rott!") // → "'shell':* & 'rott':*"
+func PrefixQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '.' && r != '_'
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.Trim(w, "-._")
+		if w != "" {
+			terms = append(terms, "'"+w+"':*")
+		}
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nholding/cso-book/internal/sweep/domain"
+)
+
+const (
+	defaultTrendDays = 30
+	maxTrendDays     = 366
+)
+
+// TrendSource provides the finding counts of past sweeps; *service.SweepService implements it.
+type TrendSource interface {
+	Trend(ctx context.Context, window time.Duration) (map[string][]domain.TrendPoint, error)
+}
+
+// TrendHandler serves the data health trend of the nightly validation sweep: per validator,
+// the number of findings of every sweep in the window.
+//
+//	GET /sweeps/trend?days=30
+//
+// Query parameters:
+//
+//	days - length of the window in days (default 30, max 366)
+//
+// Example response:
+//
+//	{
+//	  "period.gaps": [
+//	    {"SweepID": "01J...", "RunAt": "2026-03-01T02:00:00Z", "Validator": "period.gaps", "FindingCount": 2},
+//	    {"SweepID": "01J...", "RunAt": "2026-03-02T02:00:00Z", "Validator": "period.gaps", "FindingCount": 0}
+//	  ],
+//	  "trade.breakdowns": [...]
+//	}
+type TrendHandler struct {
+	source TrendSource
+}
+
+func NewTrendHandler(source TrendSource) *TrendHandler {
+	return &TrendHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *TrendHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /sweeps/trend", h)
+}
+
+func (h *TrendHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	days := defaultTrendDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxTrendDays {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("days must be between 1 and %d", maxTrendDays))
+			return
+		}
+		days = n
+	}
+
+	trend, err := h.source.Trend(r.Context(), time.Duration(days)*24*time.Hour)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, trend)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
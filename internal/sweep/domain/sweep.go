@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Validator is a single named check that is executed as part of a validation sweep.
+// Validators are deliberately tiny so that any domain can contribute one without the
+// sweep knowing about periods, trades or companies.
+type Validator interface {
+	Name() string                         // Stable name used for trend data, e.g. "period.hierarchy"
+	Validate(ctx context.Context) []error // One error per finding; nil/empty means healthy
+}
+
+type validatorFunc struct {
+	name string
+	fn   func(ctx context.Context) []error
+}
+
+func (v validatorFunc) Name() string { return v.name }
+
+func (v validatorFunc) Validate(ctx context.Context) []error { return v.fn(ctx) }
+
+// NewValidator wraps a plain function as a Validator.
+//
+// Example:
+//
+//	v := NewValidator("period.overlaps", func(ctx context.Context) []error {
+//	    return periodservice.ReportErrors(periodService.ValidateOverlaps())
+//	})
+func NewValidator(name string, fn func(ctx context.Context) []error) Validator {
+	return validatorFunc{name: name, fn: fn}
+}
+
+// Finding is a single problem reported by a validator during a sweep.
+type Finding struct {
+	Validator string    // Name of the validator that reported the finding
+	Message   string    // Human-readable description
+	FoundAt   time.Time // UTC timestamp at which the validator ran
+}
+
+// SweepReport is the persisted result of one validation sweep.
+//
+// Example:
+//
+//	SweepReport{
+//	    ID:         "01HFYEVZQYF5Y2ZYQJ2TFTKX8X",
+//	    StartedAt:  2026-03-01 02:00:00,
+//	    FinishedAt: 2026-03-01 02:00:03,
+//	    Validators: ["period.overlaps", "period.gaps", "period.hierarchy"],
+//	    Findings:   [{Validator: "period.gaps", Message: "Gap detected (MONTHLY): ..."}],
+//	}
+type SweepReport struct {
+	ID         string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Validators []string // Names of all validators that ran, including the ones without findings
+	Findings   []Finding
+}
+
+// Healthy reports whether the sweep produced no findings at all.
+func (r *SweepReport) Healthy() bool {
+	return len(r.Findings) == 0
+}
+
+// CountByValidator returns the number of findings per validator. Validators that ran
+// without findings are included with a count of 0 so trends show them recovering.
+func (r *SweepReport) CountByValidator() map[string]int {
+	counts := make(map[string]int, len(r.Validators))
+	for _, v := range r.Validators {
+		counts[v] = 0
+	}
+	for _, f := range r.Findings {
+		counts[f.Validator]++
+	}
+	return counts
+}
+
+// TrendPoint is the number of findings one validator produced in one sweep.
+// A series of TrendPoints per validator shows whether data health is improving.
+type TrendPoint struct {
+	SweepID      string
+	RunAt        time.Time
+	Validator    string
+	FindingCount int
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nholding/cso-book/internal/sweep/domain"
+)
+
+// InMemorySweepRepository is a SweepRepository that keeps the reports in memory, for local
+// development without RDS. Like RdsSweepRepository, trends count every validator that ran,
+// including the ones without findings. It is safe for concurrent use.
+//
+// Example:
+//
+//	sweepService := service.NewSweepService(repository.NewInMemorySweepRepository())
+type InMemorySweepRepository struct {
+	mu      sync.RWMutex
+	reports []domain.SweepReport
+}
+
+var _ SweepRepository = (*InMemorySweepRepository)(nil)
+
+func NewInMemorySweepRepository() *InMemorySweepRepository {
+	return &InMemorySweepRepository{}
+}
+
+// SaveReport stores a copy of the report.
+func (r *InMemorySweepRepository) SaveReport(_ context.Context, report *domain.SweepReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := *report
+	c.Validators = append([]string(nil), report.Validators...)
+	c.Findings = append([]domain.Finding(nil), report.Findings...)
+	r.reports = append(r.reports, c)
+	return nil
+}
+
+// GetTrend returns per-validator finding counts for all sweeps started at or after since.
+func (r *InMemorySweepRepository) GetTrend(_ context.Context, since time.Time) ([]domain.TrendPoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var trend []domain.TrendPoint
+	for _, report := range r.reports {
+		if report.StartedAt.Before(since) {
+			continue
+		}
+		for validator, count := range report.CountByValidator() {
+			trend = append(trend, domain.TrendPoint{
+				SweepID:      report.ID,
+				RunAt:        report.StartedAt,
+				Validator:    validator,
+				FindingCount: count,
+			})
+		}
+	}
+
+	sort.Slice(trend, func(i, j int) bool {
+		if !trend[i].RunAt.Equal(trend[j].RunAt) {
+			return trend[i].RunAt.Before(trend[j].RunAt)
+		}
+		return trend[i].Validator < trend[j].Validator
+	})
+	return trend, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/sweep/domain"
+)
+
+// SqliteSweepRepository stores sweep reports in a SQLite database opened with sqlitedb.Open,
+// in the same tables as RdsSweepRepository. Times are stored in UTC.
+//
+// Example:
+//
+//	db, err := sqlitedb.Open(ctx, "cso-book.db")
+//	sweepService := service.NewSweepService(repository.NewSqliteSweepRepository(db))
+type SqliteSweepRepository struct {
+	db *sql.DB
+}
+
+var _ SweepRepository = (*SqliteSweepRepository)(nil)
+
+func NewSqliteSweepRepository(db *sql.DB) *SqliteSweepRepository {
+	return &SqliteSweepRepository{db: db}
+}
+
+// SaveReport inserts the sweep, one row per validator that ran and every finding in one
+// transaction, like RdsSweepRepository.SaveReport.
+func (r *SqliteSweepRepository) SaveReport(ctx context.Context, report *domain.SweepReport) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO validation_sweeps (id, started_at, finished_at) VALUES (?,?,?)`,
+		report.ID, report.StartedAt.UTC(), report.FinishedAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("failed to insert sweep %s: %w", report.ID, err)
+	}
+
+	for validator, count := range report.CountByValidator() {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO validation_sweep_validators (sweep_id, validator, finding_count) VALUES (?,?,?)`,
+			report.ID, validator, count,
+		); err != nil {
+			return fmt.Errorf("failed to insert validator %s of sweep %s: %w", validator, report.ID, err)
+		}
+	}
+
+	for _, f := range report.Findings {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO validation_findings (sweep_id, validator, message, found_at) VALUES (?,?,?,?)`,
+			report.ID, f.Validator, f.Message, f.FoundAt.UTC(),
+		); err != nil {
+			return fmt.Errorf("failed to insert finding of sweep %s: %w", report.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sweep transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrend returns per-validator finding counts for all sweeps started at or after since.
+func (r *SqliteSweepRepository) GetTrend(ctx context.Context, since time.Time) ([]domain.TrendPoint, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.started_at, v.validator, v.finding_count
+		FROM validation_sweeps s
+		JOIN validation_sweep_validators v ON v.sweep_id = s.id
+		WHERE s.started_at >= ?
+		ORDER BY s.started_at, v.validator
+	`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sweep trend: %w", err)
+	}
+	defer rows.Close()
+
+	var trend []domain.TrendPoint
+	for rows.Next() {
+		var tp domain.TrendPoint
+		if err := rows.Scan(&tp.SweepID, &tp.RunAt, &tp.Validator, &tp.FindingCount); err != nil {
+			return nil, fmt.Errorf("failed to scan sweep trend row: %w", err)
+		}
+		trend = append(trend, tp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sweep trend rows: %w", err)
+	}
+
+	return trend, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/sqlitedb"
+	"github.com/nholding/cso-book/internal/sweep/domain"
+)
+
+func TestSqliteSweepRepositoryTrend(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitedb.Open(ctx, ":memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	repo := NewSqliteSweepRepository(db)
+
+	day := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	for i, findings := range []int{2, 0} {
+		started := day.AddDate(0, 0, i)
+		report := &domain.SweepReport{
+			ID:         started.Format("20060102"),
+			StartedAt:  started,
+			FinishedAt: started.Add(time.Minute),
+			Validators: []string{"period.gaps", "period.overlaps"},
+		}
+		for f := 0; f < findings; f++ {
+			report.Findings = append(report.Findings, domain.Finding{Validator: "period.gaps", Message: "gap", FoundAt: started})
+		}
+		if err := repo.SaveReport(ctx, report); err != nil {
+			t.Fatalf("SaveReport: %v", err)
+		}
+	}
+
+	trend, err := repo.GetTrend(ctx, day.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetTrend: %v", err)
+	}
+	want := []domain.TrendPoint{
+		{SweepID: "20260302", RunAt: day.AddDate(0, 0, 1), Validator: "period.gaps", FindingCount: 0},
+		{SweepID: "20260302", RunAt: day.AddDate(0, 0, 1), Validator: "period.overlaps", FindingCount: 0},
+	}
+	if len(trend) != len(want) {
+		t.Fatalf("GetTrend returned %v, want %v", trend, want)
+	}
+	for i := range want {
+		if trend[i].SweepID != want[i].SweepID || !trend[i].RunAt.Equal(want[i].RunAt) ||
+			trend[i].Validator != want[i].Validator || trend[i].FindingCount != want[i].FindingCount {
+			t.Errorf("trend[%d] = %+v, want %+v", i, trend[i], want[i])
+		}
+	}
+
+	all, err := repo.GetTrend(ctx, day)
+	if err != nil {
+		t.Fatalf("GetTrend: %v", err)
+	}
+	if len(all) != 4 || all[0].Validator != "period.gaps" || all[0].FindingCount != 2 {
+		t.Errorf("GetTrend since the first sweep = %+v, want 4 points starting with 2 gaps", all)
+	}
+}
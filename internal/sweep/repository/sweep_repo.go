@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/sweep/domain"
+)
+
+// SweepRepository persists validation sweep reports and exposes trend data over them.
+type SweepRepository interface {
+	// SaveReport persists a sweep and all its findings atomically.
+	SaveReport(ctx context.Context, report *domain.SweepReport) error
+
+	// GetTrend returns the number of findings per validator per sweep since the given time,
+	// ordered by run time.
+	GetTrend(ctx context.Context, since time.Time) ([]domain.TrendPoint, error)
+}
+
+type RdsSweepRepository struct {
+	db *sql.DB
+}
+
+func NewRdsSweepRepository(cfg *awsclient.Config) (*RdsSweepRepository, error) {
+	rdsClient, err := cfg.NewRDSClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsSweepRepository{db: rdsClient.Client}, nil
+}
+
+// SaveReport inserts the sweep into validation_sweeps, one row per validator that ran into
+// validation_sweep_validators (so clean runs show up as 0 in the trend), and every finding
+// into validation_findings.
+func (r *RdsSweepRepository) SaveReport(ctx context.Context, report *domain.SweepReport) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO validation_sweeps (id, started_at, finished_at) VALUES ($1,$2,$3)`,
+		report.ID, report.StartedAt, report.FinishedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert sweep %s: %w", report.ID, err)
+	}
+
+	for validator, count := range report.CountByValidator() {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO validation_sweep_validators (sweep_id, validator, finding_count) VALUES ($1,$2,$3)`,
+			report.ID, validator, count,
+		); err != nil {
+			return fmt.Errorf("failed to insert validator %s of sweep %s: %w", validator, report.ID, err)
+		}
+	}
+
+	for _, f := range report.Findings {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO validation_findings (sweep_id, validator, message, found_at) VALUES ($1,$2,$3,$4)`,
+			report.ID, f.Validator, f.Message, f.FoundAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert finding of sweep %s: %w", report.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sweep transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrend returns per-validator finding counts for all sweeps started at or after since.
+func (r *RdsSweepRepository) GetTrend(ctx context.Context, since time.Time) ([]domain.TrendPoint, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.started_at, v.validator, v.finding_count
+		FROM validation_sweeps s
+		JOIN validation_sweep_validators v ON v.sweep_id = s.id
+		WHERE s.started_at >= $1
+		ORDER BY s.started_at, v.validator
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sweep trend: %w", err)
+	}
+	defer rows.Close()
+
+	var trend []domain.TrendPoint
+	for rows.Next() {
+		var tp domain.TrendPoint
+		if err := rows.Scan(&tp.SweepID, &tp.RunAt, &tp.Validator, &tp.FindingCount); err != nil {
+			return nil, fmt.Errorf("failed to scan sweep trend row: %w", err)
+		}
+		trend = append(trend, tp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sweep trend rows: %w", err)
+	}
+
+	return trend, nil
+}
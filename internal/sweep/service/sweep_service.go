@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/sweep/domain"
+	"github.com/nholding/cso-book/internal/sweep/repository"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+type SweepService struct {
+	repo       repository.SweepRepository
+	validators []domain.Validator
+}
+
+func NewSweepService(repo repository.SweepRepository, validators ...domain.Validator) *SweepService {
+	return &SweepService{
+		repo:       repo,
+		validators: validators,
+	}
+}
+
+// Register adds validators to the sweep. Typically called once during wiring:
+//
+//	sweepService.Register(
+//	    domain.NewValidator("period.overlaps", func(ctx context.Context) []error { return periodservice.ReportErrors(periodService.ValidateOverlaps()) }),
+//	    domain.NewValidator("period.gaps", func(ctx context.Context) []error { return periodservice.ReportErrors(periodService.ValidateGaps()) }),
+//	    domain.NewValidator("period.hierarchy", func(ctx context.Context) []error { return periodservice.ReportErrors(periodService.ValidateHierarchy()) }),
+//	    domain.NewValidator("period.fiscal_coverage", func(ctx context.Context) []error { return periodservice.ReportErrors(periodService.ValidateFiscalCoverage()) }),
+//	    domain.NewValidator("period.data_quality", func(ctx context.Context) []error { return periodservice.ReportErrors(periodService.ValidateDataQuality()) }),
+//	)
+func (s *SweepService) Register(validators ...domain.Validator) {
+	s.validators = append(s.validators, validators...)
+}
+
+// Run
+//
+// PURPOSE:
+//
+//	Executes ALL registered validators, collects their findings into one SweepReport
+//	and persists it. Unlike startup validation this never fails fast: every validator
+//	runs, so the report always reflects the complete health of the data.
+//
+// EXAMPLE USAGE (nightly at 02:00 UTC):
+//
+//	go scheduler.RunDaily(ctx, 2, 0, time.UTC, func(ctx context.Context) error {
+//	    _, err := sweepService.Run(ctx)
+//	    return err
+//	}, func(err error) { log.Println("validation sweep failed:", err) })
+//
+// Returns the report even when persisting it fails, so callers can still log findings.
+func (s *SweepService) Run(ctx context.Context) (*domain.SweepReport, error) {
+	report := &domain.SweepReport{
+		ID:        utils.GenerateStableID(),
+		StartedAt: time.Now().UTC(),
+	}
+
+	for _, v := range s.validators {
+		if err := ctx.Err(); err != nil {
+			return report, fmt.Errorf("validation sweep %s aborted: %w", report.ID, err)
+		}
+
+		ranAt := time.Now().UTC()
+		report.Validators = append(report.Validators, v.Name())
+
+		for _, err := range v.Validate(ctx) {
+			report.Findings = append(report.Findings, domain.Finding{
+				Validator: v.Name(),
+				Message:   err.Error(),
+				FoundAt:   ranAt,
+			})
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+
+	if err := s.repo.SaveReport(ctx, report); err != nil {
+		return report, fmt.Errorf("failed to persist validation sweep %s: %w", report.ID, err)
+	}
+
+	return report, nil
+}
+
+// Trend returns the per-validator finding counts of all sweeps in the last `window`,
+// grouped by validator name and ordered chronologically within each series.
+//
+// Example:
+//
+//	trend, _ := sweepService.Trend(ctx, 30*24*time.Hour)
+//	for _, tp := range trend["period.gaps"] {
+//	    fmt.Println(tp.RunAt.Format("2006-01-02"), tp.FindingCount)
+//	}
+func (s *SweepService) Trend(ctx context.Context, window time.Duration) (map[string][]domain.TrendPoint, error) {
+	points, err := s.repo.GetTrend(ctx, time.Now().UTC().Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load validation trend: %w", err)
+	}
+
+	series := make(map[string][]domain.TrendPoint)
+	for _, tp := range points {
+		series[tp.Validator] = append(series[tp.Validator], tp)
+	}
+
+	return series, nil
+}
@@ -0,0 +1,173 @@
+package trade
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// volumeTolerance is the difference allowed between the volume a trade's header implies and
+// the sum of its breakdowns: one kilogram, the precision of the total allocation modes.
+const volumeTolerance = 0.001
+
+// AllocationMode determines what the header volume (TradeBase.VolumeMT) of a trade means and
+// how it is allocated to the months the trade delivers in.
+//
+// PER_MONTH_FIXED:     VolumeMT is delivered in every month (default). A 6-month trade of
+//
+//	10,000 MT delivers 60,000 MT. With BreakdownProRata, partially covered months
+//	receive their day-weighted share.
+//
+// TOTAL_SPREAD_EVENLY: VolumeMT is the total of the trade, split into equal monthly slices.
+//
+//	A 6-month trade of 60,000 MT delivers 10,000 MT per month.
+//
+// DAY_WEIGHTED:        VolumeMT is the total of the trade, split by delivery days per month.
+//
+//	Delivery 15 March – 30 April of 4,700 MT → March 1,700 MT (17 days), April 3,000 MT.
+//
+// For both total modes the monthly volumes add up to exactly VolumeMT: slices are rounded to
+// the kilogram and the last month absorbs the rounding difference.
+const (
+	AllocationPerMonthFixed     AllocationMode = "PER_MONTH_FIXED"
+	AllocationTotalSpreadEvenly AllocationMode = "TOTAL_SPREAD_EVENLY"
+	AllocationDayWeighted       AllocationMode = "DAY_WEIGHTED"
+)
+
+type AllocationMode string
+
+// IsValid reports whether m is a known allocation mode; empty counts as PER_MONTH_FIXED.
+func (m AllocationMode) IsValid() bool {
+	switch m {
+	case "", AllocationPerMonthFixed, AllocationTotalSpreadEvenly, AllocationDayWeighted:
+		return true
+	}
+	return false
+}
+
+// Allocation returns the allocation mode of the trade, defaulting to PER_MONTH_FIXED.
+func (tb *TradeBase) Allocation() AllocationMode {
+	if tb.AllocationMode == "" {
+		return AllocationPerMonthFixed
+	}
+	return tb.AllocationMode
+}
+
+// TotalVolumeMT returns the volume the trade delivers over its whole life, i.e. the sum of the
+// volumes of its breakdowns.
+func TotalVolumeMT(breakdowns []TradeBreakdown) float64 {
+	var total float64
+	for _, bd := range breakdowns {
+		total += bd.VolumeMT
+	}
+	return total
+}
+
+// ExpectedVolumeMT returns the volume the header of the trade implies for its live months:
+//
+//   - PER_MONTH_FIXED: VolumeMT for every month, weighted by MonthFraction for pro-rata months
+//   - TOTAL_SPREAD_EVENLY, DAY_WEIGHTED: VolumeMT
+//
+// Cancelled months (CancelledPeriodIDs) no longer count, and declared months (DeclaredVolumes)
+// count with their declared volume instead of their contractual share.
+//
+// Example (10,000 MT per month Jan–Jun 2026, MAR cancelled, APR declared at 9,500 MT):
+//
+//	ExpectedVolumeMT(tb, ps) // → 49,500
+func ExpectedVolumeMT(tb TradeBase, ps *period.PeriodStore) float64 {
+	// The contractual volume per month, before declarations and cancellations
+	contract := tb
+	contract.DeclaredVolumes = nil
+	contract.CancelledPeriodIDs = nil
+	months := CreateTradeBreakdowns(contract, ps, tb.AuditInfo.CreatedBy)
+
+	expected := tb.VolumeMT
+	if tb.Allocation() == AllocationPerMonthFixed {
+		expected = 0
+		for _, bd := range months {
+			if !bd.IsFee() {
+				expected += tb.VolumeMT * bd.MonthFraction
+			}
+		}
+	}
+
+	for _, bd := range months {
+		if bd.IsFee() {
+			continue
+		}
+		if slices.Contains(tb.CancelledPeriodIDs, bd.PeriodID) {
+			expected -= bd.VolumeMT
+		} else if declared, ok := tb.DeclaredVolumes[bd.PeriodID]; ok {
+			expected += declared - bd.VolumeMT
+		}
+	}
+	return expected
+}
+
+// CheckBreakdownVolume compares the live volume of the breakdowns of ONE trade with the volume
+// its header implies (see ExpectedVolumeMT). Returns a human-readable message on a mismatch,
+// e.g. when a month's breakdown went missing or a rebuild after an amendment was not stored.
+//
+// Example:
+//
+//	errs := CheckBreakdownVolume(tb, breakdowns, ps)
+//	// → ["trade T1 breakdowns add up to 50000.000000 MT, its terms imply 60000.000000 MT (PER_MONTH_FIXED)"]
+func CheckBreakdownVolume(tb TradeBase, breakdowns []TradeBreakdown, ps *period.PeriodStore) []string {
+	booked := TotalVolumeMT(LiveBreakdowns(breakdowns))
+	expected := ExpectedVolumeMT(tb, ps)
+	if math.Abs(booked-expected) > volumeTolerance {
+		return []string{fmt.Sprintf("trade %s breakdowns add up to %f MT, its terms imply %f MT (%s)", tb.ID, booked, expected, tb.Allocation())}
+	}
+	return nil
+}
+
+// allocateTotal spreads the volume of a trade over its breakdowns in one of the total modes:
+// evenly, or weighted by the delivery days of each breakdown. VolumeMT, Quantity and
+// TotalAmount of every breakdown are overwritten; the booked quantity is spread with the same
+// weights, so it reconciles to the booked quantity as well.
+func allocateTotal(breakdowns []TradeBreakdown, mode AllocationMode, totalMT, totalQuantity float64) {
+	weights := make([]float64, len(breakdowns))
+	for i, bd := range breakdowns {
+		weights[i] = 1
+		if mode == AllocationDayWeighted {
+			weights[i] = float64(utils.DaysInRange(bd.StartDate, bd.EndDate))
+		}
+	}
+
+	volumes := spread(totalMT, weights)
+	quantities := spread(totalQuantity, weights)
+	for i := range breakdowns {
+		breakdowns[i].VolumeMT = volumes[i]
+		breakdowns[i].Quantity = quantities[i]
+		breakdowns[i].TotalAmount = volumes[i] * breakdowns[i].PricePerMT
+	}
+}
+
+// spread splits total by weight, rounded to three decimals (kilograms for MT). The last share
+// absorbs the rounding difference, so the shares add up to total.
+func spread(total float64, weights []float64) []float64 {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+
+	shares := make([]float64, len(weights))
+	var allocated float64
+	for i, w := range weights {
+		if i == len(weights)-1 {
+			shares[i] = roundKg(total - allocated)
+			break
+		}
+		shares[i] = roundKg(total * w / sum)
+		allocated += shares[i]
+	}
+	return shares
+}
+
+// roundKg rounds a volume in metric tonnes to whole kilograms.
+func roundKg(mt float64) float64 {
+	return math.Round(mt*1000) / 1000
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+const (
+	defaultBlotterLimit = 100
+	maxBlotterLimit     = 1000
+)
+
+// TradeListSource lists and counts trades (see TradeService.ListTrades and CountTrades).
+type TradeListSource interface {
+	ListTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.Trade, error)
+	CountTrades(ctx context.Context, filter repository.TradeFilter) (int, error)
+}
+
+// BlotterHandler serves one page of the trade blotter.
+//
+//	GET /trades?status=CONFIRMED,PENDING&period=2026-Q1&sort=createdAt&order=desc&limit=50&offset=0
+//
+// Query parameters (all optional):
+//
+//	kind          - PURCHASE, SALE, SWAP or STORAGE
+//	status        - comma-separated statuses
+//	counterparty  - counterparty company ID
+//	period        - trades delivering in any part of this period, e.g. 2026-Q1 or 2026-MAR
+//	product       - product ID
+//	location      - location ID
+//	trader        - user who booked the trade
+//	createdFrom   - booked on or after this date (YYYY-MM-DD)
+//	createdTo     - booked on or before this date (YYYY-MM-DD)
+//	sort          - createdAt (default), tradeNumber, counterparty, status, deliveryStart, volume or price
+//	order         - asc (default) or desc
+//	limit, offset - page size (default 100, max 1000) and start
+//
+// Example response:
+//
+//	{
+//	  "trades": [
+//	    {"id": "01J...B", "tradeNumber": "P-2026-0001", "kind": "PURCHASE", "counterpartyId": "C42",
+//	     "status": "CONFIRMED", "startPeriodId": "2026-JAN", "endPeriodId": "2026-MAR",
+//	     "volumeMT": 10000, "pricePerMT": 3.5, "currency": "USD", "createdBy": "trader@internal.local", ...}
+//	  ],
+//	  "total": 137, "limit": 50, "offset": 0
+//	}
+type BlotterHandler struct {
+	source TradeListSource
+}
+
+func NewBlotterHandler(source TradeListSource) *BlotterHandler {
+	return &BlotterHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *BlotterHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /trades", h)
+}
+
+// BlotterRow is one trade on the blotter.
+type BlotterRow struct {
+	ID             string            `json:"id"`
+	TradeNumber    string            `json:"tradeNumber"`
+	Kind           trade.TradeKind   `json:"kind"`
+	CounterpartyID string            `json:"counterpartyId"`
+	Status         trade.TradeStatus `json:"status"`
+	StartPeriodID  string            `json:"startPeriodId,omitempty"`
+	EndPeriodID    string            `json:"endPeriodId,omitempty"`
+	DeliveryStart  *time.Time        `json:"deliveryStart,omitempty"`
+	DeliveryEnd    *time.Time        `json:"deliveryEnd,omitempty"`
+	VolumeMT       float64           `json:"volumeMT"`
+	PricePerMT     float64           `json:"pricePerMT"`
+	Currency       string            `json:"currency"`
+	ProductID      string            `json:"productId,omitempty"`
+	LocationID     string            `json:"locationId,omitempty"`
+	CreatedBy      string            `json:"createdBy"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+func (h *BlotterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseBlotterFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	trades, err := h.source.ListTrades(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	total, err := h.source.CountTrades(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	rows := make([]BlotterRow, 0, len(trades))
+	for _, t := range trades {
+		tb := t.Base()
+		rows = append(rows, BlotterRow{
+			ID:             tb.ID,
+			TradeNumber:    tb.TradeNumber,
+			Kind:           t.Kind(),
+			CounterpartyID: t.CounterpartyID(),
+			Status:         tb.Status,
+			StartPeriodID:  tb.PeriodRange.StartPeriodID,
+			EndPeriodID:    tb.PeriodRange.EndPeriodID,
+			DeliveryStart:  tb.DeliveryStart,
+			DeliveryEnd:    tb.DeliveryEnd,
+			VolumeMT:       tb.VolumeMT,
+			PricePerMT:     tb.PricePerMT,
+			Currency:       tb.Currency,
+			ProductID:      tb.ProductID,
+			LocationID:     tb.LocationID,
+			CreatedBy:      tb.AuditInfo.CreatedBy,
+			CreatedAt:      tb.AuditInfo.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"trades": rows, "total": total, "limit": filter.Limit, "offset": filter.Offset})
+}
+
+// parseBlotterFilter reads the query parameters of a blotter request.
+func parseBlotterFilter(r *http.Request) (repository.TradeFilter, error) {
+	q := r.URL.Query()
+	filter := repository.TradeFilter{
+		Kind:           trade.TradeKind(q.Get("kind")),
+		CounterpartyID: q.Get("counterparty"),
+		PeriodID:       q.Get("period"),
+		ProductID:      q.Get("product"),
+		LocationID:     q.Get("location"),
+		CreatedBy:      q.Get("trader"),
+		Sort:           repository.TradeSort(q.Get("sort")),
+		Limit:          defaultBlotterLimit,
+	}
+
+	if v := q.Get("status"); v != "" {
+		for _, st := range strings.Split(v, ",") {
+			filter.Statuses = append(filter.Statuses, trade.TradeStatus(strings.TrimSpace(st)))
+		}
+	}
+	if v := q.Get("createdFrom"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return filter, fmt.Errorf("createdFrom must be a date (YYYY-MM-DD): %w", err)
+		}
+		filter.CreatedFrom = d
+	}
+	if v := q.Get("createdTo"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return filter, fmt.Errorf("createdTo must be a date (YYYY-MM-DD): %w", err)
+		}
+		filter.CreatedTo = d.AddDate(0, 0, 1)
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, fmt.Errorf("order must be asc or desc")
+	}
+	switch filter.Sort {
+	case "", repository.TradeSortCreatedAt, repository.TradeSortTradeNumber, repository.TradeSortCounterparty,
+		repository.TradeSortStatus, repository.TradeSortDeliveryStart, repository.TradeSortVolume, repository.TradeSortPrice:
+	default:
+		return filter, fmt.Errorf("cannot sort trades by %q", filter.Sort)
+	}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxBlotterLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxBlotterLimit)
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("offset must not be negative")
+		}
+		filter.Offset = n
+	}
+	return filter, nil
+}
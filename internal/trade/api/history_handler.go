@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// TradeHistorySource provides the version history of a trade (see TradeService.GetTradeHistory).
+type TradeHistorySource interface {
+	GetTradeHistory(ctx context.Context, tradeID string) ([]trade.TradeVersion, error)
+}
+
+// HistoryHandler serves the version history of a trade for back-office dispute resolution.
+//
+//	GET /trades/{id}/history
+//
+// Any version's ID may be given. Example response:
+//
+//	{
+//	  "tradeId": "01J...B",
+//	  "versions": [
+//	    {"trade": {"id": "01J...A", "status": "SUPERSEDED", "pricePerMT": 3.5, ...}},
+//	    {"trade": {"id": "01J...B", "status": "CONFIRMED", "pricePerMT": 3.6, ...},
+//	     "changes": [{"field": "pricePerMT", "old": "3.5", "new": "3.6"}]}
+//	  ]
+//	}
+type HistoryHandler struct {
+	source TradeHistorySource
+}
+
+func NewHistoryHandler(source TradeHistorySource) *HistoryHandler {
+	return &HistoryHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *HistoryHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /trades/{id}/history", h)
+}
+
+func (h *HistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tradeID := r.PathValue("id")
+	if tradeID == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("trade id is required"))
+		return
+	}
+
+	versions, err := h.source.GetTradeHistory(r.Context(), tradeID)
+	if errors.Is(err, dberr.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to load history of trade %s: %w", tradeID, err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"tradeId": tradeID, "versions": versions})
+}
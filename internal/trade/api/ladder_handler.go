@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+const (
+	defaultLadderMonths = 12
+	maxLadderMonths     = 120
+)
+
+// CounterpartyPositionSource provides the position data of one counterparty: the monthly
+// breakdowns of all live purchases from and sales to that counterparty;
+// *service.TradeService implements it.
+type CounterpartyPositionSource interface {
+	CounterpartyBreakdowns(ctx context.Context, counterpartyID string) (purchases, sales []trade.TradeBreakdown, err error)
+}
+
+// LadderHandler serves the month-by-month volume ladder of a counterparty.
+//
+//	GET /counterparties/{id}/ladder?from=2026-JAN&months=12
+//
+// Query parameters (both optional):
+//
+//	from   - first month of the horizon (default: the current month)
+//	months - number of months in the horizon (default 12, max 120)
+//
+// Example response:
+//
+//	{
+//	  "counterpartyId": "C42",
+//	  "rungs": [
+//	    {"periodId": "2026-JAN", "purchasedMT": 10000, "soldMT": 4000, "netMT": 6000},
+//	    ...
+//	  ],
+//	  "totalPurchasedMT": 120000, "totalSoldMT": 48000, "totalNetMT": 72000
+//	}
+type LadderHandler struct {
+	source  CounterpartyPositionSource
+	periods *period.PeriodStore
+	now     func() time.Time
+}
+
+func NewLadderHandler(source CounterpartyPositionSource, periods *period.PeriodStore) *LadderHandler {
+	return &LadderHandler{
+		source:  source,
+		periods: periods,
+		now:     time.Now,
+	}
+}
+
+// Register mounts the handler on mux.
+func (h *LadderHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /counterparties/{id}/ladder", h)
+}
+
+func (h *LadderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	counterpartyID := r.PathValue("id")
+	if counterpartyID == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("counterparty id is required"))
+		return
+	}
+
+	months := defaultLadderMonths
+	if v := r.URL.Query().Get("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLadderMonths {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("months must be between 1 and %d", maxLadderMonths))
+			return
+		}
+		months = n
+	}
+
+	from := r.URL.Query().Get("from")
+	if from == "" {
+		current := h.currentMonth()
+		if current == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("no period covers the current month"))
+			return
+		}
+		from = current.ID
+	}
+
+	horizon, err := trade.LadderHorizon(h.periods, from, months)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	purchases, sales, err := h.source.CounterpartyBreakdowns(r.Context(), counterpartyID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to load positions of counterparty %s: %w", counterpartyID, err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, trade.BuildVolumeLadder(counterpartyID, horizon, purchases, sales))
+}
+
+// currentMonth returns the month containing now.
+func (h *LadderHandler) currentMonth() *period.Period {
+	now := h.now().UTC()
+	for _, m := range h.periods.Months() {
+		if !now.Before(m.StartDate) && !now.After(m.EndDate) {
+			return m
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+const maxStatusChangeLimit = 1000
+
+// StatusChangeSource queries the status history across trades (see TradeService.ListStatusChanges).
+type StatusChangeSource interface {
+	ListStatusChanges(ctx context.Context, filter repository.StatusChangeFilter) ([]trade.StatusChange, error)
+}
+
+// StatusChangeHandler serves the status history of the book, newest first, e.g. every trade
+// cancelled last month with the reason it was cancelled.
+//
+//	GET /trades/status-changes?status=CANCELLED&from=2026-03-01&to=2026-03-31
+//
+// Query parameters (all optional):
+//
+//	trade         - trade ID
+//	tradeNumber   - all versions of the deal with this number
+//	kind          - PURCHASE, SALE, SWAP or STORAGE
+//	counterparty  - counterparty company ID
+//	oldStatus     - status the trade left
+//	status        - status the trade entered
+//	user          - user who made the change
+//	reason        - text the reason contains
+//	from, to      - changed on or after / on or before these dates (YYYY-MM-DD)
+//	transitions   - true leaves out history notes without a status change
+//	limit         - maximum number of entries (max 1000)
+//
+// Example response:
+//
+//	{
+//	  "changes": [
+//	    {"tradeId": "01J...B", "tradeNumber": "P-2026-0012", "kind": "PURCHASE", "counterpartyId": "C42",
+//	     "oldStatus": "CONFIRMED", "newStatus": "CANCELLED", "changedAt": "2026-03-14T09:12:00Z",
+//	     "changedBy": "user@internal.local", "reason": "vessel unavailable"}
+//	  ]
+//	}
+type StatusChangeHandler struct {
+	source StatusChangeSource
+}
+
+func NewStatusChangeHandler(source StatusChangeSource) *StatusChangeHandler {
+	return &StatusChangeHandler{source: source}
+}
+
+// Register mounts the handler on mux.
+func (h *StatusChangeHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /trades/status-changes", h)
+}
+
+func (h *StatusChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStatusChangeFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	changes, err := h.source.ListStatusChanges(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if changes == nil {
+		changes = []trade.StatusChange{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"changes": changes})
+}
+
+// parseStatusChangeFilter reads the query parameters of a status history request.
+func parseStatusChangeFilter(r *http.Request) (repository.StatusChangeFilter, error) {
+	q := r.URL.Query()
+	filter := repository.StatusChangeFilter{
+		TradeID:        q.Get("trade"),
+		TradeNumber:    q.Get("tradeNumber"),
+		Kind:           trade.TradeKind(q.Get("kind")),
+		CounterpartyID: q.Get("counterparty"),
+		OldStatus:      trade.TradeStatus(q.Get("oldStatus")),
+		NewStatus:      trade.TradeStatus(q.Get("status")),
+		ChangedBy:      q.Get("user"),
+		Reason:         q.Get("reason"),
+	}
+
+	if v := q.Get("from"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return filter, fmt.Errorf("from must be a date (YYYY-MM-DD): %w", err)
+		}
+		filter.ChangedFrom = d
+	}
+	if v := q.Get("to"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return filter, fmt.Errorf("to must be a date (YYYY-MM-DD): %w", err)
+		}
+		filter.ChangedTo = d.AddDate(0, 0, 1)
+	}
+	if v := q.Get("transitions"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("transitions must be true or false")
+		}
+		filter.TransitionsOnly = b
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxStatusChangeLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxStatusChangeLimit)
+		}
+		filter.Limit = n
+	}
+	return filter, nil
+}
@@ -0,0 +1,21 @@
+package trade
+
+import "time"
+
+// BreakdownArchive records one month whose breakdowns were moved out of the hot
+// trade_breakdowns table into cold storage (an S3 object), e.g. years after the month was
+// HARD_CLOSED. The breakdowns themselves are unchanged; only where they are stored changes.
+//
+// Example:
+//
+//	BreakdownArchive{PeriodID: "2019-JAN", Key: "archive/trade_breakdowns/2019-JAN.jsonl.gz", RowCount: 412, ...}
+type BreakdownArchive struct {
+	PeriodID   string
+	Key        string   // S3 object key
+	VersionID  string   // S3 object version; empty on unversioned buckets
+	RowCount   int      // Breakdowns in the archive
+	SHA256     string   // Checksum of the S3 object
+	TradeIDs   []string // Trades with breakdowns in the month
+	ArchivedBy string
+	ArchivedAt time.Time
+}
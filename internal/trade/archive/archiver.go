@@ -0,0 +1,291 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	periodrepo "github.com/nholding/cso-book/internal/period/repository"
+	"github.com/nholding/cso-book/internal/platform/scheduler"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// DefaultPrefix is the S3 key prefix of the breakdown archives.
+const DefaultPrefix = "archive/trade_breakdowns/"
+
+// ObjectStore is the part of S3 that archiving needs; *awsclient.S3Client implements it.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte, contentType string) (string, error)
+	GetObject(ctx context.Context, key, versionID string) ([]byte, error)
+}
+
+// Archiver moves the breakdowns of old, HARD_CLOSED months from RDS into S3 and reads them back.
+//
+// PURPOSE:
+//
+//	HARD_CLOSED months are final (see period.PeriodStatus), so years later their breakdowns are
+//	only read for audits. Archiving keeps the hot trade_breakdowns table (and its indexes) small:
+//	each month becomes one gzipped JSON Lines object in S3, recorded in the breakdown_archives
+//	index, after which its rows are deleted from RDS. Rollups stay in RDS, so quarterly and
+//	yearly reporting is unaffected.
+//
+//	Archived breakdowns are no longer returned by BreakdownRepository; use RetrievePeriod and
+//	RetrieveTradeBreakdowns instead (e.g. for CheckRollupConsistency on an archived trade).
+//
+// EXAMPLE USAGE:
+//
+//	archiver := archive.NewArchiver(periodRepo, breakdownRepo, archiveRepo, s3Client, archive.DefaultPrefix)
+//	go scheduler.RunDaily(ctx, 3, 0, time.UTC, archiver.Job(7, audit.SystemActor), func(err error) {
+//	    log.Println("archival failed:", err)
+//	})
+//	...
+//	breakdowns, err := archiver.RetrieveTradeBreakdowns(ctx, "T1") // hot + archived months
+type Archiver struct {
+	periods    periodrepo.PeriodRepository
+	breakdowns repository.BreakdownRepository
+	archives   repository.BreakdownArchiveRepository
+	store      ObjectStore
+	prefix     string
+	now        func() time.Time
+}
+
+func NewArchiver(
+	periods periodrepo.PeriodRepository,
+	breakdowns repository.BreakdownRepository,
+	archives repository.BreakdownArchiveRepository,
+	store ObjectStore,
+	prefix string,
+) *Archiver {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &Archiver{
+		periods:    periods,
+		breakdowns: breakdowns,
+		archives:   archives,
+		store:      store,
+		prefix:     prefix,
+		now:        time.Now,
+	}
+}
+
+// Job returns ArchiveClosedPeriods as a scheduler job.
+func (a *Archiver) Job(olderThanYears int, actor string) scheduler.Job {
+	return func(ctx context.Context) error {
+		_, err := a.ArchiveClosedPeriods(ctx, olderThanYears, actor)
+		return err
+	}
+}
+
+// ArchiveClosedPeriods archives every HARD_CLOSED month that ended more than olderThanYears
+// years ago and is not archived yet, oldest first. It stops at the first failure; months
+// archived before it stay archived, and the next run continues where this one stopped.
+//
+// Example (on 2026-10-16, with 7 years):
+//
+//	archived, err := archiver.ArchiveClosedPeriods(ctx, 7, audit.SystemActor)
+//	// → every HARD_CLOSED month up to and including 2019-SEP
+func (a *Archiver) ArchiveClosedPeriods(ctx context.Context, olderThanYears int, actor string) ([]trade.BreakdownArchive, error) {
+	if olderThanYears < 1 {
+		return nil, fmt.Errorf("archival age must be at least 1 year, got %d", olderThanYears)
+	}
+	cutoff := a.now().AddDate(-olderThanYears, 0, 0)
+
+	months, err := a.periods.GetPeriodsByGranularity(ctx, period.MonthlyPeriod)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].StartDate.Before(months[j].StartDate) })
+
+	existing, err := a.archives.ListArchives(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		done[e.PeriodID] = true
+	}
+
+	var archived []trade.BreakdownArchive
+	for _, m := range months {
+		if done[m.ID] || !m.IsHardClosed() || !m.EndDate.Before(cutoff) {
+			continue
+		}
+		ar, err := a.ArchivePeriod(ctx, m, actor)
+		if err != nil {
+			return archived, err
+		}
+		archived = append(archived, *ar)
+	}
+
+	return archived, nil
+}
+
+// ArchivePeriod uploads the breakdowns of one HARD_CLOSED month to S3, then deletes them from
+// RDS. When the delete fails the upload is left behind and simply overwritten on the next run.
+func (a *Archiver) ArchivePeriod(ctx context.Context, month *period.Period, actor string) (*trade.BreakdownArchive, error) {
+	if !month.IsHardClosed() {
+		return nil, fmt.Errorf("period %s is %s; only HARD_CLOSED periods can be archived", month.ID, month.EffectiveStatus())
+	}
+
+	breakdowns, err := a.breakdowns.GetBreakdownsByPeriod(ctx, month.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := encodeBreakdowns(breakdowns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode breakdowns of %s: %w", month.ID, err)
+	}
+
+	key := a.prefix + month.ID + ".jsonl.gz"
+	versionID, err := a.store.PutObject(ctx, key, data, "application/gzip")
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	ar := trade.BreakdownArchive{
+		PeriodID:   month.ID,
+		Key:        key,
+		VersionID:  versionID,
+		RowCount:   len(breakdowns),
+		SHA256:     hex.EncodeToString(sum[:]),
+		TradeIDs:   tradeIDs(breakdowns),
+		ArchivedBy: actor,
+		ArchivedAt: a.now().UTC(),
+	}
+	if err := a.archives.ArchiveBreakdowns(ctx, ar); err != nil {
+		return nil, err
+	}
+
+	return &ar, nil
+}
+
+// RetrievePeriod returns the breakdowns of a month from wherever they are stored: the archive
+// for archived months, RDS otherwise.
+func (a *Archiver) RetrievePeriod(ctx context.Context, periodID string) ([]trade.TradeBreakdown, error) {
+	ar, err := a.archives.GetArchive(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if ar == nil {
+		return a.breakdowns.GetBreakdownsByPeriod(ctx, periodID)
+	}
+
+	return a.read(ctx, *ar)
+}
+
+// RetrieveTradeBreakdowns returns ALL breakdowns of a trade, hot and archived, by delivery start.
+func (a *Archiver) RetrieveTradeBreakdowns(ctx context.Context, tradeID string) ([]trade.TradeBreakdown, error) {
+	breakdowns, err := a.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	archives, err := a.archives.ListArchivesForTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ar := range archives {
+		archived, err := a.read(ctx, ar)
+		if err != nil {
+			return nil, err
+		}
+		for _, bd := range archived {
+			if bd.ParentTradeID == tradeID {
+				breakdowns = append(breakdowns, bd)
+			}
+		}
+	}
+
+	sort.SliceStable(breakdowns, func(i, j int) bool {
+		if !breakdowns[i].StartDate.Equal(breakdowns[j].StartDate) {
+			return breakdowns[i].StartDate.Before(breakdowns[j].StartDate)
+		}
+		return breakdowns[i].ID < breakdowns[j].ID
+	})
+
+	return breakdowns, nil
+}
+
+// read downloads and verifies one archive.
+func (a *Archiver) read(ctx context.Context, ar trade.BreakdownArchive) ([]trade.TradeBreakdown, error) {
+	data, err := a.store.GetObject(ctx, ar.Key, ar.VersionID)
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != ar.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch for archive %s of period %s", ar.Key, ar.PeriodID)
+	}
+
+	breakdowns, err := decodeBreakdowns(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode archive %s of period %s: %w", ar.Key, ar.PeriodID, err)
+	}
+	if len(breakdowns) != ar.RowCount {
+		return nil, fmt.Errorf("archive %s of period %s holds %d breakdowns, expected %d", ar.Key, ar.PeriodID, len(breakdowns), ar.RowCount)
+	}
+
+	return breakdowns, nil
+}
+
+// encodeBreakdowns writes breakdowns as gzipped JSON Lines.
+func encodeBreakdowns(breakdowns []trade.TradeBreakdown) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, bd := range breakdowns {
+		if err := enc.Encode(bd); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeBreakdowns(data []byte) ([]trade.TradeBreakdown, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var breakdowns []trade.TradeBreakdown
+	dec := json.NewDecoder(zr)
+	for dec.More() {
+		var bd trade.TradeBreakdown
+		if err := dec.Decode(&bd); err != nil {
+			return nil, err
+		}
+		breakdowns = append(breakdowns, bd)
+	}
+
+	return breakdowns, nil
+}
+
+// tradeIDs returns the distinct parent trades of breakdowns, sorted.
+func tradeIDs(breakdowns []trade.TradeBreakdown) []string {
+	seen := make(map[string]bool)
+	ids := []string{}
+	for _, bd := range breakdowns {
+		if !seen[bd.ParentTradeID] {
+			seen[bd.ParentTradeID] = true
+			ids = append(ids, bd.ParentTradeID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,58 @@
+package trade
+
+import "github.com/nholding/cso-book/internal/utils"
+
+// BreakdownComponent tells the commodity delivery of a month apart from the fees priced on it.
+type BreakdownComponent string
+
+const (
+	ComponentCommodity       BreakdownComponent = "COMMODITY"
+	ComponentAvailabilityFee BreakdownComponent = "AVAILABILITY_FEE"
+)
+
+// IsFee reports whether the breakdown is a fee component rather than the commodity delivery.
+func (bd *TradeBreakdown) IsFee() bool {
+	return bd.Component == ComponentAvailabilityFee
+}
+
+// availabilityFeeBreakdowns
+//
+// PURPOSE:
+//
+//	Builds the availability fee component of a trade: one breakdown per commodity breakdown,
+//	for the same month, priced at AvailabilityFeePerMT on the volume of that month. Fee
+//	breakdowns carry no volume of their own, so the capacity fee adds to payables and PnL
+//	without counting twice in positions, and the commodity price stays untouched:
+//
+//	  2026-JAN  COMMODITY         10,000 MT × 3.50  = 35,000
+//	  2026-JAN  AVAILABILITY_FEE       0 MT × 0.25  =  2,500  (0.25 × 10,000 MT)
+//
+//	Trades without an availability fee get none.
+//
+// EXAMPLE USAGE:
+//
+//	fees := availabilityFeeBreakdowns(tb, breakdowns)
+//	breakdowns = append(breakdowns, fees...)
+func availabilityFeeBreakdowns(tb TradeBase, breakdowns []TradeBreakdown) []TradeBreakdown {
+	if tb.AvailabilityFeePerMT == 0 {
+		return nil
+	}
+
+	fees := make([]TradeBreakdown, 0, len(breakdowns))
+	for _, bd := range breakdowns {
+		if bd.IsReversal() || bd.IsFee() {
+			continue
+		}
+		fee := bd
+		fee.ID = utils.GenerateStableID()
+		fee.Component = ComponentAvailabilityFee
+		fee.VolumeMT = 0
+		fee.Quantity = 0
+		fee.PricePerMT = tb.AvailabilityFeePerMT
+		fee.PriceStatus = PriceFinal
+		fee.TotalAmount = bd.VolumeMT * tb.AvailabilityFeePerMT
+		fee.CostAmount = 0
+		fees = append(fees, fee)
+	}
+	return fees
+}
@@ -2,9 +2,11 @@ package trade
 
 import (
 	"github.com/nholding/cso-book/internal/audit"
-	"github.com/nholding/cso-book/internal/period"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/units"
 
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -31,6 +33,20 @@ const (
 
 type TradeStatus string
 
+// BreakdownMode determines how a trade's volume is attributed to the months it spans.
+//
+// FULL_MONTHS: every month in the PeriodRange receives the full trade volume (default).
+// PRO_RATA:    months that are only partially covered by the delivery window
+//
+//	(DeliveryStart/DeliveryEnd) receive a day-weighted share of the volume.
+//	Used for balance-of-month deals, e.g. delivery 15–31 March.
+const (
+	BreakdownFullMonths BreakdownMode = "FULL_MONTHS"
+	BreakdownProRata    BreakdownMode = "PRO_RATA"
+)
+
+type BreakdownMode string
+
 type TradeStatusHistory struct {
 	OldStatus TradeStatus `json:"oldStatus"`
 	NewStatus TradeStatus `json:"newStatus"`
@@ -39,6 +55,16 @@ type TradeStatusHistory struct {
 	Reason    string      `json:"reason,omitempty"` // optional, must be provided for cancellations
 }
 
+// StatusChange is one status history entry together with the trade it belongs to, as listed
+// across trades, e.g. every cancellation of last month with its reason.
+type StatusChange struct {
+	TradeID        string    `json:"tradeId"`
+	TradeNumber    string    `json:"tradeNumber,omitempty"`
+	Kind           TradeKind `json:"kind"`
+	CounterpartyID string    `json:"counterpartyId"`
+	TradeStatusHistory
+}
+
 // TradeBase
 // Common fields for both Purchases and Sales. Includes PeriodRange.
 //
@@ -55,14 +81,146 @@ type TradeStatusHistory struct {
 //	    Currency: "EUR",
 //	}
 type TradeBase struct {
-	ID          string               `json:"id"`
-	PeriodRange period.PeriodRange   `json:"periodRange"`
-	VolumeMT    float64              `json:"volumeMT"`
-	PricePerMT  float64              `json:"pricePerMT"`
-	Currency    string               `json:"currency"`
-	Status      TradeStatus          `json:"status"`
+	ID          string             `json:"id"`
+	PeriodRange period.PeriodRange `json:"periodRange"`
+	VolumeMT    float64            `json:"volumeMT"`
+	PricePerMT  float64            `json:"pricePerMT"`
+	Currency    string             `json:"currency"`
+	Status      TradeStatus        `json:"status"`
+
+	// Pricing is the formula of a floating price (see PriceBreakdowns); PricePerMT is then the
+	// provisional price until the index quotes are known. Nil means a fixed PricePerMT.
+	Pricing *PricingFormula `json:"pricing,omitempty"`
+
+	// Costs are the fees charged on top of the price (broker fee, inspection, freight,
+	// insurance); they are allocated into the breakdowns by AllocateCosts.
+	Costs []CostComponent `json:"costs,omitempty"`
+
+	// AvailabilityFeePerMT is the capacity fee a supplier charges for keeping the volume
+	// available, priced separately from the commodity (see availabilityFeeBreakdowns) so the
+	// two are never conflated in PricePerMT. Purchases only; 0 means no fee.
+	AvailabilityFeePerMT float64 `json:"availabilityFeePerMT,omitempty"`
+
+	// TakeOrPay is the minimum volume obligation of a term contract (see EvaluateTakeOrPay).
+	// Nil means the contract has none.
+	TakeOrPay *TakeOrPayClause `json:"takeOrPay,omitempty"`
+
+	// Tolerance is the volume optionality of the trade; nil means the volume is firm.
+	// DeclaredVolumes holds the final volume per delivery month once the option holder has
+	// declared it (see DeclareVolume); the breakdowns of those months use it instead of the
+	// contractual volume.
+	Tolerance       *VolumeTolerance   `json:"tolerance,omitempty"`
+	DeclaredVolumes map[string]float64 `json:"declaredVolumes,omitempty"`
+
+	// Laycans holds the delivery window per delivery month, once scheduled (see SetLaycan).
+	// The breakdowns of those months carry it as LaycanStart/LaycanEnd.
+	Laycans map[string]Laycan `json:"laycans,omitempty"`
+
+	// VesselCalls holds the vessel and laytime data per delivery month (see SetVesselCall);
+	// the demurrage they produce is part of the costs of the month.
+	VesselCalls map[string]VesselCall `json:"vesselCalls,omitempty"`
+
+	// PaymentTerms tell when the amount of each delivery month is due, e.g. "30 days after
+	// B/L" (see PaymentSchedule). Nil means DefaultPaymentTerms.
+	PaymentTerms *PaymentTerms `json:"paymentTerms,omitempty"`
+
+	// Commodity, Unit and Quantity record the quantity as booked, e.g. 100,000 BBL of crude or
+	// 500,000 MWH of LNG. VolumeMT always holds the same quantity in metric tonnes (see
+	// units.Converter), so oil and gas legs aggregate in one book. An empty Unit means the trade
+	// was booked in MT; Quantity then equals VolumeMT.
+	Commodity string     `json:"commodity,omitempty"`
+	Unit      units.Unit `json:"unit,omitempty"`
+	Quantity  float64    `json:"quantity,omitempty"`
+
+	// ProductID is the product from the product master data, e.g. ULSD 10ppm. Positions can be
+	// reported per product. Empty for trades booked on a commodity only.
+	ProductID string `json:"productId,omitempty"`
+
+	// LocationID is the delivery location (port, terminal or storage site) from the location
+	// master data. Empty when not agreed yet.
+	LocationID string `json:"locationId,omitempty"`
+
+	// BreakdownMode selects full-month or day-weighted breakdowns. Empty means FULL_MONTHS.
+	BreakdownMode BreakdownMode `json:"breakdownMode,omitempty"`
+	// AllocationMode tells whether VolumeMT is a per-month or a total volume and how a total
+	// is spread over the months. Empty means PER_MONTH_FIXED.
+	AllocationMode AllocationMode `json:"allocationMode,omitempty"`
+	// DeliveryStart/DeliveryEnd (both inclusive, day precision) either narrow the delivery
+	// window inside the PeriodRange (PRO_RATA mode only), or — for custom-dated trades with an
+	// empty PeriodRange — define the delivery window on their own.
+	DeliveryStart *time.Time `json:"deliveryStart,omitempty"`
+	DeliveryEnd   *time.Time `json:"deliveryEnd,omitempty"`
+
 	StatusAudit []TradeStatusHistory `json:"statusAudit"`
-	AuditInfo   audit.AuditInfo      `json:"auditInfo"`
+
+	// PolicyOverrides records every booking policy (e.g. the booking window) that was
+	// bypassed through an override role, for compliance audit.
+	PolicyOverrides []PolicyOverride `json:"policyOverrides,omitempty"`
+
+	// TradeNumber is the deal number quoted to counterparties, e.g. P-2026-0001 (see
+	// FormatTradeNumber). It is assigned when the trade is booked and kept by every version of
+	// the trade; ID remains the internal key.
+	TradeNumber string `json:"tradeNumber,omitempty"`
+
+	// BusinessKey is the hash of the commercial terms (see BusinessKey), used to catch deals
+	// that are booked twice. It is recomputed whenever the terms are amended.
+	BusinessKey string `json:"businessKey,omitempty"`
+
+	// Versioning: amending a CONFIRMED trade creates a new trade (see NewVersion). The new
+	// version points back at the trade it replaces; the replaced trade becomes SUPERSEDED and
+	// points forward. Both stay persisted, so the whole chain remains auditable.
+	PreviousVersionID string `json:"previousVersionId,omitempty"`
+	SupersededByID    string `json:"supersededById,omitempty"`
+
+	// SplitFromID is set on both children of a split trade (see Split) and points at the trade
+	// that was divided; PreviousVersionID points there as well, SupersededByID of that trade
+	// stays empty.
+	SplitFromID string `json:"splitFromId,omitempty"`
+
+	// CancelledPeriodIDs are delivery months cancelled on their own (see CancelMonths), e.g.
+	// after force majeure; their breakdowns are CANCELLED while the trade stays live.
+	CancelledPeriodIDs []string `json:"cancelledPeriodIds,omitempty"`
+
+	// Soft delete: a deleted trade stays persisted (and can be restored) but is excluded
+	// from trade queries. Unlike CANCELLED, deletion removes bookings made in error.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy *string    `json:"deletedBy,omitempty"`
+
+	// RowVersion is the optimistic-lock counter of the stored trade row. Updates must carry the
+	// version they read; the repository rejects stale ones with a *dberr.ConflictError.
+	RowVersion int `json:"rowVersion,omitempty"`
+
+	AuditInfo audit.AuditInfo `json:"auditInfo"`
+}
+
+// IsDeleted reports whether the trade is soft-deleted.
+func (tb *TradeBase) IsDeleted() bool {
+	return tb.DeletedAt != nil
+}
+
+// SoftDelete marks the trade as deleted by user.
+func (tb *TradeBase) SoftDelete(user string) error {
+	if tb.IsDeleted() {
+		return fmt.Errorf("trade %s is already deleted", tb.ID)
+	}
+
+	now := time.Now().UTC()
+	tb.DeletedAt = &now
+	tb.DeletedBy = &user
+	tb.AuditInfo.UpdateAuditInfo(user)
+	return nil
+}
+
+// Restore undoes SoftDelete.
+func (tb *TradeBase) Restore(user string) error {
+	if !tb.IsDeleted() {
+		return fmt.Errorf("trade %s is not deleted", tb.ID)
+	}
+
+	tb.DeletedAt = nil
+	tb.DeletedBy = nil
+	tb.AuditInfo.UpdateAuditInfo(user)
+	return nil
 }
 
 func NewTradeBase(pr period.PeriodRange, volumeMT, pricePerMT float64, currency, createdBy string) *TradeBase {
@@ -88,24 +246,141 @@ func NewTradeBase(pr period.PeriodRange, volumeMT, pricePerMT float64, currency,
 	return &tb
 }
 
-// Method to update trade status for any TradeBase (Purchase/Sale)
+// NewCustomDatedTradeBase creates a trade with a non-standard delivery window (e.g. 12 March – 9 May)
+// instead of a PeriodRange. The breakdown engine maps the window onto every month it touches:
+// in full using BreakdownFullMonths, or day-weighted using BreakdownProRata.
+//
+// Example:
+//
+//	start := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+//	end := time.Date(2026, 5, 9, 0, 0, 0, 0, time.UTC)
+//	tb, err := NewCustomDatedTradeBase(start, end, BreakdownProRata, 10000, 3.5, "EUR", "user@internal.local")
+func NewCustomDatedTradeBase(start, end time.Time, mode BreakdownMode, volumeMT, pricePerMT float64, currency, createdBy string) (*TradeBase, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("delivery end %s is before delivery start %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+
+	tb := NewTradeBase(period.PeriodRange{}, volumeMT, pricePerMT, currency, createdBy)
+	tb.BreakdownMode = mode
+	tb.DeliveryStart = &start
+	tb.DeliveryEnd = &end
+
+	return tb, nil
+}
+
+// BookedUnit returns the unit the trade was booked in, defaulting to MT.
+func (t *TradeBase) BookedUnit() units.Unit {
+	if t.Unit == "" {
+		return units.MetricTonne
+	}
+	return t.Unit
+}
+
+// BookedQuantity returns the quantity in BookedUnit.
+func (t *TradeBase) BookedQuantity() float64 {
+	if t.BookedUnit() == units.MetricTonne {
+		return t.VolumeMT
+	}
+	return t.Quantity
+}
+
+// HasCustomDates reports whether the trade is defined by explicit delivery dates rather than a PeriodRange.
+func (t *TradeBase) HasCustomDates() bool {
+	return t.PeriodRange.IsZero() && t.DeliveryStart != nil && t.DeliveryEnd != nil
+}
+
+// allowedTransitions lists the statuses each status may move to. CANCELLED and SUPERSEDED
+// are final and have none; DRAFT is only the initial status and cannot be moved back to.
+var allowedTransitions = map[TradeStatus][]TradeStatus{
+	TradeStatusDraft:     {TradeStatusPending, TradeStatusConfirmed, TradeStatusCancelled},
+	TradeStatusPending:   {TradeStatusConfirmed, TradeStatusCancelled, TradeStatusSuperseded},
+	TradeStatusConfirmed: {TradeStatusCancelled, TradeStatusSuperseded},
+}
+
+// CanTransition reports whether a trade in status from may move to status to.
+func CanTransition(from, to TradeStatus) bool {
+	for _, next := range allowedTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateTradeStatus
+//
+// PURPOSE:
+//
+//	Moves any TradeBase (Purchase/Sale/...) to newStatus, records the change in the status
+//	history and touches the audit info. Only the transitions of allowedTransitions are
+//	accepted:
+//
+//	  DRAFT → PENDING-CONFIRMATION → CONFIRMED → SUPERSEDED
+//	    │             │                 └──────→ CANCELLED  (reason mandatory)
+//	    │             ├──────────────────────────→ SUPERSEDED (revised recap before confirmation)
+//	    │             └──────────────────────────→ CANCELLED  (recap rejected)
+//	    ├──────────────────────────→ CONFIRMED (confirmed without a pending recap)
+//	    └──────────────────────────→ CANCELLED
+//
+//	A DRAFT is edited in place rather than superseded, and a trade never moves back to DRAFT
+//	or from CONFIRMED to PENDING-CONFIRMATION. CANCELLED and SUPERSEDED are final. The trade
+//	is left unchanged when an error is returned.
+//
+// EXAMPLE USAGE:
+//
+//	err := tb.UpdateTradeStatus(TradeStatusCancelled, "vessel unavailable", "user@internal.local")
 func (t *TradeBase) UpdateTradeStatus(newStatus TradeStatus, reason, changedBy string) error {
-	// Ensure the new status is valid
-	if newStatus != "PENDING" && newStatus != "CONFIRMED" && newStatus != "CANCELLED" && newStatus != "SUPERSEDED" {
+	switch newStatus {
+	case TradeStatusDraft, TradeStatusPending, TradeStatusConfirmed, TradeStatusCancelled, TradeStatusSuperseded:
+	default:
 		return fmt.Errorf("invalid status: %s", newStatus)
 	}
+	switch t.Status {
+	case TradeStatusCancelled, TradeStatusSuperseded:
+		return fmt.Errorf("trade %s is %s and cannot change status", t.ID, t.Status)
+	case newStatus:
+		return fmt.Errorf("trade %s is already %s", t.ID, newStatus)
+	}
+	if !CanTransition(t.Status, newStatus) {
+		return fmt.Errorf("trade %s cannot move from %s to %s", t.ID, t.Status, newStatus)
+	}
+	if newStatus == TradeStatusCancelled && strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("a reason is required to cancel trade %s", t.ID)
+	}
+	if changedBy == "" {
+		return fmt.Errorf("the user changing the status of trade %s is required", t.ID)
+	}
 
 	now := time.Now().UTC()
-	oldStatus := t.Status
-
-	// Record in status history
 	t.StatusAudit = append(t.StatusAudit, TradeStatusHistory{
-		OldStatus: oldStatus,
+		OldStatus: t.Status,
 		NewStatus: newStatus,
 		ChangedAt: now,
 		ChangedBy: changedBy,
 		Reason:    reason,
 	})
+	t.Status = newStatus
+	t.AuditInfo.UpdateAuditInfo(changedBy)
 
 	return nil
 }
+
+// RecordStatusNote records an event that does not change the status, e.g. a blocked
+// confirmation or a declared volume, as a status history entry from the current status to
+// itself, and touches the audit info. The status history is the trade's timeline, so these
+// events show up next to the status changes.
+//
+// Example:
+//
+//	tb.RecordStatusNote("confirmation blocked by credit check: limit exceeded", "user@internal.local")
+//	// StatusAudit gains {CONFIRMED → CONFIRMED, Reason: "confirmation blocked by credit check: limit exceeded"}
+func (t *TradeBase) RecordStatusNote(note, user string) {
+	t.StatusAudit = append(t.StatusAudit, TradeStatusHistory{
+		OldStatus: t.Status,
+		NewStatus: t.Status,
+		ChangedAt: time.Now().UTC(),
+		ChangedBy: user,
+		Reason:    note,
+	})
+	t.AuditInfo.UpdateAuditInfo(user)
+}
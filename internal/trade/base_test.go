@@ -0,0 +1,104 @@
+package trade
+
+import (
+	"testing"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+func TestUpdateTradeStatusTransitions(t *testing.T) {
+	const (
+		draft      = TradeStatusDraft
+		pending    = TradeStatusPending
+		confirmed  = TradeStatusConfirmed
+		cancelled  = TradeStatusCancelled
+		superseded = TradeStatusSuperseded
+	)
+	legal := map[[2]TradeStatus]bool{
+		{draft, pending}:        true,
+		{draft, confirmed}:      true,
+		{draft, cancelled}:      true,
+		{pending, confirmed}:    true,
+		{pending, cancelled}:    true,
+		{pending, superseded}:   true,
+		{confirmed, cancelled}:  true,
+		{confirmed, superseded}: true,
+	}
+
+	// Every pair of statuses, including the ones that are not listed as legal
+	statuses := []TradeStatus{draft, pending, confirmed, cancelled, superseded}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := legal[[2]TradeStatus{from, to}]
+			t.Run(string(from)+"→"+string(to), func(t *testing.T) {
+				tb := NewTradeBase(period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"}, 1000, 600, "EUR", "trader@internal.local")
+				tb.Status = from
+				history := len(tb.StatusAudit)
+
+				err := tb.UpdateTradeStatus(to, "recap rejected", "ops@internal.local")
+				if CanTransition(from, to) != want {
+					t.Errorf("CanTransition = %v, want %v", !want, want)
+				}
+				if want {
+					if err != nil {
+						t.Fatalf("UpdateTradeStatus: %v", err)
+					}
+					if tb.Status != to || len(tb.StatusAudit) != history+1 {
+						t.Errorf("status %s with %d history entries, want %s with %d", tb.Status, len(tb.StatusAudit), to, history+1)
+					}
+					return
+				}
+				if err == nil {
+					t.Fatal("UpdateTradeStatus succeeded")
+				}
+				if tb.Status != from || len(tb.StatusAudit) != history {
+					t.Errorf("rejected transition changed the trade: status %s with %d history entries", tb.Status, len(tb.StatusAudit))
+				}
+			})
+		}
+	}
+}
+
+func TestUpdateTradeStatusRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    TradeStatus
+		reason    string
+		changedBy string
+	}{
+		{name: "unknown status", status: "VOIDED", reason: "typo", changedBy: "ops@internal.local"},
+		{name: "cancellation without reason", status: TradeStatusCancelled, reason: " ", changedBy: "ops@internal.local"},
+		{name: "no user", status: TradeStatusPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := NewTradeBase(period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"}, 1000, 600, "EUR", "trader@internal.local")
+
+			if err := tb.UpdateTradeStatus(tt.status, tt.reason, tt.changedBy); err == nil {
+				t.Fatal("UpdateTradeStatus succeeded")
+			}
+			if tb.Status != TradeStatusDraft {
+				t.Errorf("status changed to %s", tb.Status)
+			}
+		})
+	}
+}
+
+func TestRecordStatusNoteKeepsStatus(t *testing.T) {
+	tb := NewTradeBase(period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"}, 1000, 600, "EUR", "trader@internal.local")
+	history := len(tb.StatusAudit)
+
+	tb.RecordStatusNote("confirmation blocked by credit check: limit exceeded", "ops@internal.local")
+
+	if tb.Status != TradeStatusDraft || len(tb.StatusAudit) != history+1 {
+		t.Fatalf("status %s with %d history entries, want DRAFT with %d", tb.Status, len(tb.StatusAudit), history+1)
+	}
+	entry := tb.StatusAudit[len(tb.StatusAudit)-1]
+	if entry.OldStatus != TradeStatusDraft || entry.NewStatus != TradeStatusDraft || entry.ChangedBy != "ops@internal.local" ||
+		entry.Reason != "confirmation blocked by credit check: limit exceeded" {
+		t.Errorf("history entry = %+v", entry)
+	}
+	if tb.AuditInfo.LastActor() != "ops@internal.local" {
+		t.Errorf("last actor = %s, want ops@internal.local", tb.AuditInfo.LastActor())
+	}
+}
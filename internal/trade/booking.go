@@ -0,0 +1,74 @@
+package trade
+
+import (
+	"errors"
+	"time"
+)
+
+// BookingRequest carries the context of a booking attempt: who books, with which roles, and when.
+// It is passed through every BookingCheck of the validation pipeline.
+type BookingRequest struct {
+	User           string
+	Roles          []string
+	At             time.Time // Moment of booking; zero means time.Now()
+	OverrideReason string    // Mandatory when the user relies on an override role
+	AllowDuplicate bool      // Book even when a live trade has the same terms (see BusinessKey); needs OverrideReason
+}
+
+// HasRole reports whether the booking user holds the given role.
+func (r BookingRequest) HasRole(role string) bool {
+	for _, rr := range r.Roles {
+		if rr == role {
+			return true
+		}
+	}
+	return false
+}
+
+// at returns the booking moment, defaulting to now.
+func (r BookingRequest) at() time.Time {
+	if r.At.IsZero() {
+		return time.Now()
+	}
+	return r.At
+}
+
+// PolicyOverride is an audit record written whenever a user bypasses a booking policy
+// through an override role. Overrides are kept on the trade for its full lifetime.
+type PolicyOverride struct {
+	Policy     string    `json:"policy"`     // e.g. "booking-window"
+	User       string    `json:"user"`       // User who booked outside the policy
+	Role       string    `json:"role"`       // Override role that allowed it
+	Reason     string    `json:"reason"`     // Justification given by the user
+	At         time.Time `json:"at"`         // UTC moment of booking
+	LocalTime  string    `json:"localTime"`  // Booking moment in the policy's timezone, e.g. "2026-03-02 21:14 CET"
+	Violations string    `json:"violations"` // What would have been rejected without the override
+}
+
+// BookingCheck is one step of the trade booking validation pipeline.
+// A check may reject the booking by returning an error, or annotate the trade (e.g. with
+// a PolicyOverride) and return nil.
+type BookingCheck interface {
+	Check(t *TradeBase, req BookingRequest) error
+}
+
+// BookingPipeline runs every check in order and returns all rejections joined together,
+// so the user sees every problem with a booking at once.
+//
+// Example:
+//
+//	pipeline := BookingPipeline{bookingWindow}
+//	if err := pipeline.Validate(&purchase.TradeBase, req); err != nil {
+//	    return err
+//	}
+type BookingPipeline []BookingCheck
+
+func (p BookingPipeline) Validate(t *TradeBase, req BookingRequest) error {
+	var errs []error
+	for _, check := range p {
+		if err := check.Check(t, req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
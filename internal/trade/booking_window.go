@@ -0,0 +1,135 @@
+package trade
+
+import (
+	"fmt"
+	"time"
+)
+
+const bookingWindowPolicy = "booking-window"
+
+// BookingWindowConfig is the compliance configuration for when trades may be booked.
+//
+// Example:
+//
+//	cfg := BookingWindowConfig{
+//	    Timezone:     "Europe/Amsterdam",
+//	    Open:         "08:00",
+//	    Close:        "18:30",
+//	    Weekdays:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+//	    OverrideRole: "booking-window-override",
+//	}
+type BookingWindowConfig struct {
+	Timezone     string         // IANA timezone in which Open/Close are interpreted
+	Open         string         // Local opening time, "HH:MM" (inclusive)
+	Close        string         // Local closing time, "HH:MM" (exclusive)
+	Weekdays     []time.Weekday // Days on which booking is allowed; empty means every day
+	OverrideRole string         // Role that may book outside the window (with a reason)
+}
+
+// BookingWindow is the compiled, timezone-aware booking window policy. It implements BookingCheck.
+type BookingWindow struct {
+	loc          *time.Location
+	open, close  time.Duration // Offsets from local midnight
+	weekdays     map[time.Weekday]bool
+	overrideRole string
+}
+
+// NewBookingWindow validates the configuration and compiles it into a BookingWindow.
+func NewBookingWindow(cfg BookingWindowConfig) (*BookingWindow, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid booking window timezone %q: %w", cfg.Timezone, err)
+	}
+
+	open, err := parseClock(cfg.Open)
+	if err != nil {
+		return nil, fmt.Errorf("invalid booking window open time: %w", err)
+	}
+
+	closeAt, err := parseClock(cfg.Close)
+	if err != nil {
+		return nil, fmt.Errorf("invalid booking window close time: %w", err)
+	}
+
+	if !open.Before(closeAt) {
+		return nil, fmt.Errorf("booking window open time %s must be before close time %s", cfg.Open, cfg.Close)
+	}
+
+	weekdays := make(map[time.Weekday]bool, len(cfg.Weekdays))
+	for _, d := range cfg.Weekdays {
+		weekdays[d] = true
+	}
+
+	return &BookingWindow{
+		loc:          loc,
+		open:         sinceMidnight(open),
+		close:        sinceMidnight(closeAt),
+		weekdays:     weekdays,
+		overrideRole: cfg.OverrideRole,
+	}, nil
+}
+
+// Check rejects bookings outside the configured window, unless the user holds the override
+// role and supplies a reason. Every override is recorded on the trade as a PolicyOverride.
+func (w *BookingWindow) Check(t *TradeBase, req BookingRequest) error {
+	at := req.at()
+	violation := w.violation(at)
+	if violation == "" {
+		return nil
+	}
+
+	if w.overrideRole == "" || !req.HasRole(w.overrideRole) {
+		return fmt.Errorf("trade %s cannot be booked: %s", t.ID, violation)
+	}
+
+	if req.OverrideReason == "" {
+		return fmt.Errorf("trade %s is booked outside the booking window: an override reason is required", t.ID)
+	}
+
+	t.PolicyOverrides = append(t.PolicyOverrides, PolicyOverride{
+		Policy:     bookingWindowPolicy,
+		User:       req.User,
+		Role:       w.overrideRole,
+		Reason:     req.OverrideReason,
+		At:         at.UTC(),
+		LocalTime:  at.In(w.loc).Format("2006-01-02 15:04 MST"),
+		Violations: violation,
+	})
+
+	return nil
+}
+
+// violation returns a description of why `at` lies outside the window, or "" when it is inside.
+func (w *BookingWindow) violation(at time.Time) string {
+	local := at.In(w.loc)
+
+	if len(w.weekdays) > 0 && !w.weekdays[local.Weekday()] {
+		return fmt.Sprintf("booking is not allowed on %s (%s)", local.Weekday(), w.loc)
+	}
+
+	// Wall-clock offset, so DST transition days keep the configured local hours
+	offset := sinceMidnight(local)
+	if offset < w.open || offset >= w.close {
+		return fmt.Sprintf("%s is outside the booking window %s–%s (%s)",
+			local.Format("15:04"), fmtClock(w.open), fmtClock(w.close), w.loc)
+	}
+
+	return ""
+}
+
+// parseClock parses "HH:MM" into a time on the zero date.
+func parseClock(s string) (time.Time, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not in HH:MM format", s)
+	}
+	return t, nil
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+func fmtClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
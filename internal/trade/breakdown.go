@@ -2,7 +2,9 @@ package trade
 
 import (
 	"github.com/nholding/cso-book/internal/audit"
-	"github.com/nholding/cso-book/internal/period"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/units"
+	"github.com/nholding/cso-book/internal/utils"
 	"time"
 )
 
@@ -23,20 +25,86 @@ type TradeBreakdown struct {
 	ID            string
 	BusinessKey   string
 	ParentTradeID string // Links back to the original Purchase/Sale
+	// Display name of the trade's counterparty, copied from the company master data when the
+	// breakdowns are built, so reports need no join on companies
+	CounterpartyName string
+
 	PeriodID      string
-	StartDate     time.Time
-	EndDate       time.Time
+	StartDate     time.Time // First delivery moment within the month (month start for full months)
+	EndDate       time.Time // Last delivery moment within the month (month end for full months)
+	MonthFraction float64   // Share of the month covered by delivery: 1 for full months, e.g. 17/31 for 15–31 March
 	VolumeMT      float64
+	Unit          units.Unit // Unit the parent trade was booked in
+	Quantity      float64    // VolumeMT expressed in Unit
 	PricePerMT    float64
+	PriceStatus   PriceStatus // FINAL for fixed prices; PROVISIONAL until a pricing formula is fully known
 	Currency      string
 	TotalAmount   float64
-	AuditInfo     audit.AuditInfo // Inherit from parent trade
+	CostAmount    float64 // Trade costs allocated to the month (see AllocateCosts), in Currency
+
+	// LaycanStart/LaycanEnd are the delivery window within the month once scheduled (see
+	// TradeBase.SetLaycan); nil until then.
+	LaycanStart *time.Time
+	LaycanEnd   *time.Time
+
+	// Component is AVAILABILITY_FEE for the capacity fee of the month (see
+	// availabilityFeeBreakdowns), which carries no volume. Empty means COMMODITY.
+	Component BreakdownComponent
+
+	// Status is CANCELLED for a delivery month that was cancelled on its own (see
+	// TradeBase.CancelMonths); the row is kept for audit but no longer counts in the book.
+	// Empty means LIVE.
+	Status BreakdownStatus
+
+	// Reporting currency view (see ConvertToReporting): TotalAmount converted into the book's
+	// reporting currency, so positions and proceeds aggregate regardless of the deal currency.
+	// Empty ReportingCurrency means not converted.
+	ReportingCurrency string
+	FXRate            float64 // Currency → ReportingCurrency rate applied
+	ReportingAmount   float64
+
+	// Reversal linkage (see CancelTrade). A reversal entry is posted in an open period
+	// and offsets a breakdown that lives in a HARD_CLOSED period.
+	ReversalOfID     *string // ID of the original breakdown this entry reverses; nil for regular breakdowns
+	ReversedPeriodID *string // Delivery month of the reversed breakdown (PeriodID holds the posting month)
+
+	AuditInfo audit.AuditInfo // Inherit from parent trade
+}
+
+// BreakdownStatus tells whether a delivery month of a live trade still counts.
+type BreakdownStatus string
+
+const (
+	BreakdownLive      BreakdownStatus = "LIVE"
+	BreakdownCancelled BreakdownStatus = "CANCELLED"
+)
+
+// IsCancelled reports whether the delivery month was cancelled.
+func (bd *TradeBreakdown) IsCancelled() bool {
+	return bd.Status == BreakdownCancelled
+}
+
+// LiveBreakdowns returns the breakdowns that count in the book, leaving out cancelled months.
+func LiveBreakdowns(breakdowns []TradeBreakdown) []TradeBreakdown {
+	live := make([]TradeBreakdown, 0, len(breakdowns))
+	for _, bd := range breakdowns {
+		if !bd.IsCancelled() {
+			live = append(live, bd)
+		}
+	}
+	return live
+}
+
+// IsReversal reports whether the breakdown is a reversal entry rather than a delivery month.
+func (bd *TradeBreakdown) IsReversal() bool {
+	return bd.ReversalOfID != nil
 }
 
 // CreateTradeBreakdowns generates monthly breakdowns for a trade,
-// handling multi-month trades by duplicating the breakdown for each month the trade spans.
-// Since we deal with full months only, no partial month handling is needed.
-// This function now ensures that for each month a trade spans, the full volume and value are attributed to that month.
+// handling multi-month trades by creating a breakdown for each month the trade spans.
+// By default (AllocationPerMonthFixed) the full volume and value are attributed to every month;
+// with AllocationTotalSpreadEvenly or AllocationDayWeighted the trade volume is split over the
+// months instead, reconciling exactly to the header volume (see AllocationMode).
 //
 // Parameters:
 //   - trade: TradeBase containing trade details and PeriodRange
@@ -71,6 +139,18 @@ type TradeBreakdown struct {
 //	//   {PeriodID: "2026-MAY", Value: 35000},
 //	//   {PeriodID: "2026-JUN", Value: 35000},
 //	// ]
+//
+// Pro-rata example (balance-of-month deal, delivery 15–31 March):
+//
+//	start := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+//	tb.PeriodRange = period.PeriodRange{StartPeriodID: "2026-MAR", EndPeriodID: "2026-MAR"}
+//	tb.BreakdownMode = BreakdownProRata
+//	tb.DeliveryStart = &start
+//
+//	// Output: [{PeriodID: "2026-MAR", MonthFraction: 17/31, VolumeMT: 5483.87, ...}]
+//
+// Custom-dated trades (see NewCustomDatedTradeBase) have no PeriodRange. Their delivery window
+// is mapped onto all months it touches, in full or pro-rated depending on BreakdownMode.
 func CreateTradeBreakdowns(trade TradeBase, ps *period.PeriodStore, createdBy string) []TradeBreakdown {
 	// Prepare an empty slice to store the breakdowns for each month
 	var breakdowns []TradeBreakdown
@@ -78,9 +158,20 @@ func CreateTradeBreakdowns(trade TradeBase, ps *period.PeriodStore, createdBy st
 	// Step 1: Flatten PeriodRange into all constituent month IDs
 	// Here, we get the list of months that fall within the trade's start and end period range
 	// Note: The BreakDownTradePeriodRange function handles multi-month ranges and ensures full month handling.
-	monthIDs := ps.BreakDownTradePeriodRange(trade.PeriodRange)
+	// Custom-dated trades have no PeriodRange; their months are every month the delivery window touches.
+	var monthIDs []string
+	if trade.HasCustomDates() {
+		monthIDs = ps.BreakDownDateRange(utils.StartOfDay(*trade.DeliveryStart), utils.EndOfDay(*trade.DeliveryEnd))
+	} else {
+		monthIDs = ps.BreakDownTradePeriodRange(trade.PeriodRange)
+	}
+
+	// Step 2: Determine the delivery window.
+	// In PRO_RATA mode DeliveryStart/DeliveryEnd clip the months and weight the volume;
+	// otherwise every month is delivered in full.
+	windowStart, windowEnd, proRata := deliveryWindow(trade)
 
-	// Step 2: Create a TradeBreakdown for each month
+	// Step 3: Create a TradeBreakdown for each month
 	// For each month that the trade spans, create a TradeBreakdown
 	for _, monthID := range monthIDs {
 		p := ps.FindByID(monthID) // Find the period object for this month
@@ -88,19 +179,39 @@ func CreateTradeBreakdowns(trade TradeBase, ps *period.PeriodStore, createdBy st
 			continue // skip if month not found (should not happen if periods are preloaded)
 		}
 
-		// Here, we simply use the full trade volume for each month in the range
-		// There are no fractional calculations since we’re dealing with full months only
-		volume := trade.VolumeMT
-		totalAmount := volume * trade.PricePerMT // Total value for the entire month
+		start, end, fraction := p.StartDate, p.EndDate, 1.0
+
+		if proRata {
+			// Clip the month to the delivery window and weight by covered days
+			if windowStart != nil && windowStart.After(start) {
+				start = *windowStart
+			}
+			if windowEnd != nil && windowEnd.Before(end) {
+				end = *windowEnd
+			}
+
+			coveredDays := utils.DaysInRange(start, end)
+			if coveredDays == 0 {
+				continue // month lies completely outside the delivery window
+			}
+			fraction = float64(coveredDays) / float64(utils.DaysInRange(p.StartDate, p.EndDate))
+		}
+
+		// In FULL_MONTHS mode fraction is 1, so every month receives the full trade volume.
+		// In PRO_RATA mode the volume is weighted by the share of the month that is delivered.
+		volume := trade.VolumeMT * fraction
+		totalAmount := volume * trade.PricePerMT
 
 		bd := TradeBreakdown{
-			ID:            "TBTestID",
+			ID:            utils.GenerateStableID(),
 			ParentTradeID: trade.ID,
 			PeriodID:      p.ID,
-			StartDate:     p.StartDate,
-			EndDate:       p.EndDate,
+			StartDate:     start,
+			EndDate:       end,
+			MonthFraction: fraction,
 			VolumeMT:      volume,
 			PricePerMT:    trade.PricePerMT,
+			PriceStatus:   initialPriceStatus(trade),
 			Currency:      trade.Currency,
 			TotalAmount:   totalAmount,
 			AuditInfo:     trade.AuditInfo,
@@ -110,5 +221,75 @@ func CreateTradeBreakdowns(trade TradeBase, ps *period.PeriodStore, createdBy st
 		breakdowns = append(breakdowns, bd)
 	}
 
+	// Step 4: Express every month in the unit the trade was booked in as well
+	for i := range breakdowns {
+		breakdowns[i].Unit = trade.BookedUnit()
+		breakdowns[i].Quantity = trade.BookedQuantity() * breakdowns[i].MonthFraction
+	}
+
+	// Step 5: In the total allocation modes VolumeMT is the volume of the whole trade;
+	// replace the per-month volumes by its share per month
+	if mode := trade.Allocation(); mode != AllocationPerMonthFixed && len(breakdowns) > 0 {
+		allocateTotal(breakdowns, mode, trade.VolumeMT, trade.BookedQuantity())
+	}
+
+	// Step 6: Months whose final volume was declared within the tolerance use that volume
+	trade.applyDeclaredVolumes(breakdowns)
+
+	// Step 7: The availability fee is a component of its own, priced on the monthly volumes
+	breakdowns = append(breakdowns, availabilityFeeBreakdowns(trade, breakdowns)...)
+
+	// Step 8: Months cancelled on their own stay cancelled whenever the breakdowns are rebuilt
+	trade.markCancelled(breakdowns)
+
+	// Step 9: Scheduled months keep their delivery window
+	trade.applyLaycans(breakdowns)
+
 	return breakdowns
 }
+
+// deliveryWindow returns the inclusive delivery window of a PRO_RATA trade.
+// DeliveryStart is normalised to the start of its day and DeliveryEnd to the end of its day,
+// so a window of "15 March – 31 March" covers exactly 17 days.
+// The last return value is false when the trade uses full-month breakdowns.
+func deliveryWindow(trade TradeBase) (*time.Time, *time.Time, bool) {
+	if trade.BreakdownMode != BreakdownProRata {
+		return nil, nil, false
+	}
+
+	var start, end *time.Time
+	if trade.DeliveryStart != nil {
+		s := utils.StartOfDay(*trade.DeliveryStart)
+		start = &s
+	}
+	if trade.DeliveryEnd != nil {
+		e := utils.EndOfDay(*trade.DeliveryEnd)
+		end = &e
+	}
+
+	return start, end, true
+}
+
+// BreakdownReferences maps breakdowns to the period references they hold, for referential
+// integrity checks (see period.CalendarValidator.CheckIntegrity). Reversal entries reference
+// both their posting month and the month they reverse.
+func BreakdownReferences(breakdowns []TradeBreakdown) []period.PeriodReference {
+	var refs []period.PeriodReference
+	for _, bd := range breakdowns {
+		refs = append(refs, period.PeriodReference{
+			Kind:     "breakdown",
+			OwnerID:  bd.ParentTradeID,
+			RecordID: bd.ID,
+			PeriodID: bd.PeriodID,
+		})
+		if bd.ReversedPeriodID != nil {
+			refs = append(refs, period.PeriodReference{
+				Kind:     "breakdown",
+				OwnerID:  bd.ParentTradeID,
+				RecordID: bd.ID,
+				PeriodID: *bd.ReversedPeriodID,
+			})
+		}
+	}
+	return refs
+}
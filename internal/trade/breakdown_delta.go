@@ -0,0 +1,108 @@
+package trade
+
+import (
+	"sort"
+	"time"
+)
+
+// BreakdownDelta is what an amendment changed in one delivery month of a trade: the volume and
+// value of the month before and after, summed over its live breakdowns (commodity and fees).
+// Months the amendment added have zero Old values; months it removed zero New values.
+type BreakdownDelta struct {
+	TradeID         string    `json:"tradeId"`                   // The amended trade (the new version when it was superseded)
+	PreviousTradeID string    `json:"previousTradeId,omitempty"` // The superseded version; empty for an amendment in place
+	TradeNumber     string    `json:"tradeNumber,omitempty"`
+	PeriodID        string    `json:"periodId"`
+	OldVolumeMT     float64   `json:"oldVolumeMT"`
+	NewVolumeMT     float64   `json:"newVolumeMT"`
+	OldAmount       float64   `json:"oldAmount"`
+	NewAmount       float64   `json:"newAmount"`
+	OldCurrency     string    `json:"oldCurrency,omitempty"`
+	NewCurrency     string    `json:"newCurrency,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	AmendedBy       string    `json:"amendedBy"`
+	AmendedAt       time.Time `json:"amendedAt"`
+}
+
+// VolumeChangeMT returns the change in volume of the month.
+func (d BreakdownDelta) VolumeChangeMT() float64 { return d.NewVolumeMT - d.OldVolumeMT }
+
+// AmountChange returns the change in value of the month. It is only meaningful when the
+// currency did not change.
+func (d BreakdownDelta) AmountChange() float64 { return d.NewAmount - d.OldAmount }
+
+// DiffBreakdowns
+//
+// PURPOSE:
+//
+//	Compares the breakdowns of a trade before and after an amendment, month by month, so
+//	finance can see exactly what changed in each period:
+//
+//	  2026-JAN  10,000 MT  35,000  →  10,000 MT  36,000   (price 3.5 → 3.6)
+//	  2026-APR       0 MT       0  →   5,000 MT  18,000   (month added)
+//
+//	Cancelled breakdowns and reversal entries are ignored. Months whose volume, amount and
+//	currency are unchanged are left out, so an amendment of non-economic terms (a location)
+//	yields no deltas. The result is ordered by delivery date. The trade identity, reason and
+//	audit fields are left for the caller to fill in.
+//
+// EXAMPLE USAGE:
+//
+//	deltas := DiffBreakdowns(before, after)
+//	// → [{PeriodID: "2026-JAN", OldAmount: 35000, NewAmount: 36000, ...}]
+func DiffBreakdowns(before, after []TradeBreakdown) []BreakdownDelta {
+	type month struct {
+		start    time.Time
+		volume   float64
+		amount   float64
+		currency string
+	}
+	sum := func(breakdowns []TradeBreakdown) map[string]*month {
+		months := make(map[string]*month)
+		for _, bd := range LiveBreakdowns(breakdowns) {
+			if bd.IsReversal() {
+				continue
+			}
+			m, ok := months[bd.PeriodID]
+			if !ok {
+				m = &month{start: bd.StartDate, currency: bd.Currency}
+				months[bd.PeriodID] = m
+			}
+			m.volume += bd.VolumeMT
+			m.amount += bd.TotalAmount
+		}
+		return months
+	}
+	old, next := sum(before), sum(after)
+
+	starts := make(map[string]time.Time)
+	for id, m := range old {
+		starts[id] = m.start
+	}
+	for id, m := range next {
+		starts[id] = m.start
+	}
+
+	var deltas []BreakdownDelta
+	for id := range starts {
+		d := BreakdownDelta{PeriodID: id}
+		if m, ok := old[id]; ok {
+			d.OldVolumeMT, d.OldAmount, d.OldCurrency = m.volume, m.amount, m.currency
+		}
+		if m, ok := next[id]; ok {
+			d.NewVolumeMT, d.NewAmount, d.NewCurrency = m.volume, m.amount, m.currency
+		}
+		if d.OldVolumeMT == d.NewVolumeMT && d.OldAmount == d.NewAmount && d.OldCurrency == d.NewCurrency {
+			continue
+		}
+		deltas = append(deltas, d)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		si, sj := starts[deltas[i].PeriodID], starts[deltas[j].PeriodID]
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		return deltas[i].PeriodID < deltas[j].PeriodID
+	})
+	return deltas
+}
@@ -0,0 +1,31 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RecordConfirmationSent records in the status history that the confirmation of the trade was
+// sent to the counterparty. The status does not change; the entry tells who sent it to whom,
+// and the message ID of the mail provider for tracing delivery.
+//
+// Example:
+//
+//	err := t.Base().RecordConfirmationSent([]string{"ops@shell.example"}, "0100018f...", "user@internal.local")
+//	// StatusAudit gains {CONFIRMED → CONFIRMED, Reason: "confirmation sent to ops@shell.example (message 0100018f...)"}
+func (tb *TradeBase) RecordConfirmationSent(to []string, messageID, sentBy string) error {
+	if tb.Status != TradeStatusConfirmed {
+		return fmt.Errorf("trade %s is %s; only CONFIRMED trades are confirmed to the counterparty", tb.ID, tb.Status)
+	}
+	if len(to) == 0 {
+		return errors.New("a confirmation needs at least one recipient")
+	}
+
+	reason := "confirmation sent to " + strings.Join(to, ", ")
+	if messageID != "" {
+		reason += " (message " + messageID + ")"
+	}
+	tb.RecordStatusNote(reason, sentBy)
+	return nil
+}
@@ -0,0 +1,261 @@
+package confirmation
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"time"
+
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+//go:embed confirmation.html
+var confirmationTemplate string
+
+// TradeSource provides a trade with its breakdowns; *service.TradeService implements it.
+type TradeSource interface {
+	GetBookedTrade(ctx context.Context, id string) (*trade.BookedTrade, error)
+}
+
+// CompanySource provides the counterparty details; *repository.RdsCompanyRepository implements it.
+type CompanySource interface {
+	// FindByID returns nil, nil when the company does not exist
+	FindByID(ctx context.Context, id string) (*company.Company, error)
+}
+
+// Party is one side of a confirmation.
+type Party struct {
+	Name      string
+	Address   string
+	City      string
+	CoCNumber string
+}
+
+// Month is one row of the period breakdown table, formatted for display.
+type Month struct {
+	PeriodID    string
+	Delivery    string // e.g. 2026-03-15 – 2026-03-31
+	VolumeMT    string
+	PricePerMT  string
+	Amount      string
+	PriceStatus string
+	Fee         bool
+}
+
+// Recap holds everything a confirmation shows. It is the data of the HTML template, so other
+// renderers (e.g. a PDF service) can start from it too.
+type Recap struct {
+	TradeID          string
+	TradeNumber      string
+	CounterpartyID   string
+	Title            string // e.g. "Purchase confirmation"
+	Verb             string // e.g. "has bought from"
+	Issuer           Party
+	IssuerRole       string // Buyer or Seller
+	Counterparty     Party
+	CounterpartyRole string
+	Terms            []trade.Term
+	Months           []Month
+	Currency         string
+	TotalVolumeMT    string
+	TotalAmount      string
+	ConfirmedAt      string
+	GeneratedAt      string
+}
+
+// Document is a generated confirmation.
+type Document struct {
+	TradeID        string
+	TradeNumber    string
+	CounterpartyID string
+	Title          string // e.g. "Purchase confirmation"
+	Filename       string // e.g. P-2026-0001-confirmation.html
+	ContentType    string
+	Body           []byte
+}
+
+// Generator renders trade confirmations (recaps) as HTML.
+//
+// PURPOSE:
+//
+//	A confirmation is sent to the counterparty once a trade is CONFIRMED. It shows both
+//	parties, the contractual terms (see trade.ContractTerms) and the delivery months with
+//	their volume, price and amount:
+//
+//	    Purchase confirmation — Trade P-2026-0001
+//	    We confirm that Acme Trading has bought from Shell on the terms below.
+//	    ...
+//	    2026-JAN   2026-01-01 – 2026-01-31   10,000   3.50   35,000.00   FINAL
+//
+//	The HTML is self-contained (inline styles), so it can be mailed as is or printed to PDF.
+//
+// EXAMPLE USAGE:
+//
+//	gen, err := confirmation.NewGenerator(tradeService, companyRepo, confirmation.Party{Name: "Acme Trading B.V."})
+//	doc, err := gen.Generate(ctx, tradeID)
+//	os.WriteFile(doc.Filename, doc.Body, 0o644)
+type Generator struct {
+	trades    TradeSource
+	companies CompanySource
+	issuer    Party
+	tmpl      *template.Template
+	now       func() time.Time
+}
+
+func NewGenerator(trades TradeSource, companies CompanySource, issuer Party) (*Generator, error) {
+	tmpl, err := template.New("confirmation").Parse(confirmationTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse confirmation template: %w", err)
+	}
+
+	return &Generator{trades: trades, companies: companies, issuer: issuer, tmpl: tmpl, now: time.Now}, nil
+}
+
+// Generate renders the confirmation of a CONFIRMED trade.
+func (g *Generator) Generate(ctx context.Context, tradeID string) (*Document, error) {
+	recap, err := g.Recap(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := g.tmpl.Execute(&buf, recap); err != nil {
+		return nil, fmt.Errorf("failed to render confirmation of trade %s: %w", tradeID, err)
+	}
+
+	return &Document{
+		TradeID:        recap.TradeID,
+		TradeNumber:    recap.TradeNumber,
+		CounterpartyID: recap.CounterpartyID,
+		Title:          recap.Title,
+		Filename:       recap.TradeNumber + "-confirmation.html",
+		ContentType:    "text/html; charset=utf-8",
+		Body:           buf.Bytes(),
+	}, nil
+}
+
+// Recap loads a CONFIRMED trade with its counterparty and builds the data of its confirmation.
+func (g *Generator) Recap(ctx context.Context, tradeID string) (*Recap, error) {
+	bt, err := g.trades.GetBookedTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	tb := bt.Trade.Base()
+	if tb.Status != trade.TradeStatusConfirmed {
+		return nil, fmt.Errorf("trade %s is %s; only CONFIRMED trades are confirmed to the counterparty", tb.ID, tb.Status)
+	}
+
+	cp, err := g.companies.FindByID(ctx, bt.Trade.CounterpartyID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load counterparty %s: %w", bt.Trade.CounterpartyID(), err)
+	}
+	if cp == nil {
+		return nil, fmt.Errorf("counterparty %s of trade %s does not exist", bt.Trade.CounterpartyID(), tb.ID)
+	}
+
+	return BuildRecap(bt, cp, g.issuer, g.now()), nil
+}
+
+// BuildRecap builds the confirmation data of a trade.
+func BuildRecap(bt *trade.BookedTrade, cp *company.Company, issuer Party, generatedAt time.Time) *Recap {
+	tb := bt.Trade.Base()
+	number := tb.TradeNumber
+	if number == "" {
+		number = tb.ID
+	}
+
+	r := &Recap{
+		TradeID:        tb.ID,
+		TradeNumber:    number,
+		CounterpartyID: bt.Trade.CounterpartyID(),
+		Issuer:         issuer,
+		Counterparty: Party{
+			Name:      cp.ReportingName(),
+			Address:   cp.Address,
+			City:      cp.City,
+			CoCNumber: cp.CoCNumber,
+		},
+		Terms:       trade.ContractTerms(bt.Trade),
+		Currency:    tb.Currency,
+		ConfirmedAt: confirmedAt(tb).Format("2006-01-02"),
+		GeneratedAt: generatedAt.UTC().Format("2006-01-02 15:04 MST"),
+	}
+
+	switch bt.Trade.Kind() {
+	case trade.TradeKindPurchase:
+		r.Title, r.Verb, r.IssuerRole, r.CounterpartyRole = "Purchase confirmation", "has bought from", "Buyer", "Seller"
+	case trade.TradeKindSale:
+		r.Title, r.Verb, r.IssuerRole, r.CounterpartyRole = "Sale confirmation", "has sold to", "Seller", "Buyer"
+	case trade.TradeKindSwap:
+		r.Title, r.Verb, r.IssuerRole, r.CounterpartyRole = "Swap confirmation", "has entered into a swap with", "Party A", "Party B"
+	default:
+		r.Title, r.Verb, r.IssuerRole, r.CounterpartyRole = "Storage agreement confirmation", "has agreed storage with", "Customer", "Provider"
+	}
+
+	breakdowns := append([]trade.TradeBreakdown(nil), bt.Breakdowns...)
+	sort.SliceStable(breakdowns, func(i, j int) bool { return breakdowns[i].StartDate.Before(breakdowns[j].StartDate) })
+
+	var volume, amount float64
+	for _, bd := range breakdowns {
+		if bd.IsReversal() {
+			continue
+		}
+		volume += bd.VolumeMT
+		amount += bd.TotalAmount
+		r.Months = append(r.Months, Month{
+			PeriodID:    bd.PeriodID,
+			Delivery:    bd.StartDate.Format("2006-01-02") + " – " + bd.EndDate.Format("2006-01-02"),
+			VolumeMT:    formatAmount(bd.VolumeMT, 0),
+			PricePerMT:  formatAmount(bd.PricePerMT, 2),
+			Amount:      formatAmount(bd.TotalAmount, 2),
+			PriceStatus: string(bd.PriceStatus),
+			Fee:         bd.IsFee(),
+		})
+	}
+	r.TotalVolumeMT = formatAmount(volume, 0)
+	r.TotalAmount = formatAmount(amount, 2)
+	return r
+}
+
+// confirmedAt returns when the trade became CONFIRMED, falling back to its last update.
+func confirmedAt(tb *trade.TradeBase) time.Time {
+	for i := len(tb.StatusAudit) - 1; i >= 0; i-- {
+		if h := tb.StatusAudit[i]; h.NewStatus == trade.TradeStatusConfirmed && h.OldStatus != trade.TradeStatusConfirmed {
+			return h.ChangedAt
+		}
+	}
+	if tb.AuditInfo.UpdatedAt != nil {
+		return *tb.AuditInfo.UpdatedAt
+	}
+	return tb.AuditInfo.CreatedAt
+}
+
+// formatAmount formats a number with thousands separators, e.g. 35000 → "35,000.00".
+func formatAmount(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s, ""
+	for i := range s {
+		if s[i] == '.' {
+			intPart, frac = s[:i], s[i:]
+			break
+		}
+	}
+
+	var out []byte
+	for i := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, intPart[i])
+	}
+	return sign + string(out) + frac
+}
@@ -0,0 +1,99 @@
+package confirmation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// EmailSender sends HTML email; *awsclient.SESClient implements it.
+type EmailSender interface {
+	SendEmail(ctx context.Context, from string, to []string, subject, htmlBody string) (messageID string, err error)
+}
+
+// ContactSource provides the addresses confirmations of a counterparty are sent to;
+// *service.CompanyService implements it from the company's contact persons.
+type ContactSource interface {
+	// ConfirmationContacts returns the email addresses of a company's confirmation contacts;
+	// empty when none are known
+	ConfirmationContacts(ctx context.Context, companyID string) ([]string, error)
+}
+
+// StaticContacts is a ContactSource backed by a fixed map of company ID to addresses, e.g.
+// contacts read from configuration.
+type StaticContacts map[string][]string
+
+func (c StaticContacts) ConfirmationContacts(_ context.Context, companyID string) ([]string, error) {
+	return c[companyID], nil
+}
+
+// SendRecorder records a sent confirmation on the trade; *service.TradeService implements it.
+type SendRecorder interface {
+	RecordConfirmationSent(ctx context.Context, tradeID string, to []string, messageID string, booking trade.BookingRequest) error
+}
+
+// Delivery is a sent confirmation.
+type Delivery struct {
+	TradeID   string   `json:"tradeId"`
+	To        []string `json:"to"`
+	MessageID string   `json:"messageId"`
+}
+
+// Mailer sends trade confirmations (recaps) to the counterparty.
+//
+// PURPOSE:
+//
+//	Once a trade is CONFIRMED the recap goes to the confirmation contacts of the counterparty:
+//
+//	  1. Generate the confirmation (see Generator)
+//	  2. Send it as the HTML body of an email, e.g. through SES
+//	  3. Record the send in the trade's status history, with the recipients and message ID
+//
+//	When the email was sent but recording it fails, the error says so: sending again would
+//	mail the counterparty twice.
+//
+// EXAMPLE USAGE:
+//
+//	ses, _ := awsclient.NewSESClient(cfg)
+//	mailer := confirmation.NewMailer(generator, contacts, ses, tradeService, "confirmations@acme.example")
+//	delivery, err := mailer.Send(ctx, tradeID, trade.BookingRequest{User: "user@internal.local"})
+type Mailer struct {
+	generator *Generator
+	contacts  ContactSource
+	sender    EmailSender
+	recorder  SendRecorder
+	from      string
+}
+
+func NewMailer(generator *Generator, contacts ContactSource, sender EmailSender, recorder SendRecorder, from string) *Mailer {
+	return &Mailer{generator: generator, contacts: contacts, sender: sender, recorder: recorder, from: from}
+}
+
+// Send generates the confirmation of a CONFIRMED trade, mails it to the counterparty's
+// confirmation contacts and records the send.
+func (m *Mailer) Send(ctx context.Context, tradeID string, booking trade.BookingRequest) (*Delivery, error) {
+	doc, err := m.generator.Generate(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := m.contacts.ConfirmationContacts(ctx, doc.CounterpartyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load confirmation contacts of %s: %w", doc.CounterpartyID, err)
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("counterparty %s of trade %s has no confirmation contacts", doc.CounterpartyID, doc.TradeNumber)
+	}
+
+	messageID, err := m.sender.SendEmail(ctx, m.from, to, doc.Title+" "+doc.TradeNumber, string(doc.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the confirmation of trade %s: %w", doc.TradeNumber, err)
+	}
+
+	if err := m.recorder.RecordConfirmationSent(ctx, tradeID, to, messageID, booking); err != nil {
+		return nil, fmt.Errorf("confirmation of trade %s was sent (message %s) but not recorded: %w", doc.TradeNumber, messageID, err)
+	}
+
+	return &Delivery{TradeID: tradeID, To: to, MessageID: messageID}, nil
+}
@@ -0,0 +1,129 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CostType is the kind of cost attached to a trade.
+//
+// BROKER_FEE: commission of the broker who arranged the deal.
+// INSPECTION: independent quantity and quality inspection (surveyor).
+// FREIGHT:    transport of the cargo, when not included in the price.
+// INSURANCE:  cargo insurance.
+const (
+	CostBrokerFee  CostType = "BROKER_FEE"
+	CostInspection CostType = "INSPECTION"
+	CostFreight    CostType = "FREIGHT"
+	CostInsurance  CostType = "INSURANCE"
+)
+
+type CostType string
+
+// CostBasis tells how a cost component is charged.
+//
+// PER_MT:   Amount per metric tonne delivered, e.g. a broker fee of 0.25 EUR/MT.
+// LUMP_SUM: Amount once for the whole trade, e.g. 12,000 EUR of freight; it is spread over
+//
+//	the breakdowns in proportion to their volume.
+const (
+	CostPerMT   CostBasis = "PER_MT"
+	CostLumpSum CostBasis = "LUMP_SUM"
+)
+
+type CostBasis string
+
+// CostComponent is one cost of a trade, paid by us on top of the purchase price or deducted
+// from the sale proceeds.
+//
+// Example (broker fee of 0.25 EUR/MT and 12,000 EUR of freight):
+//
+//	tb.Costs = []CostComponent{
+//	    {Type: CostBrokerFee, Basis: CostPerMT, Amount: 0.25},
+//	    {Type: CostFreight, Basis: CostLumpSum, Amount: 12000, Description: "MT Nordic Star"},
+//	}
+type CostComponent struct {
+	Type        CostType  `json:"type"`
+	Basis       CostBasis `json:"basis"`
+	Amount      float64   `json:"amount"`             // Per MT or in total, see Basis
+	Currency    string    `json:"currency,omitempty"` // Empty means the trade currency
+	Description string    `json:"description,omitempty"`
+}
+
+// Validate checks that a cost component is complete and charged in the trade currency.
+func (c *CostComponent) Validate(tradeCurrency string) error {
+	var errs []error
+	switch c.Type {
+	case CostBrokerFee, CostInspection, CostFreight, CostInsurance:
+	default:
+		errs = append(errs, fmt.Errorf("invalid cost type %q", c.Type))
+	}
+	switch c.Basis {
+	case CostPerMT, CostLumpSum:
+	default:
+		errs = append(errs, fmt.Errorf("invalid cost basis %q", c.Basis))
+	}
+	if c.Amount < 0 {
+		errs = append(errs, fmt.Errorf("%s amount must not be negative, got %v", c.Type, c.Amount))
+	}
+	if c.Currency != "" && !strings.EqualFold(c.Currency, tradeCurrency) {
+		errs = append(errs, fmt.Errorf("%s is charged in %s; costs must be in the trade currency %s", c.Type, c.Currency, tradeCurrency))
+	}
+	return errors.Join(errs...)
+}
+
+// AllocateCosts fills the CostAmount of every breakdown from the trade's cost components:
+// PER_MT costs by the breakdown's volume, LUMP_SUM costs in proportion to its share of the
+// total volume. The demurrage of a month (see DemurrageClaims) is added to its delivery
+// breakdowns; demurrage claimed from the counterparty reduces the costs. Reversal entries are
+// left alone; they carry the negated cost of the breakdown they reverse.
+//
+// Example (3 months × 10,000 MT, broker fee 0.25/MT, freight 12,000 lump sum):
+//
+//	AllocateCosts(tb, breakdowns)
+//	// breakdowns[i].CostAmount = 10000 × 0.25 + 12000 / 3 = 6500
+func AllocateCosts(tb *TradeBase, breakdowns []TradeBreakdown) {
+	var totalMT float64
+	monthMT := make(map[string]float64)
+	for _, bd := range breakdowns {
+		if !bd.IsReversal() {
+			totalMT += bd.VolumeMT
+			if !bd.IsFee() {
+				monthMT[bd.PeriodID] += bd.VolumeMT
+			}
+		}
+	}
+	demurrage := demurrageByMonth(tb)
+
+	for i := range breakdowns {
+		bd := &breakdowns[i]
+		if bd.IsReversal() {
+			continue
+		}
+
+		bd.CostAmount = 0
+		for _, c := range tb.Costs {
+			switch c.Basis {
+			case CostPerMT:
+				bd.CostAmount += c.Amount * bd.VolumeMT
+			case CostLumpSum:
+				if totalMT > 0 {
+					bd.CostAmount += c.Amount * bd.VolumeMT / totalMT
+				}
+			}
+		}
+		if d, ok := demurrage[bd.PeriodID]; ok && !bd.IsFee() && monthMT[bd.PeriodID] > 0 {
+			bd.CostAmount += d * bd.VolumeMT / monthMT[bd.PeriodID]
+		}
+	}
+}
+
+// TotalCosts returns the sum of the costs allocated to breakdowns, reversals included.
+func TotalCosts(breakdowns []TradeBreakdown) float64 {
+	var total float64
+	for _, bd := range breakdowns {
+		total += bd.CostAmount
+	}
+	return total
+}
@@ -0,0 +1,231 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// DemurrageSide tells who bears the demurrage of a delivery.
+type DemurrageSide string
+
+const (
+	DemurragePayable    DemurrageSide = "PAYABLE"    // We pay it, e.g. the vessel waited at our terminal
+	DemurrageReceivable DemurrageSide = "RECEIVABLE" // We claim it from the counterparty
+)
+
+// VesselCall is the vessel and laytime data of one delivery month: which vessel loaded or
+// discharged the cargo, how long it was allowed to take and how long it took.
+//
+// Laytime commences NoticeHours after the notice of readiness (NOR), but not before the laycan
+// of the month opens, and stops at Completed (hoses off). ExceptedHours do not count, e.g.
+// bad weather or shifting berth.
+//
+// Example (36 hours allowed, NOR 2026-03-10 08:00, 6 hours notice, completed 2026-03-12 14:00):
+//
+//	tb.VesselCalls["2026-MAR"] = VesselCall{Vessel: "MT Nordic Star", AllowedLaytimeHours: 36,
+//	    NoticeOfReadiness: nor, NoticeHours: 6, Completed: &done, DemurrageRatePerDay: 24000,
+//	    Side: DemurragePayable}
+//	// → 48 hours used, 12 hours on demurrage, 12,000 of demurrage
+type VesselCall struct {
+	Vessel              string        `json:"vessel"`
+	IMO                 string        `json:"imo,omitempty"`
+	AllowedLaytimeHours float64       `json:"allowedLaytimeHours"`
+	NoticeOfReadiness   time.Time     `json:"noticeOfReadiness"`
+	NoticeHours         float64       `json:"noticeHours,omitempty"` // Hours after NOR before laytime counts, customarily 6
+	Completed           *time.Time    `json:"completed,omitempty"`   // Hoses off; nil while the vessel is still alongside
+	ExceptedHours       float64       `json:"exceptedHours,omitempty"`
+	DemurrageRatePerDay float64       `json:"demurrageRatePerDay"` // In the trade currency, pro rata per hour
+	Side                DemurrageSide `json:"side"`
+}
+
+// Validate checks that a vessel call is complete and its times are in order.
+func (v *VesselCall) Validate() error {
+	var errs []error
+	if v.Vessel == "" {
+		errs = append(errs, errors.New("the vessel name is required"))
+	}
+	if v.NoticeOfReadiness.IsZero() {
+		errs = append(errs, errors.New("the notice of readiness time is required"))
+	}
+	if v.AllowedLaytimeHours < 0 || v.NoticeHours < 0 || v.ExceptedHours < 0 {
+		errs = append(errs, fmt.Errorf("laytime hours must not be negative, got allowed %v, notice %v, excepted %v",
+			v.AllowedLaytimeHours, v.NoticeHours, v.ExceptedHours))
+	}
+	if v.DemurrageRatePerDay < 0 {
+		errs = append(errs, fmt.Errorf("the demurrage rate must not be negative, got %v", v.DemurrageRatePerDay))
+	}
+	if v.Completed != nil && v.Completed.Before(v.NoticeOfReadiness) {
+		errs = append(errs, errors.New("the vessel cannot complete before it tendered its notice of readiness"))
+	}
+	switch v.Side {
+	case DemurragePayable, DemurrageReceivable:
+	default:
+		errs = append(errs, fmt.Errorf("invalid demurrage side %q", v.Side))
+	}
+	return errors.Join(errs...)
+}
+
+// LaytimeStart returns when laytime commences: NoticeHours after the notice of readiness, or
+// when the laycan opens if the vessel tendered early. A nil laycan only applies the notice.
+func (v *VesselCall) LaytimeStart(laycan *Laycan) time.Time {
+	start := v.NoticeOfReadiness.Add(time.Duration(v.NoticeHours * float64(time.Hour)))
+	if laycan != nil && start.Before(laycan.Start) {
+		return laycan.Start
+	}
+	return start
+}
+
+// UsedLaytimeHours returns the laytime used from its start until completion, less the excepted
+// hours; 0 while the vessel has not completed.
+func (v *VesselCall) UsedLaytimeHours(laycan *Laycan) float64 {
+	if v.Completed == nil {
+		return 0
+	}
+	return math.Max(0, v.Completed.Sub(v.LaytimeStart(laycan)).Hours()-v.ExceptedHours)
+}
+
+// DemurrageClaim is the demurrage of one delivery month: the laytime used beyond the allowed
+// laytime, at the demurrage rate.
+type DemurrageClaim struct {
+	TradeID      string        `json:"tradeId"`
+	TradeNumber  string        `json:"tradeNumber,omitempty"`
+	PeriodID     string        `json:"periodId"`
+	Vessel       string        `json:"vessel"`
+	Side         DemurrageSide `json:"side"`
+	AllowedHours float64       `json:"allowedHours"`
+	UsedHours    float64       `json:"usedHours"`
+	ExcessHours  float64       `json:"excessHours"`
+	RatePerDay   float64       `json:"ratePerDay"`
+	Amount       float64       `json:"amount"` // Always positive; Side tells who pays
+	Currency     string        `json:"currency"`
+}
+
+// CostAmount returns the claim as a cost of the trade: positive when we pay it, negative when
+// we claim it from the counterparty.
+func (c DemurrageClaim) CostAmount() float64 {
+	if c.Side == DemurrageReceivable {
+		return -c.Amount
+	}
+	return c.Amount
+}
+
+// DemurrageClaims
+//
+// PURPOSE:
+//
+//	Calculates the demurrage of every completed vessel call of a trade, by delivery month:
+//
+//	  2026-MAR  MT Nordic Star  allowed 36h  used 48h  excess 12h × 24,000/day  = 12,000 PAYABLE
+//
+//	Calls within the allowed laytime, or not completed yet, produce no claim. Amounts are in
+//	the trade currency, rounded to cents.
+//
+// EXAMPLE USAGE:
+//
+//	claims := trade.DemurrageClaims(t.Base())
+func DemurrageClaims(tb *TradeBase) []DemurrageClaim {
+	var claims []DemurrageClaim
+	for _, periodID := range slices.Sorted(maps.Keys(tb.VesselCalls)) {
+		v := tb.VesselCalls[periodID]
+		if v.Completed == nil {
+			continue
+		}
+		var laycan *Laycan
+		if l, ok := tb.Laycans[periodID]; ok {
+			laycan = &l
+		}
+		used := v.UsedLaytimeHours(laycan)
+		excess := used - v.AllowedLaytimeHours
+		if excess <= 0 {
+			continue
+		}
+		claims = append(claims, DemurrageClaim{
+			TradeID:      tb.ID,
+			TradeNumber:  tb.TradeNumber,
+			PeriodID:     periodID,
+			Vessel:       v.Vessel,
+			Side:         v.Side,
+			AllowedHours: v.AllowedLaytimeHours,
+			UsedHours:    used,
+			ExcessHours:  excess,
+			RatePerDay:   v.DemurrageRatePerDay,
+			Amount:       math.Round(excess/24*v.DemurrageRatePerDay*100) / 100,
+			Currency:     tb.Currency,
+		})
+	}
+	return claims
+}
+
+// SetVesselCall
+//
+// PURPOSE:
+//
+//	Records the vessel and laytime data of one delivery month of a live trade, or clears it
+//	when call is nil, and reallocates the costs of the breakdowns so the month carries its
+//	demurrage (see AllocateCosts):
+//
+//	  2026-MAR  costs 6,500  → MT Nordic Star 12h on demurrage → costs 18,500
+//
+//	The call is kept in VesselCalls, so it survives rebuilt breakdowns. The trade must deliver
+//	in the month.
+//
+// EXAMPLE USAGE:
+//
+//	err := t.Base().SetVesselCall("2026-MAR", &call, breakdowns, "user@internal.local")
+func (tb *TradeBase) SetVesselCall(periodID string, call *VesselCall, breakdowns []TradeBreakdown, user string) error {
+	switch tb.Status {
+	case TradeStatusDraft, TradeStatusPending, TradeStatusConfirmed:
+	default:
+		return fmt.Errorf("trade %s is %s and cannot be scheduled", tb.ID, tb.Status)
+	}
+	if tb.IsDeleted() {
+		return fmt.Errorf("trade %s is deleted", tb.ID)
+	}
+
+	calls := maps.Clone(tb.VesselCalls)
+	if calls == nil {
+		calls = make(map[string]VesselCall)
+	}
+	if call == nil {
+		if _, ok := calls[periodID]; !ok {
+			return fmt.Errorf("trade %s has no vessel call in %s", tb.ID, periodID)
+		}
+		delete(calls, periodID)
+	} else {
+		if err := call.Validate(); err != nil {
+			return fmt.Errorf("vessel call of %s of trade %s: %w", periodID, tb.ID, err)
+		}
+		delivers := false
+		for _, bd := range LiveBreakdowns(breakdowns) {
+			if bd.PeriodID == periodID && !bd.IsReversal() && !bd.IsFee() {
+				delivers = true
+			}
+		}
+		if !delivers {
+			return fmt.Errorf("trade %s does not deliver in %s", tb.ID, periodID)
+		}
+		calls[periodID] = *call
+	}
+
+	tb.VesselCalls = calls
+	tb.AuditInfo.UpdateAuditInfo(user)
+	AllocateCosts(tb, breakdowns)
+	return nil
+}
+
+// demurrageByMonth returns the demurrage of each delivery month as a cost (see
+// DemurrageClaim.CostAmount).
+func demurrageByMonth(tb *TradeBase) map[string]float64 {
+	if len(tb.VesselCalls) == 0 {
+		return nil
+	}
+	months := make(map[string]float64)
+	for _, c := range DemurrageClaims(tb) {
+		months[c.PeriodID] += c.CostAmount()
+	}
+	return months
+}
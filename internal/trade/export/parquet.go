@@ -0,0 +1,210 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// DefaultParquetPrefix is the S3 key prefix of the breakdown dataset; the Athena table's LOCATION.
+const DefaultParquetPrefix = "analytics/trade_breakdowns/"
+
+// ObjectStore is the part of S3 that exporters need; *awsclient.S3Client implements it.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte, contentType string) (string, error)
+}
+
+// breakdownRow is the Parquet schema of one breakdown. PeriodID is not a column: it is the
+// partition key and lives in the object key (period_id=2026-JAN/), as Athena requires.
+type breakdownRow struct {
+	ID                string     `parquet:"id"`
+	BusinessKey       string     `parquet:"business_key"`
+	ParentTradeID     string     `parquet:"parent_trade_id"`
+	CounterpartyName  string     `parquet:"counterparty_name"`
+	StartDate         time.Time  `parquet:"start_date,timestamp(millisecond)"`
+	EndDate           time.Time  `parquet:"end_date,timestamp(millisecond)"`
+	MonthFraction     float64    `parquet:"month_fraction"`
+	VolumeMT          float64    `parquet:"volume_mt"`
+	Unit              string     `parquet:"unit"`
+	Quantity          float64    `parquet:"quantity"`
+	PricePerMT        float64    `parquet:"price_per_mt"`
+	PriceStatus       string     `parquet:"price_status"`
+	Currency          string     `parquet:"currency"`
+	TotalAmount       float64    `parquet:"total_amount"`
+	CostAmount        float64    `parquet:"cost_amount"`
+	Status            string     `parquet:"status"`    // LIVE or CANCELLED
+	Component         string     `parquet:"component"` // COMMODITY or AVAILABILITY_FEE
+	LaycanStart       *time.Time `parquet:"laycan_start,optional,timestamp(millisecond)"`
+	LaycanEnd         *time.Time `parquet:"laycan_end,optional,timestamp(millisecond)"`
+	ReportingCurrency string     `parquet:"reporting_currency"`
+	FXRate            float64    `parquet:"fx_rate"`
+	ReportingAmount   float64    `parquet:"reporting_amount"`
+	ReversalOfID      *string    `parquet:"reversal_of_id,optional"`
+	ReversedPeriodID  *string    `parquet:"reversed_period_id,optional"`
+	CreatedBy         string     `parquet:"created_by"`
+	CreatedAt         time.Time  `parquet:"created_at,timestamp(millisecond)"`
+	UpdatedBy         string     `parquet:"updated_by"`                        // created_by when never updated
+	UpdatedAt         time.Time  `parquet:"updated_at,timestamp(millisecond)"` // created_at when never updated
+}
+
+// ParquetPartition describes one exported period.
+type ParquetPartition struct {
+	PeriodID string
+	Key      string
+	Rows     int
+}
+
+// ParquetExporter writes the monthly breakdowns of the book to S3 as Parquet, one partition
+// per month, for querying with Athena.
+//
+// PURPOSE:
+//
+//	The analytics team queries the book without touching the OLTP database. Every month
+//	becomes a single Snappy-compressed Parquet file in a Hive-style partition:
+//
+//	    <prefix>period_id=2026-JAN/breakdowns.parquet
+//	    <prefix>period_id=2026-FEB/breakdowns.parquet
+//
+//	Exporting a month again overwrites its file, so re-running after an amend or a late trade
+//	is safe. The matching Athena table (with partition projection, so no MSCK REPAIR needed):
+//
+//	    CREATE EXTERNAL TABLE trade_breakdowns (id string, business_key string, parent_trade_id string,
+//	        counterparty_name string, start_date timestamp, end_date timestamp, month_fraction double,
+//	        volume_mt double, unit string, quantity double, price_per_mt double, price_status string,
+//	        currency string, total_amount double, cost_amount double, status string, component string,
+//	        laycan_start timestamp, laycan_end timestamp, reporting_currency string, fx_rate double,
+//	        reporting_amount double, reversal_of_id string, reversed_period_id string, created_by string,
+//	        created_at timestamp, updated_by string, updated_at timestamp)
+//	    PARTITIONED BY (period_id string)
+//	    STORED AS PARQUET
+//	    LOCATION 's3://<bucket>/analytics/trade_breakdowns/'
+//	    TBLPROPERTIES ('projection.enabled'='true', 'projection.period_id.type'='injected',
+//	        'storage.location.template'='s3://<bucket>/analytics/trade_breakdowns/period_id=${period_id}/')
+//
+// EXAMPLE USAGE:
+//
+//	exporter := export.NewParquetExporter(breakdownRepo, s3Client, export.DefaultParquetPrefix)
+//	partitions, err := exporter.ExportPeriods(ctx, []string{"2026-JAN", "2026-FEB"})
+type ParquetExporter struct {
+	breakdowns repository.BreakdownRepository
+	store      ObjectStore
+	prefix     string
+}
+
+func NewParquetExporter(breakdowns repository.BreakdownRepository, store ObjectStore, prefix string) *ParquetExporter {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &ParquetExporter{breakdowns: breakdowns, store: store, prefix: prefix}
+}
+
+// ExportPeriod writes the partition of one month, e.g. "2026-JAN". Reversal entries belong to
+// the month they were posted in. A month without breakdowns still gets an (empty) file, so a
+// stale export of that month is replaced.
+func (e *ParquetExporter) ExportPeriod(ctx context.Context, periodID string) (*ParquetPartition, error) {
+	breakdowns, err := e.breakdowns.GetBreakdownsByPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := EncodeBreakdownsParquet(breakdowns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode breakdowns of %s: %w", periodID, err)
+	}
+
+	key := fmt.Sprintf("%speriod_id=%s/breakdowns.parquet", e.prefix, periodID)
+	if _, err := e.store.PutObject(ctx, key, data, "application/vnd.apache.parquet"); err != nil {
+		return nil, err
+	}
+
+	return &ParquetPartition{PeriodID: periodID, Key: key, Rows: len(breakdowns)}, nil
+}
+
+// ExportPeriods exports several months and stops at the first failure.
+func (e *ParquetExporter) ExportPeriods(ctx context.Context, periodIDs []string) ([]ParquetPartition, error) {
+	partitions := make([]ParquetPartition, 0, len(periodIDs))
+	for _, id := range periodIDs {
+		p, err := e.ExportPeriod(ctx, id)
+		if err != nil {
+			return partitions, err
+		}
+		partitions = append(partitions, *p)
+	}
+
+	return partitions, nil
+}
+
+// EncodeBreakdownsParquet encodes breakdowns as a Snappy-compressed Parquet file.
+func EncodeBreakdownsParquet(breakdowns []trade.TradeBreakdown) ([]byte, error) {
+	rows := make([]breakdownRow, 0, len(breakdowns))
+	for _, bd := range breakdowns {
+		updatedAt := bd.AuditInfo.CreatedAt
+		if bd.AuditInfo.UpdatedAt != nil {
+			updatedAt = *bd.AuditInfo.UpdatedAt
+		}
+		rows = append(rows, breakdownRow{
+			ID:                bd.ID,
+			BusinessKey:       bd.BusinessKey,
+			ParentTradeID:     bd.ParentTradeID,
+			CounterpartyName:  bd.CounterpartyName,
+			StartDate:         bd.StartDate,
+			EndDate:           bd.EndDate,
+			MonthFraction:     bd.MonthFraction,
+			VolumeMT:          bd.VolumeMT,
+			Unit:              string(bd.Unit),
+			Quantity:          bd.Quantity,
+			PricePerMT:        bd.PricePerMT,
+			PriceStatus:       string(bd.PriceStatus),
+			Currency:          bd.Currency,
+			TotalAmount:       bd.TotalAmount,
+			CostAmount:        bd.CostAmount,
+			Status:            string(breakdownStatus(bd.Status)),
+			Component:         string(breakdownComponent(bd.Component)),
+			LaycanStart:       bd.LaycanStart,
+			LaycanEnd:         bd.LaycanEnd,
+			ReportingCurrency: bd.ReportingCurrency,
+			FXRate:            bd.FXRate,
+			ReportingAmount:   bd.ReportingAmount,
+			ReversalOfID:      bd.ReversalOfID,
+			ReversedPeriodID:  bd.ReversedPeriodID,
+			CreatedBy:         bd.AuditInfo.CreatedBy,
+			CreatedAt:         bd.AuditInfo.CreatedAt,
+			UpdatedBy:         bd.AuditInfo.LastActor(),
+			UpdatedAt:         updatedAt,
+		})
+	}
+
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[breakdownRow](&buf, parquet.Compression(&parquet.Snappy))
+	if _, err := w.Write(rows); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// breakdownComponent exports breakdowns without a component as COMMODITY.
+func breakdownComponent(c trade.BreakdownComponent) trade.BreakdownComponent {
+	if c == "" {
+		return trade.ComponentCommodity
+	}
+	return c
+}
+
+// breakdownStatus exports breakdowns without a status as LIVE.
+func breakdownStatus(s trade.BreakdownStatus) trade.BreakdownStatus {
+	if s == "" {
+		return trade.BreakdownLive
+	}
+	return s
+}
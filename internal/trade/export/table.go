@@ -0,0 +1,326 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// BookSource provides trades with their breakdowns; *service.TradeService implements it.
+type BookSource interface {
+	ListBookedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error)
+}
+
+// Column prefixes: trade.* columns describe the trade, breakdown.* columns one delivery month.
+const (
+	tradePrefix     = "trade."
+	breakdownPrefix = "breakdown."
+)
+
+// tradeColumns are the exportable trade fields, in their default order.
+var tradeColumns = []struct {
+	name  string
+	value func(t trade.Trade) any
+}{
+	{"trade.id", func(t trade.Trade) any { return t.Base().ID }},
+	{"trade.trade_number", func(t trade.Trade) any { return t.Base().TradeNumber }},
+	{"trade.kind", func(t trade.Trade) any { return string(t.Kind()) }},
+	{"trade.counterparty_id", func(t trade.Trade) any { return t.CounterpartyID() }},
+	{"trade.status", func(t trade.Trade) any { return string(t.Base().Status) }},
+	{"trade.start_period_id", func(t trade.Trade) any { return t.Base().PeriodRange.StartPeriodID }},
+	{"trade.end_period_id", func(t trade.Trade) any { return t.Base().PeriodRange.EndPeriodID }},
+	{"trade.delivery_start", func(t trade.Trade) any { return t.Base().DeliveryStart }},
+	{"trade.delivery_end", func(t trade.Trade) any { return t.Base().DeliveryEnd }},
+	{"trade.volume_mt", func(t trade.Trade) any { return t.Base().VolumeMT }},
+	{"trade.commodity", func(t trade.Trade) any { return t.Base().Commodity }},
+	{"trade.unit", func(t trade.Trade) any { return string(t.Base().Unit) }},
+	{"trade.quantity", func(t trade.Trade) any { return t.Base().Quantity }},
+	{"trade.price_per_mt", func(t trade.Trade) any { return t.Base().PricePerMT }},
+	{"trade.currency", func(t trade.Trade) any { return t.Base().Currency }},
+	{"trade.product_id", func(t trade.Trade) any { return t.Base().ProductID }},
+	{"trade.location_id", func(t trade.Trade) any { return t.Base().LocationID }},
+	{"trade.payment_terms", func(t trade.Trade) any {
+		if terms := t.Base().PaymentTerms; terms != nil {
+			return terms.String()
+		}
+		return ""
+	}},
+	{"trade.created_by", func(t trade.Trade) any { return t.Base().AuditInfo.CreatedBy }},
+	{"trade.created_at", func(t trade.Trade) any { return t.Base().AuditInfo.CreatedAt }},
+}
+
+// breakdownColumns are the exportable breakdown fields, in their default order.
+var breakdownColumns = []struct {
+	name  string
+	value func(bd *trade.TradeBreakdown) any
+}{
+	{"breakdown.id", func(bd *trade.TradeBreakdown) any { return bd.ID }},
+	{"breakdown.period_id", func(bd *trade.TradeBreakdown) any { return bd.PeriodID }},
+	{"breakdown.start_date", func(bd *trade.TradeBreakdown) any { return bd.StartDate }},
+	{"breakdown.end_date", func(bd *trade.TradeBreakdown) any { return bd.EndDate }},
+	{"breakdown.month_fraction", func(bd *trade.TradeBreakdown) any { return bd.MonthFraction }},
+	{"breakdown.component", func(bd *trade.TradeBreakdown) any { return string(breakdownComponent(bd.Component)) }},
+	{"breakdown.status", func(bd *trade.TradeBreakdown) any { return string(breakdownStatus(bd.Status)) }},
+	{"breakdown.volume_mt", func(bd *trade.TradeBreakdown) any { return bd.VolumeMT }},
+	{"breakdown.quantity", func(bd *trade.TradeBreakdown) any { return bd.Quantity }},
+	{"breakdown.price_per_mt", func(bd *trade.TradeBreakdown) any { return bd.PricePerMT }},
+	{"breakdown.price_status", func(bd *trade.TradeBreakdown) any { return string(bd.PriceStatus) }},
+	{"breakdown.currency", func(bd *trade.TradeBreakdown) any { return bd.Currency }},
+	{"breakdown.total_amount", func(bd *trade.TradeBreakdown) any { return bd.TotalAmount }},
+	{"breakdown.cost_amount", func(bd *trade.TradeBreakdown) any { return bd.CostAmount }},
+	{"breakdown.reporting_currency", func(bd *trade.TradeBreakdown) any { return bd.ReportingCurrency }},
+	{"breakdown.reporting_amount", func(bd *trade.TradeBreakdown) any { return bd.ReportingAmount }},
+	{"breakdown.laycan_start", func(bd *trade.TradeBreakdown) any { return bd.LaycanStart }},
+	{"breakdown.laycan_end", func(bd *trade.TradeBreakdown) any { return bd.LaycanEnd }},
+	{"breakdown.reversal_of_id", func(bd *trade.TradeBreakdown) any { return bd.ReversalOfID }},
+}
+
+// TableColumns returns the names of all exportable columns, in their default order.
+func TableColumns() []string {
+	names := make([]string, 0, len(tradeColumns)+len(breakdownColumns))
+	for _, c := range tradeColumns {
+		names = append(names, c.name)
+	}
+	for _, c := range breakdownColumns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// TableOptions selects what a table export contains.
+//
+// Example (price and volume of every live purchase, per month of Q1 2026):
+//
+//	TableOptions{
+//	    Filter:  repository.TradeFilter{Kind: trade.TradeKindPurchase, PeriodID: "2026-Q1"},
+//	    Columns: []string{"trade.trade_number", "trade.counterparty_id", "breakdown.period_id",
+//	        "breakdown.volume_mt", "breakdown.total_amount"},
+//	}
+type TableOptions struct {
+	// Filter selects the trades. A Filter.PeriodID also narrows the breakdowns to the months
+	// within that period.
+	Filter repository.TradeFilter
+	// Columns lists the exported columns in order (see TableColumns); empty means all.
+	Columns []string
+}
+
+// TableExporter writes trades and their breakdowns as CSV or JSON, e.g. for auditors and
+// spreadsheets.
+//
+// PURPOSE:
+//
+//	CSV is flat: one row per breakdown, with the trade columns repeated on each row. Trades
+//	without breakdowns (swaps) get one row with empty breakdown columns. Without breakdown
+//	columns the export has one row per trade:
+//
+//	    trade.trade_number,breakdown.period_id,breakdown.volume_mt
+//	    P-2026-0001,2026-JAN,10000
+//	    P-2026-0001,2026-FEB,10000
+//
+//	JSON nests the breakdowns under their trade, with the prefix dropped from the names:
+//
+//	    [{"trade_number": "P-2026-0001", "breakdowns": [{"period_id": "2026-JAN", "volume_mt": 10000}, ...]}]
+//
+//	Dates are written as RFC 3339 in both formats.
+//
+// EXAMPLE USAGE:
+//
+//	exporter := export.NewTableExporter(tradeService, periodService.GetPeriodStore())
+//	rows, err := exporter.WriteCSV(ctx, w, export.TableOptions{Filter: repository.TradeFilter{PeriodID: "2026"}})
+type TableExporter struct {
+	source  BookSource
+	periods *period.PeriodStore
+}
+
+func NewTableExporter(source BookSource, periods *period.PeriodStore) *TableExporter {
+	return &TableExporter{source: source, periods: periods}
+}
+
+// WriteCSV writes the export as CSV with a header row and returns the number of data rows.
+func (e *TableExporter) WriteCSV(ctx context.Context, w io.Writer, opts TableOptions) (int, error) {
+	tbl, err := e.load(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(tbl.columns); err != nil {
+		return 0, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	rows := 0
+	write := func(t trade.Trade, bd *trade.TradeBreakdown) error {
+		record := make([]string, len(tbl.columns))
+		for i, col := range tbl.columns {
+			record[i] = formatCSV(tbl.value(col, t, bd))
+		}
+		rows++
+		return cw.Write(record)
+	}
+	for _, bt := range tbl.trades {
+		if !tbl.withBreakdowns || len(bt.Breakdowns) == 0 {
+			if err := write(bt.Trade, nil); err != nil {
+				return rows, fmt.Errorf("failed to write CSV row: %w", err)
+			}
+			continue
+		}
+		for i := range bt.Breakdowns {
+			if err := write(bt.Trade, &bt.Breakdowns[i]); err != nil {
+				return rows, fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return rows, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return rows, nil
+}
+
+// WriteJSON writes the export as a JSON array of trades and returns the number of trades.
+func (e *TableExporter) WriteJSON(ctx context.Context, w io.Writer, opts TableOptions) (int, error) {
+	tbl, err := e.load(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	out := make([]map[string]any, 0, len(tbl.trades))
+	for _, bt := range tbl.trades {
+		obj := make(map[string]any)
+		var bdCols []string
+		for _, col := range tbl.columns {
+			if name, ok := strings.CutPrefix(col, tradePrefix); ok {
+				obj[name] = tbl.value(col, bt.Trade, nil)
+			} else {
+				bdCols = append(bdCols, col)
+			}
+		}
+		if tbl.withBreakdowns {
+			breakdowns := make([]map[string]any, 0, len(bt.Breakdowns))
+			for i := range bt.Breakdowns {
+				bd := make(map[string]any, len(bdCols))
+				for _, col := range bdCols {
+					bd[strings.TrimPrefix(col, breakdownPrefix)] = tbl.value(col, bt.Trade, &bt.Breakdowns[i])
+				}
+				breakdowns = append(breakdowns, bd)
+			}
+			obj["breakdowns"] = breakdowns
+		}
+		out = append(out, obj)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return 0, fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return len(out), nil
+}
+
+// table is a loaded export.
+type table struct {
+	columns        []string
+	trades         []trade.BookedTrade
+	withBreakdowns bool
+	tradeValues    map[string]func(t trade.Trade) any
+	bdValues       map[string]func(bd *trade.TradeBreakdown) any
+}
+
+func (tbl *table) value(col string, t trade.Trade, bd *trade.TradeBreakdown) any {
+	if f, ok := tbl.tradeValues[col]; ok {
+		return f(t)
+	}
+	if bd == nil {
+		return nil
+	}
+	return tbl.bdValues[col](bd)
+}
+
+// load checks the columns (filling in the default ones) and loads the trades, with their
+// breakdowns narrowed to the filter's period.
+func (e *TableExporter) load(ctx context.Context, opts TableOptions) (*table, error) {
+	tbl := &table{
+		tradeValues: make(map[string]func(t trade.Trade) any),
+		bdValues:    make(map[string]func(bd *trade.TradeBreakdown) any),
+	}
+	for _, c := range tradeColumns {
+		tbl.tradeValues[c.name] = c.value
+	}
+	for _, c := range breakdownColumns {
+		tbl.bdValues[c.name] = c.value
+	}
+
+	if len(opts.Columns) == 0 {
+		opts.Columns = TableColumns()
+	}
+	for _, col := range opts.Columns {
+		_, isTrade := tbl.tradeValues[col]
+		_, isBreakdown := tbl.bdValues[col]
+		if !isTrade && !isBreakdown {
+			return nil, fmt.Errorf("unknown export column %q", col)
+		}
+		tbl.withBreakdowns = tbl.withBreakdowns || isBreakdown
+	}
+	tbl.columns = opts.Columns
+
+	var within *period.Period
+	if opts.Filter.PeriodID != "" {
+		within = e.periods.FindByID(opts.Filter.PeriodID)
+		if within == nil {
+			return nil, fmt.Errorf("unknown period %s", opts.Filter.PeriodID)
+		}
+	}
+
+	trades, err := e.source.ListBookedTrades(ctx, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades for export: %w", err)
+	}
+	if within != nil {
+		for i := range trades {
+			var kept []trade.TradeBreakdown
+			for _, bd := range trades[i].Breakdowns {
+				if !bd.StartDate.Before(within.StartDate) && !bd.StartDate.After(within.EndDate) {
+					kept = append(kept, bd)
+				}
+			}
+			trades[i].Breakdowns = kept
+		}
+	}
+	tbl.trades = trades
+	return tbl, nil
+}
+
+// formatCSV renders a column value as CSV text.
+func formatCSV(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,201 @@
+package trade
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldChange is one field that differs between two consecutive versions of a trade.
+// Values are formatted for display; an empty string means "not set".
+type FieldChange struct {
+	Field string `json:"field"` // JSON name of the field, e.g. "pricePerMT"
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TradeVersion is one entry of a trade's version history: the trade as stored, plus what
+// changed compared to the version before it (nil for the first version).
+type TradeVersion struct {
+	Trade   Trade         `json:"trade"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// DiffTrades lists the contractual fields that differ between two versions of a trade, in a
+// fixed order. Bookkeeping fields (IDs, status, audit info, row version) are not compared:
+// they always differ between versions.
+//
+// Example (price corrected from 3.5 to 3.6):
+//
+//	DiffTrades(v1, v2) // → [{Field: "pricePerMT", Old: "3.5", New: "3.6"}]
+func DiffTrades(prev, next Trade) []FieldChange {
+	a, b := prev.Base(), next.Base()
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"counterpartyId", prev.CounterpartyID(), next.CounterpartyID()},
+		{"startPeriodId", a.PeriodRange.StartPeriodID, b.PeriodRange.StartPeriodID},
+		{"endPeriodId", a.PeriodRange.EndPeriodID, b.PeriodRange.EndPeriodID},
+		{"deliveryStart", formatDate(a.DeliveryStart), formatDate(b.DeliveryStart)},
+		{"deliveryEnd", formatDate(a.DeliveryEnd), formatDate(b.DeliveryEnd)},
+		{"breakdownMode", string(breakdownModeOrDefault(a.BreakdownMode)), string(breakdownModeOrDefault(b.BreakdownMode))},
+		{"allocationMode", string(a.Allocation()), string(b.Allocation())},
+		{"commodity", a.Commodity, b.Commodity},
+		{"productId", a.ProductID, b.ProductID},
+		{"locationId", a.LocationID, b.LocationID},
+		{"unit", string(a.BookedUnit()), string(b.BookedUnit())},
+		{"quantity", formatFloat(a.BookedQuantity()), formatFloat(b.BookedQuantity())},
+		{"volumeMT", formatFloat(a.VolumeMT), formatFloat(b.VolumeMT)},
+		{"pricePerMT", formatFloat(a.PricePerMT), formatFloat(b.PricePerMT)},
+		{"pricing", formatPricing(a.Pricing), formatPricing(b.Pricing)},
+		{"costs", formatCosts(a.Costs), formatCosts(b.Costs)},
+		{"availabilityFeePerMT", formatFloat(a.AvailabilityFeePerMT), formatFloat(b.AvailabilityFeePerMT)},
+		{"takeOrPay", formatTakeOrPay(a.TakeOrPay), formatTakeOrPay(b.TakeOrPay)},
+		{"tolerance", formatTolerance(a.Tolerance), formatTolerance(b.Tolerance)},
+		{"declaredVolumes", formatDeclared(a.DeclaredVolumes), formatDeclared(b.DeclaredVolumes)},
+		{"laycans", formatLaycans(a.Laycans), formatLaycans(b.Laycans)},
+		{"vesselCalls", formatVesselCalls(a.VesselCalls), formatVesselCalls(b.VesselCalls)},
+		{"paymentTerms", formatPaymentTerms(a.PaymentTerms), formatPaymentTerms(b.PaymentTerms)},
+		{"currency", a.Currency, b.Currency},
+		{"cancelledPeriodIds", strings.Join(a.CancelledPeriodIDs, ", "), strings.Join(b.CancelledPeriodIDs, ", ")},
+	}
+
+	var changes []FieldChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, FieldChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// BuildVersionHistory turns a version chain, oldest first, into its history with the diffs
+// between consecutive versions.
+func BuildVersionHistory(chain []Trade) []TradeVersion {
+	history := make([]TradeVersion, 0, len(chain))
+	for i, t := range chain {
+		v := TradeVersion{Trade: t}
+		if i > 0 {
+			v.Changes = DiffTrades(chain[i-1], t)
+		}
+		history = append(history, v)
+	}
+	return history
+}
+
+func breakdownModeOrDefault(m BreakdownMode) BreakdownMode {
+	if m == "" {
+		return BreakdownFullMonths
+	}
+	return m
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatTolerance(v *VolumeTolerance) string {
+	if v == nil {
+		return ""
+	}
+	return "+" + formatFloat(v.PlusPct) + "%/-" + formatFloat(v.MinusPct) + "% " + string(v.Option)
+}
+
+// formatDeclared lists declared volumes by month, e.g. "2026-FEB: 9800, 2026-JAN: 10400".
+func formatDeclared(declared map[string]float64) string {
+	parts := make([]string, 0, len(declared))
+	for _, id := range slices.Sorted(maps.Keys(declared)) {
+		parts = append(parts, id+": "+formatFloat(declared[id]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatLaycans lists laycans by month, e.g. "2026-MAR: 2026-03-10..2026-03-12".
+func formatLaycans(laycans map[string]Laycan) string {
+	parts := make([]string, 0, len(laycans))
+	for _, id := range slices.Sorted(maps.Keys(laycans)) {
+		l := laycans[id]
+		parts = append(parts, id+": "+l.Start.Format("2006-01-02")+".."+l.End.Format("2006-01-02"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatVesselCalls lists vessel calls by month, e.g. "2026-MAR: MT Nordic Star NOR 2026-03-10 08:00
+// completed 2026-03-12 14:00".
+func formatVesselCalls(calls map[string]VesselCall) string {
+	parts := make([]string, 0, len(calls))
+	for _, id := range slices.Sorted(maps.Keys(calls)) {
+		v := calls[id]
+		s := id + ": " + v.Vessel + " NOR " + v.NoticeOfReadiness.Format("2006-01-02 15:04")
+		if v.Completed != nil {
+			s += " completed " + v.Completed.Format("2006-01-02 15:04")
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatPaymentTerms(p *PaymentTerms) string {
+	if p == nil {
+		return ""
+	}
+	return p.String()
+}
+
+func formatTakeOrPay(c *TakeOrPayClause) string {
+	if c == nil {
+		return ""
+	}
+	s := "min " + formatFloat(c.MinVolumeMT) + " MT " + string(c.Granularity)
+	if c.DeficiencyPricePerMT > 0 {
+		s += " @ " + formatFloat(c.DeficiencyPricePerMT)
+	}
+	if c.MakeUpPeriods > 0 {
+		s += ", make-up " + strconv.Itoa(c.MakeUpPeriods)
+	}
+	return s
+}
+
+// formatPricing renders a pricing formula, e.g. "MONTHLY_AVERAGE ICE-BRENT +1.2".
+func formatPricing(f *PricingFormula) string {
+	if f == nil {
+		return ""
+	}
+	s := string(f.Type)
+	if f.Index != "" {
+		s += " " + f.Index
+	}
+	if f.Premium > 0 {
+		s += " +" + formatFloat(f.Premium)
+	} else if f.Premium < 0 {
+		s += " " + formatFloat(f.Premium)
+	}
+	if f.PricingDay != nil {
+		s += " on " + formatDate(f.PricingDay)
+	}
+	return s
+}
+
+// formatCosts renders cost components, e.g. "BROKER_FEE 0.25/MT, FREIGHT 12000".
+func formatCosts(costs []CostComponent) string {
+	parts := make([]string, 0, len(costs))
+	for _, c := range costs {
+		s := string(c.Type) + " " + formatFloat(c.Amount)
+		if c.Basis == CostPerMT {
+			s += "/MT"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
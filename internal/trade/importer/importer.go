@@ -0,0 +1,377 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/exposure"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/service"
+	"github.com/nholding/cso-book/internal/units"
+)
+
+// Format is the file format of an import.
+type Format string
+
+const (
+	FormatCSV  Format = "CSV"
+	FormatXLSX Format = "XLSX"
+)
+
+// FormatFromFilename derives the format of an uploaded file from its extension.
+func FormatFromFilename(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("cannot import %s: only .csv and .xlsx files are supported", name)
+}
+
+// Booker books the imported trades; *service.TradeService implements it.
+type Booker interface {
+	CreatePurchase(ctx context.Context, req service.TradeRequest, booking trade.BookingRequest) (*trade.Purchase, []trade.TradeBreakdown, *exposure.Warning, error)
+	CreateSale(ctx context.Context, req service.TradeRequest, booking trade.BookingRequest) (*trade.Ticket, []trade.TradeBreakdown, *exposure.Warning, error)
+}
+
+// CompanyLister lists the counterparties an import may refer to; *repository.RdsCompanyRepository
+// implements it.
+type CompanyLister interface {
+	// List retrieves all companies that were not merged into another
+	List(ctx context.Context) ([]*company.Company, error)
+}
+
+// Columns of an import file. The header row names them, in any order and case; only kind,
+// counterparty, price_per_mt, currency, a volume and a delivery period are required.
+const (
+	ColumnReference      = "reference" // Free text echoed in the report, e.g. the deal number in the old system
+	ColumnKind           = "kind"      // PURCHASE or SALE
+	ColumnCounterparty   = "counterparty"
+	ColumnStartPeriod    = "start_period"
+	ColumnEndPeriod      = "end_period"
+	ColumnDeliveryStart  = "delivery_start" // YYYY-MM-DD, for custom-dated trades or PRO_RATA
+	ColumnDeliveryEnd    = "delivery_end"
+	ColumnBreakdownMode  = "breakdown_mode"
+	ColumnAllocationMode = "allocation_mode"
+	ColumnVolumeMT       = "volume_mt"
+	ColumnCommodity      = "commodity"
+	ColumnUnit           = "unit"
+	ColumnQuantity       = "quantity"
+	ColumnPricePerMT     = "price_per_mt"
+	ColumnCurrency       = "currency"
+	ColumnProductID      = "product_id"
+	ColumnLocationID     = "location_id"
+	ColumnPaymentTerms   = "payment_terms" // As quoted in the contract, e.g. "30 days after B/L"
+)
+
+var requiredColumns = []string{ColumnKind, ColumnCounterparty, ColumnPricePerMT, ColumnCurrency}
+
+// RowResult is the outcome of one data row. Line is the line (CSV) or row number (XLSX) in the
+// file, counting the header as 1.
+type RowResult struct {
+	Line        int      `json:"line"`
+	Reference   string   `json:"reference,omitempty"`
+	TradeID     string   `json:"tradeId,omitempty"`
+	TradeNumber string   `json:"tradeNumber,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"` // Booked anyway, e.g. near the credit limit
+}
+
+// Report is the outcome of an import, one result per data row.
+type Report struct {
+	Rows    int         `json:"rows"`
+	Booked  int         `json:"booked"`
+	Failed  int         `json:"failed"`
+	DryRun  bool        `json:"dryRun"`
+	Results []RowResult `json:"results"`
+}
+
+type Importer struct {
+	booker    Booker
+	companies CompanyLister
+	periods   *period.PeriodStore
+}
+
+func NewImporter(booker Booker, companies CompanyLister, periods *period.PeriodStore) *Importer {
+	return &Importer{booker: booker, companies: companies, periods: periods}
+}
+
+// Import
+//
+// PURPOSE:
+//
+//	Books the historical trades of a CSV or XLSX file, one trade per data row. Every row is
+//	checked before it is booked:
+//
+//	  - the period references exist in the PeriodStore
+//	  - the counterparty resolves to exactly one company, by ID, CoC number or name
+//	  - the numbers, dates, modes and unit parse
+//
+//	Rows are booked one by one through the TradeService, so every booking rule applies
+//	(booking window, duplicate check, credit). A failing row does not stop the import: its
+//	errors are reported and the next row is processed. With dryRun nothing is booked, so
+//	a file can be checked first.
+//
+//	Historical trades usually fall outside the booking window; pass a booking request with
+//	the override role and an OverrideReason, e.g. "migration from the old system".
+//
+// EXAMPLE USAGE:
+//
+//	f, _ := os.Open("trades-2024.csv")
+//	report, err := importer.Import(ctx, f, FormatCSV, booking, false)
+//	// report.Results[3] → {Line: 5, Reference: "OLD-118", Errors: ["unknown period 2024-FEBR"]}
+func (im *Importer) Import(ctx context.Context, r io.Reader, format Format, booking trade.BookingRequest, dryRun bool) (*Report, error) {
+	records, err := readRecords(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("import file is empty")
+	}
+
+	header := make(map[string]int)
+	for i, name := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, col := range requiredColumns {
+		if _, ok := header[col]; !ok {
+			return nil, fmt.Errorf("import file lacks the %s column", col)
+		}
+	}
+
+	companies, err := im.companies.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load counterparties: %w", err)
+	}
+
+	report := &Report{DryRun: dryRun}
+	for i, record := range records[1:] {
+		row := row{header: header, record: record}
+		if row.empty() {
+			continue
+		}
+
+		res := RowResult{Line: i + 2, Reference: row.get(ColumnReference)}
+		kind, req, errs := im.parse(row, companies)
+		if len(errs) == 0 && !dryRun {
+			id, number, warning, err := im.book(ctx, kind, req, booking)
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+			if warning != nil {
+				res.Warnings = append(res.Warnings, warning.Message)
+			}
+			res.TradeID, res.TradeNumber = id, number
+		}
+
+		res.Errors = errs
+		report.Rows++
+		if len(errs) > 0 {
+			report.Failed++
+		} else if !dryRun {
+			report.Booked++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+// parse turns one row into a trade request, collecting every problem of the row.
+func (im *Importer) parse(r row, companies []*company.Company) (trade.TradeKind, service.TradeRequest, []string) {
+	var errs []string
+	fail := func(format string, args ...any) { errs = append(errs, fmt.Sprintf(format, args...)) }
+
+	kind := trade.TradeKind(strings.ToUpper(r.get(ColumnKind)))
+	if kind != trade.TradeKindPurchase && kind != trade.TradeKindSale {
+		fail("kind must be %s or %s, got %q", trade.TradeKindPurchase, trade.TradeKindSale, r.get(ColumnKind))
+	}
+
+	req := service.TradeRequest{
+		Currency:       strings.ToUpper(r.get(ColumnCurrency)),
+		Commodity:      r.get(ColumnCommodity),
+		ProductID:      r.get(ColumnProductID),
+		LocationID:     r.get(ColumnLocationID),
+		BreakdownMode:  trade.BreakdownMode(strings.ToUpper(r.get(ColumnBreakdownMode))),
+		AllocationMode: trade.AllocationMode(strings.ToUpper(r.get(ColumnAllocationMode))),
+	}
+
+	cp, err := resolveCounterparty(r.get(ColumnCounterparty), companies)
+	if err != nil {
+		fail("%v", err)
+	} else {
+		req.CounterpartyID = cp.ID
+	}
+
+	start, end := r.get(ColumnStartPeriod), r.get(ColumnEndPeriod)
+	if start != "" && end == "" {
+		end = start
+	}
+	if start != "" && im.periods.FindByID(start) == nil {
+		fail("unknown period %s", start)
+	}
+	if end != start && im.periods.FindByID(end) == nil {
+		fail("unknown period %s", end)
+	}
+	req.PeriodRange = period.PeriodRange{StartPeriodID: start, EndPeriodID: end}
+
+	dates := []struct {
+		col string
+		dst **time.Time
+	}{{ColumnDeliveryStart, &req.DeliveryStart}, {ColumnDeliveryEnd, &req.DeliveryEnd}}
+	for _, field := range dates {
+		col, dst := field.col, field.dst
+		if v := r.get(col); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				fail("%s must be a date (YYYY-MM-DD), got %q", col, v)
+				continue
+			}
+			*dst = &d
+		}
+	}
+	if req.PeriodRange.IsZero() && (req.DeliveryStart == nil || req.DeliveryEnd == nil) {
+		fail("either start_period or delivery_start and delivery_end are required")
+	}
+
+	numbers := []struct {
+		col string
+		dst *float64
+	}{{ColumnVolumeMT, &req.VolumeMT}, {ColumnQuantity, &req.Quantity}, {ColumnPricePerMT, &req.PricePerMT}}
+	for _, field := range numbers {
+		col, dst := field.col, field.dst
+		if v := r.get(col); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				fail("%s must be a number, got %q", col, v)
+				continue
+			}
+			*dst = n
+		}
+	}
+	if v := r.get(ColumnUnit); v != "" {
+		u, err := units.ParseUnit(v)
+		if err != nil {
+			fail("%v", err)
+		}
+		req.Unit = u
+	}
+	if v := r.get(ColumnPaymentTerms); v != "" {
+		terms, err := trade.ParsePaymentTerms(v)
+		if err != nil {
+			fail("%v", err)
+		}
+		req.PaymentTerms = &terms
+	}
+	if r.get(ColumnVolumeMT) == "" && r.get(ColumnQuantity) == "" {
+		fail("either volume_mt or quantity is required")
+	}
+	if req.Currency == "" {
+		fail("currency is required")
+	}
+
+	return kind, req, errs
+}
+
+// book books one parsed row and returns the ID and number of the new trade, and the credit
+// warning of the booking.
+func (im *Importer) book(ctx context.Context, kind trade.TradeKind, req service.TradeRequest, booking trade.BookingRequest) (string, string, *exposure.Warning, error) {
+	var t trade.Trade
+	var warning *exposure.Warning
+	var err error
+	if kind == trade.TradeKindPurchase {
+		t, _, warning, err = im.booker.CreatePurchase(ctx, req, booking)
+	} else {
+		t, _, warning, err = im.booker.CreateSale(ctx, req, booking)
+	}
+	if err != nil {
+		return "", "", nil, err
+	}
+	return t.Base().ID, t.Base().TradeNumber, warning, nil
+}
+
+// resolveCounterparty finds the company ref refers to: its ID, its CoC number, or one of its
+// names (case-insensitive). A name shared by several companies is ambiguous.
+func resolveCounterparty(ref string, companies []*company.Company) (*company.Company, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("counterparty is required")
+	}
+	for _, c := range companies {
+		if c.ID == ref || (c.CoCNumber != "" && c.CoCNumber == ref) {
+			return c, nil
+		}
+	}
+
+	var matches []*company.Company
+	for _, c := range companies {
+		for _, name := range []string{c.Name, c.CommonName, c.DisplayName} {
+			if name != "" && strings.EqualFold(name, ref) {
+				matches = append(matches, c)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown counterparty %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("counterparty %q is ambiguous: %d companies have that name; use the ID or CoC number", ref, len(matches))
+}
+
+// readRecords reads all rows of the file, header included.
+func readRecords(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		records, err := cr.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(records) > 0 && len(records[0]) > 0 {
+			records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff") // Excel writes a BOM
+		}
+		return records, nil
+	case FormatXLSX:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read XLSX: %w", err)
+		}
+		return readXLSX(data)
+	}
+	return nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+// row is one data row, addressed by column name.
+type row struct {
+	header map[string]int
+	record []string
+}
+
+func (r row) get(column string) string {
+	i, ok := r.header[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r row) empty() bool {
+	for _, v := range r.record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// readXLSX returns the cells of the first worksheet of an Office Open XML workbook as rows of
+// strings, the way encoding/csv returns a CSV file. Only what a trade sheet needs is read:
+// shared and inline strings, numbers and booleans. Formulas yield their cached value; dates
+// must be entered as text (2026-01-31), since Excel stores real dates as serial numbers.
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an XLSX workbook: %w", err)
+	}
+
+	var shared []string
+	if f := findZipFile(zr, "xl/sharedStrings.xml"); f != nil {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, fmt.Errorf("failed to read shared strings: %w", err)
+		}
+		for _, si := range sst.Items {
+			shared = append(shared, si.String())
+		}
+	}
+
+	sheet := findZipFile(zr, "xl/worksheets/sheet1.xml")
+	if sheet == nil {
+		return nil, fmt.Errorf("workbook has no first worksheet (xl/worksheets/sheet1.xml)")
+	}
+	var ws struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(sheet, &ws); err != nil {
+		return nil, fmt.Errorf("failed to read the first worksheet: %w", err)
+	}
+
+	rows := make([][]string, 0, len(ws.Rows))
+	for _, r := range ws.Rows {
+		var row []string
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, fmt.Errorf("cell %s refers to unknown shared string %q", c.Ref, c.Value)
+				}
+				row[col] = shared[n]
+			case "inlineStr":
+				row[col] = c.Inline.String()
+			case "b":
+				row[col] = strconv.FormatBool(c.Value == "1")
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// xlsxText is a string item, either plain (<t>) or rich text made of runs (<r><t>).
+type xlsxText struct {
+	Text string   `xml:"t"`
+	Runs []string `xml:"r>t"`
+}
+
+func (t xlsxText) String() string {
+	return t.Text + strings.Join(t.Runs, "")
+}
+
+func findZipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// columnIndex returns the zero-based column of a cell reference, e.g. "C7" → 2, "AA1" → 26.
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
@@ -0,0 +1,109 @@
+package trade
+
+import (
+	"fmt"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+// LadderRung is one month of a counterparty volume ladder.
+type LadderRung struct {
+	PeriodID    string  `json:"periodId"`
+	PurchasedMT float64 `json:"purchasedMT"`
+	SoldMT      float64 `json:"soldMT"`
+	NetMT       float64 `json:"netMT"` // Purchased minus sold; positive means long
+}
+
+// VolumeLadder is the month-by-month purchased vs sold volume with one counterparty
+// over a horizon. Every month of the horizon has a rung, including months without trades,
+// so the ladder can be rendered as-is.
+type VolumeLadder struct {
+	CounterpartyID string       `json:"counterpartyId"`
+	Rungs          []LadderRung `json:"rungs"`
+	TotalPurchased float64      `json:"totalPurchasedMT"`
+	TotalSold      float64      `json:"totalSoldMT"`
+	TotalNet       float64      `json:"totalNetMT"`
+}
+
+// LadderHorizon returns the IDs of `months` consecutive months starting at fromMonthID.
+// The horizon is shorter when the store runs out of months.
+//
+// Example:
+//
+//	ids, err := LadderHorizon(ps, "2026-NOV", 4)
+//	// → [2026-NOV, 2026-DEC, 2027-JAN, 2027-FEB]
+func LadderHorizon(ps *period.PeriodStore, fromMonthID string, months int) ([]string, error) {
+	if months <= 0 {
+		return nil, fmt.Errorf("ladder horizon must be at least one month, got %d", months)
+	}
+
+	from := ps.FindByID(fromMonthID)
+	if from == nil || from.Granularity != period.MonthlyPeriod {
+		return nil, fmt.Errorf("ladder start %s is not a known month", fromMonthID)
+	}
+
+	var ids []string
+	for _, m := range ps.Months() {
+		if len(ids) == months {
+			break
+		}
+		if !m.StartDate.Before(from.StartDate) {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// BuildVolumeLadder aggregates the breakdowns of a counterparty's purchases and sales into
+// a ladder over the given horizon months. Breakdowns outside the horizon are ignored.
+//
+// Reversal entries (see CancelTrade) are attributed to the delivery month they reverse,
+// not to their posting month, so a cancelled trade drops out of the ladder entirely.
+// Cancelled months (see CancelMonths) are left out.
+//
+// Example:
+//
+//	horizon, _ := LadderHorizon(ps, "2026-JAN", 3)
+//	ladder := BuildVolumeLadder("C42", horizon, purchaseBreakdowns, saleBreakdowns)
+//	// ladder.Rungs[0] → {PeriodID: "2026-JAN", PurchasedMT: 10000, SoldMT: 4000, NetMT: 6000}
+func BuildVolumeLadder(counterpartyID string, horizon []string, purchases, sales []TradeBreakdown) VolumeLadder {
+	ladder := VolumeLadder{
+		CounterpartyID: counterpartyID,
+		Rungs:          make([]LadderRung, len(horizon)),
+	}
+
+	index := make(map[string]int, len(horizon))
+	for i, id := range horizon {
+		ladder.Rungs[i].PeriodID = id
+		index[id] = i
+	}
+
+	add := func(bds []TradeBreakdown, apply func(r *LadderRung, v float64)) {
+		for _, bd := range bds {
+			if bd.IsCancelled() {
+				continue
+			}
+			deliveryMonth := bd.PeriodID
+			if bd.IsReversal() && bd.ReversedPeriodID != nil {
+				deliveryMonth = *bd.ReversedPeriodID
+			}
+			if i, ok := index[deliveryMonth]; ok {
+				apply(&ladder.Rungs[i], bd.VolumeMT)
+			}
+		}
+	}
+
+	add(purchases, func(r *LadderRung, v float64) { r.PurchasedMT += v })
+	add(sales, func(r *LadderRung, v float64) { r.SoldMT += v })
+
+	for i := range ladder.Rungs {
+		r := &ladder.Rungs[i]
+		r.NetMT = r.PurchasedMT - r.SoldMT
+		ladder.TotalPurchased += r.PurchasedMT
+		ladder.TotalSold += r.SoldMT
+	}
+	ladder.TotalNet = ladder.TotalPurchased - ladder.TotalSold
+
+	return ladder
+}
@@ -0,0 +1,112 @@
+package trade
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// Laycan is the delivery window (laydays/cancelling) of one delivery month: the vessel may not
+// arrive before Start and the buyer may cancel when it has not arrived by End. Both dates are
+// inclusive, day precision. Scheduling and demurrage work from it.
+type Laycan struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SetLaycan
+//
+// PURPOSE:
+//
+//	Sets the delivery window of one delivery month of a live trade, or clears it when laycan
+//	is nil. The window must lie within the delivery of that month (see ValidateLaycans):
+//
+//	  2026-MAR  delivery 2026-03-01..2026-03-31  laycan 2026-03-10..2026-03-12  ✓
+//	  2026-MAR  delivery 2026-03-01..2026-03-31  laycan 2026-03-30..2026-04-02  ✗
+//
+//	The window is kept in Laycans, so it survives rebuilt breakdowns, and copied onto the
+//	breakdowns of the month.
+//
+// EXAMPLE USAGE:
+//
+//	err := t.Base().SetLaycan("2026-MAR", &Laycan{Start: mar10, End: mar12}, breakdowns, "user@internal.local")
+func (tb *TradeBase) SetLaycan(periodID string, laycan *Laycan, breakdowns []TradeBreakdown, user string) error {
+	switch tb.Status {
+	case TradeStatusDraft, TradeStatusPending, TradeStatusConfirmed:
+	default:
+		return fmt.Errorf("trade %s is %s and cannot be scheduled", tb.ID, tb.Status)
+	}
+	if tb.IsDeleted() {
+		return fmt.Errorf("trade %s is deleted", tb.ID)
+	}
+
+	laycans := maps.Clone(tb.Laycans)
+	if laycans == nil {
+		laycans = make(map[string]Laycan)
+	}
+	if laycan == nil {
+		if _, ok := laycans[periodID]; !ok {
+			return fmt.Errorf("trade %s has no laycan in %s", tb.ID, periodID)
+		}
+		delete(laycans, periodID)
+	} else {
+		laycans[periodID] = Laycan{Start: utils.StartOfDay(laycan.Start), End: utils.EndOfDay(laycan.End)}
+	}
+	if err := validateLaycans(tb.ID, laycans, breakdowns); err != nil {
+		return err
+	}
+
+	tb.Laycans = laycans
+	tb.applyLaycans(breakdowns)
+	tb.AuditInfo.UpdateAuditInfo(user)
+	return nil
+}
+
+// ValidateLaycans checks that every laycan of a trade starts no later than it ends and lies
+// within the delivery of its month, i.e. between StartDate and EndDate of the breakdown.
+func ValidateLaycans(tb *TradeBase, breakdowns []TradeBreakdown) error {
+	return validateLaycans(tb.ID, tb.Laycans, breakdowns)
+}
+
+func validateLaycans(tradeID string, laycans map[string]Laycan, breakdowns []TradeBreakdown) error {
+	for _, id := range slices.Sorted(maps.Keys(laycans)) {
+		l := laycans[id]
+		if l.End.Before(l.Start) {
+			return fmt.Errorf("laycan of %s of trade %s ends before it starts", id, tradeID)
+		}
+
+		var delivery *TradeBreakdown
+		for i := range breakdowns {
+			bd := &breakdowns[i]
+			if bd.PeriodID == id && !bd.IsReversal() && !bd.IsFee() {
+				delivery = bd
+			}
+		}
+		if delivery == nil {
+			return fmt.Errorf("trade %s has a laycan in %s but does not deliver in that month", tradeID, id)
+		}
+		if l.Start.Before(utils.StartOfDay(delivery.StartDate)) || l.End.After(utils.EndOfDay(delivery.EndDate)) {
+			return fmt.Errorf("laycan %s..%s of trade %s lies outside its delivery in %s (%s..%s)", l.Start.Format("2006-01-02"), l.End.Format("2006-01-02"),
+				tradeID, id, delivery.StartDate.Format("2006-01-02"), delivery.EndDate.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// applyLaycans copies the laycan of each month onto its delivery breakdowns.
+func (tb *TradeBase) applyLaycans(breakdowns []TradeBreakdown) {
+	for i := range breakdowns {
+		bd := &breakdowns[i]
+		if bd.IsReversal() || bd.IsFee() {
+			continue
+		}
+		bd.LaycanStart, bd.LaycanEnd = nil, nil
+		if l, ok := tb.Laycans[bd.PeriodID]; ok {
+			start, end := l.Start, l.End
+			bd.LaycanStart, bd.LaycanEnd = &start, &end
+		}
+	}
+}
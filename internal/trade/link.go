@@ -0,0 +1,261 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/nholding/cso-book/internal/audit"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// linkVolumeEpsilonMT absorbs rounding when linked volumes are checked against a trade.
+const linkVolumeEpsilonMT = 0.001
+
+// TradeLink ties a purchase to a sale it was bought for (or sold from) back-to-back. A purchase
+// may be linked to several sales and a sale to several purchases; each link covers VolumeMT in
+// every month both trades deliver in.
+//
+// Links refer to the trades by trade number, so they survive amendments that give a trade a
+// new ID; trades without a number are referred to by ID.
+//
+// Example (10,000 MT/month of P-2026-0012 sold on to S-2026-0042 for Q1):
+//
+//	TradeLink{PurchaseRef: "P-2026-0012", SaleRef: "S-2026-0042", VolumeMT: 10000,
+//	    PeriodIDs: []string{"2026-JAN", "2026-FEB", "2026-MAR"}}
+type TradeLink struct {
+	ID          string          `json:"id"` // Stable ULID (primary key)
+	PurchaseRef string          `json:"purchaseRef"`
+	SaleRef     string          `json:"saleRef"`
+	VolumeMT    float64         `json:"volumeMT"`  // Per delivery month
+	PeriodIDs   []string        `json:"periodIds"` // Months both trades delivered in when linked
+	AuditInfo   audit.AuditInfo `json:"audit"`
+}
+
+// TradeRef returns the reference links keep a trade under: its trade number, or its ID when it
+// has none.
+func TradeRef(tb *TradeBase) string {
+	if tb.TradeNumber != "" {
+		return tb.TradeNumber
+	}
+	return tb.ID
+}
+
+// deliveredMT sums the live delivery volume of a trade per month, fees and reversals aside.
+func deliveredMT(breakdowns []TradeBreakdown) map[string]float64 {
+	months := make(map[string]float64)
+	for _, bd := range LiveBreakdowns(breakdowns) {
+		if !bd.IsReversal() && !bd.IsFee() {
+			months[bd.PeriodID] += bd.VolumeMT
+		}
+	}
+	return months
+}
+
+// NewTradeLink
+//
+// PURPOSE:
+//
+//	Links a purchase and a sale back-to-back for volumeMT per month, in the months both
+//	deliver in, after checking that no month of either trade becomes over-linked: together
+//	with its existing links a trade never passes on more than it delivers.
+//
+//	  P-2026-0012  JAN–MAR 10,000 MT   linked to S-2026-0042 6,000 MT  → 4,000 MT left
+//	  P-2026-0012                      link to S-2026-0051 5,000 MT    ✗ 11,000 > 10,000 MT
+//
+//	existing holds the current links of both trades. The trades may be passed in either
+//	order; both must be live and one must be a purchase, the other a sale.
+//
+// EXAMPLE USAGE:
+//
+//	link, err := trade.NewTradeLink(purchase, sale, 6000, existing, "user@internal.local")
+func NewTradeLink(a, b BookedTrade, volumeMT float64, existing []TradeLink, user string) (*TradeLink, error) {
+	purchase, sale := a, b
+	if a.Trade.Kind() == TradeKindSale {
+		purchase, sale = b, a
+	}
+	if purchase.Trade.Kind() != TradeKindPurchase || sale.Trade.Kind() != TradeKindSale {
+		return nil, fmt.Errorf("a back-to-back link joins a purchase and a sale, got a %s and a %s", a.Trade.Kind(), b.Trade.Kind())
+	}
+	for _, t := range []Trade{purchase.Trade, sale.Trade} {
+		if !IsLive(t) {
+			return nil, fmt.Errorf("trade %s is %s and cannot be linked", t.Base().ID, t.Base().Status)
+		}
+	}
+	if volumeMT <= 0 {
+		return nil, fmt.Errorf("the linked volume must be positive, got %v MT", volumeMT)
+	}
+
+	purchaseRef, saleRef := TradeRef(purchase.Trade.Base()), TradeRef(sale.Trade.Base())
+	purchaseMT, saleMT := deliveredMT(purchase.Breakdowns), deliveredMT(sale.Breakdowns)
+	var months []string
+	for id := range purchaseMT {
+		if _, ok := saleMT[id]; ok {
+			months = append(months, id)
+		}
+	}
+	if len(months) == 0 {
+		return nil, fmt.Errorf("trades %s and %s have no delivery month in common", purchaseRef, saleRef)
+	}
+	slices.Sort(months)
+
+	link := &TradeLink{
+		ID:          utils.GenerateStableID(),
+		PurchaseRef: purchaseRef,
+		SaleRef:     saleRef,
+		VolumeMT:    volumeMT,
+		PeriodIDs:   months,
+		AuditInfo:   *audit.NewAuditInfo(user),
+	}
+
+	var errs []error
+	for _, l := range existing {
+		if l.PurchaseRef == purchaseRef && l.SaleRef == saleRef {
+			return nil, fmt.Errorf("trades %s and %s are already linked", purchaseRef, saleRef)
+		}
+	}
+	all := append(slices.Clone(existing), *link)
+	for _, id := range months {
+		if linked := LinkedMT(all, purchaseRef, id); linked > purchaseMT[id]+linkVolumeEpsilonMT {
+			errs = append(errs, fmt.Errorf("%s of purchase %s would be linked for %v MT, more than its %v MT", id, purchaseRef, linked, purchaseMT[id]))
+		}
+		if linked := LinkedMT(all, saleRef, id); linked > saleMT[id]+linkVolumeEpsilonMT {
+			errs = append(errs, fmt.Errorf("%s of sale %s would be linked for %v MT, more than its %v MT", id, saleRef, linked, saleMT[id]))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// LinkedMT returns the volume of one month of a trade that its links pass on.
+func LinkedMT(links []TradeLink, tradeRef, periodID string) float64 {
+	var total float64
+	for _, l := range links {
+		if (l.PurchaseRef == tradeRef || l.SaleRef == tradeRef) && slices.Contains(l.PeriodIDs, periodID) {
+			total += l.VolumeMT
+		}
+	}
+	return total
+}
+
+// LinkMonthMargin is the margin of a back-to-back link in one delivery month. Prices and costs
+// are per MT of the month; the margin is what the linked volume earns after the costs of both
+// trades.
+type LinkMonthMargin struct {
+	PeriodID           string  `json:"periodId"`
+	VolumeMT           float64 `json:"volumeMT"`
+	PurchasePricePerMT float64 `json:"purchasePricePerMT"`
+	PurchaseCostPerMT  float64 `json:"purchaseCostPerMT"`
+	SalePricePerMT     float64 `json:"salePricePerMT"`
+	SaleCostPerMT      float64 `json:"saleCostPerMT"`
+	MarginPerMT        float64 `json:"marginPerMT"`
+	Margin             float64 `json:"margin"`
+}
+
+// LinkMargin is the margin view of one back-to-back link, month by month.
+type LinkMargin struct {
+	Link          TradeLink         `json:"link"`
+	PurchaseID    string            `json:"purchaseId"` // Current versions of the linked trades
+	SaleID        string            `json:"saleId"`
+	Currency      string            `json:"currency"`
+	Months        []LinkMonthMargin `json:"months"`
+	TotalVolumeMT float64           `json:"totalVolumeMT"`
+	TotalMargin   float64           `json:"totalMargin"`
+}
+
+// monthPrice is the value of a trade's deliveries in one month, in one currency.
+type monthPrice struct {
+	volumeMT, amount, costs float64
+}
+
+// monthPrices sums the amounts and costs of a trade's deliveries per month, in the deal currency
+// or, with reporting, in the reporting currency.
+func monthPrices(breakdowns []TradeBreakdown, reporting bool) map[string]*monthPrice {
+	months := make(map[string]*monthPrice)
+	for _, bd := range LiveBreakdowns(breakdowns) {
+		if bd.IsReversal() {
+			continue
+		}
+		p := months[bd.PeriodID]
+		if p == nil {
+			p = &monthPrice{}
+			months[bd.PeriodID] = p
+		}
+		amount, costs := bd.TotalAmount, bd.CostAmount
+		if reporting {
+			amount, costs = bd.ReportingAmount, bd.CostAmount*bd.FXRate
+		}
+		if !bd.IsFee() {
+			p.volumeMT += bd.VolumeMT
+		}
+		p.amount += amount
+		p.costs += costs
+	}
+	return months
+}
+
+// ComputeLinkMargin
+//
+// PURPOSE:
+//
+//	Shows what a back-to-back link earns: in every linked month that both trades still
+//	deliver in, the linked volume at the sale price less the purchase price and the costs of
+//	both trades per MT:
+//
+//	  2026-JAN  6,000 MT  buy 612.00 + 0.85  sell 631.50 - 1.20  → 17.45/MT  104,700
+//
+//	Prices include the availability fees of the month. Trades in the same currency are
+//	compared in it; otherwise both must be converted to the same reporting currency.
+//
+// EXAMPLE USAGE:
+//
+//	margin, err := trade.ComputeLinkMargin(link, purchase, sale)
+func ComputeLinkMargin(link TradeLink, purchase, sale BookedTrade) (*LinkMargin, error) {
+	pb, sb := purchase.Trade.Base(), sale.Trade.Base()
+	currency, reporting := strings.ToUpper(pb.Currency), false
+	if !strings.EqualFold(pb.Currency, sb.Currency) {
+		currency, reporting = reportingCurrencyOf(purchase.Breakdowns), true
+		if currency == "" || currency != reportingCurrencyOf(sale.Breakdowns) {
+			return nil, fmt.Errorf("trades %s (%s) and %s (%s) have no common currency to compare", link.PurchaseRef, pb.Currency, link.SaleRef, sb.Currency)
+		}
+	}
+
+	buy, sell := monthPrices(purchase.Breakdowns, reporting), monthPrices(sale.Breakdowns, reporting)
+	m := &LinkMargin{Link: link, PurchaseID: pb.ID, SaleID: sb.ID, Currency: currency}
+	for _, id := range link.PeriodIDs {
+		b, s := buy[id], sell[id]
+		if b == nil || s == nil || b.volumeMT == 0 || s.volumeMT == 0 {
+			continue
+		}
+		month := LinkMonthMargin{
+			PeriodID:           id,
+			VolumeMT:           link.VolumeMT,
+			PurchasePricePerMT: b.amount / b.volumeMT,
+			PurchaseCostPerMT:  b.costs / b.volumeMT,
+			SalePricePerMT:     s.amount / s.volumeMT,
+			SaleCostPerMT:      s.costs / s.volumeMT,
+		}
+		month.MarginPerMT = month.SalePricePerMT - month.SaleCostPerMT - month.PurchasePricePerMT - month.PurchaseCostPerMT
+		month.Margin = math.Round(month.MarginPerMT*month.VolumeMT*100) / 100
+		m.Months = append(m.Months, month)
+		m.TotalVolumeMT += month.VolumeMT
+		m.TotalMargin += month.Margin
+	}
+	m.TotalMargin = math.Round(m.TotalMargin*100) / 100
+	return m, nil
+}
+
+// reportingCurrencyOf returns the reporting currency the breakdowns were converted to; empty
+// when they were not.
+func reportingCurrencyOf(breakdowns []TradeBreakdown) string {
+	for _, bd := range breakdowns {
+		if bd.ReportingCurrency != "" {
+			return strings.ToUpper(bd.ReportingCurrency)
+		}
+	}
+	return ""
+}
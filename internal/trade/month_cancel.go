@@ -0,0 +1,75 @@
+package trade
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CancelMonths
+//
+// PURPOSE:
+//
+//	Cancels single delivery months of a CONFIRMED trade, e.g. when force majeure stops the
+//	deliveries of one month, while the other months stay live:
+//
+//	  2026-JAN  +10,000 MT  LIVE
+//	  2026-FEB  +10,000 MT  CANCELLED  (kept for audit, no longer in positions, PnL, exposure)
+//	  2026-MAR  +10,000 MT  LIVE
+//
+//	The months are recorded in CancelledPeriodIDs, so they stay cancelled whenever the
+//	breakdowns are rebuilt (amendments, new versions, splits). The trade keeps its status;
+//	the cancellation and its mandatory reason are written to the status history. To cancel
+//	every month, cancel the trade instead (see CancelTrade).
+//
+// EXAMPLE USAGE:
+//
+//	err := t.Base().CancelMonths([]string{"2026-FEB"}, breakdowns, "force majeure at the loading port", "user@internal.local")
+func (tb *TradeBase) CancelMonths(periodIDs []string, breakdowns []TradeBreakdown, reason, cancelledBy string) error {
+	if tb.Status != TradeStatusConfirmed {
+		return fmt.Errorf("trade %s is %s; only months of CONFIRMED trades are cancelled, amend the trade instead", tb.ID, tb.Status)
+	}
+	if tb.IsDeleted() {
+		return fmt.Errorf("trade %s is deleted", tb.ID)
+	}
+	if reason == "" {
+		return fmt.Errorf("a reason is required to cancel months of trade %s", tb.ID)
+	}
+	if len(periodIDs) == 0 {
+		return fmt.Errorf("no months given to cancel of trade %s", tb.ID)
+	}
+
+	live := make(map[string]bool)
+	for _, bd := range breakdowns {
+		if !bd.IsReversal() && !slices.Contains(tb.CancelledPeriodIDs, bd.PeriodID) {
+			live[bd.PeriodID] = true
+		}
+	}
+	for _, id := range periodIDs {
+		switch {
+		case slices.Contains(tb.CancelledPeriodIDs, id):
+			return fmt.Errorf("month %s of trade %s is already cancelled", id, tb.ID)
+		case !live[id]:
+			return fmt.Errorf("trade %s does not deliver in %s", tb.ID, id)
+		}
+		delete(live, id)
+	}
+	if len(live) == 0 {
+		return fmt.Errorf("cancelling %s would cancel every month of trade %s; cancel the trade instead", strings.Join(periodIDs, ", "), tb.ID)
+	}
+
+	tb.CancelledPeriodIDs = append(tb.CancelledPeriodIDs, periodIDs...)
+	tb.RecordStatusNote(fmt.Sprintf("cancelled deliveries of %s: %s", strings.Join(periodIDs, ", "), reason), cancelledBy)
+	tb.markCancelled(breakdowns)
+
+	return nil
+}
+
+// markCancelled sets the status of the breakdowns of cancelled months to CANCELLED.
+func (tb *TradeBase) markCancelled(breakdowns []TradeBreakdown) {
+	for i := range breakdowns {
+		if slices.Contains(tb.CancelledPeriodIDs, breakdowns[i].PeriodID) {
+			breakdowns[i].Status = BreakdownCancelled
+		}
+	}
+}
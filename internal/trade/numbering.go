@@ -0,0 +1,29 @@
+package trade
+
+import "fmt"
+
+// tradeNumberPrefixes are the leading letters of trade numbers per kind.
+var tradeNumberPrefixes = map[TradeKind]string{
+	TradeKindPurchase: "P",
+	TradeKindSale:     "S",
+	TradeKindSwap:     "SW",
+	TradeKindStorage:  "ST",
+}
+
+// FormatTradeNumber formats the human-readable number of a trade from the sequential number
+// of its kind in the year it was booked. Numbers have at least four digits.
+//
+// Example:
+//
+//	FormatTradeNumber(TradeKindPurchase, 2026, 1)  // → "P-2026-0001"
+//	FormatTradeNumber(TradeKindSale, 2026, 42)     // → "S-2026-0042"
+func FormatTradeNumber(kind TradeKind, year, seq int) (string, error) {
+	prefix, ok := tradeNumberPrefixes[kind]
+	if !ok {
+		return "", fmt.Errorf("no trade number prefix for trade kind %q", kind)
+	}
+	if seq <= 0 {
+		return "", fmt.Errorf("invalid trade number sequence %d", seq)
+	}
+	return fmt.Sprintf("%s-%d-%04d", prefix, year, seq), nil
+}
@@ -0,0 +1,250 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+// PaymentBasis is the event a payment term counts from.
+type PaymentBasis string
+
+const (
+	PaymentAfterBL            PaymentBasis = "BL"                    // Days after the bill of lading date
+	PaymentAfterDelivery      PaymentBasis = "DELIVERY"              // Days after the end of delivery in the month
+	PaymentAfterInvoice       PaymentBasis = "INVOICE"               // Days after the invoice date
+	PaymentBusinessDayOfMonth PaymentBasis = "BUSINESS_DAY_OF_MONTH" // The Nth business day of a month after the delivery month
+)
+
+// DefaultPaymentTerms apply to trades booked without payment terms: 30 days after delivery.
+var DefaultPaymentTerms = PaymentTerms{Basis: PaymentAfterDelivery, Days: 30}
+
+// PaymentTerms tell when the amount of a delivery month is due.
+//
+// Example:
+//
+//	PaymentTerms{Basis: PaymentAfterBL, Days: 30}                                // "30 days after B/L"
+//	PaymentTerms{Basis: PaymentBusinessDayOfMonth, Days: 5, MonthOffset: 1}      // "5th business day of month+1"
+//	PaymentTerms{Basis: PaymentAfterInvoice, Days: 10, BusinessDays: true}       // "10 business days after invoice"
+type PaymentTerms struct {
+	Basis        PaymentBasis `json:"basis"`
+	Days         int          `json:"days"`                   // Days after the event; the business day number for BUSINESS_DAY_OF_MONTH
+	BusinessDays bool         `json:"businessDays,omitempty"` // Days count business days instead of calendar days
+	MonthOffset  int          `json:"monthOffset,omitempty"`  // BUSINESS_DAY_OF_MONTH: months after the delivery month, e.g. 1 for "month+1"
+}
+
+// Validate checks the basis and the day counts.
+func (p *PaymentTerms) Validate() error {
+	var errs []error
+	switch p.Basis {
+	case PaymentAfterBL, PaymentAfterDelivery, PaymentAfterInvoice:
+		if p.Days < 0 {
+			errs = append(errs, fmt.Errorf("payment days must not be negative, got %d", p.Days))
+		}
+		if p.MonthOffset != 0 {
+			errs = append(errs, fmt.Errorf("a month offset only applies to %s payment terms", PaymentBusinessDayOfMonth))
+		}
+	case PaymentBusinessDayOfMonth:
+		if p.Days < 1 || p.Days > 23 {
+			errs = append(errs, fmt.Errorf("the business day of the month must lie between 1 and 23, got %d", p.Days))
+		}
+		if p.MonthOffset < 0 {
+			errs = append(errs, fmt.Errorf("the month offset must not be negative, got %d", p.MonthOffset))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid payment basis %q", p.Basis))
+	}
+	return errors.Join(errs...)
+}
+
+// String returns the terms as quoted in contracts, e.g. "30 days after B/L" or "5th business
+// day of month+1". ParsePaymentTerms reads them back.
+func (p PaymentTerms) String() string {
+	if p.Basis == PaymentBusinessDayOfMonth {
+		s := ordinal(p.Days) + " business day of month"
+		if p.MonthOffset > 0 {
+			s += "+" + strconv.Itoa(p.MonthOffset)
+		}
+		return s
+	}
+
+	unit := "days"
+	if p.BusinessDays {
+		unit = "business days"
+	}
+	if p.Days == 1 {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+	event := map[PaymentBasis]string{PaymentAfterBL: "B/L", PaymentAfterDelivery: "delivery", PaymentAfterInvoice: "invoice"}[p.Basis]
+	return fmt.Sprintf("%d %s after %s", p.Days, unit, event)
+}
+
+var (
+	afterEventTerms = regexp.MustCompile(`^(\d+)\s+(business\s+)?days?\s+after\s+(b/l|bl|bill of lading|delivery|invoice)$`)
+	dayOfMonthTerms = regexp.MustCompile(`^(\d+)(?:st|nd|rd|th)?\s+business\s+day\s+of\s+(?:the\s+)?month(?:\s*\+\s*(\d+))?$`)
+)
+
+// ParsePaymentTerms reads payment terms as quoted in contracts, case-insensitively.
+//
+// Example:
+//
+//	ParsePaymentTerms("30 days after B/L")             // → {Basis: BL, Days: 30}
+//	ParsePaymentTerms("5th business day of month+1")   // → {Basis: BUSINESS_DAY_OF_MONTH, Days: 5, MonthOffset: 1}
+//	ParsePaymentTerms("10 business days after invoice") // → {Basis: INVOICE, Days: 10, BusinessDays: true}
+func ParsePaymentTerms(s string) (PaymentTerms, error) {
+	text := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+
+	var p PaymentTerms
+	if m := afterEventTerms.FindStringSubmatch(text); m != nil {
+		p.Days, _ = strconv.Atoi(m[1])
+		p.BusinessDays = m[2] != ""
+		switch m[3] {
+		case "delivery":
+			p.Basis = PaymentAfterDelivery
+		case "invoice":
+			p.Basis = PaymentAfterInvoice
+		default:
+			p.Basis = PaymentAfterBL
+		}
+	} else if m := dayOfMonthTerms.FindStringSubmatch(text); m != nil {
+		p.Basis = PaymentBusinessDayOfMonth
+		p.Days, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			p.MonthOffset, _ = strconv.Atoi(m[2])
+		}
+	} else {
+		return PaymentTerms{}, fmt.Errorf("unknown payment terms %q", s)
+	}
+
+	if err := p.Validate(); err != nil {
+		return PaymentTerms{}, fmt.Errorf("payment terms %q: %w", s, err)
+	}
+	return p, nil
+}
+
+// DueDate
+//
+// PURPOSE:
+//
+//	Calculates the due date from the date of the event the terms count from: the B/L,
+//	delivery or invoice date, or any day of the delivery month for BUSINESS_DAY_OF_MONTH.
+//	Calendar-day terms that end on a weekend or holiday of cal roll forward to the next
+//	business day:
+//
+//	  30 days after B/L            B/L 2026-03-12  → 2026-04-13 (2026-04-11 is a Saturday)
+//	  5th business day of month+1  delivery MAR    → 2026-04-09 (Good Friday and Easter Monday off)
+//
+//	A nil cal only skips weekends. The result is the start of the day (UTC).
+//
+// EXAMPLE USAGE:
+//
+//	due := terms.DueDate(cal, blDate)
+func (p PaymentTerms) DueDate(cal *period.HolidayCalendar, event time.Time) time.Time {
+	if cal == nil {
+		cal = period.NewHolidayCalendar()
+	}
+
+	if p.Basis == PaymentBusinessDayOfMonth {
+		u := event.UTC()
+		first := time.Date(u.Year(), u.Month()+time.Month(p.MonthOffset), 1, 0, 0, 0, 0, time.UTC)
+		return cal.AddBusinessDays(cal.AddBusinessDays(first, 0), p.Days-1)
+	}
+	if p.BusinessDays {
+		return cal.AddBusinessDays(event, p.Days)
+	}
+	return cal.AddBusinessDays(event.AddDate(0, 0, p.Days), 0)
+}
+
+// EventDate returns the best estimate of the event the terms count from for one breakdown:
+// the end of the laycan for B/L terms (the vessel has loaded by then), the delivery month for
+// BUSINESS_DAY_OF_MONTH terms, otherwise the end of delivery in the month. Actual B/L and
+// invoice dates, once known, replace the estimate.
+func (p PaymentTerms) EventDate(bd TradeBreakdown) time.Time {
+	switch p.Basis {
+	case PaymentAfterBL:
+		if bd.LaycanEnd != nil {
+			return *bd.LaycanEnd
+		}
+	case PaymentBusinessDayOfMonth:
+		return bd.StartDate
+	}
+	return bd.EndDate
+}
+
+// PaymentDue is the amount of one breakdown with the date it is due.
+type PaymentDue struct {
+	TradeID           string             `json:"tradeId"`
+	BreakdownID       string             `json:"breakdownId"`
+	PeriodID          string             `json:"periodId"`
+	Component         BreakdownComponent `json:"component,omitempty"`
+	DueDate           time.Time          `json:"dueDate"`
+	Amount            float64            `json:"amount"`
+	Currency          string             `json:"currency"`
+	ReportingAmount   float64            `json:"reportingAmount,omitempty"`
+	ReportingCurrency string             `json:"reportingCurrency,omitempty"`
+	Terms             string             `json:"terms"` // e.g. "30 days after B/L"
+}
+
+// PaymentSchedule
+//
+// PURPOSE:
+//
+//	Lists when the live breakdowns of a trade are due under its payment terms, or under
+//	DefaultPaymentTerms when it has none, in delivery order:
+//
+//	  2026-JAN  30 days after B/L  laycan end 2026-01-12  → due 2026-02-11  496,000 EUR
+//	  2026-FEB  30 days after B/L  laycan end 2026-02-14  → due 2026-03-16  496,000 EUR
+//
+//	Event dates are estimated per breakdown (see EventDate). Amounts are those of the
+//	breakdowns; who pays follows from the trade kind. Reversal entries are left out.
+//
+// EXAMPLE USAGE:
+//
+//	schedule := trade.PaymentSchedule(cal, t, breakdowns)
+func PaymentSchedule(cal *period.HolidayCalendar, t Trade, breakdowns []TradeBreakdown) []PaymentDue {
+	tb := t.Base()
+	terms := DefaultPaymentTerms
+	if tb.PaymentTerms != nil {
+		terms = *tb.PaymentTerms
+	}
+
+	var schedule []PaymentDue
+	for _, bd := range LiveBreakdowns(breakdowns) {
+		if bd.IsReversal() {
+			continue
+		}
+		schedule = append(schedule, PaymentDue{
+			TradeID:           tb.ID,
+			BreakdownID:       bd.ID,
+			PeriodID:          bd.PeriodID,
+			Component:         bd.Component,
+			DueDate:           terms.DueDate(cal, terms.EventDate(bd)),
+			Amount:            bd.TotalAmount,
+			Currency:          bd.Currency,
+			ReportingAmount:   bd.ReportingAmount,
+			ReportingCurrency: bd.ReportingCurrency,
+			Terms:             terms.String(),
+		})
+	}
+	return schedule
+}
+
+// ordinal returns n with its English ordinal suffix, e.g. "1st", "2nd", "11th", "23rd".
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
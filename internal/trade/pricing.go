@@ -0,0 +1,217 @@
+package trade
+
+import (
+	"fmt"
+	"time"
+)
+
+// PricingType tells how the price of a trade is determined.
+//
+// FIXED:              PricePerMT, agreed up front (default).
+// INDEX_PLUS_PREMIUM: the index quote of one pricing day plus a premium, e.g. Platts ULSD
+//
+//	CIF NWE on the bill of lading date + 12.50.
+//
+// MONTHLY_AVERAGE:    the average of all index quotes published in the delivery month plus a
+//
+//	premium, e.g. the January average of ICE Brent + 1.20.
+const (
+	PricingFixed            PricingType = "FIXED"
+	PricingIndexPlusPremium PricingType = "INDEX_PLUS_PREMIUM"
+	PricingMonthlyAverage   PricingType = "MONTHLY_AVERAGE"
+)
+
+type PricingType string
+
+// PriceStatus tells whether the price of a breakdown can still change.
+//
+// PROVISIONAL: the index quotes the formula needs are not all published yet; the price is an
+//
+//	estimate (the quotes so far, or the trade's PricePerMT when there are none).
+//
+// FINAL:       every quote is known; the price no longer changes.
+const (
+	PriceProvisional PriceStatus = "PROVISIONAL"
+	PriceFinal       PriceStatus = "FINAL"
+)
+
+type PriceStatus string
+
+// PricingFormula describes a floating price. It is evaluated per breakdown month with
+// PriceBreakdowns once index quotes are known; until then the trade's PricePerMT serves as
+// the provisional price.
+//
+// Example (January-average Brent + 1.20 USD/MT):
+//
+//	tb.Pricing = &PricingFormula{Type: PricingMonthlyAverage, Index: "ICE-BRENT", Premium: 1.20}
+type PricingFormula struct {
+	Type    PricingType `json:"type"`
+	Index   string      `json:"index,omitempty"`   // Price index, e.g. "PLATTS-ULSD-CIF-NWE"
+	Premium float64     `json:"premium,omitempty"` // Added to the index price per MT; negative for a discount
+
+	// PricingDay is the day of the quote of an INDEX_PLUS_PREMIUM formula. Empty means the
+	// first delivery day of each breakdown.
+	PricingDay *time.Time `json:"pricingDay,omitempty"`
+}
+
+// IndexQuote is the published price of an index on one day, per MT in the trade currency.
+type IndexQuote struct {
+	Index string    `json:"index"`
+	Day   time.Time `json:"day"`
+	Price float64   `json:"price"`
+}
+
+// IndexQuoteSource provides published index quotes.
+type IndexQuoteSource interface {
+	// Quotes returns the quotes of index published between from and to (both inclusive, day
+	// precision), oldest first
+	Quotes(index string, from, to time.Time) ([]IndexQuote, error)
+}
+
+// ForwardPriceSource is implemented by quote sources that can also estimate index prices
+// before they are published, from a forward curve (see curves.WithForwards). PriceBreakdowns
+// then uses the forward estimate instead of the trade's PricePerMT as provisional price.
+type ForwardPriceSource interface {
+	// ForwardAverage returns the expected average price of index over [from, to]
+	ForwardAverage(index string, from, to time.Time) (float64, error)
+}
+
+// Validate checks that a formula is complete.
+func (f *PricingFormula) Validate() error {
+	switch f.Type {
+	case PricingFixed:
+		return nil
+	case PricingIndexPlusPremium, PricingMonthlyAverage:
+		if f.Index == "" {
+			return fmt.Errorf("pricing formula %s requires an index", f.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown pricing type %q", f.Type)
+	}
+}
+
+// IsFloating reports whether the trade is priced by a formula rather than a fixed price.
+func (tb *TradeBase) IsFloating() bool {
+	return tb.Pricing != nil && tb.Pricing.Type != PricingFixed
+}
+
+// PriceBreakdowns
+//
+// PURPOSE:
+//
+//	Evaluates the pricing formula of a trade for each of its breakdowns as known on asOf, and
+//	sets PricePerMT, TotalAmount and PriceStatus accordingly:
+//
+//	    FIXED               PricePerMT of the trade                          → FINAL
+//	    INDEX_PLUS_PREMIUM  quote on the pricing day + premium               → FINAL once quoted
+//	    MONTHLY_AVERAGE     average of the month's quotes so far + premium   → FINAL after the month
+//
+//	Without any quote yet the provisional price is the forward estimate when the source is a
+//	ForwardPriceSource with a curve for the index, and the trade's PricePerMT otherwise. Reversal entries are skipped: they keep the price of the breakdown they
+//	reverse. Reporting currency amounts are not recomputed; run ConvertToReporting afterwards
+//	when they are in use.
+//
+// EXAMPLE USAGE:
+//
+//	tb.PricePerMT = 610 // Provisional estimate
+//	tb.Pricing = &PricingFormula{Type: PricingMonthlyAverage, Index: "ICE-BRENT", Premium: 1.20}
+//	err := PriceBreakdowns(*tb, breakdowns, quotes, time.Now())
+//	// Mid-January: breakdowns[0] = {PeriodID: "2026-JAN", PricePerMT: 605.4, PriceStatus: PROVISIONAL}
+//	// February:    breakdowns[0] = {PeriodID: "2026-JAN", PricePerMT: 603.9, PriceStatus: FINAL}
+func PriceBreakdowns(tb TradeBase, breakdowns []TradeBreakdown, quotes IndexQuoteSource, asOf time.Time) error {
+	if !tb.IsFloating() {
+		for i := range breakdowns {
+			if breakdowns[i].IsReversal() {
+				continue
+			}
+			breakdowns[i].PriceStatus = PriceFinal
+		}
+		return nil
+	}
+	if err := tb.Pricing.Validate(); err != nil {
+		return fmt.Errorf("trade %s: %w", tb.ID, err)
+	}
+
+	prices := make([]float64, len(breakdowns))
+	statuses := make([]PriceStatus, len(breakdowns))
+	for i, bd := range breakdowns {
+		if bd.IsReversal() || bd.IsFee() {
+			continue
+		}
+		price, status, err := evaluateFormula(tb, bd, quotes, asOf)
+		if err != nil {
+			return fmt.Errorf("breakdown %s of trade %s: %w", bd.PeriodID, tb.ID, err)
+		}
+		prices[i], statuses[i] = price, status
+	}
+
+	for i := range breakdowns {
+		if breakdowns[i].IsReversal() || breakdowns[i].IsFee() {
+			continue
+		}
+		breakdowns[i].PricePerMT = prices[i]
+		breakdowns[i].TotalAmount = breakdowns[i].VolumeMT * prices[i]
+		breakdowns[i].PriceStatus = statuses[i]
+	}
+	return nil
+}
+
+// initialPriceStatus is the price status of a new breakdown, before any formula is evaluated.
+func initialPriceStatus(tb TradeBase) PriceStatus {
+	if tb.IsFloating() {
+		return PriceProvisional
+	}
+	return PriceFinal
+}
+
+// evaluateFormula prices one breakdown of a floating trade.
+func evaluateFormula(tb TradeBase, bd TradeBreakdown, quotes IndexQuoteSource, asOf time.Time) (float64, PriceStatus, error) {
+	f := tb.Pricing
+
+	var from, to time.Time
+	switch f.Type {
+	case PricingIndexPlusPremium:
+		from = bd.StartDate
+		if f.PricingDay != nil {
+			from = *f.PricingDay
+		}
+		to = from
+	case PricingMonthlyAverage:
+		from, to = bd.StartDate, bd.EndDate
+	}
+
+	// Quotes published after asOf are not known yet
+	known := to
+	if asOf.Before(known) {
+		known = asOf
+	}
+	var qs []IndexQuote
+	if quotes != nil && !known.Before(from) {
+		var err error
+		if qs, err = quotes.Quotes(f.Index, from, known); err != nil {
+			return 0, "", fmt.Errorf("failed to load %s quotes: %w", f.Index, err)
+		}
+	}
+
+	if len(qs) == 0 {
+		if fwd, ok := quotes.(ForwardPriceSource); ok {
+			if avg, err := fwd.ForwardAverage(f.Index, from, to); err == nil {
+				return avg + f.Premium, PriceProvisional, nil
+			}
+		}
+		return tb.PricePerMT, PriceProvisional, nil
+	}
+
+	var sum float64
+	for _, q := range qs {
+		sum += q.Price
+	}
+	price := sum/float64(len(qs)) + f.Premium
+
+	status := PriceProvisional
+	if f.Type == PricingIndexPlusPremium || asOf.After(to) {
+		status = PriceFinal
+	}
+	return price, status, nil
+}
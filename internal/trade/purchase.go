@@ -1,28 +1,26 @@
 package trade
 
 import (
-	"github.com/nholding/cso-book/internal/period"
+	period "github.com/nholding/cso-book/internal/period/domain"
 )
 
 // Purchase
 // Represents a purchase trade .
 type Purchase struct {
 	TradeBase
-	SupplierID string
+	SupplierID string // ID of the supplying company (company domain)
 }
 
-func NewPurchase(ps period.PeriodStore, supplierName string, pr period.PeriodRange, volumeMT, pricePerMT float64, currency, createdBy string) (Purchase, []TradeBreakdown) {
+// NewPurchase builds a purchase from the company supplierID. The supplier is not checked here;
+// TradeService resolves it against the company master data before the purchase is booked.
+func NewPurchase(ps *period.PeriodStore, supplierID string, pr period.PeriodRange, volumeMT, pricePerMT float64, currency, createdBy string) (Purchase, []TradeBreakdown) {
 	// User does NOT provide status. The new purchase ALWAYS starts as Pending.
 	p := Purchase{
 		TradeBase:  *NewTradeBase(pr, volumeMT, pricePerMT, currency, createdBy),
-		SupplierID: "TestSupplierID",
+		SupplierID: supplierID,
 	}
 
-	breakdowns := CreateTradeBreakdowns(p.TradeBase, &ps, createdBy)
+	breakdowns := CreateTradeBreakdowns(p.TradeBase, ps, createdBy)
 
 	return p, breakdowns
 }
-
-func (p *Purchase) UpdateAvailabilityFee(newAvailabilityFee float64) {
-	p.TradeBase.PricePerMT = newAvailabilityFee
-}
@@ -0,0 +1,105 @@
+package trade
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/fx"
+)
+
+// ConvertToReporting fills the reporting currency fields of breakdowns: TotalAmount converted
+// into reportingCurrency at the rate of the breakdown's first delivery day (the most recent
+// rate on or before it, so future months use the latest fixing). It fails without changing
+// anything when a rate is missing.
+//
+// Example (USD cargo, book reported in EUR):
+//
+//	err := ConvertToReporting(breakdowns, fxTable, "EUR")
+//	// breakdowns[0].TotalAmount = 35000 (USD), ReportingAmount = 32281.87, FXRate = 0.92234
+func ConvertToReporting(breakdowns []TradeBreakdown, rates fx.RateSource, reportingCurrency string) error {
+	converted := make([]float64, len(breakdowns))
+	for i, bd := range breakdowns {
+		r, err := rates.Rate(bd.Currency, reportingCurrency, bd.StartDate)
+		if err != nil {
+			return fmt.Errorf("breakdown %s of trade %s: %w", bd.PeriodID, bd.ParentTradeID, err)
+		}
+		converted[i] = r
+	}
+
+	for i := range breakdowns {
+		breakdowns[i].ReportingCurrency = reportingCurrency
+		breakdowns[i].FXRate = converted[i]
+		breakdowns[i].ReportingAmount = breakdowns[i].TotalAmount * converted[i]
+	}
+	return nil
+}
+
+// ReportingTotal adds up the reporting amounts of breakdowns. It fails when a breakdown has
+// not been converted or the breakdowns are reported in different currencies.
+//
+// Example:
+//
+//	total, ccy, err := ReportingTotal(breakdowns) // → 1250000, "EUR"
+func ReportingTotal(breakdowns []TradeBreakdown) (float64, string, error) {
+	var total float64
+	var currency string
+	for _, bd := range breakdowns {
+		if bd.ReportingCurrency == "" {
+			return 0, "", fmt.Errorf("breakdown %s of trade %s has no reporting amount", bd.PeriodID, bd.ParentTradeID)
+		}
+		if currency != "" && bd.ReportingCurrency != currency {
+			return 0, "", fmt.Errorf("breakdowns are reported in both %s and %s", currency, bd.ReportingCurrency)
+		}
+		currency = bd.ReportingCurrency
+		total += bd.ReportingAmount
+	}
+	return total, currency, nil
+}
+
+// FXRevaluation is the currency result of one breakdown: its reporting amount at the rate it
+// was booked at, compared with the same amount at the rate of a revaluation day.
+type FXRevaluation struct {
+	PeriodID          string  `json:"periodId"`
+	Currency          string  `json:"currency"` // Currency of the deal
+	ReportingCurrency string  `json:"reportingCurrency"`
+	TotalAmount       float64 `json:"totalAmount"`
+	BookedRate        float64 `json:"bookedRate"`
+	RevaluedRate      float64 `json:"revaluedRate"`
+	BookedAmount      float64 `json:"bookedAmount"`
+	RevaluedAmount    float64 `json:"revaluedAmount"`
+	Result            float64 `json:"result"` // RevaluedAmount - BookedAmount; positive is a gain
+}
+
+// RevalueReporting revalues converted breakdowns at the rates of asOf (month-end closing,
+// daily mark-to-market). The breakdowns themselves are not changed: their booked reporting
+// amounts stay what was reported. Breakdowns that were never converted are an error.
+//
+// Example (USD cargo booked at 0.92234, EUR/USD moved to 1.10 at month end):
+//
+//	revals, err := RevalueReporting(breakdowns, fxRates, monthEnd)
+//	// revals[0].BookedAmount = 32281.87, RevaluedAmount = 31818.18, Result = -463.69
+func RevalueReporting(breakdowns []TradeBreakdown, rates fx.RateSource, asOf time.Time) ([]FXRevaluation, error) {
+	revals := make([]FXRevaluation, 0, len(breakdowns))
+	for _, bd := range breakdowns {
+		if bd.ReportingCurrency == "" {
+			return nil, fmt.Errorf("breakdown %s of trade %s has no reporting amount", bd.PeriodID, bd.ParentTradeID)
+		}
+		r, err := rates.Rate(bd.Currency, bd.ReportingCurrency, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("breakdown %s of trade %s: %w", bd.PeriodID, bd.ParentTradeID, err)
+		}
+		revalued := bd.TotalAmount * r
+		revals = append(revals, FXRevaluation{
+			PeriodID:          bd.PeriodID,
+			Currency:          bd.Currency,
+			ReportingCurrency: bd.ReportingCurrency,
+			TotalAmount:       bd.TotalAmount,
+			BookedRate:        bd.FXRate,
+			RevaluedRate:      r,
+			BookedAmount:      bd.ReportingAmount,
+			RevaluedAmount:    revalued,
+			Result:            revalued - bd.ReportingAmount,
+		})
+	}
+	return revals, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// BreakdownArchiveRepository keeps the index of months whose breakdowns live in cold storage.
+type BreakdownArchiveRepository interface {
+	// ArchiveBreakdowns deletes the breakdowns of a.PeriodID that GetBreakdownsByPeriod returns
+	// from the hot table and records the archive, in one transaction. It fails when the month no
+	// longer holds exactly a.RowCount of them, i.e. when the archive would not contain every
+	// deleted row. Breakdowns of SUPERSEDED trades stay in the hot table.
+	ArchiveBreakdowns(ctx context.Context, a trade.BreakdownArchive) error
+
+	// GetArchive returns the archive of a month; returns nil, nil when the month is not archived
+	GetArchive(ctx context.Context, periodID string) (*trade.BreakdownArchive, error)
+
+	// ListArchives returns all archives, oldest month first
+	ListArchives(ctx context.Context) ([]trade.BreakdownArchive, error)
+
+	// ListArchivesForTrade returns the archives holding breakdowns of one trade
+	ListArchivesForTrade(ctx context.Context, tradeID string) ([]trade.BreakdownArchive, error)
+}
+
+// Compile-time check that RdsBreakdownArchiveRepository satisfies BreakdownArchiveRepository
+var _ BreakdownArchiveRepository = (*RdsBreakdownArchiveRepository)(nil)
+
+type RdsBreakdownArchiveRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Read-only queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsBreakdownArchiveRepository(cfg *awsclient.Config) (*RdsBreakdownArchiveRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsBreakdownArchiveRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// ArchiveBreakdowns removes the breakdowns of one month from trade_breakdowns once they are
+// safely in S3. The archive row and a period ARCHIVE audit event are written in the same
+// transaction, so a month is never missing from both the hot table and the index.
+//
+// Example:
+//
+//	// after uploading the month to S3
+//	err := repo.ArchiveBreakdowns(ctx, trade.BreakdownArchive{PeriodID: "2019-JAN", Key: key, RowCount: len(bds), ...})
+func (r *RdsBreakdownArchiveRepository) ArchiveBreakdowns(ctx context.Context, a trade.BreakdownArchive) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// The same rows as GetBreakdownsByPeriod, which the archive was written from
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM trade_breakdowns b
+		WHERE b.period_id=$1
+		  AND NOT EXISTS (SELECT 1 FROM trades t WHERE t.id=b.parent_trade_id AND t.status='`+string(trade.TradeStatusSuperseded)+`')
+	`, a.PeriodID)
+	if err != nil {
+		return fmt.Errorf("failed to delete breakdowns of %s: %w", a.PeriodID, err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to count deleted breakdowns of %s: %w", a.PeriodID, err)
+	}
+	if int(deleted) != a.RowCount {
+		return fmt.Errorf("period %s holds %d breakdowns, archive holds %d; not archived", a.PeriodID, deleted, a.RowCount)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO breakdown_archives (period_id, s3_key, version_id, row_count, sha256, trade_ids, archived_by, archived_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	`, a.PeriodID, a.Key, a.VersionID, a.RowCount, a.SHA256, pq.Array(a.TradeIDs), a.ArchivedBy, a.ArchivedAt); err != nil {
+		return fmt.Errorf("failed to record archive of %s: %w", a.PeriodID, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityPeriod, a.PeriodID, audit.ActionArchive, a.ArchivedBy,
+		map[string]any{"key": a.Key, "versionId": a.VersionID, "breakdowns": a.RowCount})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit archive of %s: %w", a.PeriodID, err)
+	}
+
+	return nil
+}
+
+// archiveColumns is the column list scanned by queryArchives.
+const archiveColumns = `period_id, s3_key, version_id, row_count, sha256, trade_ids, archived_by, archived_at`
+
+func (r *RdsBreakdownArchiveRepository) GetArchive(ctx context.Context, periodID string) (*trade.BreakdownArchive, error) {
+	var a trade.BreakdownArchive
+	err := r.reader.QueryRowContext(ctx, `SELECT `+archiveColumns+` FROM breakdown_archives WHERE period_id=$1`, periodID).
+		Scan(&a.PeriodID, &a.Key, &a.VersionID, &a.RowCount, &a.SHA256, pq.Array(&a.TradeIDs), &a.ArchivedBy, &a.ArchivedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archive of %s: %w", periodID, err)
+	}
+
+	return &a, nil
+}
+
+func (r *RdsBreakdownArchiveRepository) ListArchives(ctx context.Context) ([]trade.BreakdownArchive, error) {
+	return r.queryArchives(ctx, `SELECT `+archiveColumns+` FROM breakdown_archives ORDER BY period_id`)
+}
+
+// ListArchivesForTrade returns the archives holding breakdowns of one trade, e.g. to rebuild
+// its complete breakdown history.
+func (r *RdsBreakdownArchiveRepository) ListArchivesForTrade(ctx context.Context, tradeID string) ([]trade.BreakdownArchive, error) {
+	return r.queryArchives(ctx, `
+		SELECT `+archiveColumns+` FROM breakdown_archives
+		WHERE trade_ids @> ARRAY[$1]::TEXT[]
+		ORDER BY period_id
+	`, tradeID)
+}
+
+func (r *RdsBreakdownArchiveRepository) queryArchives(ctx context.Context, query string, args ...any) ([]trade.BreakdownArchive, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query breakdown archives: %w", err)
+	}
+	defer rows.Close()
+
+	var archives []trade.BreakdownArchive
+	for rows.Next() {
+		var a trade.BreakdownArchive
+		if err := rows.Scan(&a.PeriodID, &a.Key, &a.VersionID, &a.RowCount, &a.SHA256, pq.Array(&a.TradeIDs), &a.ArchivedBy, &a.ArchivedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan breakdown archive: %w", err)
+		}
+		archives = append(archives, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate breakdown archives: %w", err)
+	}
+
+	return archives, nil
+}
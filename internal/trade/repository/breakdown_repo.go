@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/pgbulk"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/units"
+)
+
+// BreakdownRepository stores the monthly breakdowns of trades, keyed by ParentTradeID.
+//
+// Breakdowns are always written per trade, together with the trade's rollups (see
+// trade.ComputeRollups), so the materialized rollups can never drift from the months they
+// summarize.
+type BreakdownRepository interface {
+	// SaveBreakdowns bulk-inserts the breakdowns and rollups of a trade that has none stored yet
+	SaveBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error
+
+	// ReplaceBreakdowns atomically replaces ALL breakdowns and rollups of a trade, e.g. on amend
+	ReplaceBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error
+
+	// GetBreakdowns returns the breakdowns of a trade, including reversal entries, by delivery start
+	GetBreakdowns(ctx context.Context, tradeID string) ([]trade.TradeBreakdown, error)
+
+	// GetBreakdownsByPeriod returns the breakdowns of all trades posted in one month, e.g.
+	// "2026-JAN"; breakdowns of SUPERSEDED trades are left out
+	GetBreakdownsByPeriod(ctx context.Context, periodID string) ([]trade.TradeBreakdown, error)
+}
+
+// Compile-time check that RdsBreakdownRepository satisfies BreakdownRepository
+var _ BreakdownRepository = (*RdsBreakdownRepository)(nil)
+
+type RdsBreakdownRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Reporting queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsBreakdownRepository(cfg *awsclient.Config) (*RdsBreakdownRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsBreakdownRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveBreakdowns inserts the breakdowns returned by trade.CreateTradeBreakdowns, plus the
+// rollups computed from them, in one transaction. Rows are streamed with Postgres COPY
+// (see pgbulk.CopyIn), so multi-year trades with hundreds of months are one round trip.
+//
+// It fails when the trade already has breakdowns; use ReplaceBreakdowns to amend.
+//
+// Example:
+//
+//	p, breakdowns := trade.NewPurchase(ps, "C42", pr, 10000, 3.5, "EUR", "user@internal.local")
+//	err := repo.SaveBreakdowns(ctx, p.ID, breakdowns, trade.ComputeRollups(breakdowns, ps))
+func (r *RdsBreakdownRepository) SaveBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error {
+	return r.writeBreakdowns(ctx, tradeID, breakdowns, rollups, false)
+}
+
+// ReplaceBreakdowns deletes the existing breakdowns (including reversal entries) and rollups
+// of a trade and inserts the given ones, in one transaction: readers see either the old or
+// the new breakdowns, never a mix or none.
+//
+// Example (amend the volume of a trade):
+//
+//	tb.VolumeMT = 12000
+//	breakdowns := trade.CreateTradeBreakdowns(tb, ps, "user@internal.local")
+//	err := repo.ReplaceBreakdowns(ctx, tb.ID, breakdowns, trade.ComputeRollups(breakdowns, ps))
+func (r *RdsBreakdownRepository) ReplaceBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error {
+	return r.writeBreakdowns(ctx, tradeID, breakdowns, rollups, true)
+}
+
+func (r *RdsBreakdownRepository) writeBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup, replace bool) error {
+	for _, bd := range breakdowns {
+		if bd.ParentTradeID != tradeID {
+			return fmt.Errorf("breakdown %s belongs to trade %s, not %s", bd.ID, bd.ParentTradeID, tradeID)
+		}
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if replace {
+		// Reversal entries reference breakdowns of the same trade; one statement removes both
+		if _, err := tx.ExecContext(ctx, `DELETE FROM trade_breakdowns WHERE parent_trade_id=$1`, tradeID); err != nil {
+			return fmt.Errorf("failed to delete breakdowns of trade %s: %w", tradeID, err)
+		}
+	}
+
+	if err := insertBreakdownsTx(ctx, tx.Tx, tradeID, breakdowns); err != nil {
+		return err
+	}
+
+	if err := ReplaceRollupsTx(ctx, tx.Tx, tradeID, rollups); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit breakdown transaction: %w", err)
+	}
+
+	return nil
+}
+
+// breakdownCopyColumns is the column order of the COPY rows built by insertBreakdownsTx.
+var breakdownCopyColumns = []string{
+	"id", "business_key", "parent_trade_id", "counterparty_name", "period_id", "start_date", "end_date", "month_fraction",
+	"volume_mt", "unit", "quantity", "price_per_mt", "price_status", "currency", "total_amount", "cost_amount", "status",
+	"component", "laycan_start", "laycan_end", "reporting_currency", "fx_rate", "reporting_amount", "reversal_of_id", "reversed_period_id",
+	"audit_created_by", "audit_created_at", "audit_updated_by", "audit_updated_at",
+}
+
+// bookedUnit defaults the unit of breakdowns built before units existed to MT.
+func bookedUnit(u units.Unit) units.Unit {
+	if u == "" {
+		return units.MetricTonne
+	}
+	return u
+}
+
+// priceStatus defaults the price status of breakdowns built before pricing formulas existed to FINAL.
+func priceStatus(s trade.PriceStatus) trade.PriceStatus {
+	if s == "" {
+		return trade.PriceFinal
+	}
+	return s
+}
+
+// breakdownStatus stores breakdowns without a status as LIVE.
+func breakdownStatus(s trade.BreakdownStatus) trade.BreakdownStatus {
+	if s == "" {
+		return trade.BreakdownLive
+	}
+	return s
+}
+
+// breakdownComponent stores breakdowns without a component as COMMODITY.
+func breakdownComponent(c trade.BreakdownComponent) trade.BreakdownComponent {
+	if c == "" {
+		return trade.ComponentCommodity
+	}
+	return c
+}
+
+// insertBreakdownsTx bulk-inserts breakdowns inside an existing transaction.
+func insertBreakdownsTx(ctx context.Context, tx *sql.Tx, tradeID string, breakdowns []trade.TradeBreakdown) error {
+	rows := make([][]any, 0, len(breakdowns))
+	for _, bd := range breakdowns {
+		rows = append(rows, []any{
+			bd.ID,
+			bd.BusinessKey,
+			bd.ParentTradeID,
+			bd.CounterpartyName,
+			bd.PeriodID,
+			bd.StartDate,
+			bd.EndDate,
+			bd.MonthFraction,
+			bd.VolumeMT,
+			string(bookedUnit(bd.Unit)),
+			bd.Quantity,
+			bd.PricePerMT,
+			string(priceStatus(bd.PriceStatus)),
+			bd.Currency,
+			bd.TotalAmount,
+			bd.CostAmount,
+			string(breakdownStatus(bd.Status)),
+			string(breakdownComponent(bd.Component)),
+			bd.LaycanStart,
+			bd.LaycanEnd,
+			bd.ReportingCurrency,
+			bd.FXRate,
+			bd.ReportingAmount,
+			bd.ReversalOfID,
+			bd.ReversedPeriodID,
+			bd.AuditInfo.CreatedBy,
+			bd.AuditInfo.CreatedAt,
+			bd.AuditInfo.UpdatedBy,
+			bd.AuditInfo.UpdatedAt,
+		})
+	}
+
+	if err := pgbulk.CopyIn(ctx, tx, "trade_breakdowns", breakdownCopyColumns, rows); err != nil {
+		return fmt.Errorf("failed to insert breakdowns of trade %s: %w", tradeID, err)
+	}
+
+	return nil
+}
+
+func (r *RdsBreakdownRepository) GetBreakdowns(ctx context.Context, tradeID string) ([]trade.TradeBreakdown, error) {
+	return r.queryBreakdowns(ctx, `
+		SELECT `+breakdownColumns+` FROM trade_breakdowns
+		WHERE parent_trade_id=$1
+		ORDER BY start_date, id
+	`, tradeID)
+}
+
+// GetBreakdownsByPeriod returns the breakdowns posted in one month, across all trades.
+// Reversal entries are returned in the month they were posted in (PeriodID), not in the
+// month they reverse. Breakdowns of SUPERSEDED trades are left out: they are kept for audit
+// (see GetBreakdowns) while the new version of the trade counts instead.
+func (r *RdsBreakdownRepository) GetBreakdownsByPeriod(ctx context.Context, periodID string) ([]trade.TradeBreakdown, error) {
+	return r.queryBreakdowns(ctx, `
+		SELECT `+breakdownColumns+` FROM trade_breakdowns b
+		WHERE b.period_id=$1
+		  AND NOT EXISTS (SELECT 1 FROM trades t WHERE t.id=b.parent_trade_id AND t.status='`+string(trade.TradeStatusSuperseded)+`')
+		ORDER BY parent_trade_id, start_date, id
+	`, periodID)
+}
+
+// breakdownColumns is the column list scanned by queryBreakdowns.
+const breakdownColumns = `id, business_key, parent_trade_id, counterparty_name, period_id, start_date, end_date, month_fraction,
+	volume_mt, unit, quantity, price_per_mt, price_status, currency, total_amount, cost_amount,
+	status, component, laycan_start, laycan_end, reporting_currency, fx_rate, reporting_amount, reversal_of_id, reversed_period_id,
+	audit_created_by, audit_created_at, audit_updated_by, audit_updated_at`
+
+func (r *RdsBreakdownRepository) queryBreakdowns(ctx context.Context, query string, arg string) ([]trade.TradeBreakdown, error) {
+	rows, err := r.reader.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query breakdowns: %w", err)
+	}
+	defer rows.Close()
+
+	var breakdowns []trade.TradeBreakdown
+	for rows.Next() {
+		var bd trade.TradeBreakdown
+		if err := rows.Scan(
+			&bd.ID,
+			&bd.BusinessKey,
+			&bd.ParentTradeID,
+			&bd.CounterpartyName,
+			&bd.PeriodID,
+			&bd.StartDate,
+			&bd.EndDate,
+			&bd.MonthFraction,
+			&bd.VolumeMT,
+			&bd.Unit,
+			&bd.Quantity,
+			&bd.PricePerMT,
+			&bd.PriceStatus,
+			&bd.Currency,
+			&bd.TotalAmount,
+			&bd.CostAmount,
+			&bd.Status,
+			&bd.Component,
+			&bd.LaycanStart,
+			&bd.LaycanEnd,
+			&bd.ReportingCurrency,
+			&bd.FXRate,
+			&bd.ReportingAmount,
+			&bd.ReversalOfID,
+			&bd.ReversedPeriodID,
+			&bd.AuditInfo.CreatedBy,
+			&bd.AuditInfo.CreatedAt,
+			&bd.AuditInfo.UpdatedBy,
+			&bd.AuditInfo.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan breakdown: %w", err)
+		}
+		breakdowns = append(breakdowns, bd)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate breakdown rows: %w", err)
+	}
+
+	return breakdowns, nil
+}
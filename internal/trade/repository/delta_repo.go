@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// BreakdownDeltaRepository stores what amendments changed per delivery month (see
+// trade.DiffBreakdowns).
+type BreakdownDeltaRepository interface {
+	// SaveDeltas inserts the deltas of one amendment
+	SaveDeltas(ctx context.Context, deltas []trade.BreakdownDelta) error
+
+	// ListDeltas retrieves the deltas matching the filter, oldest amendment first
+	ListDeltas(ctx context.Context, filter DeltaFilter) ([]trade.BreakdownDelta, error)
+}
+
+// DeltaFilter narrows ListDeltas. Zero fields match everything.
+//
+// Example (every change to January 2026 of deal P-2026-0001, across its versions):
+//
+//	DeltaFilter{TradeNumber: "P-2026-0001", PeriodID: "2026-JAN"}
+type DeltaFilter struct {
+	TradeID     string // Amendments that created or superseded this version
+	TradeNumber string // Amendments of any version of the deal
+	PeriodID    string
+}
+
+// Compile-time check that RdsBreakdownDeltaRepository satisfies BreakdownDeltaRepository
+var _ BreakdownDeltaRepository = (*RdsBreakdownDeltaRepository)(nil)
+
+// RdsBreakdownDeltaRepository keeps the deltas in trade_breakdown_deltas.
+type RdsBreakdownDeltaRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Listing queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsBreakdownDeltaRepository(cfg *awsclient.Config) (*RdsBreakdownDeltaRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsBreakdownDeltaRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveDeltas inserts the deltas of one amendment. Inside a txn.Manager transaction they commit
+// or roll back with the amendment.
+func (r *RdsBreakdownDeltaRepository) SaveDeltas(ctx context.Context, deltas []trade.BreakdownDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, d := range deltas {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO trade_breakdown_deltas (
+				trade_id, previous_trade_id, trade_number, period_id, old_volume_mt, new_volume_mt,
+				old_amount, new_amount, old_currency, new_currency, reason, amended_by, amended_at
+			) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+		`,
+			d.TradeID,
+			nullString(d.PreviousTradeID),
+			nullString(d.TradeNumber),
+			d.PeriodID,
+			d.OldVolumeMT,
+			d.NewVolumeMT,
+			d.OldAmount,
+			d.NewAmount,
+			d.OldCurrency,
+			d.NewCurrency,
+			d.Reason,
+			d.AmendedBy,
+			d.AmendedAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert breakdown delta of trade %s in %s: %w", d.TradeID, d.PeriodID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit breakdown delta transaction: %w", err)
+	}
+	return nil
+}
+
+// ListDeltas retrieves the deltas matching the filter, oldest amendment first and by delivery
+// month within an amendment.
+//
+// Example:
+//
+//	deltas, err := repo.ListDeltas(ctx, DeltaFilter{PeriodID: "2026-JAN"})
+func (r *RdsBreakdownDeltaRepository) ListDeltas(ctx context.Context, filter DeltaFilter) ([]trade.BreakdownDelta, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TradeID != "" {
+		add("(trade_id=$%[1]d OR previous_trade_id=$%[1]d)", filter.TradeID)
+	}
+	if filter.TradeNumber != "" {
+		add("trade_number=$%d", filter.TradeNumber)
+	}
+	if filter.PeriodID != "" {
+		add("period_id=$%d", filter.PeriodID)
+	}
+
+	query := `SELECT trade_id, COALESCE(previous_trade_id, ''), COALESCE(trade_number, ''), period_id, old_volume_mt,
+		new_volume_mt, old_amount, new_amount, old_currency, new_currency, reason, amended_by, amended_at
+		FROM trade_breakdown_deltas`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY amended_at, trade_id, period_id`
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query breakdown deltas: %w", err)
+	}
+	defer rows.Close()
+
+	var deltas []trade.BreakdownDelta
+	for rows.Next() {
+		var d trade.BreakdownDelta
+		if err := rows.Scan(&d.TradeID, &d.PreviousTradeID, &d.TradeNumber, &d.PeriodID, &d.OldVolumeMT, &d.NewVolumeMT,
+			&d.OldAmount, &d.NewAmount, &d.OldCurrency, &d.NewCurrency, &d.Reason, &d.AmendedBy, &d.AmendedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan breakdown delta: %w", err)
+		}
+		deltas = append(deltas, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate breakdown deltas: %w", err)
+	}
+	return deltas, nil
+}
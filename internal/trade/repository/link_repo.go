@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// TradeLinkRepository stores back-to-back links between purchases and sales (see
+// trade.NewTradeLink).
+type TradeLinkRepository interface {
+	// SaveLink inserts a new link; it fails with *dberr.DuplicateError when the two trades are
+	// already linked
+	SaveLink(ctx context.Context, link *trade.TradeLink) error
+
+	// DeleteLink removes a link; it fails with *dberr.NotFoundError when it does not exist
+	DeleteLink(ctx context.Context, id, user string) error
+
+	// ListLinks retrieves the links matching the filter, by purchase and sale reference
+	ListLinks(ctx context.Context, filter LinkFilter) ([]trade.TradeLink, error)
+}
+
+// LinkFilter narrows ListLinks. Zero fields match everything.
+//
+// Example (every link of P-2026-0012, whichever side it is on):
+//
+//	LinkFilter{TradeRef: "P-2026-0012"}
+type LinkFilter struct {
+	ID       string
+	TradeRef string // Links with this trade on either side, see trade.TradeRef
+}
+
+// Compile-time check that RdsTradeLinkRepository satisfies TradeLinkRepository
+var _ TradeLinkRepository = (*RdsTradeLinkRepository)(nil)
+
+// RdsTradeLinkRepository keeps back-to-back links in trade_links.
+type RdsTradeLinkRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Listing queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsTradeLinkRepository(cfg *awsclient.Config) (*RdsTradeLinkRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsTradeLinkRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveLink inserts a link. (purchase_ref, sale_ref) is unique, so two trades are linked once.
+func (r *RdsTradeLinkRepository) SaveLink(ctx context.Context, link *trade.TradeLink) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO trade_links (id, purchase_ref, sale_ref, volume_mt, period_ids, audit_created_by, audit_created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+	`,
+		link.ID,
+		link.PurchaseRef,
+		link.SaleRef,
+		link.VolumeMT,
+		pq.Array(link.PeriodIDs),
+		link.AuditInfo.CreatedBy,
+		link.AuditInfo.CreatedAt,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "trade link", ID: link.ID, Key: link.PurchaseRef + " ↔ " + link.SaleRef, Err: err}
+		}
+		return fmt.Errorf("failed to insert link %s ↔ %s: %w", link.PurchaseRef, link.SaleRef, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityTradeLink, link.ID, audit.ActionCreate, link.AuditInfo.CreatedBy,
+		map[string]any{"purchaseRef": link.PurchaseRef, "saleRef": link.SaleRef, "volumeMT": link.VolumeMT})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trade link transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteLink removes a link; the audit log keeps who removed it.
+func (r *RdsTradeLinkRepository) DeleteLink(ctx context.Context, id, user string) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var purchaseRef, saleRef string
+	err = tx.QueryRowContext(ctx, `DELETE FROM trade_links WHERE id=$1 RETURNING purchase_ref, sale_ref`, id).Scan(&purchaseRef, &saleRef)
+	if err == sql.ErrNoRows {
+		return &dberr.NotFoundError{Entity: "trade link", ID: id}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete trade link %s: %w", id, err)
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityTradeLink, id, audit.ActionDelete, user,
+		map[string]any{"purchaseRef": purchaseRef, "saleRef": saleRef})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trade link transaction: %w", err)
+	}
+	return nil
+}
+
+// ListLinks retrieves the links matching the filter, ordered by purchase and sale reference.
+//
+// Example:
+//
+//	links, err := repo.ListLinks(ctx, LinkFilter{TradeRef: "S-2026-0042"})
+func (r *RdsTradeLinkRepository) ListLinks(ctx context.Context, filter LinkFilter) ([]trade.TradeLink, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ID != "" {
+		add("id=$%d", filter.ID)
+	}
+	if filter.TradeRef != "" {
+		add("(purchase_ref=$%[1]d OR sale_ref=$%[1]d)", filter.TradeRef)
+	}
+
+	query := `SELECT id, purchase_ref, sale_ref, volume_mt, period_ids, audit_created_by, audit_created_at FROM trade_links`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY purchase_ref, sale_ref`
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []trade.TradeLink
+	for rows.Next() {
+		var l trade.TradeLink
+		if err := rows.Scan(&l.ID, &l.PurchaseRef, &l.SaleRef, &l.VolumeMT, pq.Array(&l.PeriodIDs),
+			&l.AuditInfo.CreatedBy, &l.AuditInfo.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trade link: %w", err)
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trade links: %w", err)
+	}
+	return links, nil
+}
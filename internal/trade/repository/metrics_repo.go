@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nholding/cso-book/internal/platform/metrics"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// MetricsTradeRepository decorates a TradeRepository with Prometheus metrics (see
+// metrics.RepositoryMetrics), labelled repository="trade".
+//
+// Example:
+//
+//	m := metrics.NewRepositoryMetrics(prometheus.DefaultRegisterer)
+//	trades := NewMetricsTradeRepository(rdsTrades, m)
+//	breakdowns := NewMetricsBreakdownRepository(rdsBreakdowns, m)
+type MetricsTradeRepository struct {
+	inner   TradeRepository
+	metrics *metrics.RepositoryMetrics
+}
+
+var _ TradeRepository = (*MetricsTradeRepository)(nil)
+
+func NewMetricsTradeRepository(inner TradeRepository, m *metrics.RepositoryMetrics) *MetricsTradeRepository {
+	return &MetricsTradeRepository{
+		inner:   inner,
+		metrics: m,
+	}
+}
+
+func (m *MetricsTradeRepository) observe(op string, start time.Time, rows int, err error) {
+	m.metrics.Observe("trade", op, start, rows, err)
+}
+
+func (m *MetricsTradeRepository) SaveTrade(ctx context.Context, t trade.Trade) error {
+	began := time.Now()
+	err := m.inner.SaveTrade(ctx, t)
+	m.observe("SaveTrade", began, 1, err)
+	return err
+}
+
+func (m *MetricsTradeRepository) UpdateTrade(ctx context.Context, t trade.Trade) error {
+	began := time.Now()
+	err := m.inner.UpdateTrade(ctx, t)
+	m.observe("UpdateTrade", began, 1, err)
+	return err
+}
+
+func (m *MetricsTradeRepository) GetTradeByID(ctx context.Context, id string) (trade.Trade, error) {
+	began := time.Now()
+	result, err := m.inner.GetTradeByID(ctx, id)
+	rows := 0
+	if result != nil {
+		rows = 1
+	}
+	m.observe("GetTradeByID", began, rows, err)
+	return result, err
+}
+
+func (m *MetricsTradeRepository) ListTrades(ctx context.Context, filter TradeFilter) ([]trade.Trade, error) {
+	began := time.Now()
+	result, err := m.inner.ListTrades(ctx, filter)
+	m.observe("ListTrades", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsTradeRepository) CountTrades(ctx context.Context, filter TradeFilter) (int, error) {
+	began := time.Now()
+	result, err := m.inner.CountTrades(ctx, filter)
+	m.observe("CountTrades", began, 1, err)
+	return result, err
+}
+
+func (m *MetricsTradeRepository) ListStatusChanges(ctx context.Context, filter StatusChangeFilter) ([]trade.StatusChange, error) {
+	began := time.Now()
+	result, err := m.inner.ListStatusChanges(ctx, filter)
+	m.observe("ListStatusChanges", began, len(result), err)
+	return result, err
+}
+
+// MetricsBreakdownRepository decorates a BreakdownRepository with Prometheus metrics,
+// labelled repository="breakdown". Writes count the breakdowns, not the rollups.
+type MetricsBreakdownRepository struct {
+	inner   BreakdownRepository
+	metrics *metrics.RepositoryMetrics
+}
+
+var _ BreakdownRepository = (*MetricsBreakdownRepository)(nil)
+
+func NewMetricsBreakdownRepository(inner BreakdownRepository, m *metrics.RepositoryMetrics) *MetricsBreakdownRepository {
+	return &MetricsBreakdownRepository{
+		inner:   inner,
+		metrics: m,
+	}
+}
+
+func (m *MetricsBreakdownRepository) observe(op string, start time.Time, rows int, err error) {
+	m.metrics.Observe("breakdown", op, start, rows, err)
+}
+
+func (m *MetricsBreakdownRepository) SaveBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error {
+	began := time.Now()
+	err := m.inner.SaveBreakdowns(ctx, tradeID, breakdowns, rollups)
+	m.observe("SaveBreakdowns", began, len(breakdowns), err)
+	return err
+}
+
+func (m *MetricsBreakdownRepository) ReplaceBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error {
+	began := time.Now()
+	err := m.inner.ReplaceBreakdowns(ctx, tradeID, breakdowns, rollups)
+	m.observe("ReplaceBreakdowns", began, len(breakdowns), err)
+	return err
+}
+
+func (m *MetricsBreakdownRepository) GetBreakdowns(ctx context.Context, tradeID string) ([]trade.TradeBreakdown, error) {
+	began := time.Now()
+	result, err := m.inner.GetBreakdowns(ctx, tradeID)
+	m.observe("GetBreakdowns", began, len(result), err)
+	return result, err
+}
+
+func (m *MetricsBreakdownRepository) GetBreakdownsByPeriod(ctx context.Context, periodID string) ([]trade.TradeBreakdown, error) {
+	began := time.Now()
+	result, err := m.inner.GetBreakdownsByPeriod(ctx, periodID)
+	m.observe("GetBreakdownsByPeriod", began, len(result), err)
+	return result, err
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/pgbulk"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// RollupRepository stores the materialized quarterly/yearly rollups of trades.
+type RollupRepository interface {
+	// ReplaceRollups atomically replaces ALL rollups of a trade.
+	ReplaceRollups(ctx context.Context, tradeID string, rollups []trade.TradeRollup) error
+
+	// GetRollups returns the stored rollups of a trade.
+	GetRollups(ctx context.Context, tradeID string) ([]trade.TradeRollup, error)
+
+	// GetRollupsByPeriod returns the rollups of all trades for one quarter or year,
+	// e.g. "2026" or "FY2026-Q1"; SUPERSEDED trades are left out. This is the query annual
+	// reporting is built on.
+	GetRollupsByPeriod(ctx context.Context, periodID string) ([]trade.TradeRollup, error)
+}
+
+type RdsRollupRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Reporting queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsRollupRepository(cfg *awsclient.Config) (*RdsRollupRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsRollupRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// ReplaceRollups deletes the existing rollups of a trade and inserts the given ones in one transaction.
+func (r *RdsRollupRepository) ReplaceRollups(ctx context.Context, tradeID string, rollups []trade.TradeRollup) error {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := ReplaceRollupsTx(ctx, tx.Tx, tradeID, rollups); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rollup transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceRollupsTx replaces the rollups of a trade inside an existing transaction.
+// Breakdown persistence must call this in the SAME transaction that writes the breakdowns,
+// so rollups can never drift from the months they summarize.
+func ReplaceRollupsTx(ctx context.Context, tx *sql.Tx, tradeID string, rollups []trade.TradeRollup) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM trade_rollups WHERE parent_trade_id=$1`, tradeID); err != nil {
+		return fmt.Errorf("failed to delete rollups of trade %s: %w", tradeID, err)
+	}
+
+	rows := make([][]any, 0, len(rollups))
+	for _, ro := range rollups {
+		rows = append(rows, []any{
+			tradeID,
+			ro.PeriodID,
+			string(ro.Calendar),
+			string(ro.Granularity),
+			ro.VolumeMT,
+			ro.TotalAmount,
+			ro.MonthCount,
+		})
+	}
+
+	if err := pgbulk.CopyIn(ctx, tx, "trade_rollups", []string{
+		"parent_trade_id", "period_id", "calendar", "granularity", "volume_mt", "total_amount", "month_count",
+	}, rows); err != nil {
+		return fmt.Errorf("failed to insert rollups of trade %s: %w", tradeID, err)
+	}
+
+	return nil
+}
+
+// GetRollups returns all rollups of one trade.
+func (r *RdsRollupRepository) GetRollups(ctx context.Context, tradeID string) ([]trade.TradeRollup, error) {
+	return r.queryRollups(ctx, `
+		SELECT parent_trade_id, period_id, calendar, granularity, volume_mt, total_amount, month_count
+		FROM trade_rollups WHERE parent_trade_id=$1
+	`, tradeID)
+}
+
+// GetRollupsByPeriod returns the rollups of all trades for one quarter or year, leaving out
+// SUPERSEDED trades like GetBreakdownsByPeriod.
+func (r *RdsRollupRepository) GetRollupsByPeriod(ctx context.Context, periodID string) ([]trade.TradeRollup, error) {
+	return r.queryRollups(ctx, `
+		SELECT parent_trade_id, period_id, calendar, granularity, volume_mt, total_amount, month_count
+		FROM trade_rollups r WHERE r.period_id=$1
+		  AND NOT EXISTS (SELECT 1 FROM trades t WHERE t.id=r.parent_trade_id AND t.status='`+string(trade.TradeStatusSuperseded)+`')
+	`, periodID)
+}
+
+func (r *RdsRollupRepository) queryRollups(ctx context.Context, query string, arg string) ([]trade.TradeRollup, error) {
+	rows, err := r.reader.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rollups: %w", err)
+	}
+	defer rows.Close()
+
+	var rollups []trade.TradeRollup
+	for rows.Next() {
+		var ro trade.TradeRollup
+		var calendar, granularity string
+		if err := rows.Scan(&ro.ParentTradeID, &ro.PeriodID, &calendar, &granularity, &ro.VolumeMT, &ro.TotalAmount, &ro.MonthCount); err != nil {
+			return nil, fmt.Errorf("failed to scan rollup row: %w", err)
+		}
+		ro.Calendar = period.CalendarType(calendar)
+		ro.Granularity = period.PeriodGranularity(granularity)
+		rollups = append(rollups, ro)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rollup rows: %w", err)
+	}
+
+	return rollups, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// StatusChangeFilter narrows ListStatusChanges. Zero fields match everything.
+//
+// Example (all trades cancelled in March 2026, with their reasons):
+//
+//	StatusChangeFilter{NewStatus: trade.TradeStatusCancelled,
+//	    ChangedFrom: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), ChangedTo: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+type StatusChangeFilter struct {
+	TradeID        string
+	TradeNumber    string // All versions of the deal with this number
+	Kind           trade.TradeKind
+	CounterpartyID string
+	OldStatus      trade.TradeStatus
+	NewStatus      trade.TradeStatus
+	ChangedBy      string
+	ChangedFrom    time.Time
+	ChangedTo      time.Time // Exclusive
+	Reason         string    // Entries whose reason contains this text, case-insensitively
+
+	// TransitionsOnly leaves out the notes written to the history without a status change,
+	// e.g. declared volumes and sent confirmations.
+	TransitionsOnly bool
+
+	Limit int // 0 returns all matching entries
+}
+
+// ListStatusChanges retrieves the status history entries matching the filter with the trade
+// they belong to, newest first. Deleted trades are included: their history stays on record.
+//
+// Example:
+//
+//	changes, err := repo.ListStatusChanges(ctx, StatusChangeFilter{NewStatus: trade.TradeStatusCancelled, ChangedFrom: from, ChangedTo: to})
+//	// → [{TradeNumber: "P-2026-0012", Kind: PURCHASE, CounterpartyID: "C42",
+//	//     OldStatus: CONFIRMED, NewStatus: CANCELLED, ChangedBy: "user@internal.local", Reason: "vessel unavailable"}, ...]
+func (r *RdsTradeRepository) ListStatusChanges(ctx context.Context, filter StatusChangeFilter) ([]trade.StatusChange, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TradeID != "" {
+		add("h.trade_id=$%d", filter.TradeID)
+	}
+	if filter.TradeNumber != "" {
+		add("t.trade_number=$%d", filter.TradeNumber)
+	}
+	if filter.Kind != "" {
+		add("t.kind=$%d", string(filter.Kind))
+	}
+	if filter.CounterpartyID != "" {
+		add("t.counterparty_id=$%d", filter.CounterpartyID)
+	}
+	if filter.OldStatus != "" {
+		add("h.old_status=$%d", string(filter.OldStatus))
+	}
+	if filter.NewStatus != "" {
+		add("h.new_status=$%d", string(filter.NewStatus))
+	}
+	if filter.ChangedBy != "" {
+		add("h.changed_by=$%d", filter.ChangedBy)
+	}
+	if !filter.ChangedFrom.IsZero() {
+		add("h.changed_at >= $%d", filter.ChangedFrom)
+	}
+	if !filter.ChangedTo.IsZero() {
+		add("h.changed_at < $%d", filter.ChangedTo)
+	}
+	if filter.Reason != "" {
+		add("h.reason ILIKE '%%' || $%d || '%%'", filter.Reason)
+	}
+	if filter.TransitionsOnly {
+		conditions = append(conditions, "h.old_status <> h.new_status")
+	}
+
+	query := `SELECT h.trade_id, COALESCE(t.trade_number, ''), t.kind, t.counterparty_id,
+		h.old_status, h.new_status, h.changed_at, h.changed_by, h.reason
+		FROM trade_status_history h JOIN trades t ON t.id = h.trade_id`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY h.changed_at DESC, h.seq DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade status changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []trade.StatusChange
+	for rows.Next() {
+		var (
+			c                          trade.StatusChange
+			kind, oldStatus, newStatus string
+		)
+		if err := rows.Scan(&c.TradeID, &c.TradeNumber, &kind, &c.CounterpartyID,
+			&oldStatus, &newStatus, &c.ChangedAt, &c.ChangedBy, &c.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan trade status change: %w", err)
+		}
+		c.Kind = trade.TradeKind(kind)
+		c.OldStatus = trade.TradeStatus(oldStatus)
+		c.NewStatus = trade.TradeStatus(newStatus)
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trade status changes: %w", err)
+	}
+	return changes, nil
+}
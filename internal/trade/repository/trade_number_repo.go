@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// TradeNumberRepository hands out the sequential part of human-readable trade numbers (see
+// trade.FormatTradeNumber): one sequence per trade kind and year, starting at 1.
+type TradeNumberRepository interface {
+	// NextNumber reserves and returns the next number of kind in year
+	NextNumber(ctx context.Context, kind trade.TradeKind, year int) (int, error)
+}
+
+// Compile-time check that RdsTradeNumberRepository satisfies TradeNumberRepository
+var _ TradeNumberRepository = (*RdsTradeNumberRepository)(nil)
+
+// RdsTradeNumberRepository keeps the sequences in the trade_number_sequences table, one
+// counter row per kind and year, rather than in Postgres sequences: a counter restarts every
+// year without DDL, and when it is incremented in the booking transaction a rolled back
+// booking gives its number back, so deal numbers have no gaps. Concurrent bookings of the same
+// kind wait for each other on the counter row until they commit.
+type RdsTradeNumberRepository struct {
+	db *sql.DB
+}
+
+func NewRdsTradeNumberRepository(cfg *awsclient.Config) (*RdsTradeNumberRepository, error) {
+	rdsClient, err := cfg.NewRDSClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsTradeNumberRepository{db: rdsClient.Client}, nil
+}
+
+// NextNumber increments the counter of kind in year, creating it on first use. Inside a
+// txn.Manager transaction the increment commits or rolls back with the booking.
+//
+// Example:
+//
+//	seq, err := repo.NextNumber(ctx, trade.TradeKindSale, 2026) // → 42
+//	number, _ := trade.FormatTradeNumber(trade.TradeKindSale, 2026, seq) // → "S-2026-0042"
+func (r *RdsTradeNumberRepository) NextNumber(ctx context.Context, kind trade.TradeKind, year int) (int, error) {
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var next int
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO trade_number_sequences (kind, year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (kind, year) DO UPDATE SET last_number = trade_number_sequences.last_number + 1
+		RETURNING last_number
+	`, string(kind), year).Scan(&next); err != nil {
+		return 0, fmt.Errorf("failed to reserve %s trade number for %d: %w", kind, year, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit trade number transaction: %w", err)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,971 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nholding/cso-book/internal/audit"
+	auditrepo "github.com/nholding/cso-book/internal/audit/repository"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/awsclient"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	"github.com/nholding/cso-book/internal/trade"
+)
+
+// TradeRepository stores parent trades (purchases and sales) together with their status history.
+// Breakdowns and rollups are stored separately, linked by the trade ID.
+type TradeRepository interface {
+	// SaveTrade inserts a new trade and its status history
+	SaveTrade(ctx context.Context, t trade.Trade) error
+
+	// UpdateTrade updates an existing trade, checked against its RowVersion (optimistic
+	// concurrency; a lost race returns *dberr.ConflictError), and appends new status history
+	UpdateTrade(ctx context.Context, t trade.Trade) error
+
+	// GetTradeByID retrieves a trade with its status history; returns nil, nil when it does
+	// not exist or is soft-deleted
+	GetTradeByID(ctx context.Context, id string) (trade.Trade, error)
+
+	// ListTrades retrieves the trades matching the filter, by default ordered by creation time
+	ListTrades(ctx context.Context, filter TradeFilter) ([]trade.Trade, error)
+
+	// CountTrades counts the trades matching the filter, ignoring its sort and page
+	CountTrades(ctx context.Context, filter TradeFilter) (int, error)
+
+	// ListStatusChanges retrieves the status history entries matching the filter across trades,
+	// newest first
+	ListStatusChanges(ctx context.Context, filter StatusChangeFilter) ([]trade.StatusChange, error)
+}
+
+// TradeFilter narrows ListTrades. Zero fields match everything.
+//
+// Example (all live, confirmed purchases from C42):
+//
+//	TradeFilter{Kind: trade.TradeKindPurchase, CounterpartyID: "C42", Status: trade.TradeStatusConfirmed}
+//
+// Example (blotter: the second page of 50 trades of one trader delivering in Q1 2026, newest first):
+//
+//	TradeFilter{PeriodID: "2026-Q1", CreatedBy: "trader@internal.local",
+//	    Sort: TradeSortCreatedAt, Descending: true, Limit: 50, Offset: 50}
+type TradeFilter struct {
+	Kind           trade.TradeKind
+	CounterpartyID string
+	Status         trade.TradeStatus
+	Statuses       []trade.TradeStatus // Any of these statuses
+	LocationID     string
+	ProductID      string
+	TradeNumber    string // All versions of the deal with this number
+	BusinessKey    string // Trades with the same commercial terms, see trade.BusinessKey
+	SplitFromID    string // The children of a split trade
+	PeriodID       string // Trades delivering in any part of this period, e.g. 2026-Q1
+	CreatedBy      string // Trades booked by this trader
+	CreatedFrom    time.Time
+	CreatedTo      time.Time // Exclusive
+	IncludeDeleted bool      // Also return soft-deleted trades
+
+	// Sort orders the result; empty means TradeSortCreatedAt. Ties are broken by ID, so pages
+	// are stable. Limit 0 returns all matching trades.
+	Sort       TradeSort
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// TradeSort is a column ListTrades can order by.
+type TradeSort string
+
+const (
+	TradeSortCreatedAt     TradeSort = "createdAt"
+	TradeSortTradeNumber   TradeSort = "tradeNumber"
+	TradeSortCounterparty  TradeSort = "counterparty"
+	TradeSortStatus        TradeSort = "status"
+	TradeSortDeliveryStart TradeSort = "deliveryStart"
+	TradeSortVolume        TradeSort = "volume"
+	TradeSortPrice         TradeSort = "price"
+)
+
+// tradeSortColumns maps every TradeSort to the SQL it orders by. Only these expressions ever
+// reach the ORDER BY clause.
+var tradeSortColumns = map[TradeSort]string{
+	TradeSortCreatedAt:     "audit_created_at",
+	TradeSortTradeNumber:   "trade_number",
+	TradeSortCounterparty:  "counterparty_id",
+	TradeSortStatus:        "status",
+	TradeSortDeliveryStart: tradeDeliveryStart,
+	TradeSortVolume:        "volume_mt",
+	TradeSortPrice:         "price_per_mt",
+}
+
+// tradeDeliveryStart and tradeDeliveryEnd are the first and last delivery day of a trade: its
+// delivery window when set, the dates of its period range otherwise.
+const (
+	tradeDeliveryStart = `COALESCE(delivery_start, (SELECT start_date FROM periods WHERE id=trades.start_period_id AND effective_to IS NULL))`
+	tradeDeliveryEnd   = `COALESCE(delivery_end, (SELECT end_date FROM periods WHERE id=trades.end_period_id AND effective_to IS NULL))`
+)
+
+// where builds the WHERE clause of the filter and its arguments.
+func (f TradeFilter) where() (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Kind != "" {
+		add("kind=$%d", string(f.Kind))
+	}
+	if f.CounterpartyID != "" {
+		add("counterparty_id=$%d", f.CounterpartyID)
+	}
+	if f.Status != "" {
+		add("status=$%d", string(f.Status))
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, st := range f.Statuses {
+			statuses[i] = string(st)
+		}
+		add("status=ANY($%d)", pq.Array(statuses))
+	}
+	if f.LocationID != "" {
+		add("location_id=$%d", f.LocationID)
+	}
+	if f.ProductID != "" {
+		add("product_id=$%d", f.ProductID)
+	}
+	if f.TradeNumber != "" {
+		add("trade_number=$%d", f.TradeNumber)
+	}
+	if f.BusinessKey != "" {
+		add("business_key=$%d", f.BusinessKey)
+	}
+	if f.SplitFromID != "" {
+		add("split_from_id=$%d", f.SplitFromID)
+	}
+	if f.PeriodID != "" {
+		add(`EXISTS (SELECT 1 FROM periods p WHERE p.id=$%d AND p.effective_to IS NULL
+			AND `+tradeDeliveryStart+` <= p.end_date AND `+tradeDeliveryEnd+` >= p.start_date)`, f.PeriodID)
+	}
+	if f.CreatedBy != "" {
+		add("audit_created_by=$%d", f.CreatedBy)
+	}
+	if !f.CreatedFrom.IsZero() {
+		add("audit_created_at >= $%d", f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		add("audit_created_at < $%d", f.CreatedTo)
+	}
+	if !f.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+// Compile-time check that RdsTradeRepository satisfies TradeRepository
+var _ TradeRepository = (*RdsTradeRepository)(nil)
+
+// RdsTradeRepository stores trades in Postgres. Purchases and sales share the trades table;
+// the kind column tells them apart. GetTradeByID reads from the writer, so a trade loaded for
+// an update always carries the latest RowVersion; ListTrades reads from the reader endpoint.
+type RdsTradeRepository struct {
+	db     *sql.DB // Writer
+	reader *sql.DB // Listing queries; the writer when no reader endpoint is configured
+}
+
+func NewRdsTradeRepository(cfg *awsclient.Config) (*RdsTradeRepository, error) {
+	writer, reader, err := cfg.NewRDSReadWriteClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the AWS RDS Client: %v", err)
+	}
+
+	return &RdsTradeRepository{db: writer.Client, reader: reader.Client}, nil
+}
+
+// SaveTrade inserts a new trade together with its complete status history in one transaction.
+// It fails when a trade with the same ID already exists.
+//
+// Example:
+//
+//	p, breakdowns := trade.NewPurchase(ps, "C42", pr, 10000, 3.5, "EUR", "user@internal.local")
+//	err := repo.SaveTrade(ctx, &p)
+func (r *RdsTradeRepository) SaveTrade(ctx context.Context, t trade.Trade) error {
+	tb := t.Base()
+
+	overrides, err := json.Marshal(policyOverrides(tb))
+	if err != nil {
+		return fmt.Errorf("failed to encode policy overrides of trade %s: %w", tb.ID, err)
+	}
+	pricing, err := pricingJSON(tb)
+	if err != nil {
+		return err
+	}
+	costs, err := json.Marshal(costComponents(tb))
+	if err != nil {
+		return fmt.Errorf("failed to encode costs of trade %s: %w", tb.ID, err)
+	}
+	storage, err := storageTermsJSON(t)
+	if err != nil {
+		return err
+	}
+	takeOrPay, err := takeOrPayJSON(tb)
+	if err != nil {
+		return err
+	}
+	tolerance, declared, err := toleranceJSON(tb)
+	if err != nil {
+		return err
+	}
+	laycans, err := laycansJSON(tb)
+	if err != nil {
+		return err
+	}
+	paymentTerms, err := paymentTermsJSON(tb)
+	if err != nil {
+		return err
+	}
+	vesselCalls, err := vesselCallsJSON(tb)
+	if err != nil {
+		return err
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO trades (
+			id, trade_number, business_key, kind, counterparty_id, swap_side, storage_terms, start_period_id, end_period_id,
+			delivery_start, delivery_end, breakdown_mode, allocation_mode, volume_mt, commodity, unit, quantity, price_per_mt,
+			availability_fee_per_mt, pricing, costs, take_or_pay, tolerance, declared_volumes, laycans, cancelled_period_ids,
+			location_id, product_id, currency, status, policy_overrides, previous_version_id, split_from_id, superseded_by_id,
+			deleted_at, deleted_by, row_version, audit_created_by, audit_created_at, audit_updated_by, audit_updated_at,
+			payment_terms, vessel_calls
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,1,$37,$38,$39,$40,$41,$42)
+	`,
+		tb.ID,
+		nullString(tb.TradeNumber),
+		nullString(tb.BusinessKey),
+		string(t.Kind()),
+		t.CounterpartyID(),
+		nullString(string(swapSide(t))),
+		storage,
+		nullString(tb.PeriodRange.StartPeriodID),
+		nullString(tb.PeriodRange.EndPeriodID),
+		tb.DeliveryStart,
+		tb.DeliveryEnd,
+		string(breakdownMode(tb)),
+		string(tb.Allocation()),
+		tb.VolumeMT,
+		tb.Commodity,
+		string(tb.BookedUnit()),
+		tb.BookedQuantity(),
+		tb.PricePerMT,
+		tb.AvailabilityFeePerMT,
+		pricing,
+		costs,
+		takeOrPay,
+		tolerance,
+		declared,
+		laycans,
+		pq.Array(cancelledPeriods(tb)),
+		nullString(tb.LocationID),
+		nullString(tb.ProductID),
+		tb.Currency,
+		string(tb.Status),
+		overrides,
+		nullString(tb.PreviousVersionID),
+		nullString(tb.SplitFromID),
+		nullString(tb.SupersededByID),
+		tb.DeletedAt,
+		tb.DeletedBy,
+		tb.AuditInfo.CreatedBy,
+		tb.AuditInfo.CreatedAt,
+		tb.AuditInfo.UpdatedBy,
+		tb.AuditInfo.UpdatedAt,
+		paymentTerms,
+		vesselCalls,
+	); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return &dberr.DuplicateError{Entity: "trade", ID: tb.ID, Err: err}
+		}
+		return fmt.Errorf("failed to insert trade %s: %w", tb.ID, err)
+	}
+
+	if err := insertStatusHistory(ctx, tx.Tx, tb.ID, tb.StatusAudit); err != nil {
+		return err
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, audit.NewEvent(audit.EntityTrade, tb.ID, audit.ActionCreate, tb.AuditInfo.CreatedBy,
+		map[string]any{"kind": string(t.Kind()), "tradeNumber": tb.TradeNumber, "counterpartyId": t.CounterpartyID(), "status": string(tb.Status)})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trade transaction: %w", err)
+	}
+
+	tb.RowVersion = 1
+	return nil
+}
+
+// UpdateTrade writes the current state of an existing trade. The kind of a trade and its
+// creation audit never change.
+//
+// The status history is append-only: entries of t.StatusAudit beyond those already stored are
+// inserted, stored entries are never rewritten.
+//
+// Updates are checked with optimistic concurrency: t must carry the RowVersion it was read
+// with. When the stored trade was changed in the meantime, nothing is written and a
+// *dberr.ConflictError is returned. On success t.RowVersion is incremented to match the DB.
+//
+// Example:
+//
+//	t, _ := repo.GetTradeByID(ctx, "T1")
+//	_ = t.Base().UpdateTradeStatus(trade.TradeStatusConfirmed, "recap signed", "user@internal.local")
+//	err := repo.UpdateTrade(ctx, t)
+func (r *RdsTradeRepository) UpdateTrade(ctx context.Context, t trade.Trade) error {
+	tb := t.Base()
+	expected := rowVersionOrDefault(tb.RowVersion)
+
+	overrides, err := json.Marshal(policyOverrides(tb))
+	if err != nil {
+		return fmt.Errorf("failed to encode policy overrides of trade %s: %w", tb.ID, err)
+	}
+	pricing, err := pricingJSON(tb)
+	if err != nil {
+		return err
+	}
+	costs, err := json.Marshal(costComponents(tb))
+	if err != nil {
+		return fmt.Errorf("failed to encode costs of trade %s: %w", tb.ID, err)
+	}
+	storage, err := storageTermsJSON(t)
+	if err != nil {
+		return err
+	}
+	takeOrPay, err := takeOrPayJSON(tb)
+	if err != nil {
+		return err
+	}
+	tolerance, declared, err := toleranceJSON(tb)
+	if err != nil {
+		return err
+	}
+	laycans, err := laycansJSON(tb)
+	if err != nil {
+		return err
+	}
+	paymentTerms, err := paymentTermsJSON(tb)
+	if err != nil {
+		return err
+	}
+	vesselCalls, err := vesselCallsJSON(tb)
+	if err != nil {
+		return err
+	}
+
+	tx, err := txn.Begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Lock the row first: the stored state decides between conflict, delete/restore and update
+	var actual int
+	var wasDeleted bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT row_version, deleted_at IS NOT NULL FROM trades WHERE id=$1 FOR UPDATE`, tb.ID,
+	).Scan(&actual, &wasDeleted)
+	if err == sql.ErrNoRows {
+		return &dberr.NotFoundError{Entity: "trade", ID: tb.ID}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read row version of trade %s: %w", tb.ID, err)
+	}
+	if actual != expected {
+		return &dberr.ConflictError{Entity: "trade", ID: tb.ID, ExpectedVersion: expected, ActualVersion: actual}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE trades
+		SET counterparty_id=$1, start_period_id=$2, end_period_id=$3, delivery_start=$4, delivery_end=$5,
+		    breakdown_mode=$6, allocation_mode=$7, volume_mt=$8, commodity=$9, unit=$10, quantity=$11,
+		    price_per_mt=$12, availability_fee_per_mt=$13, pricing=$14, costs=$15, take_or_pay=$16, tolerance=$17,
+		    declared_volumes=$18, laycans=$19, cancelled_period_ids=$20, location_id=$21, product_id=$22,
+		    currency=$23, status=$24, policy_overrides=$25, superseded_by_id=$26, deleted_at=$27, deleted_by=$28,
+		    business_key=$29, storage_terms=$30, audit_updated_by=$31, audit_updated_at=$32, payment_terms=$34,
+		    vessel_calls=$35, row_version=row_version+1
+		WHERE id=$33
+	`,
+		t.CounterpartyID(),
+		nullString(tb.PeriodRange.StartPeriodID),
+		nullString(tb.PeriodRange.EndPeriodID),
+		tb.DeliveryStart,
+		tb.DeliveryEnd,
+		string(breakdownMode(tb)),
+		string(tb.Allocation()),
+		tb.VolumeMT,
+		tb.Commodity,
+		string(tb.BookedUnit()),
+		tb.BookedQuantity(),
+		tb.PricePerMT,
+		tb.AvailabilityFeePerMT,
+		pricing,
+		costs,
+		takeOrPay,
+		tolerance,
+		declared,
+		laycans,
+		pq.Array(cancelledPeriods(tb)),
+		nullString(tb.LocationID),
+		nullString(tb.ProductID),
+		tb.Currency,
+		string(tb.Status),
+		overrides,
+		nullString(tb.SupersededByID),
+		tb.DeletedAt,
+		tb.DeletedBy,
+		nullString(tb.BusinessKey),
+		storage,
+		tb.AuditInfo.UpdatedBy,
+		time.Now().UTC(),
+		tb.ID,
+		paymentTerms,
+		vesselCalls,
+	); err != nil {
+		return fmt.Errorf("failed to update trade %s: %w", tb.ID, err)
+	}
+
+	var stored int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM trade_status_history WHERE trade_id=$1`, tb.ID,
+	).Scan(&stored); err != nil {
+		return fmt.Errorf("failed to count status history of trade %s: %w", tb.ID, err)
+	}
+	var added []trade.TradeStatusHistory
+	if stored < len(tb.StatusAudit) {
+		added = tb.StatusAudit[stored:]
+		if err := insertStatusHistory(ctx, tx.Tx, tb.ID, added); err != nil {
+			return err
+		}
+	}
+
+	if err := auditrepo.RecordTx(ctx, tx.Tx, tradeUpdateEvents(t, wasDeleted, added)...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trade transaction: %w", err)
+	}
+
+	tb.RowVersion = expected + 1
+	return nil
+}
+
+// tradeUpdateEvents returns the audit events of an UpdateTrade call: one per added status
+// history entry, plus a DELETE, RESTORE or UPDATE event for the trade itself.
+func tradeUpdateEvents(t trade.Trade, wasDeleted bool, added []trade.TradeStatusHistory) []audit.Event {
+	tb := t.Base()
+
+	events := make([]audit.Event, 0, len(added)+1)
+	for _, h := range added {
+		events = append(events, audit.NewEvent(audit.EntityTrade, tb.ID, audit.ActionStatusChange, h.ChangedBy,
+			map[string]any{"from": string(h.OldStatus), "to": string(h.NewStatus), "reason": h.Reason}))
+	}
+
+	switch {
+	case !wasDeleted && tb.IsDeleted():
+		actor := tb.AuditInfo.LastActor()
+		if tb.DeletedBy != nil {
+			actor = *tb.DeletedBy
+		}
+		events = append(events, audit.NewEvent(audit.EntityTrade, tb.ID, audit.ActionDelete, actor, nil))
+	case wasDeleted && !tb.IsDeleted():
+		events = append(events, audit.NewEvent(audit.EntityTrade, tb.ID, audit.ActionRestore, tb.AuditInfo.LastActor(), nil))
+	default:
+		events = append(events, audit.NewEvent(audit.EntityTrade, tb.ID, audit.ActionUpdate, tb.AuditInfo.LastActor(),
+			map[string]any{"volumeMT": tb.VolumeMT, "pricePerMT": tb.PricePerMT, "availabilityFeePerMT": tb.AvailabilityFeePerMT,
+				"currency": tb.Currency, "counterpartyId": t.CounterpartyID()}))
+	}
+
+	return events
+}
+
+// GetTradeByID retrieves a live trade with its status history: a *trade.Purchase, a
+// *trade.Ticket, a *trade.Swap or a *trade.StorageAgreement, depending on its kind.
+func (r *RdsTradeRepository) GetTradeByID(ctx context.Context, id string) (trade.Trade, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+tradeColumns+` FROM trades WHERE id=$1 AND deleted_at IS NULL`, id)
+
+	t, err := scanTrade(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan trade: %w", err)
+	}
+
+	if err := r.loadStatusHistory(ctx, r.db, []trade.Trade{t}); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ListTrades retrieves the trades matching the filter with their status history, in the
+// order and page the filter asks for; by default all of them, oldest first.
+//
+// Example:
+//
+//	sales, err := repo.ListTrades(ctx, TradeFilter{Kind: trade.TradeKindSale})
+func (r *RdsTradeRepository) ListTrades(ctx context.Context, filter TradeFilter) ([]trade.Trade, error) {
+	sortBy := filter.Sort
+	if sortBy == "" {
+		sortBy = TradeSortCreatedAt
+	}
+	column, ok := tradeSortColumns[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("cannot sort trades by %q", sortBy)
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, fmt.Errorf("limit and offset must not be negative, got %d and %d", filter.Limit, filter.Offset)
+	}
+	direction := ` ASC`
+	if filter.Descending {
+		direction = ` DESC`
+	}
+
+	where, args := filter.where()
+	query := `SELECT ` + tradeColumns + ` FROM trades` + where + ` ORDER BY ` + column + direction + `, id` + direction
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(` OFFSET %d`, filter.Offset)
+	}
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []trade.Trade
+	for rows.Next() {
+		t, err := scanTrade(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
+		}
+		trades = append(trades, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trade rows: %w", err)
+	}
+
+	if err := r.loadStatusHistory(ctx, r.reader, trades); err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+// CountTrades returns the number of trades matching filter, ignoring its sort and page, e.g.
+// to page through a blotter.
+func (r *RdsTradeRepository) CountTrades(ctx context.Context, filter TradeFilter) (int, error) {
+	where, args := filter.where()
+
+	var n int
+	if err := r.reader.QueryRowContext(ctx, `SELECT COUNT(*) FROM trades`+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count trades: %w", err)
+	}
+	return n, nil
+}
+
+// tradeColumns is the column list scanned by scanTrade.
+const tradeColumns = `id, trade_number, business_key, kind, counterparty_id, swap_side, storage_terms, start_period_id,
+	end_period_id, delivery_start, delivery_end, breakdown_mode, allocation_mode, volume_mt, commodity, unit, quantity,
+	price_per_mt, availability_fee_per_mt, pricing, costs, take_or_pay, tolerance, declared_volumes, laycans,
+	cancelled_period_ids, location_id, product_id, currency, status, policy_overrides, previous_version_id, split_from_id,
+	superseded_by_id, deleted_at, deleted_by, row_version, audit_created_by, audit_created_at, audit_updated_by, audit_updated_at,
+	payment_terms, vessel_calls`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTrade scans one row of tradeColumns into a *trade.Purchase, *trade.Ticket, *trade.Swap or
+// *trade.StorageAgreement.
+func scanTrade(row rowScanner) (trade.Trade, error) {
+	var (
+		tb                     trade.TradeBase
+		kind, counterpartyID   string
+		startPeriod, endPeriod sql.NullString
+		previous, supersededBy sql.NullString
+		splitFrom, side        sql.NullString
+		location, productID    sql.NullString
+		number, businessKey    sql.NullString
+		mode, allocation       string
+		status                 string
+		overrides, pricing     []byte
+		costs, storage         []byte
+		takeOrPay              []byte
+		tolerance, declared    []byte
+		laycans, paymentTerms  []byte
+		vesselCalls            []byte
+	)
+
+	if err := row.Scan(
+		&tb.ID,
+		&number,
+		&businessKey,
+		&kind,
+		&counterpartyID,
+		&side,
+		&storage,
+		&startPeriod,
+		&endPeriod,
+		&tb.DeliveryStart,
+		&tb.DeliveryEnd,
+		&mode,
+		&allocation,
+		&tb.VolumeMT,
+		&tb.Commodity,
+		&tb.Unit,
+		&tb.Quantity,
+		&tb.PricePerMT,
+		&tb.AvailabilityFeePerMT,
+		&pricing,
+		&costs,
+		&takeOrPay,
+		&tolerance,
+		&declared,
+		&laycans,
+		pq.Array(&tb.CancelledPeriodIDs),
+		&location,
+		&productID,
+		&tb.Currency,
+		&status,
+		&overrides,
+		&previous,
+		&splitFrom,
+		&supersededBy,
+		&tb.DeletedAt,
+		&tb.DeletedBy,
+		&tb.RowVersion,
+		&tb.AuditInfo.CreatedBy,
+		&tb.AuditInfo.CreatedAt,
+		&tb.AuditInfo.UpdatedBy,
+		&tb.AuditInfo.UpdatedAt,
+		&paymentTerms,
+		&vesselCalls,
+	); err != nil {
+		return nil, err
+	}
+
+	tb.PeriodRange = period.PeriodRange{StartPeriodID: startPeriod.String, EndPeriodID: endPeriod.String}
+	tb.BreakdownMode = trade.BreakdownMode(mode)
+	tb.AllocationMode = trade.AllocationMode(allocation)
+	tb.PreviousVersionID = previous.String
+	tb.SupersededByID = supersededBy.String
+	tb.SplitFromID = splitFrom.String
+	tb.LocationID = location.String
+	tb.ProductID = productID.String
+	tb.TradeNumber = number.String
+	tb.BusinessKey = businessKey.String
+	tb.Status = trade.TradeStatus(status)
+	if len(overrides) > 0 {
+		if err := json.Unmarshal(overrides, &tb.PolicyOverrides); err != nil {
+			return nil, fmt.Errorf("failed to decode policy overrides of trade %s: %w", tb.ID, err)
+		}
+	}
+
+	if len(pricing) > 0 {
+		tb.Pricing = &trade.PricingFormula{}
+		if err := json.Unmarshal(pricing, tb.Pricing); err != nil {
+			return nil, fmt.Errorf("failed to decode pricing formula of trade %s: %w", tb.ID, err)
+		}
+	}
+
+	if len(costs) > 0 {
+		if err := json.Unmarshal(costs, &tb.Costs); err != nil {
+			return nil, fmt.Errorf("failed to decode costs of trade %s: %w", tb.ID, err)
+		}
+	}
+
+	if len(takeOrPay) > 0 {
+		tb.TakeOrPay = &trade.TakeOrPayClause{}
+		if err := json.Unmarshal(takeOrPay, tb.TakeOrPay); err != nil {
+			return nil, fmt.Errorf("failed to decode take-or-pay clause of trade %s: %w", tb.ID, err)
+		}
+	}
+
+	if len(tolerance) > 0 {
+		tb.Tolerance = &trade.VolumeTolerance{}
+		if err := json.Unmarshal(tolerance, tb.Tolerance); err != nil {
+			return nil, fmt.Errorf("failed to decode volume tolerance of trade %s: %w", tb.ID, err)
+		}
+	}
+	if len(declared) > 0 {
+		if err := json.Unmarshal(declared, &tb.DeclaredVolumes); err != nil {
+			return nil, fmt.Errorf("failed to decode declared volumes of trade %s: %w", tb.ID, err)
+		}
+	}
+	if len(laycans) > 0 {
+		if err := json.Unmarshal(laycans, &tb.Laycans); err != nil {
+			return nil, fmt.Errorf("failed to decode laycans of trade %s: %w", tb.ID, err)
+		}
+	}
+	if len(paymentTerms) > 0 {
+		tb.PaymentTerms = &trade.PaymentTerms{}
+		if err := json.Unmarshal(paymentTerms, tb.PaymentTerms); err != nil {
+			return nil, fmt.Errorf("failed to decode payment terms of trade %s: %w", tb.ID, err)
+		}
+	}
+	if len(vesselCalls) > 0 {
+		if err := json.Unmarshal(vesselCalls, &tb.VesselCalls); err != nil {
+			return nil, fmt.Errorf("failed to decode vessel calls of trade %s: %w", tb.ID, err)
+		}
+	}
+
+	switch trade.TradeKind(kind) {
+	case trade.TradeKindPurchase:
+		return &trade.Purchase{TradeBase: tb, SupplierID: counterpartyID}, nil
+	case trade.TradeKindSale:
+		return &trade.Ticket{TradeBase: tb, BuyerID: counterpartyID}, nil
+	case trade.TradeKindSwap:
+		return &trade.Swap{TradeBase: tb, CounterpartyCompanyID: counterpartyID, Side: trade.SwapSide(side.String)}, nil
+	case trade.TradeKindStorage:
+		sa := &trade.StorageAgreement{TradeBase: tb, ProviderID: counterpartyID}
+		if len(storage) > 0 {
+			if err := json.Unmarshal(storage, &sa.Terms); err != nil {
+				return nil, fmt.Errorf("failed to decode storage terms of trade %s: %w", tb.ID, err)
+			}
+		}
+		return sa, nil
+	default:
+		return nil, fmt.Errorf("trade %s has unknown kind %q", tb.ID, kind)
+	}
+}
+
+// loadStatusHistory loads the status history of all given trades with a single query.
+func (r *RdsTradeRepository) loadStatusHistory(ctx context.Context, db *sql.DB, trades []trade.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*trade.TradeBase, len(trades))
+	ids := make([]string, 0, len(trades))
+	for _, t := range trades {
+		byID[t.Base().ID] = t.Base()
+		ids = append(ids, t.Base().ID)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT trade_id, old_status, new_status, changed_at, changed_by, reason
+		FROM trade_status_history
+		WHERE trade_id = ANY($1)
+		ORDER BY trade_id, seq
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query trade status history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			tradeID              string
+			oldStatus, newStatus string
+			h                    trade.TradeStatusHistory
+		)
+		if err := rows.Scan(&tradeID, &oldStatus, &newStatus, &h.ChangedAt, &h.ChangedBy, &h.Reason); err != nil {
+			return fmt.Errorf("failed to scan trade status history: %w", err)
+		}
+		h.OldStatus = trade.TradeStatus(oldStatus)
+		h.NewStatus = trade.TradeStatus(newStatus)
+
+		tb := byID[tradeID]
+		tb.StatusAudit = append(tb.StatusAudit, h)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate trade status history: %w", err)
+	}
+
+	return nil
+}
+
+// insertStatusHistory appends status history entries of a trade inside an existing transaction.
+func insertStatusHistory(ctx context.Context, tx *sql.Tx, tradeID string, history []trade.TradeStatusHistory) error {
+	for _, h := range history {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO trade_status_history (trade_id, old_status, new_status, changed_at, changed_by, reason)
+			VALUES ($1,$2,$3,$4,$5,$6)
+		`, tradeID, string(h.OldStatus), string(h.NewStatus), h.ChangedAt, h.ChangedBy, h.Reason); err != nil {
+			return fmt.Errorf("failed to insert status history of trade %s: %w", tradeID, err)
+		}
+	}
+	return nil
+}
+
+// breakdownMode maps the zero value of TradeBase.BreakdownMode to FULL_MONTHS, as documented.
+func breakdownMode(tb *trade.TradeBase) trade.BreakdownMode {
+	if tb.BreakdownMode == "" {
+		return trade.BreakdownFullMonths
+	}
+	return tb.BreakdownMode
+}
+
+// pricingJSON encodes the pricing formula of a trade; NULL for fixed-price trades.
+func pricingJSON(tb *trade.TradeBase) ([]byte, error) {
+	if tb.Pricing == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(tb.Pricing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pricing formula of trade %s: %w", tb.ID, err)
+	}
+	return b, nil
+}
+
+// takeOrPayJSON encodes the take-or-pay clause of a trade; trades without one store null.
+func takeOrPayJSON(tb *trade.TradeBase) ([]byte, error) {
+	if tb.TakeOrPay == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(tb.TakeOrPay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode take-or-pay clause of trade %s: %w", tb.ID, err)
+	}
+	return b, nil
+}
+
+// toleranceJSON encodes the volume tolerance and the declared volumes of a trade; trades
+// without them store null.
+func toleranceJSON(tb *trade.TradeBase) ([]byte, []byte, error) {
+	var tolerance, declared []byte
+	var err error
+	if tb.Tolerance != nil {
+		if tolerance, err = json.Marshal(tb.Tolerance); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode volume tolerance of trade %s: %w", tb.ID, err)
+		}
+	}
+	if len(tb.DeclaredVolumes) > 0 {
+		if declared, err = json.Marshal(tb.DeclaredVolumes); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode declared volumes of trade %s: %w", tb.ID, err)
+		}
+	}
+	return tolerance, declared, nil
+}
+
+// laycansJSON encodes the laycans of a trade; trades without one store null.
+func laycansJSON(tb *trade.TradeBase) ([]byte, error) {
+	if len(tb.Laycans) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(tb.Laycans)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode laycans of trade %s: %w", tb.ID, err)
+	}
+	return b, nil
+}
+
+// paymentTermsJSON encodes the payment terms of a trade; trades without them store null.
+func paymentTermsJSON(tb *trade.TradeBase) ([]byte, error) {
+	if tb.PaymentTerms == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(tb.PaymentTerms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payment terms of trade %s: %w", tb.ID, err)
+	}
+	return b, nil
+}
+
+// vesselCallsJSON encodes the vessel calls of a trade; trades without one store null.
+func vesselCallsJSON(tb *trade.TradeBase) ([]byte, error) {
+	if len(tb.VesselCalls) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(tb.VesselCalls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode vessel calls of trade %s: %w", tb.ID, err)
+	}
+	return b, nil
+}
+
+// storageTermsJSON encodes the terms of a storage agreement; other trades store null.
+func storageTermsJSON(t trade.Trade) ([]byte, error) {
+	sa, ok := t.(*trade.StorageAgreement)
+	if !ok {
+		return nil, nil
+	}
+	b, err := json.Marshal(sa.Terms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode storage terms of trade %s: %w", sa.ID, err)
+	}
+	return b, nil
+}
+
+// costComponents returns the costs of a trade, never nil, so the column holds [] instead of null.
+func costComponents(tb *trade.TradeBase) []trade.CostComponent {
+	if tb.Costs == nil {
+		return []trade.CostComponent{}
+	}
+	return tb.Costs
+}
+
+// swapSide returns the side of a swap, empty for physical trades.
+func swapSide(t trade.Trade) trade.SwapSide {
+	if sw, ok := t.(*trade.Swap); ok {
+		return sw.Side
+	}
+	return ""
+}
+
+// cancelledPeriods returns the cancelled months of a trade, never nil, so the column holds {}
+// instead of null.
+func cancelledPeriods(tb *trade.TradeBase) []string {
+	if tb.CancelledPeriodIDs == nil {
+		return []string{}
+	}
+	return tb.CancelledPeriodIDs
+}
+
+// policyOverrides returns the overrides of a trade, never nil, so the column holds [] instead of null.
+func policyOverrides(tb *trade.TradeBase) []trade.PolicyOverride {
+	if tb.PolicyOverrides == nil {
+		return []trade.PolicyOverride{}
+	}
+	return tb.PolicyOverrides
+}
+
+// rowVersionOrDefault maps the zero value of TradeBase.RowVersion to 1 (the first version).
+func rowVersionOrDefault(v int) int {
+	if v <= 0 {
+		return 1
+	}
+	return v
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,146 @@
+package trade
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// CancellationResult describes the breakdown changes required to cancel a trade.
+//
+//   - Removed:   breakdowns in OPEN/SOFT_CLOSED months. They may simply be deleted.
+//   - Reversals: new entries offsetting breakdowns in HARD_CLOSED months. Closed data is
+//     never touched; instead a negative entry is posted in the current open month,
+//     linked to the original via ReversalOfID/ReversedPeriodID.
+//
+// P&L explain reports reversals separately from regular deliveries by checking IsReversal(),
+// attributing the amount to the posting month while still naming the original delivery month.
+type CancellationResult struct {
+	Removed   []TradeBreakdown
+	Reversals []TradeBreakdown
+}
+
+// CancelTrade
+//
+// Purpose:
+//
+//	Cancels a trade following accounting practice for closed periods:
+//
+//	  2026-JAN (HARD_CLOSED)  +10,000 MT   → kept, reversed by:
+//	  2026-MAR (open)         -10,000 MT   ReversalOfID → JAN breakdown, ReversedPeriodID = 2026-JAN
+//	  2026-APR (open)         +10,000 MT   → removed
+//
+//	The trade status moves to CANCELLED; a reason is mandatory.
+//
+// Parameters:
+//   - trade:      the trade being cancelled
+//   - breakdowns: the trade's current breakdowns (reversal entries from earlier runs are ignored)
+//   - ps:         PeriodStore with up-to-date period statuses
+//   - at:         moment of cancellation; determines the posting month of reversals
+//
+// Returns an error (and changes nothing) when a reversal is needed but no open month exists.
+func CancelTrade(trade *TradeBase, breakdowns []TradeBreakdown, ps *period.PeriodStore, at time.Time, reason, cancelledBy string) (*CancellationResult, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to cancel trade %s", trade.ID)
+	}
+
+	result := &CancellationResult{}
+	var posting *period.Period
+
+	for _, bd := range breakdowns {
+		if bd.IsReversal() {
+			continue
+		}
+
+		p := ps.FindByID(bd.PeriodID)
+		if p == nil {
+			return nil, fmt.Errorf("breakdown %s references unknown period %s", bd.ID, bd.PeriodID)
+		}
+
+		if !p.IsHardClosed() {
+			result.Removed = append(result.Removed, bd)
+			continue
+		}
+
+		if posting == nil {
+			posting = ps.CurrentOpenMonth(at)
+			if posting == nil {
+				return nil, fmt.Errorf("no open period available after %s to post reversals of trade %s", at.Format("2006-01-02"), trade.ID)
+			}
+		}
+
+		result.Reversals = append(result.Reversals, newReversal(bd, posting, cancelledBy))
+	}
+
+	if err := trade.UpdateTradeStatus(TradeStatusCancelled, reason, cancelledBy); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReportedBreakdowns returns the breakdowns of a booked trade that count in positions and PnL.
+// A live trade reports all its breakdowns, a deleted or superseded one none. A cancelled trade
+// reports what CancelTrade left in the book: its breakdowns in HARD_CLOSED months, which were
+// already reported and stay, and the reversal entries offsetting them. Its breakdowns in months
+// that are still open were removed by the cancellation and are ignored.
+//
+// Example (Q1 purchase cancelled after JAN was hard-closed):
+//
+//	ReportedBreakdowns(bt, ps) // → [2026-JAN +1000 MT, 2026-MAR -1000 MT (reversal of 2026-JAN)]
+func ReportedBreakdowns(bt BookedTrade, ps *period.PeriodStore) []TradeBreakdown {
+	if IsLive(bt.Trade) {
+		return bt.Breakdowns
+	}
+	tb := bt.Trade.Base()
+	if tb.IsDeleted() || tb.Status != TradeStatusCancelled {
+		return nil
+	}
+
+	var reported []TradeBreakdown
+	for _, bd := range bt.Breakdowns {
+		if !bd.IsReversal() {
+			if p := ps.FindByID(bd.PeriodID); p == nil || !p.IsHardClosed() {
+				continue
+			}
+		}
+		reported = append(reported, bd)
+	}
+	return reported
+}
+
+// newReversal creates the offsetting entry of a breakdown, posted in the given month.
+func newReversal(original TradeBreakdown, posting *period.Period, createdBy string) TradeBreakdown {
+	originalID := original.ID
+	originalPeriodID := original.PeriodID
+
+	return TradeBreakdown{
+		ID:               utils.GenerateStableID(),
+		ParentTradeID:    original.ParentTradeID,
+		CounterpartyName: original.CounterpartyName,
+		Status:           original.Status,
+		Component:        original.Component,
+		PeriodID:         posting.ID,
+		StartDate:        posting.StartDate,
+		EndDate:          posting.EndDate,
+		MonthFraction:    original.MonthFraction,
+		VolumeMT:         -original.VolumeMT,
+		Unit:             original.Unit,
+		Quantity:         -original.Quantity,
+		PricePerMT:       original.PricePerMT,
+		PriceStatus:      original.PriceStatus,
+		Currency:         original.Currency,
+		TotalAmount:      -original.TotalAmount,
+		CostAmount:       -original.CostAmount,
+		// The reversal offsets the original at the original rate, so the book nets to zero
+		ReportingCurrency: original.ReportingCurrency,
+		FXRate:            original.FXRate,
+		ReportingAmount:   -original.ReportingAmount,
+		ReversalOfID:      &originalID,
+		ReversedPeriodID:  &originalPeriodID,
+		AuditInfo:         *audit.NewAuditInfo(createdBy),
+	}
+}
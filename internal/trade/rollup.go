@@ -0,0 +1,139 @@
+package trade
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+// rollupTolerance is the absolute difference allowed between a stored rollup and the
+// sum of its breakdowns before the consistency checker reports it (float rounding).
+const rollupTolerance = 1e-6
+
+// TradeRollup
+// Pre-aggregated totals of one trade for one quarter or year (Gregorian or fiscal).
+// Rollups are materialized next to the monthly breakdowns so annual and quarterly
+// reports don't have to sum months at query time.
+//
+// Example (trade T1, 10,000 MT/month Jan–Jun 2026 at 3.5):
+//
+//	TradeRollup{ParentTradeID: "T1", PeriodID: "2026-Q1", VolumeMT: 30000, TotalAmount: 105000, MonthCount: 3}
+//	TradeRollup{ParentTradeID: "T1", PeriodID: "2026",    VolumeMT: 60000, TotalAmount: 210000, MonthCount: 6}
+type TradeRollup struct {
+	ParentTradeID string
+	PeriodID      string
+	Calendar      period.CalendarType
+	Granularity   period.PeriodGranularity
+	VolumeMT      float64
+	TotalAmount   float64
+	MonthCount    int // Number of breakdown months that contributed to the rollup
+}
+
+// ComputeRollups aggregates the breakdowns of ONE trade into quarterly and yearly rollups
+// for every quarter/year (CAL and FY) in the store that contains at least one breakdown month.
+//
+// A breakdown belongs to a quarter/year when its month lies fully within the quarter/year's
+// date range — the same overlay rule used for fiscal calendars. Cancelled months are left out.
+//
+// The result is sorted by granularity (quarters before years), then by period start date.
+func ComputeRollups(breakdowns []TradeBreakdown, ps *period.PeriodStore) []TradeRollup {
+	var rollups []TradeRollup
+
+	for _, group := range [][]*period.Period{ps.Quarters(), ps.Years()} {
+		for _, agg := range group {
+			if agg == nil {
+				continue
+			}
+
+			r := TradeRollup{
+				PeriodID:    agg.ID,
+				Calendar:    agg.Calendar,
+				Granularity: agg.Granularity,
+			}
+
+			for _, bd := range breakdowns {
+				if bd.IsCancelled() {
+					continue
+				}
+				month := ps.FindByID(bd.PeriodID)
+				if month == nil {
+					continue
+				}
+
+				if !month.StartDate.Before(agg.StartDate) && !month.EndDate.After(agg.EndDate) {
+					r.ParentTradeID = bd.ParentTradeID
+					r.VolumeMT += bd.VolumeMT
+					r.TotalAmount += bd.TotalAmount
+					r.MonthCount++
+				}
+			}
+
+			if r.MonthCount > 0 {
+				rollups = append(rollups, r)
+			}
+		}
+	}
+
+	sort.SliceStable(rollups, func(i, j int) bool {
+		if rollups[i].Granularity != rollups[j].Granularity {
+			return rollups[i].Granularity == period.QuarterlyPeriod
+		}
+		return ps.FindByID(rollups[i].PeriodID).StartDate.Before(ps.FindByID(rollups[j].PeriodID).StartDate)
+	})
+
+	return rollups
+}
+
+// CheckRollupConsistency
+// Recomputes the rollups of one trade from its breakdowns and compares them with the
+// stored rollups. Returns one human-readable message per discrepancy:
+//
+//   - rollup missing for a quarter/year that has breakdown months
+//   - stale rollup for a quarter/year that no longer has breakdown months
+//   - volume, amount or month count that differs from the sum of the breakdowns
+//
+// Example:
+//
+//	errs := CheckRollupConsistency(breakdowns, storedRollups, ps)
+//	// → ["rollup T1/2026-Q1 volume 20000.000000 does not match breakdowns 30000.000000"]
+func CheckRollupConsistency(breakdowns []TradeBreakdown, stored []TradeRollup, ps *period.PeriodStore) []string {
+	expected := make(map[string]TradeRollup)
+	for _, r := range ComputeRollups(breakdowns, ps) {
+		expected[r.PeriodID] = r
+	}
+
+	var errs []string
+	seen := make(map[string]bool)
+
+	for _, s := range stored {
+		seen[s.PeriodID] = true
+
+		e, ok := expected[s.PeriodID]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("stale rollup %s/%s has no breakdown months", s.ParentTradeID, s.PeriodID))
+			continue
+		}
+
+		if math.Abs(s.VolumeMT-e.VolumeMT) > rollupTolerance {
+			errs = append(errs, fmt.Sprintf("rollup %s/%s volume %f does not match breakdowns %f", s.ParentTradeID, s.PeriodID, s.VolumeMT, e.VolumeMT))
+		}
+		if math.Abs(s.TotalAmount-e.TotalAmount) > rollupTolerance {
+			errs = append(errs, fmt.Sprintf("rollup %s/%s amount %f does not match breakdowns %f", s.ParentTradeID, s.PeriodID, s.TotalAmount, e.TotalAmount))
+		}
+		if s.MonthCount != e.MonthCount {
+			errs = append(errs, fmt.Sprintf("rollup %s/%s month count %d does not match breakdowns %d", s.ParentTradeID, s.PeriodID, s.MonthCount, e.MonthCount))
+		}
+	}
+
+	var missing []string
+	for id, e := range expected {
+		if !seen[id] {
+			missing = append(missing, fmt.Sprintf("missing rollup %s/%s", e.ParentTradeID, id))
+		}
+	}
+	sort.Strings(missing)
+
+	return append(errs, missing...)
+}
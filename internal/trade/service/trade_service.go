@@ -0,0 +1,2140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	company "github.com/nholding/cso-book/internal/company/domain"
+	"github.com/nholding/cso-book/internal/exposure"
+	"github.com/nholding/cso-book/internal/fx"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/platform/txn"
+	product "github.com/nholding/cso-book/internal/product/domain"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+	"github.com/nholding/cso-book/internal/units"
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// TradeService books and maintains trades: it validates them, generates their breakdowns and
+// rollups, and persists trade, breakdowns and rollups together.
+//
+// PURPOSE:
+//
+//	trade.NewPurchase only builds a trade in memory. TradeService is the persistent
+//	counterpart used by the application:
+//
+//	  - the periods a trade delivers in must exist and must not be HARD_CLOSED
+//	  - the booking pipeline (e.g. the booking window) runs before anything is written
+//	  - trade, breakdowns and rollups are written in ONE transaction when a txn.Manager is
+//	    given; the repositories record the audit events in that same transaction
+//
+// EXAMPLE USAGE:
+//
+//	tradeService := service.NewTradeService(tradeRepo, breakdownRepo, periodService.GetPeriodStore(),
+//	    txn.NewManager(rdsClient.Client), trade.BookingPipeline{bookingWindow})
+//
+//	p, breakdowns, warning, err := tradeService.CreatePurchase(ctx, service.TradeRequest{
+//	    CounterpartyID: "C42",
+//	    PeriodRange:    period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q2"},
+//	    VolumeMT:       10000,
+//	    PricePerMT:     3.5,
+//	    Currency:       "EUR",
+//	}, trade.BookingRequest{User: "user@internal.local"})
+type TradeService struct {
+	trades     repository.TradeRepository
+	breakdowns repository.BreakdownRepository
+	store      *period.PeriodStore
+	tx         *txn.Manager
+	booking    trade.BookingPipeline
+	units      *units.Converter
+
+	rates             fx.RateSource // nil: breakdowns are not converted
+	reportingCurrency string
+
+	quotes trade.IndexQuoteSource // nil: floating prices stay provisional
+
+	holidays *period.HolidayCalendar // nil: due dates only skip weekends
+
+	numbers repository.TradeNumberRepository // nil: trades get no trade number
+
+	deltas repository.BreakdownDeltaRepository // nil: amendments record no breakdown deltas
+
+	links repository.TradeLinkRepository // nil: trades cannot be linked back-to-back
+
+	rollups repository.RollupRepository // nil: CheckRollups has nothing to compare against
+
+	locations LocationChecker  // nil: location IDs are not checked
+	products  ProductCatalog   // nil: product IDs are not checked
+	companies CompanyDirectory // nil: counterparties are not checked
+
+	credit             CreditChecker // nil: confirmations are not credit checked
+	creditPolicy       CreditPolicy
+	creditOverrideRole string
+
+	compliance ComplianceChecker // nil: counterparties are not KYC checked
+}
+
+// CreditChecker projects the exposure to a trade's counterparty; *exposure.Service implements it.
+type CreditChecker interface {
+	// CheckTrade returns nil when the trade keeps the counterparty below its warn ratio
+	CheckTrade(ctx context.Context, t trade.Trade, breakdowns []trade.TradeBreakdown) (*exposure.Warning, error)
+}
+
+// CreditPolicy decides what ConfirmTrade does with a trade that takes its counterparty over
+// the credit limit. Trades that only come NEAR_LIMIT are always confirmed and flagged.
+type CreditPolicy string
+
+const (
+	CreditPolicyBlock CreditPolicy = "BLOCK" // The confirmation is rejected with an *exposure.LimitError
+	CreditPolicyFlag  CreditPolicy = "FLAG"  // The trade is confirmed; the breach is recorded in its status history
+)
+
+// Names of the service's checks in PolicyOverride records.
+const (
+	creditLimitPolicy      = "credit-limit"
+	duplicateBookingPolicy = "duplicate-booking"
+)
+
+// ComplianceChecker tells whether trades with a counterparty may be confirmed;
+// *companyservice.CompanyService implements it from the KYC and sanctions screening status.
+type ComplianceChecker interface {
+	// CheckCounterparty fails with a *company.ComplianceError when the company is not approved
+	CheckCounterparty(ctx context.Context, companyID string) error
+}
+
+// CompanyDirectory looks up counterparties in the company master data;
+// companyrepository.CompanyRepository implements it.
+type CompanyDirectory interface {
+	// FindByID returns nil, nil when the company does not exist
+	FindByID(ctx context.Context, id string) (*company.Company, error)
+}
+
+// LocationChecker validates the delivery location of a trade against the location master
+// data; *locationservice.LocationService implements it.
+type LocationChecker interface {
+	// CheckLocation fails when the location does not exist or may not be used for new trades
+	CheckLocation(ctx context.Context, id string) error
+}
+
+// NewTradeService creates a TradeService. store must be the live PeriodStore (see
+// PeriodService.GetPeriodStore), so period status changes are seen immediately. tx may be nil,
+// e.g. with in-memory repositories; trade and breakdowns are then written one after another.
+// Quantities are converted with units.DefaultCommodities; see SetUnitConverter.
+func NewTradeService(trades repository.TradeRepository, breakdowns repository.BreakdownRepository, store *period.PeriodStore, tx *txn.Manager, booking trade.BookingPipeline) *TradeService {
+	return &TradeService{
+		trades:     trades,
+		breakdowns: breakdowns,
+		store:      store,
+		tx:         tx,
+		booking:    booking,
+		units:      units.NewConverter(units.DefaultCommodities),
+	}
+}
+
+// SetUnitConverter replaces the converter used to express booked quantities in metric tonnes,
+// e.g. with a book's contractual density and energy factors.
+func (s *TradeService) SetUnitConverter(c *units.Converter) {
+	s.units = c
+}
+
+// SetFXRates makes the service convert the amounts of every new breakdown into the book's
+// reporting currency (see trade.ConvertToReporting). Booking then fails when a rate is missing.
+func (s *TradeService) SetFXRates(rates fx.RateSource, reportingCurrency string) {
+	s.rates = rates
+	s.reportingCurrency = reportingCurrency
+}
+
+// SetIndexQuotes sets the source of the index quotes that floating prices are evaluated with
+// (see trade.PriceBreakdowns).
+func (s *TradeService) SetIndexQuotes(quotes trade.IndexQuoteSource) {
+	s.quotes = quotes
+}
+
+// ProductCatalog resolves the product of a trade from the product master data;
+// *productservice.ProductService implements it.
+type ProductCatalog interface {
+	// ActiveProduct fails when the product does not exist or may not be used for new trades
+	ActiveProduct(ctx context.Context, id string) (*product.Product, error)
+}
+
+// SetProducts makes the service resolve the product of every new or amended trade: the product
+// must be active, and it supplies the commodity and the default booking unit.
+func (s *TradeService) SetProducts(products ProductCatalog) {
+	s.products = products
+}
+
+// SetHolidays sets the holiday calendar due dates are calculated on (see PaymentSchedule).
+func (s *TradeService) SetHolidays(cal *period.HolidayCalendar) {
+	s.holidays = cal
+}
+
+// SetTradeNumbers makes the service give every new trade a human-readable trade number, e.g.
+// P-2026-0001, numbered per kind and booking year. Numbers are reserved inside the booking
+// transaction, so pass a txn.Manager to NewTradeService to keep them free of gaps.
+func (s *TradeService) SetTradeNumbers(numbers repository.TradeNumberRepository) {
+	s.numbers = numbers
+}
+
+// SetBreakdownDeltas makes AmendTrade record what every amendment changed per delivery month
+// (see trade.DiffBreakdowns), in the amendment's transaction.
+func (s *TradeService) SetBreakdownDeltas(deltas repository.BreakdownDeltaRepository) {
+	s.deltas = deltas
+}
+
+// SetTradeLinks enables back-to-back links between purchases and sales (see LinkTrades).
+func (s *TradeService) SetTradeLinks(links repository.TradeLinkRepository) {
+	s.links = links
+}
+
+// SetRollups gives CheckRollups access to the stored rollups of trades.
+func (s *TradeService) SetRollups(rollups repository.RollupRepository) {
+	s.rollups = rollups
+}
+
+// SetCreditCheck makes ConfirmTrade check the counterparty's credit limit first (see
+// CreditPolicy). Users holding overrideRole may confirm a blocked trade with an override
+// reason, which is recorded as a PolicyOverride; an empty overrideRole allows no overrides.
+// Trades are checked when they are booked or amended as well; there the checker's warning
+// is returned with the trade but never blocks the booking.
+func (s *TradeService) SetCreditCheck(checker CreditChecker, policy CreditPolicy, overrideRole string) {
+	s.credit = checker
+	s.creditPolicy = policy
+	s.creditOverrideRole = overrideRole
+}
+
+// SetComplianceCheck makes ConfirmTrade reject trades with counterparties that are not
+// approved for trading (KYC not approved or expired, or an open sanctions hit).
+func (s *TradeService) SetComplianceCheck(checker ComplianceChecker) {
+	s.compliance = checker
+}
+
+// SetCompanies makes the service resolve the counterparty of every trade in the company master
+// data: unknown and merged (tombstoned) companies are rejected, and the company's display name
+// is copied into the breakdowns.
+func (s *TradeService) SetCompanies(companies CompanyDirectory) {
+	s.companies = companies
+}
+
+// SetLocations makes the service reject trades whose delivery location is unknown or inactive.
+func (s *TradeService) SetLocations(locations LocationChecker) {
+	s.locations = locations
+}
+
+// TradeRequest holds the economic terms of a new trade. Either PeriodRange or
+// DeliveryStart/DeliveryEnd (a custom-dated trade) must be set.
+//
+// Trades in tonnes set VolumeMT. Trades in another unit set Unit, Quantity and Commodity
+// instead, e.g. {Unit: units.Barrel, Quantity: 100000, Commodity: "crude"}; VolumeMT is then
+// derived from them.
+type TradeRequest struct {
+	CounterpartyID string // Supplier of a purchase, buyer of a sale
+	PeriodRange    period.PeriodRange
+	DeliveryStart  *time.Time
+	DeliveryEnd    *time.Time
+	BreakdownMode  trade.BreakdownMode  // Empty means FULL_MONTHS
+	AllocationMode trade.AllocationMode // Empty means PER_MONTH_FIXED: VolumeMT per month
+	VolumeMT       float64
+	Commodity      string
+	Unit           units.Unit            // Empty means MT
+	Quantity       float64               // In Unit; only used when Unit is not MT
+	PricePerMT     float64               // Fixed price, or the provisional price of a floating one
+	Pricing        *trade.PricingFormula // Nil means fixed at PricePerMT
+	Currency       string
+	Costs          []trade.CostComponent // Broker fee, inspection, freight, insurance
+	LocationID     string                // Delivery location; empty when not agreed yet
+	ProductID      string                // Product master data; sets Commodity and the default Unit
+	PaymentTerms   *trade.PaymentTerms   // Nil means trade.DefaultPaymentTerms
+}
+
+// CreatePurchase books a new purchase from req.CounterpartyID and persists it together with its
+// breakdowns and rollups. The purchase starts as DRAFT. With SetCreditCheck, the warning of
+// the credit check is returned as well; it does not stop the booking.
+func (s *TradeService) CreatePurchase(ctx context.Context, req TradeRequest, booking trade.BookingRequest) (*trade.Purchase, []trade.TradeBreakdown, *exposure.Warning, error) {
+	tb, err := s.newTradeBase(ctx, req, booking.User)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	p := &trade.Purchase{TradeBase: *tb, SupplierID: req.CounterpartyID}
+	breakdowns, warning, err := s.create(ctx, p, booking)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return p, breakdowns, warning, nil
+}
+
+// CreateSale books a new sale (a Ticket) to req.CounterpartyID and persists it together with
+// its breakdowns and rollups. The sale starts as DRAFT. With SetCreditCheck, a sale that takes
+// the counterparty near or over its credit limit is booked and returned with the warning.
+//
+// Example:
+//
+//	sale, breakdowns, warning, err := tradeService.CreateSale(ctx, req, booking)
+//	if warning != nil {
+//	    log.Printf("credit: %s", warning.Message) // "C42 exposure 5.2M EUR would exceed its limit of 5M EUR"
+//	}
+func (s *TradeService) CreateSale(ctx context.Context, req TradeRequest, booking trade.BookingRequest) (*trade.Ticket, []trade.TradeBreakdown, *exposure.Warning, error) {
+	tb, err := s.newTradeBase(ctx, req, booking.User)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	t := &trade.Ticket{TradeBase: *tb, BuyerID: req.CounterpartyID}
+	breakdowns, warning, err := s.create(ctx, t, booking)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return t, breakdowns, warning, nil
+}
+
+// CreateSwap books a new fixed-vs-index swap with req.CounterpartyID and persists it. req.Pricing
+// is the floating leg and req.PricePerMT the fixed price; the notional volume per month follows
+// the period range, breakdown and allocation modes like a physical trade. Swaps are settled in
+// cash (see SettleSwap), so no breakdowns or rollups are stored and they do not count in
+// positions. The swap starts as DRAFT.
+//
+// Example (pay 610 USD/MT fixed against the monthly average of ICE Brent in Q1 2026):
+//
+//	sw, err := tradeService.CreateSwap(ctx, TradeRequest{
+//	    CounterpartyID: "C42", PeriodRange: period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"},
+//	    VolumeMT: 5000, PricePerMT: 610, Currency: "USD",
+//	    Pricing: &trade.PricingFormula{Type: trade.PricingMonthlyAverage, Index: "ICE-BRENT"},
+//	}, trade.SwapPayFixed, trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) CreateSwap(ctx context.Context, req TradeRequest, side trade.SwapSide, booking trade.BookingRequest) (*trade.Swap, error) {
+	tb, err := s.newTradeBase(ctx, req, booking.User)
+	if err != nil {
+		return nil, err
+	}
+
+	sw := &trade.Swap{TradeBase: *tb, CounterpartyCompanyID: req.CounterpartyID, Side: side}
+	if err := validateTrade(sw); err != nil {
+		return nil, err
+	}
+	if err := s.checkDuplicate(ctx, sw, booking, ""); err != nil {
+		return nil, err
+	}
+	if _, err := s.counterpartyName(ctx, sw); err != nil {
+		return nil, err
+	}
+	// The notional schedule passes the same checks as deliveries; it is not stored
+	if _, err := s.prepare(sw, booking); err != nil {
+		return nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.assignTradeNumber(ctx, sw, booking); err != nil {
+			return err
+		}
+		if err := s.trades.SaveTrade(ctx, sw); err != nil {
+			return fmt.Errorf("failed to save trade %s: %w", sw.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sw, nil
+}
+
+// CreateStorage books a new storage or throughput agreement with the terminal operator
+// req.CounterpartyID and persists it together with its breakdowns, the monthly fee accruals
+// (see trade.StorageAgreement.AccrueFees). req.VolumeMT is the contracted capacity;
+// req.PricePerMT and req.Pricing are not used. The agreement starts as DRAFT.
+//
+// Example (20,000 MT of tank capacity in Rotterdam for 2026 at 85,000 EUR/month):
+//
+//	sa, breakdowns, err := tradeService.CreateStorage(ctx, TradeRequest{
+//	    CounterpartyID: "C17", PeriodRange: period.PeriodRange{StartPeriodID: "2026", EndPeriodID: "2026"},
+//	    VolumeMT: 20000, Currency: "EUR", LocationID: "NLRTM",
+//	}, trade.StorageTerms{Service: trade.StorageServiceStorage, FeePerPeriod: 85000}, trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) CreateStorage(ctx context.Context, req TradeRequest, terms trade.StorageTerms, booking trade.BookingRequest) (*trade.StorageAgreement, []trade.TradeBreakdown, error) {
+	tb, err := s.newTradeBase(ctx, req, booking.User)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sa := &trade.StorageAgreement{TradeBase: *tb, ProviderID: req.CounterpartyID, Terms: terms}
+	breakdowns, _, err := s.create(ctx, sa, booking) // Only sales add to the credit exposure
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sa, breakdowns, nil
+}
+
+// EvaluateTakeOrPay compares the actual volumes of a trade, in MT per delivery month, to the
+// obligations of its take-or-pay clause as of asOf and returns the liability per obligation
+// period (see trade.EvaluateTakeOrPay). The liability is owed by the book for purchases and
+// by the counterparty for sales.
+//
+// Example:
+//
+//	eval, err := tradeService.EvaluateTakeOrPay(ctx, "01J...", map[string]float64{"2026-JAN": 7000, "2026-FEB": 8200}, time.Now())
+func (s *TradeService) EvaluateTakeOrPay(ctx context.Context, tradeID string, actuals map[string]float64, asOf time.Time) (*trade.TakeOrPayEvaluation, error) {
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if s.store == nil {
+		return nil, errors.New("period store not initialised")
+	}
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+
+	return trade.EvaluateTakeOrPay(s.store, t, breakdowns, actuals, asOf)
+}
+
+// SettleSwap returns the monthly cash settlements of a swap as known on asOf (see
+// trade.SettleSwap). Months whose fixings are not all published yet are PROVISIONAL.
+func (s *TradeService) SettleSwap(ctx context.Context, tradeID string, asOf time.Time) ([]trade.SwapSettlement, error) {
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	sw, ok := t.(*trade.Swap)
+	if !ok {
+		return nil, fmt.Errorf("trade %s is a %s, not a swap", tradeID, t.Kind())
+	}
+	if s.store == nil {
+		return nil, errors.New("period store not initialised")
+	}
+
+	return trade.SettleSwap(sw, s.store, s.quotes, asOf)
+}
+
+// SetAvailabilityFee changes the availability fee of a purchase (see
+// trade.TradeBase.AvailabilityFeePerMT) as an amendment: the fee breakdowns are rebuilt and
+// the change is recorded in the trade's audit trail and version history, while the commodity
+// price stays as it is. A fee of 0 removes the fee component.
+//
+// Example:
+//
+//	next, _, err := tradeService.SetAvailabilityFee(ctx, "01J...", 0.25, "capacity fee agreed for 2026", trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) SetAvailabilityFee(ctx context.Context, id string, feePerMT float64, reason string, booking trade.BookingRequest) (trade.Trade, *exposure.Warning, error) {
+	t, err := s.GetTrade(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.Kind() != trade.TradeKindPurchase {
+		return nil, nil, fmt.Errorf("trade %s is a %s; only purchases carry an availability fee", id, t.Kind())
+	}
+
+	return s.AmendTrade(ctx, id, reason, func(tb *trade.TradeBase) {
+		tb.AvailabilityFeePerMT = feePerMT
+	}, booking)
+}
+
+// AmendTrade changes the terms of a trade and regenerates its breakdowns and rollups. apply
+// receives the TradeBase to amend; nothing is written when the amended trade fails validation
+// or the booking pipeline. Neither the current nor the amended delivery months may be
+// HARD_CLOSED.
+//
+//   - DRAFT and PENDING trades are amended in place; the trade is returned.
+//   - CONFIRMED trades are never rewritten: the trade is cloned into a new version (see
+//     TradeBase.NewVersion) which receives the amendment and is returned, and the original
+//     becomes SUPERSEDED. The new version gets its own breakdowns; those of the original are
+//     kept for audit but no longer count in the book. reason is mandatory here.
+//
+// With SetBreakdownDeltas the change in volume and value of every affected month is recorded
+// as well (see ListBreakdownDeltas). With SetCreditCheck the amended trade is credit checked
+// like a new one; the warning is returned with it.
+//
+// Example (correct the price of a confirmed trade):
+//
+//	next, warning, err := tradeService.AmendTrade(ctx, "01J...", "price correction", func(tb *trade.TradeBase) {
+//	    tb.PricePerMT = 3.6
+//	}, trade.BookingRequest{User: "user@internal.local"})
+//	// next.Base().PreviousVersionID → "01J..."
+func (s *TradeService) AmendTrade(ctx context.Context, id, reason string, apply func(tb *trade.TradeBase), booking trade.BookingRequest) (trade.Trade, *exposure.Warning, error) {
+	t, err := s.GetTrade(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if t.Kind() == trade.TradeKindSwap {
+		return nil, nil, fmt.Errorf("trade %s is a swap; cancel it and book a new one instead", id)
+	}
+
+	tb := t.Base()
+	switch tb.Status {
+	case trade.TradeStatusDraft, trade.TradeStatusPending, trade.TradeStatusConfirmed:
+	default:
+		return nil, nil, fmt.Errorf("trade %s is %s and cannot be amended", id, tb.Status)
+	}
+
+	// Months the trade delivered in before the amendment must still be open as well
+	current, err := s.breakdowns.GetBreakdowns(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", id, err)
+	}
+	if err := s.checkOpen(tb.ID, current); err != nil {
+		return nil, nil, err
+	}
+
+	if tb.Status == trade.TradeStatusConfirmed {
+		return s.supersede(ctx, t, current, reason, apply, booking)
+	}
+
+	previousLocation, previousProduct, previousKey := tb.LocationID, tb.ProductID, tb.BusinessKey
+	apply(tb)
+	tb.ID = id // The identity of a trade never changes
+	tb.AuditInfo.UpdateAuditInfo(booking.User)
+	if _, err := s.applyProduct(ctx, tb, previousProduct); err != nil {
+		return nil, nil, err
+	}
+	if err := s.convertQuantity(tb); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateTrade(t); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkDuplicate(ctx, t, booking, previousKey); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkLocation(ctx, tb, previousLocation); err != nil {
+		return nil, nil, err
+	}
+	counterparty, err := s.counterpartyName(ctx, t)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakdowns, err := s.prepare(t, booking)
+	if err != nil {
+		return nil, nil, err
+	}
+	setCounterpartyName(breakdowns, counterparty)
+	warning, err := s.checkExposure(ctx, t, breakdowns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to update trade %s: %w", id, err)
+		}
+		if err := s.breakdowns.ReplaceBreakdowns(ctx, id, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to replace breakdowns of trade %s: %w", id, err)
+		}
+		return s.recordDeltas(ctx, t, "", current, breakdowns, reason, booking.User)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, warning, nil
+}
+
+// supersede amends a CONFIRMED trade by a new version. In one transaction the new version is
+// saved with its breakdowns and the original is marked SUPERSEDED. The original keeps its
+// breakdowns for audit; positions and period queries (see
+// repository.BreakdownRepository.GetBreakdownsByPeriod) skip superseded trades, so they never
+// count both versions.
+func (s *TradeService) supersede(ctx context.Context, original trade.Trade, current []trade.TradeBreakdown, reason string, apply func(tb *trade.TradeBase), booking trade.BookingRequest) (trade.Trade, *exposure.Warning, error) {
+	ob := original.Base()
+	newID := utils.GenerateStableID()
+
+	nb, err := ob.NewVersion(newID, reason, booking.User)
+	if err != nil {
+		return nil, nil, err
+	}
+	apply(nb)
+	nb.ID = newID
+	nb.PreviousVersionID = ob.ID
+	if _, err := s.applyProduct(ctx, nb, ob.ProductID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.convertQuantity(nb); err != nil {
+		return nil, nil, err
+	}
+
+	next, err := withBase(original, nb)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateTrade(next); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkDuplicate(ctx, next, booking, ob.BusinessKey, ob.ID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkLocation(ctx, nb, ob.LocationID); err != nil {
+		return nil, nil, err
+	}
+	counterparty, err := s.counterpartyName(ctx, next)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakdowns, err := s.prepare(next, booking)
+	if err != nil {
+		return nil, nil, err
+	}
+	setCounterpartyName(breakdowns, counterparty)
+	warning, err := s.checkExposure(ctx, next, breakdowns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		// The new version first: the original references it
+		if err := s.trades.SaveTrade(ctx, next); err != nil {
+			return fmt.Errorf("failed to save trade %s (new version of %s): %w", newID, ob.ID, err)
+		}
+		if err := s.trades.UpdateTrade(ctx, original); err != nil {
+			return fmt.Errorf("failed to supersede trade %s: %w", ob.ID, err)
+		}
+		if err := s.breakdowns.SaveBreakdowns(ctx, newID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to save breakdowns of trade %s: %w", newID, err)
+		}
+		return s.recordDeltas(ctx, next, ob.ID, current, breakdowns, reason, booking.User)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return next, warning, nil
+}
+
+// recordDeltas stores what an amendment of t changed per delivery month, when the service
+// records breakdown deltas. previousID is the superseded version; empty for an amendment in
+// place.
+func (s *TradeService) recordDeltas(ctx context.Context, t trade.Trade, previousID string, before, after []trade.TradeBreakdown, reason, user string) error {
+	if s.deltas == nil {
+		return nil
+	}
+
+	tb := t.Base()
+	now := time.Now().UTC()
+	deltas := trade.DiffBreakdowns(before, after)
+	for i := range deltas {
+		deltas[i].TradeID = tb.ID
+		deltas[i].PreviousTradeID = previousID
+		deltas[i].TradeNumber = tb.TradeNumber
+		deltas[i].Reason = reason
+		deltas[i].AmendedBy = user
+		deltas[i].AmendedAt = now
+	}
+	if err := s.deltas.SaveDeltas(ctx, deltas); err != nil {
+		return fmt.Errorf("failed to record breakdown deltas of trade %s: %w", tb.ID, err)
+	}
+	return nil
+}
+
+// ListBreakdownDeltas returns what amendments changed per delivery month, oldest first.
+//
+// Example (every change to deal P-2026-0001):
+//
+//	deltas, err := tradeService.ListBreakdownDeltas(ctx, repository.DeltaFilter{TradeNumber: "P-2026-0001"})
+func (s *TradeService) ListBreakdownDeltas(ctx context.Context, filter repository.DeltaFilter) ([]trade.BreakdownDelta, error) {
+	if s.deltas == nil {
+		return nil, errors.New("breakdown deltas are not recorded")
+	}
+
+	deltas, err := s.deltas.ListDeltas(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list breakdown deltas: %w", err)
+	}
+	return deltas, nil
+}
+
+// SplitTrade divides a live trade into two child trades at a month or a volume (see
+// TradeBase.Split) and returns the children. In one transaction the children are saved with
+// newly generated breakdowns and the trade is marked SUPERSEDED; like an amended version it
+// keeps its breakdowns for audit (see supersede). Every month the trade delivers in must be open. The children are not checked for
+// duplicates: splitting by volume may well produce two trades with equal terms.
+//
+// Example (hand the second quarter of a half-year deal to another book):
+//
+//	children, err := tradeService.SplitTrade(ctx, "01J...", trade.SplitPoint{PeriodID: "2026-APR"},
+//	    "Q2 novated to C77", trade.BookingRequest{User: "user@internal.local"})
+//	// children[0]: 2026-Q1..2026-MAR, children[1]: 2026-APR..2026-Q2
+func (s *TradeService) SplitTrade(ctx context.Context, tradeID string, at trade.SplitPoint, reason string, booking trade.BookingRequest) ([]trade.Trade, error) {
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	parent, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	switch parent.Kind() {
+	case trade.TradeKindSwap, trade.TradeKindStorage:
+		return nil, fmt.Errorf("trade %s is a %s and cannot be split", tradeID, parent.Kind())
+	}
+	pb := parent.Base()
+
+	current, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+	if err := s.checkOpen(tradeID, current); err != nil {
+		return nil, err
+	}
+
+	first, second, err := pb.Split(s.store, at, current, utils.GenerateStableID(), utils.GenerateStableID(), reason, booking.User)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]trade.Trade, 0, 2)
+	childBreakdowns := make([][]trade.TradeBreakdown, 0, 2)
+	for _, nb := range []*trade.TradeBase{first, second} {
+		child, err := withBase(parent, nb)
+		if err != nil {
+			return nil, err
+		}
+		cb := child.Base()
+		if err := validateTrade(child); err != nil {
+			return nil, err
+		}
+		cb.BusinessKey = trade.BusinessKey(child)
+		counterparty, err := s.counterpartyName(ctx, child)
+		if err != nil {
+			return nil, err
+		}
+		breakdowns, err := s.prepare(child, booking)
+		if err != nil {
+			return nil, err
+		}
+		setCounterpartyName(breakdowns, counterparty)
+
+		children = append(children, child)
+		childBreakdowns = append(childBreakdowns, breakdowns)
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		for i, child := range children {
+			id := child.Base().ID
+			if err := s.trades.SaveTrade(ctx, child); err != nil {
+				return fmt.Errorf("failed to save trade %s (split from %s): %w", id, tradeID, err)
+			}
+			if err := s.breakdowns.SaveBreakdowns(ctx, id, childBreakdowns[i], trade.ComputeRollups(childBreakdowns[i], s.store)); err != nil {
+				return fmt.Errorf("failed to save breakdowns of trade %s: %w", id, err)
+			}
+		}
+		if err := s.trades.UpdateTrade(ctx, parent); err != nil {
+			return fmt.Errorf("failed to supersede split trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// CancelMonths cancels single delivery months of a CONFIRMED trade, e.g. after force majeure
+// (see TradeBase.CancelMonths), and returns the breakdowns as stored. The months must not be
+// HARD_CLOSED. Their breakdowns stay stored as CANCELLED; rollups are recomputed without them.
+//
+// Example:
+//
+//	breakdowns, err := tradeService.CancelMonths(ctx, "01J...", []string{"2026-FEB"},
+//	    "force majeure at the loading port", trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) CancelMonths(ctx context.Context, tradeID string, periodIDs []string, reason string, booking trade.BookingRequest) ([]trade.TradeBreakdown, error) {
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() == trade.TradeKindSwap {
+		return nil, fmt.Errorf("trade %s is a swap and has no deliveries to cancel", tradeID)
+	}
+	for _, id := range periodIDs {
+		if p := s.store.FindByID(id); p != nil && p.IsHardClosed() {
+			return nil, &dberr.PeriodClosedError{PeriodID: p.ID, Status: string(p.EffectiveStatus()), Op: "cancel deliveries of trade " + tradeID}
+		}
+	}
+
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+	if err := t.Base().CancelMonths(periodIDs, breakdowns, reason, booking.User); err != nil {
+		return nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to update trade %s: %w", tradeID, err)
+		}
+		if err := s.breakdowns.ReplaceBreakdowns(ctx, tradeID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to replace breakdowns of trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdowns, nil
+}
+
+// CancelTrade cancels a trade (see trade.CancelTrade) and returns its breakdowns as stored.
+// Breakdowns in months that are not HARD_CLOSED are removed; those in HARD_CLOSED months stay
+// and are offset by reversal entries posted in the current open month. The status change with
+// its history entry and the new breakdowns and rollups are written in one unit of work.
+//
+// Example (JAN hard-closed, Q1 trade cancelled in February):
+//
+//	breakdowns, err := tradeService.CancelTrade(ctx, "01J...", "counterparty default",
+//	    trade.BookingRequest{User: "user@internal.local"})
+//	// → [2026-JAN +10000 MT, 2026-FEB -10000 MT (reversal of 2026-JAN)]
+func (s *TradeService) CancelTrade(ctx context.Context, tradeID, reason string, booking trade.BookingRequest) ([]trade.TradeBreakdown, error) {
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+	if s.store == nil {
+		return nil, errors.New("period store not initialised")
+	}
+
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+
+	result, err := trade.CancelTrade(t.Base(), breakdowns, s.store, bookingTime(booking), reason, booking.User)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make(map[string]bool, len(result.Removed))
+	for _, bd := range result.Removed {
+		removed[bd.ID] = true
+	}
+	kept := make([]trade.TradeBreakdown, 0, len(breakdowns)+len(result.Reversals))
+	for _, bd := range breakdowns {
+		if !removed[bd.ID] {
+			kept = append(kept, bd)
+		}
+	}
+	kept = append(kept, result.Reversals...)
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to cancel trade %s: %w", tradeID, err)
+		}
+		if err := s.breakdowns.ReplaceBreakdowns(ctx, tradeID, kept, trade.ComputeRollups(kept, s.store)); err != nil {
+			return fmt.Errorf("failed to replace breakdowns of trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return kept, nil
+}
+
+// DeclareVolume records the final volume of one delivery month of a CONFIRMED trade within its
+// volume tolerance (see TradeBase.DeclareVolume) and returns the recalculated breakdowns as
+// stored. The month must not be HARD_CLOSED.
+//
+// Example (seller declares 10,400 MT for January under 10,000 MT ±5%):
+//
+//	breakdowns, err := tradeService.DeclareVolume(ctx, "01J...", "2026-JAN", 10400, trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) DeclareVolume(ctx context.Context, tradeID, periodID string, volumeMT float64, booking trade.BookingRequest) ([]trade.TradeBreakdown, error) {
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if p := s.store.FindByID(periodID); p != nil && p.IsHardClosed() {
+		return nil, &dberr.PeriodClosedError{PeriodID: p.ID, Status: string(p.EffectiveStatus()), Op: "declare the volume of trade " + tradeID}
+	}
+
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+	if err := t.Base().DeclareVolume(periodID, volumeMT, breakdowns, booking.User); err != nil {
+		return nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to update trade %s: %w", tradeID, err)
+		}
+		if err := s.breakdowns.ReplaceBreakdowns(ctx, tradeID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to replace breakdowns of trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdowns, nil
+}
+
+// RecordConfirmationSent records in the status history of a CONFIRMED trade that its
+// confirmation was sent to the given addresses (see TradeBase.RecordConfirmationSent).
+func (s *TradeService) RecordConfirmationSent(ctx context.Context, tradeID string, to []string, messageID string, booking trade.BookingRequest) error {
+	if booking.User == "" {
+		return errors.New("the booking user is required")
+	}
+
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return err
+	}
+	if err := t.Base().RecordConfirmationSent(to, messageID, booking.User); err != nil {
+		return err
+	}
+
+	return s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to update trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+}
+
+// SetLaycan sets the delivery window of one delivery month of a live trade, or clears it when
+// laycan is nil (see TradeBase.SetLaycan), and returns the breakdowns as stored. The window
+// must lie within the delivery of that month, which must not be HARD_CLOSED.
+//
+// Example:
+//
+//	breakdowns, err := tradeService.SetLaycan(ctx, "01J...", "2026-MAR",
+//	    &trade.Laycan{Start: mar10, End: mar12}, trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) SetLaycan(ctx context.Context, tradeID, periodID string, laycan *trade.Laycan, booking trade.BookingRequest) ([]trade.TradeBreakdown, error) {
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if p := s.store.FindByID(periodID); p != nil && p.IsHardClosed() {
+		return nil, &dberr.PeriodClosedError{PeriodID: p.ID, Status: string(p.EffectiveStatus()), Op: "set the laycan of trade " + tradeID}
+	}
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+	if err := t.Base().SetLaycan(periodID, laycan, breakdowns, booking.User); err != nil {
+		return nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to update trade %s: %w", tradeID, err)
+		}
+		if err := s.breakdowns.ReplaceBreakdowns(ctx, tradeID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to replace breakdowns of trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdowns, nil
+}
+
+// SetVesselCall records the vessel and laytime data of one delivery month of a live trade, or
+// clears it when call is nil (see TradeBase.SetVesselCall), and returns the breakdowns as
+// stored, with the demurrage of the month in their costs.
+//
+// Example:
+//
+//	breakdowns, err := tradeService.SetVesselCall(ctx, "01J...", "2026-MAR", &trade.VesselCall{
+//	    Vessel: "MT Nordic Star", AllowedLaytimeHours: 36, NoticeOfReadiness: nor, NoticeHours: 6,
+//	    Completed: &done, DemurrageRatePerDay: 24000, Side: trade.DemurragePayable,
+//	}, trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) SetVesselCall(ctx context.Context, tradeID, periodID string, call *trade.VesselCall, booking trade.BookingRequest) ([]trade.TradeBreakdown, error) {
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if p := s.store.FindByID(periodID); p != nil && p.IsHardClosed() {
+		return nil, &dberr.PeriodClosedError{PeriodID: p.ID, Status: string(p.EffectiveStatus()), Op: "record the vessel call of trade " + tradeID}
+	}
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+	if err := t.Base().SetVesselCall(periodID, call, breakdowns, booking.User); err != nil {
+		return nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to update trade %s: %w", tradeID, err)
+		}
+		if err := s.breakdowns.ReplaceBreakdowns(ctx, tradeID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to replace breakdowns of trade %s: %w", tradeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdowns, nil
+}
+
+// DemurrageClaims returns the demurrage claims (see trade.DemurrageClaims) of the live trades
+// matching the filter, e.g. of one counterparty or delivery period.
+//
+// Example:
+//
+//	claims, err := tradeService.DemurrageClaims(ctx, repository.TradeFilter{PeriodID: "2026-Q1"})
+//	// → [{TradeNumber: "P-2026-0012", PeriodID: "2026-MAR", Vessel: "MT Nordic Star", ExcessHours: 12, Amount: 12000, Side: PAYABLE}]
+func (s *TradeService) DemurrageClaims(ctx context.Context, filter repository.TradeFilter) ([]trade.DemurrageClaim, error) {
+	trades, err := s.ListTrades(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims []trade.DemurrageClaim
+	for _, t := range trades {
+		if trade.IsLive(t) {
+			claims = append(claims, trade.DemurrageClaims(t.Base())...)
+		}
+	}
+	return claims, nil
+}
+
+// LinkTrades
+//
+// PURPOSE:
+//
+//	Links a purchase and a sale back-to-back for volumeMT per month in the months both deliver
+//	in (see trade.NewTradeLink). The trades may be given in either order. The link is
+//	rejected when it would pass on more of a month than either trade delivers, counting the
+//	links both trades already have.
+//
+// EXAMPLE USAGE:
+//
+//	link, err := tradeService.LinkTrades(ctx, purchaseID, saleID, 6000, trade.BookingRequest{User: "user@internal.local"})
+func (s *TradeService) LinkTrades(ctx context.Context, tradeID, otherID string, volumeMT float64, booking trade.BookingRequest) (*trade.TradeLink, error) {
+	if s.links == nil {
+		return nil, errors.New("back-to-back links are not enabled")
+	}
+	if booking.User == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	a, err := s.GetBookedTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.GetBookedTrade(ctx, otherID)
+	if err != nil {
+		return nil, err
+	}
+
+	var link *trade.TradeLink
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		var existing []trade.TradeLink
+		for _, bt := range []*trade.BookedTrade{a, b} {
+			links, err := s.links.ListLinks(ctx, repository.LinkFilter{TradeRef: trade.TradeRef(bt.Trade.Base())})
+			if err != nil {
+				return fmt.Errorf("failed to load the links of trade %s: %w", bt.Trade.Base().ID, err)
+			}
+			existing = append(existing, links...)
+		}
+
+		link, err = trade.NewTradeLink(*a, *b, volumeMT, existing, booking.User)
+		if err != nil {
+			return err
+		}
+		if err := s.links.SaveLink(ctx, link); err != nil {
+			return fmt.Errorf("failed to save link %s ↔ %s: %w", link.PurchaseRef, link.SaleRef, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// UnlinkTrades removes a back-to-back link.
+func (s *TradeService) UnlinkTrades(ctx context.Context, linkID string, booking trade.BookingRequest) error {
+	if s.links == nil {
+		return errors.New("back-to-back links are not enabled")
+	}
+	if booking.User == "" {
+		return errors.New("the booking user is required")
+	}
+	if err := s.links.DeleteLink(ctx, linkID, booking.User); err != nil {
+		return fmt.Errorf("failed to delete trade link %s: %w", linkID, err)
+	}
+	return nil
+}
+
+// LinkMargins returns the margin view (see trade.ComputeLinkMargin) of every back-to-back link
+// of a trade, computed on the current versions of the linked trades.
+//
+// Example:
+//
+//	margins, err := tradeService.LinkMargins(ctx, purchaseID)
+//	// → [{Link: {PurchaseRef: "P-2026-0012", SaleRef: "S-2026-0042", VolumeMT: 6000}, Currency: "EUR",
+//	//     Months: [{PeriodID: "2026-JAN", MarginPerMT: 17.45, Margin: 104700}, ...], TotalMargin: 314100}]
+func (s *TradeService) LinkMargins(ctx context.Context, tradeID string) ([]trade.LinkMargin, error) {
+	if s.links == nil {
+		return nil, errors.New("back-to-back links are not enabled")
+	}
+
+	bt, err := s.GetBookedTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	ref := trade.TradeRef(bt.Trade.Base())
+	links, err := s.links.ListLinks(ctx, repository.LinkFilter{TradeRef: ref})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the links of trade %s: %w", tradeID, err)
+	}
+
+	margins := make([]trade.LinkMargin, 0, len(links))
+	for _, l := range links {
+		otherRef := l.SaleRef
+		if otherRef == ref {
+			otherRef = l.PurchaseRef
+		}
+		other, err := s.liveByRef(ctx, otherRef)
+		if err != nil {
+			return nil, err
+		}
+
+		purchase, sale := *bt, *other
+		if bt.Trade.Kind() == trade.TradeKindSale {
+			purchase, sale = *other, *bt
+		}
+		m, err := trade.ComputeLinkMargin(l, purchase, sale)
+		if err != nil {
+			return nil, err
+		}
+		margins = append(margins, *m)
+	}
+	return margins, nil
+}
+
+// liveByRef returns the live version of the trade with a trade.TradeRef: the live trade with
+// that trade number, or the trade with that ID.
+func (s *TradeService) liveByRef(ctx context.Context, ref string) (*trade.BookedTrade, error) {
+	versions, err := s.trades.ListTrades(ctx, repository.TradeFilter{TradeNumber: ref})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trade %s: %w", ref, err)
+	}
+	for _, t := range versions {
+		if trade.IsLive(t) {
+			return s.GetBookedTrade(ctx, t.Base().ID)
+		}
+	}
+	return s.GetBookedTrade(ctx, ref)
+}
+
+// withBase returns a trade of the same type and counterparty as original with the TradeBase tb.
+func withBase(original trade.Trade, tb *trade.TradeBase) (trade.Trade, error) {
+	switch o := original.(type) {
+	case *trade.Purchase:
+		return &trade.Purchase{TradeBase: *tb, SupplierID: o.SupplierID}, nil
+	case *trade.Ticket:
+		return &trade.Ticket{TradeBase: *tb, BuyerID: o.BuyerID}, nil
+	case *trade.Swap:
+		return &trade.Swap{TradeBase: *tb, CounterpartyCompanyID: o.CounterpartyCompanyID, Side: o.Side}, nil
+	case *trade.StorageAgreement:
+		return &trade.StorageAgreement{TradeBase: *tb, ProviderID: o.ProviderID, Terms: o.Terms}, nil
+	default:
+		return nil, fmt.Errorf("trade %s has unsupported type %T", original.Base().ID, original)
+	}
+}
+
+// ConfirmTrade moves a DRAFT or PENDING trade to CONFIRMED once the recap is signed.
+//
+// PURPOSE:
+//
+//	With a credit check configured (SetCreditCheck) the counterparty's exposure including the
+//	trade is projected first, and the decision is written into the status history next to
+//	the confirmation:
+//
+//	  - below the warn ratio, or no limit: confirmed, "credit check passed"
+//	  - NEAR_LIMIT, or a BREACH under CreditPolicyFlag: confirmed and flagged
+//	  - a BREACH under CreditPolicyBlock: the trade keeps its status, the rejection is
+//	    recorded in its status history and an *exposure.LimitError is returned, unless the
+//	    user holds the override role and gives booking.OverrideReason
+//
+//	The warning of the credit check, if any, is returned in all cases.
+//
+//	With a compliance check configured (SetComplianceCheck) a counterparty that is not
+//	approved for trading blocks the confirmation before the credit check: the rejection is
+//	recorded in the status history and a *company.ComplianceError is returned.
+//
+// EXAMPLE USAGE:
+//
+//	t, warning, err := tradeService.ConfirmTrade(ctx, "01J...", "recap signed",
+//	    trade.BookingRequest{User: "user@internal.local"})
+//	var limitErr *exposure.LimitError
+//	if errors.As(err, &limitErr) {
+//	    // e.g. "trade 01J... cannot be confirmed: C42 exposure 5200000.00 EUR would exceed its limit of 5000000.00 EUR"
+//	}
+func (s *TradeService) ConfirmTrade(ctx context.Context, id, reason string, booking trade.BookingRequest) (trade.Trade, *exposure.Warning, error) {
+	if booking.User == "" {
+		return nil, nil, errors.New("the confirming user is required")
+	}
+
+	t, err := s.GetTrade(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	tb := t.Base()
+	switch tb.Status {
+	case trade.TradeStatusDraft, trade.TradeStatusPending:
+	default:
+		return nil, nil, fmt.Errorf("trade %s is %s and cannot be confirmed", id, tb.Status)
+	}
+
+	if err := s.checkCompliance(ctx, t, booking); err != nil {
+		return nil, nil, err
+	}
+
+	warning, decision, err := s.checkCredit(ctx, t, booking)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if decision == "" {
+		// Blocked: keep the status, but keep the decision as well
+		tb.RecordStatusNote("confirmation blocked by credit check: "+warning.Message, booking.User)
+		if err := s.trades.UpdateTrade(ctx, t); err != nil {
+			return nil, nil, fmt.Errorf("failed to record blocked confirmation of trade %s: %w", id, err)
+		}
+		return nil, warning, &exposure.LimitError{Warning: *warning}
+	}
+
+	if reason != "" {
+		decision = reason + "; " + decision
+	}
+	if err := tb.UpdateTradeStatus(trade.TradeStatusConfirmed, decision, booking.User); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.trades.UpdateTrade(ctx, t); err != nil {
+		return nil, nil, fmt.Errorf("failed to confirm trade %s: %w", id, err)
+	}
+	return t, warning, nil
+}
+
+// checkCompliance rejects the confirmation of a trade with a counterparty that is not approved
+// for trading. The rejection is recorded in the trade's status history, like a blocked credit
+// check.
+func (s *TradeService) checkCompliance(ctx context.Context, t trade.Trade, booking trade.BookingRequest) error {
+	if s.compliance == nil {
+		return nil
+	}
+
+	err := s.compliance.CheckCounterparty(ctx, t.CounterpartyID())
+	var rejected *company.ComplianceError
+	if !errors.As(err, &rejected) {
+		return err
+	}
+
+	tb := t.Base()
+	tb.RecordStatusNote("confirmation blocked by compliance check: "+rejected.Reason, booking.User)
+	if err := s.trades.UpdateTrade(ctx, t); err != nil {
+		return fmt.Errorf("failed to record blocked confirmation of trade %s: %w", tb.ID, err)
+	}
+	return fmt.Errorf("trade %s cannot be confirmed: %w", tb.ID, rejected)
+}
+
+// checkCredit runs the credit check of a confirmation. It returns the status history note of
+// an allowed confirmation, or "" when the confirmation is blocked. An allowed override is
+// recorded on the trade as a PolicyOverride.
+func (s *TradeService) checkCredit(ctx context.Context, t trade.Trade, booking trade.BookingRequest) (*exposure.Warning, string, error) {
+	if s.credit == nil {
+		return nil, "confirmed without credit check", nil
+	}
+
+	tb := t.Base()
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tb.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load breakdowns of trade %s: %w", tb.ID, err)
+	}
+	warning, err := s.credit.CheckTrade(ctx, t, trade.LiveBreakdowns(breakdowns))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check credit of trade %s: %w", tb.ID, err)
+	}
+
+	switch {
+	case warning == nil:
+		return nil, "credit check passed", nil
+	case warning.Level != exposure.LevelBreach || s.creditPolicy != CreditPolicyBlock:
+		return warning, fmt.Sprintf("credit check flagged %s: %s", warning.Level, warning.Message), nil
+	case s.creditOverrideRole == "" || !booking.HasRole(s.creditOverrideRole):
+		return warning, "", nil
+	case booking.OverrideReason == "":
+		return nil, "", fmt.Errorf("trade %s exceeds the credit limit of %s: an override reason is required", tb.ID, t.CounterpartyID())
+	}
+
+	at := bookingTime(booking)
+	tb.PolicyOverrides = append(tb.PolicyOverrides, trade.PolicyOverride{
+		Policy:     creditLimitPolicy,
+		User:       booking.User,
+		Role:       s.creditOverrideRole,
+		Reason:     booking.OverrideReason,
+		At:         at.UTC(),
+		LocalTime:  at.UTC().Format("2006-01-02 15:04 MST"),
+		Violations: warning.Message,
+	})
+	return warning, "credit limit overridden: " + warning.Message, nil
+}
+
+// checkExposure projects the counterparty's exposure with a trade that is booked or amended.
+// The warning is only reported: booking is never blocked, ConfirmTrade enforces the limit.
+func (s *TradeService) checkExposure(ctx context.Context, t trade.Trade, breakdowns []trade.TradeBreakdown) (*exposure.Warning, error) {
+	if s.credit == nil {
+		return nil, nil
+	}
+
+	warning, err := s.credit.CheckTrade(ctx, t, trade.LiveBreakdowns(breakdowns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check credit of trade %s: %w", t.Base().ID, err)
+	}
+	return warning, nil
+}
+
+// GetTrade returns a live trade: a *trade.Purchase or a *trade.Ticket. It fails with a
+// *dberr.NotFoundError when the trade does not exist or is soft-deleted.
+func (s *TradeService) GetTrade(ctx context.Context, id string) (trade.Trade, error) {
+	t, err := s.trades.GetTradeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trade %s: %w", id, err)
+	}
+	if t == nil {
+		return nil, &dberr.NotFoundError{Entity: "trade", ID: id}
+	}
+
+	return t, nil
+}
+
+// GetBookedTrade returns a live trade with its live breakdowns. It fails with a
+// *dberr.NotFoundError when the trade does not exist or is soft-deleted.
+func (s *TradeService) GetBookedTrade(ctx context.Context, id string) (*trade.BookedTrade, error) {
+	t, err := s.GetTrade(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", id, err)
+	}
+	return &trade.BookedTrade{Trade: t, Breakdowns: trade.LiveBreakdowns(breakdowns)}, nil
+}
+
+// PaymentSchedule returns when the live breakdowns of a trade are due under its payment terms
+// (see trade.PaymentSchedule), on the holiday calendar set through SetHolidays.
+//
+// Example:
+//
+//	schedule, err := tradeService.PaymentSchedule(ctx, "01J...")
+//	// → [{PeriodID: "2026-JAN", DueDate: 2026-02-11, Amount: 496000, Terms: "30 days after B/L"}, ...]
+func (s *TradeService) PaymentSchedule(ctx context.Context, tradeID string) ([]trade.PaymentDue, error) {
+	bt, err := s.GetBookedTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	return trade.PaymentSchedule(s.holidays, bt.Trade, bt.Breakdowns), nil
+}
+
+// maxVersionChain bounds GetTradeHistory, so a corrupt chain (a cycle) cannot loop forever.
+const maxVersionChain = 1000
+
+// GetTradeHistory
+//
+// PURPOSE:
+//
+//	Back-office dispute resolution: "what exactly did we confirm, and what changed since?".
+//	Returns every version of a trade, oldest first, with the field-level changes between
+//	consecutive versions. Any trade ID of the chain may be given; the chain is followed
+//	backwards through PreviousVersionID and forwards through SupersededByID.
+//
+// EXAMPLE USAGE:
+//
+//	history, err := tradeService.GetTradeHistory(ctx, "01J...")
+//	for _, v := range history {
+//	    fmt.Println(v.Trade.Base().ID, v.Trade.Base().Status)
+//	    for _, c := range v.Changes {
+//	        fmt.Printf("  %s: %s → %s\n", c.Field, c.Old, c.New) // pricePerMT: 3.5 → 3.6
+//	    }
+//	}
+func (s *TradeService) GetTradeHistory(ctx context.Context, tradeID string) ([]trade.TradeVersion, error) {
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{tradeID: true}
+	follow := func(id string) (trade.Trade, error) {
+		if seen[id] || len(seen) >= maxVersionChain {
+			return nil, fmt.Errorf("version chain of trade %s is corrupt: trade %s is reached twice", tradeID, id)
+		}
+		seen[id] = true
+
+		v, err := s.trades.GetTradeByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load version %s of trade %s: %w", id, tradeID, err)
+		}
+		if v == nil {
+			return nil, &dberr.NotFoundError{Entity: "trade", ID: id, Reason: "is missing from the version chain of trade " + tradeID}
+		}
+		return v, nil
+	}
+
+	var older []trade.Trade
+	for prev := t.Base().PreviousVersionID; prev != ""; {
+		v, err := follow(prev)
+		if err != nil {
+			return nil, err
+		}
+		older = append(older, v)
+		prev = v.Base().PreviousVersionID
+	}
+
+	chain := make([]trade.Trade, 0, len(older)+1)
+	for i := len(older) - 1; i >= 0; i-- {
+		chain = append(chain, older[i])
+	}
+	chain = append(chain, t)
+
+	for next := t.Base().SupersededByID; next != ""; {
+		v, err := follow(next)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, v)
+		next = v.Base().SupersededByID
+	}
+
+	return trade.BuildVersionHistory(chain), nil
+}
+
+// ListTrades returns the trades matching filter, by default all of them, oldest first.
+func (s *TradeService) ListTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.Trade, error) {
+	trades, err := s.trades.ListTrades(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trades: %w", err)
+	}
+
+	return trades, nil
+}
+
+// ListStatusChanges returns the status history entries matching the filter across trades,
+// newest first.
+//
+// Example (all trades cancelled last month, with their reasons):
+//
+//	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+//	changes, err := tradeService.ListStatusChanges(ctx, repository.StatusChangeFilter{
+//	    NewStatus:   trade.TradeStatusCancelled,
+//	    ChangedFrom: firstOfMonth.AddDate(0, -1, 0),
+//	    ChangedTo:   firstOfMonth,
+//	})
+func (s *TradeService) ListStatusChanges(ctx context.Context, filter repository.StatusChangeFilter) ([]trade.StatusChange, error) {
+	changes, err := s.trades.ListStatusChanges(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trade status changes: %w", err)
+	}
+	return changes, nil
+}
+
+// CountTrades returns the number of trades matching filter, ignoring its sort and page.
+func (s *TradeService) CountTrades(ctx context.Context, filter repository.TradeFilter) (int, error) {
+	n, err := s.trades.CountTrades(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count trades: %w", err)
+	}
+
+	return n, nil
+}
+
+// RepriceTrade evaluates the pricing formula of a floating trade again with the index quotes
+// known on asOf, and stores the new prices. Only PROVISIONAL breakdowns in months that are not
+// HARD_CLOSED are repriced; FINAL prices never change. It returns the breakdowns as stored.
+//
+// Example (daily job after the index quotes of the day are loaded):
+//
+//	breakdowns, err := tradeService.RepriceTrade(ctx, "01J...", time.Now())
+func (s *TradeService) RepriceTrade(ctx context.Context, tradeID string, asOf time.Time) ([]trade.TradeBreakdown, error) {
+	t, err := s.GetTrade(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	tb := t.Base()
+	if !tb.IsFloating() {
+		return nil, fmt.Errorf("trade %s has a fixed price", tradeID)
+	}
+
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+
+	var open []int
+	for i, bd := range breakdowns {
+		if bd.IsReversal() || bd.IsCancelled() || bd.PriceStatus == trade.PriceFinal {
+			continue
+		}
+		if p := s.store.FindByID(bd.PeriodID); p != nil && p.IsHardClosed() {
+			continue
+		}
+		open = append(open, i)
+	}
+	if len(open) == 0 {
+		return breakdowns, nil
+	}
+
+	repriced := make([]trade.TradeBreakdown, len(open))
+	for j, i := range open {
+		repriced[j] = breakdowns[i]
+	}
+	if err := trade.PriceBreakdowns(*tb, repriced, s.quotes, asOf); err != nil {
+		return nil, err
+	}
+	if s.rates != nil {
+		if err := trade.ConvertToReporting(repriced, s.rates, s.reportingCurrency); err != nil {
+			return nil, err
+		}
+	}
+	for j, i := range open {
+		breakdowns[i] = repriced[j]
+	}
+
+	if err := s.breakdowns.ReplaceBreakdowns(ctx, tradeID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+		return nil, fmt.Errorf("failed to store repriced breakdowns of trade %s: %w", tradeID, err)
+	}
+	return breakdowns, nil
+}
+
+// RevalueTrade revalues the breakdowns of a trade at the FX rates of asOf, e.g. at month end
+// (see trade.RevalueReporting). It requires FX rates (SetFXRates).
+//
+// Example:
+//
+//	revals, err := tradeService.RevalueTrade(ctx, "01J...", monthEnd)
+func (s *TradeService) RevalueTrade(ctx context.Context, tradeID string, asOf time.Time) ([]trade.FXRevaluation, error) {
+	if s.rates == nil {
+		return nil, fmt.Errorf("cannot revalue trade %s: no FX rates are configured", tradeID)
+	}
+	if _, err := s.GetTrade(ctx, tradeID); err != nil {
+		return nil, err
+	}
+
+	breakdowns, err := s.breakdowns.GetBreakdowns(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", tradeID, err)
+	}
+	return trade.RevalueReporting(trade.LiveBreakdowns(breakdowns), s.rates, asOf)
+}
+
+// ListBookedTrades returns the live trades matching filter (see trade.IsLive), each with its
+// breakdowns except cancelled months, oldest first. It is the input of book-wide calculations
+// such as exposure and netting; positions and PnL use ListReportedTrades.
+//
+// Example:
+//
+//	book, err := tradeService.ListBookedTrades(ctx, repository.TradeFilter{CounterpartyID: "C42"})
+func (s *TradeService) ListBookedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error) {
+	trades, err := s.ListTrades(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	book := make([]trade.BookedTrade, 0, len(trades))
+	for _, t := range trades {
+		if !trade.IsLive(t) {
+			continue
+		}
+		breakdowns, err := s.breakdowns.GetBreakdowns(ctx, t.Base().ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", t.Base().ID, err)
+		}
+		book = append(book, trade.BookedTrade{Trade: t, Breakdowns: trade.LiveBreakdowns(breakdowns)})
+	}
+	return book, nil
+}
+
+// ListReportedTrades returns the trades matching filter that count in positions and PnL, each
+// with the breakdowns it reports (see trade.ReportedBreakdowns): the live trades of
+// ListBookedTrades, and cancelled trades that keep HARD_CLOSED months with the reversal
+// entries offsetting them.
+//
+// Example:
+//
+//	book, err := tradeService.ListReportedTrades(ctx, repository.TradeFilter{})
+//	report, err := pnlCalculator.Calculate(book, time.Now())
+func (s *TradeService) ListReportedTrades(ctx context.Context, filter repository.TradeFilter) ([]trade.BookedTrade, error) {
+	trades, err := s.ListTrades(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	book := make([]trade.BookedTrade, 0, len(trades))
+	for _, t := range trades {
+		if !trade.IsLive(t) && t.Base().Status != trade.TradeStatusCancelled {
+			continue
+		}
+		breakdowns, err := s.breakdowns.GetBreakdowns(ctx, t.Base().ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", t.Base().ID, err)
+		}
+		bt := trade.BookedTrade{Trade: t, Breakdowns: trade.LiveBreakdowns(breakdowns)}
+		bt.Breakdowns = trade.ReportedBreakdowns(bt, s.store)
+		if !trade.IsLive(t) && len(bt.Breakdowns) == 0 {
+			continue
+		}
+		book = append(book, bt)
+	}
+	return book, nil
+}
+
+// CounterpartyBreakdowns returns the breakdowns of all live purchases from and sales to a
+// counterparty; it implements api.CounterpartyPositionSource for the volume ladder.
+//
+// Example:
+//
+//	purchases, sales, err := tradeService.CounterpartyBreakdowns(ctx, "C42")
+//	ladder := trade.BuildVolumeLadder("C42", horizon, purchases, sales)
+func (s *TradeService) CounterpartyBreakdowns(ctx context.Context, counterpartyID string) (purchases, sales []trade.TradeBreakdown, err error) {
+	book, err := s.ListBookedTrades(ctx, repository.TradeFilter{CounterpartyID: counterpartyID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, bt := range book {
+		switch bt.Trade.Kind() {
+		case trade.TradeKindPurchase:
+			purchases = append(purchases, bt.Breakdowns...)
+		case trade.TradeKindSale:
+			sales = append(sales, bt.Breakdowns...)
+		}
+	}
+	return purchases, sales, nil
+}
+
+// CheckRollups compares the stored rollups of every trade with its breakdowns (see
+// trade.CheckRollupConsistency) and returns one error per discrepancy, e.g. for the nightly
+// validation sweep. A failure to load the data is returned as the single error.
+//
+// Example:
+//
+//	sweepdomain.NewValidator("trade.rollups", tradeService.CheckRollups)
+func (s *TradeService) CheckRollups(ctx context.Context) []error {
+	if s.rollups == nil {
+		return []error{errors.New("no rollup repository configured")}
+	}
+
+	trades, err := s.ListTrades(ctx, repository.TradeFilter{})
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, t := range trades {
+		id := t.Base().ID
+		breakdowns, err := s.breakdowns.GetBreakdowns(ctx, id)
+		if err != nil {
+			return []error{fmt.Errorf("failed to load breakdowns of trade %s: %w", id, err)}
+		}
+		stored, err := s.rollups.GetRollups(ctx, id)
+		if err != nil {
+			return []error{fmt.Errorf("failed to load rollups of trade %s: %w", id, err)}
+		}
+		for _, msg := range trade.CheckRollupConsistency(breakdowns, stored, s.store) {
+			errs = append(errs, errors.New(msg))
+		}
+	}
+	return errs
+}
+
+// CheckBreakdowns compares the live volume of the breakdowns of every live trade with the
+// volume its terms imply (see trade.CheckBreakdownVolume) and returns one error per
+// discrepancy, e.g. for the nightly validation sweep. A failure to load the data is returned
+// as the single error.
+//
+// Example:
+//
+//	sweepdomain.NewValidator("trade.breakdowns", tradeService.CheckBreakdowns)
+func (s *TradeService) CheckBreakdowns(ctx context.Context) []error {
+	trades, err := s.ListTrades(ctx, repository.TradeFilter{})
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, t := range trades {
+		if !trade.IsLive(t) {
+			continue
+		}
+		id := t.Base().ID
+		breakdowns, err := s.breakdowns.GetBreakdowns(ctx, id)
+		if err != nil {
+			return []error{fmt.Errorf("failed to load breakdowns of trade %s: %w", id, err)}
+		}
+		for _, msg := range trade.CheckBreakdownVolume(*t.Base(), breakdowns, s.store) {
+			errs = append(errs, errors.New(msg))
+		}
+	}
+	return errs
+}
+
+// BreakdownReferences returns the period references of the breakdowns of every trade (see
+// trade.BreakdownReferences), for the referential integrity check of the periods.
+//
+// Example:
+//
+//	refs, err := tradeService.BreakdownReferences(ctx)
+//	report, err := periodService.CheckIntegrity(refs...)
+func (s *TradeService) BreakdownReferences(ctx context.Context) ([]period.PeriodReference, error) {
+	trades, err := s.ListTrades(ctx, repository.TradeFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []period.PeriodReference
+	for _, t := range trades {
+		breakdowns, err := s.breakdowns.GetBreakdowns(ctx, t.Base().ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breakdowns of trade %s: %w", t.Base().ID, err)
+		}
+		refs = append(refs, trade.BreakdownReferences(breakdowns)...)
+	}
+	return refs, nil
+}
+
+// newTradeBase builds the TradeBase of a new trade from req, with a fresh ID.
+func (s *TradeService) newTradeBase(ctx context.Context, req TradeRequest, createdBy string) (*trade.TradeBase, error) {
+	if createdBy == "" {
+		return nil, errors.New("the booking user is required")
+	}
+
+	var tb *trade.TradeBase
+	if req.PeriodRange.IsZero() && req.DeliveryStart != nil && req.DeliveryEnd != nil {
+		var err error
+		tb, err = trade.NewCustomDatedTradeBase(*req.DeliveryStart, *req.DeliveryEnd, req.BreakdownMode, req.VolumeMT, req.PricePerMT, req.Currency, createdBy)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		tb = trade.NewTradeBase(req.PeriodRange, req.VolumeMT, req.PricePerMT, req.Currency, createdBy)
+		tb.BreakdownMode = req.BreakdownMode
+		tb.DeliveryStart = req.DeliveryStart
+		tb.DeliveryEnd = req.DeliveryEnd
+	}
+	tb.AllocationMode = req.AllocationMode
+	tb.Pricing = req.Pricing
+	tb.Costs = req.Costs
+	tb.LocationID = req.LocationID
+	tb.PaymentTerms = req.PaymentTerms
+	tb.ProductID = req.ProductID
+	tb.Commodity = req.Commodity
+	p, err := s.applyProduct(ctx, tb, "")
+	if err != nil {
+		return nil, err
+	}
+	if p != nil && req.Unit == "" && req.VolumeMT == 0 && req.Quantity > 0 {
+		req.Unit = p.DefaultUnit // A quantity without a unit is in the product's default unit
+	}
+	if req.Unit != "" && req.Unit != units.MetricTonne {
+		if req.VolumeMT != 0 {
+			return nil, fmt.Errorf("set either VolumeMT or a Quantity in %s, not both", req.Unit)
+		}
+		tb.Unit = req.Unit
+		tb.Quantity = req.Quantity
+	}
+	if err := s.convertQuantity(tb); err != nil {
+		return nil, err
+	}
+	tb.ID = utils.GenerateStableID()
+
+	return tb, nil
+}
+
+// create validates a new trade, runs the booking pipeline and persists the trade with its
+// breakdowns and rollups.
+func (s *TradeService) create(ctx context.Context, t trade.Trade, booking trade.BookingRequest) ([]trade.TradeBreakdown, *exposure.Warning, error) {
+	tb := t.Base()
+
+	if err := validateTrade(t); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkDuplicate(ctx, t, booking, ""); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkLocation(ctx, tb, ""); err != nil {
+		return nil, nil, err
+	}
+	counterparty, err := s.counterpartyName(ctx, t)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakdowns, err := s.prepare(t, booking)
+	if err != nil {
+		return nil, nil, err
+	}
+	setCounterpartyName(breakdowns, counterparty)
+	warning, err := s.checkExposure(ctx, t, breakdowns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.assignTradeNumber(ctx, t, booking); err != nil {
+			return err
+		}
+		if err := s.trades.SaveTrade(ctx, t); err != nil {
+			return fmt.Errorf("failed to save trade %s: %w", tb.ID, err)
+		}
+		if err := s.breakdowns.SaveBreakdowns(ctx, tb.ID, breakdowns, trade.ComputeRollups(breakdowns, s.store)); err != nil {
+			return fmt.Errorf("failed to save breakdowns of trade %s: %w", tb.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return breakdowns, warning, nil
+}
+
+// prepare runs the checks shared by every write of a trade and returns its new breakdowns:
+// the periods must exist, no delivery month may be closed, and the booking pipeline must pass.
+// The breakdowns of a storage agreement are its monthly fee accruals.
+func (s *TradeService) prepare(t trade.Trade, booking trade.BookingRequest) ([]trade.TradeBreakdown, error) {
+	tb := t.Base()
+	if s.store == nil {
+		return nil, errors.New("period store not initialised")
+	}
+
+	if !tb.HasCustomDates() {
+		for _, id := range []string{tb.PeriodRange.StartPeriodID, tb.PeriodRange.EndPeriodID} {
+			if s.store.FindByID(id) == nil {
+				return nil, &dberr.NotFoundError{Entity: "period", ID: id}
+			}
+		}
+	}
+
+	breakdowns := trade.CreateTradeBreakdowns(*tb, s.store, booking.User)
+	if len(breakdowns) == 0 {
+		return nil, fmt.Errorf("trade %s does not deliver in any known month", tb.ID)
+	}
+	if err := s.checkOpen(tb.ID, breakdowns); err != nil {
+		return nil, err
+	}
+	if err := trade.ValidateLaycans(tb, breakdowns); err != nil {
+		return nil, err
+	}
+	if err := trade.PriceBreakdowns(*tb, breakdowns, s.quotes, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	trade.AllocateCosts(tb, breakdowns)
+	if sa, ok := t.(*trade.StorageAgreement); ok {
+		sa.AccrueFees(breakdowns)
+	}
+	if s.rates != nil {
+		if err := trade.ConvertToReporting(breakdowns, s.rates, s.reportingCurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.booking.Validate(tb, booking); err != nil {
+		return nil, err
+	}
+
+	return breakdowns, nil
+}
+
+// checkOpen fails with a *dberr.PeriodClosedError when one of the breakdowns lies in a
+// HARD_CLOSED month. SOFT_CLOSED months are accepted: month-end adjustments are still allowed
+// there. Reversal entries are ignored; they are posted in open months by construction.
+func (s *TradeService) checkOpen(tradeID string, breakdowns []trade.TradeBreakdown) error {
+	for _, bd := range breakdowns {
+		if bd.IsReversal() {
+			continue
+		}
+		if p := s.store.FindByID(bd.PeriodID); p != nil && p.IsHardClosed() {
+			return &dberr.PeriodClosedError{PeriodID: p.ID, Status: string(p.EffectiveStatus()), Op: "take deliveries of trade " + tradeID}
+		}
+	}
+	return nil
+}
+
+// checkDuplicate sets the business key of a trade (see trade.BusinessKey) and rejects the
+// trade with a *dberr.DuplicateError when another live trade has the same terms. A booking
+// with AllowDuplicate and an OverrideReason goes ahead; the duplicate is then flagged on the
+// trade as a PolicyOverride. The check is skipped when the key equals previous, i.e. when an
+// amendment leaves the terms unchanged; trades in ignore (e.g. the version being replaced)
+// never count as duplicates.
+func (s *TradeService) checkDuplicate(ctx context.Context, t trade.Trade, booking trade.BookingRequest, previous string, ignore ...string) error {
+	tb := t.Base()
+	tb.BusinessKey = trade.BusinessKey(t)
+	if tb.BusinessKey == previous {
+		return nil
+	}
+
+	existing, err := s.trades.ListTrades(ctx, repository.TradeFilter{BusinessKey: tb.BusinessKey})
+	if err != nil {
+		return fmt.Errorf("failed to look up duplicates of trade %s: %w", tb.ID, err)
+	}
+	var duplicates []string
+	for _, e := range existing {
+		id := e.Base().ID
+		if id == tb.ID || slices.Contains(ignore, id) || !trade.IsLive(e) {
+			continue
+		}
+		duplicates = append(duplicates, id)
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	if !booking.AllowDuplicate {
+		return &dberr.DuplicateError{Entity: "trade", ID: duplicates[0], Key: "same terms as trade " + tb.ID}
+	}
+	if booking.OverrideReason == "" {
+		return fmt.Errorf("trade %s has the same terms as trade %s: an override reason is required to book it anyway", tb.ID, duplicates[0])
+	}
+
+	at := bookingTime(booking)
+	tb.PolicyOverrides = append(tb.PolicyOverrides, trade.PolicyOverride{
+		Policy:     duplicateBookingPolicy,
+		User:       booking.User,
+		Reason:     booking.OverrideReason,
+		At:         at.UTC(),
+		LocalTime:  at.UTC().Format("2006-01-02 15:04 MST"),
+		Violations: "same terms as trade " + strings.Join(duplicates, ", "),
+	})
+	return nil
+}
+
+// assignTradeNumber reserves the next trade number of the trade's kind in its booking year.
+func (s *TradeService) assignTradeNumber(ctx context.Context, t trade.Trade, booking trade.BookingRequest) error {
+	if s.numbers == nil {
+		return nil
+	}
+
+	year := bookingTime(booking).UTC().Year()
+	seq, err := s.numbers.NextNumber(ctx, t.Kind(), year)
+	if err != nil {
+		return fmt.Errorf("failed to number trade %s: %w", t.Base().ID, err)
+	}
+	number, err := trade.FormatTradeNumber(t.Kind(), year, seq)
+	if err != nil {
+		return err
+	}
+	t.Base().TradeNumber = number
+	return nil
+}
+
+// bookingTime returns the moment of a booking request, defaulting to now.
+func bookingTime(booking trade.BookingRequest) time.Time {
+	if booking.At.IsZero() {
+		return time.Now()
+	}
+	return booking.At
+}
+
+// counterpartyName resolves the counterparty of a trade in the company master data and returns
+// its reporting name. It fails with a *dberr.NotFoundError for an unknown company and rejects a
+// company that was merged into another. Without a CompanyDirectory it returns "".
+func (s *TradeService) counterpartyName(ctx context.Context, t trade.Trade) (string, error) {
+	if s.companies == nil {
+		return "", nil
+	}
+
+	id := t.CounterpartyID()
+	c, err := s.companies.FindByID(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to load counterparty %s of trade %s: %w", id, t.Base().ID, err)
+	}
+	if c == nil {
+		return "", &dberr.NotFoundError{Entity: "company", ID: id, Reason: "counterparty of trade " + t.Base().ID}
+	}
+	if c.IsTombstoned() {
+		return "", fmt.Errorf("counterparty %s of trade %s was merged into %s; book against that company", id, t.Base().ID, *c.MergedIntoID)
+	}
+	return c.ReportingName(), nil
+}
+
+func setCounterpartyName(breakdowns []trade.TradeBreakdown, name string) {
+	for i := range breakdowns {
+		breakdowns[i].CounterpartyName = name
+	}
+}
+
+// checkLocation validates the delivery location of a trade when it has one and a
+// LocationChecker is set. An amendment that keeps the previous location is not checked again,
+// so trades at a since deactivated location can still be amended.
+func (s *TradeService) checkLocation(ctx context.Context, tb *trade.TradeBase, previous string) error {
+	if s.locations == nil || tb.LocationID == "" || tb.LocationID == previous {
+		return nil
+	}
+	if err := s.locations.CheckLocation(ctx, tb.LocationID); err != nil {
+		return fmt.Errorf("trade %s: delivery location: %w", tb.ID, err)
+	}
+	return nil
+}
+
+// applyProduct resolves the product of a trade when it has one, a ProductCatalog is set and
+// the product differs from previous (an amendment keeping its product is not checked again).
+// The trade takes the product's commodity; a trade booked with another commodity is rejected.
+// It returns nil when nothing was resolved.
+func (s *TradeService) applyProduct(ctx context.Context, tb *trade.TradeBase, previous string) (*product.Product, error) {
+	if s.products == nil || tb.ProductID == "" || tb.ProductID == previous {
+		return nil, nil
+	}
+
+	p, err := s.products.ActiveProduct(ctx, tb.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("trade %s: product: %w", tb.ID, err)
+	}
+	switch {
+	case tb.Commodity == "":
+		tb.Commodity = p.Commodity
+	case !strings.EqualFold(tb.Commodity, p.Commodity):
+		return nil, fmt.Errorf("trade %s: commodity %s does not match product %s (%s)", tb.ID, tb.Commodity, p.Code, p.Commodity)
+	}
+	return p, nil
+}
+
+// convertQuantity derives VolumeMT from the booked quantity of a trade that is not booked in
+// tonnes. For those trades the booked quantity is authoritative, also after an amendment.
+func (s *TradeService) convertQuantity(tb *trade.TradeBase) error {
+	if tb.BookedUnit() == units.MetricTonne {
+		tb.Unit, tb.Quantity = "", 0
+		return nil
+	}
+
+	mt, err := s.units.ToMT(tb.Quantity, tb.Unit, tb.Commodity)
+	if err != nil {
+		return fmt.Errorf("trade %s: cannot convert %v %s to MT: %w", tb.ID, tb.Quantity, tb.Unit, err)
+	}
+	tb.VolumeMT = mt
+	return nil
+}
+
+// validateTrade checks the terms every trade must have.
+func validateTrade(t trade.Trade) error {
+	tb := t.Base()
+
+	var errs []error
+	if t.CounterpartyID() == "" {
+		errs = append(errs, errors.New("counterparty is required"))
+	}
+	if tb.PeriodRange.IsZero() && !tb.HasCustomDates() {
+		errs = append(errs, errors.New("a period range or delivery start and end are required"))
+	}
+	if tb.VolumeMT <= 0 {
+		errs = append(errs, fmt.Errorf("volume must be positive, got %v MT", tb.VolumeMT))
+	}
+	if tb.PricePerMT < 0 {
+		errs = append(errs, fmt.Errorf("price must not be negative, got %v", tb.PricePerMT))
+	}
+	switch {
+	case tb.AvailabilityFeePerMT < 0:
+		errs = append(errs, fmt.Errorf("availability fee must not be negative, got %v", tb.AvailabilityFeePerMT))
+	case tb.AvailabilityFeePerMT > 0 && t.Kind() != trade.TradeKindPurchase:
+		errs = append(errs, fmt.Errorf("only purchases carry an availability fee, not a %s", t.Kind()))
+	}
+	if tb.Currency == "" {
+		errs = append(errs, errors.New("currency is required"))
+	}
+	switch tb.BreakdownMode {
+	case "", trade.BreakdownFullMonths, trade.BreakdownProRata:
+	default:
+		errs = append(errs, fmt.Errorf("invalid breakdown mode %q", tb.BreakdownMode))
+	}
+	if !tb.AllocationMode.IsValid() {
+		errs = append(errs, fmt.Errorf("invalid allocation mode %q", tb.AllocationMode))
+	}
+	if tb.Pricing != nil {
+		if err := tb.Pricing.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := range tb.Costs {
+		if err := tb.Costs[i].Validate(tb.Currency); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if tb.TakeOrPay != nil {
+		switch t.Kind() {
+		case trade.TradeKindPurchase, trade.TradeKindSale:
+			if err := tb.TakeOrPay.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			errs = append(errs, fmt.Errorf("a %s has no take-or-pay obligation", t.Kind()))
+		}
+	}
+	if tb.Tolerance != nil {
+		switch t.Kind() {
+		case trade.TradeKindPurchase, trade.TradeKindSale:
+			if err := tb.Tolerance.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			errs = append(errs, fmt.Errorf("a %s has no volume tolerance", t.Kind()))
+		}
+	}
+	if tb.PaymentTerms != nil {
+		if err := tb.PaymentTerms.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch v := t.(type) {
+	case *trade.Swap:
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	case *trade.StorageAgreement:
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("trade %s is invalid: %w", tb.ID, errors.Join(errs...))
+	}
+	return nil
+}
+
+// withinTx runs fn in one transaction when the service has a txn.Manager, and directly otherwise.
+func (s *TradeService) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nholding/cso-book/internal/exposure"
+	period "github.com/nholding/cso-book/internal/period/domain"
+	"github.com/nholding/cso-book/internal/platform/dberr"
+	"github.com/nholding/cso-book/internal/position"
+	"github.com/nholding/cso-book/internal/trade"
+	"github.com/nholding/cso-book/internal/trade/repository"
+)
+
+// fakeTrades is an in-memory TradeRepository holding the trades by ID.
+type fakeTrades struct {
+	trades  map[string]trade.Trade
+	updates int
+}
+
+func (f *fakeTrades) SaveTrade(_ context.Context, t trade.Trade) error {
+	f.trades[t.Base().ID] = t
+	return nil
+}
+
+func (f *fakeTrades) UpdateTrade(_ context.Context, t trade.Trade) error {
+	f.updates++
+	f.trades[t.Base().ID] = t
+	return nil
+}
+
+func (f *fakeTrades) GetTradeByID(_ context.Context, id string) (trade.Trade, error) {
+	return f.trades[id], nil
+}
+
+func (f *fakeTrades) ListTrades(_ context.Context, filter repository.TradeFilter) ([]trade.Trade, error) {
+	var out []trade.Trade
+	for _, t := range f.trades {
+		if filter.CounterpartyID != "" && t.CounterpartyID() != filter.CounterpartyID {
+			continue
+		}
+		if filter.BusinessKey != "" && t.Base().BusinessKey != filter.BusinessKey {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func (f *fakeTrades) CountTrades(_ context.Context, _ repository.TradeFilter) (int, error) {
+	return len(f.trades), nil
+}
+
+func (f *fakeTrades) ListStatusChanges(_ context.Context, _ repository.StatusChangeFilter) ([]trade.StatusChange, error) {
+	return nil, nil
+}
+
+// fakeBreakdowns is an in-memory BreakdownRepository; err makes every write fail.
+type fakeBreakdowns struct {
+	breakdowns map[string][]trade.TradeBreakdown
+	rollups    map[string][]trade.TradeRollup
+	err        error
+}
+
+func (f *fakeBreakdowns) SaveBreakdowns(_ context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.breakdowns[tradeID] = breakdowns
+	f.rollups[tradeID] = rollups
+	return nil
+}
+
+func (f *fakeBreakdowns) ReplaceBreakdowns(ctx context.Context, tradeID string, breakdowns []trade.TradeBreakdown, rollups []trade.TradeRollup) error {
+	return f.SaveBreakdowns(ctx, tradeID, breakdowns, rollups)
+}
+
+func (f *fakeBreakdowns) GetBreakdowns(_ context.Context, tradeID string) ([]trade.TradeBreakdown, error) {
+	return append([]trade.TradeBreakdown(nil), f.breakdowns[tradeID]...), nil
+}
+
+func (f *fakeBreakdowns) GetBreakdownsByPeriod(_ context.Context, periodID string) ([]trade.TradeBreakdown, error) {
+	var out []trade.TradeBreakdown
+	for _, bds := range f.breakdowns {
+		for _, bd := range bds {
+			if bd.PeriodID == periodID {
+				out = append(out, bd)
+			}
+		}
+	}
+	return out, nil
+}
+
+// newCancelFixture books a Q1 2026 purchase of 1000 MT per month with JAN hard-closed.
+func newCancelFixture(t *testing.T) (*TradeService, *fakeTrades, *fakeBreakdowns, *period.PeriodStore) {
+	t.Helper()
+
+	store := period.NewMockPeriodStore(2026, 2026)
+	if err := store.Update("2026-JAN", func(p *period.Period) { p.Status = period.PeriodHardClosed }); err != nil {
+		t.Fatalf("closing 2026-JAN: %v", err)
+	}
+
+	purchase, breakdowns := trade.NewPurchase(store, "C42", period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"},
+		1000, 600, "EUR", "trader@internal.local")
+	trades := &fakeTrades{trades: map[string]trade.Trade{purchase.ID: &purchase}}
+	bds := &fakeBreakdowns{
+		breakdowns: map[string][]trade.TradeBreakdown{purchase.ID: breakdowns},
+		rollups:    map[string][]trade.TradeRollup{},
+	}
+
+	return NewTradeService(trades, bds, store, nil, trade.BookingPipeline{}), trades, bds, store
+}
+
+func TestCancelTradePersistsStatusAndReversals(t *testing.T) {
+	s, trades, bds, store := newCancelFixture(t)
+	ctx := context.Background()
+	booking := trade.BookingRequest{User: "ops@internal.local", At: time.Date(2026, time.February, 10, 9, 0, 0, 0, time.UTC)}
+
+	stored, err := s.CancelTrade(ctx, "test", "counterparty default", booking)
+	if err != nil {
+		t.Fatalf("CancelTrade: %v", err)
+	}
+
+	tb := trades.trades["test"].Base()
+	if tb.Status != trade.TradeStatusCancelled {
+		t.Errorf("status %s, want CANCELLED", tb.Status)
+	}
+	last := tb.StatusAudit[len(tb.StatusAudit)-1]
+	if last.NewStatus != trade.TradeStatusCancelled || last.Reason != "counterparty default" || last.ChangedBy != booking.User {
+		t.Errorf("last status history entry %+v", last)
+	}
+
+	// JAN stays and is reversed in FEB, the first open month; FEB and MAR are removed
+	got := bds.breakdowns["test"]
+	if len(got) != 2 || len(stored) != 2 {
+		t.Fatalf("stored %d breakdowns (returned %d), want 2: %+v", len(got), len(stored), got)
+	}
+	if got[0].PeriodID != "2026-JAN" || got[0].IsReversal() || got[0].VolumeMT != 1000 {
+		t.Errorf("first breakdown %s %v MT (reversal %v), want the JAN delivery", got[0].PeriodID, got[0].VolumeMT, got[0].IsReversal())
+	}
+	if !got[1].IsReversal() || got[1].PeriodID != "2026-FEB" || *got[1].ReversedPeriodID != "2026-JAN" || got[1].VolumeMT != -1000 {
+		t.Errorf("second breakdown %s %v MT, want the FEB reversal of JAN", got[1].PeriodID, got[1].VolumeMT)
+	}
+
+	// The quarter rollup nets to zero
+	for _, r := range bds.rollups["test"] {
+		if r.PeriodID == "2026-Q1" && r.VolumeMT != 0 {
+			t.Errorf("2026-Q1 rollup %v MT, want 0", r.VolumeMT)
+		}
+	}
+
+	// The reversal offsets JAN in the position
+	book, err := s.ListReportedTrades(ctx, repository.TradeFilter{})
+	if err != nil {
+		t.Fatalf("ListReportedTrades: %v", err)
+	}
+	if len(book) != 1 {
+		t.Fatalf("cancelled trade with a closed month is not reported: %+v", book)
+	}
+	pos, err := position.Compute(store, book).Position("2026-JAN")
+	if err != nil {
+		t.Fatalf("Position: %v", err)
+	}
+	if pos.NetMT != 0 {
+		t.Errorf("2026-JAN net position %v MT, want 0", pos.NetMT)
+	}
+
+	if _, err := s.CancelTrade(ctx, "test", "again", booking); err == nil {
+		t.Error("cancelling a cancelled trade succeeded")
+	}
+}
+
+func TestCancelTradeRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  string
+		booking trade.BookingRequest
+	}{
+		{name: "no reason", booking: trade.BookingRequest{User: "ops@internal.local"}},
+		{name: "no user", reason: "counterparty default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, trades, _, _ := newCancelFixture(t)
+
+			if _, err := s.CancelTrade(context.Background(), "test", tt.reason, tt.booking); err == nil {
+				t.Fatal("CancelTrade succeeded")
+			}
+			if trades.updates != 0 {
+				t.Errorf("trade was written %d times", trades.updates)
+			}
+			if status := trades.trades["test"].Base().Status; status == trade.TradeStatusCancelled {
+				t.Errorf("status changed to %s", status)
+			}
+		})
+	}
+}
+
+func TestCancelTradeReportsWriteFailure(t *testing.T) {
+	s, _, bds, _ := newCancelFixture(t)
+	bds.err = errors.New("connection reset")
+
+	_, err := s.CancelTrade(context.Background(), "test", "counterparty default", trade.BookingRequest{User: "ops@internal.local"})
+	if !errors.Is(err, bds.err) {
+		t.Fatalf("got %v, want the breakdown write error", err)
+	}
+}
+
+func TestCounterpartyBreakdownsSplitsPurchasesAndSales(t *testing.T) {
+	s, trades, bds, store := newCancelFixture(t)
+
+	// A sale to the same counterparty, and a purchase from another one
+	book := func(id string, t trade.Trade) {
+		tb := t.Base()
+		tb.ID = id
+		trades.trades[id] = t
+		bds.breakdowns[id] = trade.CreateTradeBreakdowns(*tb, store, "trader@internal.local")
+	}
+	base := trades.trades["test"].Base()
+	book("sale", &trade.Ticket{TradeBase: *base, BuyerID: "C42"})
+	book("other", &trade.Purchase{TradeBase: *base, SupplierID: "C7"})
+
+	purchases, sales, err := s.CounterpartyBreakdowns(context.Background(), "C42")
+	if err != nil {
+		t.Fatalf("CounterpartyBreakdowns: %v", err)
+	}
+	if len(purchases) != 3 || len(sales) != 3 {
+		t.Fatalf("got %d purchase and %d sale breakdowns, want 3 and 3", len(purchases), len(sales))
+	}
+	for _, bd := range purchases {
+		if bd.ParentTradeID != "test" {
+			t.Errorf("purchase breakdown of trade %s", bd.ParentTradeID)
+		}
+	}
+	for _, bd := range sales {
+		if bd.ParentTradeID != "sale" {
+			t.Errorf("sale breakdown of trade %s", bd.ParentTradeID)
+		}
+	}
+}
+
+// fakeCredit is a CreditChecker returning warning for every trade it checks.
+type fakeCredit struct {
+	warning *exposure.Warning
+	checked []string
+}
+
+func (f *fakeCredit) CheckTrade(_ context.Context, t trade.Trade, _ []trade.TradeBreakdown) (*exposure.Warning, error) {
+	f.checked = append(f.checked, t.Base().ID)
+	return f.warning, nil
+}
+
+func TestCreateAndAmendReportCreditWarning(t *testing.T) {
+	s, trades, bds, _ := newCancelFixture(t)
+	credit := &fakeCredit{warning: &exposure.Warning{Level: exposure.LevelBreach, Message: "C42 exposure would exceed its limit"}}
+	s.SetCreditCheck(credit, CreditPolicyBlock, "")
+	ctx := context.Background()
+	booking := trade.BookingRequest{User: "trader@internal.local"}
+
+	sale, breakdowns, warning, err := s.CreateSale(ctx, TradeRequest{
+		CounterpartyID: "C42",
+		PeriodRange:    period.PeriodRange{StartPeriodID: "2026-Q2", EndPeriodID: "2026-Q2"},
+		VolumeMT:       500,
+		PricePerMT:     610,
+		Currency:       "EUR",
+	}, booking)
+	if err != nil {
+		t.Fatalf("CreateSale: %v", err)
+	}
+	if warning != credit.warning {
+		t.Errorf("CreateSale warning %+v, want the credit check's", warning)
+	}
+	// A warning never blocks the booking
+	if trades.trades[sale.ID] == nil || len(bds.breakdowns[sale.ID]) != len(breakdowns) {
+		t.Errorf("sale %s was not booked", sale.ID)
+	}
+
+	// A CONFIRMED trade is amended by a new version, which is checked instead of the original
+	sale.Status = trade.TradeStatusConfirmed
+	next, warning, err := s.AmendTrade(ctx, sale.ID, "volume correction", func(tb *trade.TradeBase) {
+		tb.VolumeMT = 1200
+	}, booking)
+	if err != nil {
+		t.Fatalf("AmendTrade: %v", err)
+	}
+	if warning != credit.warning {
+		t.Errorf("AmendTrade warning %+v, want the credit check's", warning)
+	}
+	if want := []string{sale.ID, next.Base().ID}; len(credit.checked) != 2 || credit.checked[0] != want[0] || credit.checked[1] != want[1] {
+		t.Errorf("checked trades %v, want %v", credit.checked, want)
+	}
+	// The superseded original keeps its breakdowns for audit
+	if len(bds.breakdowns[sale.ID]) != len(breakdowns) || len(bds.breakdowns[next.Base().ID]) == 0 {
+		t.Errorf("original has %d breakdowns (want %d), new version %d", len(bds.breakdowns[sale.ID]), len(breakdowns), len(bds.breakdowns[next.Base().ID]))
+	}
+}
+
+func TestCheckBreakdownsReconcilesVolumes(t *testing.T) {
+	s, trades, bds, store := newCancelFixture(t)
+	ctx := context.Background()
+
+	// A total-mode trade of 3000 MT over Q1 with FEB cancelled and MAR declared at 900 MT
+	total := *trades.trades["test"].Base()
+	total.ID = "total"
+	total.VolumeMT = 3000
+	total.AllocationMode = trade.AllocationTotalSpreadEvenly
+	total.CancelledPeriodIDs = []string{"2026-FEB"}
+	total.DeclaredVolumes = map[string]float64{"2026-MAR": 900}
+	trades.trades["total"] = &trade.Purchase{TradeBase: total, SupplierID: "C42"}
+	bds.breakdowns["total"] = trade.CreateTradeBreakdowns(total, store, "trader@internal.local")
+
+	if errs := s.CheckBreakdowns(ctx); len(errs) != 0 {
+		t.Fatalf("CheckBreakdowns on consistent trades: %v", errs)
+	}
+
+	// A month of the per-month trade goes missing
+	bds.breakdowns["test"] = bds.breakdowns["test"][1:]
+	errs := s.CheckBreakdowns(ctx)
+	if len(errs) != 1 {
+		t.Fatalf("CheckBreakdowns returned %v, want one discrepancy", errs)
+	}
+
+	// Cancelled trades are not checked
+	trades.trades["test"].Base().Status = trade.TradeStatusCancelled
+	if errs := s.CheckBreakdowns(ctx); len(errs) != 0 {
+		t.Errorf("CheckBreakdowns checked a cancelled trade: %v", errs)
+	}
+}
+
+func TestSetLaycanRejectsHardClosedMonth(t *testing.T) {
+	s, trades, _, _ := newCancelFixture(t)
+	booking := trade.BookingRequest{User: "ops@internal.local"}
+	laycan := &trade.Laycan{Start: time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2026, time.January, 12, 0, 0, 0, 0, time.UTC)}
+
+	if _, err := s.SetLaycan(context.Background(), "test", "2026-JAN", laycan, booking); !errors.Is(err, dberr.ErrPeriodClosed) {
+		t.Fatalf("SetLaycan in the HARD_CLOSED month: %v, want a closed period error", err)
+	}
+	if trades.updates != 0 {
+		t.Errorf("trade was written %d times", trades.updates)
+	}
+}
@@ -0,0 +1,162 @@
+package trade
+
+import (
+	"fmt"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+// SplitPoint tells Split where to divide a trade. Exactly one of the fields is set.
+type SplitPoint struct {
+	// PeriodID is the first month of the second child, e.g. "2026-APR": the first child
+	// delivers the months before it, the second child the month itself and all after it.
+	PeriodID string `json:"periodId,omitempty"`
+
+	// VolumeMT is the volume of the first child; the second child takes the rest. It has the
+	// meaning of TradeBase.VolumeMT, i.e. per month for PER_MONTH_FIXED trades.
+	VolumeMT float64 `json:"volumeMT,omitempty"`
+}
+
+// Split
+//
+// PURPOSE:
+//
+//	Divides a live trade into two child trades, e.g. when a term deal is novated in part
+//	to another book or a cargo is split over two vessels. The trade is split either in time
+//	(SplitPoint.PeriodID) or in volume (SplitPoint.VolumeMT); all other terms are copied:
+//
+//	  T1 (→ SUPERSEDED)  2026-Q1..2026-Q2, 10,000 MT/month
+//	  T2                 2026-Q1..2026-FEB   SplitFromID = T1, PreviousVersionID = T1
+//	  T3                 2026-MAR..2026-Q2   SplitFromID = T1, PreviousVersionID = T1
+//
+//	Total volumes (TOTAL_SPREAD_EVENLY, DAY_WEIGHTED) and lump-sum costs are divided by
+//	the share of each child in the volume of breakdowns, the current breakdowns of the
+//	trade. The children start in PENDING-CONFIRMATION, since their terms must be confirmed
+//	(again). The trade becomes SUPERSEDED; a DRAFT is amended in place instead.
+//
+// EXAMPLE USAGE:
+//
+//	first, second, err := t.Base().Split(ps, SplitPoint{PeriodID: "2026-MAR"}, breakdowns,
+//	    utils.GenerateStableID(), utils.GenerateStableID(), "novation of Mar-Jun to C77", "user@internal.local")
+func (tb *TradeBase) Split(ps *period.PeriodStore, at SplitPoint, breakdowns []TradeBreakdown, firstID, secondID, reason, splitBy string) (*TradeBase, *TradeBase, error) {
+	switch tb.Status {
+	case TradeStatusPending, TradeStatusConfirmed:
+	case TradeStatusDraft:
+		return nil, nil, fmt.Errorf("trade %s is DRAFT; amend it instead of splitting it", tb.ID)
+	default:
+		return nil, nil, fmt.Errorf("trade %s is %s and cannot be split", tb.ID, tb.Status)
+	}
+	if tb.IsDeleted() {
+		return nil, nil, fmt.Errorf("trade %s is deleted", tb.ID)
+	}
+	if reason == "" {
+		return nil, nil, fmt.Errorf("a reason is required to split trade %s", tb.ID)
+	}
+
+	note := fmt.Sprintf("split from %s: %s", tb.ID, reason)
+	first := tb.successor(firstID, TradeStatusPending, note, splitBy)
+	second := tb.successor(secondID, TradeStatusPending, note, splitBy)
+	first.SplitFromID = tb.ID
+	second.SplitFromID = tb.ID
+
+	var (
+		share float64 // Share of the first child in the volume of the trade
+		err   error
+	)
+	switch {
+	case at.PeriodID != "" && at.VolumeMT != 0:
+		return nil, nil, fmt.Errorf("trade %s: split either by period or by volume, not both", tb.ID)
+	case at.PeriodID != "":
+		share, err = tb.splitByPeriod(ps, at.PeriodID, breakdowns, first, second)
+		if err != nil {
+			return nil, nil, err
+		}
+	case at.VolumeMT > 0 && at.VolumeMT < tb.VolumeMT:
+		share = at.VolumeMT / tb.VolumeMT
+		first.VolumeMT = at.VolumeMT
+		second.VolumeMT = tb.VolumeMT - at.VolumeMT
+		first.Quantity = tb.Quantity * share
+		second.Quantity = tb.Quantity - first.Quantity
+		for id, mt := range tb.DeclaredVolumes {
+			first.DeclaredVolumes[id] = mt * share
+			second.DeclaredVolumes[id] = mt - first.DeclaredVolumes[id]
+		}
+	default:
+		return nil, nil, fmt.Errorf("split volume %v of trade %s must be above 0 and below its volume of %v MT", at.VolumeMT, tb.ID, tb.VolumeMT)
+	}
+
+	for i := range tb.Costs {
+		if tb.Costs[i].Basis == CostLumpSum {
+			first.Costs[i].Amount = tb.Costs[i].Amount * share
+			second.Costs[i].Amount = tb.Costs[i].Amount - first.Costs[i].Amount
+		}
+	}
+
+	if err := tb.UpdateTradeStatus(TradeStatusSuperseded, fmt.Sprintf("split into %s and %s: %s", firstID, secondID, reason), splitBy); err != nil {
+		return nil, nil, err
+	}
+
+	return first, second, nil
+}
+
+// splitByPeriod divides the delivery of tb at the start of month periodID into first and
+// second, and returns the share of first in the volume of breakdowns.
+func (tb *TradeBase) splitByPeriod(ps *period.PeriodStore, periodID string, breakdowns []TradeBreakdown, first, second *TradeBase) (float64, error) {
+	month := ps.FindByID(periodID)
+	if month == nil {
+		return 0, fmt.Errorf("unknown split period %s", periodID)
+	}
+	if month.Granularity != period.MonthlyPeriod {
+		return 0, fmt.Errorf("trade %s can only be split at a month, not at %s", tb.ID, periodID)
+	}
+
+	if !tb.HasCustomDates() {
+		months := ps.BreakDownTradePeriodRange(tb.PeriodRange)
+		at := -1
+		for i, id := range months {
+			if id == periodID {
+				at = i
+			}
+		}
+		if at <= 0 {
+			return 0, fmt.Errorf("split period %s is not a month after the first of trade %s (%s..%s)",
+				periodID, tb.ID, tb.PeriodRange.StartPeriodID, tb.PeriodRange.EndPeriodID)
+		}
+		first.PeriodRange.EndPeriodID = months[at-1]
+		second.PeriodRange.StartPeriodID = periodID
+	}
+
+	if tb.DeliveryStart != nil && tb.DeliveryEnd != nil {
+		if !month.StartDate.After(*tb.DeliveryStart) || month.StartDate.After(*tb.DeliveryEnd) {
+			return 0, fmt.Errorf("split period %s does not fall inside the delivery window of trade %s (%s..%s)",
+				periodID, tb.ID, tb.DeliveryStart.Format("2006-01-02"), tb.DeliveryEnd.Format("2006-01-02"))
+		}
+		end := month.StartDate.AddDate(0, 0, -1)
+		start := month.StartDate
+		first.DeliveryEnd = &end
+		second.DeliveryStart = &start
+	}
+
+	var before, total float64
+	for _, bd := range breakdowns {
+		if bd.IsReversal() {
+			continue
+		}
+		total += bd.VolumeMT
+		if bd.StartDate.Before(month.StartDate) {
+			before += bd.VolumeMT
+		}
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("trade %s has no delivered volume to split", tb.ID)
+	}
+	share := before / total
+
+	if tb.Allocation() != AllocationPerMonthFixed {
+		first.VolumeMT = tb.VolumeMT * share
+		second.VolumeMT = tb.VolumeMT - first.VolumeMT
+		first.Quantity = tb.Quantity * share
+		second.Quantity = tb.Quantity - first.Quantity
+	}
+	return share, nil
+}
@@ -0,0 +1,93 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StorageService tells storage and throughput agreements apart.
+type StorageService string
+
+const (
+	StorageServiceStorage    StorageService = "STORAGE"    // Tank capacity rented for the period
+	StorageServiceThroughput StorageService = "THROUGHPUT" // Right to move volume through a terminal or pipeline
+)
+
+// StorageTerms are the terms of a storage or throughput agreement next to the shared
+// TradeBase. They are stored as JSON in trades.storage_terms.
+type StorageTerms struct {
+	Service          StorageService `json:"service"`
+	FeePerPeriod     float64        `json:"feePerPeriod"`     // Fixed fee per delivery month, in the trade currency
+	InjectionRateMT  float64        `json:"injectionRateMT"`  // Injection right in MT per day; 0 means not limited
+	WithdrawalRateMT float64        `json:"withdrawalRateMT"` // Withdrawal right in MT per day; 0 means not limited
+}
+
+// StorageAgreement is a storage or throughput contract with a terminal operator. The book
+// does not buy or sell product with it but carries its fixed fee: the breakdowns of an
+// agreement are monthly fee accruals (see AccrueFees) without volume, so the agreement adds to
+// payables and PnL but not to positions. TradeBase.VolumeMT is the contracted capacity.
+//
+// Example (20,000 MT of tank capacity in Rotterdam for 2026 at 85,000 EUR/month):
+//
+//	tb := NewTradeBase(period.PeriodRange{StartPeriodID: "2026", EndPeriodID: "2026"}, 20000, 0, "EUR", "user@internal.local")
+//	tb.LocationID = "NLRTM"
+//	sa := &StorageAgreement{TradeBase: *tb, ProviderID: "C17",
+//	    Terms: StorageTerms{Service: StorageServiceStorage, FeePerPeriod: 85000, InjectionRateMT: 2500, WithdrawalRateMT: 2500}}
+type StorageAgreement struct {
+	TradeBase
+	ProviderID string // ID of the terminal operator (company domain)
+	Terms      StorageTerms
+}
+
+func (s *StorageAgreement) Base() *TradeBase       { return &s.TradeBase }
+func (s *StorageAgreement) Kind() TradeKind        { return TradeKindStorage }
+func (s *StorageAgreement) CounterpartyID() string { return s.ProviderID }
+
+// Validate checks the storage-specific terms. The fee is fixed, so no pricing formula is allowed.
+func (s *StorageAgreement) Validate() error {
+	var errs []error
+	switch s.Terms.Service {
+	case StorageServiceStorage, StorageServiceThroughput:
+	default:
+		errs = append(errs, fmt.Errorf("invalid storage service %q", s.Terms.Service))
+	}
+	if s.Terms.FeePerPeriod < 0 {
+		errs = append(errs, fmt.Errorf("storage fee must not be negative, got %v", s.Terms.FeePerPeriod))
+	}
+	if s.Terms.InjectionRateMT < 0 || s.Terms.WithdrawalRateMT < 0 {
+		errs = append(errs, errors.New("injection and withdrawal rates must not be negative"))
+	}
+	if s.Pricing != nil {
+		errs = append(errs, errors.New("storage agreements have a fixed fee and no pricing formula"))
+	}
+	return errors.Join(errs...)
+}
+
+// AccrueFees
+//
+// PURPOSE:
+//
+//	Turns the monthly breakdowns built from the agreement's period range (see
+//	CreateTradeBreakdowns) into fee accruals: each month carries FeePerPeriod, pro-rated by
+//	MonthFraction for PRO_RATA agreements, as its TotalAmount. Volume and price are cleared, so
+//	the capacity does not count as purchased product. Costs allocated before (AllocateCosts)
+//	are kept.
+//
+// EXAMPLE USAGE:
+//
+//	breakdowns := CreateTradeBreakdowns(sa.TradeBase, ps, "user@internal.local")
+//	sa.AccrueFees(breakdowns)
+//	// → {PeriodID: "2026-JAN", VolumeMT: 0, TotalAmount: 85000, Currency: "EUR", PriceStatus: FINAL}, ...
+func (s *StorageAgreement) AccrueFees(breakdowns []TradeBreakdown) {
+	for i := range breakdowns {
+		bd := &breakdowns[i]
+		if bd.IsReversal() {
+			continue
+		}
+		bd.VolumeMT = 0
+		bd.Quantity = 0
+		bd.PricePerMT = 0
+		bd.PriceStatus = PriceFinal
+		bd.TotalAmount = s.Terms.FeePerPeriod * bd.MonthFraction
+	}
+}
@@ -0,0 +1,119 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+// SwapSide tells which leg of a swap the book pays. It is stored in trades.swap_side.
+type SwapSide string
+
+const (
+	SwapPayFixed     SwapSide = "PAY_FIXED"     // Pays the fixed price, receives the index, e.g. to hedge a floating-priced purchase
+	SwapReceiveFixed SwapSide = "RECEIVE_FIXED" // Receives the fixed price, pays the index
+)
+
+// Swap is a financially settled fixed-vs-index swap (a paper trade). Nothing is delivered:
+// every month the difference between the fixed price (PricePerMT) and the index fixings of
+// the floating leg (Pricing) is settled in cash on the notional volume (see SettleSwap).
+// Swaps therefore have no breakdowns and do not count in physical positions.
+//
+// Example (Q1 2026, 5,000 MT/month, pay 610 fixed against the monthly average of ICE Brent):
+//
+//	tb := NewTradeBase(period.PeriodRange{StartPeriodID: "2026-Q1", EndPeriodID: "2026-Q1"}, 5000, 610, "USD", "user@internal.local")
+//	tb.Pricing = &PricingFormula{Type: PricingMonthlyAverage, Index: "ICE-BRENT"}
+//	sw := &Swap{TradeBase: *tb, CounterpartyCompanyID: "C42", Side: SwapPayFixed}
+type Swap struct {
+	TradeBase
+	CounterpartyCompanyID string   // ID of the counterparty company (company domain)
+	Side                  SwapSide // Leg the book pays
+}
+
+func (s *Swap) Base() *TradeBase       { return &s.TradeBase }
+func (s *Swap) Kind() TradeKind        { return TradeKindSwap }
+func (s *Swap) CounterpartyID() string { return s.CounterpartyCompanyID }
+
+// Validate checks the swap-specific terms: a known side and an index for the floating leg.
+func (s *Swap) Validate() error {
+	var errs []error
+	switch s.Side {
+	case SwapPayFixed, SwapReceiveFixed:
+	default:
+		errs = append(errs, fmt.Errorf("invalid swap side %q", s.Side))
+	}
+	if !s.IsFloating() {
+		errs = append(errs, errors.New("a swap requires an index pricing formula for its floating leg"))
+	}
+	if len(s.Costs) > 0 {
+		errs = append(errs, errors.New("swaps carry no delivery costs"))
+	}
+	return errors.Join(errs...)
+}
+
+// SwapSettlement is the cash settlement of one month of a swap. Amount is seen from the book:
+// positive when the book receives, negative when it pays.
+type SwapSettlement struct {
+	TradeID       string      `json:"tradeId"`
+	PeriodID      string      `json:"periodId"`
+	StartDate     time.Time   `json:"startDate"`
+	EndDate       time.Time   `json:"endDate"`
+	VolumeMT      float64     `json:"volumeMT"` // Notional volume of the month
+	FixedPrice    float64     `json:"fixedPrice"`
+	FloatingPrice float64     `json:"floatingPrice"` // Fixings of the floating leg, plus its premium
+	Amount        float64     `json:"amount"`
+	Currency      string      `json:"currency"`
+	PriceStatus   PriceStatus `json:"priceStatus"` // FINAL once every fixing of the month is published
+}
+
+// SettleSwap
+//
+// PURPOSE:
+//
+//	Calculates the monthly cash settlements of a swap as known on asOf. The notional volume
+//	per month follows the trade's period range, breakdown and allocation modes exactly like a
+//	physical trade; the floating price is evaluated like PriceBreakdowns does:
+//
+//	    PAY_FIXED:     Amount = (floating - fixed) × volume
+//	    RECEIVE_FIXED: Amount = (fixed - floating) × volume
+//
+//	Months whose fixings are not all published are PROVISIONAL; their floating price is the
+//	fixings so far, the forward estimate, or the fixed price when nothing is known.
+//
+// EXAMPLE USAGE:
+//
+//	settlements, err := SettleSwap(sw, ps, quotes, time.Now())
+//	// January average Brent 615.20 → {PeriodID: "2026-JAN", FloatingPrice: 615.2, Amount: 26000, PriceStatus: FINAL}
+func SettleSwap(sw *Swap, ps *period.PeriodStore, quotes IndexQuoteSource, asOf time.Time) ([]SwapSettlement, error) {
+	if err := sw.Validate(); err != nil {
+		return nil, fmt.Errorf("swap %s is invalid: %w", sw.ID, err)
+	}
+
+	months := CreateTradeBreakdowns(sw.TradeBase, ps, sw.AuditInfo.CreatedBy)
+	if err := PriceBreakdowns(sw.TradeBase, months, quotes, asOf); err != nil {
+		return nil, err
+	}
+
+	settlements := make([]SwapSettlement, 0, len(months))
+	for _, m := range LiveBreakdowns(months) {
+		diff := m.PricePerMT - sw.PricePerMT
+		if sw.Side == SwapReceiveFixed {
+			diff = -diff
+		}
+		settlements = append(settlements, SwapSettlement{
+			TradeID:       sw.ID,
+			PeriodID:      m.PeriodID,
+			StartDate:     m.StartDate,
+			EndDate:       m.EndDate,
+			VolumeMT:      m.VolumeMT,
+			FixedPrice:    sw.PricePerMT,
+			FloatingPrice: m.PricePerMT,
+			Amount:        diff * m.VolumeMT,
+			Currency:      sw.Currency,
+			PriceStatus:   m.PriceStatus,
+		})
+	}
+	return settlements, nil
+}
@@ -0,0 +1,205 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	period "github.com/nholding/cso-book/internal/period/domain"
+)
+
+// TakeOrPayClause is a minimum volume obligation of a term contract: per obligation period
+// (a quarter or a year) the buyer takes at least MinVolumeMT or pays for the shortfall. With
+// make-up rights, volume paid for but not taken may be taken in the following periods on top
+// of their minimum; that volume is credited against the payment made.
+//
+// Example (at least 100,000 MT per year, shortfall paid at 40 USD/MT, made up within a year):
+//
+//	tb.TakeOrPay = &TakeOrPayClause{Granularity: period.CalendarYearPeriod, MinVolumeMT: 100000,
+//	    DeficiencyPricePerMT: 40, MakeUpPeriods: 1}
+type TakeOrPayClause struct {
+	Granularity          period.PeriodGranularity `json:"granularity"`                    // QUARTERLY or CALENDAR
+	MinVolumeMT          float64                  `json:"minVolumeMT"`                    // Minimum offtake per obligation period
+	DeficiencyPricePerMT float64                  `json:"deficiencyPricePerMT,omitempty"` // Paid per MT short; 0 means the trade's PricePerMT
+	MakeUpPeriods        int                      `json:"makeUpPeriods,omitempty"`        // Following periods in which a shortfall may be made up; 0 means none
+}
+
+// Validate checks the terms of the clause.
+func (c *TakeOrPayClause) Validate() error {
+	var errs []error
+	switch c.Granularity {
+	case period.QuarterlyPeriod, period.CalendarYearPeriod:
+	default:
+		errs = append(errs, fmt.Errorf("take-or-pay obligations are quarterly or yearly, not %q", c.Granularity))
+	}
+	if c.MinVolumeMT <= 0 {
+		errs = append(errs, fmt.Errorf("take-or-pay minimum volume must be positive, got %v MT", c.MinVolumeMT))
+	}
+	if c.DeficiencyPricePerMT < 0 {
+		errs = append(errs, fmt.Errorf("take-or-pay deficiency price must not be negative, got %v", c.DeficiencyPricePerMT))
+	}
+	if c.MakeUpPeriods < 0 {
+		errs = append(errs, fmt.Errorf("make-up periods must not be negative, got %d", c.MakeUpPeriods))
+	}
+	return errors.Join(errs...)
+}
+
+// TakeOrPayObligation is the outcome of one obligation period of a take-or-pay clause.
+// Liability is owed by the book for purchases and by the counterparty for sales.
+type TakeOrPayObligation struct {
+	PeriodID     string    `json:"periodId"` // Obligation period, e.g. 2026-Q1 or 2026
+	StartDate    time.Time `json:"startDate"`
+	EndDate      time.Time `json:"endDate"`
+	ObligationMT float64   `json:"obligationMT"` // Minimum volume, pro-rated when the contract covers part of the period
+	ActualMT     float64   `json:"actualMT"`     // Nominated or delivered volume
+	ShortfallMT  float64   `json:"shortfallMT"`  // Volume paid for but not taken; only set once the period has ended
+	MadeUpMT     float64   `json:"madeUpMT"`     // Volume above the obligation that makes up earlier shortfalls
+	Liability    float64   `json:"liability"`    // ShortfallMT × deficiency price
+	MakeUpCredit float64   `json:"makeUpCredit"` // MadeUpMT × deficiency price of the periods made up
+	Final        bool      `json:"final"`        // The period has ended on the evaluation date
+}
+
+// TakeOrPayEvaluation sums the obligations of one trade. NetLiability is the total liability
+// less the make-up credits, in Currency.
+type TakeOrPayEvaluation struct {
+	TradeID      string                `json:"tradeId"`
+	Currency     string                `json:"currency"`
+	Obligations  []TakeOrPayObligation `json:"obligations"`
+	NetLiability float64               `json:"netLiability"`
+}
+
+// EvaluateTakeOrPay
+//
+// PURPOSE:
+//
+//	Compares the actual volumes of a trade to its take-or-pay obligations as of asOf. actuals
+//	holds the nominated or delivered MT per delivery month; the obligation periods are the
+//	quarters or years its live breakdowns fall in:
+//
+//	  2026  obligation 100,000 MT  actual  80,000 MT  shortfall 20,000 MT  liability 800,000
+//	  2027  obligation 100,000 MT  actual 110,000 MT  made up   10,000 MT  credit    400,000
+//
+//	Periods that have not ended yet carry no shortfall: the volume may still be taken. A
+//	shortfall is made up by volume above the obligation of a later period that has ended,
+//	within MakeUpPeriods periods, oldest shortfall first.
+//
+// EXAMPLE USAGE:
+//
+//	eval, err := EvaluateTakeOrPay(ps, t, breakdowns, map[string]float64{"2026-JAN": 7000, ...}, time.Now())
+//	// eval.NetLiability → 400000
+func EvaluateTakeOrPay(ps *period.PeriodStore, t Trade, breakdowns []TradeBreakdown, actuals map[string]float64, asOf time.Time) (*TakeOrPayEvaluation, error) {
+	tb := t.Base()
+	clause := tb.TakeOrPay
+	if clause == nil {
+		return nil, fmt.Errorf("trade %s has no take-or-pay clause", tb.ID)
+	}
+	if err := clause.Validate(); err != nil {
+		return nil, fmt.Errorf("trade %s: %w", tb.ID, err)
+	}
+	price := clause.DeficiencyPricePerMT
+	if price == 0 {
+		price = tb.PricePerMT
+	}
+
+	var candidates []*period.Period
+	if clause.Granularity == period.QuarterlyPeriod {
+		candidates = ps.Quarters()
+	} else {
+		candidates = ps.Years()
+	}
+
+	// Obligation periods with the share of their months the contract covers
+	covered := make(map[string]float64)
+	byID := make(map[string]*period.Period)
+	for _, bd := range LiveBreakdowns(breakdowns) {
+		if bd.IsReversal() || bd.IsFee() {
+			continue
+		}
+		p := containing(candidates, bd.StartDate)
+		if p == nil {
+			return nil, fmt.Errorf("trade %s: no %s period contains %s", tb.ID, clause.Granularity, bd.PeriodID)
+		}
+		covered[p.ID] += bd.MonthFraction / float64(monthsIn(p))
+		byID[p.ID] = p
+	}
+
+	months := ps.Months()
+	obligations := make([]TakeOrPayObligation, 0, len(byID))
+	for id, p := range byID {
+		o := TakeOrPayObligation{
+			PeriodID:     id,
+			StartDate:    p.StartDate,
+			EndDate:      p.EndDate,
+			ObligationMT: clause.MinVolumeMT * min(covered[id], 1),
+			Final:        p.EndDate.Before(asOf),
+		}
+		for _, m := range months {
+			if !m.StartDate.Before(p.StartDate) && !m.StartDate.After(p.EndDate) {
+				o.ActualMT += actuals[m.ID]
+			}
+		}
+		obligations = append(obligations, o)
+	}
+	sort.Slice(obligations, func(i, j int) bool { return obligations[i].StartDate.Before(obligations[j].StartDate) })
+
+	type deficiency struct {
+		index int
+		mt    float64
+	}
+	var bank []deficiency
+	eval := &TakeOrPayEvaluation{TradeID: tb.ID, Currency: tb.Currency}
+	for i := range obligations {
+		o := &obligations[i]
+		if !o.Final {
+			continue
+		}
+		if o.ActualMT < o.ObligationMT {
+			o.ShortfallMT = o.ObligationMT - o.ActualMT
+			o.Liability = o.ShortfallMT * price
+			if clause.MakeUpPeriods > 0 {
+				bank = append(bank, deficiency{index: i, mt: o.ShortfallMT})
+			}
+			continue
+		}
+
+		excess := o.ActualMT - o.ObligationMT
+		for b := range bank {
+			if excess <= 0 {
+				break
+			}
+			if bank[b].mt <= 0 || i-bank[b].index > clause.MakeUpPeriods {
+				continue
+			}
+			used := min(excess, bank[b].mt)
+			bank[b].mt -= used
+			excess -= used
+			o.MadeUpMT += used
+		}
+		o.MakeUpCredit = o.MadeUpMT * price
+	}
+
+	for _, o := range obligations {
+		eval.NetLiability += o.Liability - o.MakeUpCredit
+	}
+	eval.Obligations = obligations
+	return eval, nil
+}
+
+// containing returns the period of candidates whose date range contains at.
+func containing(candidates []*period.Period, at time.Time) *period.Period {
+	for _, p := range candidates {
+		if p.Calendar == period.CalendarGregorian && !at.Before(p.StartDate) && !at.After(p.EndDate) {
+			return p
+		}
+	}
+	return nil
+}
+
+// monthsIn returns the number of calendar months a quarter or year spans.
+func monthsIn(p *period.Period) int {
+	if p.Granularity == period.QuarterlyPeriod {
+		return 3
+	}
+	return 12
+}
@@ -0,0 +1,82 @@
+package trade
+
+import (
+	"strings"
+
+	"github.com/nholding/cso-book/internal/units"
+)
+
+// Term is one contractual term of a trade, formatted for display on a confirmation.
+type Term struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ContractTerms lists the contractual terms of a trade in the order a confirmation shows them.
+// Terms that are not set (no pricing formula, no costs, no tolerance, ...) are left out.
+//
+// Example (fixed-price purchase of 10,000 MT per month in Q1 2026):
+//
+//	ContractTerms(p)
+//	// → [{Delivery period, 2026-Q1}, {Volume, 10000 MT per month}, {Price, 3.5 USD/MT}, ...]
+func ContractTerms(t Trade) []Term {
+	tb := t.Base()
+
+	delivery := tb.PeriodRange.StartPeriodID
+	if tb.PeriodRange.EndPeriodID != "" && tb.PeriodRange.EndPeriodID != tb.PeriodRange.StartPeriodID {
+		delivery += " – " + tb.PeriodRange.EndPeriodID
+	}
+	window := ""
+	if tb.DeliveryStart != nil && tb.DeliveryEnd != nil {
+		window = formatDate(tb.DeliveryStart) + " – " + formatDate(tb.DeliveryEnd)
+	}
+
+	volume := formatFloat(tb.VolumeMT) + " MT"
+	if tb.BookedUnit() != units.MetricTonne {
+		volume = formatFloat(tb.BookedQuantity()) + " " + string(tb.BookedUnit()) + " (" + volume + ")"
+	}
+	if tb.Allocation() == AllocationPerMonthFixed {
+		volume += " per month"
+	} else {
+		volume += " in total, " + strings.ToLower(strings.ReplaceAll(string(tb.Allocation()), "_", " "))
+	}
+
+	price := formatFloat(tb.PricePerMT) + " " + tb.Currency + "/MT"
+	if tb.IsFloating() {
+		price = formatPricing(tb.Pricing) + " " + tb.Currency + "/MT (provisional " + formatFloat(tb.PricePerMT) + ")"
+	}
+
+	all := []Term{
+		{"Delivery period", delivery},
+		{"Delivery window", window},
+		{"Commodity", tb.Commodity},
+		{"Product", tb.ProductID},
+		{"Location", tb.LocationID},
+		{"Volume", volume},
+		{"Price", price},
+		{"Costs", formatCosts(tb.Costs)},
+		{"Volume tolerance", formatTolerance(tb.Tolerance)},
+		{"Take-or-pay", formatTakeOrPay(tb.TakeOrPay)},
+		{"Laycans", formatLaycans(tb.Laycans)},
+		{"Payment", formatPaymentTerms(tb.PaymentTerms)},
+		{"Cancelled months", strings.Join(tb.CancelledPeriodIDs, ", ")},
+	}
+	if tb.AvailabilityFeePerMT != 0 {
+		all = append(all, Term{"Availability fee", formatFloat(tb.AvailabilityFeePerMT) + " " + tb.Currency + "/MT"})
+	}
+	switch v := t.(type) {
+	case *Swap:
+		all = append(all, Term{"Swap side", string(v.Side)})
+	case *StorageAgreement:
+		all = append(all, Term{"Storage service", string(v.Terms.Service)},
+			Term{"Storage fee", formatFloat(v.Terms.FeePerPeriod) + " " + tb.Currency + " per month"})
+	}
+
+	terms := make([]Term, 0, len(all))
+	for _, term := range all {
+		if term.Value != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
@@ -4,5 +4,5 @@ package trade
 // Represents a Ticket sale trade. Distinctive type from Purchase.
 type Ticket struct {
 	TradeBase
-	BuyerID string
+	BuyerID string // ID of the buying company (company domain)
 }
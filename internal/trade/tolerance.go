@@ -0,0 +1,126 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ToleranceOption tells which party declares the final volume within the tolerance.
+type ToleranceOption string
+
+const (
+	ToleranceBuyersOption  ToleranceOption = "BUYER"
+	ToleranceSellersOption ToleranceOption = "SELLER"
+)
+
+// VolumeTolerance is the volume optionality of a trade: the party holding the option declares
+// the final volume of each month within MinusPct below and PlusPct above the contractual volume.
+//
+// Example (10,000 MT ±5% in seller's option):
+//
+//	tb.Tolerance = &VolumeTolerance{PlusPct: 5, MinusPct: 5, Option: ToleranceSellersOption}
+//	// → each month may be declared between 9,500 and 10,500 MT
+type VolumeTolerance struct {
+	PlusPct  float64         `json:"plusPct"`
+	MinusPct float64         `json:"minusPct"`
+	Option   ToleranceOption `json:"option"`
+}
+
+// Validate checks the percentages and the option holder.
+func (v *VolumeTolerance) Validate() error {
+	var errs []error
+	if v.PlusPct < 0 || v.PlusPct > 100 || v.MinusPct < 0 || v.MinusPct > 100 {
+		errs = append(errs, fmt.Errorf("tolerance must lie between 0%% and 100%%, got +%v%%/-%v%%", v.PlusPct, v.MinusPct))
+	}
+	switch v.Option {
+	case ToleranceBuyersOption, ToleranceSellersOption:
+	default:
+		errs = append(errs, fmt.Errorf("invalid tolerance option %q", v.Option))
+	}
+	return errors.Join(errs...)
+}
+
+// DeclareVolume
+//
+// PURPOSE:
+//
+//	Records the final volume of one delivery month of a CONFIRMED trade, declared by the
+//	holder of the tolerance option, and recalculates the breakdowns of that month in place:
+//
+//	  2026-JAN  10,000 MT  (contract, ±5% seller's option)
+//	  2026-JAN  10,400 MT  declared → amount, costs and fees of January follow
+//
+//	The declaration is kept in DeclaredVolumes, so the month keeps its final volume whenever
+//	the breakdowns are rebuilt, and written to the status history. A month is declared once.
+//
+// EXAMPLE USAGE:
+//
+//	err := t.Base().DeclareVolume("2026-JAN", 10400, breakdowns, "user@internal.local")
+func (tb *TradeBase) DeclareVolume(periodID string, volumeMT float64, breakdowns []TradeBreakdown, declaredBy string) error {
+	if tb.Status != TradeStatusConfirmed {
+		return fmt.Errorf("trade %s is %s; volumes are declared on CONFIRMED trades only", tb.ID, tb.Status)
+	}
+	if tb.IsDeleted() {
+		return fmt.Errorf("trade %s is deleted", tb.ID)
+	}
+	if tb.Tolerance == nil {
+		return fmt.Errorf("trade %s has no volume tolerance to declare", tb.ID)
+	}
+	if _, ok := tb.DeclaredVolumes[periodID]; ok {
+		return fmt.Errorf("the volume of %s of trade %s is already declared", periodID, tb.ID)
+	}
+
+	var contractual *TradeBreakdown
+	for i := range breakdowns {
+		bd := &breakdowns[i]
+		if bd.PeriodID == periodID && !bd.IsReversal() && !bd.IsFee() && !bd.IsCancelled() {
+			contractual = bd
+		}
+	}
+	if contractual == nil {
+		return fmt.Errorf("trade %s does not deliver in %s", tb.ID, periodID)
+	}
+	low := contractual.VolumeMT * (1 - tb.Tolerance.MinusPct/100)
+	high := contractual.VolumeMT * (1 + tb.Tolerance.PlusPct/100)
+	if volumeMT < low || volumeMT > high {
+		return fmt.Errorf("declared volume %v MT of %s lies outside the tolerance of trade %s (%v–%v MT)", volumeMT, periodID, tb.ID, low, high)
+	}
+
+	if tb.DeclaredVolumes == nil {
+		tb.DeclaredVolumes = make(map[string]float64)
+	}
+	tb.DeclaredVolumes[periodID] = volumeMT
+	tb.RecordStatusNote(fmt.Sprintf("declared %v MT for %s in %s's option", volumeMT, periodID, tb.Tolerance.Option), declaredBy)
+
+	tb.applyDeclaredVolumes(breakdowns)
+	AllocateCosts(tb, breakdowns)
+	return nil
+}
+
+// applyDeclaredVolumes replaces the contractual volume of every declared month by its declared
+// volume, and prices the month and its fee component on it.
+func (tb *TradeBase) applyDeclaredVolumes(breakdowns []TradeBreakdown) {
+	if len(tb.DeclaredVolumes) == 0 {
+		return
+	}
+
+	for i := range breakdowns {
+		bd := &breakdowns[i]
+		declared, ok := tb.DeclaredVolumes[bd.PeriodID]
+		if !ok || bd.IsReversal() {
+			continue
+		}
+		if bd.IsFee() {
+			bd.TotalAmount = declared * bd.PricePerMT
+		} else {
+			if bd.VolumeMT != 0 {
+				bd.Quantity *= declared / bd.VolumeMT
+			}
+			bd.VolumeMT = declared
+			bd.TotalAmount = declared * bd.PricePerMT
+		}
+		if bd.ReportingCurrency != "" {
+			bd.ReportingAmount = bd.TotalAmount * bd.FXRate
+		}
+	}
+}
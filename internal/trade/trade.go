@@ -0,0 +1,115 @@
+package trade
+
+import (
+	"strconv"
+
+	"github.com/nholding/cso-book/internal/utils"
+)
+
+// TradeKind tells purchases, sales, swaps and storage agreements apart. It is stored in trades.kind.
+type TradeKind string
+
+const (
+	TradeKindPurchase TradeKind = "PURCHASE"
+	TradeKindSale     TradeKind = "SALE"
+	TradeKindSwap     TradeKind = "SWAP"    // Financially settled, see Swap
+	TradeKindStorage  TradeKind = "STORAGE" // Storage or throughput fee, see StorageAgreement
+)
+
+// Trade is implemented by every parent trade type: *Purchase, *Ticket (the sale side), *Swap
+// and *StorageAgreement. It gives persistence and reporting code uniform access to the shared TradeBase and to
+// the counterparty, which is a supplier for purchases and a buyer for sales.
+//
+// Example:
+//
+//	var t Trade = &Purchase{TradeBase: *tb, SupplierID: "C42"}
+//	t.Kind()           // → PURCHASE
+//	t.CounterpartyID() // → "C42"
+type Trade interface {
+	Base() *TradeBase
+	Kind() TradeKind
+	CounterpartyID() string
+}
+
+var (
+	_ Trade = (*Purchase)(nil)
+	_ Trade = (*Ticket)(nil)
+	_ Trade = (*Swap)(nil)
+	_ Trade = (*StorageAgreement)(nil)
+)
+
+func (p *Purchase) Base() *TradeBase       { return &p.TradeBase }
+func (p *Purchase) Kind() TradeKind        { return TradeKindPurchase }
+func (p *Purchase) CounterpartyID() string { return p.SupplierID }
+
+func (t *Ticket) Base() *TradeBase       { return &t.TradeBase }
+func (t *Ticket) Kind() TradeKind        { return TradeKindSale }
+func (t *Ticket) CounterpartyID() string { return t.BuyerID }
+
+// BookedTrade is a trade together with its breakdowns: the input of book-wide calculations
+// such as PnL, positions and exposure.
+type BookedTrade struct {
+	Trade      Trade
+	Breakdowns []TradeBreakdown
+}
+
+// IsLive reports whether a trade counts in the book: it is not deleted, cancelled or
+// superseded by a newer version.
+func IsLive(t Trade) bool {
+	tb := t.Base()
+	if tb.IsDeleted() {
+		return false
+	}
+	switch tb.Status {
+	case TradeStatusCancelled, TradeStatusSuperseded:
+		return false
+	}
+	return true
+}
+
+// businessKeyVersion is the version of the trade business key logic, see BusinessKey.
+const businessKeyVersion = "T1"
+
+// BusinessKey returns the deterministic key of a trade's commercial terms: kind, counterparty,
+// delivery period, volume, price, currency and product (or commodity). Two live trades with the
+// same key are most likely the same deal booked twice.
+//
+// Example:
+//
+//	p := &Purchase{TradeBase: *tb, SupplierID: "C42"}
+//	p.BusinessKey = BusinessKey(p) // → "T1_Qm9v..."
+func BusinessKey(t Trade) string {
+	tb := t.Base()
+
+	fields := map[string]string{
+		"kind":         string(t.Kind()),
+		"counterparty": t.CounterpartyID(),
+		"startPeriod":  tb.PeriodRange.StartPeriodID,
+		"endPeriod":    tb.PeriodRange.EndPeriodID,
+		"volumeMT":     strconv.FormatFloat(tb.VolumeMT, 'f', -1, 64),
+		"pricePerMT":   strconv.FormatFloat(tb.PricePerMT, 'f', -1, 64),
+		"currency":     tb.Currency,
+		"product":      tb.ProductID,
+		"commodity":    tb.Commodity,
+	}
+	if tb.DeliveryStart != nil && tb.DeliveryEnd != nil {
+		fields["deliveryStart"] = tb.DeliveryStart.Format("2006-01-02")
+		fields["deliveryEnd"] = tb.DeliveryEnd.Format("2006-01-02")
+	}
+	if tb.Pricing != nil {
+		fields["index"] = tb.Pricing.Index
+		fields["premium"] = strconv.FormatFloat(tb.Pricing.Premium, 'f', -1, 64)
+	}
+	if tb.AvailabilityFeePerMT != 0 {
+		fields["availabilityFee"] = strconv.FormatFloat(tb.AvailabilityFeePerMT, 'f', -1, 64)
+	}
+	switch v := t.(type) {
+	case *Swap:
+		fields["swapSide"] = string(v.Side)
+	case *StorageAgreement:
+		fields["storageService"] = string(v.Terms.Service)
+		fields["storageFee"] = strconv.FormatFloat(v.Terms.FeePerPeriod, 'f', -1, 64)
+	}
+
+	return utils.GenerateBusinessKey(businessKeyVersion, fields)
+}
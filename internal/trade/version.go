@@ -0,0 +1,142 @@
+package trade
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/nholding/cso-book/internal/audit"
+)
+
+// NewVersion
+//
+// PURPOSE:
+//
+//	Amends a CONFIRMED trade without rewriting what the counterparty confirmed. The trade
+//	is cloned into a new version with its own ID, and the original is marked SUPERSEDED:
+//
+//	  T1 (CONFIRMED → SUPERSEDED)  SupersededByID    = T2
+//	  T2 (PENDING-CONFIRMATION)    PreviousVersionID = T1
+//
+//	The new version carries the terms of the original; the caller applies the amendment to
+//	it afterwards. It starts as PENDING-CONFIRMATION, since the amended terms must be
+//	confirmed again. Policy overrides and the status history are not copied: they belong to
+//	the booking of the original.
+//
+// EXAMPLE USAGE:
+//
+//	next, err := original.Base().NewVersion(utils.GenerateStableID(), "price correction", "user@internal.local")
+//	if err != nil {
+//	    return err
+//	}
+//	next.PricePerMT = 3.6
+func (tb *TradeBase) NewVersion(newID, reason, amendedBy string) (*TradeBase, error) {
+	if tb.Status != TradeStatusConfirmed {
+		return nil, fmt.Errorf("trade %s is %s; only CONFIRMED trades are amended by a new version", tb.ID, tb.Status)
+	}
+	if tb.IsDeleted() {
+		return nil, fmt.Errorf("trade %s is deleted", tb.ID)
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to amend trade %s", tb.ID)
+	}
+
+	next := tb.successor(newID, TradeStatusPending, fmt.Sprintf("amendment of %s: %s", tb.ID, reason), amendedBy)
+
+	if err := tb.UpdateTradeStatus(TradeStatusSuperseded, fmt.Sprintf("superseded by %s: %s", newID, reason), amendedBy); err != nil {
+		return nil, err
+	}
+	tb.SupersededByID = newID
+
+	return next, nil
+}
+
+// successor copies the terms of a trade into a new trade with its own ID that points back at
+// tb, starting in status with a single status history entry.
+func (tb *TradeBase) successor(newID string, status TradeStatus, reason, user string) *TradeBase {
+	return &TradeBase{
+		ID:                   newID,
+		TradeNumber:          tb.TradeNumber,
+		PeriodRange:          tb.PeriodRange,
+		VolumeMT:             tb.VolumeMT,
+		PricePerMT:           tb.PricePerMT,
+		Pricing:              copyPricing(tb.Pricing),
+		Costs:                append([]CostComponent(nil), tb.Costs...),
+		AvailabilityFeePerMT: tb.AvailabilityFeePerMT,
+		TakeOrPay:            copyTakeOrPay(tb.TakeOrPay),
+		Tolerance:            copyTolerance(tb.Tolerance),
+		DeclaredVolumes:      maps.Clone(tb.DeclaredVolumes),
+		Laycans:              maps.Clone(tb.Laycans),
+		VesselCalls:          maps.Clone(tb.VesselCalls),
+		PaymentTerms:         copyPaymentTerms(tb.PaymentTerms),
+		Currency:             tb.Currency,
+		Commodity:            tb.Commodity,
+		LocationID:           tb.LocationID,
+		ProductID:            tb.ProductID,
+		Unit:                 tb.Unit,
+		Quantity:             tb.Quantity,
+		Status:               status,
+		BreakdownMode:        tb.BreakdownMode,
+		AllocationMode:       tb.AllocationMode,
+		DeliveryStart:        copyTime(tb.DeliveryStart),
+		DeliveryEnd:          copyTime(tb.DeliveryEnd),
+		PreviousVersionID:    tb.ID,
+		CancelledPeriodIDs:   append([]string(nil), tb.CancelledPeriodIDs...),
+		StatusAudit: []TradeStatusHistory{
+			{
+				OldStatus: status,
+				NewStatus: status,
+				ChangedAt: time.Now().UTC(),
+				ChangedBy: user,
+				Reason:    reason,
+			},
+		},
+		AuditInfo: *audit.NewAuditInfo(user),
+	}
+}
+
+// IsSuperseded reports whether a newer version of the trade exists.
+func (tb *TradeBase) IsSuperseded() bool {
+	return tb.SupersededByID != ""
+}
+
+func copyPricing(f *PricingFormula) *PricingFormula {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	c.PricingDay = copyTime(f.PricingDay)
+	return &c
+}
+
+func copyTakeOrPay(c *TakeOrPayClause) *TakeOrPayClause {
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	return &cp
+}
+
+func copyTolerance(v *VolumeTolerance) *VolumeTolerance {
+	if v == nil {
+		return nil
+	}
+	cp := *v
+	return &cp
+}
+
+func copyPaymentTerms(p *PaymentTerms) *PaymentTerms {
+	if p == nil {
+		return nil
+	}
+	cp := *p
+	return &cp
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
@@ -0,0 +1,175 @@
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Unit is the unit a trade quantity is booked in.
+type Unit string
+
+const (
+	MetricTonne Unit = "MT"  // Mass; the unit every position is aggregated in
+	Barrel      Unit = "BBL" // Oil barrel, 158.987294928 litres
+	CubicMetre  Unit = "M3"  // Volume
+	MWh         Unit = "MWH" // Energy, gross calorific value
+)
+
+// litresPerBarrel is the volume of one oil barrel (42 US gallons).
+const litresPerBarrel = 158.987294928
+
+// ParseUnit parses a unit, accepting common spellings: "mt", "bbl", "m³", "m3", "MWh".
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "", "MT", "T", "TONNE", "TONNES":
+		return MetricTonne, nil
+	case "BBL", "BARREL", "BARRELS":
+		return Barrel, nil
+	case "M3", "M³", "CBM":
+		return CubicMetre, nil
+	case "MWH":
+		return MWh, nil
+	}
+	return "", fmt.Errorf("unknown unit %q (MT, BBL, M3 or MWH)", s)
+}
+
+// CommodityFactors are the physical properties needed to convert a commodity between mass,
+// volume and energy. A zero factor means the conversion is not defined for the commodity.
+type CommodityFactors struct {
+	DensityKgPerM3 float64 // Mass per volume at standard conditions (15 °C)
+	MWhPerMT       float64 // Energy content per tonne (gross calorific value)
+}
+
+// DefaultCommodities are typical industry factors. Books with contractual factors (e.g. a
+// certified density per cargo) should pass their own table to NewConverter.
+var DefaultCommodities = map[string]CommodityFactors{
+	"crude":    {DensityKgPerM3: 850, MWhPerMT: 11.63},
+	"diesel":   {DensityKgPerM3: 845, MWhPerMT: 11.94},
+	"gasoline": {DensityKgPerM3: 745, MWhPerMT: 12.06},
+	"jet":      {DensityKgPerM3: 800, MWhPerMT: 11.94},
+	"fuel-oil": {DensityKgPerM3: 980, MWhPerMT: 11.28},
+	"naphtha":  {DensityKgPerM3: 700, MWhPerMT: 12.28},
+	"lng":      {DensityKgPerM3: 450, MWhPerMT: 15.28},
+	"lpg":      {DensityKgPerM3: 540, MWhPerMT: 13.83},
+}
+
+// Converter converts quantities between units using commodity-specific factors.
+//
+// PURPOSE:
+//
+//	Oil legs are booked in MT, bbl or m³, gas legs in MWh. Positions, ladders and exposures
+//	are aggregated in metric tonnes, so every booked quantity is also expressed in MT:
+//
+//	    bbl → m³ → MT   via the barrel volume and the density
+//	    MWh → MT        via the energy content
+//
+// EXAMPLE USAGE:
+//
+//	conv := units.NewConverter(units.DefaultCommodities)
+//	mt, err := conv.Convert(100000, units.Barrel, units.MetricTonne, "crude") // → 13,513.92 MT
+type Converter struct {
+	commodities map[string]CommodityFactors
+}
+
+func NewConverter(commodities map[string]CommodityFactors) *Converter {
+	c := &Converter{commodities: make(map[string]CommodityFactors, len(commodities))}
+	for name, f := range commodities {
+		c.commodities[strings.ToLower(name)] = f
+	}
+	return c
+}
+
+// Commodities lists the commodities known to the converter, sorted.
+func (c *Converter) Commodities() []string {
+	names := make([]string, 0, len(c.commodities))
+	for name := range c.commodities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Convert converts quantity from one unit to another. The commodity is only needed when the
+// conversion crosses mass, volume and energy (e.g. bbl → MT); bbl ↔ m³ never needs it.
+func (c *Converter) Convert(quantity float64, from, to Unit, commodity string) (float64, error) {
+	if from == to {
+		return quantity, nil
+	}
+
+	mt, err := c.toMT(quantity, from, commodity)
+	if err != nil {
+		return 0, err
+	}
+	return c.fromMT(mt, to, commodity)
+}
+
+// ToMT converts quantity in unit to metric tonnes.
+func (c *Converter) ToMT(quantity float64, unit Unit, commodity string) (float64, error) {
+	return c.Convert(quantity, unit, MetricTonne, commodity)
+}
+
+func (c *Converter) toMT(quantity float64, unit Unit, commodity string) (float64, error) {
+	switch unit {
+	case MetricTonne:
+		return quantity, nil
+	case Barrel:
+		return c.toMT(quantity*litresPerBarrel/1000, CubicMetre, commodity)
+	case CubicMetre:
+		f, err := c.factor(commodity, "density", func(f CommodityFactors) float64 { return f.DensityKgPerM3 })
+		if err != nil {
+			return 0, err
+		}
+		return quantity * f / 1000, nil
+	case MWh:
+		f, err := c.factor(commodity, "energy content", func(f CommodityFactors) float64 { return f.MWhPerMT })
+		if err != nil {
+			return 0, err
+		}
+		return quantity / f, nil
+	}
+	return 0, fmt.Errorf("unknown unit %q", unit)
+}
+
+func (c *Converter) fromMT(mt float64, unit Unit, commodity string) (float64, error) {
+	switch unit {
+	case MetricTonne:
+		return mt, nil
+	case Barrel:
+		m3, err := c.fromMT(mt, CubicMetre, commodity)
+		if err != nil {
+			return 0, err
+		}
+		return m3 * 1000 / litresPerBarrel, nil
+	case CubicMetre:
+		f, err := c.factor(commodity, "density", func(f CommodityFactors) float64 { return f.DensityKgPerM3 })
+		if err != nil {
+			return 0, err
+		}
+		return mt * 1000 / f, nil
+	case MWh:
+		f, err := c.factor(commodity, "energy content", func(f CommodityFactors) float64 { return f.MWhPerMT })
+		if err != nil {
+			return 0, err
+		}
+		return mt * f, nil
+	}
+	return 0, fmt.Errorf("unknown unit %q", unit)
+}
+
+// factor looks up one factor of a commodity, failing when the commodity is unknown or the
+// factor is not defined for it.
+func (c *Converter) factor(commodity, name string, get func(CommodityFactors) float64) (float64, error) {
+	f, ok := c.commodities[strings.ToLower(commodity)]
+	if !ok {
+		if commodity == "" {
+			return 0, fmt.Errorf("a commodity is required to convert via %s", name)
+		}
+		return 0, fmt.Errorf("unknown commodity %q", commodity)
+	}
+	v := get(f)
+	if v <= 0 {
+		return 0, fmt.Errorf("commodity %s has no %s factor", commodity, name)
+	}
+	return v, nil
+}
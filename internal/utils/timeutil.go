@@ -8,3 +8,28 @@ import (
 func DateInRange(date, start, end time.Time) bool {
 	return (date.Equal(start) || date.After(start)) && (date.Equal(end) || date.Before(end))
 }
+
+// StartOfDay returns midnight (00:00:00) of the day t falls on, in UTC.
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// EndOfDay returns the last nanosecond of the day t falls on, in UTC.
+// This matches the inclusive EndDate convention used by Periods.
+func EndOfDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// DaysInRange returns the number of calendar days touched by the inclusive range [start, end].
+// Returns 0 when end is before start.
+//
+// Example:
+//
+//	DaysInRange(2026-03-15, 2026-03-31 23:59:59.999999999) // → 17
+func DaysInRange(start, end time.Time) int {
+	if end.Before(start) {
+		return 0
+	}
+	return int(StartOfDay(end).Sub(StartOfDay(start)).Hours()/24) + 1
+}